@@ -22,10 +22,15 @@ import (
 	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/handler"
 	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
 	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/router"
-	"github.com/sachin-sivadasan/ledgerguard/pkg/crypto"
-	apikeyhandler "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/handler"
 	apikeysvc "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	apikeycache "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/infrastructure/cache"
 	apikeypersist "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/infrastructure/persistence"
+	revenuegraphql "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/graphql"
+	apikeyhandler "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/handler"
+	apikeymw "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/middleware"
+	revenuerouter "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/router"
+	"github.com/sachin-sivadasan/ledgerguard/pkg/crypto"
+	"github.com/sachin-sivadasan/ledgerguard/pkg/netguard"
 )
 
 func main() {
@@ -103,6 +108,13 @@ func run() error {
 		}
 	}
 
+	// Outbound requests to user-supplied URLs (notification and Revenue API webhooks)
+	// may only reach public addresses
+	webhookURLGuard := netguard.New(cfg.Webhooks.AllowLoopback)
+	if cfg.Webhooks.AllowLoopback {
+		log.Println("WARNING: webhooks may target localhost (WEBHOOK_ALLOW_LOOPBACK); do not enable in production")
+	}
+
 	// Initialize repositories
 	var userRepo *persistence.PostgresUserRepository
 	var partnerRepo *persistence.PostgresPartnerAccountRepository
@@ -185,10 +197,34 @@ func run() error {
 		log.Println("Metrics handler initialized (without aggregator)")
 	}
 
+	// Initialize the subscription change hub behind the Revenue API stream.
+	// Changes go through Postgres NOTIFY so every replica's streams see them.
+	var subscriptionChangeHub *apikeysvc.SubscriptionChangeHub
+	var subscriptionChangeNotifier *apikeypersist.PostgresSubscriptionChangeNotifier
+	if db != nil {
+		subscriptionChangeNotifier = apikeypersist.NewPostgresSubscriptionChangeNotifier(db.Pool)
+		subscriptionChangeHub = apikeysvc.NewSubscriptionChangeHub(apikeysvc.DefaultSubscriptionChangeBufferSize).
+			WithTransport(subscriptionChangeNotifier)
+		subscriptionChangeNotifier.Start(ctx, subscriptionChangeHub.Broadcast)
+		log.Println("Subscription change hub initialized, change listener started")
+	}
+
 	// Initialize sync service and handler
 	var syncService *appservice.SyncService
 	var syncHandler *handler.SyncHandler
 	var syncScheduler *scheduler.SyncScheduler
+	var readModelChecker *apikeysvc.ReadModelConsistencyChecker
+	var readModelBuilder *apikeysvc.ReadModelBuilder
+	var alertHandler *handler.AlertHandler
+	var notificationHandler *handler.NotificationHandler
+	var notificationEmailHandler *handler.NotificationEmailHandler
+	var notificationChannelHandler *handler.NotificationChannelHandler
+	var notificationPreferencesHandler *handler.NotificationPreferencesHandler
+	var notificationOutbox *appservice.NotificationOutboxService
+	var dailySummaryScheduler *appservice.DailySummaryScheduler
+	var dunningService *appservice.DunningService
+	var dunningHandler *handler.DunningHandler
+	var smtpSender appservice.EmailSender
 
 	if txRepo != nil && appRepo != nil && partnerRepo != nil && encryptor != nil && subscriptionRepo != nil {
 		// Initialize ledger service for rebuilding after sync
@@ -207,6 +243,129 @@ func run() error {
 			ledgerService,
 		)
 
+		// Project every sync into the Revenue API read model and check it for drift
+		if db != nil {
+			readModelBuilder = apikeysvc.NewReadModelBuilder(
+				subscriptionRepo,
+				txRepo,
+				apikeypersist.NewPostgresSubscriptionStatusRepository(db.Pool),
+				apikeypersist.NewPostgresUsageStatusRepository(db.Pool),
+			).WithCheckpointRepo(apikeypersist.NewPostgresProjectionCheckpointRepository(db.Pool)).
+				WithChangeHub(subscriptionChangeHub)
+			syncService.WithProjector(readModelBuilder)
+
+			readModelChecker = apikeysvc.NewReadModelConsistencyChecker(readModelBuilder, partnerRepo, appRepo)
+			readModelChecker.Start(ctx)
+			log.Println("Read model projection enabled, consistency checker started (6-hour interval)")
+		}
+
+		// Record sync outcomes and evaluate alert rules after every sync
+		if db != nil {
+			var pushProvider appservice.PushNotificationProvider
+			if fcm, err := external.NewFirebaseMessagingService(ctx, cfg.Firebase.CredentialsFile); err != nil {
+				log.Printf("WARNING: Push notifications not configured: %v", err)
+			} else {
+				pushProvider = fcm
+			}
+			notificationPrefsRepo := persistence.NewPostgresNotificationPreferencesRepository(db.Pool)
+			webhookClient := webhookURLGuard.HTTPClient(10 * time.Second)
+			notificationService := appservice.NewNotificationService(
+				persistence.NewPostgresDeviceTokenRepository(db.Pool),
+				notificationPrefsRepo,
+				pushProvider,
+			).WithSlackNotifier(external.NewSlackNotificationProviderWithClient(webhookClient)).
+				WithWebhooks(
+					persistence.NewPostgresNotificationWebhookRepository(db.Pool),
+					external.NewTeamsNotificationProviderWithClient(webhookClient),
+					external.NewDiscordNotificationProviderWithClient(webhookClient),
+					external.NewOutgoingWebhookProviderWithClient(webhookClient),
+				).
+				WithURLGuard(webhookURLGuard)
+			if encryptor != nil {
+				notificationService.WithWebhookSecretEncryptor(encryptor)
+			}
+			notificationChannelHandler = handler.NewNotificationChannelHandler(notificationService)
+			notificationPreferencesHandler = handler.NewNotificationPreferencesHandler(notificationService)
+
+			// Email channel, sent to users' verified notification addresses
+			if cfg.Email.SMTPHost != "" && cfg.Encryption.MasterKey == "" {
+				log.Println("WARNING: Email notifications disabled - encryption.master_key is required to sign unsubscribe links")
+			} else if cfg.Email.SMTPHost != "" {
+				if smtpProvider, err := external.NewSMTPEmailProvider(
+					cfg.Email.SMTPHost,
+					cfg.Email.SMTPPort,
+					cfg.Email.SMTPUsername,
+					cfg.Email.SMTPPassword,
+					cfg.Email.From,
+				); err != nil {
+					log.Printf("WARNING: Email notifications not configured: %v", err)
+				} else {
+					smtpSender = smtpProvider
+					emailRepo := persistence.NewPostgresNotificationEmailRepository(db.Pool)
+					unsubscribeKey := []byte(cfg.Encryption.MasterKey)
+					notificationService.WithEmail(emailRepo, smtpProvider, cfg.Email.PublicURL, unsubscribeKey)
+					notificationEmailHandler = handler.NewNotificationEmailHandler(
+						appservice.NewNotificationEmailService(emailRepo, smtpProvider, cfg.Email.PublicURL, unsubscribeKey),
+					)
+					log.Printf("Email notifications enabled (SMTP %s:%s)", cfg.Email.SMTPHost, cfg.Email.SMTPPort)
+				}
+			}
+
+			// Notifications go through the outbox and are delivered with retries
+			notificationRepo := persistence.NewPostgresNotificationRepository(db.Pool)
+			notificationService.WithOutbox(notificationRepo)
+			notificationOutbox = appservice.NewNotificationOutboxService(notificationRepo, notificationService)
+			notificationOutbox.Start(ctx)
+			notificationHandler = handler.NewNotificationHandler(notificationOutbox)
+			log.Println("Notification outbox worker started (10-second interval)")
+
+			// Daily summaries at each user's preferred local time, queued in the outbox
+			if snapshotRepo != nil {
+				dailySummaryScheduler = appservice.NewDailySummaryScheduler(
+					notificationPrefsRepo,
+					notificationRepo,
+					partnerRepo,
+					appRepo,
+					snapshotRepo,
+					notificationService,
+				)
+				dailySummaryScheduler.Start(ctx)
+				log.Println("Daily summary scheduler started (1-minute interval)")
+			}
+
+			syncStatusRepo := persistence.NewPostgresAppSyncStatusRepository(db.Pool)
+			alertService := appservice.NewAlertService(
+				persistence.NewPostgresAlertRuleRepository(db.Pool),
+				persistence.NewPostgresAlertRepository(db.Pool),
+				snapshotRepo,
+				subscriptionRepo,
+				txRepo,
+				syncStatusRepo,
+			)
+			syncService.WithSyncStatusRepo(syncStatusRepo).WithAlertEvaluator(alertService)
+
+			alertHandler = handler.NewAlertHandler(alertService, partnerRepo, appRepo)
+			log.Println("Alert rules enabled (evaluated after each sync)")
+
+			// Dunning sequences, started by subscription events recorded from webhooks
+			dunningService = appservice.NewDunningService(
+				persistence.NewPostgresDunningSequenceRepository(db.Pool),
+				persistence.NewPostgresDunningRunRepository(db.Pool),
+				persistence.NewPostgresDunningTaskRepository(db.Pool),
+				persistence.NewPostgresShopContactRepository(db.Pool),
+				appRepo,
+				subscriptionRepo,
+				notificationRepo,
+				notificationService,
+			)
+			if smtpSender != nil {
+				dunningService.WithEmailSender(smtpSender)
+			}
+			dunningService.Start(ctx)
+			dunningHandler = handler.NewDunningHandler(dunningService, partnerRepo, appRepo)
+			log.Println("Dunning worker started (1-minute interval)")
+		}
+
 		syncHandler = handler.NewSyncHandler(syncService, partnerRepo, appRepo)
 		log.Println("Sync handler initialized")
 
@@ -239,21 +398,196 @@ func run() error {
 		log.Println("Revenue handler initialized")
 	}
 
-	// Initialize fee handler
+	// Initialize fee and tax profile handlers
 	var feeHandler *handler.FeeHandler
+	var taxProfileHandler *handler.TaxProfileHandler
 	if appRepo != nil && partnerRepo != nil && txRepo != nil {
 		feeService := domainservice.NewFeeVerificationService()
 		feeHandler = handler.NewFeeHandler(appRepo, partnerRepo, txRepo, feeService)
 		log.Println("Fee handler initialized")
+
+		if db != nil {
+			taxProfileRepo := persistence.NewPostgresTaxJurisdictionProfileRepository(db.Pool)
+			feeHandler.WithTaxProfiles(taxProfileRepo)
+			taxProfileHandler = handler.NewTaxProfileHandler(appservice.NewTaxProfileService(taxProfileRepo), partnerRepo)
+			log.Println("Tax profile handler initialized")
+		}
+	}
+
+	// Initialize accounting export handler
+	var accountingExportHandler *handler.AccountingExportHandler
+	if db != nil && txRepo != nil && partnerRepo != nil && appRepo != nil {
+		glMappingRepo := persistence.NewPostgresGLAccountMappingRepository(db.Pool)
+		exportRunRepo := persistence.NewPostgresAccountingExportRunRepository(db.Pool)
+		accountingSvc := appservice.NewAccountingExportService(txRepo, glMappingRepo, exportRunRepo)
+		auditSvc := appservice.NewAuditService(persistence.NewPostgresAuditLogRepository(db.Pool))
+		accountingExportHandler = handler.NewAccountingExportHandler(accountingSvc, partnerRepo, appRepo).
+			WithAuditService(auditSvc)
+		log.Println("Accounting export handler initialized")
+	}
+
+	// Initialize revenue recognition handler
+	var revenueRecognitionHandler *handler.RevenueRecognitionHandler
+	if db != nil && txRepo != nil && subscriptionRepo != nil && partnerRepo != nil && appRepo != nil {
+		eventRepo := persistence.NewPostgresSubscriptionEventRepository(db.Pool)
+		recognitionSvc := appservice.NewRevenueRecognitionService(txRepo, subscriptionRepo, eventRepo)
+		revenueRecognitionHandler = handler.NewRevenueRecognitionHandler(recognitionSvc, partnerRepo, appRepo)
+		log.Println("Revenue recognition handler initialized")
+	}
+
+	// Initialize Revenue API subscription status service (app resolution for API keys and entitlements)
+	var revenueStatusSvc *apikeysvc.SubscriptionStatusService
+	if db != nil && partnerRepo != nil && appRepo != nil {
+		revenueStatusSvc = apikeysvc.NewSubscriptionStatusService(
+			apikeypersist.NewPostgresSubscriptionStatusRepository(db.Pool), appRepo, partnerRepo,
+		)
 	}
 
-	// Initialize API key handler
+	// Initialize API key handler and usage analytics (rollups refreshed in the background)
 	var apiKeyHandler *apikeyhandler.APIKeyHandler
+	var apiUsageHandler *apikeyhandler.APIUsageHandler
+	var apiUsageSvc *apikeysvc.APIUsageService
+	var apiKeyAuthMW *apikeymw.APIKeyAuth
+	var auditLoggerMW *apikeymw.AuditLogger
+	var apiKeyUsageTracker *apikeysvc.APIKeyUsageTracker
 	if db != nil {
 		apiKeyRepo := apikeypersist.NewPostgresAPIKeyRepository(db.Pool)
 		apiKeySvc := apikeysvc.NewAPIKeyService(apiKeyRepo)
+		if revenueStatusSvc != nil {
+			apiKeySvc.WithAppResolver(revenueStatusSvc)
+		}
 		apiKeyHandler = apikeyhandler.NewAPIKeyHandler(apiKeySvc)
-		log.Println("API key handler initialized")
+		apiKeyAuthMW = apikeymw.NewAPIKeyAuth(apiKeySvc)
+
+		// Requests the audit logger records also mark the key's last use
+		apiKeyUsageTracker = apikeysvc.NewAPIKeyUsageTracker(apiKeyRepo)
+		apiKeyUsageTracker.Start(ctx)
+		auditLoggerMW = apikeymw.NewAuditLogger(apikeypersist.NewPostgresAuditLogRepository(db.Pool)).
+			WithUsageTracker(apiKeyUsageTracker)
+
+		apiUsageSvc = apikeysvc.NewAPIUsageService(apikeypersist.NewPostgresAPIUsageRepository(db.Pool), apiKeyRepo)
+		apiUsageSvc.Start(ctx)
+		apiUsageHandler = apikeyhandler.NewAPIUsageHandler(apiUsageSvc)
+		log.Println("API key handler initialized, usage rollups and last-used tracking started")
+	}
+
+	// Initialize Revenue API rate limiting. Quotas live in Postgres or Redis so they
+	// hold across replicas; keys use their own per-minute limit.
+	var rateLimiterMW *apikeymw.RateLimiter
+	var rateLimitRedis *apikeycache.RedisClient
+	if cfg.RateLimit.Store == "redis" && cfg.RateLimit.RedisAddr != "" {
+		rateLimitRedis = apikeycache.NewRedisClient(cfg.RateLimit.RedisAddr).
+			WithPassword(cfg.RateLimit.RedisPassword).
+			WithDB(cfg.RateLimit.RedisDB)
+		rateLimiterMW = apikeymw.NewRateLimiter(apikeycache.NewRedisRateLimitStore(rateLimitRedis), 60, 60)
+		log.Printf("Rate limiter initialized (Redis at %s)", cfg.RateLimit.RedisAddr)
+	} else if db != nil {
+		if cfg.RateLimit.Store != "postgres" {
+			log.Printf("WARNING: rate limit store %q unusable (unknown, or redis without redis_addr); using Postgres", cfg.RateLimit.Store)
+		}
+		rateLimiterMW = apikeymw.NewRateLimiter(apikeypersist.NewPostgresRateLimitStore(db.Pool), 60, 60)
+		log.Println("Rate limiter initialized (Postgres)")
+	}
+	if rateLimiterMW != nil && cfg.RateLimit.UserLimitPerMinute > 0 {
+		rateLimiterMW.WithUserLimit(cfg.RateLimit.UserLimitPerMinute)
+	}
+
+	// Initialize Revenue API customer webhooks (endpoint management + delivery worker)
+	var webhookEndpointHandler *apikeyhandler.WebhookEndpointHandler
+	var webhookDispatcher *apikeysvc.WebhookDispatcher
+	if db != nil && encryptor != nil {
+		endpointRepo := apikeypersist.NewPostgresWebhookEndpointRepository(db.Pool)
+		deliveryRepo := apikeypersist.NewPostgresWebhookDeliveryRepository(db.Pool)
+
+		webhookEndpointSvc := apikeysvc.NewWebhookEndpointService(endpointRepo, deliveryRepo, encryptor).
+			WithURLGuard(webhookURLGuard)
+		webhookEndpointHandler = apikeyhandler.NewWebhookEndpointHandler(webhookEndpointSvc)
+
+		webhookDispatcher = apikeysvc.NewWebhookDispatcher(endpointRepo, deliveryRepo, encryptor).
+			WithURLGuard(webhookURLGuard)
+		webhookDispatcher.Start(ctx)
+		log.Println("Webhook endpoint handler initialized, dispatcher started")
+	}
+
+	// Initialize Shopify webhooks (per-app signing secrets + event processing)
+	var webhookHandler *handler.WebhookHandler
+	var webhookSecretHandler *handler.WebhookSecretHandler
+	var webhookDeliveryHandler *handler.WebhookDeliveryHandler
+	var webhookDeliveryService *appservice.WebhookDeliveryService
+	if db != nil && subscriptionRepo != nil && appRepo != nil && partnerRepo != nil && encryptor != nil {
+		webhookSecretSvc := appservice.NewWebhookSecretService(
+			persistence.NewPostgresWebhookSecretRepository(db.Pool), appRepo, encryptor,
+		).WithClientSecret(cfg.Shopify.ClientSecret)
+		webhookSecretHandler = handler.NewWebhookSecretHandler(webhookSecretSvc, partnerRepo, appRepo)
+
+		webhookService := appservice.NewWebhookService(subscriptionRepo, appRepo).
+			WithSubscriptionEventRepo(persistence.NewPostgresSubscriptionEventRepository(db.Pool)).
+			WithEventPublisher(apikeysvc.NewWebhookPublisher(
+				appRepo,
+				partnerRepo,
+				apikeypersist.NewPostgresWebhookEndpointRepository(db.Pool),
+				apikeypersist.NewPostgresWebhookDeliveryRepository(db.Pool),
+			)).
+			WithSignatureVerifier(webhookSecretSvc).
+			WithShopData(
+				persistence.NewPostgresShopDataRepository(db.Pool),
+				persistence.NewPostgresComplianceRequestRepository(db.Pool),
+			)
+		if readModelBuilder != nil {
+			webhookService.WithProjector(readModelBuilder)
+		}
+		if dunningService != nil {
+			webhookService.WithEventPublisher(dunningService)
+		}
+		if subscriptionChangeHub != nil {
+			webhookService.WithEventPublisher(subscriptionChangeHub)
+		}
+
+		// Deliveries are stored and acknowledged, then processed in the background
+		webhookDeliveryService = appservice.NewWebhookDeliveryService(
+			persistence.NewPostgresWebhookDeliveryRepository(db.Pool), webhookService,
+		)
+		webhookDeliveryService.Start(ctx)
+		webhookDeliveryHandler = handler.NewWebhookDeliveryHandler(webhookDeliveryService, partnerRepo, appRepo)
+
+		webhookHandler = handler.NewWebhookHandler(webhookService).WithDeliveryService(webhookDeliveryService)
+		log.Println("Shopify webhook handler initialized, delivery worker started")
+	}
+
+	// Initialize entitlement decisions (GET /v1/entitlements) and their per-app policies
+	var entitlementHandler *apikeyhandler.EntitlementHandler
+	var entitlementPolicyHandler *apikeyhandler.EntitlementPolicyHandler
+	if db != nil && revenueStatusSvc != nil {
+		entitlementSvc := apikeysvc.NewEntitlementService(revenueStatusSvc, apikeypersist.NewPostgresEntitlementPolicyRepository(db.Pool))
+		entitlementHandler = apikeyhandler.NewEntitlementHandler(entitlementSvc)
+		entitlementPolicyHandler = apikeyhandler.NewEntitlementPolicyHandler(entitlementSvc)
+		log.Println("Entitlement handlers initialized")
+	}
+
+	// Initialize Revenue API status lookups (REST and GraphQL) and usage reconciliation
+	var subscriptionStatusHandler *apikeyhandler.SubscriptionStatusHandler
+	var usageStatusHandler *apikeyhandler.UsageStatusHandler
+	var usageReconciliationHandler *apikeyhandler.UsageReconciliationHandler
+	var graphqlHandler *revenuegraphql.Handler
+	if db != nil && revenueStatusSvc != nil && subscriptionRepo != nil {
+		usageStatusRepo := apikeypersist.NewPostgresUsageStatusRepository(db.Pool)
+		usageStatusSvc := apikeysvc.NewUsageStatusService(
+			usageStatusRepo, apikeypersist.NewPostgresSubscriptionStatusRepository(db.Pool), appRepo, partnerRepo,
+		)
+		subscriptionStatusHandler = apikeyhandler.NewSubscriptionStatusHandler(revenueStatusSvc)
+		usageStatusHandler = apikeyhandler.NewUsageStatusHandler(usageStatusSvc)
+		usageReconciliationHandler = apikeyhandler.NewUsageReconciliationHandler(
+			apikeysvc.NewUsageReconciliationService(usageStatusRepo, subscriptionRepo, revenueStatusSvc),
+		)
+		graphqlHandler = revenuegraphql.NewHandler(revenuegraphql.NewResolver(revenueStatusSvc, usageStatusSvc))
+		log.Println("Revenue API status handlers initialized")
+	}
+
+	// Initialize Revenue API subscription stream (Server-Sent Events, API key auth)
+	var subscriptionStreamHandler *apikeyhandler.SubscriptionStreamHandler
+	if subscriptionChangeHub != nil && revenueStatusSvc != nil {
+		subscriptionStreamHandler = apikeyhandler.NewSubscriptionStreamHandler(subscriptionChangeHub, revenueStatusSvc)
+		log.Println("Subscription stream handler initialized")
 	}
 
 	// Initialize user preferences handler
@@ -263,15 +597,35 @@ func run() error {
 		log.Println("User preferences handler initialized")
 	}
 
+	// Initialize team workspaces. Invitations are emailed when SMTP is configured.
+	var workspaceService *appservice.WorkspaceService
+	var workspaceHandler *handler.WorkspaceHandler
+	if db != nil {
+		workspaceService = appservice.NewWorkspaceService(
+			persistence.NewPostgresWorkspaceRepository(db.Pool),
+			persistence.NewPostgresWorkspaceMemberRepository(db.Pool),
+			persistence.NewPostgresWorkspaceInvitationRepository(db.Pool),
+		)
+		if smtpSender != nil {
+			workspaceService.WithEmailSender(smtpSender)
+		}
+		workspaceHandler = handler.NewWorkspaceHandler(workspaceService)
+		log.Println("Workspace handler initialized")
+	}
+
 	// Initialize auth middleware
 	var authMW func(http.Handler) http.Handler
 	if firebaseAuth != nil && userRepo != nil {
 		authMiddleware := middleware.NewAuthMiddleware(firebaseAuth, userRepo)
+		if workspaceService != nil {
+			// Requests act in a workspace and roles come from its membership
+			authMiddleware.WithWorkspaces(workspaceService)
+		}
 		authMW = authMiddleware.Authenticate
 		log.Println("Auth middleware initialized")
 	}
 
-	// Initialize admin middleware (requires ADMIN or OWNER role)
+	// Initialize admin middleware (requires ADMIN or OWNER role, in the workspace when enabled)
 	adminMW := middleware.RequireRoles(valueobject.RoleAdmin, valueobject.RoleOwner)
 
 	// Initialize internal key middleware for service-to-service calls
@@ -284,30 +638,65 @@ func run() error {
 
 	// Build router config
 	routerCfg := router.Config{
-		HealthHandler:            healthHandler,
-		MeHandler:                meHandler,
-		OAuthHandler:             oauthHandler,
-		ManualTokenHandler:       manualTokenHandler,
-		IntegrationStatusHandler: integrationStatusHandler,
-		AppHandler:               appHandler,
-		MetricsHandler:           metricsHandler,
-		RevenueHandler:           revenueHandler,
-		FeeHandler:               feeHandler,
-		SyncHandler:              syncHandler,
-		SubscriptionHandler:      subscriptionHandler,
-		StoreHealthHandler:       storeHealthHandler,
-		UserPreferencesHandler:   userPreferencesHandler,
-		APIKeyHandler:            apiKeyHandler,
-		AuthMW:                   authMW,
-		AdminMW:                  adminMW,
-		InternalMW:               internalMW,
+		HealthHandler:                  healthHandler,
+		MeHandler:                      meHandler,
+		OAuthHandler:                   oauthHandler,
+		ManualTokenHandler:             manualTokenHandler,
+		IntegrationStatusHandler:       integrationStatusHandler,
+		AppHandler:                     appHandler,
+		MetricsHandler:                 metricsHandler,
+		RevenueHandler:                 revenueHandler,
+		FeeHandler:                     feeHandler,
+		TaxProfileHandler:              taxProfileHandler,
+		AccountingExportHandler:        accountingExportHandler,
+		RevenueRecognitionHandler:      revenueRecognitionHandler,
+		SyncHandler:                    syncHandler,
+		SubscriptionHandler:            subscriptionHandler,
+		StoreHealthHandler:             storeHealthHandler,
+		UserPreferencesHandler:         userPreferencesHandler,
+		WebhookHandler:                 webhookHandler,
+		WebhookSecretHandler:           webhookSecretHandler,
+		WebhookDeliveryHandler:         webhookDeliveryHandler,
+		AlertHandler:                   alertHandler,
+		DunningHandler:                 dunningHandler,
+		NotificationHandler:            notificationHandler,
+		NotificationEmailHandler:       notificationEmailHandler,
+		NotificationChannelHandler:     notificationChannelHandler,
+		NotificationPreferencesHandler: notificationPreferencesHandler,
+		WorkspaceHandler:               workspaceHandler,
+		APIKeyHandler:                  apiKeyHandler,
+		APIUsageHandler:                apiUsageHandler,
+		WebhookEndpointHandler:         webhookEndpointHandler,
+		EntitlementPolicyHandler:       entitlementPolicyHandler,
+		AuthMW:                         authMW,
+		AdminMW:                        adminMW,
+		InternalMW:                     internalMW,
 	}
 
 	r := router.New(routerCfg)
 
+	// The Revenue API (API key auth) is served under /v1 next to the dashboard API.
+	// Key management stays on the dashboard router under /api/v1/api-keys.
+	revenueRouter := revenuerouter.New(revenuerouter.Config{
+		SubscriptionStatusHandler:  subscriptionStatusHandler,
+		UsageStatusHandler:         usageStatusHandler,
+		UsageReconciliationHandler: usageReconciliationHandler,
+		SubscriptionStreamHandler:  subscriptionStreamHandler,
+		EntitlementHandler:         entitlementHandler,
+		WebhookEndpointHandler:     webhookEndpointHandler,
+		GraphQLHandler:             graphqlHandler,
+		APIKeyAuthMW:               apiKeyAuthMW,
+		RateLimiterMW:              rateLimiterMW,
+		AuditLoggerMW:              auditLoggerMW,
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/", revenueRouter)
+	mux.Handle("/", r)
+
 	server := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
-		Handler:      r,
+		Handler:      mux,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -331,6 +720,42 @@ func run() error {
 		syncScheduler.Stop()
 		log.Println("Sync scheduler stopped")
 	}
+	if readModelChecker != nil {
+		readModelChecker.Stop()
+		log.Println("Read model consistency checker stopped")
+	}
+	if webhookDispatcher != nil {
+		webhookDispatcher.Stop()
+		log.Println("Webhook dispatcher stopped")
+	}
+	if subscriptionChangeNotifier != nil {
+		subscriptionChangeNotifier.Stop()
+		log.Println("Subscription change listener stopped")
+	}
+	if apiUsageSvc != nil {
+		apiUsageSvc.Stop()
+		log.Println("API usage rollups stopped")
+	}
+	if apiKeyUsageTracker != nil {
+		apiKeyUsageTracker.Stop()
+		log.Println("API key last-used tracking stopped")
+	}
+	if webhookDeliveryService != nil {
+		webhookDeliveryService.Stop()
+		log.Println("Webhook delivery worker stopped")
+	}
+	if dunningService != nil {
+		dunningService.Stop()
+		log.Println("Dunning worker stopped")
+	}
+	if dailySummaryScheduler != nil {
+		dailySummaryScheduler.Stop()
+		log.Println("Daily summary scheduler stopped")
+	}
+	if notificationOutbox != nil {
+		notificationOutbox.Stop()
+		log.Println("Notification outbox worker stopped")
+	}
 
 	shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
@@ -338,6 +763,9 @@ func run() error {
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		return fmt.Errorf("server shutdown error: %w", err)
 	}
+	if rateLimitRedis != nil {
+		rateLimitRedis.Close()
+	}
 
 	log.Println("Server stopped")
 	return nil
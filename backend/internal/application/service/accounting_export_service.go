@@ -0,0 +1,327 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	domainservice "github.com/sachin-sivadasan/ledgerguard/internal/domain/service"
+)
+
+var (
+	// ErrPeriodAlreadyExported is returned when the requested period overlaps a previous export
+	// and the caller did not confirm re-exporting it
+	ErrPeriodAlreadyExported = errors.New("period has already been exported")
+
+	// ErrInvalidAccountingFormat is returned for unsupported accounting formats
+	ErrInvalidAccountingFormat = errors.New("unsupported accounting export format")
+
+	// ErrInvalidJournalGranularity is returned for unsupported summary granularities
+	ErrInvalidJournalGranularity = errors.New("granularity must be day or month")
+)
+
+// AccountingExportRequest describes a journal export
+type AccountingExportRequest struct {
+	AppID       uuid.UUID
+	UserID      *uuid.UUID
+	Format      entity.AccountingFormat
+	Granularity entity.JournalGranularity
+	From        time.Time // First day (inclusive)
+	To          time.Time // Last day (inclusive)
+	Confirm     bool      // Export even if the period overlaps a previous run
+}
+
+// AccountingExportService produces journal-style exports for QuickBooks and Xero
+type AccountingExportService struct {
+	transactionRepo repository.TransactionRepository
+	mappingRepo     repository.GLAccountMappingRepository
+	runRepo         repository.AccountingExportRunRepository
+	journalBuilder  *domainservice.JournalBuilder
+}
+
+// NewAccountingExportService creates a new accounting export service
+func NewAccountingExportService(
+	transactionRepo repository.TransactionRepository,
+	mappingRepo repository.GLAccountMappingRepository,
+	runRepo repository.AccountingExportRunRepository,
+) *AccountingExportService {
+	return &AccountingExportService{
+		transactionRepo: transactionRepo,
+		mappingRepo:     mappingRepo,
+		runRepo:         runRepo,
+		journalBuilder:  domainservice.NewJournalBuilder(),
+	}
+}
+
+// GetMapping returns the app's GL mapping, or the default chart of accounts if none is saved
+func (s *AccountingExportService) GetMapping(ctx context.Context, appID uuid.UUID) (*entity.GLAccountMapping, error) {
+	mapping, err := s.mappingRepo.FindByAppID(ctx, appID)
+	if errors.Is(err, repository.ErrGLAccountMappingNotFound) {
+		// No saved mapping, use defaults
+		return entity.NewGLAccountMapping(appID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// SaveMapping validates and stores the app's GL mapping
+func (s *AccountingExportService) SaveMapping(ctx context.Context, mapping *entity.GLAccountMapping) error {
+	if err := mapping.Validate(); err != nil {
+		return err
+	}
+	mapping.UpdatedAt = time.Now().UTC()
+	return s.mappingRepo.Upsert(ctx, mapping)
+}
+
+// FindOverlappingRuns returns previous exports that intersect the given period
+func (s *AccountingExportService) FindOverlappingRuns(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.AccountingExportRun, error) {
+	return s.runRepo.FindOverlapping(ctx, appID, truncateToDay(from), truncateToDay(to))
+}
+
+// ListRuns returns the export history for an app, newest first
+func (s *AccountingExportService) ListRuns(ctx context.Context, appID uuid.UUID, limit int) ([]*entity.AccountingExportRun, error) {
+	return s.runRepo.FindByAppID(ctx, appID, limit)
+}
+
+// Export builds summarized journals for the period, renders them in the requested format
+// and records the run. Returns ErrPeriodAlreadyExported if the period overlaps a previous
+// run and req.Confirm is false.
+func (s *AccountingExportService) Export(ctx context.Context, req AccountingExportRequest) (*ExportResult, *entity.AccountingExportRun, error) {
+	if !req.Format.IsValid() {
+		return nil, nil, ErrInvalidAccountingFormat
+	}
+	if !req.Granularity.IsValid() {
+		return nil, nil, ErrInvalidJournalGranularity
+	}
+
+	from := truncateToDay(req.From)
+	to := truncateToDay(req.To)
+	if to.Before(from) {
+		return nil, nil, ErrInvalidDateRange
+	}
+
+	previous, err := s.runRepo.FindOverlapping(ctx, req.AppID, from, to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check export history: %w", err)
+	}
+	if len(previous) > 0 && !req.Confirm {
+		return nil, nil, ErrPeriodAlreadyExported
+	}
+
+	mapping, err := s.GetMapping(ctx, req.AppID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Include the entire end day: [from, to+1 day)
+	end := to.AddDate(0, 0, 1)
+	fetched, err := s.transactionRepo.FindByAppID(ctx, req.AppID, from, end)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+	transactions := make([]*entity.Transaction, 0, len(fetched))
+	for _, tx := range fetched {
+		if tx.TransactionDate.Before(end) {
+			transactions = append(transactions, tx)
+		}
+	}
+
+	entries := s.journalBuilder.Build(transactions, mapping, req.Granularity)
+
+	var data []byte
+	var contentType, ext string
+	switch req.Format {
+	case entity.AccountingFormatQuickBooksIIF:
+		data, err = journalsToIIF(entries)
+		contentType, ext = "text/plain", ".iif"
+	case entity.AccountingFormatQuickBooksCSV:
+		data, err = journalsToQuickBooksCSV(entries)
+		contentType, ext = "text/csv", ".csv"
+	case entity.AccountingFormatXeroCSV:
+		data, err = journalsToXeroCSV(entries, mapping.XeroTaxRate)
+		contentType, ext = "text/csv", ".csv"
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	run := entity.NewAccountingExportRun(req.AppID, req.UserID, req.Format, req.Granularity, from, to)
+	run.JournalCount = len(entries)
+	run.TransactionCount = len(transactions)
+	for _, e := range entries {
+		run.TotalDebitCents += e.TotalDebitCents()
+	}
+
+	// Re-checked while recording, in case another export of the period finished meanwhile
+	previous, err = s.runRepo.CreateIfNoOverlap(ctx, run, req.Confirm)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to record export run: %w", err)
+	}
+	if len(previous) > 0 && !req.Confirm {
+		return nil, nil, ErrPeriodAlreadyExported
+	}
+
+	return &ExportResult{
+		Data:        data,
+		ContentType: contentType,
+		Filename: fmt.Sprintf("journal_%s_%s_%s_to_%s%s",
+			req.Format,
+			req.AppID.String()[:8],
+			from.Format("2006-01-02"),
+			to.Format("2006-01-02"),
+			ext,
+		),
+		RecordCount: len(entries),
+	}, run, nil
+}
+
+// journalsToIIF renders journals as QuickBooks Desktop IIF general journal transactions.
+// Debits are positive and credits negative; the first line of each entry is the TRNS row.
+func journalsToIIF(entries []*entity.JournalEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Comma = '\t'
+
+	headers := [][]string{
+		{"!TRNS", "TRNSID", "TRNSTYPE", "DATE", "ACCNT", "AMOUNT", "DOCNUM", "MEMO"},
+		{"!SPL", "SPLID", "TRNSTYPE", "DATE", "ACCNT", "AMOUNT", "DOCNUM", "MEMO"},
+		{"!ENDTRNS"},
+	}
+	if err := writer.WriteAll(headers); err != nil {
+		return nil, fmt.Errorf("failed to write IIF header: %w", err)
+	}
+
+	for _, e := range entries {
+		date := e.Date.Format("01/02/2006")
+		docNum := fmt.Sprintf("LG-%d", e.Number)
+		for i, line := range e.Lines {
+			rowType := "SPL"
+			if i == 0 {
+				rowType = "TRNS"
+			}
+			row := []string{
+				rowType, "", "GENERAL JOURNAL", date, line.AccountCode,
+				formatSignedCents(line.DebitCents - line.CreditCents),
+				docNum, e.Memo + " - " + line.Description,
+			}
+			if err := writer.Write(row); err != nil {
+				return nil, fmt.Errorf("failed to write IIF row: %w", err)
+			}
+		}
+		if err := writer.Write([]string{"ENDTRNS"}); err != nil {
+			return nil, fmt.Errorf("failed to write IIF row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("IIF writer error: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// journalsToQuickBooksCSV renders journals in the QuickBooks Online journal entry import layout
+func journalsToQuickBooksCSV(entries []*entity.JournalEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := []string{"JournalNo", "JournalDate", "Currency", "Memo", "AccountName", "Debits", "Credits", "Description"}
+	if err := writer.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, e := range entries {
+		for _, line := range e.Lines {
+			row := []string{
+				fmt.Sprintf("LG-%d", e.Number),
+				e.Date.Format("01/02/2006"),
+				e.Currency,
+				e.Memo,
+				line.AccountCode,
+				formatOptionalCents(line.DebitCents),
+				formatOptionalCents(line.CreditCents),
+				line.Description,
+			}
+			if err := writer.Write(row); err != nil {
+				return nil, fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("CSV writer error: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// journalsToXeroCSV renders journals in the Xero manual journal import layout.
+// Xero expects positive amounts for debits and negative for credits.
+func journalsToXeroCSV(entries []*entity.JournalEntry, taxRate string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := []string{"*Narration", "*Date", "Description", "*AccountCode", "*TaxRate", "*Amount", "TrackingName1", "TrackingOption1"}
+	if err := writer.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, e := range entries {
+		for _, line := range e.Lines {
+			row := []string{
+				e.Memo,
+				e.Date.Format("2006-01-02"),
+				line.Description,
+				line.AccountCode,
+				taxRate,
+				formatSignedCents(line.DebitCents - line.CreditCents),
+				"Currency",
+				e.Currency,
+			}
+			if err := writer.Write(row); err != nil {
+				return nil, fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("CSV writer error: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// formatSignedCents formats cents as a signed decimal amount (e.g., -12.34)
+func formatSignedCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// formatOptionalCents formats cents as a decimal amount, or empty when zero
+func formatOptionalCents(cents int64) string {
+	if cents == 0 {
+		return ""
+	}
+	return formatSignedCents(cents)
+}
+
+// truncateToDay truncates a time to the start of its UTC day
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
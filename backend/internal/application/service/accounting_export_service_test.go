@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+type mockGLMappingRepo struct {
+	mappings map[uuid.UUID]*entity.GLAccountMapping
+	err      error
+}
+
+func newMockGLMappingRepo() *mockGLMappingRepo {
+	return &mockGLMappingRepo{mappings: make(map[uuid.UUID]*entity.GLAccountMapping)}
+}
+
+func (m *mockGLMappingRepo) FindByAppID(ctx context.Context, appID uuid.UUID) (*entity.GLAccountMapping, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	mapping, ok := m.mappings[appID]
+	if !ok {
+		return nil, repository.ErrGLAccountMappingNotFound
+	}
+	return mapping, nil
+}
+
+func (m *mockGLMappingRepo) Upsert(ctx context.Context, mapping *entity.GLAccountMapping) error {
+	m.mappings[mapping.AppID] = mapping
+	return nil
+}
+
+type mockExportRunRepo struct {
+	runs []*entity.AccountingExportRun
+}
+
+func (m *mockExportRunRepo) CreateIfNoOverlap(ctx context.Context, run *entity.AccountingExportRun, allowOverlap bool) ([]*entity.AccountingExportRun, error) {
+	overlapping, _ := m.FindOverlapping(ctx, run.AppID, run.PeriodStart, run.PeriodEnd)
+	if len(overlapping) > 0 && !allowOverlap {
+		return overlapping, nil
+	}
+	run.Confirmed = len(overlapping) > 0
+	m.runs = append(m.runs, run)
+	return overlapping, nil
+}
+
+func (m *mockExportRunRepo) FindByAppID(ctx context.Context, appID uuid.UUID, limit int) ([]*entity.AccountingExportRun, error) {
+	var result []*entity.AccountingExportRun
+	for _, r := range m.runs {
+		if r.AppID == appID {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockExportRunRepo) FindOverlapping(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.AccountingExportRun, error) {
+	var result []*entity.AccountingExportRun
+	for _, r := range m.runs {
+		if r.AppID == appID && r.Overlaps(from, to) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func newAccountingTestService(appID uuid.UUID) (*AccountingExportService, *mockExportRunRepo, *mockGLMappingRepo) {
+	txRepo := &mockTxRepo{
+		transactions: []*entity.Transaction{
+			{
+				ID:                 uuid.New(),
+				AppID:              appID,
+				ChargeType:         valueobject.ChargeTypeRecurring,
+				GrossAmountCents:   4900,
+				ShopifyFeeCents:    980,
+				ProcessingFeeCents: 142,
+				NetAmountCents:     3778,
+				Currency:           "USD",
+				TransactionDate:    time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC),
+			},
+			{
+				ID:                 uuid.New(),
+				AppID:              appID,
+				ChargeType:         valueobject.ChargeTypeUsage,
+				GrossAmountCents:   1000,
+				ProcessingFeeCents: 29,
+				NetAmountCents:     971,
+				Currency:           "USD",
+				TransactionDate:    time.Date(2026, 3, 31, 18, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	runRepo := &mockExportRunRepo{}
+	mappingRepo := newMockGLMappingRepo()
+	return NewAccountingExportService(txRepo, mappingRepo, runRepo), runRepo, mappingRepo
+}
+
+func TestAccountingExportService_Export_XeroMonthly(t *testing.T) {
+	appID := uuid.New()
+	svc, runRepo, _ := newAccountingTestService(appID)
+
+	result, run, err := svc.Export(context.Background(), AccountingExportRequest{
+		AppID:       appID,
+		Format:      entity.AccountingFormatXeroCSV,
+		Granularity: entity.JournalGranularityMonth,
+		From:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if run.JournalCount != 1 {
+		t.Errorf("JournalCount = %d, want 1", run.JournalCount)
+	}
+	if run.TransactionCount != 2 {
+		t.Errorf("TransactionCount = %d, want 2 (end day must be inclusive)", run.TransactionCount)
+	}
+	if run.TotalDebitCents != 5900 {
+		t.Errorf("TotalDebitCents = %d, want 5900", run.TotalDebitCents)
+	}
+	if len(runRepo.runs) != 1 {
+		t.Errorf("expected run to be recorded")
+	}
+
+	csv := string(result.Data)
+	if !strings.HasPrefix(csv, "*Narration,*Date") {
+		t.Errorf("unexpected Xero header: %q", strings.SplitN(csv, "\n", 2)[0])
+	}
+	if !strings.Contains(csv, ",4000,Tax Exempt,-49.00,") {
+		t.Errorf("expected recurring revenue credit line, got:\n%s", csv)
+	}
+	if !strings.Contains(csv, ",1210,Tax Exempt,47.49,") {
+		t.Errorf("expected clearing debit line, got:\n%s", csv)
+	}
+	if !strings.HasSuffix(result.Filename, ".csv") {
+		t.Errorf("Filename = %s, want .csv suffix", result.Filename)
+	}
+}
+
+func TestAccountingExportService_Export_RequiresConfirmationForExportedPeriod(t *testing.T) {
+	appID := uuid.New()
+	svc, runRepo, _ := newAccountingTestService(appID)
+
+	req := AccountingExportRequest{
+		AppID:       appID,
+		Format:      entity.AccountingFormatQuickBooksCSV,
+		Granularity: entity.JournalGranularityDay,
+		From:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	if _, _, err := svc.Export(context.Background(), req); err != nil {
+		t.Fatalf("first export failed: %v", err)
+	}
+
+	// Overlapping period without confirmation
+	req.From = time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	req.To = time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+	if _, _, err := svc.Export(context.Background(), req); !errors.Is(err, ErrPeriodAlreadyExported) {
+		t.Fatalf("err = %v, want ErrPeriodAlreadyExported", err)
+	}
+	if len(runRepo.runs) != 1 {
+		t.Errorf("rejected export should not be recorded, runs = %d", len(runRepo.runs))
+	}
+
+	// Confirmed re-export
+	req.Confirm = true
+	_, run, err := svc.Export(context.Background(), req)
+	if err != nil {
+		t.Fatalf("confirmed export failed: %v", err)
+	}
+	if !run.Confirmed {
+		t.Error("expected run to be marked as confirmed re-export")
+	}
+
+	// Adjacent period does not need confirmation
+	req.Confirm = false
+	req.From = time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC)
+	req.To = time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)
+	if _, _, err := svc.Export(context.Background(), req); err != nil {
+		t.Errorf("adjacent period export failed: %v", err)
+	}
+}
+
+// racingTxRepo records an overlapping export while the transactions are being
+// fetched, as a concurrent request for the same period would
+type racingTxRepo struct {
+	*mockTxRepo
+	runRepo *mockExportRunRepo
+	run     *entity.AccountingExportRun
+}
+
+func (m *racingTxRepo) FindByAppID(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.Transaction, error) {
+	if m.run != nil {
+		m.runRepo.runs = append(m.runRepo.runs, m.run)
+		m.run = nil
+	}
+	return m.mockTxRepo.FindByAppID(ctx, appID, from, to)
+}
+
+func TestAccountingExportService_Export_ConcurrentExportOfPeriod(t *testing.T) {
+	appID := uuid.New()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	runRepo := &mockExportRunRepo{}
+	txRepo := &racingTxRepo{
+		mockTxRepo: &mockTxRepo{},
+		runRepo:    runRepo,
+		run: entity.NewAccountingExportRun(appID, nil, entity.AccountingFormatXeroCSV,
+			entity.JournalGranularityMonth, from, to),
+	}
+	svc := NewAccountingExportService(txRepo, newMockGLMappingRepo(), runRepo)
+
+	_, _, err := svc.Export(context.Background(), AccountingExportRequest{
+		AppID:       appID,
+		Format:      entity.AccountingFormatXeroCSV,
+		Granularity: entity.JournalGranularityMonth,
+		From:        from,
+		To:          to,
+	})
+	if !errors.Is(err, ErrPeriodAlreadyExported) {
+		t.Fatalf("err = %v, want ErrPeriodAlreadyExported", err)
+	}
+	if len(runRepo.runs) != 1 {
+		t.Errorf("only the concurrent export should be recorded, runs = %d", len(runRepo.runs))
+	}
+}
+
+func TestAccountingExportService_Export_IncludesWholeEndDay(t *testing.T) {
+	appID := uuid.New()
+	txRepo := &mockTxRepo{
+		transactions: []*entity.Transaction{
+			{
+				ID:               uuid.New(),
+				AppID:            appID,
+				ChargeType:       valueobject.ChargeTypeUsage,
+				GrossAmountCents: 1000,
+				NetAmountCents:   1000,
+				Currency:         "USD",
+				TransactionDate:  time.Date(2026, 3, 31, 23, 59, 59, 500_000_000, time.UTC),
+			},
+			{
+				ID:               uuid.New(),
+				AppID:            appID,
+				ChargeType:       valueobject.ChargeTypeUsage,
+				GrossAmountCents: 1000,
+				NetAmountCents:   1000,
+				Currency:         "USD",
+				TransactionDate:  time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	svc := NewAccountingExportService(txRepo, newMockGLMappingRepo(), &mockExportRunRepo{})
+
+	_, run, err := svc.Export(context.Background(), AccountingExportRequest{
+		AppID:       appID,
+		Format:      entity.AccountingFormatQuickBooksCSV,
+		Granularity: entity.JournalGranularityDay,
+		From:        time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.TransactionCount != 1 {
+		t.Errorf("TransactionCount = %d, want 1 (last sub-second of the day in, next midnight out)", run.TransactionCount)
+	}
+}
+
+func TestAccountingExportService_Export_QuickBooksIIF(t *testing.T) {
+	appID := uuid.New()
+	svc, _, mappingRepo := newAccountingTestService(appID)
+
+	mapping := entity.NewGLAccountMapping(appID)
+	mapping.RecurringRevenueAccount = "Subscription Income"
+	mapping.ClearingAccount = "Shopify Clearing"
+	mappingRepo.mappings[appID] = mapping
+
+	result, _, err := svc.Export(context.Background(), AccountingExportRequest{
+		AppID:       appID,
+		Format:      entity.AccountingFormatQuickBooksIIF,
+		Granularity: entity.JournalGranularityDay,
+		From:        time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	iif := string(result.Data)
+	if !strings.HasPrefix(iif, "!TRNS\t") {
+		t.Errorf("expected IIF header, got %q", strings.SplitN(iif, "\n", 2)[0])
+	}
+	if !strings.Contains(iif, "TRNS\t\tGENERAL JOURNAL\t03/10/2026\tShopify Clearing\t37.78\t") {
+		t.Errorf("expected TRNS clearing line, got:\n%s", iif)
+	}
+	if !strings.Contains(iif, "SPL\t\tGENERAL JOURNAL\t03/10/2026\tSubscription Income\t-49.00\t") {
+		t.Errorf("expected SPL revenue line, got:\n%s", iif)
+	}
+	if strings.Count(iif, "\nENDTRNS") != 1 {
+		t.Errorf("expected exactly one journal, got:\n%s", iif)
+	}
+}
+
+func TestAccountingExportService_Export_InvalidRequest(t *testing.T) {
+	appID := uuid.New()
+	svc, _, _ := newAccountingTestService(appID)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		req  AccountingExportRequest
+		want error
+	}{
+		{"bad format", AccountingExportRequest{AppID: appID, Format: "ofx", Granularity: entity.JournalGranularityDay, From: from, To: from}, ErrInvalidAccountingFormat},
+		{"bad granularity", AccountingExportRequest{AppID: appID, Format: entity.AccountingFormatXeroCSV, Granularity: "week", From: from, To: from}, ErrInvalidJournalGranularity},
+		{"reversed range", AccountingExportRequest{AppID: appID, Format: entity.AccountingFormatXeroCSV, Granularity: entity.JournalGranularityDay, From: from, To: from.AddDate(0, 0, -1)}, ErrInvalidDateRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := svc.Export(context.Background(), tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccountingExportService_SaveMapping_Validates(t *testing.T) {
+	appID := uuid.New()
+	svc, _, mappingRepo := newAccountingTestService(appID)
+
+	mapping := entity.NewGLAccountMapping(appID)
+	mapping.ClearingAccount = ""
+	if err := svc.SaveMapping(context.Background(), mapping); !errors.Is(err, entity.ErrGLAccountRequired) {
+		t.Errorf("err = %v, want ErrGLAccountRequired", err)
+	}
+
+	mapping.ClearingAccount = "1200"
+	if err := svc.SaveMapping(context.Background(), mapping); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ := svc.GetMapping(context.Background(), appID)
+	if got.ClearingAccount != "1200" {
+		t.Errorf("ClearingAccount = %s, want 1200", got.ClearingAccount)
+	}
+	if _, ok := mappingRepo.mappings[appID]; !ok {
+		t.Error("expected mapping to be stored")
+	}
+}
+
+func TestAccountingExportService_GetMapping(t *testing.T) {
+	t.Run("falls back to defaults when no mapping is saved", func(t *testing.T) {
+		appID := uuid.New()
+		svc, _, _ := newAccountingTestService(appID)
+
+		got, err := svc.GetMapping(context.Background(), appID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := entity.NewGLAccountMapping(appID); got.ClearingAccount != want.ClearingAccount {
+			t.Errorf("ClearingAccount = %s, want default %s", got.ClearingAccount, want.ClearingAccount)
+		}
+	})
+
+	t.Run("returns repository errors instead of defaults", func(t *testing.T) {
+		appID := uuid.New()
+		svc, _, mappingRepo := newAccountingTestService(appID)
+		dbErr := errors.New("connection refused")
+		mappingRepo.err = dbErr
+
+		got, err := svc.GetMapping(context.Background(), appID)
+		if !errors.Is(err, dbErr) {
+			t.Errorf("err = %v, want %v", err, dbErr)
+		}
+		if got != nil {
+			t.Errorf("mapping = %+v, want nil", got)
+		}
+	})
+}
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+const maxAlertList = 200
+
+var (
+	// ErrAlertRuleNotFound is returned when the rule does not belong to the user and app
+	ErrAlertRuleNotFound = errors.New("alert rule not found")
+
+	// ErrAlertNotFound is returned when the alert does not belong to the user and app
+	ErrAlertNotFound = errors.New("alert not found")
+)
+
+// AlertService manages user-defined alert rules and evaluates them against an
+// app's latest metrics. A rule fires once per key; it fires again only after
+// its alert is resolved and the cooldown has passed. Alerts whose condition
+// has cleared are resolved automatically. A triggered alert is stored with
+// its notification, which the notification outbox delivers.
+type AlertService struct {
+	ruleRepo       repository.AlertRuleRepository
+	alertRepo      repository.AlertRepository
+	snapshotRepo   repository.DailyMetricsSnapshotRepository
+	subRepo        repository.SubscriptionRepository
+	txRepo         repository.TransactionRepository
+	syncStatusRepo repository.AppSyncStatusRepository
+	now            func() time.Time
+}
+
+// NewAlertService creates a new AlertService
+func NewAlertService(
+	ruleRepo repository.AlertRuleRepository,
+	alertRepo repository.AlertRepository,
+	snapshotRepo repository.DailyMetricsSnapshotRepository,
+	subRepo repository.SubscriptionRepository,
+	txRepo repository.TransactionRepository,
+	syncStatusRepo repository.AppSyncStatusRepository,
+) *AlertService {
+	return &AlertService{
+		ruleRepo:       ruleRepo,
+		alertRepo:      alertRepo,
+		snapshotRepo:   snapshotRepo,
+		subRepo:        subRepo,
+		txRepo:         txRepo,
+		syncStatusRepo: syncStatusRepo,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// ListRules returns the user's rules for an app
+func (s *AlertService) ListRules(ctx context.Context, userID, appID uuid.UUID) ([]*entity.AlertRule, error) {
+	rules, err := s.ruleRepo.FindByUserAndApp(ctx, userID, appID)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []*entity.AlertRule{}
+	}
+	return rules, nil
+}
+
+// CreateRule validates and stores a new rule
+func (s *AlertService) CreateRule(ctx context.Context, rule *entity.AlertRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	return s.ruleRepo.Create(ctx, rule)
+}
+
+// UpdateRule replaces the settings of a rule owned by the user on the same app
+func (s *AlertService) UpdateRule(ctx context.Context, rule *entity.AlertRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	existing, err := s.findRule(ctx, rule.UserID, rule.AppID, rule.ID)
+	if err != nil {
+		return err
+	}
+
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = s.now()
+	return s.ruleRepo.Update(ctx, rule)
+}
+
+// DeleteRule removes a rule owned by the user, with its alert history
+func (s *AlertService) DeleteRule(ctx context.Context, userID, appID, id uuid.UUID) error {
+	if _, err := s.findRule(ctx, userID, appID, id); err != nil {
+		return err
+	}
+	return s.ruleRepo.Delete(ctx, userID, id)
+}
+
+// ListAlerts returns the most recent alerts matching the filter
+func (s *AlertService) ListAlerts(ctx context.Context, filter repository.AlertFilter) ([]*entity.Alert, error) {
+	if filter.Limit <= 0 || filter.Limit > maxAlertList {
+		filter.Limit = maxAlertList
+	}
+
+	alerts, err := s.alertRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if alerts == nil {
+		alerts = []*entity.Alert{}
+	}
+	return alerts, nil
+}
+
+// AcknowledgeAlert marks an open alert as seen
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, userID, appID, id uuid.UUID) (*entity.Alert, error) {
+	alert, err := s.findAlert(ctx, userID, appID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := alert.Acknowledge(s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.alertRepo.Update(ctx, alert); err != nil {
+		return nil, fmt.Errorf("failed to update alert: %w", err)
+	}
+	return alert, nil
+}
+
+// ResolveAlert closes an alert. The rule can fire again for the same key once its cooldown has passed.
+func (s *AlertService) ResolveAlert(ctx context.Context, userID, appID, id uuid.UUID) (*entity.Alert, error) {
+	alert, err := s.findAlert(ctx, userID, appID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := alert.Resolve(s.now(), false); err != nil {
+		return nil, err
+	}
+	if err := s.alertRepo.Update(ctx, alert); err != nil {
+		return nil, fmt.Errorf("failed to update alert: %w", err)
+	}
+	return alert, nil
+}
+
+// EvaluateApp evaluates every enabled rule on the app, triggering alerts for
+// conditions that newly hold and resolving alerts whose condition cleared.
+// A failing rule does not stop the others; the last error is returned.
+func (s *AlertService) EvaluateApp(ctx context.Context, appID uuid.UUID) error {
+	rules, err := s.ruleRepo.FindEnabledByAppID(ctx, appID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch alert rules: %w", err)
+	}
+
+	var lastErr error
+	for _, rule := range rules {
+		if err := s.evaluateRule(ctx, rule); err != nil {
+			lastErr = fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+	}
+	return lastErr
+}
+
+// alertCondition is a rule condition that currently holds for a key
+type alertCondition struct {
+	key     string
+	title   string
+	message string
+}
+
+func (s *AlertService) evaluateRule(ctx context.Context, rule *entity.AlertRule) error {
+	now := s.now()
+
+	conditions, evaluated, err := s.checkRule(ctx, rule, now)
+	if err != nil {
+		return err
+	}
+	if !evaluated {
+		return nil // Not enough data; keep existing alerts as they are
+	}
+
+	firing := make(map[string]bool, len(conditions))
+	for _, c := range conditions {
+		firing[c.key] = true
+
+		latest, err := s.alertRepo.FindLatestByRuleAndKey(ctx, rule.ID, c.key)
+		if err != nil {
+			return fmt.Errorf("failed to fetch latest alert: %w", err)
+		}
+		if latest != nil && (latest.Status != entity.AlertStatusResolved || now.Sub(latest.TriggeredAt) < rule.Cooldown()) {
+			continue
+		}
+
+		alert := entity.NewAlert(rule, c.key, c.title, c.message, now)
+		if err := s.alertRepo.Create(ctx, alert, newAlertNotification(alert, rule.Channels)); err != nil {
+			return fmt.Errorf("failed to store alert: %w", err)
+		}
+	}
+
+	unresolved, err := s.alertRepo.FindUnresolvedByRuleID(ctx, rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch unresolved alerts: %w", err)
+	}
+	for _, alert := range unresolved {
+		if firing[alert.Key] {
+			continue
+		}
+		if err := alert.Resolve(now, true); err != nil {
+			continue
+		}
+		if err := s.alertRepo.Update(ctx, alert); err != nil {
+			return fmt.Errorf("failed to resolve alert: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkRule returns the conditions that hold for the rule, and false if the
+// data needed to evaluate it is missing
+func (s *AlertService) checkRule(ctx context.Context, rule *entity.AlertRule, now time.Time) ([]alertCondition, bool, error) {
+	switch rule.Type {
+	case entity.AlertRuleMRRDrop:
+		return s.checkMRRDrop(ctx, rule, now)
+	case entity.AlertRuleShopAtRisk:
+		return s.checkShopAtRisk(ctx, rule)
+	case entity.AlertRuleUsageRevenueZero:
+		return s.checkUsageRevenueZero(ctx, rule, now)
+	case entity.AlertRuleSyncFailing:
+		return s.checkSyncFailing(ctx, rule, now)
+	}
+	return nil, false, nil
+}
+
+// checkMRRDrop compares today's active MRR with yesterday's snapshot
+func (s *AlertService) checkMRRDrop(ctx context.Context, rule *entity.AlertRule, now time.Time) ([]alertCondition, bool, error) {
+	day := truncateToDay(now)
+	today, err := s.snapshotRepo.FindByAppIDAndDate(ctx, rule.AppID, day)
+	if err != nil || today == nil {
+		return nil, false, nil
+	}
+	yesterday, err := s.snapshotRepo.FindByAppIDAndDate(ctx, rule.AppID, day.AddDate(0, 0, -1))
+	if err != nil || yesterday == nil || yesterday.ActiveMRRCents <= 0 {
+		return nil, false, nil
+	}
+
+	drop := float64(yesterday.ActiveMRRCents-today.ActiveMRRCents) / float64(yesterday.ActiveMRRCents) * 100
+	if drop <= rule.Threshold {
+		return nil, true, nil
+	}
+
+	return []alertCondition{{
+		title: rule.Name,
+		message: fmt.Sprintf("Active MRR dropped %.1f%% day over day, from $%.2f to $%.2f",
+			drop, float64(yesterday.ActiveMRRCents)/100, float64(today.ActiveMRRCents)/100),
+	}}, true, nil
+}
+
+// checkShopAtRisk fires once per shop in the rule's risk state worth more than the threshold
+func (s *AlertService) checkShopAtRisk(ctx context.Context, rule *entity.AlertRule) ([]alertCondition, bool, error) {
+	subs, err := s.subRepo.FindByRiskState(ctx, rule.AppID, rule.RiskState)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch subscriptions: %w", err)
+	}
+
+	var conditions []alertCondition
+	for _, sub := range subs {
+		mrr := sub.MRRCents()
+		if float64(mrr) <= rule.Threshold {
+			continue
+		}
+		shop := sub.ShopName
+		if shop == "" {
+			shop = sub.MyshopifyDomain
+		}
+		conditions = append(conditions, alertCondition{
+			key:     sub.MyshopifyDomain,
+			title:   fmt.Sprintf("%s: %s", rule.Name, shop),
+			message: fmt.Sprintf("%s ($%.2f/month) moved to %s", sub.MyshopifyDomain, float64(mrr)/100, rule.RiskState),
+		})
+	}
+	return conditions, true, nil
+}
+
+// checkUsageRevenueZero fires when no usage charge was earned within the threshold's days
+func (s *AlertService) checkUsageRevenueZero(ctx context.Context, rule *entity.AlertRule, now time.Time) ([]alertCondition, bool, error) {
+	window := time.Duration(rule.Threshold * float64(24*time.Hour))
+	txs, err := s.txRepo.FindByAppID(ctx, rule.AppID, now.Add(-window), now)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	for _, tx := range txs {
+		if tx.ChargeType == valueobject.ChargeTypeUsage && tx.NetAmountCents > 0 {
+			return nil, true, nil
+		}
+	}
+
+	return []alertCondition{{
+		title:   rule.Name,
+		message: fmt.Sprintf("No usage revenue in the last %g days", rule.Threshold),
+	}}, true, nil
+}
+
+// checkSyncFailing fires when the app's syncs have failed for longer than the threshold's hours
+func (s *AlertService) checkSyncFailing(ctx context.Context, rule *entity.AlertRule, now time.Time) ([]alertCondition, bool, error) {
+	status, err := s.syncStatusRepo.FindByAppID(ctx, rule.AppID)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	window := time.Duration(rule.Threshold * float64(time.Hour))
+	if status.FailingSince == nil || now.Sub(*status.FailingSince) < window {
+		return nil, true, nil
+	}
+
+	return []alertCondition{{
+		title: rule.Name,
+		message: fmt.Sprintf("Syncs have failed since %s (%d attempts): %s",
+			status.FailingSince.Format(time.RFC3339), status.ConsecutiveFailures, status.LastError),
+	}}, true, nil
+}
+
+// newAlertNotification creates the outbox notification for a triggered alert
+func newAlertNotification(alert *entity.Alert, channels []entity.NotificationChannel) *entity.Notification {
+	n := entity.NewNotification(alert.UserID, entity.NotificationKindAlert, alert.Severity, alert.Title, alert.Message, channels, alert.TriggeredAt)
+	appID, alertID := alert.AppID, alert.ID
+	n.AppID = &appID
+	n.SourceID = &alertID
+	return n
+}
+
+func (s *AlertService) findRule(ctx context.Context, userID, appID, id uuid.UUID) (*entity.AlertRule, error) {
+	rule, err := s.ruleRepo.FindByID(ctx, id)
+	if err != nil || rule.UserID != userID || rule.AppID != appID {
+		return nil, ErrAlertRuleNotFound
+	}
+	return rule, nil
+}
+
+func (s *AlertService) findAlert(ctx context.Context, userID, appID, id uuid.UUID) (*entity.Alert, error) {
+	alert, err := s.alertRepo.FindByID(ctx, id)
+	if err != nil || alert.UserID != userID || alert.AppID != appID {
+		return nil, ErrAlertNotFound
+	}
+	return alert, nil
+}
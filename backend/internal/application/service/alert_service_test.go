@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+type mockAlertRuleRepo struct {
+	rules []*entity.AlertRule
+}
+
+func (m *mockAlertRuleRepo) Create(ctx context.Context, rule *entity.AlertRule) error {
+	m.rules = append(m.rules, rule)
+	return nil
+}
+
+func (m *mockAlertRuleRepo) Update(ctx context.Context, rule *entity.AlertRule) error {
+	for i, r := range m.rules {
+		if r.ID == rule.ID {
+			m.rules[i] = rule
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *mockAlertRuleRepo) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	for i, r := range m.rules {
+		if r.ID == id && r.UserID == userID {
+			m.rules = append(m.rules[:i], m.rules[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *mockAlertRuleRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.AlertRule, error) {
+	for _, r := range m.rules {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockAlertRuleRepo) FindByUserAndApp(ctx context.Context, userID, appID uuid.UUID) ([]*entity.AlertRule, error) {
+	var result []*entity.AlertRule
+	for _, r := range m.rules {
+		if r.UserID == userID && r.AppID == appID {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockAlertRuleRepo) FindEnabledByAppID(ctx context.Context, appID uuid.UUID) ([]*entity.AlertRule, error) {
+	var result []*entity.AlertRule
+	for _, r := range m.rules {
+		if r.AppID == appID && r.Enabled {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+type mockAlertRepo struct {
+	alerts        []*entity.Alert
+	notifications []*entity.Notification
+}
+
+func (m *mockAlertRepo) Create(ctx context.Context, alert *entity.Alert, notification *entity.Notification) error {
+	m.alerts = append(m.alerts, alert)
+	if notification != nil {
+		m.notifications = append(m.notifications, notification)
+	}
+	return nil
+}
+
+func (m *mockAlertRepo) Update(ctx context.Context, alert *entity.Alert) error {
+	return nil
+}
+
+func (m *mockAlertRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.Alert, error) {
+	for _, a := range m.alerts {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockAlertRepo) List(ctx context.Context, filter repository.AlertFilter) ([]*entity.Alert, error) {
+	var result []*entity.Alert
+	for _, a := range m.alerts {
+		if a.UserID == filter.UserID && a.AppID == filter.AppID && (filter.Status == "" || a.Status == filter.Status) {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockAlertRepo) FindLatestByRuleAndKey(ctx context.Context, ruleID uuid.UUID, key string) (*entity.Alert, error) {
+	var latest *entity.Alert
+	for _, a := range m.alerts {
+		if a.RuleID == ruleID && a.Key == key && (latest == nil || a.TriggeredAt.After(latest.TriggeredAt)) {
+			latest = a
+		}
+	}
+	return latest, nil
+}
+
+func (m *mockAlertRepo) FindUnresolvedByRuleID(ctx context.Context, ruleID uuid.UUID) ([]*entity.Alert, error) {
+	var result []*entity.Alert
+	for _, a := range m.alerts {
+		if a.RuleID == ruleID && a.Status != entity.AlertStatusResolved {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+type mockAppSyncStatusRepo struct {
+	statuses map[uuid.UUID]*entity.AppSyncStatus
+}
+
+func newMockAppSyncStatusRepo() *mockAppSyncStatusRepo {
+	return &mockAppSyncStatusRepo{statuses: make(map[uuid.UUID]*entity.AppSyncStatus)}
+}
+
+func (m *mockAppSyncStatusRepo) Upsert(ctx context.Context, status *entity.AppSyncStatus) error {
+	m.statuses[status.AppID] = status
+	return nil
+}
+
+func (m *mockAppSyncStatusRepo) FindByAppID(ctx context.Context, appID uuid.UUID) (*entity.AppSyncStatus, error) {
+	status, ok := m.statuses[appID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return status, nil
+}
+
+type mockAlertSubscriptionRepo struct {
+	mockRecognitionSubscriptionRepo
+}
+
+func (m *mockAlertSubscriptionRepo) FindByRiskState(ctx context.Context, appID uuid.UUID, riskState valueobject.RiskState) ([]*entity.Subscription, error) {
+	var result []*entity.Subscription
+	for _, s := range m.subscriptions {
+		if s.AppID == appID && s.RiskState == riskState {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func newTestMRRSnapshot(appID uuid.UUID, day time.Time, cents int64) *entity.DailyMetricsSnapshot {
+	return &entity.DailyMetricsSnapshot{
+		AppID:          appID,
+		Date:           truncateToDay(day),
+		ActiveMRRCents: cents,
+	}
+}
+
+func TestAlertService_EvaluateApp(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("MRR drop fires once and auto-resolves", func(t *testing.T) {
+		now := time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC)
+		appID := uuid.New()
+		alertRepo := &mockAlertRepo{}
+		snapshotRepo := &mockSnapshotRepo{snapshots: []*entity.DailyMetricsSnapshot{
+			newTestMRRSnapshot(appID, now.AddDate(0, 0, -1), 100000),
+			newTestMRRSnapshot(appID, now, 94000),
+		}}
+		svc := NewAlertService(&mockAlertRuleRepo{}, alertRepo, snapshotRepo, &mockAlertSubscriptionRepo{}, &mockTxRepo{}, newMockAppSyncStatusRepo())
+		svc.now = func() time.Time { return now }
+
+		rule := entity.NewAlertRule(uuid.New(), appID, "MRR drop", entity.AlertRuleMRRDrop, 5)
+		svc.CreateRule(ctx, rule)
+
+		for i := 0; i < 2; i++ {
+			if err := svc.EvaluateApp(ctx, appID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if len(alertRepo.alerts) != 1 || len(alertRepo.notifications) != 1 {
+			t.Fatalf("expected a single alert while unresolved, got %d alerts and %d notifications", len(alertRepo.alerts), len(alertRepo.notifications))
+		}
+		alert := alertRepo.alerts[0]
+		if alert.Status != entity.AlertStatusOpen || alert.Severity != entity.AlertSeverityWarning || alert.Key != "" {
+			t.Errorf("unexpected alert %+v", alert)
+		}
+		notification := alertRepo.notifications[0]
+		if notification.Kind != entity.NotificationKindAlert || *notification.SourceID != alert.ID ||
+			notification.Status != entity.NotificationStatusPending || len(notification.PendingChannels) != 1 {
+			t.Errorf("expected a pending outbox notification for the alert, got %+v", notification)
+		}
+
+		// The next day MRR holds steady, so the drop has cleared
+		now = now.AddDate(0, 0, 1)
+		snapshotRepo.snapshots = append(snapshotRepo.snapshots, newTestMRRSnapshot(appID, now, 94000))
+		svc.EvaluateApp(ctx, appID)
+		if alert.Status != entity.AlertStatusResolved || !alert.AutoResolved {
+			t.Errorf("expected alert to be auto-resolved, got %s (auto=%v)", alert.Status, alert.AutoResolved)
+		}
+
+		// A drop below the threshold does not fire
+		now = now.AddDate(0, 0, 1)
+		snapshotRepo.snapshots = append(snapshotRepo.snapshots, newTestMRRSnapshot(appID, now, 90000))
+		svc.EvaluateApp(ctx, appID)
+		if len(alertRepo.alerts) != 1 {
+			t.Errorf("expected a 4.3%% drop not to fire, got %d alerts", len(alertRepo.alerts))
+		}
+	})
+
+	t.Run("respects the cooldown after a manual resolve", func(t *testing.T) {
+		now := time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC)
+		appID := uuid.New()
+		alertRepo := &mockAlertRepo{}
+		syncStatusRepo := newMockAppSyncStatusRepo()
+		svc := NewAlertService(&mockAlertRuleRepo{}, alertRepo, &mockSnapshotRepo{err: errors.New("not found")}, &mockAlertSubscriptionRepo{}, &mockTxRepo{}, syncStatusRepo)
+		svc.now = func() time.Time { return now }
+
+		rule := entity.NewAlertRule(uuid.New(), appID, "Sync failing", entity.AlertRuleSyncFailing, 24)
+		rule.CooldownMinutes = 120
+		svc.CreateRule(ctx, rule)
+
+		status := entity.NewAppSyncStatus(appID)
+		status.RecordFailure(now.Add(-25*time.Hour), errors.New("401 Unauthorized"))
+		syncStatusRepo.Upsert(ctx, status)
+
+		svc.EvaluateApp(ctx, appID)
+		if len(alertRepo.alerts) != 1 {
+			t.Fatalf("expected sync failing alert, got %d", len(alertRepo.alerts))
+		}
+		alert, err := svc.ResolveAlert(ctx, rule.UserID, appID, alertRepo.alerts[0].ID)
+		if err != nil || alert.AutoResolved {
+			t.Fatalf("ResolveAlert = %+v, %v", alert, err)
+		}
+
+		now = now.Add(time.Hour)
+		svc.EvaluateApp(ctx, appID)
+		if len(alertRepo.alerts) != 1 {
+			t.Errorf("expected no alert within the cooldown, got %d", len(alertRepo.alerts))
+		}
+
+		now = now.Add(time.Hour)
+		svc.EvaluateApp(ctx, appID)
+		if len(alertRepo.alerts) != 2 {
+			t.Errorf("expected the rule to fire again after the cooldown, got %d alerts", len(alertRepo.alerts))
+		}
+	})
+
+	t.Run("shop at risk fires per shop", func(t *testing.T) {
+		now := time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC)
+		appID := uuid.New()
+
+		big := entity.NewSubscription(appID, "gid://shopify/AppSubscription/1", "big.myshopify.com", "Big Shop", "Pro", 20000, "USD", valueobject.BillingIntervalMonthly)
+		big.RiskState = valueobject.RiskStateOneCycleMissed
+		small := entity.NewSubscription(appID, "gid://shopify/AppSubscription/2", "small.myshopify.com", "Small Shop", "Basic", 900, "USD", valueobject.BillingIntervalMonthly)
+		small.RiskState = valueobject.RiskStateOneCycleMissed
+
+		alertRepo := &mockAlertRepo{}
+		subRepo := &mockAlertSubscriptionRepo{}
+		subRepo.subscriptions = []*entity.Subscription{big, small}
+		svc := NewAlertService(&mockAlertRuleRepo{}, alertRepo, &mockSnapshotRepo{err: errors.New("not found")}, subRepo, &mockTxRepo{}, newMockAppSyncStatusRepo())
+		svc.now = func() time.Time { return now }
+
+		rule := entity.NewAlertRule(uuid.New(), appID, "Big shop at risk", entity.AlertRuleShopAtRisk, 10000)
+		rule.RiskState = valueobject.RiskStateOneCycleMissed
+		rule.Severity = entity.AlertSeverityCritical
+		if err := svc.CreateRule(ctx, rule); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		svc.EvaluateApp(ctx, appID)
+		if len(alertRepo.alerts) != 1 {
+			t.Fatalf("expected only the shop above the threshold to fire, got %d", len(alertRepo.alerts))
+		}
+		if a := alertRepo.alerts[0]; a.Key != "big.myshopify.com" || a.Severity != entity.AlertSeverityCritical {
+			t.Errorf("unexpected alert %+v", a)
+		}
+
+		if _, err := svc.AcknowledgeAlert(ctx, uuid.New(), appID, alertRepo.alerts[0].ID); !errors.Is(err, ErrAlertNotFound) {
+			t.Errorf("expected ErrAlertNotFound for another user, got %v", err)
+		}
+		acked, err := svc.AcknowledgeAlert(ctx, rule.UserID, appID, alertRepo.alerts[0].ID)
+		if err != nil || acked.Status != entity.AlertStatusAcknowledged || acked.AcknowledgedAt == nil {
+			t.Fatalf("AcknowledgeAlert = %+v, %v", acked, err)
+		}
+
+		// Recovered shop: the acknowledged alert resolves automatically
+		big.RiskState = valueobject.RiskStateSafe
+		svc.EvaluateApp(ctx, appID)
+		if acked.Status != entity.AlertStatusResolved || !acked.AutoResolved {
+			t.Errorf("expected alert to be auto-resolved, got %s", acked.Status)
+		}
+		if _, err := svc.ResolveAlert(ctx, rule.UserID, appID, acked.ID); !errors.Is(err, entity.ErrAlertAlreadyResolved) {
+			t.Errorf("expected ErrAlertAlreadyResolved, got %v", err)
+		}
+	})
+
+	t.Run("usage revenue zero", func(t *testing.T) {
+		now := time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC)
+		appID := uuid.New()
+		alertRepo := &mockAlertRepo{}
+		txRepo := &mockTxRepo{transactions: []*entity.Transaction{
+			{AppID: appID, ChargeType: valueobject.ChargeTypeUsage, NetAmountCents: 500, TransactionDate: now.AddDate(0, 0, -2)},
+		}}
+		svc := NewAlertService(&mockAlertRuleRepo{}, alertRepo, &mockSnapshotRepo{err: errors.New("not found")}, &mockAlertSubscriptionRepo{}, txRepo, newMockAppSyncStatusRepo())
+		svc.now = func() time.Time { return now }
+
+		rule := entity.NewAlertRule(uuid.New(), appID, "No usage revenue", entity.AlertRuleUsageRevenueZero, 3)
+		svc.CreateRule(ctx, rule)
+
+		svc.EvaluateApp(ctx, appID)
+		if len(alertRepo.alerts) != 0 {
+			t.Fatalf("expected no alert with recent usage revenue, got %d", len(alertRepo.alerts))
+		}
+
+		now = now.AddDate(0, 0, 2)
+		svc.EvaluateApp(ctx, appID)
+		if len(alertRepo.alerts) != 1 {
+			t.Errorf("expected alert after 3 days without usage revenue, got %d", len(alertRepo.alerts))
+		}
+	})
+}
+
+func TestAlertService_Rules(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC)
+	userID, appID := uuid.New(), uuid.New()
+
+	t.Run("rejects invalid rules", func(t *testing.T) {
+		svc := NewAlertService(&mockAlertRuleRepo{}, &mockAlertRepo{}, &mockSnapshotRepo{err: errors.New("not found")}, &mockAlertSubscriptionRepo{}, &mockTxRepo{}, newMockAppSyncStatusRepo())
+		svc.now = func() time.Time { return now }
+
+		invalid := entity.NewAlertRule(userID, appID, "Bad", entity.AlertRuleMRRDrop, 150)
+		if err := svc.CreateRule(ctx, invalid); !errors.Is(err, entity.ErrInvalidAlertThreshold) {
+			t.Errorf("expected ErrInvalidAlertThreshold, got %v", err)
+		}
+		invalid = entity.NewAlertRule(userID, appID, "Bad", entity.AlertRuleShopAtRisk, 10000)
+		if err := svc.CreateRule(ctx, invalid); !errors.Is(err, entity.ErrInvalidAlertRiskState) {
+			t.Errorf("expected ErrInvalidAlertRiskState, got %v", err)
+		}
+	})
+
+	t.Run("updates only the owner's rule", func(t *testing.T) {
+		svc := NewAlertService(&mockAlertRuleRepo{}, &mockAlertRepo{}, &mockSnapshotRepo{err: errors.New("not found")}, &mockAlertSubscriptionRepo{}, &mockTxRepo{}, newMockAppSyncStatusRepo())
+		svc.now = func() time.Time { return now }
+
+		rule := entity.NewAlertRule(userID, appID, "MRR drop", entity.AlertRuleMRRDrop, 5)
+		if err := svc.CreateRule(ctx, rule); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		update := entity.NewAlertRule(uuid.New(), appID, "MRR drop", entity.AlertRuleMRRDrop, 10)
+		update.ID = rule.ID
+		if err := svc.UpdateRule(ctx, update); !errors.Is(err, ErrAlertRuleNotFound) {
+			t.Errorf("expected ErrAlertRuleNotFound for another user, got %v", err)
+		}
+		update.UserID = userID
+		update.Channels = []entity.NotificationChannel{entity.NotificationChannelPush, entity.NotificationChannelSlack}
+		if err := svc.UpdateRule(ctx, update); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if update.CreatedAt != rule.CreatedAt || !update.UpdatedAt.Equal(now) {
+			t.Errorf("expected CreatedAt kept and UpdatedAt set, got %v and %v", update.CreatedAt, update.UpdatedAt)
+		}
+	})
+
+	t.Run("deletes only the app's rule", func(t *testing.T) {
+		svc := NewAlertService(&mockAlertRuleRepo{}, &mockAlertRepo{}, &mockSnapshotRepo{err: errors.New("not found")}, &mockAlertSubscriptionRepo{}, &mockTxRepo{}, newMockAppSyncStatusRepo())
+		svc.now = func() time.Time { return now }
+
+		rule := entity.NewAlertRule(userID, appID, "MRR drop", entity.AlertRuleMRRDrop, 5)
+		if err := svc.CreateRule(ctx, rule); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := svc.DeleteRule(ctx, userID, uuid.New(), rule.ID); !errors.Is(err, ErrAlertRuleNotFound) {
+			t.Errorf("expected ErrAlertRuleNotFound for another app, got %v", err)
+		}
+		if err := svc.DeleteRule(ctx, userID, appID, rule.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rules, _ := svc.ListRules(ctx, userID, appID); len(rules) != 0 {
+			t.Errorf("expected no rules after delete, got %d", len(rules))
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+const (
+	dailySummaryBatch    = 100
+	dailySummaryLease    = 5 * time.Minute // Longer than building and queueing a summary can take
+	dailySummaryMaxDelay = 6 * time.Hour   // Summaries this late (e.g. after an outage) are skipped, not sent
+)
+
+// DailySummaryScheduler sends each user's daily summary at their preferred
+// time in their own time zone, honouring quiet hours and weekend skipping.
+// Users are claimed with a lease so each is handled by one replica, and the
+// summary is stored with its local date, so it is sent at most once a day
+// even across restarts. The summary is queued in the notification outbox.
+type DailySummaryScheduler struct {
+	prefsRepo           repository.NotificationPreferencesRepository
+	notificationRepo    repository.NotificationRepository
+	partnerRepo         repository.PartnerAccountRepository
+	appRepo             repository.AppRepository
+	snapshotRepo        repository.DailyMetricsSnapshotRepository
+	notificationService *NotificationService
+	interval            time.Duration
+	batchSize           int
+	now                 func() time.Time
+	stopCh              chan struct{}
+	doneCh              chan struct{}
+}
+
+// NewDailySummaryScheduler creates a new DailySummaryScheduler polling every minute
+func NewDailySummaryScheduler(
+	prefsRepo repository.NotificationPreferencesRepository,
+	notificationRepo repository.NotificationRepository,
+	partnerRepo repository.PartnerAccountRepository,
+	appRepo repository.AppRepository,
+	snapshotRepo repository.DailyMetricsSnapshotRepository,
+	notificationService *NotificationService,
+) *DailySummaryScheduler {
+	return &DailySummaryScheduler{
+		prefsRepo:           prefsRepo,
+		notificationRepo:    notificationRepo,
+		partnerRepo:         partnerRepo,
+		appRepo:             appRepo,
+		snapshotRepo:        snapshotRepo,
+		notificationService: notificationService,
+		interval:            time.Minute,
+		batchSize:           dailySummaryBatch,
+		now:                 func() time.Time { return time.Now().UTC() },
+		stopCh:              make(chan struct{}),
+		doneCh:              make(chan struct{}),
+	}
+}
+
+// WithInterval sets how often due summaries are polled
+func (s *DailySummaryScheduler) WithInterval(interval time.Duration) *DailySummaryScheduler {
+	s.interval = interval
+	return s
+}
+
+// Start begins sending daily summaries
+func (s *DailySummaryScheduler) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+// Stop gracefully stops the scheduler after the current batch
+func (s *DailySummaryScheduler) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+func (s *DailySummaryScheduler) run(ctx context.Context) {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		// Drain full batches before waiting again
+		for {
+			n, err := s.ProcessDue(ctx)
+			if err != nil {
+				log.Printf("DailySummaryScheduler: %v", err)
+			}
+			if err != nil || n < s.batchSize {
+				break
+			}
+		}
+	}
+}
+
+// ProcessDue sends the summaries that are due, schedules users who have no
+// send time yet, and returns how many users were handled. A user whose
+// summary fails keeps their lease until it expires and is then retried.
+func (s *DailySummaryScheduler) ProcessDue(ctx context.Context) (int, error) {
+	due, err := s.prefsRepo.ClaimDueDailySummaries(ctx, s.now(), dailySummaryLease, s.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due daily summaries: %w", err)
+	}
+
+	for _, prefs := range due {
+		if err := s.process(ctx, prefs); err != nil {
+			log.Printf("DailySummaryScheduler: user %s: %v", prefs.UserID, err)
+		}
+	}
+
+	return len(due), nil
+}
+
+// process sends a claimed user's summary if it is due, then stores their next send time
+func (s *DailySummaryScheduler) process(ctx context.Context, prefs *entity.NotificationPreferences) error {
+	now := s.now()
+
+	if prefs.NextDailySummaryAt != nil {
+		dueAt := *prefs.NextDailySummaryAt
+		if now.Sub(dueAt) <= dailySummaryMaxDelay {
+			if err := s.send(ctx, prefs, dueAt); err != nil {
+				return err
+			}
+		} else {
+			log.Printf("DailySummaryScheduler: user %s: skipping summary due at %s", prefs.UserID, dueAt.Format(time.RFC3339))
+		}
+	}
+
+	next := prefs.NextDailySummaryAfter(now)
+	if err := s.prefsRepo.SetNextDailySummaryAt(ctx, prefs.UserID, next); err != nil {
+		return fmt.Errorf("failed to schedule next daily summary: %w", err)
+	}
+	return nil
+}
+
+// send queues the summary for the user's local date of dueAt, unless one was already queued for it
+func (s *DailySummaryScheduler) send(ctx context.Context, prefs *entity.NotificationPreferences, dueAt time.Time) error {
+	notification, err := s.buildSummary(ctx, prefs)
+	if err != nil {
+		return err
+	}
+	if notification == nil {
+		return nil // No tracked apps with metrics yet
+	}
+
+	local := dueAt.In(prefs.Location())
+	summaryDate := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	if _, err := s.notificationRepo.CreateDailySummary(ctx, notification, summaryDate); err != nil {
+		return fmt.Errorf("failed to queue daily summary: %w", err)
+	}
+	return nil
+}
+
+// buildSummary builds the summary from the latest snapshot of each tracked
+// app, with the change from the day before. Returns nil if there is nothing to report.
+func (s *DailySummaryScheduler) buildSummary(ctx context.Context, prefs *entity.NotificationPreferences) (*entity.Notification, error) {
+	// The user's own account and those of the workspaces they are a member of
+	accounts, err := s.partnerRepo.FindAccessibleByUserID(ctx, prefs.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch partner accounts: %w", err)
+	}
+
+	var apps []*entity.App
+	for _, account := range accounts {
+		accountApps, err := s.appRepo.FindByPartnerAccountID(ctx, account.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch apps: %w", err)
+		}
+		apps = append(apps, accountApps...)
+	}
+
+	var lines []string
+	var summarized []*entity.App
+	for _, app := range apps {
+		if !app.TrackingEnabled {
+			continue
+		}
+		latest, err := s.snapshotRepo.FindLatestByAppID(ctx, app.ID)
+		if err != nil || latest == nil {
+			continue
+		}
+		previous, err := s.snapshotRepo.FindByAppIDAndDate(ctx, app.ID, latest.Date.AddDate(0, 0, -1))
+		if err != nil {
+			previous = nil
+		}
+		lines = append(lines, formatSnapshotChange(previous, latest))
+		summarized = append(summarized, app)
+	}
+
+	switch len(summarized) {
+	case 0:
+		return nil, nil
+	case 1:
+		app := summarized[0]
+		n := s.notificationService.NewNotification(ctx, prefs.UserID, entity.NotificationKindDailySummary,
+			entity.AlertSeverityInfo, fmt.Sprintf("📊 Daily Summary: %s", app.Name), lines[0])
+		appID := app.ID
+		n.AppID = &appID
+		return n, nil
+	}
+
+	for i, app := range summarized {
+		lines[i] = fmt.Sprintf("%s: %s", app.Name, lines[i])
+	}
+	return s.notificationService.NewNotification(ctx, prefs.UserID, entity.NotificationKindDailySummary,
+		entity.AlertSeverityInfo, "📊 Daily Summary", strings.Join(lines, "\n")), nil
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// mockAppListRepo returns several apps for a partner account
+type mockAppListRepo struct {
+	mockAppRepoForSync
+	apps []*entity.App
+}
+
+func (m *mockAppListRepo) FindByPartnerAccountID(ctx context.Context, partnerAccountID uuid.UUID) ([]*entity.App, error) {
+	return m.apps, nil
+}
+
+func newTestSummaryApp(name string) *entity.App {
+	return &entity.App{ID: uuid.New(), Name: name, TrackingEnabled: true}
+}
+
+func nextSummaryAt(prefsRepo *mockNotificationPreferencesRepository, userID uuid.UUID) time.Time {
+	next := prefsRepo.prefs[userID].NextDailySummaryAt
+	if next == nil {
+		return time.Time{}
+	}
+	return next.UTC()
+}
+
+func summaryPreferences(t *testing.T, timezone string, hour, minute int) *entity.NotificationPreferences {
+	t.Helper()
+	prefs := entity.NewNotificationPreferences(uuid.New())
+	if err := prefs.SetTimezone(timezone); err != nil {
+		t.Fatalf("SetTimezone(%q): %v", timezone, err)
+	}
+	prefs.DailySummaryTime = time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	return prefs
+}
+
+func clock(hour, minute int) *time.Time {
+	t := time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	return &t
+}
+
+func TestDailySummaryScheduler_ProcessDue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends at local time across DST", func(t *testing.T) {
+		// US daylight saving starts on Sunday 8 March 2026
+		now := time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC)
+		prefs := summaryPreferences(t, "America/New_York", 8, 0)
+		prefsRepo := newMockNotificationPreferencesRepository()
+		prefsRepo.Upsert(ctx, prefs)
+		notificationRepo := newMockNotificationRepo()
+		app := newTestSummaryApp("MyApp")
+		appRepo := &mockAppListRepo{apps: []*entity.App{app}}
+		snapshotRepo := &mockSnapshotRepo{snapshots: []*entity.DailyMetricsSnapshot{
+			{AppID: app.ID, Date: time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC), ActiveMRRCents: 100000},
+		}}
+		partnerRepo := &mockPartnerRepoForSync{account: &entity.PartnerAccount{ID: uuid.New(), UserID: prefs.UserID}}
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), prefsRepo, nil).WithOutbox(notificationRepo)
+		scheduler := NewDailySummaryScheduler(prefsRepo, notificationRepo, partnerRepo, appRepo, snapshotRepo, notificationService)
+		scheduler.now = func() time.Time { return now }
+
+		// The first pass only schedules the user
+		if n, err := scheduler.ProcessDue(ctx); err != nil || n != 1 {
+			t.Fatalf("ProcessDue = %d, %v", n, err)
+		}
+		if want := time.Date(2026, 3, 7, 13, 0, 0, 0, time.UTC); !nextSummaryAt(prefsRepo, prefs.UserID).Equal(want) {
+			t.Fatalf("expected 08:00 EST (%s), got %s", want, nextSummaryAt(prefsRepo, prefs.UserID))
+		}
+		if n, _ := scheduler.ProcessDue(ctx); n != 0 || len(notificationRepo.notifications) != 0 {
+			t.Fatalf("expected nothing due before 08:00, got %d claimed and %d sent", n, len(notificationRepo.notifications))
+		}
+
+		now = time.Date(2026, 3, 7, 13, 0, 30, 0, time.UTC)
+		scheduler.ProcessDue(ctx)
+		if len(notificationRepo.notifications) != 1 {
+			t.Fatalf("expected one summary, got %d", len(notificationRepo.notifications))
+		}
+		if want := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC); !nextSummaryAt(prefsRepo, prefs.UserID).Equal(want) {
+			t.Errorf("expected 08:00 EDT (%s), got %s", want, nextSummaryAt(prefsRepo, prefs.UserID))
+		}
+	})
+
+	t.Run("sends once per day", func(t *testing.T) {
+		now := time.Date(2026, 10, 15, 6, 0, 0, 0, time.UTC)
+		prefs := summaryPreferences(t, "Europe/Berlin", 9, 0)
+		prefsRepo := newMockNotificationPreferencesRepository()
+		prefsRepo.Upsert(ctx, prefs)
+		notificationRepo := newMockNotificationRepo()
+		app := newTestSummaryApp("MyApp")
+		appRepo := &mockAppListRepo{apps: []*entity.App{app}}
+		snapshotRepo := &mockSnapshotRepo{snapshots: []*entity.DailyMetricsSnapshot{
+			{AppID: app.ID, Date: time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), ActiveMRRCents: 100000},
+		}}
+		partnerRepo := &mockPartnerRepoForSync{account: &entity.PartnerAccount{ID: uuid.New(), UserID: prefs.UserID}}
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), prefsRepo, nil).WithOutbox(notificationRepo)
+		scheduler := NewDailySummaryScheduler(prefsRepo, notificationRepo, partnerRepo, appRepo, snapshotRepo, notificationService)
+		scheduler.now = func() time.Time { return now }
+
+		scheduler.ProcessDue(ctx)
+		dueAt := nextSummaryAt(prefsRepo, prefs.UserID)
+		if want := time.Date(2026, 10, 15, 7, 0, 0, 0, time.UTC); !dueAt.Equal(want) {
+			t.Fatalf("expected 09:00 CEST (%s), got %s", want, dueAt)
+		}
+
+		now = dueAt
+		scheduler.ProcessDue(ctx)
+		if len(notificationRepo.notifications) != 1 {
+			t.Fatalf("expected one summary, got %d", len(notificationRepo.notifications))
+		}
+
+		// A replica that restarted before storing the next send time handles the same slot again
+		prefsRepo.prefs[prefs.UserID].NextDailySummaryAt = &dueAt
+		now = now.Add(time.Minute)
+		scheduler.ProcessDue(ctx)
+		if len(notificationRepo.notifications) != 1 {
+			t.Errorf("expected the summary not to be resent, got %d", len(notificationRepo.notifications))
+		}
+
+		// Changing preferences clears the schedule; it is recomputed for tomorrow
+		prefsRepo.prefs[prefs.UserID].NextDailySummaryAt = nil
+		scheduler.ProcessDue(ctx)
+		if want := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC); !nextSummaryAt(prefsRepo, prefs.UserID).Equal(want) || len(notificationRepo.notifications) != 1 {
+			t.Errorf("expected tomorrow's summary at %s and no resend, got %s and %d sent", want, nextSummaryAt(prefsRepo, prefs.UserID), len(notificationRepo.notifications))
+		}
+	})
+
+	t.Run("claimed user is not processed twice", func(t *testing.T) {
+		now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+		prefs := summaryPreferences(t, "UTC", 9, 0)
+		dueAt := now
+		prefs.NextDailySummaryAt = &dueAt
+		prefsRepo := newMockNotificationPreferencesRepository()
+		prefsRepo.Upsert(ctx, prefs)
+		notificationRepo := newMockNotificationRepo()
+		partnerRepo := &mockPartnerRepoForSync{account: &entity.PartnerAccount{ID: uuid.New(), UserID: prefs.UserID}}
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), prefsRepo, nil).WithOutbox(notificationRepo)
+		scheduler := NewDailySummaryScheduler(prefsRepo, notificationRepo, partnerRepo, &mockAppListRepo{}, &mockSnapshotRepo{}, notificationService)
+		scheduler.now = func() time.Time { return now }
+
+		// Another replica holds the lease
+		prefsRepo.locked[prefs.UserID] = now.Add(dailySummaryLease)
+		if n, _ := scheduler.ProcessDue(ctx); n != 0 {
+			t.Errorf("expected the leased user to be skipped, got %d", n)
+		}
+	})
+
+	t.Run("summary content", func(t *testing.T) {
+		now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+		prefs := summaryPreferences(t, "UTC", 9, 0)
+		dueAt := now
+		prefs.NextDailySummaryAt = &dueAt
+		prefsRepo := newMockNotificationPreferencesRepository()
+		prefsRepo.Upsert(ctx, prefs)
+		notificationRepo := newMockNotificationRepo()
+
+		yesterday := time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC)
+		today := yesterday.AddDate(0, 0, 1)
+		alpha := newTestSummaryApp("Alpha")
+		beta := newTestSummaryApp("Beta")
+		untracked := newTestSummaryApp("Gamma")
+		untracked.TrackingEnabled = false
+		noSnapshots := newTestSummaryApp("Delta")
+		appRepo := &mockAppListRepo{apps: []*entity.App{alpha, beta, untracked, noSnapshots}}
+		snapshotRepo := &mockSnapshotRepo{snapshots: []*entity.DailyMetricsSnapshot{
+			{AppID: alpha.ID, Date: yesterday, ActiveMRRCents: 100000, RevenueAtRiskCents: 5000, RenewalSuccessRate: 0.9, ChurnedCount: 2},
+			{AppID: alpha.ID, Date: today, ActiveMRRCents: 110000, RevenueAtRiskCents: 2500, RenewalSuccessRate: 0.92, ChurnedCount: 3},
+			{AppID: beta.ID, Date: today, ActiveMRRCents: 5000},
+			{AppID: untracked.ID, Date: today, ActiveMRRCents: 7000},
+		}}
+		partnerRepo := &mockPartnerRepoForSync{account: &entity.PartnerAccount{ID: uuid.New(), UserID: prefs.UserID}}
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), prefsRepo, nil).WithOutbox(notificationRepo)
+		scheduler := NewDailySummaryScheduler(prefsRepo, notificationRepo, partnerRepo, appRepo, snapshotRepo, notificationService)
+		scheduler.now = func() time.Time { return now }
+
+		if _, err := scheduler.ProcessDue(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(notificationRepo.notifications) != 1 {
+			t.Fatalf("expected one summary, got %d", len(notificationRepo.notifications))
+		}
+		n := notificationRepo.notifications[0]
+		if n.Kind != entity.NotificationKindDailySummary || n.Title != "📊 Daily Summary" || n.AppID != nil {
+			t.Errorf("unexpected notification %+v", n)
+		}
+
+		lines := strings.Split(n.Body, "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected one line per tracked app with metrics, got %q", n.Body)
+		}
+		want := "Alpha: MRR: $1100.00 (+10.0%) | At Risk: $25.00 (-25.00) | Renewal Rate: 92.0% (+2.0 pts) | Churned: 3 (+1)"
+		if lines[0] != want {
+			t.Errorf("expected %q, got %q", want, lines[0])
+		}
+		if lines[1] != "Beta: MRR: $50.00 | At Risk: $0.00 | Renewal Rate: 0.0% | Churned: 0" {
+			t.Errorf("expected no changes without a previous snapshot, got %q", lines[1])
+		}
+	})
+
+	t.Run("skips late and empty summaries", func(t *testing.T) {
+		now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+		prefs := summaryPreferences(t, "UTC", 9, 0)
+		prefsRepo := newMockNotificationPreferencesRepository()
+		prefsRepo.Upsert(ctx, prefs)
+		notificationRepo := newMockNotificationRepo()
+		appRepo := &mockAppListRepo{}
+		snapshotRepo := &mockSnapshotRepo{}
+		partnerRepo := &mockPartnerRepoForSync{account: &entity.PartnerAccount{ID: uuid.New(), UserID: prefs.UserID}}
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), prefsRepo, nil).WithOutbox(notificationRepo)
+		scheduler := NewDailySummaryScheduler(prefsRepo, notificationRepo, partnerRepo, appRepo, snapshotRepo, notificationService)
+		scheduler.now = func() time.Time { return now }
+
+		// No apps: nothing is sent, but the user is rescheduled
+		dueAt := now
+		prefsRepo.prefs[prefs.UserID].NextDailySummaryAt = &dueAt
+		scheduler.ProcessDue(ctx)
+		if len(notificationRepo.notifications) != 0 || !nextSummaryAt(prefsRepo, prefs.UserID).Equal(now.AddDate(0, 0, 1)) {
+			t.Fatalf("expected no summary and tomorrow's schedule, got %d sent and %s", len(notificationRepo.notifications), nextSummaryAt(prefsRepo, prefs.UserID))
+		}
+
+		// A summary overdue by more than the maximum delay is dropped
+		app := newTestSummaryApp("MyApp")
+		appRepo.apps = append(appRepo.apps, app)
+		snapshotRepo.snapshots = append(snapshotRepo.snapshots, &entity.DailyMetricsSnapshot{
+			AppID: app.ID, Date: time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), ActiveMRRCents: 100000,
+		})
+		now = now.Add(dailySummaryMaxDelay + time.Hour)
+		prefsRepo.prefs[prefs.UserID].NextDailySummaryAt = &dueAt
+		scheduler.ProcessDue(ctx)
+		if len(notificationRepo.notifications) != 0 {
+			t.Errorf("expected the late summary to be skipped, got %d", len(notificationRepo.notifications))
+		}
+
+		prefsRepo.prefs[prefs.UserID].DailySummaryEnabled = false
+		prefsRepo.prefs[prefs.UserID].NextDailySummaryAt = nil
+		if claimed, _ := scheduler.ProcessDue(ctx); claimed != 0 {
+			t.Errorf("expected disabled summaries not to be claimed, got %d", claimed)
+		}
+	})
+}
+
+func TestDailySummaryScheduler_QuietHoursAndWeekends(t *testing.T) {
+	friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		hour         int
+		quietStart   *time.Time
+		quietEnd     *time.Time
+		skipWeekends bool
+		now          time.Time
+		want         time.Time
+	}{
+		{
+			name: "later today",
+			hour: 9, now: friday.Add(6 * time.Hour),
+			want: friday.Add(9 * time.Hour),
+		},
+		{
+			name: "tomorrow once today's has passed",
+			hour: 9, now: friday.Add(10 * time.Hour),
+			want: friday.Add(33 * time.Hour),
+		},
+		{
+			name: "deferred to the end of quiet hours",
+			hour: 8, quietStart: clock(7, 0), quietEnd: clock(10, 30), now: friday.Add(6 * time.Hour),
+			want: friday.Add(10*time.Hour + 30*time.Minute),
+		},
+		{
+			name: "quiet hours crossing midnight",
+			hour: 23, quietStart: clock(22, 0), quietEnd: clock(6, 0), now: friday.Add(12 * time.Hour),
+			want: friday.Add(30 * time.Hour),
+		},
+		{
+			name: "outside quiet hours",
+			hour: 12, quietStart: clock(22, 0), quietEnd: clock(6, 0), now: friday.Add(6 * time.Hour),
+			want: friday.Add(12 * time.Hour),
+		},
+		{
+			name: "weekend skipped to Monday",
+			hour: 9, skipWeekends: true, now: friday.Add(10 * time.Hour),
+			want: friday.AddDate(0, 0, 3).Add(9 * time.Hour),
+		},
+		{
+			name: "weekend sent without skipping",
+			hour: 9, now: friday.Add(10 * time.Hour),
+			want: friday.AddDate(0, 0, 1).Add(9 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := summaryPreferences(t, "UTC", tt.hour, 0)
+			if err := prefs.SetQuietHours(tt.quietStart, tt.quietEnd); err != nil {
+				t.Fatalf("SetQuietHours: %v", err)
+			}
+			prefs.SkipWeekends = tt.skipWeekends
+
+			ctx := context.Background()
+			prefsRepo := newMockNotificationPreferencesRepository()
+			prefsRepo.Upsert(ctx, prefs)
+			notificationRepo := newMockNotificationRepo()
+			partnerRepo := &mockPartnerRepoForSync{account: &entity.PartnerAccount{ID: uuid.New(), UserID: prefs.UserID}}
+			notificationService := NewNotificationService(newMockDeviceTokenRepository(), prefsRepo, nil).WithOutbox(notificationRepo)
+			scheduler := NewDailySummaryScheduler(prefsRepo, notificationRepo, partnerRepo, &mockAppListRepo{}, &mockSnapshotRepo{}, notificationService)
+			scheduler.now = func() time.Time { return tt.now }
+
+			scheduler.ProcessDue(ctx)
+			if got := nextSummaryAt(prefsRepo, prefs.UserID); !got.Equal(tt.want) {
+				t.Errorf("expected next summary at %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
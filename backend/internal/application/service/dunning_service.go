@@ -0,0 +1,622 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+const (
+	maxDunningList  = 200
+	dunningBatch    = 100
+	dunningLease    = 5 * time.Minute // Longer than running one step can take
+	dunningRetryGap = time.Hour       // Delay before retrying a failed step, multiplied by the attempt
+)
+
+var (
+	// ErrDunningSequenceNotFound is returned when the sequence does not belong to the user and app
+	ErrDunningSequenceNotFound = errors.New("dunning sequence not found")
+
+	// ErrDunningRunNotFound is returned when the run does not belong to the user and app
+	ErrDunningRunNotFound = errors.New("dunning run not found")
+
+	// ErrDunningTaskNotFound is returned when the task does not belong to the user and app
+	ErrDunningTaskNotFound = errors.New("dunning task not found")
+
+	// ErrInvalidDunningTemplate is returned when a step's subject or body is not a valid template
+	ErrInvalidDunningTemplate = errors.New("invalid step template")
+
+	// ErrInvalidDunningReportRange is returned when a report's range is empty
+	ErrInvalidDunningReportRange = errors.New("from must be before to")
+)
+
+// dunningTemplateData is what step subjects and bodies are rendered with,
+// e.g. "Hi {{.ContactName}}, your {{.PlanName}} plan on {{.ShopName}} ..."
+type dunningTemplateData struct {
+	ShopName    string
+	ShopDomain  string
+	ContactName string // Shop contact's name, or the shop name if it has none
+	AppName     string
+	PlanName    string
+	Amount      string // Subscription MRR, e.g. "$29.00"
+	RiskState   string
+	Day         int // Step day; 1 is the day the run started
+}
+
+// DunningRecoveryStats counts the outcomes of dunning runs. The recovery rate
+// is recovered runs over runs that have finished either way; active and
+// cancelled runs are not counted in it.
+type DunningRecoveryStats struct {
+	Started           int
+	Active            int
+	Recovered         int
+	Exhausted         int
+	Cancelled         int
+	RecoveryRate      float64 // Percent, 0 when no run has finished
+	AtRiskMRRCents    int64   // MRR of every run started
+	RecoveredMRRCents int64   // MRR of the recovered runs
+	AvgDaysToRecover  float64
+}
+
+// DunningSequenceRecovery is the recovery of one sequence's runs
+type DunningSequenceRecovery struct {
+	SequenceID uuid.UUID
+	Name       string
+	DunningRecoveryStats
+}
+
+// DunningRecoveryReport is the recovery of an app's dunning runs started in a period
+type DunningRecoveryReport struct {
+	From      time.Time
+	To        time.Time
+	Total     DunningRecoveryStats
+	Sequences []DunningSequenceRecovery
+}
+
+// DunningService runs dunning and win-back sequences. When a recorded
+// subscription event moves a shop into a sequence's trigger risk state, a
+// run of the sequence starts for the shop; its steps (internal alerts,
+// merchant emails and follow-up tasks) are executed by a background worker
+// on their day. When the shop returns to SAFE its runs stop and count as
+// recovered, which feeds the recovery report.
+type DunningService struct {
+	sequenceRepo        repository.DunningSequenceRepository
+	runRepo             repository.DunningRunRepository
+	taskRepo            repository.DunningTaskRepository
+	contactRepo         repository.ShopContactRepository
+	appRepo             repository.AppRepository
+	subRepo             repository.SubscriptionRepository
+	notificationRepo    repository.NotificationRepository
+	notificationService *NotificationService
+	emailSender         EmailSender
+	interval            time.Duration
+	batchSize           int
+	now                 func() time.Time
+	stopCh              chan struct{}
+	doneCh              chan struct{}
+}
+
+// NewDunningService creates a new DunningService polling for due steps every minute
+func NewDunningService(
+	sequenceRepo repository.DunningSequenceRepository,
+	runRepo repository.DunningRunRepository,
+	taskRepo repository.DunningTaskRepository,
+	contactRepo repository.ShopContactRepository,
+	appRepo repository.AppRepository,
+	subRepo repository.SubscriptionRepository,
+	notificationRepo repository.NotificationRepository,
+	notificationService *NotificationService,
+) *DunningService {
+	return &DunningService{
+		sequenceRepo:        sequenceRepo,
+		runRepo:             runRepo,
+		taskRepo:            taskRepo,
+		contactRepo:         contactRepo,
+		appRepo:             appRepo,
+		subRepo:             subRepo,
+		notificationRepo:    notificationRepo,
+		notificationService: notificationService,
+		interval:            time.Minute,
+		batchSize:           dunningBatch,
+		now:                 func() time.Time { return time.Now().UTC() },
+		stopCh:              make(chan struct{}),
+		doneCh:              make(chan struct{}),
+	}
+}
+
+// WithEmailSender sets the sender of merchant emails. Without one, merchant
+// email steps are skipped.
+func (s *DunningService) WithEmailSender(sender EmailSender) *DunningService {
+	s.emailSender = sender
+	return s
+}
+
+// WithInterval sets how often due steps are polled
+func (s *DunningService) WithInterval(interval time.Duration) *DunningService {
+	s.interval = interval
+	return s
+}
+
+// ListSequences returns the user's sequences for an app
+func (s *DunningService) ListSequences(ctx context.Context, userID, appID uuid.UUID) ([]*entity.DunningSequence, error) {
+	sequences, err := s.sequenceRepo.FindByUserAndApp(ctx, userID, appID)
+	if err != nil {
+		return nil, err
+	}
+	if sequences == nil {
+		sequences = []*entity.DunningSequence{}
+	}
+	return sequences, nil
+}
+
+// GetSequence returns a sequence owned by the user on the app
+func (s *DunningService) GetSequence(ctx context.Context, userID, appID, id uuid.UUID) (*entity.DunningSequence, error) {
+	return s.findSequence(ctx, userID, appID, id)
+}
+
+// CreateSequence validates and stores a new sequence
+func (s *DunningService) CreateSequence(ctx context.Context, sequence *entity.DunningSequence) error {
+	if err := validateDunningSequence(sequence); err != nil {
+		return err
+	}
+	return s.sequenceRepo.Create(ctx, sequence)
+}
+
+// UpdateSequence replaces the settings of a sequence owned by the user on the
+// same app. Runs already started keep the steps they started with.
+func (s *DunningService) UpdateSequence(ctx context.Context, sequence *entity.DunningSequence) error {
+	if err := validateDunningSequence(sequence); err != nil {
+		return err
+	}
+
+	existing, err := s.findSequence(ctx, sequence.UserID, sequence.AppID, sequence.ID)
+	if err != nil {
+		return err
+	}
+
+	sequence.CreatedAt = existing.CreatedAt
+	sequence.UpdatedAt = s.now()
+	return s.sequenceRepo.Update(ctx, sequence)
+}
+
+// DeleteSequence removes a sequence owned by the user, with its runs and tasks
+func (s *DunningService) DeleteSequence(ctx context.Context, userID, appID, id uuid.UUID) error {
+	if _, err := s.findSequence(ctx, userID, appID, id); err != nil {
+		return err
+	}
+	return s.sequenceRepo.Delete(ctx, userID, id)
+}
+
+// ListRuns returns the most recent runs matching the filter
+func (s *DunningService) ListRuns(ctx context.Context, filter repository.DunningRunFilter) ([]*entity.DunningRun, error) {
+	if filter.Limit <= 0 || filter.Limit > maxDunningList {
+		filter.Limit = maxDunningList
+	}
+
+	runs, err := s.runRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if runs == nil {
+		runs = []*entity.DunningRun{}
+	}
+	return runs, nil
+}
+
+// CancelRun stops an active run owned by the user and cancels its open tasks
+func (s *DunningService) CancelRun(ctx context.Context, userID, appID, id uuid.UUID) (*entity.DunningRun, error) {
+	run, err := s.runRepo.FindByID(ctx, id)
+	if err != nil || run.UserID != userID || run.AppID != appID {
+		return nil, ErrDunningRunNotFound
+	}
+	if err := run.Cancel(s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.runRepo.Update(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to update dunning run: %w", err)
+	}
+	if err := s.taskRepo.CancelOpenByRunID(ctx, run.ID); err != nil {
+		return nil, fmt.Errorf("failed to cancel dunning tasks: %w", err)
+	}
+	return run, nil
+}
+
+// ListTasks returns the most recent tasks matching the filter
+func (s *DunningService) ListTasks(ctx context.Context, filter repository.DunningTaskFilter) ([]*entity.DunningTask, error) {
+	if filter.Limit <= 0 || filter.Limit > maxDunningList {
+		filter.Limit = maxDunningList
+	}
+
+	tasks, err := s.taskRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if tasks == nil {
+		tasks = []*entity.DunningTask{}
+	}
+	return tasks, nil
+}
+
+// CompleteTask marks an open task owned by the user as done
+func (s *DunningService) CompleteTask(ctx context.Context, userID, appID, id uuid.UUID) (*entity.DunningTask, error) {
+	task, err := s.taskRepo.FindByID(ctx, id)
+	if err != nil || task.UserID != userID || task.AppID != appID {
+		return nil, ErrDunningTaskNotFound
+	}
+	if err := task.Complete(s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to update dunning task: %w", err)
+	}
+	return task, nil
+}
+
+// GetShopContact returns the app's contact for a shop, or nil if it has none
+func (s *DunningService) GetShopContact(ctx context.Context, appID uuid.UUID, domain string) (*entity.ShopContact, error) {
+	return s.contactRepo.FindByDomain(ctx, appID, strings.ToLower(domain))
+}
+
+// SetShopContact stores who merchant emails about a shop are sent to
+func (s *DunningService) SetShopContact(ctx context.Context, appID uuid.UUID, domain, email, name string) (*entity.ShopContact, error) {
+	contact, err := entity.NewShopContact(appID, domain, email, name, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.contactRepo.Upsert(ctx, contact); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+// DeleteShopContact removes the app's contact for a shop
+func (s *DunningService) DeleteShopContact(ctx context.Context, appID uuid.UUID, domain string) error {
+	return s.contactRepo.Delete(ctx, appID, strings.ToLower(domain))
+}
+
+// PublishSubscriptionEvent starts the app's sequences triggered by the risk
+// state a shop moved into, and stops the shop's runs when it returns to SAFE
+// (see WebhookService.WithEventPublisher)
+func (s *DunningService) PublishSubscriptionEvent(ctx context.Context, sub *entity.Subscription, event *entity.SubscriptionEvent) error {
+	if event.FromRiskState == event.ToRiskState {
+		return nil
+	}
+	if event.ToRiskState == valueobject.RiskStateSafe {
+		return s.recover(ctx, sub)
+	}
+	return s.start(ctx, sub, event.ToRiskState)
+}
+
+// start starts a run of every enabled sequence on the app triggered by the risk state
+func (s *DunningService) start(ctx context.Context, sub *entity.Subscription, riskState valueobject.RiskState) error {
+	sequences, err := s.sequenceRepo.FindEnabledByTrigger(ctx, sub.AppID, riskState)
+	if err != nil {
+		return fmt.Errorf("failed to fetch dunning sequences: %w", err)
+	}
+
+	now := s.now()
+	for _, sequence := range sequences {
+		if _, err := s.runRepo.Create(ctx, entity.NewDunningRun(sequence, sub, now)); err != nil {
+			return fmt.Errorf("failed to start dunning run: %w", err)
+		}
+	}
+	return nil
+}
+
+// recover marks the shop's active runs, and runs exhausted within the
+// recovery window, as recovered and cancels their open tasks
+func (s *DunningService) recover(ctx context.Context, sub *entity.Subscription) error {
+	now := s.now()
+	runs, err := s.runRepo.FindRecoverable(ctx, sub.ID, now.Add(-entity.DunningRecoveryWindow))
+	if err != nil {
+		return fmt.Errorf("failed to fetch dunning runs: %w", err)
+	}
+
+	for _, run := range runs {
+		if err := run.Recover(now); err != nil {
+			continue
+		}
+		if err := s.runRepo.Update(ctx, run); err != nil {
+			return fmt.Errorf("failed to update dunning run: %w", err)
+		}
+		if err := s.taskRepo.CancelOpenByRunID(ctx, run.ID); err != nil {
+			return fmt.Errorf("failed to cancel dunning tasks: %w", err)
+		}
+	}
+	return nil
+}
+
+// Start begins executing due steps
+func (s *DunningService) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+// Stop gracefully stops the worker after the current batch
+func (s *DunningService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+func (s *DunningService) run(ctx context.Context) {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		// Drain full batches before waiting again
+		for {
+			n, err := s.ProcessDue(ctx)
+			if err != nil {
+				log.Printf("DunningService: %v", err)
+			}
+			if err != nil || n < s.batchSize {
+				break
+			}
+		}
+	}
+}
+
+// ProcessDue executes the due step of each claimed run and returns how many
+// runs were handled. A failing step is retried later; after
+// MaxDunningStepAttempts it is recorded as failed and the run moves on.
+func (s *DunningService) ProcessDue(ctx context.Context) (int, error) {
+	runs, err := s.runRepo.ClaimDue(ctx, s.now(), dunningLease, s.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due dunning runs: %w", err)
+	}
+
+	for _, run := range runs {
+		if err := s.process(ctx, run); err != nil {
+			log.Printf("DunningService: run %s: %v", run.ID, err)
+		}
+	}
+
+	return len(runs), nil
+}
+
+func (s *DunningService) process(ctx context.Context, run *entity.DunningRun) error {
+	step := run.CurrentStep()
+	if step == nil {
+		return nil
+	}
+
+	status, detail, err := s.execute(ctx, run, step)
+	now := s.now()
+	if err != nil {
+		run.FailStep(err, now.Add(time.Duration(run.Attempts+1)*dunningRetryGap), now)
+	} else {
+		run.CompleteStep(status, detail, now)
+	}
+
+	if updateErr := s.runRepo.Update(ctx, run); updateErr != nil {
+		return fmt.Errorf("failed to update dunning run: %w", updateErr)
+	}
+	return err
+}
+
+// execute runs one step and returns its outcome and a detail to record
+func (s *DunningService) execute(ctx context.Context, run *entity.DunningRun, step *entity.DunningStep) (entity.DunningStepStatus, string, error) {
+	app, err := s.appRepo.FindByID(ctx, run.AppID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch app: %w", err)
+	}
+	contact, err := s.contactRepo.FindByDomain(ctx, run.AppID, strings.ToLower(run.ShopDomain))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch shop contact: %w", err)
+	}
+
+	data := s.templateData(ctx, run, step, app, contact)
+	subject, err := renderDunningTemplate(step.Subject, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := renderDunningTemplate(step.Body, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch step.Action {
+	case entity.DunningActionInternalAlert:
+		if subject == "" {
+			subject = fmt.Sprintf("Dunning: %s is %s", data.ShopName, data.RiskState)
+		}
+		if body == "" {
+			body = fmt.Sprintf("%s (%s) on %s, %s/mo at stake. Day %d of the sequence.",
+				data.ShopName, data.ShopDomain, data.AppName, data.Amount, data.Day)
+		}
+		n := s.notificationService.NewNotification(ctx, run.UserID, entity.NotificationKindDunning, entity.AlertSeverityWarning, subject, body)
+		appID, runID := run.AppID, run.ID
+		n.AppID = &appID
+		n.SourceID = &runID
+		if err := s.notificationRepo.Create(ctx, n); err != nil {
+			return "", "", fmt.Errorf("failed to queue notification: %w", err)
+		}
+		return entity.DunningStepDone, n.ID.String(), nil
+
+	case entity.DunningActionMerchantEmail:
+		if s.emailSender == nil {
+			return entity.DunningStepSkipped, "email is not configured", nil
+		}
+		if contact == nil {
+			return entity.DunningStepSkipped, "shop has no contact email", nil
+		}
+		email, err := renderMerchantEmail(subject, body, app.Name)
+		if err != nil {
+			return "", "", err
+		}
+		if err := s.emailSender.SendEmail(ctx, contact.Email, email.Subject, email.Text, email.HTML, ""); err != nil {
+			return "", "", fmt.Errorf("failed to send merchant email: %w", err)
+		}
+		return entity.DunningStepDone, contact.Email, nil
+
+	case entity.DunningActionTask:
+		task := entity.NewDunningTask(run, subject, body, step.Assignee, s.now())
+		if err := s.taskRepo.Create(ctx, task); err != nil {
+			return "", "", fmt.Errorf("failed to create task: %w", err)
+		}
+		return entity.DunningStepDone, task.ID.String(), nil
+	}
+
+	return entity.DunningStepSkipped, "unknown action", nil
+}
+
+// templateData builds what a run's step is rendered with. The plan comes from
+// the subscription's current state if it can be read.
+func (s *DunningService) templateData(ctx context.Context, run *entity.DunningRun, step *entity.DunningStep, app *entity.App, contact *entity.ShopContact) dunningTemplateData {
+	shopName := run.ShopName
+	if shopName == "" {
+		shopName = run.ShopDomain
+	}
+
+	data := dunningTemplateData{
+		ShopName:    shopName,
+		ShopDomain:  run.ShopDomain,
+		ContactName: shopName,
+		AppName:     app.Name,
+		Amount:      fmt.Sprintf("$%.2f", float64(run.MRRCents)/100),
+		RiskState:   string(run.Trigger),
+		Day:         step.Day,
+	}
+	if contact != nil && contact.Name != "" {
+		data.ContactName = contact.Name
+	}
+	if sub, err := s.subRepo.FindByID(ctx, run.SubscriptionID); err == nil && sub != nil {
+		data.PlanName = sub.PlanName
+	}
+	return data
+}
+
+// Report returns the recovery of the user's runs on an app started in [from, to)
+func (s *DunningService) Report(ctx context.Context, userID, appID uuid.UUID, from, to time.Time) (*DunningRecoveryReport, error) {
+	if !from.Before(to) {
+		return nil, ErrInvalidDunningReportRange
+	}
+
+	sequences, err := s.sequenceRepo.FindByUserAndApp(ctx, userID, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dunning sequences: %w", err)
+	}
+	runs, err := s.runRepo.FindStartedBetween(ctx, userID, appID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dunning runs: %w", err)
+	}
+
+	report := &DunningRecoveryReport{From: from, To: to, Sequences: []DunningSequenceRecovery{}}
+	bySequence := make(map[uuid.UUID][]*entity.DunningRun)
+	for _, run := range runs {
+		bySequence[run.SequenceID] = append(bySequence[run.SequenceID], run)
+	}
+	for _, sequence := range sequences {
+		report.Sequences = append(report.Sequences, DunningSequenceRecovery{
+			SequenceID:           sequence.ID,
+			Name:                 sequence.Name,
+			DunningRecoveryStats: dunningRecoveryStats(bySequence[sequence.ID]),
+		})
+	}
+	report.Total = dunningRecoveryStats(runs)
+
+	return report, nil
+}
+
+func dunningRecoveryStats(runs []*entity.DunningRun) DunningRecoveryStats {
+	var stats DunningRecoveryStats
+	var recoveryDays float64
+	for _, run := range runs {
+		stats.Started++
+		stats.AtRiskMRRCents += run.MRRCents
+
+		switch run.Status {
+		case entity.DunningRunActive:
+			stats.Active++
+		case entity.DunningRunRecovered:
+			stats.Recovered++
+			stats.RecoveredMRRCents += run.MRRCents
+			if run.RecoveredAt != nil {
+				recoveryDays += run.RecoveredAt.Sub(run.StartedAt).Hours() / 24
+			}
+		case entity.DunningRunExhausted:
+			stats.Exhausted++
+		case entity.DunningRunCancelled:
+			stats.Cancelled++
+		}
+	}
+
+	if finished := stats.Recovered + stats.Exhausted; finished > 0 {
+		stats.RecoveryRate = float64(stats.Recovered) / float64(finished) * 100
+	}
+	if stats.Recovered > 0 {
+		stats.AvgDaysToRecover = recoveryDays / float64(stats.Recovered)
+	}
+	return stats
+}
+
+func (s *DunningService) findSequence(ctx context.Context, userID, appID, id uuid.UUID) (*entity.DunningSequence, error) {
+	sequence, err := s.sequenceRepo.FindByID(ctx, id)
+	if err != nil || sequence.UserID != userID || sequence.AppID != appID {
+		return nil, ErrDunningSequenceNotFound
+	}
+	return sequence, nil
+}
+
+// validateDunningSequence validates the sequence and checks every step's
+// templates render, so mistakes surface when saving rather than on day 7
+func validateDunningSequence(sequence *entity.DunningSequence) error {
+	if err := sequence.Validate(); err != nil {
+		return err
+	}
+
+	sample := dunningTemplateData{
+		ShopName:    "Example Shop",
+		ShopDomain:  "example.myshopify.com",
+		ContactName: "Alex",
+		AppName:     "Example App",
+		PlanName:    "Pro",
+		Amount:      "$29.00",
+		RiskState:   string(sequence.Trigger),
+	}
+	for i, step := range sequence.Steps {
+		sample.Day = step.Day
+		for _, text := range []string{step.Subject, step.Body} {
+			if _, err := renderDunningTemplate(text, sample); err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// renderDunningTemplate renders a step's subject or body
+func renderDunningTemplate(text string, data dunningTemplateData) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	tmpl, err := texttemplate.New("step").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidDunningTemplate, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidDunningTemplate, err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
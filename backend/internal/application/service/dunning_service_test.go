@@ -0,0 +1,625 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+type mockDunningSequenceRepo struct {
+	sequences []*entity.DunningSequence
+}
+
+func (m *mockDunningSequenceRepo) Create(ctx context.Context, sequence *entity.DunningSequence) error {
+	m.sequences = append(m.sequences, sequence)
+	return nil
+}
+
+func (m *mockDunningSequenceRepo) Update(ctx context.Context, sequence *entity.DunningSequence) error {
+	for i, s := range m.sequences {
+		if s.ID == sequence.ID {
+			m.sequences[i] = sequence
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *mockDunningSequenceRepo) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	for i, s := range m.sequences {
+		if s.ID == id && s.UserID == userID {
+			m.sequences = append(m.sequences[:i], m.sequences[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *mockDunningSequenceRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.DunningSequence, error) {
+	for _, s := range m.sequences {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockDunningSequenceRepo) FindByUserAndApp(ctx context.Context, userID, appID uuid.UUID) ([]*entity.DunningSequence, error) {
+	var result []*entity.DunningSequence
+	for _, s := range m.sequences {
+		if s.UserID == userID && s.AppID == appID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockDunningSequenceRepo) FindEnabledByTrigger(ctx context.Context, appID uuid.UUID, trigger valueobject.RiskState) ([]*entity.DunningSequence, error) {
+	var result []*entity.DunningSequence
+	for _, s := range m.sequences {
+		if s.AppID == appID && s.Trigger == trigger && s.Enabled {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+type mockDunningRunRepo struct {
+	runs   []*entity.DunningRun
+	locked map[uuid.UUID]time.Time
+}
+
+func newMockDunningRunRepo() *mockDunningRunRepo {
+	return &mockDunningRunRepo{locked: make(map[uuid.UUID]time.Time)}
+}
+
+func (m *mockDunningRunRepo) Create(ctx context.Context, run *entity.DunningRun) (bool, error) {
+	for _, r := range m.runs {
+		if r.SequenceID == run.SequenceID && r.SubscriptionID == run.SubscriptionID && r.Status == entity.DunningRunActive {
+			return false, nil
+		}
+	}
+	m.runs = append(m.runs, run)
+	return true, nil
+}
+
+func (m *mockDunningRunRepo) Update(ctx context.Context, run *entity.DunningRun) error {
+	delete(m.locked, run.ID)
+	return nil
+}
+
+func (m *mockDunningRunRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.DunningRun, error) {
+	for _, r := range m.runs {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockDunningRunRepo) List(ctx context.Context, filter repository.DunningRunFilter) ([]*entity.DunningRun, error) {
+	var result []*entity.DunningRun
+	for _, r := range m.runs {
+		if r.UserID == filter.UserID && r.AppID == filter.AppID && (filter.Status == "" || r.Status == filter.Status) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockDunningRunRepo) FindRecoverable(ctx context.Context, subscriptionID uuid.UUID, exhaustedSince time.Time) ([]*entity.DunningRun, error) {
+	var result []*entity.DunningRun
+	for _, r := range m.runs {
+		if r.SubscriptionID != subscriptionID {
+			continue
+		}
+		if r.Status == entity.DunningRunActive || (r.Status == entity.DunningRunExhausted && !r.EndedAt.Before(exhaustedSince)) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockDunningRunRepo) FindStartedBetween(ctx context.Context, userID, appID uuid.UUID, from, to time.Time) ([]*entity.DunningRun, error) {
+	var result []*entity.DunningRun
+	for _, r := range m.runs {
+		if r.UserID == userID && r.AppID == appID && !r.StartedAt.Before(from) && r.StartedAt.Before(to) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockDunningRunRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.DunningRun, error) {
+	var result []*entity.DunningRun
+	for _, r := range m.runs {
+		if len(result) == limit {
+			break
+		}
+		if r.Status != entity.DunningRunActive || r.NextStepAt == nil || r.NextStepAt.After(now) {
+			continue
+		}
+		if until, ok := m.locked[r.ID]; ok && until.After(now) {
+			continue
+		}
+		m.locked[r.ID] = now.Add(lease)
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+type mockDunningTaskRepo struct {
+	tasks []*entity.DunningTask
+}
+
+func (m *mockDunningTaskRepo) Create(ctx context.Context, task *entity.DunningTask) error {
+	m.tasks = append(m.tasks, task)
+	return nil
+}
+
+func (m *mockDunningTaskRepo) Update(ctx context.Context, task *entity.DunningTask) error {
+	return nil
+}
+
+func (m *mockDunningTaskRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.DunningTask, error) {
+	for _, t := range m.tasks {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockDunningTaskRepo) List(ctx context.Context, filter repository.DunningTaskFilter) ([]*entity.DunningTask, error) {
+	var result []*entity.DunningTask
+	for _, t := range m.tasks {
+		if t.UserID == filter.UserID && t.AppID == filter.AppID && (filter.Status == "" || t.Status == filter.Status) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockDunningTaskRepo) CancelOpenByRunID(ctx context.Context, runID uuid.UUID) error {
+	for _, t := range m.tasks {
+		if t.RunID == runID && t.Status == entity.DunningTaskOpen {
+			t.Status = entity.DunningTaskCancelled
+		}
+	}
+	return nil
+}
+
+type mockShopContactRepo struct {
+	contacts map[string]*entity.ShopContact
+}
+
+func newMockShopContactRepo() *mockShopContactRepo {
+	return &mockShopContactRepo{contacts: make(map[string]*entity.ShopContact)}
+}
+
+func (m *mockShopContactRepo) Upsert(ctx context.Context, contact *entity.ShopContact) error {
+	m.contacts[contact.AppID.String()+contact.MyshopifyDomain] = contact
+	return nil
+}
+
+func (m *mockShopContactRepo) FindByDomain(ctx context.Context, appID uuid.UUID, domain string) (*entity.ShopContact, error) {
+	return m.contacts[appID.String()+domain], nil
+}
+
+func (m *mockShopContactRepo) Delete(ctx context.Context, appID uuid.UUID, domain string) error {
+	delete(m.contacts, appID.String()+domain)
+	return nil
+}
+
+// newTestDunningSequence builds the example sequence: day 1 internal alert, day 3 merchant email, day 7 task
+func newTestDunningSequence(userID, appID uuid.UUID, now time.Time) *entity.DunningSequence {
+	return entity.NewDunningSequence(userID, appID, "Missed payment", valueobject.RiskStateOneCycleMissed, []entity.DunningStep{
+		{Day: 1, Action: entity.DunningActionInternalAlert},
+		{Day: 3, Action: entity.DunningActionMerchantEmail, Subject: "Your {{.AppName}} payment", Body: "Hi {{.ContactName}},\nyour payment of {{.Amount}} for {{.ShopName}} did not go through."},
+		{Day: 7, Action: entity.DunningActionTask, Subject: "Call {{.ShopName}}", Assignee: "sam@example.com"},
+	}, now)
+}
+
+func newTestDunningSubscription(appID uuid.UUID) *entity.Subscription {
+	return &entity.Subscription{
+		ID:              uuid.New(),
+		AppID:           appID,
+		MyshopifyDomain: "cool-shop.myshopify.com",
+		ShopName:        "Cool Shop",
+		BasePriceCents:  2900,
+		BillingInterval: valueobject.BillingIntervalMonthly,
+	}
+}
+
+func riskEvent(sub *entity.Subscription, from, to valueobject.RiskState) *entity.SubscriptionEvent {
+	return &entity.SubscriptionEvent{ID: uuid.New(), SubscriptionID: sub.ID, FromRiskState: from, ToRiskState: to}
+}
+
+func TestDunningService_ProcessDue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("runs steps on their days", func(t *testing.T) {
+		now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		userID := uuid.New()
+		app := &entity.App{ID: uuid.New(), Name: "Bundle Builder"}
+		runRepo := newMockDunningRunRepo()
+		taskRepo := &mockDunningTaskRepo{}
+		notificationRepo := newMockNotificationRepo()
+		emailSender := &mockEmailSender{}
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+			WithOutbox(notificationRepo)
+		svc := NewDunningService(&mockDunningSequenceRepo{}, runRepo, taskRepo, newMockShopContactRepo(), &mockAppRepoForSync{app: app},
+			&mockRecognitionSubscriptionRepo{}, notificationRepo, notificationService).
+			WithEmailSender(emailSender)
+		svc.now = func() time.Time { return now }
+		if err := svc.CreateSequence(ctx, newTestDunningSequence(userID, app.ID, now)); err != nil {
+			t.Fatalf("CreateSequence() error = %v", err)
+		}
+		sub := newTestDunningSubscription(app.ID)
+		svc.SetShopContact(ctx, app.ID, sub.MyshopifyDomain, "owner@coolshop.com", "Jamie")
+
+		if err := svc.PublishSubscriptionEvent(ctx, sub, riskEvent(sub, valueobject.RiskStateSafe, valueobject.RiskStateOneCycleMissed)); err != nil {
+			t.Fatalf("PublishSubscriptionEvent() error = %v", err)
+		}
+		if len(runRepo.runs) != 1 {
+			t.Fatalf("expected 1 run, got %d", len(runRepo.runs))
+		}
+		run := runRepo.runs[0]
+		if run.MRRCents != 2900 || run.ShopName != "Cool Shop" {
+			t.Errorf("run = %+v, expected the subscription's shop and MRR", run)
+		}
+
+		// Day 1: internal alert
+		svc.ProcessDue(ctx)
+		if len(notificationRepo.notifications) != 1 {
+			t.Fatalf("expected 1 notification on day 1, got %d", len(notificationRepo.notifications))
+		}
+		n := notificationRepo.notifications[0]
+		if n.Kind != entity.NotificationKindDunning || *n.SourceID != run.ID || !strings.Contains(n.Title, "Cool Shop") {
+			t.Errorf("notification = %+v", n)
+		}
+
+		// Day 2: nothing due
+		now = now.AddDate(0, 0, 1)
+		svc.ProcessDue(ctx)
+		if len(emailSender.sent) != 0 {
+			t.Fatalf("expected no email before day 3, got %d", len(emailSender.sent))
+		}
+
+		// Day 3: merchant email to the shop contact
+		now = now.AddDate(0, 0, 1)
+		svc.ProcessDue(ctx)
+		if len(emailSender.sent) != 1 {
+			t.Fatalf("expected 1 email on day 3, got %d", len(emailSender.sent))
+		}
+		email := emailSender.sent[0]
+		if email.to != "owner@coolshop.com" || email.subject != "Your Bundle Builder payment" {
+			t.Errorf("email to %q subject %q", email.to, email.subject)
+		}
+		if !strings.Contains(email.text, "Hi Jamie,") || !strings.Contains(email.text, "$29.00 for Cool Shop") {
+			t.Errorf("email text = %q", email.text)
+		}
+		if strings.Contains(email.text, "LedgerGuard") || !strings.Contains(email.text, "Bundle Builder") {
+			t.Errorf("merchant email should be sent for the app, not LedgerGuard: %q", email.text)
+		}
+
+		// Day 7: task, then the run is exhausted
+		now = now.AddDate(0, 0, 4)
+		svc.ProcessDue(ctx)
+		if len(taskRepo.tasks) != 1 {
+			t.Fatalf("expected 1 task on day 7, got %d", len(taskRepo.tasks))
+		}
+		if task := taskRepo.tasks[0]; task.Title != "Call Cool Shop" || task.Assignee != "sam@example.com" {
+			t.Errorf("task = %+v", task)
+		}
+		if run.Status != entity.DunningRunExhausted || len(run.Results) != 3 {
+			t.Errorf("run status = %s with %d results, expected EXHAUSTED with 3", run.Status, len(run.Results))
+		}
+	})
+
+	t.Run("merchant email without contact is skipped", func(t *testing.T) {
+		now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		userID := uuid.New()
+		app := &entity.App{ID: uuid.New(), Name: "Bundle Builder"}
+		runRepo := newMockDunningRunRepo()
+		taskRepo := &mockDunningTaskRepo{}
+		notificationRepo := newMockNotificationRepo()
+		emailSender := &mockEmailSender{}
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+			WithOutbox(notificationRepo)
+		svc := NewDunningService(&mockDunningSequenceRepo{}, runRepo, taskRepo, newMockShopContactRepo(), &mockAppRepoForSync{app: app},
+			&mockRecognitionSubscriptionRepo{}, notificationRepo, notificationService).
+			WithEmailSender(emailSender)
+		svc.now = func() time.Time { return now }
+		if err := svc.CreateSequence(ctx, newTestDunningSequence(userID, app.ID, now)); err != nil {
+			t.Fatalf("CreateSequence() error = %v", err)
+		}
+		sub := newTestDunningSubscription(app.ID)
+
+		svc.PublishSubscriptionEvent(ctx, sub, riskEvent(sub, valueobject.RiskStateSafe, valueobject.RiskStateOneCycleMissed))
+		svc.ProcessDue(ctx)
+		now = now.AddDate(0, 0, 2)
+		svc.ProcessDue(ctx)
+
+		run := runRepo.runs[0]
+		if len(run.Results) != 2 || run.Results[1].Status != entity.DunningStepSkipped {
+			t.Fatalf("results = %+v, expected the email step skipped", run.Results)
+		}
+		if run.NextStep != 2 || run.Status != entity.DunningRunActive {
+			t.Errorf("run should move on to the task step, got step %d status %s", run.NextStep, run.Status)
+		}
+	})
+
+	t.Run("failing step is retried then recorded as failed", func(t *testing.T) {
+		now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		userID := uuid.New()
+		app := &entity.App{ID: uuid.New(), Name: "Bundle Builder"}
+		runRepo := newMockDunningRunRepo()
+		taskRepo := &mockDunningTaskRepo{}
+		notificationRepo := newMockNotificationRepo()
+		emailSender := &mockEmailSender{}
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+			WithOutbox(notificationRepo)
+		svc := NewDunningService(&mockDunningSequenceRepo{}, runRepo, taskRepo, newMockShopContactRepo(), &mockAppRepoForSync{app: app},
+			&mockRecognitionSubscriptionRepo{}, notificationRepo, notificationService).
+			WithEmailSender(emailSender)
+		svc.now = func() time.Time { return now }
+		if err := svc.CreateSequence(ctx, newTestDunningSequence(userID, app.ID, now)); err != nil {
+			t.Fatalf("CreateSequence() error = %v", err)
+		}
+		sub := newTestDunningSubscription(app.ID)
+		svc.SetShopContact(ctx, app.ID, sub.MyshopifyDomain, "owner@coolshop.com", "")
+		emailSender.sendErr = errors.New("smtp unavailable")
+
+		svc.PublishSubscriptionEvent(ctx, sub, riskEvent(sub, valueobject.RiskStateSafe, valueobject.RiskStateOneCycleMissed))
+		svc.ProcessDue(ctx)
+		now = now.AddDate(0, 0, 2)
+
+		run := runRepo.runs[0]
+		for attempt := 1; attempt < entity.MaxDunningStepAttempts; attempt++ {
+			svc.ProcessDue(ctx)
+			if run.Attempts != attempt || run.NextStep != 1 {
+				t.Fatalf("attempt %d: attempts = %d, step = %d", attempt, run.Attempts, run.NextStep)
+			}
+			if !run.NextStepAt.After(now) {
+				t.Fatalf("attempt %d: retry not scheduled later", attempt)
+			}
+			now = *run.NextStepAt
+		}
+
+		svc.ProcessDue(ctx)
+		if run.NextStep != 2 || run.Results[1].Status != entity.DunningStepFailed {
+			t.Errorf("results = %+v, expected the email step recorded as FAILED", run.Results)
+		}
+		if !strings.Contains(run.Results[1].Detail, "smtp unavailable") {
+			t.Errorf("detail = %q, expected the error", run.Results[1].Detail)
+		}
+	})
+}
+
+func TestDunningService_PublishSubscriptionEvent(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("starts one run per transition", func(t *testing.T) {
+		now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		userID := uuid.New()
+		app := &entity.App{ID: uuid.New(), Name: "Bundle Builder"}
+		runRepo := newMockDunningRunRepo()
+		taskRepo := &mockDunningTaskRepo{}
+		notificationRepo := newMockNotificationRepo()
+		emailSender := &mockEmailSender{}
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+			WithOutbox(notificationRepo)
+		svc := NewDunningService(&mockDunningSequenceRepo{}, runRepo, taskRepo, newMockShopContactRepo(), &mockAppRepoForSync{app: app},
+			&mockRecognitionSubscriptionRepo{}, notificationRepo, notificationService).
+			WithEmailSender(emailSender)
+		svc.now = func() time.Time { return now }
+		if err := svc.CreateSequence(ctx, newTestDunningSequence(userID, app.ID, now)); err != nil {
+			t.Fatalf("CreateSequence() error = %v", err)
+		}
+		sub := newTestDunningSubscription(app.ID)
+
+		svc.PublishSubscriptionEvent(ctx, sub, riskEvent(sub, valueobject.RiskStateSafe, valueobject.RiskStateOneCycleMissed))
+		svc.PublishSubscriptionEvent(ctx, sub, riskEvent(sub, valueobject.RiskStateSafe, valueobject.RiskStateOneCycleMissed))
+		svc.PublishSubscriptionEvent(ctx, sub, riskEvent(sub, valueobject.RiskStateOneCycleMissed, valueobject.RiskStateOneCycleMissed))
+		svc.PublishSubscriptionEvent(ctx, sub, riskEvent(sub, valueobject.RiskStateOneCycleMissed, valueobject.RiskStateTwoCyclesMissed))
+
+		if len(runRepo.runs) != 1 {
+			t.Errorf("expected 1 run, got %d", len(runRepo.runs))
+		}
+	})
+
+	t.Run("stops when shop returns to safe", func(t *testing.T) {
+		now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		userID := uuid.New()
+		app := &entity.App{ID: uuid.New(), Name: "Bundle Builder"}
+		runRepo := newMockDunningRunRepo()
+		taskRepo := &mockDunningTaskRepo{}
+		notificationRepo := newMockNotificationRepo()
+		emailSender := &mockEmailSender{}
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+			WithOutbox(notificationRepo)
+		svc := NewDunningService(&mockDunningSequenceRepo{}, runRepo, taskRepo, newMockShopContactRepo(), &mockAppRepoForSync{app: app},
+			&mockRecognitionSubscriptionRepo{}, notificationRepo, notificationService).
+			WithEmailSender(emailSender)
+		svc.now = func() time.Time { return now }
+		if err := svc.CreateSequence(ctx, newTestDunningSequence(userID, app.ID, now)); err != nil {
+			t.Fatalf("CreateSequence() error = %v", err)
+		}
+		sub := newTestDunningSubscription(app.ID)
+
+		svc.PublishSubscriptionEvent(ctx, sub, riskEvent(sub, valueobject.RiskStateSafe, valueobject.RiskStateOneCycleMissed))
+		svc.ProcessDue(ctx)
+
+		now = now.AddDate(0, 0, 2)
+		svc.PublishSubscriptionEvent(ctx, sub, riskEvent(sub, valueobject.RiskStateOneCycleMissed, valueobject.RiskStateSafe))
+
+		run := runRepo.runs[0]
+		if run.Status != entity.DunningRunRecovered || run.RecoveredAt == nil {
+			t.Fatalf("run status = %s, expected RECOVERED", run.Status)
+		}
+
+		now = now.AddDate(0, 0, 10)
+		svc.ProcessDue(ctx)
+		if len(emailSender.sent) != 0 || len(taskRepo.tasks) != 0 {
+			t.Errorf("recovered run kept running: %d emails, %d tasks", len(emailSender.sent), len(taskRepo.tasks))
+		}
+	})
+
+	t.Run("recovery cancels open tasks", func(t *testing.T) {
+		now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		userID := uuid.New()
+		app := &entity.App{ID: uuid.New(), Name: "Bundle Builder"}
+		runRepo := newMockDunningRunRepo()
+		taskRepo := &mockDunningTaskRepo{}
+		notificationRepo := newMockNotificationRepo()
+		emailSender := &mockEmailSender{}
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+			WithOutbox(notificationRepo)
+		svc := NewDunningService(&mockDunningSequenceRepo{}, runRepo, taskRepo, newMockShopContactRepo(), &mockAppRepoForSync{app: app},
+			&mockRecognitionSubscriptionRepo{}, notificationRepo, notificationService).
+			WithEmailSender(emailSender)
+		svc.now = func() time.Time { return now }
+		if err := svc.CreateSequence(ctx, newTestDunningSequence(userID, app.ID, now)); err != nil {
+			t.Fatalf("CreateSequence() error = %v", err)
+		}
+		sub := newTestDunningSubscription(app.ID)
+
+		svc.PublishSubscriptionEvent(ctx, sub, riskEvent(sub, valueobject.RiskStateSafe, valueobject.RiskStateOneCycleMissed))
+		for day := 0; day < 7; day++ {
+			svc.ProcessDue(ctx)
+			now = now.AddDate(0, 0, 1)
+		}
+		if run := runRepo.runs[0]; run.Status != entity.DunningRunExhausted {
+			t.Fatalf("run status = %s, expected EXHAUSTED", run.Status)
+		}
+
+		// A shop recovering within the window after the last step still counts
+		svc.PublishSubscriptionEvent(ctx, sub, riskEvent(sub, valueobject.RiskStateOneCycleMissed, valueobject.RiskStateSafe))
+
+		if run := runRepo.runs[0]; run.Status != entity.DunningRunRecovered {
+			t.Errorf("run status = %s, expected RECOVERED", run.Status)
+		}
+		if task := taskRepo.tasks[0]; task.Status != entity.DunningTaskCancelled {
+			t.Errorf("task status = %s, expected CANCELLED", task.Status)
+		}
+	})
+}
+
+func TestDunningService_CreateSequence(t *testing.T) {
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	userID, appID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		trigger valueobject.RiskState
+		steps   []entity.DunningStep
+		wantErr error
+	}{
+		{"unknown field", valueobject.RiskStateChurned, []entity.DunningStep{{Day: 1, Action: entity.DunningActionMerchantEmail, Subject: "Hi", Body: "{{.Nope}}"}}, ErrInvalidDunningTemplate},
+		{"syntax error", valueobject.RiskStateChurned, []entity.DunningStep{{Day: 1, Action: entity.DunningActionInternalAlert, Subject: "{{.ShopName"}}, ErrInvalidDunningTemplate},
+		{"days out of order", valueobject.RiskStateChurned, []entity.DunningStep{{Day: 3, Action: entity.DunningActionInternalAlert}, {Day: 2, Action: entity.DunningActionInternalAlert}}, entity.ErrInvalidDunningStepDay},
+		{"task without assignee", valueobject.RiskStateChurned, []entity.DunningStep{{Day: 1, Action: entity.DunningActionTask, Subject: "Call"}}, entity.ErrInvalidDunningStep},
+		{"safe trigger", valueobject.RiskStateSafe, []entity.DunningStep{{Day: 1, Action: entity.DunningActionInternalAlert}}, entity.ErrInvalidDunningTrigger},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notificationRepo := newMockNotificationRepo()
+			notificationService := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+				WithOutbox(notificationRepo)
+			svc := NewDunningService(&mockDunningSequenceRepo{}, newMockDunningRunRepo(), &mockDunningTaskRepo{}, newMockShopContactRepo(),
+				&mockAppRepoForSync{app: &entity.App{ID: appID, Name: "Bundle Builder"}}, &mockRecognitionSubscriptionRepo{}, notificationRepo, notificationService)
+
+			sequence := entity.NewDunningSequence(userID, appID, "Test", tt.trigger, tt.steps, now)
+			if err := svc.CreateSequence(context.Background(), sequence); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateSequence() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDunningService_Report(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("aggregates run outcomes", func(t *testing.T) {
+		now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		userID := uuid.New()
+		app := &entity.App{ID: uuid.New(), Name: "Bundle Builder"}
+		runRepo := newMockDunningRunRepo()
+		taskRepo := &mockDunningTaskRepo{}
+		notificationRepo := newMockNotificationRepo()
+		emailSender := &mockEmailSender{}
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+			WithOutbox(notificationRepo)
+		svc := NewDunningService(&mockDunningSequenceRepo{}, runRepo, taskRepo, newMockShopContactRepo(), &mockAppRepoForSync{app: app},
+			&mockRecognitionSubscriptionRepo{}, notificationRepo, notificationService).
+			WithEmailSender(emailSender)
+		svc.now = func() time.Time { return now }
+		sequence := newTestDunningSequence(userID, app.ID, now)
+		if err := svc.CreateSequence(ctx, sequence); err != nil {
+			t.Fatalf("CreateSequence() error = %v", err)
+		}
+		start := now
+
+		subs := make([]*entity.Subscription, 4)
+		for i := range subs {
+			subs[i] = newTestDunningSubscription(app.ID)
+			svc.PublishSubscriptionEvent(ctx, subs[i], riskEvent(subs[i], valueobject.RiskStateSafe, valueobject.RiskStateOneCycleMissed))
+		}
+
+		// Two shops recover after 2 days, one is cancelled, one runs out of steps
+		now = now.AddDate(0, 0, 2)
+		svc.PublishSubscriptionEvent(ctx, subs[0], riskEvent(subs[0], valueobject.RiskStateOneCycleMissed, valueobject.RiskStateSafe))
+		svc.PublishSubscriptionEvent(ctx, subs[1], riskEvent(subs[1], valueobject.RiskStateOneCycleMissed, valueobject.RiskStateSafe))
+		if _, err := svc.CancelRun(ctx, userID, app.ID, runRepo.runs[2].ID); err != nil {
+			t.Fatalf("CancelRun() error = %v", err)
+		}
+		now = now.AddDate(0, 0, 10)
+		for i := 0; i < 3; i++ {
+			svc.ProcessDue(ctx)
+		}
+
+		report, err := svc.Report(ctx, userID, app.ID, start.AddDate(0, 0, -1), now)
+		if err != nil {
+			t.Fatalf("Report() error = %v", err)
+		}
+
+		total := report.Total
+		if total.Started != 4 || total.Recovered != 2 || total.Exhausted != 1 || total.Cancelled != 1 {
+			t.Fatalf("total = %+v", total)
+		}
+		if total.RecoveryRate < 66.6 || total.RecoveryRate > 66.7 {
+			t.Errorf("recovery rate = %.2f, expected 66.67", total.RecoveryRate)
+		}
+		if total.RecoveredMRRCents != 5800 || total.AtRiskMRRCents != 11600 {
+			t.Errorf("recovered MRR = %d of %d, expected 5800 of 11600", total.RecoveredMRRCents, total.AtRiskMRRCents)
+		}
+		if total.AvgDaysToRecover != 2 {
+			t.Errorf("average days to recover = %.2f, expected 2", total.AvgDaysToRecover)
+		}
+		if len(report.Sequences) != 1 || report.Sequences[0].SequenceID != sequence.ID || report.Sequences[0].Recovered != 2 {
+			t.Errorf("sequences = %+v", report.Sequences)
+		}
+	})
+
+	t.Run("rejects an empty range", func(t *testing.T) {
+		now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		notificationRepo := newMockNotificationRepo()
+		notificationService := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+			WithOutbox(notificationRepo)
+		svc := NewDunningService(&mockDunningSequenceRepo{}, newMockDunningRunRepo(), &mockDunningTaskRepo{}, newMockShopContactRepo(),
+			&mockAppRepoForSync{app: &entity.App{ID: uuid.New(), Name: "Bundle Builder"}}, &mockRecognitionSubscriptionRepo{}, notificationRepo, notificationService)
+
+		if _, err := svc.Report(ctx, uuid.New(), uuid.New(), now, now); !errors.Is(err, ErrInvalidDunningReportRange) {
+			t.Errorf("Report() with an empty range error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+//go:embed email_templates/*.tmpl
+var emailTemplateFS embed.FS
+
+// Email templates, each rendered inside layout.html.tmpl and layout.txt.tmpl
+const (
+	emailTemplateCriticalAlert = "critical_alert"
+	emailTemplateDailySummary  = "daily_summary"
+	emailTemplateWeeklyDigest  = "weekly_digest"
+	emailTemplateVerifyEmail   = "verify_email"
+	emailTemplateInvitation    = "workspace_invitation"
+)
+
+// emailTemplateMerchantEmail is the dunning email to a merchant, rendered
+// inside merchant_layout without LedgerGuard branding since it is sent on
+// behalf of the app
+const emailTemplateMerchantEmail = "merchant_email"
+
+var (
+	emailHTMLTemplates = map[string]*htmltemplate.Template{}
+	emailTextTemplates = map[string]*texttemplate.Template{}
+)
+
+func init() {
+	for _, name := range []string{emailTemplateCriticalAlert, emailTemplateDailySummary, emailTemplateWeeklyDigest, emailTemplateVerifyEmail, emailTemplateInvitation} {
+		emailHTMLTemplates[name] = htmltemplate.Must(htmltemplate.ParseFS(emailTemplateFS,
+			"email_templates/layout.html.tmpl", "email_templates/"+name+".html.tmpl"))
+		emailTextTemplates[name] = texttemplate.Must(texttemplate.ParseFS(emailTemplateFS,
+			"email_templates/layout.txt.tmpl", "email_templates/"+name+".txt.tmpl"))
+	}
+
+	emailHTMLTemplates[emailTemplateMerchantEmail] = htmltemplate.Must(htmltemplate.ParseFS(emailTemplateFS,
+		"email_templates/merchant_layout.html.tmpl", "email_templates/merchant_email.html.tmpl"))
+	emailTextTemplates[emailTemplateMerchantEmail] = texttemplate.Must(texttemplate.ParseFS(emailTemplateFS,
+		"email_templates/merchant_layout.txt.tmpl", "email_templates/merchant_email.txt.tmpl"))
+}
+
+// emailContent is a rendered email
+type emailContent struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// emailMetric is one "Label: value" line of a summary or digest
+type emailMetric struct {
+	Label string
+	Value string
+}
+
+type emailTemplateData struct {
+	Title          string
+	Severity       string
+	Color          string        // Accent colour, by severity
+	Lines          []string      // Body, one paragraph per line
+	Metrics        []emailMetric // Body parsed as "Label: value" pairs, for summaries and digests
+	ActionURL      string        // Verification link
+	UnsubscribeURL string
+	Sender         string // Merchant emails: the app the email is sent for
+	Code           string // Workspace invitations: the code the invitee accepts with
+}
+
+// renderNotificationEmail renders a notification with the template for its kind
+func renderNotificationEmail(n *entity.Notification, unsubscribeURL string) (*emailContent, error) {
+	name := emailTemplateCriticalAlert
+	switch n.Kind {
+	case entity.NotificationKindDailySummary:
+		name = emailTemplateDailySummary
+	case entity.NotificationKindWeeklyDigest:
+		name = emailTemplateWeeklyDigest
+	}
+
+	return renderEmail(name, n.Title, emailTemplateData{
+		Title:          n.Title,
+		Severity:       string(n.Severity),
+		Color:          slackColorForSeverity(n.Severity),
+		Lines:          strings.Split(n.Body, "\n"),
+		Metrics:        parseEmailMetrics(n.Body),
+		UnsubscribeURL: unsubscribeURL,
+	})
+}
+
+// renderVerificationEmail renders the email confirming a new notification address
+func renderVerificationEmail(verifyURL string) (*emailContent, error) {
+	subject := "Confirm your LedgerGuard notification email"
+	return renderEmail(emailTemplateVerifyEmail, subject, emailTemplateData{
+		Title:     subject,
+		Color:     SlackColorInfo,
+		ActionURL: verifyURL,
+	})
+}
+
+// renderInvitationEmail renders the email inviting an address to a workspace
+func renderInvitationEmail(workspaceName, inviterEmail, role, token string) (*emailContent, error) {
+	subject := fmt.Sprintf("You're invited to join %s on LedgerGuard", workspaceName)
+	invitedBy := "You have"
+	if inviterEmail != "" {
+		invitedBy = inviterEmail + " has"
+	}
+	return renderEmail(emailTemplateInvitation, subject, emailTemplateData{
+		Title: subject,
+		Color: SlackColorInfo,
+		Lines: []string{fmt.Sprintf("%s invited you to join the %s workspace on LedgerGuard as %s.", invitedBy, workspaceName, role)},
+		Code:  token,
+	})
+}
+
+// renderMerchantEmail renders a dunning email to a merchant from its already
+// rendered subject and body
+func renderMerchantEmail(subject, body, sender string) (*emailContent, error) {
+	return renderEmail(emailTemplateMerchantEmail, subject, emailTemplateData{
+		Title:  subject,
+		Lines:  strings.Split(body, "\n"),
+		Sender: sender,
+	})
+}
+
+func renderEmail(name, subject string, data emailTemplateData) (*emailContent, error) {
+	var html, text bytes.Buffer
+	if err := emailHTMLTemplates[name].ExecuteTemplate(&html, "layout", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s html email: %w", name, err)
+	}
+	if err := emailTextTemplates[name].ExecuteTemplate(&text, "layout", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s text email: %w", name, err)
+	}
+
+	return &emailContent{Subject: subject, Text: text.String(), HTML: html.String()}, nil
+}
+
+// parseEmailMetrics splits a summary body such as "MRR: $10.00 | At Risk: $2.00"
+// into label and value pairs. Parts without a label are dropped.
+func parseEmailMetrics(body string) []emailMetric {
+	var metrics []emailMetric
+	for _, line := range strings.Split(body, "\n") {
+		for _, part := range strings.Split(line, " | ") {
+			label, value, ok := strings.Cut(part, ": ")
+			if !ok {
+				continue
+			}
+			metrics = append(metrics, emailMetric{Label: strings.TrimSpace(label), Value: strings.TrimSpace(value)})
+		}
+	}
+	return metrics
+}
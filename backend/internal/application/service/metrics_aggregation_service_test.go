@@ -67,6 +67,10 @@ func (m *mockTxRepo) UpsertBatch(ctx context.Context, txs []*entity.Transaction)
 	return m.err
 }
 
+func (m *mockTxRepo) FindCreatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*entity.Transaction, error) {
+	return nil, nil
+}
+
 func (m *mockTxRepo) FindByAppID(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.Transaction, error) {
 	var result []*entity.Transaction
 	for _, tx := range m.transactions {
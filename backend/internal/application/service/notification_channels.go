@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+// notificationChannels is the order channels are requested in
+var notificationChannels = []entity.NotificationChannel{
+	entity.NotificationChannelPush,
+	entity.NotificationChannelSlack,
+	entity.NotificationChannelEmail,
+	entity.NotificationChannelTeams,
+	entity.NotificationChannelDiscord,
+	entity.NotificationChannelWebhook,
+}
+
+// NotificationChannelProvider delivers notifications over one channel
+type NotificationChannelProvider interface {
+	// HasDestination returns true if the user has somewhere to receive the channel
+	HasDestination(ctx context.Context, userID uuid.UUID) (bool, error)
+	// Deliver sends the notification to every destination the user has on the
+	// channel. Returns false without an error if the user has none.
+	Deliver(ctx context.Context, notification *entity.Notification) (bool, error)
+}
+
+// pushChannel sends FCM pushes to the user's registered devices
+type pushChannel struct {
+	deviceTokenRepo repository.DeviceTokenRepository
+	provider        PushNotificationProvider
+}
+
+// HasDestination is always true: push is requested even before the user
+// registers a device, and skipped at delivery if there is still none
+func (c *pushChannel) HasDestination(ctx context.Context, userID uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+func (c *pushChannel) Deliver(ctx context.Context, notification *entity.Notification) (bool, error) {
+	tokens, err := c.deviceTokenRepo.FindByUserID(ctx, notification.UserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get device tokens: %w", err)
+	}
+	if len(tokens) == 0 {
+		return false, nil
+	}
+	var lastErr error
+	for _, token := range tokens {
+		if err := c.provider.SendPush(ctx, token.DeviceToken, token.Platform, notification.Title, notification.Body); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr == nil, lastErr
+}
+
+// emailChannel sends to each of the user's verified, subscribed addresses
+type emailChannel struct {
+	emailRepo      repository.NotificationEmailRepository
+	sender         EmailSender
+	publicURL      string
+	unsubscribeKey []byte
+}
+
+// HasDestination returns true if the user has a deliverable address
+func (c *emailChannel) HasDestination(ctx context.Context, userID uuid.UUID) (bool, error) {
+	emails, err := c.emailRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, email := range emails {
+		if email.IsDeliverable() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Deliver sends the notification to each deliverable address, with that address's unsubscribe link
+func (c *emailChannel) Deliver(ctx context.Context, notification *entity.Notification) (bool, error) {
+	emails, err := c.emailRepo.FindByUserID(ctx, notification.UserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get notification emails: %w", err)
+	}
+
+	sent := false
+	var lastErr error
+	for _, email := range emails {
+		if !email.IsDeliverable() {
+			continue
+		}
+		unsubscribeURL := notificationEmailURL(c.publicURL, "unsubscribe", entity.UnsubscribeToken(c.unsubscribeKey, email.ID))
+		content, err := renderNotificationEmail(notification, unsubscribeURL)
+		if err != nil {
+			return false, err
+		}
+		if err := c.sender.SendEmail(ctx, email.Address, content.Subject, content.Text, content.HTML, unsubscribeURL); err != nil {
+			lastErr = err
+			continue
+		}
+		sent = true
+	}
+
+	if lastErr != nil {
+		return false, lastErr
+	}
+	return sent, nil
+}
+
+// webhookChannel sends to the user's enabled webhooks of one channel. For
+// Slack it also sends to the webhook URL in the user's notification preferences.
+type webhookChannel struct {
+	channel     entity.NotificationChannel
+	webhookRepo repository.NotificationWebhookRepository     // nil if webhooks are not configured
+	prefsRepo   repository.NotificationPreferencesRepository // SLACK only
+	send        func(ctx context.Context, webhook *entity.NotificationWebhook, notification *entity.Notification) error
+}
+
+// HasDestination returns true if the user has an enabled webhook on the channel
+func (c *webhookChannel) HasDestination(ctx context.Context, userID uuid.UUID) (bool, error) {
+	webhooks, err := c.destinations(ctx, userID)
+	return len(webhooks) > 0, err
+}
+
+func (c *webhookChannel) Deliver(ctx context.Context, notification *entity.Notification) (bool, error) {
+	webhooks, err := c.destinations(ctx, notification.UserID)
+	if err != nil {
+		return false, err
+	}
+	if len(webhooks) == 0 {
+		return false, nil
+	}
+	var lastErr error
+	for _, webhook := range webhooks {
+		if err := c.send(ctx, webhook, notification); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr == nil, lastErr
+}
+
+func (c *webhookChannel) destinations(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationWebhook, error) {
+	var result []*entity.NotificationWebhook
+	if c.prefsRepo != nil {
+		if prefs, err := c.prefsRepo.FindByUserID(ctx, userID); err == nil && prefs.SlackWebhookURL != "" {
+			result = append(result, &entity.NotificationWebhook{
+				UserID:  userID,
+				Channel: c.channel,
+				Name:    "Notification preferences",
+				URL:     prefs.SlackWebhookURL,
+				Enabled: true,
+			})
+		}
+	}
+
+	if c.webhookRepo == nil {
+		return result, nil
+	}
+	webhooks, err := c.webhookRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return result, fmt.Errorf("failed to get notification webhooks: %w", err)
+	}
+	for _, webhook := range webhooks {
+		if webhook.Channel == c.channel && webhook.Enabled {
+			result = append(result, webhook)
+		}
+	}
+	return result, nil
+}
+
+// webhookNotificationPayload is the JSON body posted to generic webhooks
+type webhookNotificationPayload struct {
+	ID        string  `json:"id"`
+	Kind      string  `json:"kind"`
+	Severity  string  `json:"severity"`
+	Title     string  `json:"title"`
+	Body      string  `json:"body"`
+	AppID     *string `json:"app_id"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// marshalWebhookNotification builds the generic webhook body for a notification
+func marshalWebhookNotification(notification *entity.Notification) ([]byte, error) {
+	payload := webhookNotificationPayload{
+		ID:        notification.ID.String(),
+		Kind:      string(notification.Kind),
+		Severity:  string(notification.Severity),
+		Title:     notification.Title,
+		Body:      notification.Body,
+		CreatedAt: notification.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if notification.AppID != nil {
+		appID := notification.AppID.String()
+		payload.AppID = &appID
+	}
+	return json.Marshal(payload)
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/pkg/netguard"
+)
+
+type mockNotificationWebhookRepo struct {
+	webhooks []*entity.NotificationWebhook
+}
+
+func (m *mockNotificationWebhookRepo) Create(ctx context.Context, webhook *entity.NotificationWebhook) error {
+	m.webhooks = append(m.webhooks, webhook)
+	return nil
+}
+
+func (m *mockNotificationWebhookRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.NotificationWebhook, error) {
+	for _, w := range m.webhooks {
+		if w.ID == id {
+			return w, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockNotificationWebhookRepo) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationWebhook, error) {
+	var result []*entity.NotificationWebhook
+	for _, w := range m.webhooks {
+		if w.UserID == userID {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockNotificationWebhookRepo) Update(ctx context.Context, webhook *entity.NotificationWebhook) error {
+	return nil
+}
+
+func (m *mockNotificationWebhookRepo) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	for i, w := range m.webhooks {
+		if w.ID == id && w.UserID == userID {
+			m.webhooks = append(m.webhooks[:i], m.webhooks[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+// mockChatNotifier records Teams and Discord messages
+type mockChatNotifier struct {
+	sentMessages []slackMessage
+	sendErr      error
+}
+
+func (m *mockChatNotifier) send(webhookURL, title, body, color string) error {
+	if m.sendErr != nil {
+		return m.sendErr
+	}
+	m.sentMessages = append(m.sentMessages, slackMessage{webhookURL: webhookURL, title: title, body: body, color: color})
+	return nil
+}
+
+func (m *mockChatNotifier) SendTeams(ctx context.Context, webhookURL string, title string, body string, color string) error {
+	return m.send(webhookURL, title, body, color)
+}
+
+func (m *mockChatNotifier) SendDiscord(ctx context.Context, webhookURL string, title string, body string, color string) error {
+	return m.send(webhookURL, title, body, color)
+}
+
+type sentWebhook struct {
+	webhookURL string
+	secret     string
+	payload    []byte
+}
+
+type mockWebhookNotifier struct {
+	sent    []sentWebhook
+	sendErr error
+}
+
+func (m *mockWebhookNotifier) SendWebhook(ctx context.Context, webhookURL string, secret string, payload []byte) error {
+	if m.sendErr != nil {
+		return m.sendErr
+	}
+	m.sent = append(m.sent, sentWebhook{webhookURL: webhookURL, secret: secret, payload: payload})
+	return nil
+}
+
+func TestNotificationService_WebhookChannels(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	prefsRepo := newMockNotificationPreferencesRepository()
+	slackNotifier := newMockSlackNotifier()
+	teams := &mockChatNotifier{}
+	discord := &mockChatNotifier{}
+	generic := &mockWebhookNotifier{}
+	outbox := newMockNotificationRepo()
+	svc := NewNotificationService(newMockDeviceTokenRepository(), prefsRepo, nil).
+		WithSlackNotifier(slackNotifier).
+		WithWebhooks(&mockNotificationWebhookRepo{}, teams, discord, generic).
+		WithWebhookSecretEncryptor(prefixEncryptor{}).
+		WithOutbox(outbox)
+
+	prefs := entity.NewNotificationPreferences(userID)
+	prefs.SlackWebhookURL = "https://hooks.slack.com/services/legacy"
+	_ = prefsRepo.Upsert(ctx, prefs)
+
+	mustCreate := func(channel entity.NotificationChannel, name, url string) *entity.NotificationWebhook {
+		t.Helper()
+		webhook, err := svc.CreateWebhook(ctx, userID, channel, name, url)
+		if err != nil {
+			t.Fatalf("failed to create %s webhook: %v", channel, err)
+		}
+		return webhook
+	}
+	mustCreate(entity.NotificationChannelSlack, "Ops Slack", "https://hooks.slack.com/services/ops")
+	mustCreate(entity.NotificationChannelTeams, "Ops Teams", "https://example.webhook.office.com/ops")
+	disabled := mustCreate(entity.NotificationChannelTeams, "Old Teams", "https://example.webhook.office.com/old")
+	generic1 := mustCreate(entity.NotificationChannelWebhook, "Client", "https://client.example.com/hooks")
+	if string(generic1.EncryptedSecret) != "enc:"+generic1.Secret {
+		t.Fatalf("expected the generic webhook secret to be stored encrypted, got %q", generic1.EncryptedSecret)
+	}
+
+	off := false
+	if _, err := svc.UpdateWebhook(ctx, userID, disabled.ID, UpdateNotificationWebhookRequest{Enabled: &off}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := svc.SendCriticalAlert(ctx, userID, "MyApp", "store.myshopify.com",
+		valueobject.RiskStateSafe, valueobject.RiskStateOneCycleMissed); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	n := outbox.notifications[0]
+
+	// Discord has no webhook, so it is not requested
+	want := []entity.NotificationChannel{entity.NotificationChannelSlack, entity.NotificationChannelTeams, entity.NotificationChannelWebhook}
+	if len(n.Channels) != len(want) {
+		t.Fatalf("expected channels %v, got %v", want, n.Channels)
+	}
+	for i, c := range want {
+		if n.Channels[i] != c {
+			t.Fatalf("expected channels %v, got %v", want, n.Channels)
+		}
+	}
+
+	for _, channel := range n.Channels {
+		if sent, err := svc.Deliver(ctx, n, channel); err != nil || !sent {
+			t.Fatalf("expected %s delivery, got sent=%v err=%v", channel, sent, err)
+		}
+	}
+	if sent, err := svc.Deliver(ctx, n, entity.NotificationChannelDiscord); err != nil || sent {
+		t.Errorf("expected discord to be skipped, got sent=%v err=%v", sent, err)
+	}
+
+	if len(slackNotifier.sentMessages) != 2 || slackNotifier.sentMessages[0].webhookURL != prefs.SlackWebhookURL {
+		t.Errorf("expected the preferences webhook and the Slack channel, got %+v", slackNotifier.sentMessages)
+	}
+	if len(teams.sentMessages) != 1 || teams.sentMessages[0].webhookURL != "https://example.webhook.office.com/ops" ||
+		teams.sentMessages[0].color != SlackColorDanger {
+		t.Errorf("expected one Teams card to the enabled webhook, got %+v", teams.sentMessages)
+	}
+
+	if len(generic.sent) != 1 || generic.sent[0].secret != generic1.Secret || !strings.HasPrefix(generic1.Secret, "whsec_") {
+		t.Fatalf("expected one signed generic webhook, got %+v", generic.sent)
+	}
+	var payload webhookNotificationPayload
+	if err := json.Unmarshal(generic.sent[0].payload, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.ID != n.ID.String() || payload.Kind != "RISK_CHANGE" || payload.Severity != "CRITICAL" || payload.Title != n.Title {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+
+	// A failing Teams webhook is returned for retry
+	teams.sendErr = errors.New("teams unavailable")
+	if sent, err := svc.Deliver(ctx, n, entity.NotificationChannelTeams); err == nil || sent {
+		t.Errorf("expected error, got sent=%v err=%v", sent, err)
+	}
+}
+
+func TestNotificationService_CreateWebhook(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	svc := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+		WithSlackNotifier(newMockSlackNotifier()).
+		WithWebhooks(&mockNotificationWebhookRepo{}, &mockChatNotifier{}, nil, &mockWebhookNotifier{}).
+		WithWebhookSecretEncryptor(prefixEncryptor{})
+
+	tests := []struct {
+		name    string
+		channel entity.NotificationChannel
+		url     string
+		wantErr error
+	}{
+		{"plain http", entity.NotificationChannelSlack, "http://hooks.example.com/x", ErrInvalidNotificationWebhookURL},
+		{"relative", entity.NotificationChannelTeams, "/hooks/x", ErrInvalidNotificationWebhookURL},
+		{"not a webhook channel", entity.NotificationChannelEmail, "https://hooks.example.com/x", entity.ErrInvalidNotificationWebhookChannel},
+		{"channel not configured", entity.NotificationChannelDiscord, "https://discord.com/api/webhooks/1/x", ErrNotificationChannelUnavailable},
+		{"loopback http", entity.NotificationChannelWebhook, "http://127.0.0.1:9000/hooks", ErrInvalidNotificationWebhookURL},
+		{"metadata service", entity.NotificationChannelWebhook, "https://169.254.169.254/latest/meta-data", ErrInvalidNotificationWebhookURL},
+		{"private network", entity.NotificationChannelTeams, "https://10.0.0.8/hooks", ErrInvalidNotificationWebhookURL},
+		{"public https", entity.NotificationChannelWebhook, "https://hooks.example.com/x", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateWebhook(ctx, userID, tt.channel, "Hook", tt.url)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("loopback allowed by a development guard", func(t *testing.T) {
+		devSvc := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+			WithWebhooks(&mockNotificationWebhookRepo{}, nil, nil, &mockWebhookNotifier{}).
+			WithWebhookSecretEncryptor(prefixEncryptor{}).
+			WithURLGuard(netguard.New(true))
+		if _, err := devSvc.CreateWebhook(ctx, userID, entity.NotificationChannelWebhook, "Hook", "http://127.0.0.1:9000/hooks"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("limits webhooks per channel", func(t *testing.T) {
+		for i := 0; i < MaxNotificationWebhooksPerChannel; i++ {
+			if _, err := svc.CreateWebhook(ctx, userID, entity.NotificationChannelSlack, "Hook", "https://hooks.slack.com/x"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if _, err := svc.CreateWebhook(ctx, userID, entity.NotificationChannelSlack, "Hook", "https://hooks.slack.com/x"); !errors.Is(err, ErrTooManyNotificationWebhooks) {
+			t.Errorf("expected ErrTooManyNotificationWebhooks, got %v", err)
+		}
+	})
+}
+
+func TestNotificationService_TestWebhook(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	teams := &mockChatNotifier{}
+	svc := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+		WithWebhooks(&mockNotificationWebhookRepo{}, teams, nil, nil)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	webhook, err := svc.CreateWebhook(ctx, userID, entity.NotificationChannelTeams, "Ops", "https://example.webhook.office.com/ops")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tested, err := svc.TestWebhook(ctx, userID, webhook.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tested.LastTestedAt == nil || !tested.LastTestedAt.Equal(now) || tested.LastTestError != "" {
+		t.Errorf("expected a successful test to be recorded, got %+v", tested)
+	}
+	if len(teams.sentMessages) != 1 || !strings.Contains(teams.sentMessages[0].body, "Ops") {
+		t.Errorf("expected a test card, got %+v", teams.sentMessages)
+	}
+
+	teams.sendErr = errors.New("dial tcp 10.0.0.8:443: connect: connection refused")
+	tested, err = svc.TestWebhook(ctx, userID, webhook.ID)
+	if err != nil {
+		t.Fatalf("expected the failure to be recorded, not returned: %v", err)
+	}
+	if tested.LastTestError != ErrNotificationTestFailed.Error() {
+		t.Errorf("expected the generic LastTestError %q, got %q", ErrNotificationTestFailed, tested.LastTestError)
+	}
+
+	if _, err := svc.TestWebhook(ctx, uuid.New(), webhook.ID); !errors.Is(err, ErrNotificationWebhookNotFound) {
+		t.Errorf("expected ErrNotificationWebhookNotFound for another user, got %v", err)
+	}
+	if _, err := svc.RotateWebhookSecret(ctx, userID, webhook.ID); !errors.Is(err, ErrNotificationWebhookUnsigned) {
+		t.Errorf("expected ErrNotificationWebhookUnsigned, got %v", err)
+	}
+}
+
+func TestNotificationService_SignsWithDecryptedSecret(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	repo := &mockNotificationWebhookRepo{}
+	generic := &mockWebhookNotifier{}
+	svc := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+		WithWebhooks(repo, nil, nil, generic).
+		WithWebhookSecretEncryptor(prefixEncryptor{})
+
+	webhook, err := svc.CreateWebhook(ctx, userID, entity.NotificationChannelWebhook, "Client", "https://client.example.com/hooks")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the encrypted secret is stored; the plaintext is returned once on create
+	plaintext := webhook.Secret
+	webhook.Secret = ""
+	if _, err := svc.TestWebhook(ctx, userID, webhook.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(generic.sent) != 1 || generic.sent[0].secret != plaintext {
+		t.Errorf("expected the test to be signed with the decrypted secret, got %+v", generic.sent)
+	}
+}
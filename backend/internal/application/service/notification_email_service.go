@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+var (
+	// ErrNotificationEmailNotFound is returned when the address does not belong to the user
+	ErrNotificationEmailNotFound = errors.New("notification email not found")
+	// ErrNotificationEmailExists is returned when the user already added the address
+	ErrNotificationEmailExists = errors.New("email address already added")
+	// ErrNotificationEmailVerified is returned when resending verification for a verified address
+	ErrNotificationEmailVerified = errors.New("email address is already verified")
+	// ErrInvalidEmailLink is returned when a verification or unsubscribe token matches no address
+	ErrInvalidEmailLink = errors.New("invalid or expired link")
+)
+
+// NotificationEmailService manages the addresses a user receives notification
+// emails at. New addresses get a verification email, and only verified
+// addresses are sent notifications; every notification email carries a link
+// that unsubscribes its address.
+type NotificationEmailService struct {
+	emailRepo      repository.NotificationEmailRepository
+	emailSender    EmailSender
+	publicURL      string
+	unsubscribeKey []byte
+	now            func() time.Time
+}
+
+// NewNotificationEmailService creates a new NotificationEmailService. publicURL
+// is the API's public base URL, used for verification links. unsubscribeKey
+// signs unsubscribe tokens and must match the one given to NotificationService.WithEmail.
+func NewNotificationEmailService(
+	emailRepo repository.NotificationEmailRepository,
+	emailSender EmailSender,
+	publicURL string,
+	unsubscribeKey []byte,
+) *NotificationEmailService {
+	return &NotificationEmailService{
+		emailRepo:      emailRepo,
+		emailSender:    emailSender,
+		publicURL:      strings.TrimRight(publicURL, "/"),
+		unsubscribeKey: unsubscribeKey,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// ListAddresses returns the user's notification addresses
+func (s *NotificationEmailService) ListAddresses(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationEmail, error) {
+	emails, err := s.emailRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if emails == nil {
+		emails = []*entity.NotificationEmail{}
+	}
+	return emails, nil
+}
+
+// AddAddress adds an unverified address and sends its verification email. The
+// address is kept if the email fails; the user can resend it.
+func (s *NotificationEmailService) AddAddress(ctx context.Context, userID uuid.UUID, address string) (*entity.NotificationEmail, error) {
+	email, err := entity.NewNotificationEmail(userID, address, s.unsubscribeKey, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.emailRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range existing {
+		if e.Address == email.Address {
+			return nil, ErrNotificationEmailExists
+		}
+	}
+
+	if err := s.emailRepo.Create(ctx, email); err != nil {
+		return nil, fmt.Errorf("failed to save notification email: %w", err)
+	}
+
+	if err := s.sendVerification(ctx, email); err != nil {
+		log.Printf("NotificationEmailService: failed to send verification to %s: %v", email.Address, err)
+	}
+	return email, nil
+}
+
+// ResendVerification sends a new verification link, invalidating the previous one
+func (s *NotificationEmailService) ResendVerification(ctx context.Context, userID, id uuid.UUID) (*entity.NotificationEmail, error) {
+	email, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if email.IsVerified() {
+		return nil, ErrNotificationEmailVerified
+	}
+
+	if err := email.RenewVerification(s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.emailRepo.Update(ctx, email); err != nil {
+		return nil, fmt.Errorf("failed to save notification email: %w", err)
+	}
+
+	if err := s.sendVerification(ctx, email); err != nil {
+		return nil, fmt.Errorf("failed to send verification email: %w", err)
+	}
+	return email, nil
+}
+
+// RemoveAddress deletes one of the user's addresses
+func (s *NotificationEmailService) RemoveAddress(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.emailRepo.Delete(ctx, userID, id); err != nil {
+		return ErrNotificationEmailNotFound
+	}
+	return nil
+}
+
+// Verify confirms the address a verification link was sent to
+func (s *NotificationEmailService) Verify(ctx context.Context, token string) (*entity.NotificationEmail, error) {
+	if token == "" {
+		return nil, ErrInvalidEmailLink
+	}
+	email, err := s.emailRepo.FindByVerificationTokenHash(ctx, entity.HashEmailToken(token))
+	if err != nil {
+		return nil, ErrInvalidEmailLink
+	}
+
+	if err := email.Verify(s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.emailRepo.Update(ctx, email); err != nil {
+		return nil, fmt.Errorf("failed to save notification email: %w", err)
+	}
+	return email, nil
+}
+
+// FindUnsubscribeAddress returns the address an unsubscribe link belongs to,
+// without unsubscribing it
+func (s *NotificationEmailService) FindUnsubscribeAddress(ctx context.Context, token string) (*entity.NotificationEmail, error) {
+	if token == "" {
+		return nil, ErrInvalidEmailLink
+	}
+	email, err := s.emailRepo.FindByUnsubscribeTokenHash(ctx, entity.HashEmailToken(token))
+	if err != nil {
+		return nil, ErrInvalidEmailLink
+	}
+	return email, nil
+}
+
+// Unsubscribe stops notification email to the address an unsubscribe link belongs to
+func (s *NotificationEmailService) Unsubscribe(ctx context.Context, token string) (*entity.NotificationEmail, error) {
+	email, err := s.FindUnsubscribeAddress(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	email.Unsubscribe(s.now())
+	if err := s.emailRepo.Update(ctx, email); err != nil {
+		return nil, fmt.Errorf("failed to save notification email: %w", err)
+	}
+	return email, nil
+}
+
+func (s *NotificationEmailService) sendVerification(ctx context.Context, email *entity.NotificationEmail) error {
+	content, err := renderVerificationEmail(notificationEmailURL(s.publicURL, "verify", email.VerificationToken))
+	if err != nil {
+		return err
+	}
+	return s.emailSender.SendEmail(ctx, email.Address, content.Subject, content.Text, content.HTML, "")
+}
+
+func (s *NotificationEmailService) findOwned(ctx context.Context, userID, id uuid.UUID) (*entity.NotificationEmail, error) {
+	email, err := s.emailRepo.FindByID(ctx, id)
+	if err != nil || email.UserID != userID {
+		return nil, ErrNotificationEmailNotFound
+	}
+	return email, nil
+}
+
+// notificationEmailURL builds a verification or unsubscribe link
+func notificationEmailURL(publicURL, action, token string) string {
+	return publicURL + "/api/v1/notification-emails/" + action + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+type mockNotificationEmailRepo struct {
+	emails []*entity.NotificationEmail
+}
+
+func (m *mockNotificationEmailRepo) Create(ctx context.Context, email *entity.NotificationEmail) error {
+	m.emails = append(m.emails, email)
+	return nil
+}
+
+func (m *mockNotificationEmailRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.NotificationEmail, error) {
+	for _, e := range m.emails {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockNotificationEmailRepo) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationEmail, error) {
+	var result []*entity.NotificationEmail
+	for _, e := range m.emails {
+		if e.UserID == userID {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockNotificationEmailRepo) FindByVerificationTokenHash(ctx context.Context, tokenHash string) (*entity.NotificationEmail, error) {
+	for _, e := range m.emails {
+		if e.VerificationTokenHash == tokenHash {
+			return e, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockNotificationEmailRepo) FindByUnsubscribeTokenHash(ctx context.Context, tokenHash string) (*entity.NotificationEmail, error) {
+	for _, e := range m.emails {
+		if e.UnsubscribeTokenHash == tokenHash {
+			return e, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockNotificationEmailRepo) Update(ctx context.Context, email *entity.NotificationEmail) error {
+	return nil
+}
+
+func (m *mockNotificationEmailRepo) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	for i, e := range m.emails {
+		if e.ID == id && e.UserID == userID {
+			m.emails = append(m.emails[:i], m.emails[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+var testUnsubscribeKey = []byte("test-unsubscribe-key")
+
+type sentEmail struct {
+	to             string
+	subject        string
+	text           string
+	html           string
+	unsubscribeURL string
+}
+
+type mockEmailSender struct {
+	sent    []sentEmail
+	sendErr error
+}
+
+func (m *mockEmailSender) SendEmail(ctx context.Context, to, subject, textBody, htmlBody, unsubscribeURL string) error {
+	if m.sendErr != nil {
+		return m.sendErr
+	}
+	m.sent = append(m.sent, sentEmail{to: to, subject: subject, text: textBody, html: htmlBody, unsubscribeURL: unsubscribeURL})
+	return nil
+}
+
+// linkToken extracts the token query parameter from the link in an email body
+func linkToken(t *testing.T, body, action string) string {
+	t.Helper()
+	prefix := "https://api.example.com/api/v1/notification-emails/" + action + "?token="
+	i := strings.Index(body, prefix)
+	if i < 0 {
+		t.Fatalf("no %s link in %q", action, body)
+	}
+	link := strings.Fields(body[i:])[0]
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("invalid link %q: %v", link, err)
+	}
+	return u.Query().Get("token")
+}
+
+func TestNotificationEmailService_AddVerifyUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockNotificationEmailRepo{}
+	sender := &mockEmailSender{}
+	svc := NewNotificationEmailService(repo, sender, "https://api.example.com/", testUnsubscribeKey)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	userID := uuid.New()
+
+	email, err := svc.AddAddress(ctx, userID, " Owner@Example.com ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if email.Address != "owner@example.com" || email.IsVerified() {
+		t.Errorf("expected unverified lower-cased address, got %+v", email)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].to != "owner@example.com" || sender.sent[0].unsubscribeURL != "" {
+		t.Fatalf("expected a verification email, got %+v", sender.sent)
+	}
+
+	if _, err := svc.AddAddress(ctx, userID, "owner@example.com"); !errors.Is(err, ErrNotificationEmailExists) {
+		t.Errorf("expected ErrNotificationEmailExists, got %v", err)
+	}
+	if _, err := svc.AddAddress(ctx, userID, "not-an-email"); !errors.Is(err, entity.ErrInvalidEmailAddress) {
+		t.Errorf("expected ErrInvalidEmailAddress, got %v", err)
+	}
+
+	token := linkToken(t, sender.sent[0].text, "verify")
+	if email.VerificationTokenHash != entity.HashEmailToken(token) {
+		t.Errorf("expected only the verification token's hash to be stored, got %q", email.VerificationTokenHash)
+	}
+	if _, err := svc.Verify(ctx, token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !email.IsDeliverable() || email.VerificationToken != "" || email.VerificationTokenHash != "" {
+		t.Errorf("expected verified address with token cleared, got %+v", email)
+	}
+	if _, err := svc.Verify(ctx, token); !errors.Is(err, ErrInvalidEmailLink) {
+		t.Errorf("expected used link to be invalid, got %v", err)
+	}
+	if _, err := svc.ResendVerification(ctx, userID, email.ID); !errors.Is(err, ErrNotificationEmailVerified) {
+		t.Errorf("expected ErrNotificationEmailVerified, got %v", err)
+	}
+
+	unsubscribeToken := entity.UnsubscribeToken(testUnsubscribeKey, email.ID)
+	if _, err := svc.FindUnsubscribeAddress(ctx, unsubscribeToken); err != nil || !email.IsDeliverable() {
+		t.Fatalf("expected the address without unsubscribing it, got %v", err)
+	}
+	if _, err := svc.Unsubscribe(ctx, entity.UnsubscribeToken([]byte("other-key"), email.ID)); !errors.Is(err, ErrInvalidEmailLink) {
+		t.Errorf("expected a token signed with another key to be invalid, got %v", err)
+	}
+	if _, err := svc.Unsubscribe(ctx, unsubscribeToken); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if email.IsDeliverable() {
+		t.Error("expected unsubscribed address not to be deliverable")
+	}
+}
+
+func TestNotificationEmailService_VerificationExpires(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockNotificationEmailRepo{}
+	sender := &mockEmailSender{}
+	svc := NewNotificationEmailService(repo, sender, "https://api.example.com", testUnsubscribeKey)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	userID := uuid.New()
+
+	email, _ := svc.AddAddress(ctx, userID, "owner@example.com")
+	expired := email.VerificationToken
+
+	now = now.Add(entity.EmailVerificationTTL + time.Minute)
+	if _, err := svc.Verify(ctx, expired); !errors.Is(err, entity.ErrEmailVerificationExpired) {
+		t.Fatalf("expected ErrEmailVerificationExpired, got %v", err)
+	}
+
+	// Another user cannot resend it
+	if _, err := svc.ResendVerification(ctx, uuid.New(), email.ID); !errors.Is(err, ErrNotificationEmailNotFound) {
+		t.Errorf("expected ErrNotificationEmailNotFound, got %v", err)
+	}
+
+	if _, err := svc.ResendVerification(ctx, userID, email.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.Verify(ctx, expired); !errors.Is(err, ErrInvalidEmailLink) {
+		t.Errorf("expected the old link to stop working, got %v", err)
+	}
+	if _, err := svc.Verify(ctx, linkToken(t, sender.sent[1].text, "verify")); err != nil || !email.IsVerified() {
+		t.Errorf("expected the new link to verify, got %v", err)
+	}
+}
+
+func TestRenderNotificationEmail(t *testing.T) {
+	userID := uuid.New()
+	unsubscribeURL := "https://api.example.com/api/v1/notification-emails/unsubscribe?token=abc"
+
+	t.Run("critical alert", func(t *testing.T) {
+		n := entity.NewNotification(userID, entity.NotificationKindRiskChange, entity.AlertSeverityCritical,
+			"🚨 Risk Alert: MyApp", "store.myshopify.com changed from SAFE to <b>CHURNED</b>", nil, time.Now())
+		content, err := renderNotificationEmail(n, unsubscribeURL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if content.Subject != n.Title {
+			t.Errorf("Subject = %q", content.Subject)
+		}
+		if !strings.Contains(content.Text, "[CRITICAL]") || !strings.Contains(content.Text, "<b>CHURNED</b>") ||
+			!strings.Contains(content.Text, "Unsubscribe: "+unsubscribeURL) {
+			t.Errorf("unexpected text body: %q", content.Text)
+		}
+		if !strings.Contains(content.HTML, SlackColorDanger) || !strings.Contains(content.HTML, "&lt;b&gt;CHURNED&lt;/b&gt;") {
+			t.Errorf("expected escaped HTML body with danger colour, got %q", content.HTML)
+		}
+		if !strings.Contains(content.HTML, `href="https://api.example.com/api/v1/notification-emails/unsubscribe?token=abc"`) {
+			t.Errorf("expected unsubscribe link in HTML body")
+		}
+	})
+
+	t.Run("daily summary", func(t *testing.T) {
+		n := entity.NewNotification(userID, entity.NotificationKindDailySummary, entity.AlertSeverityInfo,
+			"📊 Daily Summary: MyApp", "MRR: $5000.00 | At Risk: $500.00 | Renewal Rate: 95.0%", nil, time.Now())
+		content, err := renderNotificationEmail(n, unsubscribeURL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, line := range []string{"MRR: $5000.00\n", "At Risk: $500.00\n", "Renewal Rate: 95.0%\n"} {
+			if !strings.Contains(content.Text, line) {
+				t.Errorf("expected %q in text body %q", line, content.Text)
+			}
+		}
+		if !strings.Contains(content.HTML, ">Renewal Rate</td>") {
+			t.Errorf("expected metric rows in HTML body")
+		}
+	})
+
+	t.Run("weekly digest", func(t *testing.T) {
+		previous := &entity.DailyMetricsSnapshot{ActiveMRRCents: 400000, RevenueAtRiskCents: 50000, RenewalSuccessRate: 0.9, ChurnedCount: 1}
+		current := &entity.DailyMetricsSnapshot{ActiveMRRCents: 500000, RevenueAtRiskCents: 20000, RenewalSuccessRate: 0.95, ChurnedCount: 3}
+		body := formatSnapshotChange(previous, current)
+		if body != "MRR: $5000.00 (+25.0%) | At Risk: $200.00 (-300.00) | Renewal Rate: 95.0% (+5.0 pts) | Churned: 3 (+2)" {
+			t.Errorf("unexpected digest body %q", body)
+		}
+
+		n := entity.NewNotification(userID, entity.NotificationKindWeeklyDigest, entity.AlertSeverityInfo,
+			"📈 Weekly Digest: MyApp", body, nil, time.Now())
+		content, err := renderNotificationEmail(n, unsubscribeURL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(content.Text, "compared with the week before") || !strings.Contains(content.Text, "Churned: 3 (+2)\n") {
+			t.Errorf("unexpected text body %q", content.Text)
+		}
+	})
+}
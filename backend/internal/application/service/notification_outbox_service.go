@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+const (
+	// MaxNotificationAttempts is the number of delivery attempts before a notification fails (~2h of retries)
+	MaxNotificationAttempts = 8
+
+	notificationRetryBase = time.Minute
+	notificationRetryMax  = time.Hour
+	notificationLease     = 2 * time.Minute // Longer than delivering a notification can take
+	maxNotificationList   = 100
+)
+
+// ErrNotificationNotFound is returned when the notification does not belong to the user
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationDeliverer sends a notification over one channel. Returns false
+// without an error if the user has nothing to send to on that channel.
+type NotificationDeliverer interface {
+	Deliver(ctx context.Context, notification *entity.Notification, channel entity.NotificationChannel) (bool, error)
+}
+
+// NotificationOutboxService delivers the notification outbox in the background
+// and serves the user's notification history. Each pending channel is tried
+// on every attempt and dropped once delivered, so a retry only resends to the
+// channels that failed. Every attempt is recorded per channel.
+type NotificationOutboxService struct {
+	notificationRepo repository.NotificationRepository
+	deliverer        NotificationDeliverer
+	interval         time.Duration
+	batchSize        int
+	now              func() time.Time
+	stopCh           chan struct{}
+	doneCh           chan struct{}
+}
+
+// NewNotificationOutboxService creates a new NotificationOutboxService polling every 10 seconds
+func NewNotificationOutboxService(
+	notificationRepo repository.NotificationRepository,
+	deliverer NotificationDeliverer,
+) *NotificationOutboxService {
+	return &NotificationOutboxService{
+		notificationRepo: notificationRepo,
+		deliverer:        deliverer,
+		interval:         10 * time.Second,
+		batchSize:        50,
+		now:              func() time.Time { return time.Now().UTC() },
+		stopCh:           make(chan struct{}),
+		doneCh:           make(chan struct{}),
+	}
+}
+
+// WithInterval sets how often pending notifications are polled
+func (s *NotificationOutboxService) WithInterval(interval time.Duration) *NotificationOutboxService {
+	s.interval = interval
+	return s
+}
+
+// Start begins delivering pending notifications
+func (s *NotificationOutboxService) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+// Stop gracefully stops the worker after the current batch
+func (s *NotificationOutboxService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+func (s *NotificationOutboxService) run(ctx context.Context) {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		// Drain full batches before waiting again
+		for {
+			n, err := s.ProcessDue(ctx)
+			if err != nil {
+				log.Printf("NotificationOutboxService: %v", err)
+			}
+			if err != nil || n < s.batchSize {
+				break
+			}
+		}
+	}
+}
+
+// ProcessDue attempts every pending notification that is due and returns how many were attempted
+func (s *NotificationOutboxService) ProcessDue(ctx context.Context) (int, error) {
+	notifications, err := s.notificationRepo.ClaimDue(ctx, s.now(), notificationLease, s.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due notifications: %w", err)
+	}
+
+	for _, n := range notifications {
+		if err := s.deliver(ctx, n); err != nil {
+			log.Printf("NotificationOutboxService: notification %s: %v", n.ID, err)
+		}
+	}
+
+	return len(notifications), nil
+}
+
+// deliver attempts a claimed notification's pending channels and records the outcome, releasing the lease
+func (s *NotificationOutboxService) deliver(ctx context.Context, n *entity.Notification) error {
+	now := s.now()
+	n.Attempts++
+
+	var remaining []entity.NotificationChannel
+	var lastErr error
+	for _, channel := range n.PendingChannels {
+		sent, err := s.deliverer.Deliver(ctx, n, channel)
+
+		status := entity.NotificationAttemptSent
+		errMsg := ""
+		switch {
+		case err != nil:
+			status = entity.NotificationAttemptFailed
+			errMsg = err.Error()
+			remaining = append(remaining, channel)
+			lastErr = fmt.Errorf("%s: %w", channel, err)
+		case !sent:
+			status = entity.NotificationAttemptSkipped
+		}
+
+		attempt := entity.NewNotificationAttempt(n.ID, channel, n.Attempts, status, errMsg, now)
+		if err := s.notificationRepo.CreateAttempt(ctx, attempt); err != nil {
+			log.Printf("NotificationOutboxService: failed to record attempt for %s: %v", n.ID, err)
+		}
+	}
+
+	n.PendingChannels = remaining
+	if len(remaining) == 0 {
+		n.Status = entity.NotificationStatusDelivered
+		n.NextAttemptAt = nil
+		n.LastError = ""
+		n.DeliveredAt = &now
+		return s.notificationRepo.Update(ctx, n)
+	}
+
+	n.LastError = lastErr.Error()
+	if n.Attempts < MaxNotificationAttempts {
+		next := now.Add(notificationRetryDelay(n.Attempts))
+		n.NextAttemptAt = &next
+	} else {
+		n.Status = entity.NotificationStatusFailed
+		n.NextAttemptAt = nil
+	}
+	return s.notificationRepo.Update(ctx, n)
+}
+
+// notificationRetryDelay returns the wait after the given failed attempt: 1m, 2m, 4m, ... capped at 1h
+func notificationRetryDelay(attempt int) time.Duration {
+	delay := notificationRetryBase
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= notificationRetryMax {
+			return notificationRetryMax
+		}
+	}
+	return delay
+}
+
+// ListNotifications returns the user's most recent notifications and their unread count
+func (s *NotificationOutboxService) ListNotifications(ctx context.Context, filter repository.NotificationFilter) ([]*entity.Notification, int, error) {
+	if filter.Limit <= 0 || filter.Limit > maxNotificationList {
+		filter.Limit = maxNotificationList
+	}
+
+	notifications, err := s.notificationRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if notifications == nil {
+		notifications = []*entity.Notification{}
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, filter.UserID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+// GetNotification returns one of the user's notifications with its delivery attempts
+func (s *NotificationOutboxService) GetNotification(ctx context.Context, userID, id uuid.UUID) (*entity.Notification, []*entity.NotificationAttempt, error) {
+	n, err := s.notificationRepo.FindByID(ctx, id)
+	if err != nil || n.UserID != userID {
+		return nil, nil, ErrNotificationNotFound
+	}
+
+	attempts, err := s.notificationRepo.FindAttempts(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attempts == nil {
+		attempts = []*entity.NotificationAttempt{}
+	}
+	return n, attempts, nil
+}
+
+// MarkRead marks one of the user's notifications as read
+func (s *NotificationOutboxService) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.notificationRepo.MarkRead(ctx, userID, id, s.now()); err != nil {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks all of the user's notifications as read and returns how many were unread
+func (s *NotificationOutboxService) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.notificationRepo.MarkAllRead(ctx, userID, s.now())
+}
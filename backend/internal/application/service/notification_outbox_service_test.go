@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+type mockNotificationRepo struct {
+	notifications []*entity.Notification
+	attempts      []*entity.NotificationAttempt
+	locked        map[uuid.UUID]time.Time
+	summaryDates  map[string]bool
+}
+
+func newMockNotificationRepo() *mockNotificationRepo {
+	return &mockNotificationRepo{locked: make(map[uuid.UUID]time.Time), summaryDates: make(map[string]bool)}
+}
+
+func (m *mockNotificationRepo) Create(ctx context.Context, n *entity.Notification) error {
+	m.notifications = append(m.notifications, n)
+	return nil
+}
+
+func (m *mockNotificationRepo) CreateDailySummary(ctx context.Context, n *entity.Notification, summaryDate time.Time) (bool, error) {
+	key := n.UserID.String() + summaryDate.Format("2006-01-02")
+	if m.summaryDates[key] {
+		return false, nil
+	}
+	m.summaryDates[key] = true
+	m.notifications = append(m.notifications, n)
+	return true, nil
+}
+
+func (m *mockNotificationRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error) {
+	for _, n := range m.notifications {
+		if n.ID == id {
+			return n, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockNotificationRepo) List(ctx context.Context, filter repository.NotificationFilter) ([]*entity.Notification, error) {
+	var result []*entity.Notification
+	for _, n := range m.notifications {
+		if n.UserID == filter.UserID && (!filter.UnreadOnly || !n.IsRead()) {
+			result = append(result, n)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockNotificationRepo) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	count := 0
+	for _, n := range m.notifications {
+		if n.UserID == userID && !n.IsRead() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockNotificationRepo) MarkRead(ctx context.Context, userID, id uuid.UUID, readAt time.Time) error {
+	for _, n := range m.notifications {
+		if n.ID == id && n.UserID == userID {
+			if n.ReadAt == nil {
+				n.ReadAt = &readAt
+			}
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *mockNotificationRepo) MarkAllRead(ctx context.Context, userID uuid.UUID, readAt time.Time) (int64, error) {
+	var marked int64
+	for _, n := range m.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			n.ReadAt = &readAt
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+func (m *mockNotificationRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.Notification, error) {
+	var due []*entity.Notification
+	for _, n := range m.notifications {
+		if n.Status == entity.NotificationStatusPending && !n.NextAttemptAt.After(now) && !m.locked[n.ID].After(now) {
+			due = append(due, n)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].CreatedAt.Before(due[j].CreatedAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for _, n := range due {
+		m.locked[n.ID] = now.Add(lease)
+	}
+	return due, nil
+}
+
+func (m *mockNotificationRepo) Update(ctx context.Context, n *entity.Notification) error {
+	delete(m.locked, n.ID)
+	return nil
+}
+
+func (m *mockNotificationRepo) CreateAttempt(ctx context.Context, a *entity.NotificationAttempt) error {
+	m.attempts = append(m.attempts, a)
+	return nil
+}
+
+func (m *mockNotificationRepo) FindAttempts(ctx context.Context, notificationID uuid.UUID) ([]*entity.NotificationAttempt, error) {
+	var result []*entity.NotificationAttempt
+	for _, a := range m.attempts {
+		if a.NotificationID == notificationID {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+// mockNotificationDeliverer fails the channels in errs and skips those in skip
+type mockNotificationDeliverer struct {
+	errs      map[entity.NotificationChannel]error
+	skip      map[entity.NotificationChannel]bool
+	delivered map[entity.NotificationChannel]int
+}
+
+func newMockNotificationDeliverer() *mockNotificationDeliverer {
+	return &mockNotificationDeliverer{
+		errs:      make(map[entity.NotificationChannel]error),
+		skip:      make(map[entity.NotificationChannel]bool),
+		delivered: make(map[entity.NotificationChannel]int),
+	}
+}
+
+func (m *mockNotificationDeliverer) Deliver(ctx context.Context, n *entity.Notification, channel entity.NotificationChannel) (bool, error) {
+	if err := m.errs[channel]; err != nil {
+		return false, err
+	}
+	if m.skip[channel] {
+		return false, nil
+	}
+	m.delivered[channel]++
+	return true, nil
+}
+
+func newTestNotification(userID uuid.UUID, createdAt time.Time) *entity.Notification {
+	return entity.NewNotification(userID, entity.NotificationKindAlert, entity.AlertSeverityWarning, "MRR drop",
+		"Active MRR dropped 6.0% day over day",
+		[]entity.NotificationChannel{entity.NotificationChannelPush, entity.NotificationChannelSlack}, createdAt)
+}
+
+func TestNotificationOutboxService_ProcessDue_RetriesOnlyFailedChannels(t *testing.T) {
+	repo := newMockNotificationRepo()
+	deliverer := newMockNotificationDeliverer()
+	deliverer.errs[entity.NotificationChannelSlack] = errors.New("slack returned status 500")
+	svc := NewNotificationOutboxService(repo, deliverer)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	n := newTestNotification(uuid.New(), now)
+	repo.Create(ctx, n)
+
+	if count, err := svc.ProcessDue(ctx); err != nil || count != 1 {
+		t.Fatalf("ProcessDue = %d, %v", count, err)
+	}
+	if n.Status != entity.NotificationStatusPending || n.Attempts != 1 ||
+		len(n.PendingChannels) != 1 || n.PendingChannels[0] != entity.NotificationChannelSlack {
+		t.Fatalf("expected slack left pending after first attempt, got %+v", n)
+	}
+	if !n.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("NextAttemptAt = %v, want 1m later", n.NextAttemptAt)
+	}
+	if count, _ := svc.ProcessDue(ctx); count != 0 {
+		t.Errorf("expected no attempt before the retry is due, got %d", count)
+	}
+
+	// Slack recovers; push is not resent
+	delete(deliverer.errs, entity.NotificationChannelSlack)
+	now = now.Add(time.Minute)
+	svc.ProcessDue(ctx)
+	if n.Status != entity.NotificationStatusDelivered || n.DeliveredAt == nil || len(n.PendingChannels) != 0 || n.LastError != "" {
+		t.Fatalf("expected delivered, got %+v", n)
+	}
+	if deliverer.delivered[entity.NotificationChannelPush] != 1 || deliverer.delivered[entity.NotificationChannelSlack] != 1 {
+		t.Errorf("expected one delivery per channel, got %v", deliverer.delivered)
+	}
+
+	_, attempts, err := svc.GetNotification(ctx, n.UserID, n.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(attempts) != 3 {
+		t.Fatalf("expected 3 recorded attempts, got %d", len(attempts))
+	}
+	if a := attempts[1]; a.Channel != entity.NotificationChannelSlack || a.Status != entity.NotificationAttemptFailed || a.Error == "" {
+		t.Errorf("expected failed slack attempt, got %+v", a)
+	}
+	if a := attempts[2]; a.Attempt != 2 || a.Status != entity.NotificationAttemptSent {
+		t.Errorf("expected second slack attempt sent, got %+v", a)
+	}
+}
+
+func TestNotificationOutboxService_ProcessDue_FailsAfterMaxAttempts(t *testing.T) {
+	repo := newMockNotificationRepo()
+	deliverer := newMockNotificationDeliverer()
+	deliverer.errs[entity.NotificationChannelPush] = errors.New("fcm unavailable")
+	svc := NewNotificationOutboxService(repo, deliverer)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	n := newTestNotification(uuid.New(), now)
+	repo.Create(ctx, n)
+
+	for i := 0; i < MaxNotificationAttempts; i++ {
+		svc.ProcessDue(ctx)
+		now = now.Add(notificationRetryMax)
+	}
+	if n.Status != entity.NotificationStatusFailed || n.Attempts != MaxNotificationAttempts || n.NextAttemptAt != nil {
+		t.Errorf("expected failure after %d attempts, got %s after %d", MaxNotificationAttempts, n.Status, n.Attempts)
+	}
+	if n.LastError != "PUSH: fcm unavailable" {
+		t.Errorf("LastError = %q", n.LastError)
+	}
+	if deliverer.delivered[entity.NotificationChannelSlack] != 1 {
+		t.Errorf("expected slack delivered once, got %d", deliverer.delivered[entity.NotificationChannelSlack])
+	}
+}
+
+func TestNotificationOutboxService_ProcessDue_SkippedChannelsCountAsDelivered(t *testing.T) {
+	repo := newMockNotificationRepo()
+	deliverer := newMockNotificationDeliverer()
+	deliverer.skip[entity.NotificationChannelSlack] = true
+	svc := NewNotificationOutboxService(repo, deliverer)
+	ctx := context.Background()
+
+	n := newTestNotification(uuid.New(), time.Now().UTC().Add(-time.Second))
+	repo.Create(ctx, n)
+	svc.ProcessDue(ctx)
+
+	if n.Status != entity.NotificationStatusDelivered {
+		t.Fatalf("expected delivered, got %s", n.Status)
+	}
+	if a := repo.attempts[1]; a.Channel != entity.NotificationChannelSlack || a.Status != entity.NotificationAttemptSkipped {
+		t.Errorf("expected skipped slack attempt, got %+v", a)
+	}
+}
+
+func TestNotificationRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{6, 32 * time.Minute},
+		{7, time.Hour},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := notificationRetryDelay(tt.attempt); got != tt.want {
+			t.Errorf("notificationRetryDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestNotificationOutboxService_ReadState(t *testing.T) {
+	repo := newMockNotificationRepo()
+	svc := NewNotificationOutboxService(repo, newMockNotificationDeliverer())
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	first := newTestNotification(userID, now)
+	second := newTestNotification(userID, now.Add(time.Minute))
+	other := newTestNotification(uuid.New(), now)
+	repo.Create(ctx, first)
+	repo.Create(ctx, second)
+	repo.Create(ctx, other)
+
+	if err := svc.MarkRead(ctx, userID, first.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !first.IsRead() {
+		t.Error("expected first notification read")
+	}
+	if err := svc.MarkRead(ctx, userID, other.ID); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("expected ErrNotificationNotFound for another user's notification, got %v", err)
+	}
+	if _, _, err := svc.GetNotification(ctx, userID, other.ID); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("expected ErrNotificationNotFound for another user's notification, got %v", err)
+	}
+
+	unread, count, err := svc.ListNotifications(ctx, repository.NotificationFilter{UserID: userID, UnreadOnly: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(unread) != 1 || unread[0].ID != second.ID || count != 1 {
+		t.Errorf("expected only the second notification unread, got %d (count %d)", len(unread), count)
+	}
+
+	if marked, _ := svc.MarkAllRead(ctx, userID); marked != 1 {
+		t.Errorf("expected 1 marked read, got %d", marked)
+	}
+	if _, count, _ := svc.ListNotifications(ctx, repository.NotificationFilter{UserID: userID}); count != 0 {
+		t.Errorf("expected no unread notifications, got %d", count)
+	}
+	if other.IsRead() {
+		t.Error("expected other user's notification to stay unread")
+	}
+}
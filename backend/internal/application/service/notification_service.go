@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/pkg/netguard"
 )
 
 // ErrDeviceTokenNotFound is returned when a device token is not found
@@ -18,6 +21,25 @@ var ErrDeviceTokenNotFound = errors.New("device token not found")
 // ErrInvalidPlatform is returned when an invalid platform is provided
 var ErrInvalidPlatform = errors.New("invalid platform")
 
+// MaxNotificationWebhooksPerChannel limits how many webhooks a user can add to one channel
+const MaxNotificationWebhooksPerChannel = 10
+
+var (
+	// ErrNotificationWebhookNotFound is returned when the webhook does not belong to the user
+	ErrNotificationWebhookNotFound = errors.New("notification webhook not found")
+	// ErrInvalidNotificationWebhookURL is returned for a webhook URL that is not absolute https to a public host
+	ErrInvalidNotificationWebhookURL = errors.New("url must be an absolute https URL to a public host")
+	// ErrNotificationTestFailed is recorded when a test notification is not delivered. The
+	// underlying error is only logged, so test sends can't be used to probe the network.
+	ErrNotificationTestFailed = errors.New("test notification could not be delivered")
+	// ErrNotificationChannelUnavailable is returned for a channel this server is not configured to deliver
+	ErrNotificationChannelUnavailable = errors.New("notification channel is not available")
+	// ErrTooManyNotificationWebhooks is returned when a channel already has MaxNotificationWebhooksPerChannel webhooks
+	ErrTooManyNotificationWebhooks = errors.New("too many webhooks on this channel")
+	// ErrNotificationWebhookUnsigned is returned when rotating the secret of a webhook that is not signed
+	ErrNotificationWebhookUnsigned = errors.New("only WEBHOOK channel webhooks have a signing secret")
+)
+
 // PushNotificationProvider defines the interface for sending push notifications
 type PushNotificationProvider interface {
 	// SendPush sends a push notification to a device
@@ -30,6 +52,31 @@ type SlackNotifier interface {
 	SendSlack(ctx context.Context, webhookURL string, title string, body string, color string) error
 }
 
+// TeamsNotifier defines the interface for sending Microsoft Teams notifications
+type TeamsNotifier interface {
+	// SendTeams posts an Adaptive Card to a Teams webhook
+	SendTeams(ctx context.Context, webhookURL string, title string, body string, color string) error
+}
+
+// DiscordNotifier defines the interface for sending Discord notifications
+type DiscordNotifier interface {
+	// SendDiscord posts an embed to a Discord webhook
+	SendDiscord(ctx context.Context, webhookURL string, title string, body string, color string) error
+}
+
+// WebhookNotifier defines the interface for sending generic webhook notifications
+type WebhookNotifier interface {
+	// SendWebhook POSTs a JSON payload signed with the webhook's secret
+	SendWebhook(ctx context.Context, webhookURL string, secret string, payload []byte) error
+}
+
+// EmailSender defines the interface for sending email
+type EmailSender interface {
+	// SendEmail sends a multipart text and HTML email. unsubscribeURL, if set,
+	// is sent as a one-click List-Unsubscribe header.
+	SendEmail(ctx context.Context, to string, subject string, textBody string, htmlBody string, unsubscribeURL string) error
+}
+
 // Slack color constants
 const (
 	SlackColorDanger  = "#dc3545" // Red - for critical alerts
@@ -38,12 +85,27 @@ const (
 	SlackColorInfo    = "#17a2b8" // Blue - for info
 )
 
-// NotificationService handles sending notifications to users
+// NotificationService handles sending notifications to users. Each channel is
+// a NotificationChannelProvider; a notification goes to every channel the user
+// has a destination on.
 type NotificationService struct {
 	deviceTokenRepo repository.DeviceTokenRepository
 	prefsRepo       repository.NotificationPreferencesRepository
 	pushProvider    PushNotificationProvider
 	slackNotifier   SlackNotifier
+	emailRepo       repository.NotificationEmailRepository
+	emailSender     EmailSender
+	publicURL       string // Base URL for unsubscribe links
+	unsubscribeKey  []byte // Signs unsubscribe tokens
+	webhookRepo     repository.NotificationWebhookRepository
+	teamsNotifier   TeamsNotifier
+	discordNotifier DiscordNotifier
+	webhookNotifier WebhookNotifier
+	secretEncryptor Encryptor
+	channels        map[entity.NotificationChannel]NotificationChannelProvider // Registered with WithChannel
+	urlGuard        *netguard.Guard
+	outbox          repository.NotificationRepository
+	now             func() time.Time
 }
 
 // NewNotificationService creates a new notification service
@@ -56,15 +118,72 @@ func NewNotificationService(
 		deviceTokenRepo: deviceTokenRepo,
 		prefsRepo:       prefsRepo,
 		pushProvider:    pushProvider,
+		channels:        make(map[entity.NotificationChannel]NotificationChannelProvider),
+		urlGuard:        netguard.New(false),
+		now:             func() time.Time { return time.Now().UTC() },
 	}
 }
 
+// WithURLGuard replaces the guard webhook URLs are validated with. Use the
+// same guard for the notifiers' HTTP clients.
+func (s *NotificationService) WithURLGuard(guard *netguard.Guard) *NotificationService {
+	s.urlGuard = guard
+	return s
+}
+
 // WithSlackNotifier adds Slack notification support
 func (s *NotificationService) WithSlackNotifier(notifier SlackNotifier) *NotificationService {
 	s.slackNotifier = notifier
 	return s
 }
 
+// WithEmail adds email delivery to the user's verified notification addresses.
+// publicURL is the API's public base URL, used for unsubscribe links, and
+// unsubscribeKey signs their tokens.
+func (s *NotificationService) WithEmail(repo repository.NotificationEmailRepository, sender EmailSender, publicURL string, unsubscribeKey []byte) *NotificationService {
+	s.emailRepo = repo
+	s.emailSender = sender
+	s.publicURL = strings.TrimRight(publicURL, "/")
+	s.unsubscribeKey = unsubscribeKey
+	return s
+}
+
+// WithWebhooks adds the user's configured webhooks: Slack webhooks alongside
+// the one in notification preferences, and Teams, Discord and generic webhooks.
+// A nil notifier leaves its channel off.
+func (s *NotificationService) WithWebhooks(
+	repo repository.NotificationWebhookRepository,
+	teams TeamsNotifier,
+	discord DiscordNotifier,
+	webhook WebhookNotifier,
+) *NotificationService {
+	s.webhookRepo = repo
+	s.teamsNotifier = teams
+	s.discordNotifier = discord
+	s.webhookNotifier = webhook
+	return s
+}
+
+// WithWebhookSecretEncryptor encrypts generic webhook signing secrets at rest.
+// The WEBHOOK channel is unavailable without it.
+func (s *NotificationService) WithWebhookSecretEncryptor(encryptor Encryptor) *NotificationService {
+	s.secretEncryptor = encryptor
+	return s
+}
+
+// WithChannel delivers a channel through provider, replacing the built-in one
+func (s *NotificationService) WithChannel(channel entity.NotificationChannel, provider NotificationChannelProvider) *NotificationService {
+	s.channels[channel] = provider
+	return s
+}
+
+// WithOutbox queues critical alerts and daily summaries in the notification
+// outbox, to be delivered with retries, instead of sending them directly
+func (s *NotificationService) WithOutbox(repo repository.NotificationRepository) *NotificationService {
+	s.outbox = repo
+	return s
+}
+
 // RegisterDevice registers a device token for push notifications
 func (s *NotificationService) RegisterDevice(ctx context.Context, userID uuid.UUID, deviceToken string, platform entity.Platform) error {
 	if !platform.IsValid() {
@@ -161,29 +280,7 @@ func (s *NotificationService) SendCriticalAlert(
 	title := fmt.Sprintf("🚨 Risk Alert: %s", appName)
 	body := fmt.Sprintf("%s changed from %s to %s", storeDomain, oldState, newState)
 
-	var lastErr error
-
-	// Send to Slack if configured
-	if s.slackNotifier != nil && prefs.SlackWebhookURL != "" {
-		if err := s.slackNotifier.SendSlack(ctx, prefs.SlackWebhookURL, title, body, SlackColorDanger); err != nil {
-			lastErr = err
-		}
-	}
-
-	// Get user's device tokens
-	tokens, err := s.deviceTokenRepo.FindByUserID(ctx, userID)
-	if err != nil {
-		return fmt.Errorf("failed to get device tokens: %w", err)
-	}
-
-	// Send to all devices
-	for _, token := range tokens {
-		if err := s.pushProvider.SendPush(ctx, token.DeviceToken, token.Platform, title, body); err != nil {
-			lastErr = err
-		}
-	}
-
-	return lastErr
+	return s.notify(ctx, prefs, entity.NotificationKindRiskChange, entity.AlertSeverityCritical, title, body)
 }
 
 // SendDailySummary sends a daily summary notification
@@ -211,31 +308,215 @@ func (s *NotificationService) SendDailySummary(
 	body := fmt.Sprintf("MRR: $%.2f | At Risk: $%.2f | Renewal Rate: %.1f%%",
 		mrrDollars, atRiskDollars, snapshot.RenewalSuccessRate*100)
 
-	var lastErr error
+	return s.notify(ctx, prefs, entity.NotificationKindDailySummary, entity.AlertSeverityInfo, title, body)
+}
 
-	// Send to Slack if configured
-	if s.slackNotifier != nil && prefs.SlackWebhookURL != "" {
-		if err := s.slackNotifier.SendSlack(ctx, prefs.SlackWebhookURL, title, body, SlackColorInfo); err != nil {
-			lastErr = err
+// SendWeeklyDigest sends a week-over-week digest. previous is the snapshot from
+// a week before current and may be nil, in which case no changes are shown.
+func (s *NotificationService) SendWeeklyDigest(
+	ctx context.Context,
+	userID uuid.UUID,
+	appName string,
+	previous *entity.DailyMetricsSnapshot,
+	current *entity.DailyMetricsSnapshot,
+) error {
+	// Check user preferences
+	prefs, err := s.prefsRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		// No preferences, use defaults (weekly digest enabled)
+		prefs = entity.NewNotificationPreferences(userID)
+	}
+
+	if !prefs.ShouldSendWeeklyDigest() {
+		return nil // User has disabled weekly digests
+	}
+
+	// Build notification content
+	title := fmt.Sprintf("📈 Weekly Digest: %s", appName)
+	body := formatSnapshotChange(previous, current)
+
+	return s.notify(ctx, prefs, entity.NotificationKindWeeklyDigest, entity.AlertSeverityInfo, title, body)
+}
+
+// formatSnapshotChange renders a snapshot's metrics with their change from previous, if not nil
+func formatSnapshotChange(previous, current *entity.DailyMetricsSnapshot) string {
+	mrr := fmt.Sprintf("MRR: $%.2f", float64(current.ActiveMRRCents)/100)
+	atRisk := fmt.Sprintf("At Risk: $%.2f", float64(current.RevenueAtRiskCents)/100)
+	renewal := fmt.Sprintf("Renewal Rate: %.1f%%", current.RenewalSuccessRate*100)
+	churned := fmt.Sprintf("Churned: %d", current.ChurnedCount)
+
+	if previous != nil {
+		if previous.ActiveMRRCents > 0 {
+			change := float64(current.ActiveMRRCents-previous.ActiveMRRCents) / float64(previous.ActiveMRRCents) * 100
+			mrr += fmt.Sprintf(" (%+.1f%%)", change)
 		}
+		atRisk += fmt.Sprintf(" (%+.2f)", float64(current.RevenueAtRiskCents-previous.RevenueAtRiskCents)/100)
+		renewal += fmt.Sprintf(" (%+.1f pts)", (current.RenewalSuccessRate-previous.RenewalSuccessRate)*100)
+		churned += fmt.Sprintf(" (%+d)", current.ChurnedCount-previous.ChurnedCount)
 	}
 
-	// Get user's device tokens
-	tokens, err := s.deviceTokenRepo.FindByUserID(ctx, userID)
-	if err != nil {
-		return fmt.Errorf("failed to get device tokens: %w", err)
+	return strings.Join([]string{mrr, atRisk, renewal, churned}, " | ")
+}
+
+// notify sends a notification on every channel the user has a destination
+// on, or queues it in the outbox if there is one
+func (s *NotificationService) notify(
+	ctx context.Context,
+	prefs *entity.NotificationPreferences,
+	kind entity.NotificationKind,
+	severity entity.AlertSeverity,
+	title string,
+	body string,
+) error {
+	notification := s.NewNotification(ctx, prefs.UserID, kind, severity, title, body)
+
+	if s.outbox != nil {
+		if err := s.outbox.Create(ctx, notification); err != nil {
+			return fmt.Errorf("failed to queue notification: %w", err)
+		}
+		return nil
 	}
 
-	// Send to all devices
-	for _, token := range tokens {
-		if err := s.pushProvider.SendPush(ctx, token.DeviceToken, token.Platform, title, body); err != nil {
+	// Send directly; a failing channel does not stop the others
+	var lastErr error
+	for _, channel := range notification.Channels {
+		if _, err := s.Deliver(ctx, notification, channel); err != nil {
 			lastErr = err
 		}
 	}
-
 	return lastErr
 }
 
+// NewNotification builds a notification for every channel the user has a destination on
+func (s *NotificationService) NewNotification(
+	ctx context.Context,
+	userID uuid.UUID,
+	kind entity.NotificationKind,
+	severity entity.AlertSeverity,
+	title string,
+	body string,
+) *entity.Notification {
+	return entity.NewNotification(userID, kind, severity, title, body, s.channelsFor(ctx, userID), s.now())
+}
+
+// channelsFor returns the configured channels the user has a destination on.
+// Lookup errors count as a destination so the channel is tried and retried.
+func (s *NotificationService) channelsFor(ctx context.Context, userID uuid.UUID) []entity.NotificationChannel {
+	var channels []entity.NotificationChannel
+	for _, channel := range notificationChannels {
+		provider := s.channelProvider(channel)
+		if provider == nil {
+			continue
+		}
+		if ok, err := provider.HasDestination(ctx, userID); ok || err != nil {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// Deliver sends a notification over one channel. Returns false without an
+// error if the user has nothing to send to on that channel (no registered
+// devices, no webhook, no verified email, or the channel is not configured).
+func (s *NotificationService) Deliver(ctx context.Context, notification *entity.Notification, channel entity.NotificationChannel) (bool, error) {
+	if !channel.IsValid() {
+		return false, fmt.Errorf("unsupported notification channel %q", channel)
+	}
+	provider := s.channelProvider(channel)
+	if provider == nil {
+		return false, nil
+	}
+	return provider.Deliver(ctx, notification)
+}
+
+// channelProvider returns the provider for a channel, or nil if the channel is not configured
+func (s *NotificationService) channelProvider(channel entity.NotificationChannel) NotificationChannelProvider {
+	if provider, ok := s.channels[channel]; ok {
+		return provider
+	}
+
+	switch channel {
+	case entity.NotificationChannelPush:
+		if s.pushProvider != nil {
+			return &pushChannel{deviceTokenRepo: s.deviceTokenRepo, provider: s.pushProvider}
+		}
+
+	case entity.NotificationChannelEmail:
+		if s.emailSender != nil {
+			return &emailChannel{emailRepo: s.emailRepo, sender: s.emailSender, publicURL: s.publicURL, unsubscribeKey: s.unsubscribeKey}
+		}
+
+	case entity.NotificationChannelSlack, entity.NotificationChannelTeams,
+		entity.NotificationChannelDiscord, entity.NotificationChannelWebhook:
+		if c := s.webhookChannel(channel); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// webhookChannel returns the provider for a webhook channel, or nil if its notifier is not configured
+func (s *NotificationService) webhookChannel(channel entity.NotificationChannel) *webhookChannel {
+	c := &webhookChannel{channel: channel, webhookRepo: s.webhookRepo}
+
+	switch channel {
+	case entity.NotificationChannelSlack:
+		if s.slackNotifier == nil {
+			return nil
+		}
+		c.prefsRepo = s.prefsRepo
+		c.send = func(ctx context.Context, webhook *entity.NotificationWebhook, n *entity.Notification) error {
+			return s.slackNotifier.SendSlack(ctx, webhook.URL, n.Title, n.Body, slackColorForSeverity(n.Severity))
+		}
+
+	case entity.NotificationChannelTeams:
+		if s.teamsNotifier == nil || s.webhookRepo == nil {
+			return nil
+		}
+		c.send = func(ctx context.Context, webhook *entity.NotificationWebhook, n *entity.Notification) error {
+			return s.teamsNotifier.SendTeams(ctx, webhook.URL, n.Title, n.Body, slackColorForSeverity(n.Severity))
+		}
+
+	case entity.NotificationChannelDiscord:
+		if s.discordNotifier == nil || s.webhookRepo == nil {
+			return nil
+		}
+		c.send = func(ctx context.Context, webhook *entity.NotificationWebhook, n *entity.Notification) error {
+			return s.discordNotifier.SendDiscord(ctx, webhook.URL, n.Title, n.Body, slackColorForSeverity(n.Severity))
+		}
+
+	case entity.NotificationChannelWebhook:
+		if s.webhookNotifier == nil || s.webhookRepo == nil || s.secretEncryptor == nil {
+			return nil
+		}
+		c.send = func(ctx context.Context, webhook *entity.NotificationWebhook, n *entity.Notification) error {
+			payload, err := marshalWebhookNotification(n)
+			if err != nil {
+				return err
+			}
+			secret, err := s.webhookSecret(webhook)
+			if err != nil {
+				return err
+			}
+			return s.webhookNotifier.SendWebhook(ctx, webhook.URL, secret, payload)
+		}
+
+	default:
+		return nil
+	}
+	return c
+}
+
+func slackColorForSeverity(severity entity.AlertSeverity) string {
+	switch severity {
+	case entity.AlertSeverityCritical:
+		return SlackColorDanger
+	case entity.AlertSeverityWarning:
+		return SlackColorWarning
+	}
+	return SlackColorInfo
+}
+
 // GetPreferences retrieves notification preferences for a user
 func (s *NotificationService) GetPreferences(ctx context.Context, userID uuid.UUID) (*entity.NotificationPreferences, error) {
 	prefs, err := s.prefsRepo.FindByUserID(ctx, userID)
@@ -250,3 +531,273 @@ func (s *NotificationService) GetPreferences(ctx context.Context, userID uuid.UU
 func (s *NotificationService) UpdatePreferences(ctx context.Context, prefs *entity.NotificationPreferences) error {
 	return s.prefsRepo.Upsert(ctx, prefs)
 }
+
+// UpdateNotificationPreferencesRequest changes notification preferences; nil
+// fields are unchanged. Times are local to the timezone and only their hour
+// and minute are used. Quiet hours are replaced when SetQuietHours is true,
+// and cleared if both ends are nil.
+type UpdateNotificationPreferencesRequest struct {
+	CriticalEnabled     *bool
+	DailySummaryEnabled *bool
+	DailySummaryTime    *time.Time
+	WeeklyDigestEnabled *bool
+	Timezone            *string
+	SetQuietHours       bool
+	QuietHoursStart     *time.Time
+	QuietHoursEnd       *time.Time
+	SkipWeekends        *bool
+	SlackWebhookURL     *string // Empty removes it
+}
+
+// UpdateNotificationPreferences applies a partial update to the user's
+// preferences. The daily summary is rescheduled from the new settings.
+func (s *NotificationService) UpdateNotificationPreferences(ctx context.Context, userID uuid.UUID, req UpdateNotificationPreferencesRequest) (*entity.NotificationPreferences, error) {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Timezone != nil {
+		if err := prefs.SetTimezone(strings.TrimSpace(*req.Timezone)); err != nil {
+			return nil, err
+		}
+	}
+	if req.SetQuietHours {
+		if err := prefs.SetQuietHours(req.QuietHoursStart, req.QuietHoursEnd); err != nil {
+			return nil, err
+		}
+	}
+	if req.SlackWebhookURL != nil {
+		target := strings.TrimSpace(*req.SlackWebhookURL)
+		if target != "" {
+			if err := s.validateWebhookURL(target); err != nil {
+				return nil, err
+			}
+		}
+		prefs.SlackWebhookURL = target
+	}
+	if req.CriticalEnabled != nil {
+		prefs.CriticalEnabled = *req.CriticalEnabled
+	}
+	if req.DailySummaryEnabled != nil {
+		prefs.DailySummaryEnabled = *req.DailySummaryEnabled
+	}
+	if req.DailySummaryTime != nil {
+		prefs.DailySummaryTime = time.Date(0, 1, 1, req.DailySummaryTime.Hour(), req.DailySummaryTime.Minute(), 0, 0, time.UTC)
+	}
+	if req.WeeklyDigestEnabled != nil {
+		prefs.WeeklyDigestEnabled = *req.WeeklyDigestEnabled
+	}
+	if req.SkipWeekends != nil {
+		prefs.SkipWeekends = *req.SkipWeekends
+	}
+	prefs.NextDailySummaryAt = nil
+	prefs.UpdatedAt = s.now()
+
+	if err := s.prefsRepo.Upsert(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// UpdateNotificationWebhookRequest changes a webhook; nil fields are unchanged
+type UpdateNotificationWebhookRequest struct {
+	Name    *string
+	URL     *string
+	Enabled *bool
+}
+
+// ListWebhooks returns the user's notification webhooks
+func (s *NotificationService) ListWebhooks(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationWebhook, error) {
+	if s.webhookRepo == nil {
+		return []*entity.NotificationWebhook{}, nil
+	}
+	webhooks, err := s.webhookRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if webhooks == nil {
+		webhooks = []*entity.NotificationWebhook{}
+	}
+	return webhooks, nil
+}
+
+// CreateWebhook adds a webhook on a channel this server can deliver. Generic
+// webhooks are created with a signing secret.
+func (s *NotificationService) CreateWebhook(ctx context.Context, userID uuid.UUID, channel entity.NotificationChannel, name, webhookURL string) (*entity.NotificationWebhook, error) {
+	webhook, err := entity.NewNotificationWebhook(userID, channel, name, webhookURL, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if s.webhookChannel(channel) == nil || s.webhookRepo == nil {
+		return nil, ErrNotificationChannelUnavailable
+	}
+	if err := s.validateWebhookURL(webhook.URL); err != nil {
+		return nil, err
+	}
+	if err := s.encryptWebhookSecret(webhook); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.webhookRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	count := 0
+	for _, w := range existing {
+		if w.Channel == channel {
+			count++
+		}
+	}
+	if count >= MaxNotificationWebhooksPerChannel {
+		return nil, ErrTooManyNotificationWebhooks
+	}
+
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to save notification webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// UpdateWebhook renames, re-targets, enables or disables one of the user's webhooks
+func (s *NotificationService) UpdateWebhook(ctx context.Context, userID, id uuid.UUID, req UpdateNotificationWebhookRequest) (*entity.NotificationWebhook, error) {
+	webhook, err := s.findWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, entity.ErrInvalidNotificationWebhookName
+		}
+		webhook.Name = name
+	}
+	if req.URL != nil {
+		target := strings.TrimSpace(*req.URL)
+		if err := s.validateWebhookURL(target); err != nil {
+			return nil, err
+		}
+		webhook.URL = target
+	}
+	if req.Enabled != nil {
+		webhook.Enabled = *req.Enabled
+	}
+	webhook.UpdatedAt = s.now()
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to save notification webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// RotateWebhookSecret replaces the signing secret of a generic webhook
+func (s *NotificationService) RotateWebhookSecret(ctx context.Context, userID, id uuid.UUID) (*entity.NotificationWebhook, error) {
+	webhook, err := s.findWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Channel != entity.NotificationChannelWebhook {
+		return nil, ErrNotificationWebhookUnsigned
+	}
+
+	if err := webhook.RotateSecret(s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.encryptWebhookSecret(webhook); err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to save notification webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook removes one of the user's webhooks
+func (s *NotificationService) DeleteWebhook(ctx context.Context, userID, id uuid.UUID) error {
+	if s.webhookRepo == nil {
+		return ErrNotificationWebhookNotFound
+	}
+	if err := s.webhookRepo.Delete(ctx, userID, id); err != nil {
+		return ErrNotificationWebhookNotFound
+	}
+	return nil
+}
+
+// TestWebhook sends a test notification to one of the user's webhooks, even if
+// it is disabled, and records the outcome on the webhook. A failed send is
+// reported in LastTestError, as a generic message, rather than as an error.
+func (s *NotificationService) TestWebhook(ctx context.Context, userID, id uuid.UUID) (*entity.NotificationWebhook, error) {
+	webhook, err := s.findWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	channel := s.webhookChannel(webhook.Channel)
+	if channel == nil {
+		return nil, ErrNotificationChannelUnavailable
+	}
+
+	now := s.now()
+	notification := entity.NewNotification(userID, entity.NotificationKindTest, entity.AlertSeverityInfo,
+		"✅ LedgerGuard test notification",
+		fmt.Sprintf("%s is set up to receive LedgerGuard notifications.", webhook.Name),
+		[]entity.NotificationChannel{webhook.Channel}, now)
+	testErr := channel.send(ctx, webhook, notification)
+	if testErr != nil {
+		log.Printf("NotificationService: test of webhook %s failed: %v", webhook.ID, testErr)
+		testErr = ErrNotificationTestFailed
+	}
+	webhook.RecordTest(now, testErr)
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to save notification webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+func (s *NotificationService) findWebhook(ctx context.Context, userID, id uuid.UUID) (*entity.NotificationWebhook, error) {
+	if s.webhookRepo == nil {
+		return nil, ErrNotificationWebhookNotFound
+	}
+	webhook, err := s.webhookRepo.FindByID(ctx, id)
+	if err != nil || webhook.UserID != userID {
+		return nil, ErrNotificationWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// encryptWebhookSecret sets the encrypted secret of a generic webhook from its plaintext one
+func (s *NotificationService) encryptWebhookSecret(webhook *entity.NotificationWebhook) error {
+	if webhook.Secret == "" {
+		return nil
+	}
+	if s.secretEncryptor == nil {
+		return ErrNotificationChannelUnavailable
+	}
+	encrypted, err := s.secretEncryptor.Encrypt([]byte(webhook.Secret))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+	webhook.EncryptedSecret = encrypted
+	return nil
+}
+
+// webhookSecret decrypts a generic webhook's signing secret; other channels have none
+func (s *NotificationService) webhookSecret(webhook *entity.NotificationWebhook) (string, error) {
+	if len(webhook.EncryptedSecret) == 0 {
+		return "", nil
+	}
+	secret, err := s.secretEncryptor.Decrypt(webhook.EncryptedSecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+	return string(secret), nil
+}
+
+// validateWebhookURL requires https to a public host; see netguard.Guard.ValidateURL
+func (s *NotificationService) validateWebhookURL(raw string) error {
+	if err := s.urlGuard.ValidateURL(raw); err != nil {
+		return ErrInvalidNotificationWebhookURL
+	}
+	return nil
+}
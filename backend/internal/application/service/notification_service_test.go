@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -95,13 +96,15 @@ func (m *mockDeviceTokenRepository) DeleteByToken(ctx context.Context, deviceTok
 
 type mockNotificationPreferencesRepository struct {
 	prefs     map[uuid.UUID]*entity.NotificationPreferences
+	locked    map[uuid.UUID]time.Time
 	createErr error
 	updateErr error
 }
 
 func newMockNotificationPreferencesRepository() *mockNotificationPreferencesRepository {
 	return &mockNotificationPreferencesRepository{
-		prefs: make(map[uuid.UUID]*entity.NotificationPreferences),
+		prefs:  make(map[uuid.UUID]*entity.NotificationPreferences),
+		locked: make(map[uuid.UUID]time.Time),
 	}
 }
 
@@ -137,6 +140,35 @@ func (m *mockNotificationPreferencesRepository) Upsert(ctx context.Context, pref
 	return nil
 }
 
+func (m *mockNotificationPreferencesRepository) ClaimDueDailySummaries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.NotificationPreferences, error) {
+	var due []*entity.NotificationPreferences
+	for userID, prefs := range m.prefs {
+		if !prefs.DailySummaryEnabled || m.locked[userID].After(now) {
+			continue
+		}
+		if prefs.NextDailySummaryAt != nil && prefs.NextDailySummaryAt.After(now) {
+			continue
+		}
+		if len(due) == limit {
+			break
+		}
+		m.locked[userID] = now.Add(lease)
+		claimed := *prefs
+		due = append(due, &claimed)
+	}
+	return due, nil
+}
+
+func (m *mockNotificationPreferencesRepository) SetNextDailySummaryAt(ctx context.Context, userID uuid.UUID, next time.Time) error {
+	prefs, ok := m.prefs[userID]
+	if !ok {
+		return errors.New("not found")
+	}
+	prefs.NextDailySummaryAt = &next
+	delete(m.locked, userID)
+	return nil
+}
+
 type mockPushNotificationProvider struct {
 	sentNotifications []sentNotification
 	sendErr           error
@@ -583,6 +615,64 @@ func TestNotificationService_UpdatePreferences(t *testing.T) {
 	})
 }
 
+func TestNotificationService_UpdateNotificationPreferences(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	prefsRepo := newMockNotificationPreferencesRepository()
+	svc := NewNotificationService(newMockDeviceTokenRepository(), prefsRepo, nil)
+
+	scheduled := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	existing := entity.NewNotificationPreferences(userID)
+	existing.NextDailySummaryAt = &scheduled
+	_ = prefsRepo.Upsert(ctx, existing)
+
+	timezone := "Asia/Kolkata"
+	summaryTime := time.Date(0, 1, 1, 7, 30, 0, 0, time.UTC)
+	skip := true
+	prefs, err := svc.UpdateNotificationPreferences(ctx, userID, UpdateNotificationPreferencesRequest{
+		Timezone:         &timezone,
+		DailySummaryTime: &summaryTime,
+		SetQuietHours:    true,
+		QuietHoursStart:  clock(22, 0),
+		QuietHoursEnd:    clock(7, 0),
+		SkipWeekends:     &skip,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prefs.Timezone != timezone || prefs.DailySummaryTime.Hour() != 7 || prefs.DailySummaryTime.Minute() != 30 ||
+		prefs.QuietHoursStart == nil || !prefs.SkipWeekends || !prefs.CriticalEnabled {
+		t.Errorf("unexpected preferences %+v", prefs)
+	}
+	if prefs.NextDailySummaryAt != nil {
+		t.Error("expected the daily summary to be rescheduled")
+	}
+
+	marsTimezone, plainHTTP := "Mars/Olympus", "http://hooks.slack.com/x"
+	invalid := []struct {
+		name    string
+		req     UpdateNotificationPreferencesRequest
+		wantErr error
+	}{
+		{"unknown timezone", UpdateNotificationPreferencesRequest{Timezone: &marsTimezone}, entity.ErrInvalidTimezone},
+		{"half quiet hours", UpdateNotificationPreferencesRequest{SetQuietHours: true, QuietHoursStart: clock(22, 0)}, entity.ErrInvalidQuietHours},
+		{"plain http slack", UpdateNotificationPreferencesRequest{SlackWebhookURL: &plainHTTP}, ErrInvalidNotificationWebhookURL},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.UpdateNotificationPreferences(ctx, userID, tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// Clearing quiet hours
+	prefs, err = svc.UpdateNotificationPreferences(ctx, userID, UpdateNotificationPreferencesRequest{SetQuietHours: true})
+	if err != nil || prefs.QuietHoursStart != nil || prefs.QuietHoursEnd != nil {
+		t.Errorf("expected quiet hours to be cleared, got %+v, %v", prefs, err)
+	}
+}
+
 func TestNotificationService_SlackIntegration(t *testing.T) {
 	ctx := context.Background()
 	userID := uuid.New()
@@ -745,3 +835,144 @@ func TestNotificationService_SlackIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestNotificationService_Deliver(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	tokenRepo := newMockDeviceTokenRepository()
+	prefsRepo := newMockNotificationPreferencesRepository()
+	pushProvider := newMockPushNotificationProvider()
+	slackNotifier := newMockSlackNotifier()
+	svc := NewNotificationService(tokenRepo, prefsRepo, pushProvider).WithSlackNotifier(slackNotifier)
+
+	notification := entity.NewNotification(userID, entity.NotificationKindAlert, entity.AlertSeverityCritical,
+		"MRR drop", "Active MRR dropped 7.5% day over day",
+		[]entity.NotificationChannel{entity.NotificationChannelPush, entity.NotificationChannelSlack}, time.Now())
+
+	t.Run("skips channels with nothing to send to", func(t *testing.T) {
+		for _, channel := range notification.Channels {
+			sent, err := svc.Deliver(ctx, notification, channel)
+			if err != nil || sent {
+				t.Errorf("expected %s to be skipped, got sent=%v err=%v", channel, sent, err)
+			}
+		}
+	})
+
+	_ = svc.RegisterDevice(ctx, userID, "token-1", entity.PlatformIOS)
+	prefs := entity.NewNotificationPreferences(userID)
+	prefs.SlackWebhookURL = "https://hooks.slack.com/services/xxx/yyy/zzz"
+	_ = prefsRepo.Upsert(ctx, prefs)
+
+	t.Run("slack", func(t *testing.T) {
+		sent, err := svc.Deliver(ctx, notification, entity.NotificationChannelSlack)
+		if err != nil || !sent {
+			t.Fatalf("expected slack delivery, got sent=%v err=%v", sent, err)
+		}
+		if msg := slackNotifier.sentMessages[0]; msg.color != SlackColorDanger || msg.body != notification.Body {
+			t.Errorf("unexpected slack message: %+v", msg)
+		}
+	})
+
+	t.Run("push", func(t *testing.T) {
+		sent, err := svc.Deliver(ctx, notification, entity.NotificationChannelPush)
+		if err != nil || !sent {
+			t.Fatalf("expected push delivery, got sent=%v err=%v", sent, err)
+		}
+		if len(pushProvider.sentNotifications) != 1 || pushProvider.sentNotifications[0].deviceToken != "token-1" {
+			t.Errorf("expected a push to token-1, got %+v", pushProvider.sentNotifications)
+		}
+	})
+
+	t.Run("slack error is returned for retry", func(t *testing.T) {
+		slackNotifier.sendErr = errors.New("slack unavailable")
+		defer func() { slackNotifier.sendErr = nil }()
+
+		sent, err := svc.Deliver(ctx, notification, entity.NotificationChannelSlack)
+		if err == nil || sent {
+			t.Errorf("expected error, got sent=%v err=%v", sent, err)
+		}
+	})
+}
+
+func TestNotificationService_SendCriticalAlert_WithOutbox(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	prefsRepo := newMockNotificationPreferencesRepository()
+	pushProvider := newMockPushNotificationProvider()
+	outbox := newMockNotificationRepo()
+	svc := NewNotificationService(newMockDeviceTokenRepository(), prefsRepo, pushProvider).
+		WithSlackNotifier(newMockSlackNotifier()).
+		WithOutbox(outbox)
+
+	prefs := entity.NewNotificationPreferences(userID)
+	prefs.SlackWebhookURL = "https://hooks.slack.com/services/xxx/yyy/zzz"
+	_ = prefsRepo.Upsert(ctx, prefs)
+
+	if err := svc.SendCriticalAlert(ctx, userID, "MyApp", "store.myshopify.com",
+		valueobject.RiskStateSafe, valueobject.RiskStateOneCycleMissed); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Queued rather than sent
+	if len(pushProvider.sentNotifications) != 0 {
+		t.Errorf("expected nothing sent directly, got %d pushes", len(pushProvider.sentNotifications))
+	}
+	if len(outbox.notifications) != 1 {
+		t.Fatalf("expected 1 queued notification, got %d", len(outbox.notifications))
+	}
+	n := outbox.notifications[0]
+	if n.Kind != entity.NotificationKindRiskChange || n.Status != entity.NotificationStatusPending || len(n.PendingChannels) != 2 {
+		t.Errorf("unexpected queued notification %+v", n)
+	}
+}
+
+func TestNotificationService_Deliver_Email(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now().UTC()
+
+	emailRepo := &mockNotificationEmailRepo{}
+	sender := &mockEmailSender{}
+	outbox := newMockNotificationRepo()
+	svc := NewNotificationService(newMockDeviceTokenRepository(), newMockNotificationPreferencesRepository(), nil).
+		WithEmail(emailRepo, sender, "https://api.example.com", testUnsubscribeKey).
+		WithOutbox(outbox)
+
+	verified, _ := entity.NewNotificationEmail(userID, "owner@example.com", testUnsubscribeKey, now)
+	verified.Verify(now)
+	unverified, _ := entity.NewNotificationEmail(userID, "pending@example.com", testUnsubscribeKey, now)
+	unsubscribed, _ := entity.NewNotificationEmail(userID, "gone@example.com", testUnsubscribeKey, now)
+	unsubscribed.Verify(now)
+	unsubscribed.Unsubscribe(now)
+	emailRepo.emails = []*entity.NotificationEmail{verified, unverified, unsubscribed}
+
+	if err := svc.SendCriticalAlert(ctx, userID, "MyApp", "store.myshopify.com",
+		valueobject.RiskStateSafe, valueobject.RiskStateOneCycleMissed); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	n := outbox.notifications[0]
+	// Push is not configured, so only email is requested
+	if len(n.Channels) != 1 || n.Channels[0] != entity.NotificationChannelEmail {
+		t.Fatalf("expected the EMAIL channel, got %v", n.Channels)
+	}
+
+	sent, err := svc.Deliver(ctx, n, entity.NotificationChannelEmail)
+	if err != nil || !sent {
+		t.Fatalf("expected email delivery, got sent=%v err=%v", sent, err)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].to != "owner@example.com" {
+		t.Fatalf("expected one email to the verified address, got %+v", sender.sent)
+	}
+	want := "https://api.example.com/api/v1/notification-emails/unsubscribe?token=" + entity.UnsubscribeToken(testUnsubscribeKey, verified.ID)
+	if sender.sent[0].unsubscribeURL != want || !strings.Contains(sender.sent[0].html, want) {
+		t.Errorf("expected the address's unsubscribe link, got %q", sender.sent[0].unsubscribeURL)
+	}
+
+	// Once unsubscribed there is nothing to send to
+	verified.Unsubscribe(now)
+	if sent, err := svc.Deliver(ctx, n, entity.NotificationChannelEmail); err != nil || sent {
+		t.Errorf("expected email to be skipped, got sent=%v err=%v", sent, err)
+	}
+}
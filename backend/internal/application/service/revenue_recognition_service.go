@@ -0,0 +1,269 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	domainservice "github.com/sachin-sivadasan/ledgerguard/internal/domain/service"
+)
+
+// recognitionLookbackMonths is how far before the report start charges are loaded,
+// so annual charges billed before the period still contribute their deferred balance
+const recognitionLookbackMonths = 13
+
+// ErrInvalidRecognitionView is returned for unsupported revenue recognition export views
+var ErrInvalidRecognitionView = errors.New("view must be summary or detail")
+
+// RecognitionExportView selects the level of detail in a revenue recognition export
+type RecognitionExportView string
+
+const (
+	RecognitionViewSummary RecognitionExportView = "summary" // One row per month and currency
+	RecognitionViewDetail  RecognitionExportView = "detail"  // One row per charge and month
+)
+
+// RevenueRecognitionReport is the accrual-basis revenue report for a range of months
+type RevenueRecognitionReport struct {
+	AppID     uuid.UUID
+	FromMonth time.Time
+	ToMonth   time.Time
+	Months    []*entity.MonthlyRevenueRecognition
+	Schedules []*entity.RecognitionSchedule // Schedules with activity in the range
+}
+
+// RevenueRecognitionService produces recognized / deferred revenue reports
+type RevenueRecognitionService struct {
+	transactionRepo  repository.TransactionRepository
+	subscriptionRepo repository.SubscriptionRepository
+	eventRepo        repository.SubscriptionEventRepository
+	engine           *domainservice.RevenueRecognitionEngine
+}
+
+// NewRevenueRecognitionService creates a new revenue recognition service
+func NewRevenueRecognitionService(
+	transactionRepo repository.TransactionRepository,
+	subscriptionRepo repository.SubscriptionRepository,
+	eventRepo repository.SubscriptionEventRepository,
+) *RevenueRecognitionService {
+	return &RevenueRecognitionService{
+		transactionRepo:  transactionRepo,
+		subscriptionRepo: subscriptionRepo,
+		eventRepo:        eventRepo,
+		engine:           domainservice.NewRevenueRecognitionEngine(),
+	}
+}
+
+// GetReport builds the monthly recognition report for [fromMonth, toMonth] (both inclusive)
+func (s *RevenueRecognitionService) GetReport(ctx context.Context, appID uuid.UUID, fromMonth, toMonth time.Time) (*RevenueRecognitionReport, error) {
+	fromMonth = truncateToMonth(fromMonth)
+	toMonth = truncateToMonth(toMonth)
+	if toMonth.Before(fromMonth) {
+		return nil, ErrInvalidDateRange
+	}
+	rangeEnd := toMonth.AddDate(0, 1, 0)
+
+	transactions, err := s.transactionRepo.FindByAppID(ctx, appID, fromMonth.AddDate(0, -recognitionLookbackMonths, 0), rangeEnd.Add(-time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	cancellations, err := s.cancellationsByDomain(ctx, appID, fromMonth.AddDate(0, -recognitionLookbackMonths, 0), rangeEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := s.engine.BuildSchedules(transactions, cancellations)
+
+	report := &RevenueRecognitionReport{
+		AppID:     appID,
+		FromMonth: fromMonth,
+		ToMonth:   toMonth,
+		Months:    s.engine.MonthlyBalances(schedules, fromMonth, toMonth),
+	}
+
+	// Keep schedules billed in the range or still recognizing revenue in it
+	for _, schedule := range schedules {
+		inRange := !schedule.BilledDate.Before(fromMonth) && schedule.BilledDate.Before(rangeEnd)
+		if inRange || len(s.engine.Allocate(schedule, fromMonth, toMonth)) > 0 {
+			report.Schedules = append(report.Schedules, schedule)
+		}
+	}
+
+	return report, nil
+}
+
+// Allocate returns the revenue a schedule recognizes in each month of [fromMonth, toMonth]
+func (s *RevenueRecognitionService) Allocate(schedule *entity.RecognitionSchedule, fromMonth, toMonth time.Time) []entity.RecognitionAllocation {
+	return s.engine.Allocate(schedule, fromMonth, toMonth)
+}
+
+// Export renders the recognition report as CSV or JSON for accrual-basis bookkeeping
+func (s *RevenueRecognitionService) Export(
+	ctx context.Context,
+	appID uuid.UUID,
+	fromMonth, toMonth time.Time,
+	format ExportFormat,
+	view RecognitionExportView,
+) (*ExportResult, error) {
+	if format != ExportFormatCSV && format != ExportFormatJSON {
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+	if view != RecognitionViewSummary && view != RecognitionViewDetail {
+		return nil, ErrInvalidRecognitionView
+	}
+
+	report, err := s.GetReport(ctx, appID, fromMonth, toMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	var header []string
+	if view == RecognitionViewSummary {
+		header = []string{"month", "currency", "billed", "refunded", "recognized", "deferred_opening", "deferred_closing"}
+		for _, m := range report.Months {
+			rows = append(rows, []string{
+				m.Month.Format("2006-01"),
+				m.Currency,
+				formatSignedCents(m.BilledCents),
+				formatSignedCents(m.RefundedCents),
+				formatSignedCents(m.RecognizedCents),
+				formatSignedCents(m.DeferredOpeningCents),
+				formatSignedCents(m.DeferredClosingCents),
+			})
+		}
+	} else {
+		header = []string{"month", "transaction_id", "shop_domain", "shop_name", "charge_type", "currency", "billed_date", "service_start", "service_end", "billed", "refunded", "recognized"}
+		for _, schedule := range report.Schedules {
+			for _, a := range s.engine.Allocate(schedule, report.FromMonth, report.ToMonth) {
+				rows = append(rows, []string{
+					a.Month.Format("2006-01"),
+					schedule.TransactionID.String(),
+					schedule.MyshopifyDomain,
+					schedule.ShopName,
+					string(schedule.ChargeType),
+					schedule.Currency,
+					schedule.BilledDate.Format("2006-01-02"),
+					schedule.ServiceStart.Format("2006-01-02"),
+					schedule.ServiceEnd.Format("2006-01-02"),
+					formatSignedCents(schedule.BilledCents),
+					formatSignedCents(schedule.RefundedCents()),
+					formatSignedCents(a.RecognizedCents),
+				})
+			}
+		}
+	}
+
+	var data []byte
+	var contentType string
+	filename := fmt.Sprintf("revenue_recognition_%s_%s_%s_to_%s",
+		view,
+		appID.String()[:8],
+		report.FromMonth.Format("2006-01"),
+		report.ToMonth.Format("2006-01"),
+	)
+
+	switch format {
+	case ExportFormatCSV:
+		data, err = recognitionRowsToCSV(header, rows)
+		contentType = "text/csv"
+		filename += ".csv"
+	case ExportFormatJSON:
+		data, err = recognitionRowsToJSON(header, rows)
+		contentType = "application/json"
+		filename += ".json"
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportResult{
+		Data:        data,
+		ContentType: contentType,
+		Filename:    filename,
+		RecordCount: len(rows),
+	}, nil
+}
+
+// cancellationsByDomain returns cancellation / uninstall dates keyed by shop domain
+func (s *RevenueRecognitionService) cancellationsByDomain(ctx context.Context, appID uuid.UUID, from, to time.Time) (map[string][]time.Time, error) {
+	if s.eventRepo == nil || s.subscriptionRepo == nil {
+		return nil, nil
+	}
+
+	events, err := s.eventRepo.FindByAppID(ctx, appID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch subscription events: %w", err)
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	subscriptions, err := s.subscriptionRepo.FindByAppID(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch subscriptions: %w", err)
+	}
+	domains := make(map[uuid.UUID]string, len(subscriptions))
+	for _, sub := range subscriptions {
+		domains[sub.ID] = sub.MyshopifyDomain
+	}
+
+	result := make(map[string][]time.Time)
+	for _, e := range events {
+		if e.ToStatus != "CANCELLED" && e.ToStatus != "UNINSTALLED" {
+			continue
+		}
+		if domain, ok := domains[e.SubscriptionID]; ok {
+			result[domain] = append(result[domain], e.OccurredAt)
+		}
+	}
+	return result, nil
+}
+
+func recognitionRowsToCSV(header []string, rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	if err := writer.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write CSV rows: %w", err)
+	}
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("CSV writer error: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func recognitionRowsToJSON(header []string, rows [][]string) ([]byte, error) {
+	records := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		record := make(map[string]string, len(header))
+		for j, col := range header {
+			record[col] = row[j]
+		}
+		records[i] = record
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return data, nil
+}
+
+// truncateToMonth truncates a time to the first day of its UTC month
+func truncateToMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
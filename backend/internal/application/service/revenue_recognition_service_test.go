@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+type mockRecognitionSubscriptionRepo struct {
+	subscriptions []*entity.Subscription
+}
+
+func (m *mockRecognitionSubscriptionRepo) Upsert(ctx context.Context, subscription *entity.Subscription) error {
+	return nil
+}
+
+func (m *mockRecognitionSubscriptionRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.Subscription, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockRecognitionSubscriptionRepo) FindByAppID(ctx context.Context, appID uuid.UUID) ([]*entity.Subscription, error) {
+	return m.subscriptions, nil
+}
+
+func (m *mockRecognitionSubscriptionRepo) FindByShopifyGID(ctx context.Context, shopifyGID string) (*entity.Subscription, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockRecognitionSubscriptionRepo) FindByAppIDAndDomain(ctx context.Context, appID uuid.UUID, myshopifyDomain string) (*entity.Subscription, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockRecognitionSubscriptionRepo) FindUpdatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*entity.Subscription, error) {
+	return nil, nil
+}
+
+func (m *mockRecognitionSubscriptionRepo) FindByRiskState(ctx context.Context, appID uuid.UUID, riskState valueobject.RiskState) ([]*entity.Subscription, error) {
+	return nil, nil
+}
+
+func (m *mockRecognitionSubscriptionRepo) DeleteByAppID(ctx context.Context, appID uuid.UUID) error {
+	return nil
+}
+
+func (m *mockRecognitionSubscriptionRepo) SoftDeleteByAppID(ctx context.Context, appID uuid.UUID) error {
+	return nil
+}
+
+func (m *mockRecognitionSubscriptionRepo) FindDeletedByAppID(ctx context.Context, appID uuid.UUID) ([]*entity.Subscription, error) {
+	return nil, nil
+}
+
+func (m *mockRecognitionSubscriptionRepo) RestoreByID(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *mockRecognitionSubscriptionRepo) FindWithFilters(ctx context.Context, appID uuid.UUID, filters repository.SubscriptionFilters) (*repository.SubscriptionPage, error) {
+	return &repository.SubscriptionPage{}, nil
+}
+
+func (m *mockRecognitionSubscriptionRepo) GetSummary(ctx context.Context, appID uuid.UUID) (*repository.SubscriptionSummary, error) {
+	return &repository.SubscriptionSummary{}, nil
+}
+
+func (m *mockRecognitionSubscriptionRepo) GetPriceStats(ctx context.Context, appID uuid.UUID) (*repository.PriceStats, error) {
+	return &repository.PriceStats{}, nil
+}
+
+type mockRecognitionEventRepo struct {
+	events []*entity.SubscriptionEvent
+}
+
+func (m *mockRecognitionEventRepo) Create(ctx context.Context, event *entity.SubscriptionEvent) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *mockRecognitionEventRepo) FindBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*entity.SubscriptionEvent, error) {
+	return nil, nil
+}
+
+func (m *mockRecognitionEventRepo) FindByAppID(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.SubscriptionEvent, error) {
+	var result []*entity.SubscriptionEvent
+	for _, e := range m.events {
+		if !e.OccurredAt.Before(from) && e.OccurredAt.Before(to) {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRecognitionEventRepo) FindChurnEvents(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.SubscriptionEvent, error) {
+	return nil, nil
+}
+
+func (m *mockRecognitionEventRepo) CountByEventType(ctx context.Context, appID uuid.UUID, from, to time.Time) (map[string]int, error) {
+	return nil, nil
+}
+
+func newRecognitionTestService(appID uuid.UUID) (*RevenueRecognitionService, *mockRecognitionSubscriptionRepo, *mockRecognitionEventRepo) {
+	txRepo := &mockTxRepo{
+		transactions: []*entity.Transaction{
+			{
+				ID:               uuid.New(),
+				AppID:            appID,
+				MyshopifyDomain:  "annual.myshopify.com",
+				ChargeType:       valueobject.ChargeTypeRecurring,
+				BillingInterval:  "ANNUAL",
+				GrossAmountCents: 36500,
+				NetAmountCents:   29200,
+				Currency:         "USD",
+				// Billed before the report range; must still be loaded
+				TransactionDate: time.Date(2025, 12, 1, 8, 0, 0, 0, time.UTC),
+			},
+			{
+				ID:               uuid.New(),
+				AppID:            appID,
+				MyshopifyDomain:  "usage.myshopify.com",
+				ChargeType:       valueobject.ChargeTypeUsage,
+				GrossAmountCents: 1000,
+				NetAmountCents:   800,
+				Currency:         "USD",
+				TransactionDate:  time.Date(2026, 2, 28, 22, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	subRepo := &mockRecognitionSubscriptionRepo{}
+	eventRepo := &mockRecognitionEventRepo{}
+	return NewRevenueRecognitionService(txRepo, subRepo, eventRepo), subRepo, eventRepo
+}
+
+func TestRevenueRecognitionService_GetReport(t *testing.T) {
+	appID := uuid.New()
+	svc, _, _ := newRecognitionTestService(appID)
+
+	report, err := svc.GetReport(context.Background(), appID,
+		time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Months) != 2 {
+		t.Fatalf("len(Months) = %d, want 2", len(report.Months))
+	}
+	if len(report.Schedules) != 2 {
+		t.Errorf("len(Schedules) = %d, want 2", len(report.Schedules))
+	}
+
+	jan := report.Months[0]
+	if !jan.Month.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("first month = %v, want 2026-01-01", jan.Month)
+	}
+	// 31 days of December recognized before January
+	if jan.BilledCents != 0 || jan.DeferredOpeningCents != 36500-3100 || jan.RecognizedCents != 3100 {
+		t.Errorf("January = %+v", jan)
+	}
+
+	feb := report.Months[1]
+	if feb.BilledCents != 1000 || feb.RecognizedCents != 2800+1000 {
+		t.Errorf("February billed/recognized = %d/%d, want 1000/3800", feb.BilledCents, feb.RecognizedCents)
+	}
+}
+
+func TestRevenueRecognitionService_GetReport_CancellationFromEvents(t *testing.T) {
+	appID := uuid.New()
+	svc, subRepo, eventRepo := newRecognitionTestService(appID)
+
+	sub := entity.NewSubscription(appID, "gid://shopify/AppSubscription/1", "annual.myshopify.com", "Annual", "Pro", 36500, "USD", valueobject.BillingIntervalAnnual)
+	subRepo.subscriptions = []*entity.Subscription{sub}
+	event := entity.NewSubscriptionEvent(sub.ID, "ACTIVE", "CANCELLED", valueobject.RiskStateSafe, valueobject.RiskStateChurned, "webhook", "cancelled")
+	event.OccurredAt = time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	eventRepo.events = []*entity.SubscriptionEvent{event}
+
+	report, err := svc.GetReport(context.Background(), appID,
+		time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	feb, mar := report.Months[0], report.Months[1]
+	if feb.DeferredClosingCents != 0 {
+		t.Errorf("February closing deferred = %d, want 0 after cancellation", feb.DeferredClosingCents)
+	}
+	if feb.RecognizedCents != feb.DeferredOpeningCents+1000 {
+		t.Errorf("February recognized = %d, want opening balance %d plus usage", feb.RecognizedCents, feb.DeferredOpeningCents)
+	}
+	if mar.RecognizedCents != 0 {
+		t.Errorf("March recognized = %d, want 0", mar.RecognizedCents)
+	}
+}
+
+func TestRevenueRecognitionService_Export(t *testing.T) {
+	appID := uuid.New()
+	svc, _, _ := newRecognitionTestService(appID)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	summary, err := svc.Export(context.Background(), appID, from, to, ExportFormatCSV, RecognitionViewSummary)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	csv := string(summary.Data)
+	if !strings.HasPrefix(csv, "month,currency,billed,refunded,recognized,deferred_opening,deferred_closing\n") {
+		t.Errorf("unexpected header: %q", strings.SplitN(csv, "\n", 2)[0])
+	}
+	if !strings.Contains(csv, "2026-01,USD,0.00,0.00,31.00,334.00,303.00\n") {
+		t.Errorf("expected January summary row, got:\n%s", csv)
+	}
+
+	detail, err := svc.Export(context.Background(), appID, from, to, ExportFormatJSON, RecognitionViewDetail)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rows []map[string]string
+	if err := json.Unmarshal(detail.Data, &rows); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	// Annual charge in Jan + Feb, usage charge in Feb
+	if len(rows) != 3 || detail.RecordCount != 3 {
+		t.Errorf("len(rows) = %d, RecordCount = %d, want 3", len(rows), detail.RecordCount)
+	}
+
+	if _, err := svc.Export(context.Background(), appID, from, to, ExportFormatCSV, "ledger"); !errors.Is(err, ErrInvalidRecognitionView) {
+		t.Errorf("err = %v, want ErrInvalidRecognitionView", err)
+	}
+	if _, err := svc.GetReport(context.Background(), appID, to, from); !errors.Is(err, ErrInvalidDateRange) {
+		t.Errorf("err = %v, want ErrInvalidDateRange", err)
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,6 +35,16 @@ type LedgerRebuilder interface {
 	BackfillHistoricalSnapshots(ctx context.Context, appID uuid.UUID, transactions []*entity.Transaction) (int, error)
 }
 
+// ReadModelProjector interface for projecting the synced ledger into the Revenue API read model
+type ReadModelProjector interface {
+	ProjectApp(ctx context.Context, appID uuid.UUID) error
+}
+
+// AlertEvaluator interface for evaluating alert rules after each sync
+type AlertEvaluator interface {
+	EvaluateApp(ctx context.Context, appID uuid.UUID) error
+}
+
 // SyncResult contains the result of a sync operation
 type SyncResult struct {
 	AppID            uuid.UUID
@@ -56,6 +67,9 @@ type SyncService struct {
 	partnerRepo  repository.PartnerAccountRepository
 	decryptor    Decryptor
 	ledger       LedgerRebuilder
+	projector    ReadModelProjector
+	syncStatus   repository.AppSyncStatusRepository
+	alerts       AlertEvaluator
 }
 
 func NewSyncService(
@@ -88,8 +102,60 @@ func (s *SyncService) WithSubscriptionRepo(repo repository.SubscriptionRepositor
 	return s
 }
 
+// WithProjector projects each synced app into the Revenue API read model
+func (s *SyncService) WithProjector(projector ReadModelProjector) *SyncService {
+	s.projector = projector
+	return s
+}
+
+// WithSyncStatusRepo records the outcome of each sync, so failing syncs can be alerted on
+func (s *SyncService) WithSyncStatusRepo(repo repository.AppSyncStatusRepository) *SyncService {
+	s.syncStatus = repo
+	return s
+}
+
+// WithAlertEvaluator evaluates the app's alert rules after each sync, successful or not
+func (s *SyncService) WithAlertEvaluator(evaluator AlertEvaluator) *SyncService {
+	s.alerts = evaluator
+	return s
+}
+
 // SyncApp synchronizes transactions for a single app
 func (s *SyncService) SyncApp(ctx context.Context, appID uuid.UUID) (*SyncResult, error) {
+	result, err := s.syncApp(ctx, appID)
+
+	if s.syncStatus != nil {
+		s.recordSyncStatus(ctx, appID, err)
+	}
+	if s.alerts != nil {
+		if evalErr := s.alerts.EvaluateApp(ctx, appID); evalErr != nil {
+			log.Printf("SyncService: failed to evaluate alerts for app %s: %v", appID, evalErr)
+		}
+	}
+
+	return result, err
+}
+
+// recordSyncStatus stores the outcome of a sync; failures are logged, not returned
+func (s *SyncService) recordSyncStatus(ctx context.Context, appID uuid.UUID, syncErr error) {
+	status, err := s.syncStatus.FindByAppID(ctx, appID)
+	if err != nil {
+		status = entity.NewAppSyncStatus(appID)
+	}
+
+	now := time.Now().UTC()
+	if syncErr != nil {
+		status.RecordFailure(now, syncErr)
+	} else {
+		status.RecordSuccess(now)
+	}
+
+	if err := s.syncStatus.Upsert(ctx, status); err != nil {
+		log.Printf("SyncService: failed to record sync status for app %s: %v", appID, err)
+	}
+}
+
+func (s *SyncService) syncApp(ctx context.Context, appID uuid.UUID) (*SyncResult, error) {
 	// Check if fetcher is configured
 	if s.fetcher == nil {
 		return nil, fmt.Errorf("transaction fetcher not configured")
@@ -171,6 +237,14 @@ func (s *SyncService) SyncApp(ctx context.Context, appID uuid.UUID) (*SyncResult
 		}
 	}
 
+	// Project the changes into the Revenue API read model. The ledger is already
+	// stored, so a failure is logged and repaired by the next sync or consistency check.
+	if s.projector != nil {
+		if err := s.projector.ProjectApp(ctx, appID); err != nil {
+			log.Printf("SyncService: failed to project app %s: %v", appID, err)
+		}
+	}
+
 	return &SyncResult{
 		AppID:            appID,
 		AppName:          app.Name,
@@ -39,6 +39,10 @@ func (m *mockTransactionRepo) UpsertBatch(ctx context.Context, txs []*entity.Tra
 	return m.err
 }
 
+func (m *mockTransactionRepo) FindCreatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*entity.Transaction, error) {
+	return nil, nil
+}
+
 func (m *mockTransactionRepo) FindByAppID(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.Transaction, error) {
 	return nil, nil
 }
@@ -127,6 +131,20 @@ func (m *mockPartnerRepoForSync) FindByUserID(ctx context.Context, userID uuid.U
 	return m.account, m.err
 }
 
+func (m *mockPartnerRepoForSync) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (*entity.PartnerAccount, error) {
+	return m.account, m.err
+}
+
+func (m *mockPartnerRepoForSync) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PartnerAccount, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.account == nil {
+		return nil, nil
+	}
+	return []*entity.PartnerAccount{m.account}, nil
+}
+
 func (m *mockPartnerRepoForSync) FindByPartnerID(ctx context.Context, partnerID string) (*entity.PartnerAccount, error) {
 	return nil, nil
 }
@@ -354,3 +372,46 @@ func TestSyncService_SyncAllApps(t *testing.T) {
 		t.Errorf("expected 1 result, got %d", len(results))
 	}
 }
+
+type mockAlertEvaluator struct {
+	evaluated []uuid.UUID
+}
+
+func (m *mockAlertEvaluator) EvaluateApp(ctx context.Context, appID uuid.UUID) error {
+	m.evaluated = append(m.evaluated, appID)
+	return nil
+}
+
+func TestSyncService_SyncApp_RecordsStatusAndEvaluatesAlerts(t *testing.T) {
+	appID := uuid.New()
+	statusRepo := newMockAppSyncStatusRepo()
+	evaluator := &mockAlertEvaluator{}
+	fetcher := &mockTransactionFetcher{err: errors.New("partner API unavailable")}
+	appRepo := &mockAppRepoForSync{app: &entity.App{ID: appID, PartnerAccountID: uuid.New()}}
+	partnerRepo := &mockPartnerRepoForSync{account: &entity.PartnerAccount{ID: uuid.New(), EncryptedAccessToken: []byte("token")}}
+	service := NewSyncService(fetcher, &mockTransactionRepo{}, appRepo, partnerRepo, &mockDecryptorForSync{}, nil).
+		WithSyncStatusRepo(statusRepo).
+		WithAlertEvaluator(evaluator)
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.SyncApp(context.Background(), appID); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	}
+	status := statusRepo.statuses[appID]
+	if status == nil || status.FailingSince == nil || status.ConsecutiveFailures != 2 || status.LastError == "" {
+		t.Fatalf("expected two recorded failures, got %+v", status)
+	}
+	firstFailure := *status.FailingSince
+
+	fetcher.err = nil
+	if _, err := service.SyncApp(context.Background(), appID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.FailingSince != nil || status.ConsecutiveFailures != 0 || status.LastSuccessAt == nil || status.LastSuccessAt.Before(firstFailure) {
+		t.Errorf("expected success to clear the failure, got %+v", status)
+	}
+	if len(evaluator.evaluated) != 3 {
+		t.Errorf("expected alerts evaluated after every sync, got %d", len(evaluator.evaluated))
+	}
+}
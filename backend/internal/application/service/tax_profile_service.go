@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+var (
+	// ErrOverlappingTaxProfile is returned when a profile's effective period overlaps another profile
+	ErrOverlappingTaxProfile = errors.New("tax profile effective period overlaps an existing profile")
+
+	// ErrTaxProfileNotFound is returned when the profile does not belong to the partner account
+	ErrTaxProfileNotFound = errors.New("tax profile not found")
+)
+
+// TaxProfileService manages partner tax-on-fees jurisdiction profiles
+type TaxProfileService struct {
+	profileRepo repository.TaxJurisdictionProfileRepository
+}
+
+// NewTaxProfileService creates a new tax profile service
+func NewTaxProfileService(profileRepo repository.TaxJurisdictionProfileRepository) *TaxProfileService {
+	return &TaxProfileService{profileRepo: profileRepo}
+}
+
+// ListProfiles returns the partner's profiles ordered by effective date
+func (s *TaxProfileService) ListProfiles(ctx context.Context, partnerAccountID uuid.UUID) ([]*entity.TaxJurisdictionProfile, error) {
+	return s.profileRepo.FindByPartnerAccountID(ctx, partnerAccountID)
+}
+
+// CreateProfile validates and stores a new profile. If the only overlap is an earlier
+// open-ended profile, that profile is ended on the new profile's effective date
+// (the usual case when a tax rate changes).
+func (s *TaxProfileService) CreateProfile(ctx context.Context, profile *entity.TaxJurisdictionProfile) error {
+	profile.EffectiveFrom = truncateToDay(profile.EffectiveFrom)
+	if profile.EffectiveTo != nil {
+		to := truncateToDay(*profile.EffectiveTo)
+		profile.EffectiveTo = &to
+	}
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	existing, err := s.profileRepo.FindByPartnerAccountID(ctx, profile.PartnerAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tax profiles: %w", err)
+	}
+
+	var superseded *entity.TaxJurisdictionProfile
+	for _, p := range existing {
+		if !p.Overlaps(profile) {
+			continue
+		}
+		if p.EffectiveTo == nil && p.EffectiveFrom.Before(profile.EffectiveFrom) && superseded == nil {
+			superseded = p
+			continue
+		}
+		return ErrOverlappingTaxProfile
+	}
+
+	if superseded != nil {
+		end := profile.EffectiveFrom
+		superseded.EffectiveTo = &end
+		superseded.UpdatedAt = time.Now().UTC()
+		if err := s.profileRepo.Update(ctx, superseded); err != nil {
+			return fmt.Errorf("failed to end previous tax profile: %w", err)
+		}
+	}
+
+	return s.profileRepo.Create(ctx, profile)
+}
+
+// UpdateProfile replaces an existing profile's fields, rejecting overlaps with other profiles
+func (s *TaxProfileService) UpdateProfile(ctx context.Context, profile *entity.TaxJurisdictionProfile) error {
+	profile.EffectiveFrom = truncateToDay(profile.EffectiveFrom)
+	if profile.EffectiveTo != nil {
+		to := truncateToDay(*profile.EffectiveTo)
+		profile.EffectiveTo = &to
+	}
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	existing, err := s.profileRepo.FindByPartnerAccountID(ctx, profile.PartnerAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tax profiles: %w", err)
+	}
+
+	found := false
+	for _, p := range existing {
+		if p.ID == profile.ID {
+			found = true
+			profile.CreatedAt = p.CreatedAt
+			continue
+		}
+		if p.Overlaps(profile) {
+			return ErrOverlappingTaxProfile
+		}
+	}
+	if !found {
+		return ErrTaxProfileNotFound
+	}
+
+	profile.UpdatedAt = time.Now().UTC()
+	return s.profileRepo.Update(ctx, profile)
+}
+
+// DeleteProfile removes a profile owned by the partner account
+func (s *TaxProfileService) DeleteProfile(ctx context.Context, partnerAccountID, id uuid.UUID) error {
+	existing, err := s.profileRepo.FindByPartnerAccountID(ctx, partnerAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tax profiles: %w", err)
+	}
+	for _, p := range existing {
+		if p.ID == id {
+			return s.profileRepo.Delete(ctx, partnerAccountID, id)
+		}
+	}
+	return ErrTaxProfileNotFound
+}
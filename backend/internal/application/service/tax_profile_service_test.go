@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+type mockTaxProfileRepo struct {
+	profiles []*entity.TaxJurisdictionProfile
+}
+
+func (m *mockTaxProfileRepo) Create(ctx context.Context, profile *entity.TaxJurisdictionProfile) error {
+	m.profiles = append(m.profiles, profile)
+	return nil
+}
+
+func (m *mockTaxProfileRepo) Update(ctx context.Context, profile *entity.TaxJurisdictionProfile) error {
+	for i, p := range m.profiles {
+		if p.ID == profile.ID {
+			m.profiles[i] = profile
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *mockTaxProfileRepo) Delete(ctx context.Context, partnerAccountID, id uuid.UUID) error {
+	for i, p := range m.profiles {
+		if p.ID == id && p.PartnerAccountID == partnerAccountID {
+			m.profiles = append(m.profiles[:i], m.profiles[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *mockTaxProfileRepo) FindByPartnerAccountID(ctx context.Context, partnerAccountID uuid.UUID) ([]*entity.TaxJurisdictionProfile, error) {
+	var result []*entity.TaxJurisdictionProfile
+	for _, p := range m.profiles {
+		if p.PartnerAccountID == partnerAccountID {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func TestTaxProfileService_CreateProfile_EndsOpenEndedPredecessor(t *testing.T) {
+	repo := &mockTaxProfileRepo{}
+	svc := NewTaxProfileService(repo)
+	partnerID := uuid.New()
+
+	first := entity.NewTaxJurisdictionProfile(partnerID, "CA-ON", "HST", 0.13, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err := svc.CreateProfile(context.Background(), first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rateChange := entity.NewTaxJurisdictionProfile(partnerID, "CA-ON", "HST", 0.15, time.Date(2026, 7, 1, 10, 0, 0, 0, time.UTC))
+	if err := svc.CreateProfile(context.Background(), rateChange); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantEnd := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	if first.EffectiveTo == nil || !first.EffectiveTo.Equal(wantEnd) {
+		t.Errorf("first.EffectiveTo = %v, want %v", first.EffectiveTo, wantEnd)
+	}
+
+	profiles, _ := svc.ListProfiles(context.Background(), partnerID)
+	if got := entity.EffectiveTaxProfile(profiles, time.Date(2026, 6, 30, 23, 0, 0, 0, time.UTC)); got != first {
+		t.Error("expected first profile on 2026-06-30")
+	}
+	if got := entity.EffectiveTaxProfile(profiles, wantEnd); got != rateChange {
+		t.Error("expected new profile from 2026-07-01")
+	}
+}
+
+func TestTaxProfileService_CreateProfile_RejectsInvalid(t *testing.T) {
+	repo := &mockTaxProfileRepo{}
+	svc := NewTaxProfileService(repo)
+	partnerID := uuid.New()
+
+	existing := entity.NewTaxJurisdictionProfile(partnerID, "AU", "GST", 0.10, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	end := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	existing.EffectiveTo = &end
+	repo.profiles = []*entity.TaxJurisdictionProfile{existing}
+
+	overlapping := entity.NewTaxJurisdictionProfile(partnerID, "AU", "GST", 0.10, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	if err := svc.CreateProfile(context.Background(), overlapping); !errors.Is(err, ErrOverlappingTaxProfile) {
+		t.Errorf("err = %v, want ErrOverlappingTaxProfile", err)
+	}
+
+	badRate := entity.NewTaxJurisdictionProfile(partnerID, "AU", "GST", 10, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err := svc.CreateProfile(context.Background(), badRate); !errors.Is(err, entity.ErrInvalidTaxRate) {
+		t.Errorf("err = %v, want ErrInvalidTaxRate", err)
+	}
+
+	if len(repo.profiles) != 1 {
+		t.Errorf("len(profiles) = %d, want 1", len(repo.profiles))
+	}
+
+	if err := svc.DeleteProfile(context.Background(), uuid.New(), existing.ID); !errors.Is(err, ErrTaxProfileNotFound) {
+		t.Errorf("err = %v, want ErrTaxProfileNotFound for another partner", err)
+	}
+}
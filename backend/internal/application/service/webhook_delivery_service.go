@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+const (
+	// MaxWebhookProcessingAttempts is the number of attempts before a delivery fails (~4h of retries)
+	MaxWebhookProcessingAttempts = 8
+
+	webhookProcessingRetryBase = 30 * time.Second
+	webhookProcessingRetryMax  = 2 * time.Hour
+	webhookProcessingLease     = 5 * time.Minute // Longer than processing a delivery can take
+	maxWebhookDeliveryList     = 200
+)
+
+var (
+	// ErrWebhookDeliveryNotFound is returned when the delivery does not exist for the app
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+	// ErrWebhookDeliveryInProgress is returned when a delivery is being processed
+	ErrWebhookDeliveryInProgress = errors.New("webhook delivery is being processed")
+)
+
+// WebhookEventProcessor applies a Shopify webhook event
+type WebhookEventProcessor interface {
+	ProcessEvent(ctx context.Context, event WebhookEvent) error
+}
+
+// WebhookDeliveryService stores inbound Shopify webhooks so they can be acknowledged
+// at once, and processes them in the background: duplicates (same webhook ID) are
+// dropped, failures are retried, and an event older than one already applied to the
+// same resource is skipped.
+type WebhookDeliveryService struct {
+	deliveryRepo repository.WebhookDeliveryRepository
+	processor    WebhookEventProcessor
+	interval     time.Duration
+	batchSize    int
+	now          func() time.Time
+	wakeCh       chan struct{}
+	stopCh       chan struct{}
+	doneCh       chan struct{}
+}
+
+// NewWebhookDeliveryService creates a new WebhookDeliveryService polling every 5 seconds
+func NewWebhookDeliveryService(
+	deliveryRepo repository.WebhookDeliveryRepository,
+	processor WebhookEventProcessor,
+) *WebhookDeliveryService {
+	return &WebhookDeliveryService{
+		deliveryRepo: deliveryRepo,
+		processor:    processor,
+		interval:     5 * time.Second,
+		batchSize:    50,
+		now:          func() time.Time { return time.Now().UTC() },
+		wakeCh:       make(chan struct{}, 1),
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+	}
+}
+
+// WithInterval sets how often pending deliveries are polled
+func (s *WebhookDeliveryService) WithInterval(interval time.Duration) *WebhookDeliveryService {
+	s.interval = interval
+	return s
+}
+
+// Start begins processing pending deliveries
+func (s *WebhookDeliveryService) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+// Stop gracefully stops the worker after the current batch
+func (s *WebhookDeliveryService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+func (s *WebhookDeliveryService) run(ctx context.Context) {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.wakeCh:
+		case <-s.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		// Drain full batches before waiting again
+		for {
+			n, err := s.ProcessDue(ctx)
+			if err != nil {
+				log.Printf("WebhookDeliveryService: %v", err)
+			}
+			if err != nil || n < s.batchSize {
+				break
+			}
+		}
+	}
+}
+
+// Enqueue stores a verified delivery and wakes the worker. Returns false if
+// Shopify already delivered the webhook (same webhook ID).
+func (s *WebhookDeliveryService) Enqueue(ctx context.Context, delivery *entity.WebhookDelivery) (bool, error) {
+	delivery.ResourceKey = webhookResourceKey(delivery.Topic, delivery.Payload)
+	delivery.Payload = withoutCustomerContact(delivery.Topic, delivery.Payload)
+
+	created, err := s.deliveryRepo.Create(ctx, delivery)
+	if err != nil {
+		return false, fmt.Errorf("failed to store webhook delivery: %w", err)
+	}
+
+	if created {
+		select {
+		case s.wakeCh <- struct{}{}:
+		default:
+		}
+	}
+	return created, nil
+}
+
+// ProcessDue processes every pending delivery that is due and returns how many were attempted
+func (s *WebhookDeliveryService) ProcessDue(ctx context.Context) (int, error) {
+	deliveries, err := s.deliveryRepo.ClaimDue(ctx, s.now(), webhookProcessingLease, s.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due deliveries: %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		if err := s.process(ctx, delivery, true); err != nil {
+			log.Printf("WebhookDeliveryService: delivery %s: %v", delivery.ID, err)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// ListDeliveries returns the most recent deliveries matching the filter
+func (s *WebhookDeliveryService) ListDeliveries(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]*entity.WebhookDelivery, error) {
+	if filter.Limit <= 0 || filter.Limit > maxWebhookDeliveryList {
+		filter.Limit = maxWebhookDeliveryList
+	}
+
+	deliveries, err := s.deliveryRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		deliveries = []*entity.WebhookDelivery{}
+	}
+	return deliveries, nil
+}
+
+// GetDelivery returns a delivery received for the Shopify app
+func (s *WebhookDeliveryService) GetDelivery(ctx context.Context, appGID string, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	delivery, err := s.deliveryRepo.FindByID(ctx, id)
+	if err != nil || delivery.AppGID != appGID {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+// Replay processes a delivery again right away, whatever its status. An explicit
+// replay is applied even if newer events for the same resource were processed.
+func (s *WebhookDeliveryService) Replay(ctx context.Context, appGID string, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	if _, err := s.GetDelivery(ctx, appGID, id); err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.deliveryRepo.Claim(ctx, id, s.now(), webhookProcessingLease)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+	if !claimed {
+		return nil, ErrWebhookDeliveryInProgress
+	}
+
+	// Re-read under the lease so the worker's last outcome is not overwritten
+	delivery, err := s.deliveryRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch webhook delivery: %w", err)
+	}
+	delivery.Attempts = 0
+
+	if err := s.process(ctx, delivery, false); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// process applies a claimed delivery and records the outcome, releasing the lease
+func (s *WebhookDeliveryService) process(ctx context.Context, delivery *entity.WebhookDelivery, checkOrder bool) error {
+	now := s.now()
+
+	if checkOrder && delivery.ResourceKey != "" {
+		newer, err := s.deliveryRepo.HasNewerProcessed(ctx, delivery.Topic, delivery.ResourceKey, delivery.TriggeredAt)
+		if err != nil {
+			// Keep the lease; the delivery is retried once it expires
+			return fmt.Errorf("failed to check event order: %w", err)
+		}
+		if newer {
+			delivery.Status = entity.WebhookDeliverySkipped
+			delivery.NextAttemptAt = nil
+			delivery.LastError = "superseded by a newer event for the same resource"
+			delivery.ProcessedAt = &now
+			return s.deliveryRepo.Update(ctx, delivery)
+		}
+	}
+
+	delivery.Attempts++
+	err := s.processor.ProcessEvent(ctx, WebhookEvent{
+		Topic:     delivery.Topic,
+		ShopID:    delivery.ShopDomain,
+		AppID:     delivery.AppGID,
+		Payload:   delivery.Payload,
+		Timestamp: delivery.TriggeredAt,
+	})
+
+	if err == nil {
+		delivery.Status = entity.WebhookDeliveryProcessed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+		delivery.ProcessedAt = &now
+		return s.deliveryRepo.Update(ctx, delivery)
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts < MaxWebhookProcessingAttempts {
+		next := now.Add(webhookProcessingRetryDelay(delivery.Attempts))
+		delivery.Status = entity.WebhookDeliveryPending
+		delivery.NextAttemptAt = &next
+	} else {
+		delivery.Status = entity.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+	}
+	return s.deliveryRepo.Update(ctx, delivery)
+}
+
+// webhookProcessingRetryDelay returns the wait after the given failed attempt: 30s, 1m, 2m, ... capped at 2h
+func webhookProcessingRetryDelay(attempt int) time.Duration {
+	delay := webhookProcessingRetryBase
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= webhookProcessingRetryMax {
+			return webhookProcessingRetryMax
+		}
+	}
+	return delay
+}
+
+// webhookResourceKey identifies the resource whose state an event replaces, so an
+// older event is not applied over a newer one. Empty for topics that record
+// occurrences (e.g. billing failures) rather than replace state.
+func webhookResourceKey(topic string, payload []byte) string {
+	switch topic {
+	case "app_subscriptions/update":
+		if p, err := parseSubscriptionPayload(payload); err == nil {
+			return p.ID
+		}
+	case "app/uninstalled":
+		var p AppUninstalledPayload
+		if json.Unmarshal(payload, &p) == nil {
+			return p.MyshopifyDomain
+		}
+	case "shop/update":
+		var p ShopUpdatePayload
+		if json.Unmarshal(payload, &p) == nil {
+			return p.MyshopifyDomain
+		}
+	}
+	return ""
+}
+
+// withoutCustomerContact drops the customer's e-mail and phone from customers/*
+// compliance payloads before they are stored; only the customer ID is kept
+func withoutCustomerContact(topic string, payload []byte) []byte {
+	if topic != entity.ComplianceTopicCustomersDataRequest && topic != entity.ComplianceTopicCustomersRedact {
+		return payload
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(payload, &fields) != nil {
+		return payload
+	}
+	var customer struct {
+		ID int64 `json:"id"`
+	}
+	if raw, ok := fields["customer"]; ok && json.Unmarshal(raw, &customer) == nil {
+		fields["customer"], _ = json.Marshal(customer)
+	}
+
+	stripped, err := json.Marshal(fields)
+	if err != nil {
+		return payload
+	}
+	return stripped
+}
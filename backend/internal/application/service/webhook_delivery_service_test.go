@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+type mockWebhookDeliveryRepo struct {
+	deliveries []*entity.WebhookDelivery
+	locked     map[uuid.UUID]time.Time
+}
+
+func newMockWebhookDeliveryRepo() *mockWebhookDeliveryRepo {
+	return &mockWebhookDeliveryRepo{locked: make(map[uuid.UUID]time.Time)}
+}
+
+func (m *mockWebhookDeliveryRepo) Create(ctx context.Context, delivery *entity.WebhookDelivery) (bool, error) {
+	for _, d := range m.deliveries {
+		if d.WebhookID == delivery.WebhookID {
+			return false, nil
+		}
+	}
+	m.deliveries = append(m.deliveries, delivery)
+	return true, nil
+}
+
+func (m *mockWebhookDeliveryRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	for _, d := range m.deliveries {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockWebhookDeliveryRepo) List(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]*entity.WebhookDelivery, error) {
+	var result []*entity.WebhookDelivery
+	for _, d := range m.deliveries {
+		if (filter.AppGID == "" || d.AppGID == filter.AppGID) && (filter.Status == "" || d.Status == filter.Status) {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWebhookDeliveryRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	var due []*entity.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.Status == entity.WebhookDeliveryPending && !d.NextAttemptAt.After(now) && !m.locked[d.ID].After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].TriggeredAt.Before(due[j].TriggeredAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for _, d := range due {
+		m.locked[d.ID] = now.Add(lease)
+	}
+	return due, nil
+}
+
+func (m *mockWebhookDeliveryRepo) Claim(ctx context.Context, id uuid.UUID, now time.Time, lease time.Duration) (bool, error) {
+	if m.locked[id].After(now) {
+		return false, nil
+	}
+	m.locked[id] = now.Add(lease)
+	return true, nil
+}
+
+func (m *mockWebhookDeliveryRepo) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	delete(m.locked, delivery.ID)
+	return nil
+}
+
+func (m *mockWebhookDeliveryRepo) HasNewerProcessed(ctx context.Context, topic, resourceKey string, triggeredAt time.Time) (bool, error) {
+	for _, d := range m.deliveries {
+		if d.Topic == topic && d.ResourceKey == resourceKey && d.Status == entity.WebhookDeliveryProcessed && d.TriggeredAt.After(triggeredAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type mockWebhookEventProcessor struct {
+	events []WebhookEvent
+	err    error
+}
+
+func (m *mockWebhookEventProcessor) ProcessEvent(ctx context.Context, event WebhookEvent) error {
+	m.events = append(m.events, event)
+	return m.err
+}
+
+const testAppGID = "gid://partners/App/123"
+
+func newTestDelivery(webhookID, subscriptionGID, status string, triggeredAt time.Time) *entity.WebhookDelivery {
+	payload := []byte(`{"admin_graphql_api_id":"` + subscriptionGID + `","status":"` + status + `"}`)
+	d := entity.NewWebhookDelivery(webhookID, "app_subscriptions/update", "shop.myshopify.com", testAppGID, payload, triggeredAt)
+	past := triggeredAt
+	d.NextAttemptAt = &past
+	return d
+}
+
+func TestWebhookDeliveryService_Enqueue_DropsDuplicates(t *testing.T) {
+	repo := newMockWebhookDeliveryRepo()
+	svc := NewWebhookDeliveryService(repo, &mockWebhookEventProcessor{})
+	triggeredAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	created, err := svc.Enqueue(context.Background(), newTestDelivery("wh-1", "gid://shopify/AppSubscription/1", "ACTIVE", triggeredAt))
+	if err != nil || !created {
+		t.Fatalf("expected first delivery to be stored, got %v, %v", created, err)
+	}
+	if repo.deliveries[0].ResourceKey != "gid://shopify/AppSubscription/1" {
+		t.Errorf("ResourceKey = %q, want the subscription GID", repo.deliveries[0].ResourceKey)
+	}
+
+	created, err = svc.Enqueue(context.Background(), newTestDelivery("wh-1", "gid://shopify/AppSubscription/1", "ACTIVE", triggeredAt))
+	if err != nil || created {
+		t.Errorf("expected retried delivery to be dropped, got %v, %v", created, err)
+	}
+	if len(repo.deliveries) != 1 {
+		t.Errorf("expected 1 stored delivery, got %d", len(repo.deliveries))
+	}
+}
+
+func TestWebhookDeliveryService_ProcessDue_SkipsOutOfOrderEvents(t *testing.T) {
+	repo := newMockWebhookDeliveryRepo()
+	processor := &mockWebhookEventProcessor{}
+	svc := NewWebhookDeliveryService(repo, processor)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// The cancellation arrives first but was triggered after the freeze
+	cancelled := newTestDelivery("wh-2", "gid://shopify/AppSubscription/1", "CANCELLED", now.Add(-time.Minute))
+	svc.Enqueue(ctx, cancelled)
+	if n, err := svc.ProcessDue(ctx); err != nil || n != 1 {
+		t.Fatalf("ProcessDue = %d, %v", n, err)
+	}
+
+	frozen := newTestDelivery("wh-1", "gid://shopify/AppSubscription/1", "FROZEN", now.Add(-2*time.Minute))
+	other := newTestDelivery("wh-3", "gid://shopify/AppSubscription/2", "FROZEN", now.Add(-2*time.Minute))
+	svc.Enqueue(ctx, frozen)
+	svc.Enqueue(ctx, other)
+	if n, err := svc.ProcessDue(ctx); err != nil || n != 2 {
+		t.Fatalf("ProcessDue = %d, %v", n, err)
+	}
+
+	if cancelled.Status != entity.WebhookDeliveryProcessed || other.Status != entity.WebhookDeliveryProcessed {
+		t.Errorf("expected cancellation and other subscription processed, got %s and %s", cancelled.Status, other.Status)
+	}
+	if frozen.Status != entity.WebhookDeliverySkipped || frozen.Attempts != 0 {
+		t.Errorf("expected stale event to be skipped without an attempt, got %s after %d", frozen.Status, frozen.Attempts)
+	}
+	if len(processor.events) != 2 {
+		t.Errorf("expected 2 processed events, got %d", len(processor.events))
+	}
+	if !processor.events[0].Timestamp.Equal(cancelled.TriggeredAt) || processor.events[0].AppID != testAppGID {
+		t.Errorf("expected event timestamp and app from the delivery, got %+v", processor.events[0])
+	}
+}
+
+func TestWebhookDeliveryService_ProcessDue_RetriesThenFails(t *testing.T) {
+	repo := newMockWebhookDeliveryRepo()
+	processor := &mockWebhookEventProcessor{err: errors.New("database unavailable")}
+	svc := NewWebhookDeliveryService(repo, processor)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	delivery := newTestDelivery("wh-1", "gid://shopify/AppSubscription/1", "ACTIVE", now)
+	svc.Enqueue(ctx, delivery)
+
+	svc.ProcessDue(ctx)
+	if delivery.Status != entity.WebhookDeliveryPending || delivery.Attempts != 1 || delivery.LastError != "database unavailable" {
+		t.Fatalf("expected pending retry after first failure, got %+v", delivery)
+	}
+	if !delivery.NextAttemptAt.Equal(now.Add(30 * time.Second)) {
+		t.Errorf("NextAttemptAt = %v, want 30s later", delivery.NextAttemptAt)
+	}
+
+	if n, _ := svc.ProcessDue(ctx); n != 0 {
+		t.Errorf("expected no attempt before the retry is due, got %d", n)
+	}
+
+	for i := 1; i < MaxWebhookProcessingAttempts; i++ {
+		now = now.Add(webhookProcessingRetryMax)
+		svc.ProcessDue(ctx)
+	}
+	if delivery.Status != entity.WebhookDeliveryFailed || delivery.Attempts != MaxWebhookProcessingAttempts || delivery.NextAttemptAt != nil {
+		t.Errorf("expected failure after %d attempts, got %s after %d", MaxWebhookProcessingAttempts, delivery.Status, delivery.Attempts)
+	}
+}
+
+func TestWebhookDeliveryService_Replay(t *testing.T) {
+	repo := newMockWebhookDeliveryRepo()
+	processor := &mockWebhookEventProcessor{}
+	svc := NewWebhookDeliveryService(repo, processor)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	newer := newTestDelivery("wh-2", "gid://shopify/AppSubscription/1", "CANCELLED", now)
+	older := newTestDelivery("wh-1", "gid://shopify/AppSubscription/1", "ACTIVE", now.Add(-time.Hour))
+	older.Status = entity.WebhookDeliveryFailed
+	older.Attempts = MaxWebhookProcessingAttempts
+	svc.Enqueue(ctx, newer)
+	svc.Enqueue(ctx, older)
+	svc.ProcessDue(ctx)
+
+	if _, err := svc.Replay(ctx, "gid://partners/App/999", older.ID); !errors.Is(err, ErrWebhookDeliveryNotFound) {
+		t.Errorf("expected ErrWebhookDeliveryNotFound for another app, got %v", err)
+	}
+
+	repo.locked[older.ID] = now.Add(time.Minute)
+	if _, err := svc.Replay(ctx, testAppGID, older.ID); !errors.Is(err, ErrWebhookDeliveryInProgress) {
+		t.Errorf("expected ErrWebhookDeliveryInProgress while leased, got %v", err)
+	}
+	delete(repo.locked, older.ID)
+
+	replayed, err := svc.Replay(ctx, testAppGID, older.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replayed.Status != entity.WebhookDeliveryProcessed || replayed.Attempts != 1 {
+		t.Errorf("expected replay to process despite a newer event, got %s after %d", replayed.Status, replayed.Attempts)
+	}
+	if len(processor.events) != 2 {
+		t.Errorf("expected 2 processed events, got %d", len(processor.events))
+	}
+}
+
+func TestWebhookDeliveryService_Enqueue_DropsCustomerContact(t *testing.T) {
+	repo := newMockWebhookDeliveryRepo()
+	svc := NewWebhookDeliveryService(repo, &mockWebhookEventProcessor{})
+	payload := []byte(`{"shop_domain":"shop.myshopify.com","customer":{"id":191167,"email":"john@example.com","phone":"555-625-1199"}}`)
+
+	svc.Enqueue(context.Background(), entity.NewWebhookDelivery("wh-1", "customers/redact", "shop.myshopify.com", testAppGID, payload, time.Now()))
+
+	stored := string(repo.deliveries[0].Payload)
+	if strings.Contains(stored, "john@example.com") || strings.Contains(stored, "555-625-1199") {
+		t.Errorf("expected contact details to be dropped, got %s", stored)
+	}
+	if !strings.Contains(stored, `"customer":{"id":191167}`) || !strings.Contains(stored, `"shop_domain":"shop.myshopify.com"`) {
+		t.Errorf("expected customer ID and shop to be kept, got %s", stored)
+	}
+}
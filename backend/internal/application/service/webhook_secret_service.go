@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+// minWebhookSecretLength rejects secrets short enough for the stored hint to give them away
+const minWebhookSecretLength = 16
+
+var (
+	// ErrInvalidWebhookSecret is returned when a new webhook secret is too short
+	ErrInvalidWebhookSecret = fmt.Errorf("webhook secret must be at least %d characters", minWebhookSecretLength)
+
+	// ErrWebhookSecretNotFound is returned when the secret does not belong to the app
+	ErrWebhookSecretNotFound = errors.New("webhook secret not found")
+
+	// ErrInvalidWebhookSignature is returned when no known secret produced the webhook's HMAC
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+)
+
+// Encryptor encrypts secrets before they are stored and decrypts them for use
+type Encryptor interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decryptor
+}
+
+// WebhookSecretService manages the per-app secrets Shopify signs webhooks with
+// and verifies webhook signatures against them
+type WebhookSecretService struct {
+	secretRepo   repository.WebhookSecretRepository
+	appRepo      repository.AppRepository
+	encryptor    Encryptor
+	clientSecret string
+	now          func() time.Time
+}
+
+// NewWebhookSecretService creates a new webhook secret service
+func NewWebhookSecretService(
+	secretRepo repository.WebhookSecretRepository,
+	appRepo repository.AppRepository,
+	encryptor Encryptor,
+) *WebhookSecretService {
+	return &WebhookSecretService{
+		secretRepo: secretRepo,
+		appRepo:    appRepo,
+		encryptor:  encryptor,
+		now:        func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// WithClientSecret also accepts webhooks signed with the Shopify app client secret,
+// which Shopify uses for webhooks subscribed through the app configuration
+func (s *WebhookSecretService) WithClientSecret(clientSecret string) *WebhookSecretService {
+	s.clientSecret = clientSecret
+	return s
+}
+
+// ListSecrets returns the app's unrevoked secrets, newest first
+func (s *WebhookSecretService) ListSecrets(ctx context.Context, appID uuid.UUID) ([]*entity.WebhookSecret, error) {
+	return s.secretRepo.FindByAppID(ctx, appID)
+}
+
+// AddSecret encrypts and stores a new secret for the app. Existing secrets stay
+// active unless retireExistingAfter is set, in which case they expire after that
+// overlap (zero retires them immediately).
+func (s *WebhookSecretService) AddSecret(
+	ctx context.Context,
+	appID uuid.UUID,
+	secret, label string,
+	retireExistingAfter *time.Duration,
+) (*entity.WebhookSecret, error) {
+	secret = strings.TrimSpace(secret)
+	if len(secret) < minWebhookSecretLength {
+		return nil, ErrInvalidWebhookSecret
+	}
+
+	encrypted, err := s.encryptor.Encrypt([]byte(secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	webhookSecret := entity.NewWebhookSecret(appID, strings.TrimSpace(label), encrypted, secret[len(secret)-4:])
+	webhookSecret.CreatedAt = s.now()
+	if err := s.secretRepo.Create(ctx, webhookSecret); err != nil {
+		return nil, fmt.Errorf("failed to store webhook secret: %w", err)
+	}
+
+	if retireExistingAfter != nil {
+		expiresAt := webhookSecret.CreatedAt.Add(*retireExistingAfter)
+		if err := s.secretRepo.ExpireOthers(ctx, appID, webhookSecret.ID, expiresAt); err != nil {
+			return nil, fmt.Errorf("failed to retire previous webhook secrets: %w", err)
+		}
+	}
+
+	return webhookSecret, nil
+}
+
+// RevokeSecret stops a secret from verifying webhooks
+func (s *WebhookSecretService) RevokeSecret(ctx context.Context, appID, id uuid.UUID) error {
+	existing, err := s.secretRepo.FindByAppID(ctx, appID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch webhook secrets: %w", err)
+	}
+	for _, secret := range existing {
+		if secret.ID == id {
+			return s.secretRepo.Revoke(ctx, appID, id, s.now())
+		}
+	}
+	return ErrWebhookSecretNotFound
+}
+
+// Verify checks a base64 HMAC-SHA256 webhook signature against every active secret
+// of the apps tracking partnerAppID (a Shopify app GID), then against the client
+// secret. Returns ErrInvalidWebhookSignature if none of them match.
+func (s *WebhookSecretService) Verify(ctx context.Context, partnerAppID string, body []byte, signature string) error {
+	if signature == "" {
+		return ErrInvalidWebhookSignature
+	}
+
+	if partnerAppID != "" {
+		apps, err := s.appRepo.FindAllByPartnerAppID(ctx, partnerAppID)
+		if err != nil {
+			return fmt.Errorf("failed to find apps for %s: %w", partnerAppID, err)
+		}
+
+		if len(apps) > 0 {
+			appIDs := make([]uuid.UUID, len(apps))
+			for i, app := range apps {
+				appIDs[i] = app.ID
+			}
+
+			secrets, err := s.secretRepo.FindActiveByAppIDs(ctx, appIDs, s.now())
+			if err != nil {
+				return fmt.Errorf("failed to fetch webhook secrets: %w", err)
+			}
+
+			for _, secret := range secrets {
+				plaintext, err := s.encryptor.Decrypt(secret.EncryptedSecret)
+				if err != nil {
+					log.Printf("Failed to decrypt webhook secret %s: %v", secret.ID, err)
+					continue
+				}
+				if !validShopifyHMAC(plaintext, body, signature) {
+					continue
+				}
+
+				if err := s.secretRepo.MarkUsed(ctx, secret.ID, s.now()); err != nil {
+					log.Printf("Failed to record use of webhook secret %s: %v", secret.ID, err)
+				}
+				return nil
+			}
+		}
+	}
+
+	if s.clientSecret != "" && validShopifyHMAC([]byte(s.clientSecret), body, signature) {
+		return nil
+	}
+
+	return ErrInvalidWebhookSignature
+}
+
+// validShopifyHMAC compares signature with the base64 HMAC-SHA256 of body in constant time
+func validShopifyHMAC(secret, body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	expectedMAC := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expectedMAC), []byte(signature))
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+type mockWebhookSecretRepo struct {
+	secrets []*entity.WebhookSecret
+}
+
+func (m *mockWebhookSecretRepo) Create(ctx context.Context, secret *entity.WebhookSecret) error {
+	m.secrets = append(m.secrets, secret)
+	return nil
+}
+
+func (m *mockWebhookSecretRepo) FindByAppID(ctx context.Context, appID uuid.UUID) ([]*entity.WebhookSecret, error) {
+	var result []*entity.WebhookSecret
+	for _, s := range m.secrets {
+		if s.AppID == appID && s.RevokedAt == nil {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWebhookSecretRepo) FindActiveByAppIDs(ctx context.Context, appIDs []uuid.UUID, now time.Time) ([]*entity.WebhookSecret, error) {
+	var result []*entity.WebhookSecret
+	for _, s := range m.secrets {
+		for _, appID := range appIDs {
+			if s.AppID == appID && s.IsActive(now) {
+				result = append(result, s)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWebhookSecretRepo) ExpireOthers(ctx context.Context, appID, keepID uuid.UUID, expiresAt time.Time) error {
+	for _, s := range m.secrets {
+		if s.AppID == appID && s.ID != keepID && s.RevokedAt == nil && (s.ExpiresAt == nil || s.ExpiresAt.After(expiresAt)) {
+			at := expiresAt
+			s.ExpiresAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *mockWebhookSecretRepo) Revoke(ctx context.Context, appID, id uuid.UUID, revokedAt time.Time) error {
+	for _, s := range m.secrets {
+		if s.ID == id && s.AppID == appID {
+			s.RevokedAt = &revokedAt
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *mockWebhookSecretRepo) MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	for _, s := range m.secrets {
+		if s.ID == id {
+			s.LastUsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+// prefixEncryptor is a reversible stand-in for AES encryption
+type prefixEncryptor struct{}
+
+func (prefixEncryptor) Encrypt(plaintext []byte) ([]byte, error) {
+	return append([]byte("enc:"), plaintext...), nil
+}
+
+func (prefixEncryptor) Decrypt(ciphertext []byte) ([]byte, error) {
+	if !bytes.HasPrefix(ciphertext, []byte("enc:")) {
+		return nil, errors.New("not encrypted")
+	}
+	return ciphertext[4:], nil
+}
+
+func signWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newTestWebhookSecretService(now *time.Time) (*WebhookSecretService, *mockWebhookSecretRepo, *entity.App) {
+	app := &entity.App{ID: uuid.New(), PartnerAppID: "gid://partners/App/123"}
+	repo := &mockWebhookSecretRepo{}
+	svc := NewWebhookSecretService(repo, &mockAppRepoForSync{app: app}, prefixEncryptor{})
+	svc.now = func() time.Time { return *now }
+	return svc, repo, app
+}
+
+func TestWebhookSecretService_AddSecret_EncryptsSecret(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc, repo, app := newTestWebhookSecretService(&now)
+
+	secret, err := svc.AddSecret(context.Background(), app.ID, "  shpss_0123456789abcdef  ", "primary", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(secret.EncryptedSecret) != "enc:shpss_0123456789abcdef" {
+		t.Errorf("EncryptedSecret = %q, want the trimmed secret encrypted", secret.EncryptedSecret)
+	}
+	if secret.SecretHint != "cdef" || secret.Label != "primary" || !secret.CreatedAt.Equal(now) {
+		t.Errorf("unexpected secret %+v", secret)
+	}
+	if len(repo.secrets) != 1 {
+		t.Errorf("expected 1 stored secret, got %d", len(repo.secrets))
+	}
+
+	if _, err := svc.AddSecret(context.Background(), app.ID, "short", "", nil); !errors.Is(err, ErrInvalidWebhookSecret) {
+		t.Errorf("expected ErrInvalidWebhookSecret, got %v", err)
+	}
+}
+
+func TestWebhookSecretService_Verify_AcceptsEveryActiveSecretDuringRotation(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc, _, app := newTestWebhookSecretService(&now)
+	ctx := context.Background()
+	body := []byte(`{"app_subscription":{"status":"ACTIVE"}}`)
+
+	old, _ := svc.AddSecret(ctx, app.ID, "old-secret-0123456789", "old", nil)
+	overlap := 24 * time.Hour
+	current, _ := svc.AddSecret(ctx, app.ID, "new-secret-0123456789", "new", &overlap)
+
+	if old.ExpiresAt == nil || !old.ExpiresAt.Equal(now.Add(overlap)) {
+		t.Fatalf("old.ExpiresAt = %v, want %v", old.ExpiresAt, now.Add(overlap))
+	}
+	if current.ExpiresAt != nil {
+		t.Errorf("new secret should not expire, got %v", current.ExpiresAt)
+	}
+
+	for _, secret := range []string{"old-secret-0123456789", "new-secret-0123456789"} {
+		if err := svc.Verify(ctx, app.PartnerAppID, body, signWebhook(secret, body)); err != nil {
+			t.Errorf("Verify with %s: %v", secret, err)
+		}
+	}
+	if old.LastUsedAt == nil || current.LastUsedAt == nil {
+		t.Error("expected both secrets to record their use")
+	}
+
+	now = now.Add(overlap)
+	if err := svc.Verify(ctx, app.PartnerAppID, body, signWebhook("old-secret-0123456789", body)); !errors.Is(err, ErrInvalidWebhookSignature) {
+		t.Errorf("expected expired secret to be rejected, got %v", err)
+	}
+	if err := svc.Verify(ctx, app.PartnerAppID, body, signWebhook("new-secret-0123456789", body)); err != nil {
+		t.Errorf("expected new secret to be accepted, got %v", err)
+	}
+}
+
+func TestWebhookSecretService_Verify_FallsBackToClientSecret(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc, _, app := newTestWebhookSecretService(&now)
+	ctx := context.Background()
+	body := []byte(`{"id":1}`)
+
+	if err := svc.Verify(ctx, app.PartnerAppID, body, signWebhook("client-secret", body)); !errors.Is(err, ErrInvalidWebhookSignature) {
+		t.Errorf("expected rejection without a client secret, got %v", err)
+	}
+
+	svc.WithClientSecret("client-secret")
+	for _, appID := range []string{app.PartnerAppID, ""} {
+		if err := svc.Verify(ctx, appID, body, signWebhook("client-secret", body)); err != nil {
+			t.Errorf("Verify(app=%q): %v", appID, err)
+		}
+	}
+
+	if err := svc.Verify(ctx, app.PartnerAppID, body, ""); !errors.Is(err, ErrInvalidWebhookSignature) {
+		t.Errorf("expected missing signature to be rejected, got %v", err)
+	}
+	if err := svc.Verify(ctx, app.PartnerAppID, []byte(`{"id":2}`), signWebhook("client-secret", body)); !errors.Is(err, ErrInvalidWebhookSignature) {
+		t.Errorf("expected tampered body to be rejected, got %v", err)
+	}
+}
+
+func TestWebhookSecretService_RevokeSecret(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc, _, app := newTestWebhookSecretService(&now)
+	ctx := context.Background()
+	body := []byte(`{"id":1}`)
+
+	secret, _ := svc.AddSecret(ctx, app.ID, "revoked-secret-0123456789", "", nil)
+	if err := svc.RevokeSecret(ctx, uuid.New(), secret.ID); !errors.Is(err, ErrWebhookSecretNotFound) {
+		t.Errorf("expected ErrWebhookSecretNotFound for another app, got %v", err)
+	}
+	if err := svc.RevokeSecret(ctx, app.ID, secret.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := svc.Verify(ctx, app.PartnerAppID, body, signWebhook("revoked-secret-0123456789", body)); !errors.Is(err, ErrInvalidWebhookSignature) {
+		t.Errorf("expected revoked secret to be rejected, got %v", err)
+	}
+	if secrets, _ := svc.ListSecrets(ctx, app.ID); len(secrets) != 0 {
+		t.Errorf("expected revoked secret to be hidden, got %d secrets", len(secrets))
+	}
+}
@@ -2,14 +2,13 @@ package service
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
@@ -34,6 +33,7 @@ type SubscriptionUpdatePayload struct {
 	TrialDays         int     `json:"trial_days"`
 	Test              bool    `json:"test"`
 	CappedAmount      string  `json:"capped_amount"`
+	CurrencyCode      string  `json:"currency_code"`
 	BalanceUsed       float64 `json:"balance_used"`
 	BalanceRemaining  float64 `json:"balance_remaining"`
 	RiskLevel         float64 `json:"risk_level"`
@@ -46,6 +46,26 @@ type SubscriptionUpdatePayload struct {
 	} `json:"line_items"`
 }
 
+// parseSubscriptionPayload parses an app subscription webhook, which Shopify may
+// wrap in an "app_subscription" object
+func parseSubscriptionPayload(raw []byte) (*SubscriptionUpdatePayload, error) {
+	var wrapped struct {
+		AppSubscription *SubscriptionUpdatePayload `json:"app_subscription"`
+	}
+	if err := json.Unmarshal(raw, &wrapped); err != nil {
+		return nil, err
+	}
+	if wrapped.AppSubscription != nil {
+		return wrapped.AppSubscription, nil
+	}
+
+	var payload SubscriptionUpdatePayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
 // AppUninstalledPayload represents the payload for app uninstalled webhooks
 type AppUninstalledPayload struct {
 	ID              int64  `json:"id"`
@@ -55,12 +75,63 @@ type AppUninstalledPayload struct {
 	MyshopifyDomain string `json:"myshopify_domain"`
 }
 
+// ShopUpdatePayload represents the payload for shop update webhooks
+type ShopUpdatePayload struct {
+	ID              int64  `json:"id"`
+	Name            string `json:"name"`
+	MyshopifyDomain string `json:"myshopify_domain"`
+	PlanName        string `json:"plan_name"`
+	PlanDisplayName string `json:"plan_display_name"`
+}
+
+// OneTimePurchasePayload represents the payload for one-time purchase update webhooks
+type OneTimePurchasePayload struct {
+	AppPurchaseOneTime struct {
+		ID     string `json:"admin_graphql_api_id"`
+		Name   string `json:"name"`
+		Status string `json:"status"` // ACTIVE, DECLINED, EXPIRED, PENDING
+	} `json:"app_purchase_one_time"`
+}
+
+// CompliancePayload represents the payload of the mandatory compliance webhooks
+// (customers/data_request, customers/redact and shop/redact)
+type CompliancePayload struct {
+	ShopID     int64  `json:"shop_id"`
+	ShopDomain string `json:"shop_domain"`
+	Customer   *struct {
+		ID int64 `json:"id"`
+	} `json:"customer"`
+	DataRequest *struct {
+		ID int64 `json:"id"`
+	} `json:"data_request"`
+}
+
+// SubscriptionEventPublisher notifies external consumers (e.g. Revenue API
+// customer webhooks) about recorded subscription lifecycle events
+type SubscriptionEventPublisher interface {
+	PublishSubscriptionEvent(ctx context.Context, sub *entity.Subscription, event *entity.SubscriptionEvent) error
+}
+
+// SubscriptionProjector projects a changed subscription into the Revenue API read model
+type SubscriptionProjector interface {
+	ProjectSubscription(ctx context.Context, sub *entity.Subscription) error
+}
+
+// WebhookSignatureVerifier checks the X-Shopify-Hmac-Sha256 signature of a webhook body
+type WebhookSignatureVerifier interface {
+	Verify(ctx context.Context, partnerAppID string, body []byte, signature string) error
+}
+
 // WebhookService handles webhook event processing
 type WebhookService struct {
-	subRepo         repository.SubscriptionRepository
-	subEventRepo    repository.SubscriptionEventRepository
-	appRepo         repository.AppRepository
-	webhookSecrets  map[string]string // app_id -> webhook secret
+	subRepo           repository.SubscriptionRepository
+	subEventRepo      repository.SubscriptionEventRepository
+	eventPublishers   []SubscriptionEventPublisher
+	projector         SubscriptionProjector
+	appRepo           repository.AppRepository
+	signatureVerifier WebhookSignatureVerifier
+	shopDataRepo      repository.ShopDataRepository
+	complianceRepo    repository.ComplianceRequestRepository
 }
 
 // NewWebhookService creates a new webhook service
@@ -69,9 +140,8 @@ func NewWebhookService(
 	appRepo repository.AppRepository,
 ) *WebhookService {
 	return &WebhookService{
-		subRepo:        subRepo,
-		appRepo:        appRepo,
-		webhookSecrets: make(map[string]string),
+		subRepo: subRepo,
+		appRepo: appRepo,
 	}
 }
 
@@ -81,30 +151,46 @@ func (s *WebhookService) WithSubscriptionEventRepo(repo repository.SubscriptionE
 	return s
 }
 
-// RegisterWebhookSecret registers a webhook secret for HMAC validation
-func (s *WebhookService) RegisterWebhookSecret(appID, secret string) {
-	s.webhookSecrets[appID] = secret
+// WithEventPublisher adds a publisher notified of every recorded lifecycle event
+func (s *WebhookService) WithEventPublisher(publisher SubscriptionEventPublisher) *WebhookService {
+	s.eventPublishers = append(s.eventPublishers, publisher)
+	return s
 }
 
-// ValidateHMAC validates the webhook HMAC signature
-func (s *WebhookService) ValidateHMAC(appID string, body []byte, signature string) bool {
-	secret, ok := s.webhookSecrets[appID]
-	if !ok {
-		log.Printf("No webhook secret registered for app %s", appID)
-		return false
-	}
+// WithProjector projects every subscription a webhook changes, ahead of the next sync
+func (s *WebhookService) WithProjector(projector SubscriptionProjector) *WebhookService {
+	s.projector = projector
+	return s
+}
 
-	mac := hmac.New(sha256.New, []byte(secret))
-	mac.Write(body)
-	expectedMAC := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+// WithSignatureVerifier sets the verifier webhook signatures are checked with
+func (s *WebhookService) WithSignatureVerifier(verifier WebhookSignatureVerifier) *WebhookService {
+	s.signatureVerifier = verifier
+	return s
+}
 
-	return hmac.Equal([]byte(expectedMAC), []byte(signature))
+// WithShopData enables shop/update and the compliance topics. Compliance webhooks
+// fail (and are retried) until both repositories are set.
+func (s *WebhookService) WithShopData(shopDataRepo repository.ShopDataRepository, complianceRepo repository.ComplianceRequestRepository) *WebhookService {
+	s.shopDataRepo = shopDataRepo
+	s.complianceRepo = complianceRepo
+	return s
+}
+
+// ValidateHMAC validates the webhook HMAC signature for a Shopify app GID.
+// Without a signature verifier every webhook is rejected.
+func (s *WebhookService) ValidateHMAC(ctx context.Context, appID string, body []byte, signature string) error {
+	if s.signatureVerifier == nil {
+		log.Printf("No webhook signature verifier configured")
+		return ErrInvalidWebhookSignature
+	}
+	return s.signatureVerifier.Verify(ctx, appID, body, signature)
 }
 
 // ProcessSubscriptionUpdate handles subscription status change webhooks
 func (s *WebhookService) ProcessSubscriptionUpdate(ctx context.Context, event WebhookEvent) error {
-	var payload SubscriptionUpdatePayload
-	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+	payload, err := parseSubscriptionPayload(event.Payload)
+	if err != nil {
 		return fmt.Errorf("failed to parse subscription update payload: %w", err)
 	}
 
@@ -139,10 +225,11 @@ func (s *WebhookService) ProcessSubscriptionUpdate(ctx context.Context, event We
 	if err := s.subRepo.Upsert(ctx, sub); err != nil {
 		return fmt.Errorf("failed to update subscription: %w", err)
 	}
+	s.projectSubscription(ctx, sub)
 
-	// Record lifecycle event if repository is configured
-	if s.subEventRepo != nil && oldStatus != sub.Status {
-		subEvent := entity.NewSubscriptionEvent(
+	// Record lifecycle event
+	if oldStatus != sub.Status {
+		s.recordSubscriptionEvent(ctx, sub, entity.NewSubscriptionEvent(
 			sub.ID,
 			oldStatus,
 			sub.Status,
@@ -150,11 +237,7 @@ func (s *WebhookService) ProcessSubscriptionUpdate(ctx context.Context, event We
 			sub.RiskState,
 			"webhook",
 			"",
-		)
-		if err := s.subEventRepo.Create(ctx, subEvent); err != nil {
-			log.Printf("Failed to record subscription event: %v", err)
-			// Don't fail the webhook processing for event recording failures
-		}
+		))
 	}
 
 	log.Printf("Subscription %s updated: %s -> %s", payload.ID, oldStatus, payload.Status)
@@ -199,22 +282,18 @@ func (s *WebhookService) ProcessAppUninstalled(ctx context.Context, event Webhoo
 			log.Printf("Failed to update subscription for %s: %v", payload.MyshopifyDomain, err)
 			continue
 		}
+		s.projectSubscription(ctx, sub)
 
 		// Record lifecycle event
-		if s.subEventRepo != nil {
-			subEvent := entity.NewSubscriptionEvent(
-				sub.ID,
-				oldStatus,
-				"UNINSTALLED",
-				oldRiskState,
-				valueobject.RiskStateChurned,
-				"app_uninstalled",
-				"Shop uninstalled the app",
-			)
-			if err := s.subEventRepo.Create(ctx, subEvent); err != nil {
-				log.Printf("Failed to record subscription event: %v", err)
-			}
-		}
+		s.recordSubscriptionEvent(ctx, sub, entity.NewSubscriptionEvent(
+			sub.ID,
+			oldStatus,
+			"UNINSTALLED",
+			oldRiskState,
+			valueobject.RiskStateChurned,
+			"app_uninstalled",
+			"Shop uninstalled the app",
+		))
 
 		log.Printf("Subscription soft-deleted for shop %s", payload.MyshopifyDomain)
 	}
@@ -265,11 +344,12 @@ func (s *WebhookService) ProcessBillingFailure(ctx context.Context, event Webhoo
 	if err := s.subRepo.Upsert(ctx, sub); err != nil {
 		return fmt.Errorf("failed to update subscription: %w", err)
 	}
+	s.projectSubscription(ctx, sub)
 
 	// Record billing failure event
-	if s.subEventRepo != nil && oldRiskState != sub.RiskState {
+	if oldRiskState != sub.RiskState {
 		reason := fmt.Sprintf("Billing failure: %s - %s", payload.ErrorCode, payload.ErrorMessage)
-		subEvent := entity.NewSubscriptionEvent(
+		s.recordSubscriptionEvent(ctx, sub, entity.NewSubscriptionEvent(
 			sub.ID,
 			sub.Status,
 			sub.Status,
@@ -277,10 +357,7 @@ func (s *WebhookService) ProcessBillingFailure(ctx context.Context, event Webhoo
 			sub.RiskState,
 			"billing_failure",
 			reason,
-		)
-		if err := s.subEventRepo.Create(ctx, subEvent); err != nil {
-			log.Printf("Failed to record billing failure event: %v", err)
-		}
+		))
 	}
 
 	log.Printf("Subscription %s risk escalated: %s -> %s due to billing failure",
@@ -288,6 +365,245 @@ func (s *WebhookService) ProcessBillingFailure(ctx context.Context, event Webhoo
 	return nil
 }
 
+// ProcessApproachingCappedAmount handles usage cap warnings, sent when a
+// subscription's usage charges reach 90% of its capped amount
+func (s *WebhookService) ProcessApproachingCappedAmount(ctx context.Context, event WebhookEvent) error {
+	payload, err := parseSubscriptionPayload(event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to parse capped amount payload: %w", err)
+	}
+
+	log.Printf("Processing capped amount warning: %s balance_used=%.2f capped_amount=%s",
+		payload.ID, payload.BalanceUsed, payload.CappedAmount)
+
+	sub, err := s.subRepo.FindByShopifyGID(ctx, payload.ID)
+	if err != nil {
+		log.Printf("Subscription %s not found: %v", payload.ID, err)
+		return nil
+	}
+
+	cappedAmount := payload.CappedAmount
+	if payload.CurrencyCode != "" {
+		cappedAmount += " " + payload.CurrencyCode
+	}
+	s.recordSubscriptionEvent(ctx, sub, entity.NewSubscriptionEvent(
+		sub.ID,
+		sub.Status,
+		sub.Status,
+		sub.RiskState,
+		sub.RiskState,
+		"usage_cap_warning",
+		fmt.Sprintf("Usage charges reached %.2f of the %s capped amount", payload.BalanceUsed, cappedAmount),
+	))
+
+	return nil
+}
+
+// ProcessShopUpdate refreshes the shop name and Shopify plan recorded for a shop
+func (s *WebhookService) ProcessShopUpdate(ctx context.Context, event WebhookEvent) error {
+	var payload ShopUpdatePayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to parse shop update payload: %w", err)
+	}
+
+	if s.shopDataRepo == nil {
+		log.Printf("Shop data repository not configured, ignoring shop/update")
+		return nil
+	}
+
+	domain := payload.MyshopifyDomain
+	if domain == "" {
+		domain = event.ShopID
+	}
+	plan := payload.PlanDisplayName
+	if plan == "" {
+		plan = payload.PlanName
+	}
+
+	log.Printf("Processing shop update: shop=%s name=%q plan=%q", domain, payload.Name, plan)
+
+	apps, err := s.appRepo.FindAllByPartnerAppID(ctx, event.AppID)
+	if err != nil || len(apps) == 0 {
+		log.Printf("Failed to find app for %s: %v", event.AppID, err)
+		return nil
+	}
+
+	updated, err := s.shopDataRepo.UpdateShopDetails(ctx, appIDsOf(apps), domain, payload.Name, plan)
+	if err != nil {
+		return fmt.Errorf("failed to update shop details: %w", err)
+	}
+
+	if updated > 0 {
+		for _, app := range apps {
+			sub, err := s.subRepo.FindByAppIDAndDomain(ctx, app.ID, domain)
+			if err != nil {
+				continue
+			}
+			s.projectSubscription(ctx, sub)
+		}
+	}
+
+	log.Printf("Shop %s updated: %d subscriptions renamed", domain, updated)
+	return nil
+}
+
+// ProcessOneTimePurchaseUpdate records a one-time purchase status change on the
+// shop's subscription timeline. The charge itself reaches the ledger with the next sync.
+func (s *WebhookService) ProcessOneTimePurchaseUpdate(ctx context.Context, event WebhookEvent) error {
+	var payload OneTimePurchasePayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to parse one-time purchase payload: %w", err)
+	}
+	purchase := payload.AppPurchaseOneTime
+
+	log.Printf("Processing one-time purchase update: %s -> status=%s shop=%s", purchase.ID, purchase.Status, event.ShopID)
+
+	apps, err := s.appRepo.FindAllByPartnerAppID(ctx, event.AppID)
+	if err != nil {
+		log.Printf("Failed to find app for %s: %v", event.AppID, err)
+		return nil
+	}
+
+	for _, app := range apps {
+		sub, err := s.subRepo.FindByAppIDAndDomain(ctx, app.ID, event.ShopID)
+		if err != nil {
+			continue // Shops can buy one-time purchases without a subscription
+		}
+
+		s.recordSubscriptionEvent(ctx, sub, entity.NewSubscriptionEvent(
+			sub.ID,
+			sub.Status,
+			sub.Status,
+			sub.RiskState,
+			sub.RiskState,
+			"one_time_purchase",
+			fmt.Sprintf("One-time purchase %q %s", purchase.Name, purchase.Status),
+		))
+	}
+
+	return nil
+}
+
+// ProcessCustomerDataRequest handles the mandatory customers/data_request topic.
+// LedgerGuard stores nothing about a shop's customers, so the request is only recorded.
+func (s *WebhookService) ProcessCustomerDataRequest(ctx context.Context, event WebhookEvent) error {
+	return s.recordCustomerCompliance(ctx, event, "No customer data held; nothing to report")
+}
+
+// ProcessCustomerRedact handles the mandatory customers/redact topic.
+// LedgerGuard stores nothing about a shop's customers, so the request is only recorded.
+func (s *WebhookService) ProcessCustomerRedact(ctx context.Context, event WebhookEvent) error {
+	return s.recordCustomerCompliance(ctx, event, "No customer data held; nothing to redact")
+}
+
+func (s *WebhookService) recordCustomerCompliance(ctx context.Context, event WebhookEvent, result string) error {
+	record, err := s.parseComplianceRequest(event)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Processing %s: shop=%s", event.Topic, record.ShopDomain)
+
+	record.Result = result
+	record.CompletedAt = time.Now().UTC()
+	if err := s.complianceRepo.Create(ctx, record); err != nil {
+		return fmt.Errorf("failed to record compliance request: %w", err)
+	}
+	return nil
+}
+
+// ProcessShopRedact handles the mandatory shop/redact topic, sent 48 hours after a
+// shop uninstalls the app. The shop's subscriptions, their events and its read model
+// rows are deleted; its transactions keep their amounts under an anonymous domain,
+// so revenue history stays intact.
+func (s *WebhookService) ProcessShopRedact(ctx context.Context, event WebhookEvent) error {
+	record, err := s.parseComplianceRequest(event)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Processing shop redact: shop=%s app=%s", record.ShopDomain, event.AppID)
+
+	if event.AppID == "" {
+		return fmt.Errorf("cannot redact shop %s without an app ID", record.ShopDomain)
+	}
+
+	apps, err := s.appRepo.FindAllByPartnerAppID(ctx, event.AppID)
+	if err != nil {
+		return fmt.Errorf("failed to find apps for %s: %w", event.AppID, err)
+	}
+
+	now := time.Now().UTC()
+	if len(apps) == 0 {
+		record.Result = "No data held for the shop"
+	} else {
+		placeholder := redactedShopDomain()
+		redaction, err := s.shopDataRepo.RedactShop(ctx, appIDsOf(apps), event.AppID, record.ShopDomain, placeholder, now)
+		if err != nil {
+			return fmt.Errorf("failed to redact shop %s: %w", record.ShopDomain, err)
+		}
+
+		record.TransactionsRedacted = redaction.TransactionsRedacted
+		record.SubscriptionsDeleted = redaction.SubscriptionsDeleted
+		record.EventsDeleted = redaction.EventsDeleted
+		record.Result = fmt.Sprintf("Shop data deleted; transactions anonymized as %s", placeholder)
+	}
+
+	record.CompletedAt = now
+	if err := s.complianceRepo.Create(ctx, record); err != nil {
+		return fmt.Errorf("failed to record compliance request: %w", err)
+	}
+
+	log.Printf("Shop %s redacted: %d transactions anonymized, %d subscriptions deleted",
+		record.ShopDomain, record.TransactionsRedacted, record.SubscriptionsDeleted)
+	return nil
+}
+
+// parseComplianceRequest parses a compliance webhook into an unsaved record
+func (s *WebhookService) parseComplianceRequest(event WebhookEvent) (*entity.ComplianceRequest, error) {
+	if s.shopDataRepo == nil || s.complianceRepo == nil {
+		return nil, fmt.Errorf("compliance repositories not configured for %s", event.Topic)
+	}
+
+	var payload CompliancePayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse %s payload: %w", event.Topic, err)
+	}
+
+	domain := payload.ShopDomain
+	if domain == "" {
+		domain = event.ShopID
+	}
+	if domain == "" {
+		return nil, fmt.Errorf("%s payload has no shop domain", event.Topic)
+	}
+
+	record := entity.NewComplianceRequest(event.Topic, event.AppID, payload.ShopID, domain, event.Timestamp)
+	if payload.Customer != nil {
+		customerID := payload.Customer.ID
+		record.CustomerID = &customerID
+	}
+	if payload.DataRequest != nil {
+		dataRequestID := payload.DataRequest.ID
+		record.DataRequestID = &dataRequestID
+	}
+	return record, nil
+}
+
+// redactedShopDomain returns a random placeholder for a redacted shop's domain,
+// which keeps its transactions grouped without being traceable to the shop
+func redactedShopDomain() string {
+	return "redacted-" + strings.ReplaceAll(uuid.New().String(), "-", "")[:16] + ".invalid"
+}
+
+func appIDsOf(apps []*entity.App) []uuid.UUID {
+	ids := make([]uuid.UUID, len(apps))
+	for i, app := range apps {
+		ids[i] = app.ID
+	}
+	return ids
+}
+
 // ProcessEvent routes webhook events to appropriate handlers
 func (s *WebhookService) ProcessEvent(ctx context.Context, event WebhookEvent) error {
 	switch event.Topic {
@@ -297,12 +613,51 @@ func (s *WebhookService) ProcessEvent(ctx context.Context, event WebhookEvent) e
 		return s.ProcessAppUninstalled(ctx, event)
 	case "subscription_billing_attempts/failure":
 		return s.ProcessBillingFailure(ctx, event)
+	case "app_subscriptions/approaching_capped_amount":
+		return s.ProcessApproachingCappedAmount(ctx, event)
+	case "shop/update":
+		return s.ProcessShopUpdate(ctx, event)
+	case "app_purchases_one_time/update":
+		return s.ProcessOneTimePurchaseUpdate(ctx, event)
+	case entity.ComplianceTopicCustomersDataRequest:
+		return s.ProcessCustomerDataRequest(ctx, event)
+	case entity.ComplianceTopicCustomersRedact:
+		return s.ProcessCustomerRedact(ctx, event)
+	case entity.ComplianceTopicShopRedact:
+		return s.ProcessShopRedact(ctx, event)
 	default:
 		log.Printf("Unhandled webhook topic: %s", event.Topic)
 		return nil
 	}
 }
 
+// recordSubscriptionEvent stores a lifecycle event and notifies the publisher.
+// Failures are logged only; they must not fail webhook processing.
+func (s *WebhookService) recordSubscriptionEvent(ctx context.Context, sub *entity.Subscription, event *entity.SubscriptionEvent) {
+	if s.subEventRepo != nil {
+		if err := s.subEventRepo.Create(ctx, event); err != nil {
+			log.Printf("Failed to record subscription event: %v", err)
+		}
+	}
+
+	for _, publisher := range s.eventPublishers {
+		if err := publisher.PublishSubscriptionEvent(ctx, sub, event); err != nil {
+			log.Printf("Failed to publish subscription event: %v", err)
+		}
+	}
+}
+
+// projectSubscription updates the read model before events are published, so consumers
+// reacting to them read the new state. Failures are repaired by the next sync.
+func (s *WebhookService) projectSubscription(ctx context.Context, sub *entity.Subscription) {
+	if s.projector == nil {
+		return
+	}
+	if err := s.projector.ProjectSubscription(ctx, sub); err != nil {
+		log.Printf("Failed to project subscription %s: %v", sub.ShopifyGID, err)
+	}
+}
+
 // Helper to get app internal ID from Shopify GID
 func (s *WebhookService) getAppByPartnerID(ctx context.Context, partnerAppID string) (*entity.App, error) {
 	apps, err := s.appRepo.FindAllByPartnerAppID(ctx, partnerAppID)
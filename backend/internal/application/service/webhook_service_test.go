@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+type mockWebhookSubscriptionRepo struct {
+	mockRecognitionSubscriptionRepo
+}
+
+func (m *mockWebhookSubscriptionRepo) FindByShopifyGID(ctx context.Context, shopifyGID string) (*entity.Subscription, error) {
+	for _, s := range m.subscriptions {
+		if s.ShopifyGID == shopifyGID {
+			return s, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockWebhookSubscriptionRepo) FindByAppIDAndDomain(ctx context.Context, appID uuid.UUID, myshopifyDomain string) (*entity.Subscription, error) {
+	for _, s := range m.subscriptions {
+		if s.AppID == appID && s.MyshopifyDomain == myshopifyDomain {
+			return s, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+type mockShopDataRepo struct {
+	updatedName string
+	updatedPlan string
+	redacted    []string
+	placeholder string
+	appIDs      []uuid.UUID
+}
+
+func (m *mockShopDataRepo) UpdateShopDetails(ctx context.Context, appIDs []uuid.UUID, myshopifyDomain, shopName, shopPlan string) (int64, error) {
+	m.appIDs = appIDs
+	m.updatedName = shopName
+	m.updatedPlan = shopPlan
+	return 1, nil
+}
+
+func (m *mockShopDataRepo) RedactShop(ctx context.Context, appIDs []uuid.UUID, appGID, myshopifyDomain, placeholderDomain string, redactedAt time.Time) (*repository.ShopRedaction, error) {
+	m.appIDs = appIDs
+	m.redacted = append(m.redacted, myshopifyDomain)
+	m.placeholder = placeholderDomain
+	return &repository.ShopRedaction{TransactionsRedacted: 12, SubscriptionsDeleted: 1, EventsDeleted: 3}, nil
+}
+
+type mockComplianceRepo struct {
+	requests []*entity.ComplianceRequest
+}
+
+func (m *mockComplianceRepo) Create(ctx context.Context, request *entity.ComplianceRequest) error {
+	m.requests = append(m.requests, request)
+	return nil
+}
+
+func (m *mockComplianceRepo) FindByShopDomain(ctx context.Context, shopDomain string) ([]*entity.ComplianceRequest, error) {
+	return m.requests, nil
+}
+
+type mockSubscriptionProjector struct {
+	projected []*entity.Subscription
+}
+
+func (m *mockSubscriptionProjector) ProjectSubscription(ctx context.Context, sub *entity.Subscription) error {
+	m.projected = append(m.projected, sub)
+	return nil
+}
+
+func newTestWebhookService() (*WebhookService, *mockWebhookSubscriptionRepo, *mockRecognitionEventRepo, *mockShopDataRepo, *mockComplianceRepo, *entity.App) {
+	app := &entity.App{ID: uuid.New(), PartnerAppID: testAppGID}
+	sub := &entity.Subscription{
+		ID:              uuid.New(),
+		AppID:           app.ID,
+		ShopifyGID:      "gid://shopify/AppSubscription/1",
+		MyshopifyDomain: "shop.myshopify.com",
+		ShopName:        "Old Name",
+		Status:          "ACTIVE",
+		RiskState:       valueobject.RiskStateSafe,
+	}
+	subRepo := &mockWebhookSubscriptionRepo{}
+	subRepo.subscriptions = []*entity.Subscription{sub}
+	eventRepo := &mockRecognitionEventRepo{}
+	shopDataRepo := &mockShopDataRepo{}
+	complianceRepo := &mockComplianceRepo{}
+
+	svc := NewWebhookService(subRepo, &mockAppRepoForSync{app: app}).
+		WithSubscriptionEventRepo(eventRepo).
+		WithShopData(shopDataRepo, complianceRepo)
+	return svc, subRepo, eventRepo, shopDataRepo, complianceRepo, app
+}
+
+func TestWebhookService_ProcessApproachingCappedAmount(t *testing.T) {
+	svc, _, eventRepo, _, _, _ := newTestWebhookService()
+
+	err := svc.ProcessEvent(context.Background(), WebhookEvent{
+		Topic: "app_subscriptions/approaching_capped_amount",
+		AppID: testAppGID,
+		Payload: json.RawMessage(`{"app_subscription":{"admin_graphql_api_id":"gid://shopify/AppSubscription/1",
+			"balance_used":92.5,"capped_amount":"100.0","currency_code":"USD","status":"ACTIVE"}}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(eventRepo.events) != 1 {
+		t.Fatalf("expected 1 recorded event, got %d", len(eventRepo.events))
+	}
+	event := eventRepo.events[0]
+	if event.EventType != "usage_cap_warning" || event.FromStatus != event.ToStatus {
+		t.Errorf("expected a usage_cap_warning without a status change, got %+v", event)
+	}
+	if event.Reason != "Usage charges reached 92.50 of the 100.0 USD capped amount" {
+		t.Errorf("Reason = %q", event.Reason)
+	}
+}
+
+func TestWebhookService_ProcessShopUpdate(t *testing.T) {
+	svc, subRepo, _, shopDataRepo, _, app := newTestWebhookService()
+	projector := &mockSubscriptionProjector{}
+	svc.WithProjector(projector)
+
+	err := svc.ProcessEvent(context.Background(), WebhookEvent{
+		Topic:   "shop/update",
+		ShopID:  "shop.myshopify.com",
+		AppID:   testAppGID,
+		Payload: json.RawMessage(`{"id":1,"name":"New Name","myshopify_domain":"shop.myshopify.com","plan_name":"advanced","plan_display_name":"Advanced"}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if shopDataRepo.updatedName != "New Name" || shopDataRepo.updatedPlan != "Advanced" {
+		t.Errorf("expected name and plan display name to be stored, got %q, %q", shopDataRepo.updatedName, shopDataRepo.updatedPlan)
+	}
+	if len(shopDataRepo.appIDs) != 1 || shopDataRepo.appIDs[0] != app.ID {
+		t.Errorf("expected update scoped to the app, got %v", shopDataRepo.appIDs)
+	}
+	if len(projector.projected) != 1 || projector.projected[0] != subRepo.subscriptions[0] {
+		t.Errorf("expected the shop's subscription to be projected, got %d", len(projector.projected))
+	}
+}
+
+func TestWebhookService_ProcessShopRedact(t *testing.T) {
+	svc, _, _, shopDataRepo, complianceRepo, app := newTestWebhookService()
+	triggeredAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	event := WebhookEvent{
+		Topic:     "shop/redact",
+		ShopID:    "shop.myshopify.com",
+		Payload:   json.RawMessage(`{"shop_id":954889,"shop_domain":"shop.myshopify.com"}`),
+		Timestamp: triggeredAt,
+	}
+
+	if err := svc.ProcessEvent(context.Background(), event); err == nil {
+		t.Error("expected an error without an app ID")
+	}
+
+	event.AppID = testAppGID
+	if err := svc.ProcessEvent(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(shopDataRepo.redacted) != 1 || shopDataRepo.redacted[0] != "shop.myshopify.com" || shopDataRepo.appIDs[0] != app.ID {
+		t.Errorf("expected the shop to be redacted for the app, got %v", shopDataRepo.redacted)
+	}
+	if !strings.HasPrefix(shopDataRepo.placeholder, "redacted-") || strings.Contains(shopDataRepo.placeholder, "shop") {
+		t.Errorf("expected an anonymous placeholder domain, got %q", shopDataRepo.placeholder)
+	}
+
+	if len(complianceRepo.requests) != 1 {
+		t.Fatalf("expected 1 compliance record, got %d", len(complianceRepo.requests))
+	}
+	record := complianceRepo.requests[0]
+	if record.ShopID != 954889 || record.TransactionsRedacted != 12 || record.SubscriptionsDeleted != 1 || record.EventsDeleted != 3 {
+		t.Errorf("unexpected compliance record %+v", record)
+	}
+	if !record.RequestedAt.Equal(triggeredAt) || record.CompletedAt.IsZero() {
+		t.Errorf("expected requested and completed times, got %v and %v", record.RequestedAt, record.CompletedAt)
+	}
+}
+
+func TestWebhookService_ProcessCustomerCompliance_RecordsRequest(t *testing.T) {
+	svc, _, _, shopDataRepo, complianceRepo, _ := newTestWebhookService()
+	ctx := context.Background()
+
+	for _, topic := range []string{"customers/data_request", "customers/redact"} {
+		err := svc.ProcessEvent(ctx, WebhookEvent{
+			Topic:   topic,
+			AppID:   testAppGID,
+			Payload: json.RawMessage(`{"shop_id":954889,"shop_domain":"shop.myshopify.com","customer":{"id":191167},"data_request":{"id":9999}}`),
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", topic, err)
+		}
+	}
+
+	if len(complianceRepo.requests) != 2 {
+		t.Fatalf("expected 2 compliance records, got %d", len(complianceRepo.requests))
+	}
+	for _, record := range complianceRepo.requests {
+		if record.CustomerID == nil || *record.CustomerID != 191167 || record.Result == "" {
+			t.Errorf("unexpected compliance record %+v", record)
+		}
+	}
+	if len(shopDataRepo.redacted) != 0 {
+		t.Error("customer requests must not redact shop data")
+	}
+
+	unconfigured := NewWebhookService(&mockWebhookSubscriptionRepo{}, &mockAppRepoForSync{})
+	if err := unconfigured.ProcessEvent(ctx, WebhookEvent{Topic: "customers/redact", Payload: json.RawMessage(`{"shop_domain":"shop.myshopify.com"}`)}); err == nil {
+		t.Error("expected an error so the request is retried once compliance storage is configured")
+	}
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+// personalWorkspaceName names the workspace every user gets on first login
+const personalWorkspaceName = "Personal workspace"
+
+var (
+	// ErrWorkspaceNotFound is returned when the workspace does not exist
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	// ErrWorkspaceMemberNotFound is returned when the user to manage is not a member of the workspace
+	ErrWorkspaceMemberNotFound = errors.New("workspace member not found")
+	// ErrWorkspaceInvitationNotFound is returned when the invitation does not belong to the workspace
+	ErrWorkspaceInvitationNotFound = errors.New("workspace invitation not found")
+	// ErrInvalidWorkspaceInvitation is returned when an invitation code matches no open invitation
+	ErrInvalidWorkspaceInvitation = errors.New("invalid or expired invitation")
+	// ErrWorkspaceInvitationEmailMismatch is returned when an invitation is accepted by a user with another email
+	ErrWorkspaceInvitationEmailMismatch = errors.New("invitation was sent to a different email address")
+	// ErrAlreadyWorkspaceMember is returned when inviting or adding an existing member
+	ErrAlreadyWorkspaceMember = errors.New("user is already a member of this workspace")
+	// ErrInsufficientWorkspaceRole is returned when a member manages other members without ADMIN
+	ErrInsufficientWorkspaceRole = errors.New("insufficient workspace role")
+	// ErrWorkspaceRoleNotAllowed is returned when a member grants or changes a role above their own
+	ErrWorkspaceRoleNotAllowed = errors.New("only owners can grant, change or remove the OWNER role")
+	// ErrLastWorkspaceOwner is returned when a change would leave the workspace without an owner
+	ErrLastWorkspaceOwner = errors.New("workspace must keep at least one owner")
+)
+
+// UserWorkspace is a workspace together with the user's role in it
+type UserWorkspace struct {
+	Workspace *entity.Workspace
+	Role      valueobject.Role
+}
+
+// WorkspaceService manages team workspaces: their members, member roles and
+// email invitations. A workspace owns a partner account, so every member
+// sees the same apps, limited by their role.
+type WorkspaceService struct {
+	workspaceRepo  repository.WorkspaceRepository
+	memberRepo     repository.WorkspaceMemberRepository
+	invitationRepo repository.WorkspaceInvitationRepository
+	emailSender    EmailSender // Optional: without it invitation codes are only returned to the inviter
+	now            func() time.Time
+}
+
+// NewWorkspaceService creates a new WorkspaceService
+func NewWorkspaceService(
+	workspaceRepo repository.WorkspaceRepository,
+	memberRepo repository.WorkspaceMemberRepository,
+	invitationRepo repository.WorkspaceInvitationRepository,
+) *WorkspaceService {
+	return &WorkspaceService{
+		workspaceRepo:  workspaceRepo,
+		memberRepo:     memberRepo,
+		invitationRepo: invitationRepo,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// WithEmailSender emails invitation codes to invitees
+func (s *WorkspaceService) WithEmailSender(sender EmailSender) *WorkspaceService {
+	s.emailSender = sender
+	return s
+}
+
+// ResolveMembership returns the membership a request by the user acts under.
+// Without a workspace ID it is the user's oldest membership, creating a
+// personal workspace owned by the user if they have none. With one it is
+// the user's membership of that workspace, or nil if they are not a member.
+func (s *WorkspaceService) ResolveMembership(ctx context.Context, user *entity.User, workspaceID *uuid.UUID) (*entity.WorkspaceMember, error) {
+	if workspaceID != nil {
+		return s.memberRepo.Find(ctx, *workspaceID, user.ID)
+	}
+
+	memberships, err := s.memberRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(memberships) > 0 {
+		return memberships[0], nil
+	}
+
+	_, owner, err := s.createWorkspace(ctx, user, personalWorkspaceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create personal workspace: %w", err)
+	}
+	return owner, nil
+}
+
+// ListWorkspaces returns the workspaces the user is a member of, with their role in each
+func (s *WorkspaceService) ListWorkspaces(ctx context.Context, userID uuid.UUID) ([]UserWorkspace, error) {
+	workspaces, err := s.workspaceRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := s.memberRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[uuid.UUID]valueobject.Role, len(memberships))
+	for _, m := range memberships {
+		roles[m.WorkspaceID] = m.Role
+	}
+
+	result := make([]UserWorkspace, 0, len(workspaces))
+	for _, w := range workspaces {
+		result = append(result, UserWorkspace{Workspace: w, Role: roles[w.ID]})
+	}
+	return result, nil
+}
+
+// CreateWorkspace creates a workspace owned by the user
+func (s *WorkspaceService) CreateWorkspace(ctx context.Context, user *entity.User, name string) (*entity.Workspace, error) {
+	workspace, _, err := s.createWorkspace(ctx, user, name)
+	return workspace, err
+}
+
+// GetWorkspace returns a workspace by ID
+func (s *WorkspaceService) GetWorkspace(ctx context.Context, workspaceID uuid.UUID) (*entity.Workspace, error) {
+	workspace, err := s.workspaceRepo.FindByID(ctx, workspaceID)
+	if err != nil {
+		return nil, ErrWorkspaceNotFound
+	}
+	return workspace, nil
+}
+
+// RenameWorkspace changes a workspace's name
+func (s *WorkspaceService) RenameWorkspace(ctx context.Context, workspaceID uuid.UUID, name string) (*entity.Workspace, error) {
+	workspace, err := s.GetWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := workspace.Rename(name, s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.workspaceRepo.Update(ctx, workspace); err != nil {
+		return nil, fmt.Errorf("failed to save workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// ListMembers returns the workspace's members
+func (s *WorkspaceService) ListMembers(ctx context.Context, workspaceID uuid.UUID) ([]*entity.WorkspaceMember, error) {
+	members, err := s.memberRepo.FindByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if members == nil {
+		members = []*entity.WorkspaceMember{}
+	}
+	return members, nil
+}
+
+// UpdateMemberRole changes a member's role. Only owners may grant OWNER or
+// change an owner's role, and the last owner cannot be demoted.
+func (s *WorkspaceService) UpdateMemberRole(ctx context.Context, actor *entity.WorkspaceMember, userID uuid.UUID, role valueobject.Role) (*entity.WorkspaceMember, error) {
+	if !role.IsValid() {
+		return nil, entity.ErrInvalidWorkspaceRole
+	}
+	if !actor.Role.AtLeast(valueobject.RoleAdmin) {
+		return nil, ErrInsufficientWorkspaceRole
+	}
+
+	member, err := s.findMember(ctx, actor.WorkspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if (role == valueobject.RoleOwner || member.Role == valueobject.RoleOwner) && actor.Role != valueobject.RoleOwner {
+		return nil, ErrWorkspaceRoleNotAllowed
+	}
+	if member.Role == role {
+		return member, nil
+	}
+	if member.Role == valueobject.RoleOwner {
+		if err := s.ensureAnotherOwner(ctx, actor.WorkspaceID); err != nil {
+			return nil, err
+		}
+	}
+
+	member.Role = role
+	member.UpdatedAt = s.now()
+	if err := s.memberRepo.Update(ctx, member); err != nil {
+		return nil, fmt.Errorf("failed to save workspace member: %w", err)
+	}
+	return member, nil
+}
+
+// RemoveMember removes a member from the workspace. Members may always
+// remove themselves; removing an owner takes an owner, and the last owner
+// cannot leave.
+func (s *WorkspaceService) RemoveMember(ctx context.Context, actor *entity.WorkspaceMember, userID uuid.UUID) error {
+	member, err := s.findMember(ctx, actor.WorkspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if member.UserID != actor.UserID {
+		if !actor.Role.AtLeast(valueobject.RoleAdmin) {
+			return ErrInsufficientWorkspaceRole
+		}
+		if member.Role == valueobject.RoleOwner && actor.Role != valueobject.RoleOwner {
+			return ErrWorkspaceRoleNotAllowed
+		}
+	}
+	if member.Role == valueobject.RoleOwner {
+		if err := s.ensureAnotherOwner(ctx, actor.WorkspaceID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.memberRepo.Delete(ctx, actor.WorkspaceID, userID); err != nil {
+		return ErrWorkspaceMemberNotFound
+	}
+	return nil
+}
+
+// Invite creates an invitation for an email address and emails its code.
+// Only owners may invite owners. The invitation is kept if the email fails;
+// the inviter can share the code returned with it instead.
+func (s *WorkspaceService) Invite(ctx context.Context, actor *entity.WorkspaceMember, inviterEmail, email string, role valueobject.Role) (*entity.WorkspaceInvitation, error) {
+	invitation, err := entity.NewWorkspaceInvitation(actor.WorkspaceID, email, role, actor.UserID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if !actor.Role.AtLeast(valueobject.RoleAdmin) {
+		return nil, ErrInsufficientWorkspaceRole
+	}
+	if role == valueobject.RoleOwner && actor.Role != valueobject.RoleOwner {
+		return nil, ErrWorkspaceRoleNotAllowed
+	}
+
+	members, err := s.memberRepo.FindByWorkspaceID(ctx, actor.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		if m.Email == invitation.Email {
+			return nil, ErrAlreadyWorkspaceMember
+		}
+	}
+
+	workspace, err := s.GetWorkspace(ctx, actor.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("failed to save workspace invitation: %w", err)
+	}
+
+	if s.emailSender != nil {
+		if err := s.sendInvitation(ctx, workspace, inviterEmail, invitation); err != nil {
+			log.Printf("WorkspaceService: failed to email invitation %s: %v", invitation.ID, err)
+		}
+	}
+	return invitation, nil
+}
+
+// ListInvitations returns the workspace's invitations, newest first
+func (s *WorkspaceService) ListInvitations(ctx context.Context, workspaceID uuid.UUID) ([]*entity.WorkspaceInvitation, error) {
+	invitations, err := s.invitationRepo.FindByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if invitations == nil {
+		invitations = []*entity.WorkspaceInvitation{}
+	}
+	return invitations, nil
+}
+
+// RevokeInvitation cancels an open invitation of the workspace
+func (s *WorkspaceService) RevokeInvitation(ctx context.Context, workspaceID, id uuid.UUID) (*entity.WorkspaceInvitation, error) {
+	invitation, err := s.invitationRepo.FindByID(ctx, workspaceID, id)
+	if err != nil {
+		return nil, ErrWorkspaceInvitationNotFound
+	}
+	if err := invitation.Revoke(s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("failed to save workspace invitation: %w", err)
+	}
+	return invitation, nil
+}
+
+// AcceptInvitation adds the user to the workspace an invitation code belongs
+// to, with the invited role. The user must be signed in with the invited
+// email address.
+func (s *WorkspaceService) AcceptInvitation(ctx context.Context, user *entity.User, token string) (*entity.WorkspaceMember, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, ErrInvalidWorkspaceInvitation
+	}
+	invitation, err := s.invitationRepo.FindByTokenHash(ctx, entity.HashInvitationToken(token))
+	if err != nil {
+		return nil, ErrInvalidWorkspaceInvitation
+	}
+
+	now := s.now()
+	if !invitation.IsOpen(now) {
+		return nil, ErrInvalidWorkspaceInvitation
+	}
+	if !strings.EqualFold(strings.TrimSpace(user.Email), invitation.Email) {
+		return nil, ErrWorkspaceInvitationEmailMismatch
+	}
+
+	existing, err := s.memberRepo.Find(ctx, invitation.WorkspaceID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrAlreadyWorkspaceMember
+	}
+
+	member, err := entity.NewWorkspaceMember(invitation.WorkspaceID, user.ID, user.Email, invitation.Role, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := invitation.Accept(user.ID, now); err != nil {
+		return nil, err
+	}
+	if err := s.invitationRepo.Accept(ctx, invitation, member); err != nil {
+		return nil, fmt.Errorf("failed to accept workspace invitation: %w", err)
+	}
+	return member, nil
+}
+
+func (s *WorkspaceService) createWorkspace(ctx context.Context, user *entity.User, name string) (*entity.Workspace, *entity.WorkspaceMember, error) {
+	now := s.now()
+	workspace, err := entity.NewWorkspace(name, user.ID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	owner, err := entity.NewWorkspaceMember(workspace.ID, user.ID, user.Email, valueobject.RoleOwner, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.workspaceRepo.Create(ctx, workspace, owner); err != nil {
+		return nil, nil, fmt.Errorf("failed to save workspace: %w", err)
+	}
+	return workspace, owner, nil
+}
+
+func (s *WorkspaceService) findMember(ctx context.Context, workspaceID, userID uuid.UUID) (*entity.WorkspaceMember, error) {
+	member, err := s.memberRepo.Find(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrWorkspaceMemberNotFound
+	}
+	return member, nil
+}
+
+func (s *WorkspaceService) ensureAnotherOwner(ctx context.Context, workspaceID uuid.UUID) error {
+	owners, err := s.memberRepo.CountByRole(ctx, workspaceID, valueobject.RoleOwner)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastWorkspaceOwner
+	}
+	return nil
+}
+
+func (s *WorkspaceService) sendInvitation(ctx context.Context, workspace *entity.Workspace, inviterEmail string, invitation *entity.WorkspaceInvitation) error {
+	content, err := renderInvitationEmail(workspace.Name, inviterEmail, invitation.Role.String(), invitation.Token)
+	if err != nil {
+		return err
+	}
+	return s.emailSender.SendEmail(ctx, invitation.Email, content.Subject, content.Text, content.HTML, "")
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+type mockWorkspaceRepo struct {
+	workspaces []*entity.Workspace
+	members    *mockWorkspaceMemberRepo
+}
+
+func (m *mockWorkspaceRepo) Create(ctx context.Context, workspace *entity.Workspace, owner *entity.WorkspaceMember) error {
+	m.workspaces = append(m.workspaces, workspace)
+	return m.members.Create(ctx, owner)
+}
+
+func (m *mockWorkspaceRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.Workspace, error) {
+	for _, w := range m.workspaces {
+		if w.ID == id {
+			return w, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockWorkspaceRepo) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Workspace, error) {
+	var result []*entity.Workspace
+	for _, member := range m.members.members {
+		if member.UserID == userID {
+			w, _ := m.FindByID(ctx, member.WorkspaceID)
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWorkspaceRepo) Update(ctx context.Context, workspace *entity.Workspace) error {
+	return nil
+}
+
+type mockWorkspaceMemberRepo struct {
+	members []*entity.WorkspaceMember
+}
+
+func (m *mockWorkspaceMemberRepo) Create(ctx context.Context, member *entity.WorkspaceMember) error {
+	m.members = append(m.members, member)
+	return nil
+}
+
+func (m *mockWorkspaceMemberRepo) Find(ctx context.Context, workspaceID, userID uuid.UUID) (*entity.WorkspaceMember, error) {
+	for _, member := range m.members {
+		if member.WorkspaceID == workspaceID && member.UserID == userID {
+			return member, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockWorkspaceMemberRepo) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WorkspaceMember, error) {
+	var result []*entity.WorkspaceMember
+	for _, member := range m.members {
+		if member.UserID == userID {
+			result = append(result, member)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWorkspaceMemberRepo) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]*entity.WorkspaceMember, error) {
+	var result []*entity.WorkspaceMember
+	for _, member := range m.members {
+		if member.WorkspaceID == workspaceID {
+			result = append(result, member)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWorkspaceMemberRepo) CountByRole(ctx context.Context, workspaceID uuid.UUID, role valueobject.Role) (int, error) {
+	count := 0
+	for _, member := range m.members {
+		if member.WorkspaceID == workspaceID && member.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockWorkspaceMemberRepo) Update(ctx context.Context, member *entity.WorkspaceMember) error {
+	return nil
+}
+
+func (m *mockWorkspaceMemberRepo) Delete(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	for i, member := range m.members {
+		if member.WorkspaceID == workspaceID && member.UserID == userID {
+			m.members = append(m.members[:i], m.members[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+type mockWorkspaceInvitationRepo struct {
+	invitations []*entity.WorkspaceInvitation
+	members     *mockWorkspaceMemberRepo
+	acceptErr   error
+}
+
+func (m *mockWorkspaceInvitationRepo) Create(ctx context.Context, invitation *entity.WorkspaceInvitation) error {
+	m.invitations = append(m.invitations, invitation)
+	return nil
+}
+
+func (m *mockWorkspaceInvitationRepo) FindByID(ctx context.Context, workspaceID, id uuid.UUID) (*entity.WorkspaceInvitation, error) {
+	for _, i := range m.invitations {
+		if i.ID == id && i.WorkspaceID == workspaceID {
+			return i, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockWorkspaceInvitationRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.WorkspaceInvitation, error) {
+	for _, i := range m.invitations {
+		if i.TokenHash == tokenHash {
+			return i, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockWorkspaceInvitationRepo) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]*entity.WorkspaceInvitation, error) {
+	var result []*entity.WorkspaceInvitation
+	for _, i := range m.invitations {
+		if i.WorkspaceID == workspaceID {
+			result = append(result, i)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWorkspaceInvitationRepo) Update(ctx context.Context, invitation *entity.WorkspaceInvitation) error {
+	return nil
+}
+
+func (m *mockWorkspaceInvitationRepo) Accept(ctx context.Context, invitation *entity.WorkspaceInvitation, member *entity.WorkspaceMember) error {
+	if m.acceptErr != nil {
+		return m.acceptErr
+	}
+	return m.members.Create(ctx, member)
+}
+
+func newTestWorkspaceService(now *time.Time) (*WorkspaceService, *mockWorkspaceMemberRepo, *mockEmailSender) {
+	members := &mockWorkspaceMemberRepo{}
+	sender := &mockEmailSender{}
+	svc := NewWorkspaceService(&mockWorkspaceRepo{members: members}, members, &mockWorkspaceInvitationRepo{members: members}).
+		WithEmailSender(sender)
+	svc.now = func() time.Time { return *now }
+	return svc, members, sender
+}
+
+func newTestUser(email string) *entity.User {
+	return &entity.User{ID: uuid.New(), Email: email, Role: valueobject.RoleOwner}
+}
+
+// invitationCode extracts the invitation code from the text of an invitation email
+func invitationCode(t *testing.T, body string) string {
+	t.Helper()
+	_, code, ok := strings.Cut(body, "Invitation code: ")
+	if !ok {
+		t.Fatalf("no invitation code in %q", body)
+	}
+	return strings.Fields(code)[0]
+}
+
+func TestWorkspaceService_ResolveMembershipCreatesPersonalWorkspace(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc, members, _ := newTestWorkspaceService(&now)
+	user := newTestUser("founder@example.com")
+
+	member, err := svc.ResolveMembership(ctx, user, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if member.Role != valueobject.RoleOwner || member.UserID != user.ID {
+		t.Errorf("expected user to own their personal workspace, got %+v", member)
+	}
+
+	again, err := svc.ResolveMembership(ctx, user, nil)
+	if err != nil || again.WorkspaceID != member.WorkspaceID || len(members.members) != 1 {
+		t.Errorf("expected the same workspace on the next request, got %+v (%v)", again, err)
+	}
+
+	workspaceID := member.WorkspaceID
+	if m, err := svc.ResolveMembership(ctx, newTestUser("other@example.com"), &workspaceID); err != nil || m != nil {
+		t.Errorf("expected no membership for a non-member, got %+v (%v)", m, err)
+	}
+}
+
+func TestWorkspaceService_InviteAndAccept(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc, _, sender := newTestWorkspaceService(&now)
+	founder := newTestUser("founder@example.com")
+
+	owner, _ := svc.ResolveMembership(ctx, founder, nil)
+	invitation, err := svc.Invite(ctx, owner, founder.Email, " Accountant@Example.com ", valueobject.RoleAnalyst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if invitation.Email != "accountant@example.com" || !invitation.ExpiresAt.Equal(now.Add(entity.WorkspaceInvitationTTL)) {
+		t.Errorf("unexpected invitation %+v", invitation)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].to != "accountant@example.com" ||
+		!strings.Contains(sender.sent[0].text, "founder@example.com has invited you") {
+		t.Fatalf("expected an invitation email, got %+v", sender.sent)
+	}
+	code := invitationCode(t, sender.sent[0].text)
+	if invitation.TokenHash != entity.HashInvitationToken(code) || invitation.TokenHash == code {
+		t.Errorf("expected only the code's hash to be stored, got %q", invitation.TokenHash)
+	}
+
+	if _, err := svc.AcceptInvitation(ctx, newTestUser("intruder@example.com"), code); !errors.Is(err, ErrWorkspaceInvitationEmailMismatch) {
+		t.Errorf("expected ErrWorkspaceInvitationEmailMismatch, got %v", err)
+	}
+
+	accountant := newTestUser("accountant@example.com")
+	member, err := svc.AcceptInvitation(ctx, accountant, code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if member.WorkspaceID != owner.WorkspaceID || member.Role != valueobject.RoleAnalyst {
+		t.Errorf("expected analyst membership of the founder's workspace, got %+v", member)
+	}
+	if invitation.AcceptedBy == nil || *invitation.AcceptedBy != accountant.ID || invitation.TokenHash != "" {
+		t.Errorf("expected invitation accepted with code cleared, got %+v", invitation)
+	}
+	if _, err := svc.AcceptInvitation(ctx, accountant, code); !errors.Is(err, ErrInvalidWorkspaceInvitation) {
+		t.Errorf("expected used code to be invalid, got %v", err)
+	}
+
+	// The accountant's first membership is now the founder's workspace
+	resolved, _ := svc.ResolveMembership(ctx, accountant, nil)
+	if resolved.WorkspaceID != owner.WorkspaceID {
+		t.Errorf("expected accountant to resolve to the shared workspace, got %+v", resolved)
+	}
+
+	if _, err := svc.Invite(ctx, owner, founder.Email, "accountant@example.com", valueobject.RoleViewer); !errors.Is(err, ErrAlreadyWorkspaceMember) {
+		t.Errorf("expected ErrAlreadyWorkspaceMember, got %v", err)
+	}
+	if _, err := svc.Invite(ctx, member, accountant.Email, "viewer@example.com", valueobject.RoleViewer); !errors.Is(err, ErrInsufficientWorkspaceRole) {
+		t.Errorf("expected analysts not to invite, got %v", err)
+	}
+}
+
+func TestWorkspaceService_InvitationExpiresAndRevokes(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc, _, _ := newTestWorkspaceService(&now)
+	founder := newTestUser("founder@example.com")
+	owner, _ := svc.ResolveMembership(ctx, founder, nil)
+
+	expiring, _ := svc.Invite(ctx, owner, founder.Email, "late@example.com", valueobject.RoleViewer)
+	revoked, _ := svc.Invite(ctx, owner, founder.Email, "revoked@example.com", valueobject.RoleViewer)
+	revokedCode := revoked.Token
+
+	if _, err := svc.RevokeInvitation(ctx, owner.WorkspaceID, revoked.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.RevokeInvitation(ctx, owner.WorkspaceID, revoked.ID); !errors.Is(err, entity.ErrWorkspaceInvitationClosed) {
+		t.Errorf("expected ErrWorkspaceInvitationClosed, got %v", err)
+	}
+	if _, err := svc.RevokeInvitation(ctx, uuid.New(), expiring.ID); !errors.Is(err, ErrWorkspaceInvitationNotFound) {
+		t.Errorf("expected another workspace's invitation not to be found, got %v", err)
+	}
+	if _, err := svc.AcceptInvitation(ctx, newTestUser("revoked@example.com"), revokedCode); !errors.Is(err, ErrInvalidWorkspaceInvitation) {
+		t.Errorf("expected revoked code to be invalid, got %v", err)
+	}
+
+	now = now.Add(entity.WorkspaceInvitationTTL)
+	if _, err := svc.AcceptInvitation(ctx, newTestUser("late@example.com"), expiring.Token); !errors.Is(err, ErrInvalidWorkspaceInvitation) {
+		t.Errorf("expected expired code to be invalid, got %v", err)
+	}
+}
+
+func TestWorkspaceService_AcceptInvitationFailureAddsNoMember(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	members := &mockWorkspaceMemberRepo{}
+	invitations := &mockWorkspaceInvitationRepo{members: members}
+	svc := NewWorkspaceService(&mockWorkspaceRepo{members: members}, members, invitations)
+	svc.now = func() time.Time { return now }
+	founder := newTestUser("founder@example.com")
+	owner, _ := svc.ResolveMembership(ctx, founder, nil)
+	invitation, _ := svc.Invite(ctx, owner, founder.Email, "accountant@example.com", valueobject.RoleAnalyst)
+
+	// The invitation was accepted or revoked after it was read
+	invitations.acceptErr = errors.New("workspace invitation not found")
+	if _, err := svc.AcceptInvitation(ctx, newTestUser("accountant@example.com"), invitation.Token); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(members.members) != 1 {
+		t.Errorf("expected no member to be added, got %d members", len(members.members))
+	}
+}
+
+func TestWorkspaceService_RoleRules(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc, members, _ := newTestWorkspaceService(&now)
+	founder := newTestUser("founder@example.com")
+	owner, _ := svc.ResolveMembership(ctx, founder, nil)
+
+	join := func(email string, role valueobject.Role) *entity.WorkspaceMember {
+		user := newTestUser(email)
+		member, _ := entity.NewWorkspaceMember(owner.WorkspaceID, user.ID, email, role, now)
+		members.Create(ctx, member)
+		return member
+	}
+	admin := join("support@example.com", valueobject.RoleAdmin)
+	viewer := join("viewer@example.com", valueobject.RoleViewer)
+
+	// Admins manage non-owners but cannot touch the OWNER role
+	if _, err := svc.UpdateMemberRole(ctx, admin, viewer.UserID, valueobject.RoleAnalyst); err != nil || viewer.Role != valueobject.RoleAnalyst {
+		t.Errorf("expected admin to promote viewer to analyst, got %v", err)
+	}
+	if _, err := svc.UpdateMemberRole(ctx, admin, viewer.UserID, valueobject.RoleOwner); !errors.Is(err, ErrWorkspaceRoleNotAllowed) {
+		t.Errorf("expected admin not to grant OWNER, got %v", err)
+	}
+	if _, err := svc.UpdateMemberRole(ctx, admin, owner.UserID, valueobject.RoleViewer); !errors.Is(err, ErrWorkspaceRoleNotAllowed) {
+		t.Errorf("expected admin not to demote the owner, got %v", err)
+	}
+	if err := svc.RemoveMember(ctx, admin, owner.UserID); !errors.Is(err, ErrWorkspaceRoleNotAllowed) {
+		t.Errorf("expected admin not to remove the owner, got %v", err)
+	}
+	if _, err := svc.Invite(ctx, admin, "", "cofounder@example.com", valueobject.RoleOwner); !errors.Is(err, ErrWorkspaceRoleNotAllowed) {
+		t.Errorf("expected admin not to invite an owner, got %v", err)
+	}
+	if _, err := svc.UpdateMemberRole(ctx, viewer, admin.UserID, valueobject.RoleViewer); !errors.Is(err, ErrInsufficientWorkspaceRole) {
+		t.Errorf("expected analyst not to change roles, got %v", err)
+	}
+
+	// The last owner can neither step down nor leave
+	if _, err := svc.UpdateMemberRole(ctx, owner, owner.UserID, valueobject.RoleAdmin); !errors.Is(err, ErrLastWorkspaceOwner) {
+		t.Errorf("expected ErrLastWorkspaceOwner, got %v", err)
+	}
+	if err := svc.RemoveMember(ctx, owner, owner.UserID); !errors.Is(err, ErrLastWorkspaceOwner) {
+		t.Errorf("expected ErrLastWorkspaceOwner, got %v", err)
+	}
+
+	// Once another owner exists, the founder can step down
+	if _, err := svc.UpdateMemberRole(ctx, owner, admin.UserID, valueobject.RoleOwner); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.UpdateMemberRole(ctx, owner, owner.UserID, valueobject.RoleAdmin); err != nil || owner.Role != valueobject.RoleAdmin {
+		t.Errorf("expected founder to step down to admin, got %v", err)
+	}
+
+	// Members can always leave
+	if err := svc.RemoveMember(ctx, viewer, viewer.UserID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m, _ := members.Find(ctx, owner.WorkspaceID, viewer.UserID); m != nil {
+		t.Error("expected member to be removed")
+	}
+}
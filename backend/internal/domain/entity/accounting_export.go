@@ -0,0 +1,219 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+// AccountingFormat represents the target accounting system file format
+type AccountingFormat string
+
+const (
+	AccountingFormatQuickBooksIIF AccountingFormat = "quickbooks_iif" // QuickBooks Desktop IIF general journal
+	AccountingFormatQuickBooksCSV AccountingFormat = "quickbooks_csv" // QuickBooks Online journal entry CSV import
+	AccountingFormatXeroCSV       AccountingFormat = "xero_csv"       // Xero manual journal CSV import
+)
+
+// IsValid returns true if the format is supported
+func (f AccountingFormat) IsValid() bool {
+	switch f {
+	case AccountingFormatQuickBooksIIF, AccountingFormatQuickBooksCSV, AccountingFormatXeroCSV:
+		return true
+	}
+	return false
+}
+
+// JournalGranularity controls how transactions are summarized into journal entries
+type JournalGranularity string
+
+const (
+	JournalGranularityDay   JournalGranularity = "day"
+	JournalGranularityMonth JournalGranularity = "month"
+)
+
+// IsValid returns true if the granularity is supported
+func (g JournalGranularity) IsValid() bool {
+	return g == JournalGranularityDay || g == JournalGranularityMonth
+}
+
+// PeriodStart truncates a time to the start of its journal period (UTC)
+func (g JournalGranularity) PeriodStart(t time.Time) time.Time {
+	t = t.UTC()
+	if g == JournalGranularityMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// PeriodEnd returns the last day of the journal period containing t (UTC, start of day)
+func (g JournalGranularity) PeriodEnd(t time.Time) time.Time {
+	start := g.PeriodStart(t)
+	if g == JournalGranularityMonth {
+		return start.AddDate(0, 1, -1)
+	}
+	return start
+}
+
+// ErrGLAccountRequired is returned when a GL mapping is missing a required account code
+var ErrGLAccountRequired = errors.New("all GL account codes are required")
+
+// GLAccountMapping maps charge types and fee components to general ledger account codes.
+// One mapping per app. Codes are free text so they can hold either an account number
+// (Xero) or a full account name (QuickBooks).
+type GLAccountMapping struct {
+	ID                      uuid.UUID
+	AppID                   uuid.UUID
+	RecurringRevenueAccount string // Credited with gross RECURRING charges
+	UsageRevenueAccount     string // Credited with gross USAGE charges
+	OneTimeRevenueAccount   string // Credited with gross ONE_TIME charges
+	RefundAccount           string // Debited with gross REFUND amounts (contra-revenue)
+	RevenueShareFeeAccount  string // Debited with Shopify revenue share
+	ProcessingFeeAccount    string // Debited with processing fees
+	TaxOnFeesAccount        string // Debited with tax charged on Shopify fees
+	ClearingAccount         string // Debited with net payout (Shopify payouts clearing)
+	RoundingAccount         string // Absorbs gross/net/fee rounding differences
+	XeroTaxRate             string // Tax rate name written to Xero journal lines
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+}
+
+// NewGLAccountMapping creates a mapping with a default chart of accounts
+func NewGLAccountMapping(appID uuid.UUID) *GLAccountMapping {
+	now := time.Now().UTC()
+	return &GLAccountMapping{
+		ID:                      uuid.New(),
+		AppID:                   appID,
+		RecurringRevenueAccount: "4000",
+		UsageRevenueAccount:     "4010",
+		OneTimeRevenueAccount:   "4020",
+		RefundAccount:           "4090",
+		RevenueShareFeeAccount:  "6100",
+		ProcessingFeeAccount:    "6110",
+		TaxOnFeesAccount:        "6120",
+		ClearingAccount:         "1210",
+		RoundingAccount:         "6190",
+		XeroTaxRate:             "Tax Exempt",
+		CreatedAt:               now,
+		UpdatedAt:               now,
+	}
+}
+
+// RevenueAccountFor returns the account credited (or debited, for refunds) with the gross amount
+func (m *GLAccountMapping) RevenueAccountFor(chargeType valueobject.ChargeType) string {
+	switch chargeType {
+	case valueobject.ChargeTypeUsage:
+		return m.UsageRevenueAccount
+	case valueobject.ChargeTypeOneTime:
+		return m.OneTimeRevenueAccount
+	case valueobject.ChargeTypeRefund:
+		return m.RefundAccount
+	default:
+		return m.RecurringRevenueAccount
+	}
+}
+
+// Validate ensures every account code is set
+func (m *GLAccountMapping) Validate() error {
+	for _, code := range []string{
+		m.RecurringRevenueAccount,
+		m.UsageRevenueAccount,
+		m.OneTimeRevenueAccount,
+		m.RefundAccount,
+		m.RevenueShareFeeAccount,
+		m.ProcessingFeeAccount,
+		m.TaxOnFeesAccount,
+		m.ClearingAccount,
+		m.RoundingAccount,
+	} {
+		if code == "" {
+			return ErrGLAccountRequired
+		}
+	}
+	return nil
+}
+
+// JournalLine is a single debit or credit against a GL account
+type JournalLine struct {
+	AccountCode string
+	Description string
+	DebitCents  int64
+	CreditCents int64
+}
+
+// JournalEntry is a balanced, summarized journal entry for one period and currency
+type JournalEntry struct {
+	Number      int       // Sequential journal number within an export
+	Date        time.Time // Last day of the summarized period
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Currency    string
+	Memo        string
+	Lines       []JournalLine
+}
+
+// TotalDebitCents returns the sum of all debit lines
+func (e *JournalEntry) TotalDebitCents() int64 {
+	var total int64
+	for _, l := range e.Lines {
+		total += l.DebitCents
+	}
+	return total
+}
+
+// TotalCreditCents returns the sum of all credit lines
+func (e *JournalEntry) TotalCreditCents() int64 {
+	var total int64
+	for _, l := range e.Lines {
+		total += l.CreditCents
+	}
+	return total
+}
+
+// IsBalanced returns true if debits equal credits
+func (e *JournalEntry) IsBalanced() bool {
+	return e.TotalDebitCents() == e.TotalCreditCents()
+}
+
+// AccountingExportRun records a completed journal export so periods aren't exported twice
+type AccountingExportRun struct {
+	ID               uuid.UUID
+	AppID            uuid.UUID
+	UserID           *uuid.UUID // Nullable for system-initiated exports
+	Format           AccountingFormat
+	Granularity      JournalGranularity
+	PeriodStart      time.Time // Inclusive, start of day
+	PeriodEnd        time.Time // Inclusive, start of last day
+	JournalCount     int
+	TransactionCount int
+	TotalDebitCents  int64
+	Confirmed        bool // True if the user confirmed re-exporting an already exported period
+	CreatedAt        time.Time
+}
+
+// NewAccountingExportRun creates a new export run record
+func NewAccountingExportRun(
+	appID uuid.UUID,
+	userID *uuid.UUID,
+	format AccountingFormat,
+	granularity JournalGranularity,
+	periodStart, periodEnd time.Time,
+) *AccountingExportRun {
+	return &AccountingExportRun{
+		ID:          uuid.New(),
+		AppID:       appID,
+		UserID:      userID,
+		Format:      format,
+		Granularity: granularity,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+// Overlaps returns true if the run's period intersects [from, to] (both inclusive days)
+func (r *AccountingExportRun) Overlaps(from, to time.Time) bool {
+	return !r.PeriodStart.After(to) && !r.PeriodEnd.Before(from)
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrAlertAlreadyResolved is returned when acknowledging or resolving a resolved alert
+var ErrAlertAlreadyResolved = errors.New("alert is already resolved")
+
+// AlertStatus is the lifecycle state of a triggered alert
+type AlertStatus string
+
+const (
+	AlertStatusOpen         AlertStatus = "OPEN"
+	AlertStatusAcknowledged AlertStatus = "ACKNOWLEDGED"
+	AlertStatusResolved     AlertStatus = "RESOLVED"
+)
+
+// IsValid returns true if the status is supported
+func (s AlertStatus) IsValid() bool {
+	switch s {
+	case AlertStatusOpen, AlertStatusAcknowledged, AlertStatusResolved:
+		return true
+	}
+	return false
+}
+
+// Alert is one firing of an alert rule. Key identifies what the rule fired for
+// (a shop domain for SHOP_AT_RISK, empty for app-wide rules).
+type Alert struct {
+	ID             uuid.UUID
+	RuleID         uuid.UUID
+	UserID         uuid.UUID
+	AppID          uuid.UUID
+	Key            string
+	Severity       AlertSeverity
+	Title          string
+	Message        string
+	Status         AlertStatus
+	TriggeredAt    time.Time
+	AcknowledgedAt *time.Time
+	ResolvedAt     *time.Time
+	AutoResolved   bool // Resolved because the condition cleared, not by the user
+}
+
+// NewAlert creates an open alert for a rule
+func NewAlert(rule *AlertRule, key, title, message string, triggeredAt time.Time) *Alert {
+	return &Alert{
+		ID:          uuid.New(),
+		RuleID:      rule.ID,
+		UserID:      rule.UserID,
+		AppID:       rule.AppID,
+		Key:         key,
+		Severity:    rule.Severity,
+		Title:       title,
+		Message:     message,
+		Status:      AlertStatusOpen,
+		TriggeredAt: triggeredAt,
+	}
+}
+
+// Acknowledge marks the alert as seen; it stays unresolved
+func (a *Alert) Acknowledge(at time.Time) error {
+	if a.Status == AlertStatusResolved {
+		return ErrAlertAlreadyResolved
+	}
+	if a.Status == AlertStatusOpen {
+		a.Status = AlertStatusAcknowledged
+		a.AcknowledgedAt = &at
+	}
+	return nil
+}
+
+// Resolve closes the alert
+func (a *Alert) Resolve(at time.Time, auto bool) error {
+	if a.Status == AlertStatusResolved {
+		return ErrAlertAlreadyResolved
+	}
+	a.Status = AlertStatusResolved
+	a.ResolvedAt = &at
+	a.AutoResolved = auto
+	return nil
+}
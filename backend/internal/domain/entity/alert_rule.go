@@ -0,0 +1,151 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+var (
+	// ErrAlertRuleNameRequired is returned when a rule has no name
+	ErrAlertRuleNameRequired = errors.New("name is required")
+
+	// ErrInvalidAlertRuleType is returned for an unknown rule type
+	ErrInvalidAlertRuleType = errors.New("rule_type must be one of MRR_DROP, SHOP_AT_RISK, USAGE_REVENUE_ZERO, SYNC_FAILING")
+
+	// ErrInvalidAlertThreshold is returned when the threshold does not suit the rule type
+	ErrInvalidAlertThreshold = errors.New("threshold must be positive (percent for MRR_DROP, at most 100)")
+
+	// ErrInvalidAlertRiskState is returned when a SHOP_AT_RISK rule has no at-risk state
+	ErrInvalidAlertRiskState = errors.New("risk_state must be one of ONE_CYCLE_MISSED, TWO_CYCLES_MISSED, CHURNED")
+
+	// ErrInvalidAlertSeverity is returned for an unknown severity
+	ErrInvalidAlertSeverity = errors.New("severity must be one of INFO, WARNING, CRITICAL")
+
+	// ErrInvalidAlertChannel is returned when a rule has no channel or an unknown one
+	ErrInvalidAlertChannel = errors.New("channels must contain at least one of PUSH, SLACK, EMAIL, TEAMS, DISCORD, WEBHOOK")
+
+	// ErrInvalidAlertCooldown is returned for a negative cooldown
+	ErrInvalidAlertCooldown = errors.New("cooldown_minutes must not be negative")
+)
+
+// AlertRuleType is the condition an alert rule watches
+type AlertRuleType string
+
+const (
+	AlertRuleMRRDrop          AlertRuleType = "MRR_DROP"           // Threshold: percent drop in active MRR day over day
+	AlertRuleShopAtRisk       AlertRuleType = "SHOP_AT_RISK"       // Threshold: minimum shop MRR in cents; RiskState: the state entered
+	AlertRuleUsageRevenueZero AlertRuleType = "USAGE_REVENUE_ZERO" // Threshold: days without usage revenue
+	AlertRuleSyncFailing      AlertRuleType = "SYNC_FAILING"       // Threshold: hours since syncs started failing
+)
+
+// IsValid returns true if the rule type is supported
+func (t AlertRuleType) IsValid() bool {
+	switch t {
+	case AlertRuleMRRDrop, AlertRuleShopAtRisk, AlertRuleUsageRevenueZero, AlertRuleSyncFailing:
+		return true
+	}
+	return false
+}
+
+// AlertSeverity is how urgent a triggered alert is
+type AlertSeverity string
+
+const (
+	AlertSeverityInfo     AlertSeverity = "INFO"
+	AlertSeverityWarning  AlertSeverity = "WARNING"
+	AlertSeverityCritical AlertSeverity = "CRITICAL"
+)
+
+// IsValid returns true if the severity is supported
+func (s AlertSeverity) IsValid() bool {
+	switch s {
+	case AlertSeverityInfo, AlertSeverityWarning, AlertSeverityCritical:
+		return true
+	}
+	return false
+}
+
+// DefaultAlertCooldownMinutes is how long a rule stays quiet for a key after it fired
+const DefaultAlertCooldownMinutes = 24 * 60
+
+// AlertRule is a user-defined condition on an app, evaluated after every sync.
+// A rule fires at most once per key (e.g. per shop) while its alert is unresolved,
+// and not again within the cooldown after the last alert.
+type AlertRule struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	AppID           uuid.UUID
+	Name            string
+	Type            AlertRuleType
+	Threshold       float64               // Meaning depends on Type
+	RiskState       valueobject.RiskState // SHOP_AT_RISK only
+	Severity        AlertSeverity
+	Channels        []NotificationChannel
+	CooldownMinutes int
+	Enabled         bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// NewAlertRule creates an enabled rule with WARNING severity, push delivery and the default cooldown
+func NewAlertRule(userID, appID uuid.UUID, name string, ruleType AlertRuleType, threshold float64) *AlertRule {
+	now := time.Now().UTC()
+	return &AlertRule{
+		ID:              uuid.New(),
+		UserID:          userID,
+		AppID:           appID,
+		Name:            strings.TrimSpace(name),
+		Type:            ruleType,
+		Threshold:       threshold,
+		Severity:        AlertSeverityWarning,
+		Channels:        []NotificationChannel{NotificationChannelPush},
+		CooldownMinutes: DefaultAlertCooldownMinutes,
+		Enabled:         true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// Validate checks the rule's fields
+func (r *AlertRule) Validate() error {
+	if r.Name == "" {
+		return ErrAlertRuleNameRequired
+	}
+	if !r.Type.IsValid() {
+		return ErrInvalidAlertRuleType
+	}
+	if r.Threshold <= 0 || (r.Type == AlertRuleMRRDrop && r.Threshold > 100) {
+		return ErrInvalidAlertThreshold
+	}
+	if r.Type == AlertRuleShopAtRisk {
+		switch r.RiskState {
+		case valueobject.RiskStateOneCycleMissed, valueobject.RiskStateTwoCyclesMissed, valueobject.RiskStateChurned:
+		default:
+			return ErrInvalidAlertRiskState
+		}
+	}
+	if !r.Severity.IsValid() {
+		return ErrInvalidAlertSeverity
+	}
+	if len(r.Channels) == 0 {
+		return ErrInvalidAlertChannel
+	}
+	for _, c := range r.Channels {
+		if !c.IsValid() {
+			return ErrInvalidAlertChannel
+		}
+	}
+	if r.CooldownMinutes < 0 {
+		return ErrInvalidAlertCooldown
+	}
+	return nil
+}
+
+// Cooldown returns the rule's cooldown as a duration
+func (r *AlertRule) Cooldown() time.Duration {
+	return time.Duration(r.CooldownMinutes) * time.Minute
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AppSyncStatus tracks the outcome of an app's Partner API syncs
+type AppSyncStatus struct {
+	AppID               uuid.UUID
+	LastAttemptAt       time.Time
+	LastSuccessAt       *time.Time
+	FailingSince        *time.Time // First failure since the last success; nil while syncs succeed
+	LastError           string
+	ConsecutiveFailures int
+}
+
+// NewAppSyncStatus creates the sync status of an app that has not synced yet
+func NewAppSyncStatus(appID uuid.UUID) *AppSyncStatus {
+	return &AppSyncStatus{AppID: appID}
+}
+
+// RecordSuccess records a successful sync
+func (s *AppSyncStatus) RecordSuccess(at time.Time) {
+	s.LastAttemptAt = at
+	s.LastSuccessAt = &at
+	s.FailingSince = nil
+	s.LastError = ""
+	s.ConsecutiveFailures = 0
+}
+
+// RecordFailure records a failed sync
+func (s *AppSyncStatus) RecordFailure(at time.Time, err error) {
+	s.LastAttemptAt = at
+	if s.FailingSince == nil {
+		s.FailingSince = &at
+	}
+	s.LastError = err.Error()
+	s.ConsecutiveFailures++
+}
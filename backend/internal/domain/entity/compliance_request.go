@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shopify mandatory compliance webhook topics
+const (
+	ComplianceTopicCustomersDataRequest = "customers/data_request"
+	ComplianceTopicCustomersRedact      = "customers/redact"
+	ComplianceTopicShopRedact           = "shop/redact"
+)
+
+// ComplianceRequest records how a Shopify compliance webhook was handled. Customer
+// contact details from the request are never kept; only Shopify's opaque IDs are.
+type ComplianceRequest struct {
+	ID                   uuid.UUID
+	Topic                string
+	AppGID               string
+	ShopID               int64
+	ShopDomain           string
+	CustomerID           *int64 // customers/* topics only
+	DataRequestID        *int64 // customers/data_request only
+	TransactionsRedacted int64
+	SubscriptionsDeleted int64
+	EventsDeleted        int64
+	Result               string
+	RequestedAt          time.Time // When Shopify triggered the webhook
+	CompletedAt          time.Time
+}
+
+// NewComplianceRequest creates a record of a compliance webhook for a shop
+func NewComplianceRequest(topic, appGID string, shopID int64, shopDomain string, requestedAt time.Time) *ComplianceRequest {
+	return &ComplianceRequest{
+		ID:          uuid.New(),
+		Topic:       topic,
+		AppGID:      appGID,
+		ShopID:      shopID,
+		ShopDomain:  shopDomain,
+		RequestedAt: requestedAt,
+	}
+}
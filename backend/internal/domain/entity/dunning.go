@@ -0,0 +1,383 @@
+package entity
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+const (
+	// MaxDunningSteps is the most steps a sequence can have
+	MaxDunningSteps = 10
+
+	// MaxDunningStepDay is the latest day a step can be scheduled on
+	MaxDunningStepDay = 90
+
+	// MaxDunningStepAttempts is how often a failing step is tried before it is recorded as failed and skipped
+	MaxDunningStepAttempts = 3
+
+	// DunningRecoveryWindow is how long after its last step a run still counts as recovered if the shop returns to SAFE
+	DunningRecoveryWindow = 30 * 24 * time.Hour
+)
+
+var (
+	// ErrDunningSequenceNameRequired is returned when a sequence has no name
+	ErrDunningSequenceNameRequired = errors.New("name is required")
+
+	// ErrInvalidDunningTrigger is returned when a sequence is not triggered by an at-risk state
+	ErrInvalidDunningTrigger = errors.New("trigger must be one of ONE_CYCLE_MISSED, TWO_CYCLES_MISSED, CHURNED")
+
+	// ErrInvalidDunningSteps is returned when a sequence has no steps or too many
+	ErrInvalidDunningSteps = errors.New("steps must contain between 1 and 10 steps")
+
+	// ErrInvalidDunningStepDay is returned when a step's day is out of range or before the previous step's
+	ErrInvalidDunningStepDay = errors.New("step day must be between 1 and 90, in order")
+
+	// ErrInvalidDunningAction is returned for an unknown step action
+	ErrInvalidDunningAction = errors.New("step action must be one of INTERNAL_ALERT, MERCHANT_EMAIL, TASK")
+
+	// ErrInvalidDunningStep is returned when a step is missing what its action needs
+	ErrInvalidDunningStep = errors.New("MERCHANT_EMAIL steps need a subject and body; TASK steps need a subject and an assignee email")
+
+	// ErrDunningRunEnded is returned when stopping a run that has already ended
+	ErrDunningRunEnded = errors.New("dunning run has already ended")
+
+	// ErrDunningTaskClosed is returned when completing a task that is not open
+	ErrDunningTaskClosed = errors.New("task is not open")
+)
+
+// DunningAction is what a sequence step does
+type DunningAction string
+
+const (
+	DunningActionInternalAlert DunningAction = "INTERNAL_ALERT" // Notification to the team on their notification channels
+	DunningActionMerchantEmail DunningAction = "MERCHANT_EMAIL" // Templated email to the shop's contact
+	DunningActionTask          DunningAction = "TASK"           // Follow-up task assigned to a teammate
+)
+
+// IsValid returns true if the action is supported
+func (a DunningAction) IsValid() bool {
+	switch a {
+	case DunningActionInternalAlert, DunningActionMerchantEmail, DunningActionTask:
+		return true
+	}
+	return false
+}
+
+// DunningStep is one step of a sequence. Subject and Body are text templates
+// rendered with the run's shop; for tasks Subject is the task title.
+type DunningStep struct {
+	Day      int           `json:"day"` // Day 1 is the day the sequence started
+	Action   DunningAction `json:"action"`
+	Subject  string        `json:"subject,omitempty"`
+	Body     string        `json:"body,omitempty"`
+	Assignee string        `json:"assignee,omitempty"` // TASK only: teammate's email
+}
+
+// Validate checks the step has what its action needs
+func (s DunningStep) Validate() error {
+	if !s.Action.IsValid() {
+		return ErrInvalidDunningAction
+	}
+	switch s.Action {
+	case DunningActionMerchantEmail:
+		if strings.TrimSpace(s.Subject) == "" || strings.TrimSpace(s.Body) == "" {
+			return ErrInvalidDunningStep
+		}
+	case DunningActionTask:
+		if strings.TrimSpace(s.Subject) == "" {
+			return ErrInvalidDunningStep
+		}
+		if _, err := mail.ParseAddress(s.Assignee); err != nil {
+			return ErrInvalidDunningStep
+		}
+	}
+	return nil
+}
+
+// DunningSequence is a user's follow-up sequence for an app, started for a
+// shop when its subscription enters the trigger risk state
+type DunningSequence struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	AppID     uuid.UUID
+	Name      string
+	Trigger   valueobject.RiskState
+	Steps     []DunningStep
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewDunningSequence creates an enabled sequence
+func NewDunningSequence(userID, appID uuid.UUID, name string, trigger valueobject.RiskState, steps []DunningStep, now time.Time) *DunningSequence {
+	return &DunningSequence{
+		ID:        uuid.New(),
+		UserID:    userID,
+		AppID:     appID,
+		Name:      strings.TrimSpace(name),
+		Trigger:   trigger,
+		Steps:     steps,
+		Enabled:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate checks the sequence's fields and steps
+func (s *DunningSequence) Validate() error {
+	if s.Name == "" {
+		return ErrDunningSequenceNameRequired
+	}
+	if !s.Trigger.IsAtRisk() && !s.Trigger.IsChurned() {
+		return ErrInvalidDunningTrigger
+	}
+	if len(s.Steps) == 0 || len(s.Steps) > MaxDunningSteps {
+		return ErrInvalidDunningSteps
+	}
+
+	previousDay := 1
+	for _, step := range s.Steps {
+		if step.Day < previousDay || step.Day > MaxDunningStepDay {
+			return ErrInvalidDunningStepDay
+		}
+		previousDay = step.Day
+		if err := step.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DunningRunStatus is the outcome of a sequence run for one shop
+type DunningRunStatus string
+
+const (
+	DunningRunActive    DunningRunStatus = "ACTIVE"    // Steps still to run
+	DunningRunRecovered DunningRunStatus = "RECOVERED" // The shop returned to SAFE
+	DunningRunExhausted DunningRunStatus = "EXHAUSTED" // Every step ran without the shop recovering (yet)
+	DunningRunCancelled DunningRunStatus = "CANCELLED" // Stopped by the user
+)
+
+// IsValid returns true if the status is supported
+func (s DunningRunStatus) IsValid() bool {
+	switch s {
+	case DunningRunActive, DunningRunRecovered, DunningRunExhausted, DunningRunCancelled:
+		return true
+	}
+	return false
+}
+
+// DunningStepStatus is the outcome of one step of a run
+type DunningStepStatus string
+
+const (
+	DunningStepDone    DunningStepStatus = "DONE"
+	DunningStepSkipped DunningStepStatus = "SKIPPED" // Nothing to do, e.g. the shop has no contact email
+	DunningStepFailed  DunningStepStatus = "FAILED"  // Failed MaxDunningStepAttempts times
+)
+
+// DunningStepResult records what a run's step did
+type DunningStepResult struct {
+	Step       int               `json:"step"` // Index into the run's steps
+	Action     DunningAction     `json:"action"`
+	Status     DunningStepStatus `json:"status"`
+	Detail     string            `json:"detail,omitempty"` // Recipient, task ID or error
+	ExecutedAt time.Time         `json:"executed_at"`
+}
+
+// DunningRun is one sequence started for one subscription. The steps are
+// copied from the sequence when it starts, so editing the sequence only
+// affects new runs.
+type DunningRun struct {
+	ID             uuid.UUID
+	SequenceID     uuid.UUID
+	UserID         uuid.UUID
+	AppID          uuid.UUID
+	SubscriptionID uuid.UUID
+	ShopDomain     string
+	ShopName       string
+	Trigger        valueobject.RiskState
+	MRRCents       int64 // Subscription MRR when the run started, the revenue at stake
+	Steps          []DunningStep
+	Results        []DunningStepResult
+	Status         DunningRunStatus
+	NextStep       int
+	NextStepAt     *time.Time // nil once the run has ended
+	Attempts       int        // Failed attempts at the next step
+	LastError      string
+	StartedAt      time.Time
+	EndedAt        *time.Time
+	RecoveredAt    *time.Time
+}
+
+// NewDunningRun starts a run of the sequence for a subscription, with its first step due on its day
+func NewDunningRun(sequence *DunningSequence, sub *Subscription, startedAt time.Time) *DunningRun {
+	steps := make([]DunningStep, len(sequence.Steps))
+	copy(steps, sequence.Steps)
+
+	run := &DunningRun{
+		ID:             uuid.New(),
+		SequenceID:     sequence.ID,
+		UserID:         sequence.UserID,
+		AppID:          sequence.AppID,
+		SubscriptionID: sub.ID,
+		ShopDomain:     sub.MyshopifyDomain,
+		ShopName:       sub.ShopName,
+		Trigger:        sequence.Trigger,
+		MRRCents:       sub.MRRCents(),
+		Steps:          steps,
+		Status:         DunningRunActive,
+		StartedAt:      startedAt,
+	}
+	next := run.stepDueAt(0)
+	run.NextStepAt = &next
+	return run
+}
+
+// IsEnded returns true if the run has no more steps to run
+func (r *DunningRun) IsEnded() bool {
+	return r.Status != DunningRunActive
+}
+
+// CurrentStep returns the step due next, or nil if the run has ended
+func (r *DunningRun) CurrentStep() *DunningStep {
+	if r.IsEnded() || r.NextStep >= len(r.Steps) {
+		return nil
+	}
+	return &r.Steps[r.NextStep]
+}
+
+// CompleteStep records the outcome of the current step and schedules the
+// next, or marks the run exhausted after the last step
+func (r *DunningRun) CompleteStep(status DunningStepStatus, detail string, now time.Time) {
+	r.Results = append(r.Results, DunningStepResult{
+		Step:       r.NextStep,
+		Action:     r.Steps[r.NextStep].Action,
+		Status:     status,
+		Detail:     detail,
+		ExecutedAt: now,
+	})
+	r.NextStep++
+	r.Attempts = 0
+	r.LastError = ""
+
+	if r.NextStep >= len(r.Steps) {
+		r.Status = DunningRunExhausted
+		r.NextStepAt = nil
+		r.EndedAt = &now
+		return
+	}
+
+	next := r.stepDueAt(r.NextStep)
+	if next.Before(now) {
+		next = now
+	}
+	r.NextStepAt = &next
+}
+
+// FailStep records a failed attempt at the current step and schedules a
+// retry. After MaxDunningStepAttempts the step is recorded as failed and the
+// run moves on.
+func (r *DunningRun) FailStep(err error, retryAt time.Time, now time.Time) {
+	r.Attempts++
+	if r.Attempts >= MaxDunningStepAttempts {
+		r.CompleteStep(DunningStepFailed, err.Error(), now)
+		return
+	}
+	r.LastError = err.Error()
+	r.NextStepAt = &retryAt
+}
+
+// Recover marks the run recovered because the shop returned to SAFE
+func (r *DunningRun) Recover(now time.Time) error {
+	if r.Status != DunningRunActive && r.Status != DunningRunExhausted {
+		return ErrDunningRunEnded
+	}
+	r.Status = DunningRunRecovered
+	r.RecoveredAt = &now
+	if r.EndedAt == nil {
+		r.EndedAt = &now
+	}
+	r.NextStepAt = nil
+	return nil
+}
+
+// Cancel stops an active run
+func (r *DunningRun) Cancel(now time.Time) error {
+	if r.Status != DunningRunActive {
+		return ErrDunningRunEnded
+	}
+	r.Status = DunningRunCancelled
+	r.EndedAt = &now
+	r.NextStepAt = nil
+	return nil
+}
+
+// stepDueAt returns when a step is due: its day counted from the day the run started
+func (r *DunningRun) stepDueAt(step int) time.Time {
+	return r.StartedAt.AddDate(0, 0, r.Steps[step].Day-1)
+}
+
+// DunningTaskStatus is the state of a follow-up task
+type DunningTaskStatus string
+
+const (
+	DunningTaskOpen      DunningTaskStatus = "OPEN"
+	DunningTaskDone      DunningTaskStatus = "DONE"
+	DunningTaskCancelled DunningTaskStatus = "CANCELLED" // The shop recovered or the run was stopped
+)
+
+// IsValid returns true if the status is supported
+func (s DunningTaskStatus) IsValid() bool {
+	switch s {
+	case DunningTaskOpen, DunningTaskDone, DunningTaskCancelled:
+		return true
+	}
+	return false
+}
+
+// DunningTask is a follow-up assigned to a teammate by a TASK step
+type DunningTask struct {
+	ID          uuid.UUID
+	RunID       uuid.UUID
+	UserID      uuid.UUID
+	AppID       uuid.UUID
+	ShopDomain  string
+	Title       string
+	Description string
+	Assignee    string // Teammate's email
+	Status      DunningTaskStatus
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// NewDunningTask creates an open task for a run
+func NewDunningTask(run *DunningRun, title, description, assignee string, now time.Time) *DunningTask {
+	return &DunningTask{
+		ID:          uuid.New(),
+		RunID:       run.ID,
+		UserID:      run.UserID,
+		AppID:       run.AppID,
+		ShopDomain:  run.ShopDomain,
+		Title:       title,
+		Description: description,
+		Assignee:    assignee,
+		Status:      DunningTaskOpen,
+		CreatedAt:   now,
+	}
+}
+
+// Complete marks an open task done
+func (t *DunningTask) Complete(now time.Time) error {
+	if t.Status != DunningTaskOpen {
+		return ErrDunningTaskClosed
+	}
+	t.Status = DunningTaskDone
+	t.CompletedAt = &now
+	return nil
+}
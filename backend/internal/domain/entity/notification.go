@@ -0,0 +1,139 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationChannel is a way of reaching a user
+type NotificationChannel string
+
+const (
+	NotificationChannelPush    NotificationChannel = "PUSH"    // FCM push to the user's devices
+	NotificationChannelSlack   NotificationChannel = "SLACK"   // The Slack webhook from notification preferences and the user's Slack webhooks
+	NotificationChannelEmail   NotificationChannel = "EMAIL"   // The user's verified email addresses
+	NotificationChannelTeams   NotificationChannel = "TEAMS"   // The user's Microsoft Teams webhooks, as Adaptive Cards
+	NotificationChannelDiscord NotificationChannel = "DISCORD" // The user's Discord webhooks, as embeds
+	NotificationChannelWebhook NotificationChannel = "WEBHOOK" // The user's generic webhooks, as signed JSON
+)
+
+// IsValid returns true if the channel is supported
+func (c NotificationChannel) IsValid() bool {
+	switch c {
+	case NotificationChannelPush, NotificationChannelSlack, NotificationChannelEmail,
+		NotificationChannelTeams, NotificationChannelDiscord, NotificationChannelWebhook:
+		return true
+	}
+	return false
+}
+
+// IsWebhook returns true if the channel is delivered to webhooks the user configures
+func (c NotificationChannel) IsWebhook() bool {
+	switch c {
+	case NotificationChannelSlack, NotificationChannelTeams, NotificationChannelDiscord, NotificationChannelWebhook:
+		return true
+	}
+	return false
+}
+
+// NotificationKind is what caused a notification
+type NotificationKind string
+
+const (
+	NotificationKindAlert        NotificationKind = "ALERT"         // An alert rule fired
+	NotificationKindRiskChange   NotificationKind = "RISK_CHANGE"   // A subscription changed risk state
+	NotificationKindDailySummary NotificationKind = "DAILY_SUMMARY" // Daily metrics summary
+	NotificationKindWeeklyDigest NotificationKind = "WEEKLY_DIGEST" // Week-over-week metrics digest
+	NotificationKindTest         NotificationKind = "TEST"          // Test sent from notification channel settings
+	NotificationKindDunning      NotificationKind = "DUNNING"       // Internal alert step of a dunning sequence
+)
+
+// NotificationStatus is the delivery state of an outbox notification
+type NotificationStatus string
+
+const (
+	NotificationStatusPending   NotificationStatus = "PENDING"   // Channels left to deliver
+	NotificationStatusDelivered NotificationStatus = "DELIVERED" // Every channel delivered or skipped
+	NotificationStatusFailed    NotificationStatus = "FAILED"    // Gave up on the remaining channels
+)
+
+// Notification is a message to a user in the notification outbox. It is stored
+// with the event that causes it and delivered by a background worker, which
+// retries each channel until it succeeds or the attempts run out.
+type Notification struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	AppID           *uuid.UUID // App the notification is about, if any
+	Kind            NotificationKind
+	SourceID        *uuid.UUID // e.g. the alert that caused it
+	Severity        AlertSeverity
+	Title           string
+	Body            string
+	Channels        []NotificationChannel // Requested channels
+	PendingChannels []NotificationChannel // Channels not yet delivered
+	Status          NotificationStatus
+	Attempts        int
+	NextAttemptAt   *time.Time
+	LastError       string
+	CreatedAt       time.Time
+	DeliveredAt     *time.Time
+	ReadAt          *time.Time
+}
+
+// NewNotification creates a pending notification, due at once on every channel
+func NewNotification(userID uuid.UUID, kind NotificationKind, severity AlertSeverity, title, body string, channels []NotificationChannel, createdAt time.Time) *Notification {
+	pending := make([]NotificationChannel, len(channels))
+	copy(pending, channels)
+	return &Notification{
+		ID:              uuid.New(),
+		UserID:          userID,
+		Kind:            kind,
+		Severity:        severity,
+		Title:           title,
+		Body:            body,
+		Channels:        channels,
+		PendingChannels: pending,
+		Status:          NotificationStatusPending,
+		NextAttemptAt:   &createdAt,
+		CreatedAt:       createdAt,
+	}
+}
+
+// IsRead returns true if the user has read the notification
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
+
+// NotificationAttemptStatus is the outcome of one delivery attempt on one channel
+type NotificationAttemptStatus string
+
+const (
+	NotificationAttemptSent    NotificationAttemptStatus = "SENT"
+	NotificationAttemptFailed  NotificationAttemptStatus = "FAILED"
+	NotificationAttemptSkipped NotificationAttemptStatus = "SKIPPED" // Nothing to send to (no devices, no webhook)
+)
+
+// NotificationAttempt records one delivery attempt of a notification on a channel
+type NotificationAttempt struct {
+	ID             uuid.UUID
+	NotificationID uuid.UUID
+	Channel        NotificationChannel
+	Attempt        int
+	Status         NotificationAttemptStatus
+	Error          string
+	AttemptedAt    time.Time
+}
+
+// NewNotificationAttempt creates an attempt record
+func NewNotificationAttempt(notificationID uuid.UUID, channel NotificationChannel, attempt int, status NotificationAttemptStatus, errMsg string, attemptedAt time.Time) *NotificationAttempt {
+	return &NotificationAttempt{
+		ID:             uuid.New(),
+		NotificationID: notificationID,
+		Channel:        channel,
+		Attempt:        attempt,
+		Status:         status,
+		Error:          errMsg,
+		AttemptedAt:    attemptedAt,
+	}
+}
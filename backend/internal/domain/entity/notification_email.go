@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerificationTTL is how long a verification link stays valid
+const EmailVerificationTTL = 48 * time.Hour
+
+var (
+	// ErrInvalidEmailAddress is returned when an address cannot be parsed
+	ErrInvalidEmailAddress = errors.New("invalid email address")
+	// ErrEmailVerificationExpired is returned when a verification link is used after EmailVerificationTTL
+	ErrEmailVerificationExpired = errors.New("email verification link has expired")
+)
+
+// NotificationEmail is an address a user receives notification emails at. It
+// only receives email once verified, and stops for good once unsubscribed
+// through the link in an email. Only hashes of the link tokens are stored.
+type NotificationEmail struct {
+	ID                    uuid.UUID
+	UserID                uuid.UUID
+	Address               string // Lower-cased
+	VerificationToken     string // Plaintext, only set on a new or renewed verification
+	VerificationTokenHash string // Cleared once verified
+	VerificationSentAt    time.Time
+	VerifiedAt            *time.Time
+	UnsubscribeTokenHash  string // Hash of UnsubscribeToken, which is derived again for every email
+	UnsubscribedAt        *time.Time
+	CreatedAt             time.Time
+}
+
+// NewNotificationEmail creates an unverified address with a fresh verification
+// token. unsubscribeKey signs the address's unsubscribe token.
+func NewNotificationEmail(userID uuid.UUID, address string, unsubscribeKey []byte, now time.Time) (*NotificationEmail, error) {
+	parsed, err := mail.ParseAddress(strings.TrimSpace(address))
+	if err != nil || parsed.Name != "" {
+		return nil, ErrInvalidEmailAddress
+	}
+
+	verificationToken, err := newEmailToken()
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.New()
+	return &NotificationEmail{
+		ID:                    id,
+		UserID:                userID,
+		Address:               strings.ToLower(parsed.Address),
+		VerificationToken:     verificationToken,
+		VerificationTokenHash: HashEmailToken(verificationToken),
+		VerificationSentAt:    now,
+		UnsubscribeTokenHash:  HashEmailToken(UnsubscribeToken(unsubscribeKey, id)),
+		CreatedAt:             now,
+	}, nil
+}
+
+// UnsubscribeToken derives the token in an address's unsubscribe links as an
+// HMAC-SHA256 of its ID, so every email can carry it without storing it
+func UnsubscribeToken(unsubscribeKey []byte, emailID uuid.UUID) string {
+	mac := hmac.New(sha256.New, unsubscribeKey)
+	mac.Write(emailID[:])
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HashEmailToken hashes a verification or unsubscribe token using SHA-256
+func HashEmailToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// RenewVerification replaces the verification token, for resending the verification email
+func (e *NotificationEmail) RenewVerification(now time.Time) error {
+	token, err := newEmailToken()
+	if err != nil {
+		return err
+	}
+	e.VerificationToken = token
+	e.VerificationTokenHash = HashEmailToken(token)
+	e.VerificationSentAt = now
+	return nil
+}
+
+// Verify marks the address verified if the verification link has not expired
+func (e *NotificationEmail) Verify(now time.Time) error {
+	if e.IsVerified() {
+		return nil
+	}
+	if now.Sub(e.VerificationSentAt) > EmailVerificationTTL {
+		return ErrEmailVerificationExpired
+	}
+	e.VerifiedAt = &now
+	e.VerificationToken = ""
+	e.VerificationTokenHash = ""
+	return nil
+}
+
+// Unsubscribe stops all notification email to the address
+func (e *NotificationEmail) Unsubscribe(now time.Time) {
+	if e.UnsubscribedAt == nil {
+		e.UnsubscribedAt = &now
+	}
+}
+
+// IsVerified returns true if the user has confirmed the address
+func (e *NotificationEmail) IsVerified() bool {
+	return e.VerifiedAt != nil
+}
+
+// IsDeliverable returns true if notification email can be sent to the address
+func (e *NotificationEmail) IsDeliverable() bool {
+	return e.IsVerified() && e.UnsubscribedAt == nil
+}
+
+func newEmailToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -1,19 +1,32 @@
 package entity
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrInvalidTimezone is returned for a timezone that is not an IANA name
+var ErrInvalidTimezone = errors.New("timezone must be an IANA time zone name, e.g. Europe/Berlin")
+
+// ErrInvalidQuietHours is returned when only one end of quiet hours is set, or both are the same time
+var ErrInvalidQuietHours = errors.New("quiet hours need a different start and end time")
+
 // NotificationPreferences represents a user's notification settings
 type NotificationPreferences struct {
 	ID                  uuid.UUID
 	UserID              uuid.UUID
-	CriticalEnabled     bool      // Risk state change alerts
-	DailySummaryEnabled bool      // Daily summary notifications
-	DailySummaryTime    time.Time // Preferred time for daily summary (hour/minute only)
-	SlackWebhookURL     string    // Slack integration (Pro tier)
+	CriticalEnabled     bool       // Risk state change alerts
+	DailySummaryEnabled bool       // Daily summary notifications
+	DailySummaryTime    time.Time  // Preferred time for daily summary (hour/minute only), in Timezone
+	WeeklyDigestEnabled bool       // Weekly digest notifications
+	SlackWebhookURL     string     // Slack integration (Pro tier)
+	Timezone            string     // IANA time zone for DailySummaryTime and quiet hours
+	QuietHoursStart     *time.Time // Daily summaries due in quiet hours wait until they end (hour/minute only)
+	QuietHoursEnd       *time.Time
+	SkipWeekends        bool       // No daily summary on Saturday and Sunday
+	NextDailySummaryAt  *time.Time // Set by the scheduler; nil until scheduled
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
 }
@@ -29,7 +42,9 @@ func NewNotificationPreferences(userID uuid.UUID) *NotificationPreferences {
 		UserID:              userID,
 		CriticalEnabled:     true,  // Enabled by default
 		DailySummaryEnabled: true,  // Enabled by default
+		WeeklyDigestEnabled: true,  // Enabled by default
 		DailySummaryTime:    defaultTime,
+		Timezone:            "UTC",
 		CreatedAt:           now,
 		UpdatedAt:           now,
 	}
@@ -44,3 +59,100 @@ func (p *NotificationPreferences) ShouldSendCritical() bool {
 func (p *NotificationPreferences) ShouldSendDailySummary() bool {
 	return p.DailySummaryEnabled
 }
+
+// ShouldSendWeeklyDigest returns true if weekly digests are enabled
+func (p *NotificationPreferences) ShouldSendWeeklyDigest() bool {
+	return p.WeeklyDigestEnabled
+}
+
+// Location returns the preferences' time zone, or UTC if it is not set or invalid
+func (p *NotificationPreferences) Location() *time.Location {
+	if p.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// SetTimezone sets the time zone after checking it is a known IANA name
+func (p *NotificationPreferences) SetTimezone(name string) error {
+	if name == "" || name == "Local" {
+		return ErrInvalidTimezone
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return ErrInvalidTimezone
+	}
+	p.Timezone = name
+	return nil
+}
+
+// SetQuietHours sets the local quiet hours; both nil clears them. The range may
+// cross midnight, e.g. 22:00 to 07:00.
+func (p *NotificationPreferences) SetQuietHours(start, end *time.Time) error {
+	if start == nil && end == nil {
+		p.QuietHoursStart, p.QuietHoursEnd = nil, nil
+		return nil
+	}
+	if start == nil || end == nil || minuteOfDay(*start) == minuteOfDay(*end) {
+		return ErrInvalidQuietHours
+	}
+	p.QuietHoursStart, p.QuietHoursEnd = start, end
+	return nil
+}
+
+// NextDailySummaryAfter returns the first daily summary send time after after.
+// The summary is due at DailySummaryTime in the user's time zone; if that falls
+// in quiet hours it waits until they end, and with SkipWeekends a send time on
+// a Saturday or Sunday moves to Monday's.
+func (p *NotificationPreferences) NextDailySummaryAfter(after time.Time) time.Time {
+	loc := p.Location()
+	local := after.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	// A week ahead always has a weekday, so this ends within 8 days
+	for i := 0; ; i++ {
+		candidate := p.deferForQuietHours(atMinuteOfDay(day.AddDate(0, 0, i), minuteOfDay(p.DailySummaryTime)))
+		if !candidate.After(after) {
+			continue
+		}
+		if p.SkipWeekends {
+			if wd := candidate.In(loc).Weekday(); wd == time.Saturday || wd == time.Sunday {
+				continue
+			}
+		}
+		return candidate
+	}
+}
+
+// deferForQuietHours moves a local time in quiet hours to the end of them
+func (p *NotificationPreferences) deferForQuietHours(t time.Time) time.Time {
+	if p.QuietHoursStart == nil || p.QuietHoursEnd == nil {
+		return t
+	}
+	start, end, m := minuteOfDay(*p.QuietHoursStart), minuteOfDay(*p.QuietHoursEnd), minuteOfDay(t)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	switch {
+	case start < end && m >= start && m < end:
+		return atMinuteOfDay(day, end)
+	case start > end && m >= start: // Before midnight in quiet hours crossing midnight
+		return atMinuteOfDay(day.AddDate(0, 0, 1), end)
+	case start > end && m < end: // After midnight
+		return atMinuteOfDay(day, end)
+	}
+	return t
+}
+
+// minuteOfDay returns the hour and minute of t as minutes since midnight
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+// atMinuteOfDay returns the local time minutes after midnight on day. Times
+// skipped by a DST change are normalised forward by time.Date.
+func atMinuteOfDay(day time.Time, minutes int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), minutes/60, minutes%60, 0, 0, day.Location())
+}
@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// NotificationWebhookSecretPrefix is the prefix for generic webhook signing secrets
+	NotificationWebhookSecretPrefix = "whsec_"
+	// maxNotificationWebhookTestError bounds the stored error from the last test
+	maxNotificationWebhookTestError = 500
+)
+
+var (
+	// ErrInvalidNotificationWebhookChannel is returned for a channel that is not delivered to webhooks
+	ErrInvalidNotificationWebhookChannel = errors.New("channel must be one of SLACK, TEAMS, DISCORD, WEBHOOK")
+	// ErrInvalidNotificationWebhookName is returned when a webhook has no name
+	ErrInvalidNotificationWebhookName = errors.New("name is required")
+)
+
+// NotificationWebhook is a Slack, Microsoft Teams, Discord or generic webhook a
+// user receives notifications on. A user can have several per channel; every
+// enabled webhook of a channel receives the channel's notifications.
+type NotificationWebhook struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	Channel         NotificationChannel // SLACK, TEAMS, DISCORD or WEBHOOK
+	Name            string
+	URL             string
+	Secret          string // WEBHOOK only: plaintext HMAC-SHA256 signing secret; only set on create and rotation
+	EncryptedSecret []byte // WEBHOOK only: signing secret encrypted at rest
+	Enabled         bool
+	LastTestedAt    *time.Time
+	LastTestError   string // Empty if the last test succeeded
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// NewNotificationWebhook creates an enabled webhook. Generic webhooks get a
+// random signing secret.
+func NewNotificationWebhook(userID uuid.UUID, channel NotificationChannel, name, url string, now time.Time) (*NotificationWebhook, error) {
+	if !channel.IsWebhook() {
+		return nil, ErrInvalidNotificationWebhookChannel
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidNotificationWebhookName
+	}
+
+	webhook := &NotificationWebhook{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Channel:   channel,
+		Name:      name,
+		URL:       strings.TrimSpace(url),
+		Enabled:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if channel == NotificationChannelWebhook {
+		if err := webhook.RotateSecret(now); err != nil {
+			return nil, err
+		}
+	}
+	return webhook, nil
+}
+
+// RotateSecret replaces the signing secret of a generic webhook
+func (w *NotificationWebhook) RotateSecret(now time.Time) error {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	w.Secret = NotificationWebhookSecretPrefix + hex.EncodeToString(b)
+	w.UpdatedAt = now
+	return nil
+}
+
+// RecordTest stores the outcome of a test notification; testErr is nil on success
+func (w *NotificationWebhook) RecordTest(now time.Time, testErr error) {
+	w.LastTestedAt = &now
+	w.LastTestError = ""
+	if testErr != nil {
+		w.LastTestError = testErr.Error()
+		if len(w.LastTestError) > maxNotificationWebhookTestError {
+			w.LastTestError = w.LastTestError[:maxNotificationWebhookTestError]
+		}
+	}
+	w.UpdatedAt = now
+}
@@ -9,7 +9,8 @@ import (
 
 type PartnerAccount struct {
 	ID                   uuid.UUID
-	UserID               uuid.UUID
+	UserID               uuid.UUID  // User who connected the account
+	WorkspaceID          *uuid.UUID // Workspace that owns the account; nil for accounts connected outside a workspace
 	IntegrationType      valueobject.IntegrationType
 	PartnerID            string
 	EncryptedAccessToken []byte
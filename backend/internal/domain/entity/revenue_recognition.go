@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+// RecognitionSegment recognizes AmountCents ratably per day over [Start, End).
+// A segment with Start == End is recognized in full on that day.
+type RecognitionSegment struct {
+	Start        time.Time
+	End          time.Time
+	AmountCents  int64
+	FromDeferred bool // False for direct adjustments (refunds exceeding the deferred balance)
+}
+
+// ScheduleAdjustment is a dated amount applied to a schedule (refund or deferred reduction)
+type ScheduleAdjustment struct {
+	Date        time.Time
+	AmountCents int64
+}
+
+// RecognitionAllocation is the revenue recognized from one schedule in one month
+type RecognitionAllocation struct {
+	Month           time.Time // First day of month (UTC)
+	RecognizedCents int64
+}
+
+// RecognitionSchedule describes how a single charge is recognized over its service period
+type RecognitionSchedule struct {
+	TransactionID   uuid.UUID
+	ShopifyGID      string
+	MyshopifyDomain string
+	ShopName        string
+	ChargeType      valueobject.ChargeType
+	Currency        string
+	BilledCents     int64     // Cash billed on BilledDate (0 for unmatched refunds)
+	BilledDate      time.Time // Day the charge was billed
+	ServiceStart    time.Time // Inclusive
+	ServiceEnd      time.Time // Exclusive; equal to ServiceStart for point-in-time charges
+	CancelledAt     *time.Time
+	Segments        []RecognitionSegment
+	Refunds         []ScheduleAdjustment // Refund cash issued against this charge
+	Reductions      []ScheduleAdjustment // Portion of refunds taken from the deferred balance
+}
+
+// RefundedCents returns the total refunded against this charge
+func (s *RecognitionSchedule) RefundedCents() int64 {
+	var total int64
+	for _, r := range s.Refunds {
+		total += r.AmountCents
+	}
+	return total
+}
+
+// IsRatable returns true if the charge is recognized over a service period
+func (s *RecognitionSchedule) IsRatable() bool {
+	return s.ServiceEnd.After(s.ServiceStart)
+}
+
+// MonthlyRevenueRecognition summarizes recognized and deferred revenue for one month and currency
+type MonthlyRevenueRecognition struct {
+	Month                time.Time // First day of month (UTC)
+	Currency             string
+	BilledCents          int64 // Cash billed in the month (all charge types)
+	RefundedCents        int64 // Refunds issued in the month
+	RecognizedCents      int64 // Revenue recognized in the month (accrual basis)
+	DeferredOpeningCents int64 // Deferred revenue balance at start of month
+	DeferredClosingCents int64 // Deferred revenue balance at end of month
+}
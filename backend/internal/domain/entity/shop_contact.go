@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidShopContactEmail is returned when a shop contact's email is not a valid address
+var ErrInvalidShopContactEmail = errors.New("email must be a valid address")
+
+// ShopContact is who to email about a shop's subscription, e.g. the merchant's
+// billing contact. Merchant emails of dunning sequences are sent to it.
+type ShopContact struct {
+	AppID           uuid.UUID
+	MyshopifyDomain string
+	Email           string
+	Name            string
+	UpdatedAt       time.Time
+}
+
+// NewShopContact creates a shop contact, validating the email
+func NewShopContact(appID uuid.UUID, domain, email, name string, now time.Time) (*ShopContact, error) {
+	addr, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil {
+		return nil, ErrInvalidShopContactEmail
+	}
+	return &ShopContact{
+		AppID:           appID,
+		MyshopifyDomain: strings.ToLower(strings.TrimSpace(domain)),
+		Email:           addr.Address,
+		Name:            strings.TrimSpace(name),
+		UpdatedAt:       now,
+	}, nil
+}
@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidTaxRate is returned when a tax rate is outside [0, 1)
+	ErrInvalidTaxRate = errors.New("tax rate must be between 0 and 1 (e.g., 0.13 for 13%)")
+
+	// ErrInvalidEffectivePeriod is returned when effective_to is not after effective_from
+	ErrInvalidEffectivePeriod = errors.New("effective_to must be after effective_from")
+
+	// ErrJurisdictionRequired is returned when the jurisdiction code is missing
+	ErrJurisdictionRequired = errors.New("jurisdiction is required")
+)
+
+// TaxPeriod is the granularity of the tax-on-fees report
+type TaxPeriod string
+
+const (
+	TaxPeriodMonth   TaxPeriod = "month"
+	TaxPeriodQuarter TaxPeriod = "quarter"
+	TaxPeriodYear    TaxPeriod = "year"
+)
+
+// IsValid returns true if the period is supported
+func (p TaxPeriod) IsValid() bool {
+	switch p {
+	case TaxPeriodMonth, TaxPeriodQuarter, TaxPeriodYear:
+		return true
+	}
+	return false
+}
+
+// PeriodStart returns the first day of the period containing t (UTC)
+func (p TaxPeriod) PeriodStart(t time.Time) time.Time {
+	t = t.UTC()
+	switch p {
+	case TaxPeriodQuarter:
+		month := time.Month((int(t.Month())-1)/3*3 + 1)
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	case TaxPeriodYear:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// PeriodEnd returns the last day of the period starting at start
+func (p TaxPeriod) PeriodEnd(start time.Time) time.Time {
+	switch p {
+	case TaxPeriodQuarter:
+		return start.AddDate(0, 3, -1)
+	case TaxPeriodYear:
+		return start.AddDate(1, 0, -1)
+	default:
+		return start.AddDate(0, 1, -1)
+	}
+}
+
+// TaxJurisdictionProfile is the tax a partner is charged on Shopify fees
+// (e.g., Canadian HST, Australian GST, EU VAT) for a date range.
+// Profiles for a partner account must not overlap.
+type TaxJurisdictionProfile struct {
+	ID                 uuid.UUID
+	PartnerAccountID   uuid.UUID
+	Jurisdiction       string  // ISO country / subdivision code, e.g. CA-ON, AU, IE
+	TaxName            string  // GST, VAT, HST, ...
+	Rate               float64 // Fraction applied to fees, e.g. 0.13 for 13%
+	RegistrationNumber string  // Partner's tax registration number (for input tax credit claims)
+	EffectiveFrom      time.Time
+	EffectiveTo        *time.Time // Exclusive; nil = open-ended
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// NewTaxJurisdictionProfile creates a new open-ended tax profile
+func NewTaxJurisdictionProfile(partnerAccountID uuid.UUID, jurisdiction, taxName string, rate float64, effectiveFrom time.Time) *TaxJurisdictionProfile {
+	now := time.Now().UTC()
+	return &TaxJurisdictionProfile{
+		ID:               uuid.New(),
+		PartnerAccountID: partnerAccountID,
+		Jurisdiction:     jurisdiction,
+		TaxName:          taxName,
+		Rate:             rate,
+		EffectiveFrom:    effectiveFrom,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+// Validate checks the profile's rate and effective dates
+func (p *TaxJurisdictionProfile) Validate() error {
+	if p.Jurisdiction == "" {
+		return ErrJurisdictionRequired
+	}
+	if p.Rate < 0 || p.Rate >= 1 {
+		return ErrInvalidTaxRate
+	}
+	if p.EffectiveTo != nil && !p.EffectiveTo.After(p.EffectiveFrom) {
+		return ErrInvalidEffectivePeriod
+	}
+	return nil
+}
+
+// IsEffectiveOn returns true if the profile applies on the given date
+func (p *TaxJurisdictionProfile) IsEffectiveOn(t time.Time) bool {
+	if t.Before(p.EffectiveFrom) {
+		return false
+	}
+	return p.EffectiveTo == nil || t.Before(*p.EffectiveTo)
+}
+
+// Overlaps returns true if the two profiles' effective periods intersect
+func (p *TaxJurisdictionProfile) Overlaps(other *TaxJurisdictionProfile) bool {
+	startsBeforeOtherEnds := other.EffectiveTo == nil || p.EffectiveFrom.Before(*other.EffectiveTo)
+	otherStartsBeforeEnd := p.EffectiveTo == nil || other.EffectiveFrom.Before(*p.EffectiveTo)
+	return startsBeforeOtherEnds && otherStartsBeforeEnd
+}
+
+// RatePercent returns the rate as a percentage (e.g., 13.0)
+func (p *TaxJurisdictionProfile) RatePercent() float64 {
+	return p.Rate * 100
+}
+
+// EffectiveTaxProfile returns the profile in effect on the given date, or nil
+func EffectiveTaxProfile(profiles []*TaxJurisdictionProfile, t time.Time) *TaxJurisdictionProfile {
+	for _, p := range profiles {
+		if p.IsEffectiveOn(t) {
+			return p
+		}
+	}
+	return nil
+}
+
+// TaxOnFeesPeriod is one row of the tax-on-fees report
+type TaxOnFeesPeriod struct {
+	PeriodStart         time.Time
+	PeriodEnd           time.Time // Inclusive
+	Currency            string
+	Jurisdiction        string // Empty when no profile applied
+	TaxName             string
+	Rate                float64
+	TransactionCount    int
+	RevenueShareCents   int64
+	ProcessingFeeCents  int64
+	TaxableFeesCents    int64 // Revenue share + processing fee
+	TaxChargedCents     int64 // Tax on fees actually charged by Shopify
+	ExpectedTaxCents    int64 // Rate applied to taxable fees
+	TaxDiscrepancyCents int64 // Charged - expected
+}
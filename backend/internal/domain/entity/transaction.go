@@ -25,6 +25,7 @@ type Transaction struct {
 	ShopifyShopGID  string // Shopify shop GID (gid://shopify/Shop/xxx)
 	ShopPlan        string // Shop's Shopify plan (Basic, Shopify, Advanced, Plus)
 	ChargeType      valueobject.ChargeType
+	ChargeGID       string // Charge that produced the sale (the AppUsageRecord for usage sales)
 	GrossAmountCents   int64 // What the merchant paid (from Shopify Partner API)
 	ShopifyFeeCents    int64 // Revenue share deducted (0%, 15%, or 20%)
 	ProcessingFeeCents int64 // Processing fee (2.9%)
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookDeliveryStatus is the processing state of an inbound Shopify webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"   // Waiting for a processing attempt
+	WebhookDeliveryProcessed WebhookDeliveryStatus = "PROCESSED" // Applied
+	WebhookDeliverySkipped   WebhookDeliveryStatus = "SKIPPED"   // Older than an event already applied to the same resource
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"    // Gave up after the maximum number of attempts
+)
+
+// IsValid returns true if the status is known
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliveryProcessed, WebhookDeliverySkipped, WebhookDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery is a verified Shopify webhook, stored before it is processed
+type WebhookDelivery struct {
+	ID            uuid.UUID
+	WebhookID     string // X-Shopify-Webhook-Id; Shopify reuses it when retrying
+	Topic         string
+	ShopDomain    string
+	AppGID        string // Shopify app GID from the webhook URL; empty if not given
+	ResourceKey   string // Resource whose state the event replaces (e.g. subscription GID); empty if none
+	Payload       json.RawMessage
+	TriggeredAt   time.Time // When Shopify raised the event; orders events for the same resource
+	Status        WebhookDeliveryStatus
+	Attempts      int
+	NextAttemptAt *time.Time
+	LastError     string
+	ReceivedAt    time.Time
+	ProcessedAt   *time.Time
+}
+
+// NewWebhookDelivery creates a pending delivery due immediately
+func NewWebhookDelivery(webhookID, topic, shopDomain, appGID string, payload []byte, triggeredAt time.Time) *WebhookDelivery {
+	now := time.Now().UTC()
+	return &WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhookID,
+		Topic:         topic,
+		ShopDomain:    shopDomain,
+		AppGID:        appGID,
+		Payload:       payload,
+		TriggeredAt:   triggeredAt,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: &now,
+		ReceivedAt:    now,
+	}
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSecret is a secret Shopify signs an app's webhooks with. The secret
+// itself is only stored encrypted; SecretHint keeps its last characters so
+// partners can tell secrets apart.
+type WebhookSecret struct {
+	ID              uuid.UUID
+	AppID           uuid.UUID
+	Label           string
+	EncryptedSecret []byte
+	SecretHint      string
+	ExpiresAt       *time.Time // Set when a rotation retires the secret; nil = no expiry
+	LastUsedAt      *time.Time // Last time the secret verified a webhook
+	CreatedAt       time.Time
+	RevokedAt       *time.Time
+}
+
+// NewWebhookSecret creates a webhook secret for an app
+func NewWebhookSecret(appID uuid.UUID, label string, encryptedSecret []byte, secretHint string) *WebhookSecret {
+	return &WebhookSecret{
+		ID:              uuid.New(),
+		AppID:           appID,
+		Label:           label,
+		EncryptedSecret: encryptedSecret,
+		SecretHint:      secretHint,
+		CreatedAt:       time.Now().UTC(),
+	}
+}
+
+// IsActive returns true if the secret is neither revoked nor expired at now
+func (s *WebhookSecret) IsActive(now time.Time) bool {
+	if s.RevokedAt != nil {
+		return false
+	}
+	return s.ExpiresAt == nil || now.Before(*s.ExpiresAt)
+}
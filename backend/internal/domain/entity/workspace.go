@@ -0,0 +1,177 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+// WorkspaceInvitationTTL is how long an invitation link stays valid
+const WorkspaceInvitationTTL = 7 * 24 * time.Hour
+
+// MaxWorkspaceNameLength bounds workspace names
+const MaxWorkspaceNameLength = 100
+
+var (
+	// ErrInvalidWorkspaceName is returned when a workspace name is empty or too long
+	ErrInvalidWorkspaceName = errors.New("workspace name must be 1-100 characters")
+	// ErrInvalidWorkspaceRole is returned for roles other than OWNER, ADMIN, ANALYST or VIEWER
+	ErrInvalidWorkspaceRole = errors.New("role must be OWNER, ADMIN, ANALYST or VIEWER")
+	// ErrWorkspaceInvitationExpired is returned when an invitation is accepted after WorkspaceInvitationTTL
+	ErrWorkspaceInvitationExpired = errors.New("workspace invitation has expired")
+	// ErrWorkspaceInvitationClosed is returned when an invitation was already accepted or revoked
+	ErrWorkspaceInvitationClosed = errors.New("workspace invitation is no longer open")
+)
+
+// Workspace is a team that owns a partner account and its apps. Every user
+// gets a personal workspace on first login; others join by invitation.
+type Workspace struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedBy uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewWorkspace creates a workspace. The creator should be added as its OWNER.
+func NewWorkspace(name string, createdBy uuid.UUID, now time.Time) (*Workspace, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > MaxWorkspaceNameLength {
+		return nil, ErrInvalidWorkspaceName
+	}
+
+	return &Workspace{
+		ID:        uuid.New(),
+		Name:      name,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Rename changes the workspace name
+func (w *Workspace) Rename(name string, now time.Time) error {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > MaxWorkspaceNameLength {
+		return ErrInvalidWorkspaceName
+	}
+	w.Name = name
+	w.UpdatedAt = now
+	return nil
+}
+
+// WorkspaceMember is a user's membership of a workspace. The role decides
+// what the user may do inside the workspace.
+type WorkspaceMember struct {
+	WorkspaceID uuid.UUID
+	UserID      uuid.UUID
+	Email       string // Denormalized from the user for member lists
+	Role        valueobject.Role
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewWorkspaceMember adds a user to a workspace with the given role
+func NewWorkspaceMember(workspaceID, userID uuid.UUID, email string, role valueobject.Role, now time.Time) (*WorkspaceMember, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidWorkspaceRole
+	}
+
+	return &WorkspaceMember{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Email:       strings.ToLower(strings.TrimSpace(email)),
+		Role:        role,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// WorkspaceInvitation invites an email address to join a workspace. The token
+// is emailed to the invitee and accepted by whichever user signs in with it.
+// Only its SHA-256 hash is stored, like API keys.
+type WorkspaceInvitation struct {
+	ID          uuid.UUID
+	WorkspaceID uuid.UUID
+	Email       string // Lower-cased
+	Role        valueobject.Role
+	Token       string // Plaintext, only set on a newly created invitation
+	TokenHash   string // Cleared once accepted or revoked
+	InvitedBy   uuid.UUID
+	ExpiresAt   time.Time
+	AcceptedAt  *time.Time
+	AcceptedBy  *uuid.UUID
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// NewWorkspaceInvitation creates an open invitation with a fresh token
+func NewWorkspaceInvitation(workspaceID uuid.UUID, email string, role valueobject.Role, invitedBy uuid.UUID, now time.Time) (*WorkspaceInvitation, error) {
+	parsed, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || parsed.Name != "" {
+		return nil, ErrInvalidEmailAddress
+	}
+	if !role.IsValid() {
+		return nil, ErrInvalidWorkspaceRole
+	}
+
+	token, err := newEmailToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &WorkspaceInvitation{
+		ID:          uuid.New(),
+		WorkspaceID: workspaceID,
+		Email:       strings.ToLower(parsed.Address),
+		Role:        role,
+		Token:       token,
+		TokenHash:   HashInvitationToken(token),
+		InvitedBy:   invitedBy,
+		ExpiresAt:   now.Add(WorkspaceInvitationTTL),
+		CreatedAt:   now,
+	}, nil
+}
+
+// HashInvitationToken hashes an invitation token using SHA-256
+func HashInvitationToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// IsOpen returns true if the invitation can still be accepted
+func (i *WorkspaceInvitation) IsOpen(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// Accept marks the invitation accepted by the given user
+func (i *WorkspaceInvitation) Accept(userID uuid.UUID, now time.Time) error {
+	if i.AcceptedAt != nil || i.RevokedAt != nil {
+		return ErrWorkspaceInvitationClosed
+	}
+	if !now.Before(i.ExpiresAt) {
+		return ErrWorkspaceInvitationExpired
+	}
+	i.AcceptedAt = &now
+	i.AcceptedBy = &userID
+	i.Token = ""
+	i.TokenHash = ""
+	return nil
+}
+
+// Revoke cancels an open invitation
+func (i *WorkspaceInvitation) Revoke(now time.Time) error {
+	if i.AcceptedAt != nil || i.RevokedAt != nil {
+		return ErrWorkspaceInvitationClosed
+	}
+	i.RevokedAt = &now
+	i.Token = ""
+	i.TokenHash = ""
+	return nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// ErrGLAccountMappingNotFound is returned when an app has no saved GL mapping
+var ErrGLAccountMappingNotFound = errors.New("gl account mapping not found")
+
+// GLAccountMappingRepository defines operations for per-app GL account mappings
+type GLAccountMappingRepository interface {
+	// FindByAppID returns the mapping for an app.
+	// Returns ErrGLAccountMappingNotFound if the app has no saved mapping.
+	FindByAppID(ctx context.Context, appID uuid.UUID) (*entity.GLAccountMapping, error)
+
+	// Upsert creates or replaces the mapping for an app (unique by app_id)
+	Upsert(ctx context.Context, mapping *entity.GLAccountMapping) error
+}
+
+// AccountingExportRunRepository defines operations for accounting export history
+type AccountingExportRunRepository interface {
+	// CreateIfNoOverlap records a completed export run unless another run for the
+	// app intersects its period, and returns the intersecting runs. The check and
+	// insert share a transaction that locks the app row, so concurrent exports of
+	// the same period cannot both be recorded. With allowOverlap the run is
+	// recorded anyway and marked confirmed.
+	CreateIfNoOverlap(ctx context.Context, run *entity.AccountingExportRun, allowOverlap bool) ([]*entity.AccountingExportRun, error)
+
+	// FindByAppID returns export runs for an app, newest first
+	FindByAppID(ctx context.Context, appID uuid.UUID, limit int) ([]*entity.AccountingExportRun, error)
+
+	// FindOverlapping returns runs for an app whose period intersects [from, to]
+	FindOverlapping(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.AccountingExportRun, error)
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// AlertFilter selects triggered alerts
+type AlertFilter struct {
+	UserID uuid.UUID
+	AppID  uuid.UUID
+	Status entity.AlertStatus // Empty = any
+	Limit  int
+}
+
+// AlertRuleRepository defines operations for user-defined alert rules
+type AlertRuleRepository interface {
+	// Create stores a new rule
+	Create(ctx context.Context, rule *entity.AlertRule) error
+
+	// Update replaces a rule's settings
+	Update(ctx context.Context, rule *entity.AlertRule) error
+
+	// Delete removes a rule of the user, with its alerts
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+
+	// FindByID finds a rule by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.AlertRule, error)
+
+	// FindByUserAndApp returns the user's rules for an app, oldest first
+	FindByUserAndApp(ctx context.Context, userID, appID uuid.UUID) ([]*entity.AlertRule, error)
+
+	// FindEnabledByAppID returns every user's enabled rules for an app
+	FindEnabledByAppID(ctx context.Context, appID uuid.UUID) ([]*entity.AlertRule, error)
+}
+
+// AlertRepository defines operations for triggered alerts
+type AlertRepository interface {
+	// Create stores a triggered alert and its notification in one transaction
+	Create(ctx context.Context, alert *entity.Alert, notification *entity.Notification) error
+
+	// Update stores an alert's status
+	Update(ctx context.Context, alert *entity.Alert) error
+
+	// FindByID finds an alert by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Alert, error)
+
+	// List returns the most recent alerts matching the filter
+	List(ctx context.Context, filter AlertFilter) ([]*entity.Alert, error)
+
+	// FindLatestByRuleAndKey returns the rule's most recent alert for the key, or nil
+	FindLatestByRuleAndKey(ctx context.Context, ruleID uuid.UUID, key string) (*entity.Alert, error)
+
+	// FindUnresolvedByRuleID returns the rule's open and acknowledged alerts
+	FindUnresolvedByRuleID(ctx context.Context, ruleID uuid.UUID) ([]*entity.Alert, error)
+}
+
+// AppSyncStatusRepository stores the outcome of each app's syncs
+type AppSyncStatusRepository interface {
+	// Upsert stores an app's sync status
+	Upsert(ctx context.Context, status *entity.AppSyncStatus) error
+
+	// FindByAppID returns an app's sync status
+	FindByAppID(ctx context.Context, appID uuid.UUID) (*entity.AppSyncStatus, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// ComplianceRequestRepository stores the record of handled Shopify compliance webhooks
+type ComplianceRequestRepository interface {
+	// Create stores a compliance record
+	Create(ctx context.Context, request *entity.ComplianceRequest) error
+
+	// FindByShopDomain returns the records for a shop, newest first
+	FindByShopDomain(ctx context.Context, shopDomain string) ([]*entity.ComplianceRequest, error)
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+// DunningRunFilter selects a user's dunning runs
+type DunningRunFilter struct {
+	UserID     uuid.UUID
+	AppID      uuid.UUID
+	SequenceID *uuid.UUID              // nil = any sequence
+	Status     entity.DunningRunStatus // Empty = any
+	Limit      int
+}
+
+// DunningTaskFilter selects a user's dunning tasks
+type DunningTaskFilter struct {
+	UserID uuid.UUID
+	AppID  uuid.UUID
+	Status entity.DunningTaskStatus // Empty = any
+	Limit  int
+}
+
+// DunningSequenceRepository defines operations for dunning sequences
+type DunningSequenceRepository interface {
+	// Create stores a new sequence
+	Create(ctx context.Context, sequence *entity.DunningSequence) error
+
+	// Update replaces a sequence's settings and steps
+	Update(ctx context.Context, sequence *entity.DunningSequence) error
+
+	// Delete removes a sequence of the user, with its runs
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+
+	// FindByID finds a sequence by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.DunningSequence, error)
+
+	// FindByUserAndApp returns the user's sequences for an app, oldest first
+	FindByUserAndApp(ctx context.Context, userID, appID uuid.UUID) ([]*entity.DunningSequence, error)
+
+	// FindEnabledByTrigger returns every user's enabled sequences for an app started by the risk state
+	FindEnabledByTrigger(ctx context.Context, appID uuid.UUID, trigger valueobject.RiskState) ([]*entity.DunningSequence, error)
+}
+
+// DunningRunRepository defines operations for dunning runs
+type DunningRunRepository interface {
+	// Create stores a new run. Returns false without an error if the
+	// sequence already has an active run for the subscription.
+	Create(ctx context.Context, run *entity.DunningRun) (bool, error)
+
+	// Update saves a run's progress and releases the lease
+	Update(ctx context.Context, run *entity.DunningRun) error
+
+	// FindByID finds a run by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.DunningRun, error)
+
+	// List returns the runs matching the filter, newest first
+	List(ctx context.Context, filter DunningRunFilter) ([]*entity.DunningRun, error)
+
+	// FindRecoverable returns the subscription's active runs and the runs
+	// exhausted since the given time
+	FindRecoverable(ctx context.Context, subscriptionID uuid.UUID, exhaustedSince time.Time) ([]*entity.DunningRun, error)
+
+	// FindStartedBetween returns the user's runs for an app started in [from, to)
+	FindStartedBetween(ctx context.Context, userID, appID uuid.UUID, from, to time.Time) ([]*entity.DunningRun, error)
+
+	// ClaimDue leases up to limit active runs with a step due, oldest first
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.DunningRun, error)
+}
+
+// DunningTaskRepository defines operations for follow-up tasks of dunning runs
+type DunningTaskRepository interface {
+	// Create stores a new task
+	Create(ctx context.Context, task *entity.DunningTask) error
+
+	// Update saves a task's status
+	Update(ctx context.Context, task *entity.DunningTask) error
+
+	// FindByID finds a task by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.DunningTask, error)
+
+	// List returns the tasks matching the filter, newest first
+	List(ctx context.Context, filter DunningTaskFilter) ([]*entity.DunningTask, error)
+
+	// CancelOpenByRunID cancels a run's open tasks
+	CancelOpenByRunID(ctx context.Context, runID uuid.UUID) error
+}
+
+// ShopContactRepository defines operations for shops' contact emails
+type ShopContactRepository interface {
+	// Upsert stores a shop's contact, replacing any previous one
+	Upsert(ctx context.Context, contact *entity.ShopContact) error
+
+	// FindByDomain returns an app's contact for a shop, or nil if it has none
+	FindByDomain(ctx context.Context, appID uuid.UUID, domain string) (*entity.ShopContact, error)
+
+	// Delete removes an app's contact for a shop, if any
+	Delete(ctx context.Context, appID uuid.UUID, domain string) error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// NotificationEmailRepository defines operations for users' notification email addresses
+type NotificationEmailRepository interface {
+	// Create stores a new address. Fails if the user already has it.
+	Create(ctx context.Context, email *entity.NotificationEmail) error
+
+	// FindByID returns an address by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.NotificationEmail, error)
+
+	// FindByUserID returns all of the user's addresses, oldest first
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationEmail, error)
+
+	// FindByVerificationTokenHash returns the address whose verification token hashes to tokenHash
+	FindByVerificationTokenHash(ctx context.Context, tokenHash string) (*entity.NotificationEmail, error)
+
+	// FindByUnsubscribeTokenHash returns the address whose unsubscribe token hashes to tokenHash
+	FindByUnsubscribeTokenHash(ctx context.Context, tokenHash string) (*entity.NotificationEmail, error)
+
+	// Update saves verification and unsubscribe state
+	Update(ctx context.Context, email *entity.NotificationEmail) error
+
+	// Delete removes one of the user's addresses
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
@@ -20,4 +21,12 @@ type NotificationPreferencesRepository interface {
 
 	// Upsert creates or updates notification preferences
 	Upsert(ctx context.Context, prefs *entity.NotificationPreferences) error
+
+	// ClaimDueDailySummaries leases up to limit users with daily summaries
+	// enabled whose next send time has passed or is not computed yet, so only
+	// one scheduler replica handles each
+	ClaimDueDailySummaries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.NotificationPreferences, error)
+
+	// SetNextDailySummaryAt stores the next daily summary send time, releasing the lease
+	SetNextDailySummaryAt(ctx context.Context, userID uuid.UUID, next time.Time) error
 }
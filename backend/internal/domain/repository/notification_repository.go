@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// NotificationFilter selects a user's notifications
+type NotificationFilter struct {
+	UserID     uuid.UUID
+	UnreadOnly bool
+	Limit      int
+}
+
+// NotificationRepository defines operations for the notification outbox
+type NotificationRepository interface {
+	// Create stores a new notification for delivery
+	Create(ctx context.Context, notification *entity.Notification) error
+
+	// CreateDailySummary stores the user's daily summary for a local date, at
+	// most once per date. Returns false without an error if one was already stored.
+	CreateDailySummary(ctx context.Context, notification *entity.Notification, summaryDate time.Time) (bool, error)
+
+	// FindByID returns a notification by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error)
+
+	// List returns the user's notifications matching the filter, newest first
+	List(ctx context.Context, filter NotificationFilter) ([]*entity.Notification, error)
+
+	// CountUnread returns how many of the user's notifications are unread
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+
+	// MarkRead marks one of the user's notifications as read
+	MarkRead(ctx context.Context, userID, id uuid.UUID, readAt time.Time) error
+
+	// MarkAllRead marks every unread notification of the user as read and returns how many changed
+	MarkAllRead(ctx context.Context, userID uuid.UUID, readAt time.Time) (int64, error)
+
+	// ClaimDue leases up to limit due pending notifications, oldest first
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.Notification, error)
+
+	// Update saves the outcome of a delivery attempt and releases the lease
+	Update(ctx context.Context, notification *entity.Notification) error
+
+	// CreateAttempt records a delivery attempt on one channel
+	CreateAttempt(ctx context.Context, attempt *entity.NotificationAttempt) error
+
+	// FindAttempts returns a notification's delivery attempts, oldest first
+	FindAttempts(ctx context.Context, notificationID uuid.UUID) ([]*entity.NotificationAttempt, error)
+}
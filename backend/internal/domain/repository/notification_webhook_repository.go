@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// NotificationWebhookRepository defines operations for users' notification webhooks
+type NotificationWebhookRepository interface {
+	// Create stores a new webhook
+	Create(ctx context.Context, webhook *entity.NotificationWebhook) error
+
+	// FindByID returns a webhook by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.NotificationWebhook, error)
+
+	// FindByUserID returns all of the user's webhooks, oldest first
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationWebhook, error)
+
+	// Update saves a webhook's settings, encrypted secret and last test result
+	Update(ctx context.Context, webhook *entity.NotificationWebhook) error
+
+	// Delete removes one of the user's webhooks
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
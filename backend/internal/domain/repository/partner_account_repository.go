@@ -11,6 +11,11 @@ type PartnerAccountRepository interface {
 	Create(ctx context.Context, account *entity.PartnerAccount) error
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.PartnerAccount, error)
 	FindByUserID(ctx context.Context, userID uuid.UUID) (*entity.PartnerAccount, error)
+	// FindByWorkspaceID returns the partner account owned by a workspace
+	FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (*entity.PartnerAccount, error)
+	// FindAccessibleByUserID returns the partner accounts the user can read: the
+	// one they connected and those of the workspaces they are a member of, oldest first
+	FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PartnerAccount, error)
 	FindByPartnerID(ctx context.Context, partnerID string) (*entity.PartnerAccount, error)
 	Update(ctx context.Context, account *entity.PartnerAccount) error
 	Delete(ctx context.Context, userID uuid.UUID) error
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShopRedaction counts what a shop redaction removed or anonymized
+type ShopRedaction struct {
+	TransactionsRedacted int64
+	SubscriptionsDeleted int64
+	EventsDeleted        int64
+}
+
+// ShopDataRepository changes everything stored about a shop across the ledger,
+// the subscription tables and the Revenue API read model
+type ShopDataRepository interface {
+	// UpdateShopDetails refreshes the shop name and Shopify plan recorded for a shop
+	// of the given apps. Returns the number of subscriptions updated.
+	UpdateShopDetails(ctx context.Context, appIDs []uuid.UUID, myshopifyDomain, shopName, shopPlan string) (int64, error)
+
+	// RedactShop removes a shop's subscriptions, their events and read model rows, and
+	// anonymizes its transactions under placeholderDomain, in one transaction. Stored
+	// webhook payloads mentioning the shop are redacted and its contacts deleted as well.
+	RedactShop(ctx context.Context, appIDs []uuid.UUID, appGID, myshopifyDomain, placeholderDomain string, redactedAt time.Time) (*ShopRedaction, error)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
@@ -60,6 +61,8 @@ type SubscriptionRepository interface {
 	FindByShopifyGID(ctx context.Context, shopifyGID string) (*entity.Subscription, error)
 	FindByAppIDAndDomain(ctx context.Context, appID uuid.UUID, myshopifyDomain string) (*entity.Subscription, error)
 	FindByRiskState(ctx context.Context, appID uuid.UUID, riskState valueobject.RiskState) ([]*entity.Subscription, error)
+	// FindUpdatedSince returns subscriptions updated after since, including soft-deleted ones
+	FindUpdatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*entity.Subscription, error)
 	DeleteByAppID(ctx context.Context, appID uuid.UUID) error
 
 	// Soft delete operations (preserves historical data)
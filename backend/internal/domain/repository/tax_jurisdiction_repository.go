@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// TaxJurisdictionProfileRepository defines operations for partner tax-on-fees profiles
+type TaxJurisdictionProfileRepository interface {
+	// Create stores a new profile
+	Create(ctx context.Context, profile *entity.TaxJurisdictionProfile) error
+
+	// Update saves changes to an existing profile
+	Update(ctx context.Context, profile *entity.TaxJurisdictionProfile) error
+
+	// Delete removes a profile owned by the partner account
+	Delete(ctx context.Context, partnerAccountID, id uuid.UUID) error
+
+	// FindByPartnerAccountID returns all profiles for a partner account, ordered by effective_from
+	FindByPartnerAccountID(ctx context.Context, partnerAccountID uuid.UUID) ([]*entity.TaxJurisdictionProfile, error)
+}
@@ -31,6 +31,9 @@ type TransactionRepository interface {
 	// FindByAppID returns transactions for an app within date range
 	FindByAppID(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.Transaction, error)
 
+	// FindCreatedSince returns transactions first stored after since, oldest first
+	FindCreatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*entity.Transaction, error)
+
 	// FindByShopifyGID finds a transaction by its Shopify GID
 	FindByShopifyGID(ctx context.Context, shopifyGID string) (*entity.Transaction, error)
 
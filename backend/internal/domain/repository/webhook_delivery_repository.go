@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// WebhookDeliveryFilter narrows a webhook delivery listing. Empty fields match everything.
+type WebhookDeliveryFilter struct {
+	AppGID string
+	Status entity.WebhookDeliveryStatus
+	Topic  string
+	Limit  int
+}
+
+// WebhookDeliveryRepository defines operations for inbound Shopify webhook deliveries
+type WebhookDeliveryRepository interface {
+	// Create stores a new delivery. Returns false if a delivery with the same webhook ID exists.
+	Create(ctx context.Context, delivery *entity.WebhookDelivery) (bool, error)
+
+	// FindByID returns a delivery by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error)
+
+	// List returns deliveries matching the filter, newest first
+	List(ctx context.Context, filter WebhookDeliveryFilter) ([]*entity.WebhookDelivery, error)
+
+	// ClaimDue leases up to limit due pending deliveries, oldest event first
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error)
+
+	// Claim leases one delivery in any status unless another lease holds it.
+	// Returns false if the delivery is leased.
+	Claim(ctx context.Context, id uuid.UUID, now time.Time, lease time.Duration) (bool, error)
+
+	// Update saves the outcome of a processing attempt and releases the lease
+	Update(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// HasNewerProcessed returns true if a processed delivery of the topic for the
+	// same resource was triggered after triggeredAt
+	HasNewerProcessed(ctx context.Context, topic, resourceKey string, triggeredAt time.Time) (bool, error)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// WebhookSecretRepository defines operations for per-app Shopify webhook secrets
+type WebhookSecretRepository interface {
+	// Create stores a new secret
+	Create(ctx context.Context, secret *entity.WebhookSecret) error
+
+	// FindByAppID returns the app's secrets that are not revoked, newest first (expired ones included)
+	FindByAppID(ctx context.Context, appID uuid.UUID) ([]*entity.WebhookSecret, error)
+
+	// FindActiveByAppIDs returns the secrets of any of the apps that are active at now
+	FindActiveByAppIDs(ctx context.Context, appIDs []uuid.UUID, now time.Time) ([]*entity.WebhookSecret, error)
+
+	// ExpireOthers sets expires_at on the app's active secrets other than keepID
+	// that would otherwise outlive expiresAt
+	ExpireOthers(ctx context.Context, appID, keepID uuid.UUID, expiresAt time.Time) error
+
+	// Revoke marks a secret of the app as revoked
+	Revoke(ctx context.Context, appID, id uuid.UUID, revokedAt time.Time) error
+
+	// MarkUsed records that a secret verified a webhook
+	MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
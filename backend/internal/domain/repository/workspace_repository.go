@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+// WorkspaceRepository defines operations for workspaces
+type WorkspaceRepository interface {
+	// Create stores a new workspace together with its first member
+	Create(ctx context.Context, workspace *entity.Workspace, owner *entity.WorkspaceMember) error
+
+	// FindByID returns a workspace by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Workspace, error)
+
+	// FindByUserID returns the workspaces the user is a member of, oldest membership first
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Workspace, error)
+
+	// Update saves the workspace name
+	Update(ctx context.Context, workspace *entity.Workspace) error
+}
+
+// WorkspaceMemberRepository defines operations for workspace memberships
+type WorkspaceMemberRepository interface {
+	// Create adds a member. Fails if the user is already a member.
+	Create(ctx context.Context, member *entity.WorkspaceMember) error
+
+	// Find returns the user's membership of a workspace, or nil if the user is not a member
+	Find(ctx context.Context, workspaceID, userID uuid.UUID) (*entity.WorkspaceMember, error)
+
+	// FindByUserID returns the user's memberships, oldest first
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WorkspaceMember, error)
+
+	// FindByWorkspaceID returns the workspace's members, oldest first
+	FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]*entity.WorkspaceMember, error)
+
+	// CountByRole returns how many members of the workspace hold the role
+	CountByRole(ctx context.Context, workspaceID uuid.UUID, role valueobject.Role) (int, error)
+
+	// Update saves the member's role
+	Update(ctx context.Context, member *entity.WorkspaceMember) error
+
+	// Delete removes a member from the workspace
+	Delete(ctx context.Context, workspaceID, userID uuid.UUID) error
+}
+
+// WorkspaceInvitationRepository defines operations for workspace invitations
+type WorkspaceInvitationRepository interface {
+	// Create stores a new invitation
+	Create(ctx context.Context, invitation *entity.WorkspaceInvitation) error
+
+	// FindByID returns an invitation of the workspace by ID
+	FindByID(ctx context.Context, workspaceID, id uuid.UUID) (*entity.WorkspaceInvitation, error)
+
+	// FindByTokenHash returns the invitation whose token hashes to tokenHash
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.WorkspaceInvitation, error)
+
+	// FindByWorkspaceID returns the workspace's invitations, newest first
+	FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]*entity.WorkspaceInvitation, error)
+
+	// Update saves acceptance and revocation state
+	Update(ctx context.Context, invitation *entity.WorkspaceInvitation) error
+
+	// Accept saves an accepted invitation together with the member it adds. Fails,
+	// adding no one, if the invitation was accepted or revoked in the meantime.
+	Accept(ctx context.Context, invitation *entity.WorkspaceInvitation, member *entity.WorkspaceMember) error
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"math"
+	"sort"
+	"time"
 
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
@@ -32,6 +34,12 @@ type FeeVerificationResult struct {
 	ActualTotalFeesCents     int64
 	ActualNetAmountCents     int64
 
+	// Tax on fees (only verified when a tax profile applies)
+	TaxProfile                *entity.TaxJurisdictionProfile
+	ExpectedTaxOnFeesCents    int64
+	ActualTaxOnFeesCents      int64
+	TaxOnFeesDiscrepancyCents int64
+
 	// Discrepancies
 	RevenueShareDiscrepancyCents  int64
 	ProcessingFeeDiscrepancyCents int64
@@ -43,32 +51,59 @@ type FeeVerificationResult struct {
 	DiscrepancyPercent float64
 }
 
-// VerifyTransaction verifies a single transaction's fees against expected tier-based calculations
+// VerifyTransaction verifies a single transaction's fees against expected tier-based calculations.
+// Tax on fees is not verified; use VerifyTransactionWithTax when the partner's tax profile is known.
 func (s *FeeVerificationService) VerifyTransaction(
 	tx *entity.Transaction,
 	tier valueobject.RevenueShareTier,
 	tolerancePercent float64, // e.g., 0.01 for 1% tolerance
+) *FeeVerificationResult {
+	return s.VerifyTransactionWithTax(tx, tier, nil, tolerancePercent)
+}
+
+// VerifyTransactionWithTax verifies a transaction's fees including the tax on fees expected
+// under the partner's tax profile. A nil profile skips tax verification.
+// Like the fee summary and tax report, expected tax is computed on the fees Shopify actually
+// charged, so a misapplied fee shows up as a fee discrepancy and not also as a tax one.
+func (s *FeeVerificationService) VerifyTransactionWithTax(
+	tx *entity.Transaction,
+	tier valueobject.RevenueShareTier,
+	profile *entity.TaxJurisdictionProfile,
+	tolerancePercent float64,
 ) *FeeVerificationResult {
 	// Calculate expected fees based on tier
-	expected := tier.CalculateFeeBreakdown(tx.GrossAmountCents, 0) // Tax rate is variable, so we don't include it
+	expected := tier.CalculateFeeBreakdown(tx.GrossAmountCents, 0)
+
+	var expectedTax int64
+	if profile != nil {
+		expectedTax = expectedTaxOnFees(tx, profile.Rate)
+	}
+	expectedTotal := expected.RevenueShareCents + expected.ProcessingFeeCents + expectedTax
 
 	result := &FeeVerificationResult{
 		Transaction: tx,
 		Tier:        tier,
+		TaxProfile:  profile,
 
-		// Expected (excluding tax, as tax is variable)
+		// Expected (tax included only when a profile applies)
 		ExpectedRevenueShareCents:  expected.RevenueShareCents,
 		ExpectedProcessingFeeCents: expected.ProcessingFeeCents,
-		ExpectedTotalFeesCents:     expected.RevenueShareCents + expected.ProcessingFeeCents,
-		ExpectedNetAmountCents:     tx.GrossAmountCents - expected.RevenueShareCents - expected.ProcessingFeeCents,
+		ExpectedTaxOnFeesCents:     expectedTax,
+		ExpectedTotalFeesCents:     expectedTotal,
+		ExpectedNetAmountCents:     tx.GrossAmountCents - expectedTotal,
 
 		// Actual from Shopify
 		ActualRevenueShareCents:  tx.ShopifyFeeCents,
 		ActualProcessingFeeCents: tx.ProcessingFeeCents,
+		ActualTaxOnFeesCents:     tx.TaxOnFeesCents,
 		ActualTotalFeesCents:     tx.TotalFeesCents(),
 		ActualNetAmountCents:     tx.NetAmountCents,
 	}
 
+	if profile != nil {
+		result.TaxOnFeesDiscrepancyCents = result.ActualTaxOnFeesCents - result.ExpectedTaxOnFeesCents
+	}
+
 	// Calculate discrepancies
 	result.RevenueShareDiscrepancyCents = result.ActualRevenueShareCents - result.ExpectedRevenueShareCents
 	result.ProcessingFeeDiscrepancyCents = result.ActualProcessingFeeCents - result.ExpectedProcessingFeeCents
@@ -81,11 +116,20 @@ func (s *FeeVerificationService) VerifyTransaction(
 	}
 
 	// Determine if verified (within tolerance)
-	// Note: We allow some tolerance because tax is variable and not included in expected
 	toleranceAmount := int64(float64(tx.GrossAmountCents) * tolerancePercent)
 	result.IsVerified = math.Abs(float64(result.RevenueShareDiscrepancyCents)) <= float64(toleranceAmount) &&
 		math.Abs(float64(result.ProcessingFeeDiscrepancyCents)) <= float64(toleranceAmount)
 
+	// Tax is computed by Shopify on the rounded fees, so allow at least 1 cent of rounding
+	if profile != nil {
+		taxTolerance := toleranceAmount
+		if taxTolerance < 1 {
+			taxTolerance = 1
+		}
+		result.IsVerified = result.IsVerified &&
+			math.Abs(float64(result.TaxOnFeesDiscrepancyCents)) <= float64(taxTolerance)
+	}
+
 	return result
 }
 
@@ -95,6 +139,8 @@ type FeeSummary struct {
 	TotalRevenueShareCents    int64
 	TotalProcessingFeeCents   int64
 	TotalTaxOnFeesCents       int64
+	ExpectedTaxOnFeesCents    int64 // Tax on fees under the partner's tax profiles
+	TaxOnFeesDiscrepancyCents int64 // Charged - expected (0 when no profile applies)
 	TotalFeesCents            int64
 	TotalNetAmountCents       int64
 	TransactionCount          int
@@ -105,6 +151,16 @@ type FeeSummary struct {
 
 // CalculateFeeSummary calculates aggregated fee information for a list of transactions
 func (s *FeeVerificationService) CalculateFeeSummary(transactions []*entity.Transaction) *FeeSummary {
+	return s.CalculateFeeSummaryWithTax(transactions, nil)
+}
+
+// CalculateFeeSummaryWithTax calculates aggregated fee information, including the tax on fees
+// expected under the tax profile in effect on each transaction date.
+// As in the tax report, refunds reverse their expected tax and its discrepancy.
+func (s *FeeVerificationService) CalculateFeeSummaryWithTax(
+	transactions []*entity.Transaction,
+	profiles []*entity.TaxJurisdictionProfile,
+) *FeeSummary {
 	summary := &FeeSummary{
 		TransactionCount: len(transactions),
 	}
@@ -115,6 +171,16 @@ func (s *FeeVerificationService) CalculateFeeSummary(transactions []*entity.Tran
 		summary.TotalProcessingFeeCents += tx.ProcessingFeeCents
 		summary.TotalTaxOnFeesCents += tx.TaxOnFeesCents
 		summary.TotalNetAmountCents += tx.NetAmountCents
+
+		if profile := entity.EffectiveTaxProfile(profiles, tx.TransactionDate); profile != nil {
+			sign := int64(1)
+			if tx.ChargeType == valueobject.ChargeTypeRefund {
+				sign = -1
+			}
+			expected := expectedTaxOnFees(tx, profile.Rate)
+			summary.ExpectedTaxOnFeesCents += sign * expected
+			summary.TaxOnFeesDiscrepancyCents += sign * (tx.TaxOnFeesCents - expected)
+		}
 	}
 
 	summary.TotalFeesCents = summary.TotalRevenueShareCents +
@@ -161,3 +227,82 @@ func (s *FeeVerificationService) CalculateTierSavings(
 
 	return result
 }
+
+// BuildTaxOnFeesReport summarizes fees and the tax charged on them per period, currency and
+// tax profile, for filing input tax credits. A rate change inside a period produces one row per rate.
+// Refunds reverse their fees and tax.
+func (s *FeeVerificationService) BuildTaxOnFeesReport(
+	transactions []*entity.Transaction,
+	profiles []*entity.TaxJurisdictionProfile,
+	period entity.TaxPeriod,
+) []*entity.TaxOnFeesPeriod {
+	type key struct {
+		start     time.Time
+		currency  string
+		profileID string
+	}
+
+	rows := make(map[key]*entity.TaxOnFeesPeriod)
+	for _, tx := range transactions {
+		profile := entity.EffectiveTaxProfile(profiles, tx.TransactionDate)
+		start := period.PeriodStart(tx.TransactionDate)
+
+		k := key{start: start, currency: tx.Currency}
+		if profile != nil {
+			k.profileID = profile.ID.String()
+		}
+
+		row, ok := rows[k]
+		if !ok {
+			row = &entity.TaxOnFeesPeriod{
+				PeriodStart: start,
+				PeriodEnd:   period.PeriodEnd(start),
+				Currency:    tx.Currency,
+			}
+			if profile != nil {
+				row.Jurisdiction = profile.Jurisdiction
+				row.TaxName = profile.TaxName
+				row.Rate = profile.Rate
+			}
+			rows[k] = row
+		}
+
+		sign := int64(1)
+		if tx.ChargeType == valueobject.ChargeTypeRefund {
+			sign = -1
+		}
+
+		row.TransactionCount++
+		row.RevenueShareCents += sign * tx.ShopifyFeeCents
+		row.ProcessingFeeCents += sign * tx.ProcessingFeeCents
+		row.TaxableFeesCents += sign * (tx.ShopifyFeeCents + tx.ProcessingFeeCents)
+		row.TaxChargedCents += sign * tx.TaxOnFeesCents
+		if profile != nil {
+			row.ExpectedTaxCents += sign * expectedTaxOnFees(tx, profile.Rate)
+		}
+	}
+
+	result := make([]*entity.TaxOnFeesPeriod, 0, len(rows))
+	for _, row := range rows {
+		row.TaxDiscrepancyCents = row.TaxChargedCents - row.ExpectedTaxCents
+		result = append(result, row)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].PeriodStart.Equal(result[j].PeriodStart) {
+			return result[i].PeriodStart.Before(result[j].PeriodStart)
+		}
+		if result[i].Currency != result[j].Currency {
+			return result[i].Currency < result[j].Currency
+		}
+		return result[i].Jurisdiction < result[j].Jurisdiction
+	})
+
+	return result
+}
+
+// expectedTaxOnFees returns the tax expected on the fees Shopify actually charged,
+// truncated like RevenueShareTier.CalculateFeeBreakdown
+func expectedTaxOnFees(tx *entity.Transaction, rate float64) int64 {
+	return int64(float64(tx.ShopifyFeeCents+tx.ProcessingFeeCents) * rate)
+}
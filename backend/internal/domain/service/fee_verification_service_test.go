@@ -206,3 +206,142 @@ func createTransaction(grossCents, shopifyFeeCents, processingFeeCents, taxCents
 		TransactionDate:    date,
 	}
 }
+
+func TestFeeVerificationService_VerifyTransactionWithTax(t *testing.T) {
+	svc := NewFeeVerificationService()
+	profile := entity.NewTaxJurisdictionProfile(uuid.New(), "CA-ON", "HST", 0.13, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	// $49 gross, DEFAULT_20: fees 980 + 142 = 1122, 13% HST = 145
+	tx := createTransaction(4900, 980, 142, 145, 3633, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	result := svc.VerifyTransactionWithTax(tx, valueobject.RevenueShareTierDefault, profile, 0)
+	if result.ExpectedTaxOnFeesCents != 145 {
+		t.Errorf("ExpectedTaxOnFeesCents = %d, want 145", result.ExpectedTaxOnFeesCents)
+	}
+	if result.ExpectedNetAmountCents != 3633 {
+		t.Errorf("ExpectedNetAmountCents = %d, want 3633", result.ExpectedNetAmountCents)
+	}
+	if !result.IsVerified {
+		t.Errorf("expected verified, tax discrepancy = %d", result.TaxOnFeesDiscrepancyCents)
+	}
+
+	// Shopify charged no tax although the partner is HST-registered
+	tx.TaxOnFeesCents = 0
+	result = svc.VerifyTransactionWithTax(tx, valueobject.RevenueShareTierDefault, profile, 0)
+	if result.TaxOnFeesDiscrepancyCents != -145 {
+		t.Errorf("TaxOnFeesDiscrepancyCents = %d, want -145", result.TaxOnFeesDiscrepancyCents)
+	}
+	if result.IsVerified {
+		t.Error("expected tax discrepancy to fail verification")
+	}
+}
+
+func TestFeeVerificationService_VerifyTransactionWithTax_MisappliedFee(t *testing.T) {
+	svc := NewFeeVerificationService()
+	profile := entity.NewTaxJurisdictionProfile(uuid.New(), "CA-ON", "HST", 0.13, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	// $49 gross charged the 25% rate instead of 20%: fees 1225 + 142 = 1367, 13% HST = 177
+	tx := createTransaction(4900, 1225, 142, 177, 3356, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	result := svc.VerifyTransactionWithTax(tx, valueobject.RevenueShareTierDefault, profile, 0)
+	if result.RevenueShareDiscrepancyCents != 245 {
+		t.Errorf("RevenueShareDiscrepancyCents = %d, want 245", result.RevenueShareDiscrepancyCents)
+	}
+	// Tax is expected on the fees actually charged, as in the summary and tax report
+	if result.TaxOnFeesDiscrepancyCents != 0 {
+		t.Errorf("TaxOnFeesDiscrepancyCents = %d, want 0", result.TaxOnFeesDiscrepancyCents)
+	}
+	summary := svc.CalculateFeeSummaryWithTax([]*entity.Transaction{tx}, []*entity.TaxJurisdictionProfile{profile})
+	if summary.ExpectedTaxOnFeesCents != result.ExpectedTaxOnFeesCents {
+		t.Errorf("summary expects %d tax, verification expects %d", summary.ExpectedTaxOnFeesCents, result.ExpectedTaxOnFeesCents)
+	}
+	if result.ExpectedNetAmountCents != 4900-980-142-177 {
+		t.Errorf("ExpectedNetAmountCents = %d, want %d", result.ExpectedNetAmountCents, 4900-980-142-177)
+	}
+	if result.IsVerified {
+		t.Error("expected the misapplied fee to fail verification")
+	}
+}
+
+func TestFeeVerificationService_CalculateFeeSummaryWithTax_EffectiveDates(t *testing.T) {
+	svc := NewFeeVerificationService()
+	partnerID := uuid.New()
+
+	// 10% until April, 15% from April
+	early := entity.NewTaxJurisdictionProfile(partnerID, "AU", "GST", 0.10, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	april := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	early.EffectiveTo = &april
+	late := entity.NewTaxJurisdictionProfile(partnerID, "AU", "GST", 0.15, april)
+	profiles := []*entity.TaxJurisdictionProfile{early, late}
+
+	transactions := []*entity.Transaction{
+		createTransaction(10000, 1000, 0, 100, 8900, time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)),
+		createTransaction(10000, 1000, 0, 100, 8900, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)),
+	}
+
+	summary := svc.CalculateFeeSummaryWithTax(transactions, profiles)
+	if summary.ExpectedTaxOnFeesCents != 250 {
+		t.Errorf("ExpectedTaxOnFeesCents = %d, want 250", summary.ExpectedTaxOnFeesCents)
+	}
+	if summary.TaxOnFeesDiscrepancyCents != -50 {
+		t.Errorf("TaxOnFeesDiscrepancyCents = %d, want -50", summary.TaxOnFeesDiscrepancyCents)
+	}
+
+	// Without profiles, expected tax is not modeled
+	if plain := svc.CalculateFeeSummary(transactions); plain.ExpectedTaxOnFeesCents != 0 || plain.TotalTaxOnFeesCents != 200 {
+		t.Errorf("plain summary expected/charged = %d/%d, want 0/200", plain.ExpectedTaxOnFeesCents, plain.TotalTaxOnFeesCents)
+	}
+}
+
+func TestFeeVerificationService_CalculateFeeSummaryWithTax_Refunds(t *testing.T) {
+	svc := NewFeeVerificationService()
+	profile := entity.NewTaxJurisdictionProfile(uuid.New(), "AU", "GST", 0.10, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	// The charge is taxed as expected; the refund reverses 10 cents less tax than it should
+	charge := createTransaction(10000, 1000, 0, 100, 8900, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	refund := createTransaction(10000, 1000, 0, 90, 8910, time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC))
+	refund.ChargeType = valueobject.ChargeTypeRefund
+
+	summary := svc.CalculateFeeSummaryWithTax([]*entity.Transaction{charge, refund}, []*entity.TaxJurisdictionProfile{profile})
+	if summary.ExpectedTaxOnFeesCents != 0 {
+		t.Errorf("ExpectedTaxOnFeesCents = %d, want 0 (refund reverses expected tax)", summary.ExpectedTaxOnFeesCents)
+	}
+	if summary.TaxOnFeesDiscrepancyCents != 10 {
+		t.Errorf("TaxOnFeesDiscrepancyCents = %d, want 10", summary.TaxOnFeesDiscrepancyCents)
+	}
+}
+
+func TestFeeVerificationService_BuildTaxOnFeesReport(t *testing.T) {
+	svc := NewFeeVerificationService()
+	profile := entity.NewTaxJurisdictionProfile(uuid.New(), "CA-ON", "HST", 0.13, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	jan := createTransaction(4900, 980, 142, 146, 3632, time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC))
+	feb := createTransaction(4900, 980, 142, 145, 3633, time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC))
+	refund := createTransaction(4900, 980, 142, 145, 3633, time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC))
+	refund.ChargeType = valueobject.ChargeTypeRefund
+	beforeProfile := createTransaction(1000, 200, 29, 0, 771, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	for _, tx := range []*entity.Transaction{jan, feb, refund, beforeProfile} {
+		tx.Currency = "USD"
+	}
+
+	rows := svc.BuildTaxOnFeesReport([]*entity.Transaction{jan, feb, refund, beforeProfile}, []*entity.TaxJurisdictionProfile{profile}, entity.TaxPeriodQuarter)
+	if len(rows) != 2 {
+		t.Fatalf("len(rows) = %d, want 2", len(rows))
+	}
+
+	q4 := rows[0]
+	if q4.Jurisdiction != "" || q4.ExpectedTaxCents != 0 || q4.TaxableFeesCents != 229 {
+		t.Errorf("Q4 2025 row = %+v, want no profile and 229 taxable fees", q4)
+	}
+
+	q1 := rows[1]
+	if !q1.PeriodStart.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || !q1.PeriodEnd.Equal(time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Q1 period = %v..%v", q1.PeriodStart, q1.PeriodEnd)
+	}
+	if q1.TransactionCount != 3 || q1.TaxableFeesCents != 1122 {
+		t.Errorf("Q1 count/taxable = %d/%d, want 3/1122 (refund reverses fees)", q1.TransactionCount, q1.TaxableFeesCents)
+	}
+	if q1.TaxChargedCents != 146 || q1.ExpectedTaxCents != 145 || q1.TaxDiscrepancyCents != 1 {
+		t.Errorf("Q1 charged/expected/discrepancy = %d/%d/%d, want 146/145/1", q1.TaxChargedCents, q1.ExpectedTaxCents, q1.TaxDiscrepancyCents)
+	}
+}
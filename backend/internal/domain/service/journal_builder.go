@@ -0,0 +1,163 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+// JournalBuilder summarizes ledger transactions into balanced double-entry journals.
+//
+// For every period (day or month) and currency it produces one entry:
+//   - Credit revenue accounts with gross RECURRING / USAGE / ONE_TIME amounts
+//   - Debit the refund account with gross REFUND amounts
+//   - Debit fee expense accounts with revenue share, processing fee and tax on fees
+//   - Debit the clearing account with the net payout
+//
+// Refunds reverse the fee and clearing lines. Any difference between gross and
+// net + fees (Shopify rounds each component separately) goes to the rounding account.
+type JournalBuilder struct{}
+
+// NewJournalBuilder creates a new journal builder
+func NewJournalBuilder() *JournalBuilder {
+	return &JournalBuilder{}
+}
+
+type journalBucketKey struct {
+	periodStart time.Time
+	currency    string
+}
+
+// Build groups transactions by period and currency and returns entries sorted by date then currency
+func (b *JournalBuilder) Build(
+	transactions []*entity.Transaction,
+	mapping *entity.GLAccountMapping,
+	granularity entity.JournalGranularity,
+) []*entity.JournalEntry {
+	// Signed amounts per account: positive = debit, negative = credit
+	buckets := make(map[journalBucketKey]map[string]int64)
+	for _, tx := range transactions {
+		key := journalBucketKey{
+			periodStart: granularity.PeriodStart(tx.TransactionDate),
+			currency:    tx.Currency,
+		}
+		amounts, ok := buckets[key]
+		if !ok {
+			amounts = make(map[string]int64)
+			buckets[key] = amounts
+		}
+		b.post(amounts, tx, mapping)
+	}
+
+	keys := make([]journalBucketKey, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].periodStart.Equal(keys[j].periodStart) {
+			return keys[i].periodStart.Before(keys[j].periodStart)
+		}
+		return keys[i].currency < keys[j].currency
+	})
+
+	entries := make([]*entity.JournalEntry, 0, len(keys))
+	for i, k := range keys {
+		periodEnd := granularity.PeriodEnd(k.periodStart)
+		entry := &entity.JournalEntry{
+			Number:      i + 1,
+			Date:        periodEnd,
+			PeriodStart: k.periodStart,
+			PeriodEnd:   periodEnd,
+			Currency:    k.currency,
+			Memo:        journalMemo(granularity, k.periodStart, k.currency),
+		}
+		entry.Lines = b.lines(buckets[k], mapping)
+		if len(entry.Lines) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+
+	// Renumber in case empty entries were skipped
+	for i, e := range entries {
+		e.Number = i + 1
+	}
+
+	return entries
+}
+
+// post adds a transaction's signed amounts to the per-account totals
+func (b *JournalBuilder) post(amounts map[string]int64, tx *entity.Transaction, mapping *entity.GLAccountMapping) {
+	sign := int64(1)
+	if tx.ChargeType == valueobject.ChargeTypeRefund {
+		sign = -1
+	}
+
+	gross := tx.GrossAmountCents
+	if gross == 0 {
+		// Older rows have no gross amount; reconstruct it from net + fees
+		gross = tx.NetAmountCents + tx.TotalFeesCents()
+	}
+
+	// Gross: credit revenue for sales, debit refund account for refunds
+	amounts[mapping.RevenueAccountFor(tx.ChargeType)] -= sign * gross
+
+	amounts[mapping.RevenueShareFeeAccount] += sign * tx.ShopifyFeeCents
+	amounts[mapping.ProcessingFeeAccount] += sign * tx.ProcessingFeeCents
+	amounts[mapping.TaxOnFeesAccount] += sign * tx.TaxOnFeesCents
+	amounts[mapping.ClearingAccount] += sign * tx.NetAmountCents
+
+	if diff := gross - tx.NetAmountCents - tx.TotalFeesCents(); diff != 0 {
+		amounts[mapping.RoundingAccount] += sign * diff
+	}
+}
+
+// lines converts per-account totals into journal lines in a stable chart-of-accounts order
+func (b *JournalBuilder) lines(amounts map[string]int64, mapping *entity.GLAccountMapping) []entity.JournalLine {
+	order := []struct {
+		account     string
+		description string
+	}{
+		{mapping.ClearingAccount, "Shopify payouts (net)"},
+		{mapping.RecurringRevenueAccount, "Subscription revenue"},
+		{mapping.UsageRevenueAccount, "Usage revenue"},
+		{mapping.OneTimeRevenueAccount, "One-time revenue"},
+		{mapping.RefundAccount, "Refunds and credits"},
+		{mapping.RevenueShareFeeAccount, "Shopify revenue share"},
+		{mapping.ProcessingFeeAccount, "Shopify processing fees"},
+		{mapping.TaxOnFeesAccount, "Tax on Shopify fees"},
+		{mapping.RoundingAccount, "Rounding"},
+	}
+
+	var lines []entity.JournalLine
+	seen := make(map[string]bool)
+	for _, o := range order {
+		// Several components may share one account code; emit each account once
+		if seen[o.account] {
+			continue
+		}
+		seen[o.account] = true
+
+		amount := amounts[o.account]
+		if amount == 0 {
+			continue
+		}
+		line := entity.JournalLine{AccountCode: o.account, Description: o.description}
+		if amount > 0 {
+			line.DebitCents = amount
+		} else {
+			line.CreditCents = -amount
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func journalMemo(granularity entity.JournalGranularity, periodStart time.Time, currency string) string {
+	if granularity == entity.JournalGranularityMonth {
+		return fmt.Sprintf("Shopify app revenue %s (%s)", periodStart.Format("January 2006"), currency)
+	}
+	return fmt.Sprintf("Shopify app revenue %s (%s)", periodStart.Format("2006-01-02"), currency)
+}
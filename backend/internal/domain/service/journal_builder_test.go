@@ -0,0 +1,172 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+func journalTx(chargeType valueobject.ChargeType, date time.Time, gross, revShare, processing, tax, net int64) *entity.Transaction {
+	return &entity.Transaction{
+		ID:                 uuid.New(),
+		ChargeType:         chargeType,
+		GrossAmountCents:   gross,
+		ShopifyFeeCents:    revShare,
+		ProcessingFeeCents: processing,
+		TaxOnFeesCents:     tax,
+		NetAmountCents:     net,
+		Currency:           "USD",
+		TransactionDate:    date,
+	}
+}
+
+func findLine(entry *entity.JournalEntry, account string) *entity.JournalLine {
+	for i := range entry.Lines {
+		if entry.Lines[i].AccountCode == account {
+			return &entry.Lines[i]
+		}
+	}
+	return nil
+}
+
+func TestJournalBuilder_Build_DailyBalancedEntry(t *testing.T) {
+	builder := NewJournalBuilder()
+	mapping := entity.NewGLAccountMapping(uuid.New())
+	day := time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	txs := []*entity.Transaction{
+		journalTx(valueobject.ChargeTypeRecurring, day, 4900, 980, 142, 90, 3688),
+		journalTx(valueobject.ChargeTypeUsage, day.Add(2*time.Hour), 1000, 200, 29, 18, 753),
+	}
+
+	entries := builder.Build(txs, mapping, entity.JournalGranularityDay)
+
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) = %d, want 1", len(entries))
+	}
+	entry := entries[0]
+
+	if !entry.IsBalanced() {
+		t.Errorf("entry not balanced: debits %d, credits %d", entry.TotalDebitCents(), entry.TotalCreditCents())
+	}
+	if got := findLine(entry, mapping.RecurringRevenueAccount); got == nil || got.CreditCents != 4900 {
+		t.Errorf("recurring revenue line = %+v, want credit 4900", got)
+	}
+	if got := findLine(entry, mapping.UsageRevenueAccount); got == nil || got.CreditCents != 1000 {
+		t.Errorf("usage revenue line = %+v, want credit 1000", got)
+	}
+	if got := findLine(entry, mapping.ClearingAccount); got == nil || got.DebitCents != 3688+753 {
+		t.Errorf("clearing line = %+v, want debit %d", got, 3688+753)
+	}
+	if got := findLine(entry, mapping.RevenueShareFeeAccount); got == nil || got.DebitCents != 1180 {
+		t.Errorf("revenue share line = %+v, want debit 1180", got)
+	}
+	if got := findLine(entry, mapping.RoundingAccount); got != nil {
+		t.Errorf("unexpected rounding line %+v", got)
+	}
+	if !entry.Date.Equal(time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("entry.Date = %v, want 2026-03-15", entry.Date)
+	}
+}
+
+func TestJournalBuilder_Build_MonthlyGroupsByPeriodAndCurrency(t *testing.T) {
+	builder := NewJournalBuilder()
+	mapping := entity.NewGLAccountMapping(uuid.New())
+
+	eur := journalTx(valueobject.ChargeTypeRecurring, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), 1000, 0, 29, 0, 971)
+	eur.Currency = "EUR"
+
+	txs := []*entity.Transaction{
+		journalTx(valueobject.ChargeTypeRecurring, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 1000, 0, 29, 0, 971),
+		journalTx(valueobject.ChargeTypeRecurring, time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC), 1000, 0, 29, 0, 971),
+		journalTx(valueobject.ChargeTypeRecurring, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), 1000, 0, 29, 0, 971),
+		eur,
+	}
+
+	entries := builder.Build(txs, mapping, entity.JournalGranularityMonth)
+
+	if len(entries) != 3 {
+		t.Fatalf("len(entries) = %d, want 3", len(entries))
+	}
+	if entries[0].Currency != "EUR" || entries[1].Currency != "USD" {
+		t.Errorf("March entries currencies = %s, %s, want EUR, USD", entries[0].Currency, entries[1].Currency)
+	}
+	if got := findLine(entries[1], mapping.RecurringRevenueAccount); got == nil || got.CreditCents != 2000 {
+		t.Errorf("March USD revenue = %+v, want credit 2000", got)
+	}
+	if !entries[1].Date.Equal(time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("March entry date = %v, want 2026-03-31", entries[1].Date)
+	}
+	for i, e := range entries {
+		if e.Number != i+1 {
+			t.Errorf("entries[%d].Number = %d, want %d", i, e.Number, i+1)
+		}
+	}
+}
+
+func TestJournalBuilder_Build_RefundReversesFees(t *testing.T) {
+	builder := NewJournalBuilder()
+	mapping := entity.NewGLAccountMapping(uuid.New())
+	day := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	txs := []*entity.Transaction{
+		journalTx(valueobject.ChargeTypeRecurring, day, 4900, 980, 142, 0, 3778),
+		journalTx(valueobject.ChargeTypeRefund, day, 4900, 980, 142, 0, 3778),
+	}
+
+	entries := builder.Build(txs, mapping, entity.JournalGranularityDay)
+
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) = %d, want 1", len(entries))
+	}
+	entry := entries[0]
+	if !entry.IsBalanced() {
+		t.Errorf("entry not balanced")
+	}
+	if got := findLine(entry, mapping.RefundAccount); got == nil || got.DebitCents != 4900 {
+		t.Errorf("refund line = %+v, want debit 4900", got)
+	}
+	if got := findLine(entry, mapping.ClearingAccount); got != nil {
+		t.Errorf("clearing should net to zero, got %+v", got)
+	}
+}
+
+func TestJournalBuilder_Build_RoundingDifference(t *testing.T) {
+	builder := NewJournalBuilder()
+	mapping := entity.NewGLAccountMapping(uuid.New())
+
+	// Gross 1000, fees 29, net 970: one cent lost to rounding
+	txs := []*entity.Transaction{
+		journalTx(valueobject.ChargeTypeRecurring, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), 1000, 0, 29, 0, 970),
+	}
+
+	entries := builder.Build(txs, mapping, entity.JournalGranularityDay)
+
+	if len(entries) != 1 || !entries[0].IsBalanced() {
+		t.Fatalf("expected one balanced entry")
+	}
+	if got := findLine(entries[0], mapping.RoundingAccount); got == nil || got.DebitCents != 1 {
+		t.Errorf("rounding line = %+v, want debit 1", got)
+	}
+}
+
+func TestJournalBuilder_Build_MissingGrossUsesNetPlusFees(t *testing.T) {
+	builder := NewJournalBuilder()
+	mapping := entity.NewGLAccountMapping(uuid.New())
+
+	txs := []*entity.Transaction{
+		journalTx(valueobject.ChargeTypeOneTime, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), 0, 0, 0, 0, 2500),
+	}
+
+	entries := builder.Build(txs, mapping, entity.JournalGranularityDay)
+
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) = %d, want 1", len(entries))
+	}
+	if got := findLine(entries[0], mapping.OneTimeRevenueAccount); got == nil || got.CreditCents != 2500 {
+		t.Errorf("one-time revenue = %+v, want credit 2500", got)
+	}
+}
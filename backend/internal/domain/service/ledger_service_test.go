@@ -25,6 +25,10 @@ func (m *mockTxRepoForLedger) UpsertBatch(ctx context.Context, txs []*entity.Tra
 	return nil
 }
 
+func (m *mockTxRepoForLedger) FindCreatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*entity.Transaction, error) {
+	return nil, nil
+}
+
 func (m *mockTxRepoForLedger) FindByAppID(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.Transaction, error) {
 	return m.transactions, m.err
 }
@@ -86,6 +90,10 @@ func (m *mockSubRepoForLedger) FindByAppIDAndDomain(ctx context.Context, appID u
 	return nil, nil
 }
 
+func (m *mockSubRepoForLedger) FindUpdatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*entity.Subscription, error) {
+	return nil, nil
+}
+
 func (m *mockSubRepoForLedger) FindByRiskState(ctx context.Context, appID uuid.UUID, riskState valueobject.RiskState) ([]*entity.Subscription, error) {
 	return nil, nil
 }
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+// RevenueRecognitionEngine builds accrual-basis revenue schedules from cash transactions.
+//
+// Recognition rules:
+//   - RECURRING: recognized ratably per day over the service period
+//     [charge date, period end), where period end is the subscription period end
+//     when known, otherwise the next charge date for the billing interval
+//   - USAGE / ONE_TIME: recognized in full on the charge date
+//   - REFUND: matched to the latest recurring charge for the same shop on or before
+//     the refund date. The refund first reduces the unrecognized (deferred) balance;
+//     any excess reverses already recognized revenue on the refund date.
+//     Unmatched refunds reverse revenue on the refund date.
+//   - Cancellation during a service period: the remaining deferred balance is
+//     recognized on the cancellation date, since no further service is owed.
+//
+// Amounts are gross (what the merchant paid); fees are an expense, not a reduction of revenue.
+type RevenueRecognitionEngine struct{}
+
+// NewRevenueRecognitionEngine creates a new revenue recognition engine
+func NewRevenueRecognitionEngine() *RevenueRecognitionEngine {
+	return &RevenueRecognitionEngine{}
+}
+
+// scheduleEvent is a refund or cancellation applied to a recurring schedule
+type scheduleEvent struct {
+	date        time.Time
+	refundCents int64 // 0 for cancellations
+	isCancel    bool
+}
+
+// BuildSchedules creates one schedule per charge. cancellations maps a shop domain to the
+// dates its subscription was cancelled or the app was uninstalled.
+func (e *RevenueRecognitionEngine) BuildSchedules(
+	transactions []*entity.Transaction,
+	cancellations map[string][]time.Time,
+) []*entity.RecognitionSchedule {
+	sorted := make([]*entity.Transaction, len(transactions))
+	copy(sorted, transactions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TransactionDate.Before(sorted[j].TransactionDate)
+	})
+
+	var schedules []*entity.RecognitionSchedule
+	recurringByDomain := make(map[string][]*entity.RecognitionSchedule)
+	subscriptionGIDs := make(map[*entity.RecognitionSchedule]string)
+	events := make(map[*entity.RecognitionSchedule][]scheduleEvent)
+
+	for _, tx := range sorted {
+		day := startOfDay(tx.TransactionDate)
+		amount := grossCents(tx)
+
+		if tx.ChargeType == valueobject.ChargeTypeRefund {
+			if match := latestMatchingSchedule(recurringByDomain[tx.MyshopifyDomain], subscriptionGIDs, tx.SubscriptionGID, day); match != nil {
+				events[match] = append(events[match], scheduleEvent{date: day, refundCents: amount})
+				continue
+			}
+
+			// Unmatched refund: reverse revenue on the refund date
+			schedules = append(schedules, &entity.RecognitionSchedule{
+				TransactionID:   tx.ID,
+				ShopifyGID:      tx.ShopifyGID,
+				MyshopifyDomain: tx.MyshopifyDomain,
+				ShopName:        tx.ShopName,
+				ChargeType:      tx.ChargeType,
+				Currency:        tx.Currency,
+				BilledDate:      day,
+				ServiceStart:    day,
+				ServiceEnd:      day,
+				Segments:        []entity.RecognitionSegment{{Start: day, End: day, AmountCents: -amount}},
+				Refunds:         []entity.ScheduleAdjustment{{Date: day, AmountCents: amount}},
+			})
+			continue
+		}
+
+		schedule := &entity.RecognitionSchedule{
+			TransactionID:   tx.ID,
+			ShopifyGID:      tx.ShopifyGID,
+			MyshopifyDomain: tx.MyshopifyDomain,
+			ShopName:        tx.ShopName,
+			ChargeType:      tx.ChargeType,
+			Currency:        tx.Currency,
+			BilledCents:     amount,
+			BilledDate:      day,
+			ServiceStart:    day,
+			ServiceEnd:      day,
+		}
+
+		if tx.ChargeType == valueobject.ChargeTypeRecurring {
+			schedule.ServiceEnd = servicePeriodEnd(tx, day)
+			recurringByDomain[tx.MyshopifyDomain] = append(recurringByDomain[tx.MyshopifyDomain], schedule)
+			subscriptionGIDs[schedule] = tx.SubscriptionGID
+		}
+
+		schedule.Segments = []entity.RecognitionSegment{{
+			Start:        schedule.ServiceStart,
+			End:          schedule.ServiceEnd,
+			AmountCents:  amount,
+			FromDeferred: true,
+		}}
+		schedules = append(schedules, schedule)
+	}
+
+	// Attach cancellations that fall inside a recurring service period
+	for domain, dates := range cancellations {
+		for _, schedule := range recurringByDomain[domain] {
+			for _, d := range dates {
+				day := startOfDay(d)
+				if day.After(schedule.ServiceStart) && day.Before(schedule.ServiceEnd) {
+					events[schedule] = append(events[schedule], scheduleEvent{date: day, isCancel: true})
+				}
+			}
+		}
+	}
+
+	for schedule, evs := range events {
+		sort.SliceStable(evs, func(i, j int) bool { return evs[i].date.Before(evs[j].date) })
+		for _, ev := range evs {
+			applyScheduleEvent(schedule, ev)
+		}
+	}
+
+	return schedules
+}
+
+// Allocate returns the revenue recognized from a schedule in each month of [fromMonth, toMonth]
+func (e *RevenueRecognitionEngine) Allocate(schedule *entity.RecognitionSchedule, fromMonth, toMonth time.Time) []entity.RecognitionAllocation {
+	var allocations []entity.RecognitionAllocation
+	for m := startOfMonth(fromMonth); !m.After(startOfMonth(toMonth)); m = m.AddDate(0, 1, 0) {
+		amount := recognizedBetween(schedule, m, m.AddDate(0, 1, 0), false)
+		if amount != 0 {
+			allocations = append(allocations, entity.RecognitionAllocation{Month: m, RecognizedCents: amount})
+		}
+	}
+	return allocations
+}
+
+// MonthlyBalances summarizes billed, recognized and deferred revenue per month and currency
+func (e *RevenueRecognitionEngine) MonthlyBalances(
+	schedules []*entity.RecognitionSchedule,
+	fromMonth, toMonth time.Time,
+) []*entity.MonthlyRevenueRecognition {
+	byCurrency := make(map[string][]*entity.RecognitionSchedule)
+	var currencies []string
+	for _, s := range schedules {
+		if _, ok := byCurrency[s.Currency]; !ok {
+			currencies = append(currencies, s.Currency)
+		}
+		byCurrency[s.Currency] = append(byCurrency[s.Currency], s)
+	}
+	sort.Strings(currencies)
+
+	var rows []*entity.MonthlyRevenueRecognition
+	for m := startOfMonth(fromMonth); !m.After(startOfMonth(toMonth)); m = m.AddDate(0, 1, 0) {
+		next := m.AddDate(0, 1, 0)
+		for _, currency := range currencies {
+			row := &entity.MonthlyRevenueRecognition{Month: m, Currency: currency}
+			for _, s := range byCurrency[currency] {
+				if !s.BilledDate.Before(m) && s.BilledDate.Before(next) {
+					row.BilledCents += s.BilledCents
+				}
+				for _, r := range s.Refunds {
+					if !r.Date.Before(m) && r.Date.Before(next) {
+						row.RefundedCents += r.AmountCents
+					}
+				}
+				row.RecognizedCents += recognizedBetween(s, m, next, false)
+				row.DeferredOpeningCents += deferredBefore(s, m)
+				row.DeferredClosingCents += deferredBefore(s, next)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
+
+// applyScheduleEvent applies a refund or cancellation to a recurring schedule
+func applyScheduleEvent(schedule *entity.RecognitionSchedule, ev scheduleEvent) {
+	remaining := splitRatableSegment(schedule, ev.date)
+	end := schedule.ServiceEnd
+
+	if ev.isCancel {
+		if schedule.CancelledAt != nil {
+			return
+		}
+		cancelledAt := ev.date
+		schedule.CancelledAt = &cancelledAt
+		if remaining > 0 {
+			schedule.Segments = append(schedule.Segments, entity.RecognitionSegment{
+				Start: ev.date, End: ev.date, AmountCents: remaining, FromDeferred: true,
+			})
+		}
+		return
+	}
+
+	schedule.Refunds = append(schedule.Refunds, entity.ScheduleAdjustment{Date: ev.date, AmountCents: ev.refundCents})
+
+	reduce := ev.refundCents
+	if reduce > remaining {
+		reduce = remaining
+	}
+	if reduce > 0 {
+		schedule.Reductions = append(schedule.Reductions, entity.ScheduleAdjustment{Date: ev.date, AmountCents: reduce})
+	}
+	if excess := ev.refundCents - reduce; excess > 0 {
+		schedule.Segments = append(schedule.Segments, entity.RecognitionSegment{
+			Start: ev.date, End: ev.date, AmountCents: -excess,
+		})
+	}
+
+	remaining -= reduce
+	if remaining > 0 {
+		start := ev.date
+		if start.Before(schedule.ServiceStart) {
+			start = schedule.ServiceStart
+		}
+		schedule.Segments = append(schedule.Segments, entity.RecognitionSegment{
+			Start: start, End: end, AmountCents: remaining, FromDeferred: true,
+		})
+	}
+}
+
+// splitRatableSegment truncates the open ratable segment at date and returns the
+// amount that had not been recognized before that date. Returns 0 if nothing remains.
+func splitRatableSegment(schedule *entity.RecognitionSchedule, date time.Time) int64 {
+	idx := -1
+	for i := len(schedule.Segments) - 1; i >= 0; i-- {
+		seg := schedule.Segments[i]
+		if seg.FromDeferred && seg.End.After(seg.Start) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return 0
+	}
+
+	seg := schedule.Segments[idx]
+	if !date.Before(seg.End) {
+		return 0 // Already fully recognized
+	}
+
+	before := segmentRecognizedBefore(seg, date)
+	remaining := seg.AmountCents - before
+
+	if before == 0 && !date.After(seg.Start) {
+		schedule.Segments = append(schedule.Segments[:idx], schedule.Segments[idx+1:]...)
+	} else {
+		schedule.Segments[idx].End = date
+		schedule.Segments[idx].AmountCents = before
+	}
+
+	return remaining
+}
+
+// segmentRecognizedBefore returns the amount a segment recognizes before date (exclusive)
+func segmentRecognizedBefore(seg entity.RecognitionSegment, date time.Time) int64 {
+	if !seg.End.After(seg.Start) {
+		if seg.Start.Before(date) {
+			return seg.AmountCents
+		}
+		return 0
+	}
+
+	total := daysBetween(seg.Start, seg.End)
+	elapsed := daysBetween(seg.Start, date)
+	if elapsed <= 0 {
+		return 0
+	}
+	if elapsed >= total {
+		return seg.AmountCents
+	}
+	// Cumulative daily ratable amount; rounding settles on the last day
+	return seg.AmountCents * int64(elapsed) / int64(total)
+}
+
+// recognizedBetween returns revenue recognized in [from, to). If deferredOnly, direct
+// adjustments (excess refunds) are excluded.
+func recognizedBetween(schedule *entity.RecognitionSchedule, from, to time.Time, deferredOnly bool) int64 {
+	var total int64
+	for _, seg := range schedule.Segments {
+		if deferredOnly && !seg.FromDeferred {
+			continue
+		}
+		total += segmentRecognizedBefore(seg, to) - segmentRecognizedBefore(seg, from)
+	}
+	return total
+}
+
+// deferredBefore returns the schedule's deferred revenue balance at the start of date
+func deferredBefore(schedule *entity.RecognitionSchedule, date time.Time) int64 {
+	if !schedule.BilledDate.Before(date) {
+		return 0
+	}
+	balance := schedule.BilledCents - recognizedBetween(schedule, schedule.BilledDate, date, true)
+	for _, r := range schedule.Reductions {
+		if r.Date.Before(date) {
+			balance -= r.AmountCents
+		}
+	}
+	return balance
+}
+
+// latestMatchingSchedule finds the most recent recurring schedule billed on or before date,
+// preferring the same subscription GID when both sides have one
+func latestMatchingSchedule(
+	candidates []*entity.RecognitionSchedule,
+	subscriptionGIDs map[*entity.RecognitionSchedule]string,
+	subscriptionGID string,
+	date time.Time,
+) *entity.RecognitionSchedule {
+	var match *entity.RecognitionSchedule
+	for _, s := range candidates {
+		if s.BilledDate.After(date) {
+			continue
+		}
+		if subscriptionGID != "" && subscriptionGIDs[s] != "" && subscriptionGIDs[s] != subscriptionGID {
+			continue
+		}
+		if match == nil || !s.BilledDate.Before(match.BilledDate) {
+			match = s
+		}
+	}
+	return match
+}
+
+// servicePeriodEnd returns the exclusive end of a recurring charge's service period
+func servicePeriodEnd(tx *entity.Transaction, start time.Time) time.Time {
+	if tx.SubscriptionPeriodEnd != nil {
+		end := startOfDay(*tx.SubscriptionPeriodEnd)
+		if end.After(start) {
+			return end
+		}
+	}
+
+	interval := valueobject.BillingInterval(tx.BillingInterval)
+	if !interval.IsValid() {
+		interval = valueobject.BillingIntervalMonthly
+	}
+	return interval.NextChargeDate(start)
+}
+
+// grossCents returns the gross charge amount, reconstructing it for rows without one
+func grossCents(tx *entity.Transaction) int64 {
+	if tx.GrossAmountCents > 0 {
+		return tx.GrossAmountCents
+	}
+	return tx.NetAmountCents + tx.TotalFeesCents()
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+func recognitionTx(chargeType valueobject.ChargeType, domain string, date time.Time, gross int64, interval string) *entity.Transaction {
+	return &entity.Transaction{
+		ID:               uuid.New(),
+		MyshopifyDomain:  domain,
+		ChargeType:       chargeType,
+		GrossAmountCents: gross,
+		NetAmountCents:   gross,
+		Currency:         "USD",
+		BillingInterval:  interval,
+		TransactionDate:  date,
+	}
+}
+
+func monthRow(rows []*entity.MonthlyRevenueRecognition, month time.Month) *entity.MonthlyRevenueRecognition {
+	for _, r := range rows {
+		if r.Month.Month() == month {
+			return r
+		}
+	}
+	return nil
+}
+
+func TestRevenueRecognitionEngine_AnnualChargeSpreadDaily(t *testing.T) {
+	engine := NewRevenueRecognitionEngine()
+	// 365 days from 2026-01-01 to 2027-01-01, $365.00 → $1.00 per day
+	txs := []*entity.Transaction{
+		recognitionTx(valueobject.ChargeTypeRecurring, "a.myshopify.com", time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC), 36500, "ANNUAL"),
+	}
+
+	schedules := engine.BuildSchedules(txs, nil)
+	if len(schedules) != 1 {
+		t.Fatalf("len(schedules) = %d, want 1", len(schedules))
+	}
+	if !schedules[0].IsRatable() {
+		t.Fatal("expected annual charge to be ratable")
+	}
+	if want := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC); !schedules[0].ServiceEnd.Equal(want) {
+		t.Errorf("ServiceEnd = %v, want %v", schedules[0].ServiceEnd, want)
+	}
+
+	rows := engine.MonthlyBalances(schedules, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC))
+	if len(rows) != 12 {
+		t.Fatalf("len(rows) = %d, want 12", len(rows))
+	}
+
+	jan := monthRow(rows, time.January)
+	if jan.BilledCents != 36500 || jan.RecognizedCents != 3100 {
+		t.Errorf("January billed/recognized = %d/%d, want 36500/3100", jan.BilledCents, jan.RecognizedCents)
+	}
+	if jan.DeferredOpeningCents != 0 || jan.DeferredClosingCents != 33400 {
+		t.Errorf("January deferred = %d→%d, want 0→33400", jan.DeferredOpeningCents, jan.DeferredClosingCents)
+	}
+
+	feb := monthRow(rows, time.February)
+	if feb.RecognizedCents != 2800 || feb.DeferredOpeningCents != 33400 {
+		t.Errorf("February recognized/opening = %d/%d, want 2800/33400", feb.RecognizedCents, feb.DeferredOpeningCents)
+	}
+
+	var total int64
+	for _, r := range rows {
+		total += r.RecognizedCents
+	}
+	if total != 36500 {
+		t.Errorf("total recognized = %d, want 36500", total)
+	}
+	if dec := monthRow(rows, time.December); dec.DeferredClosingCents != 0 {
+		t.Errorf("December closing deferred = %d, want 0", dec.DeferredClosingCents)
+	}
+}
+
+func TestRevenueRecognitionEngine_RefundReducesDeferredBalance(t *testing.T) {
+	engine := NewRevenueRecognitionEngine()
+	domain := "a.myshopify.com"
+	txs := []*entity.Transaction{
+		recognitionTx(valueobject.ChargeTypeRecurring, domain, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 36500, "ANNUAL"),
+		// Partial refund on Feb 1 (31 days recognized, 33400 deferred)
+		recognitionTx(valueobject.ChargeTypeRefund, domain, time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC), 10000, ""),
+	}
+
+	schedules := engine.BuildSchedules(txs, nil)
+	if len(schedules) != 1 {
+		t.Fatalf("refund should attach to the annual charge, got %d schedules", len(schedules))
+	}
+	if schedules[0].RefundedCents() != 10000 {
+		t.Errorf("RefundedCents = %d, want 10000", schedules[0].RefundedCents())
+	}
+
+	rows := engine.MonthlyBalances(schedules, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC))
+
+	feb := monthRow(rows, time.February)
+	if feb.RefundedCents != 10000 {
+		t.Errorf("February refunded = %d, want 10000", feb.RefundedCents)
+	}
+	if feb.DeferredOpeningCents != 33400 {
+		t.Errorf("February opening = %d, want 33400", feb.DeferredOpeningCents)
+	}
+	// Remaining 23400 spread over 334 days; February recognizes 28 of them
+	wantFeb := int64(23400 * 28 / 334)
+	if feb.RecognizedCents != wantFeb {
+		t.Errorf("February recognized = %d, want %d", feb.RecognizedCents, wantFeb)
+	}
+	if feb.DeferredClosingCents != 23400-wantFeb {
+		t.Errorf("February closing = %d, want %d", feb.DeferredClosingCents, 23400-wantFeb)
+	}
+
+	var total int64
+	for _, r := range rows {
+		total += r.RecognizedCents
+	}
+	if total != 26500 {
+		t.Errorf("total recognized = %d, want 26500 (billed less refund)", total)
+	}
+}
+
+func TestRevenueRecognitionEngine_CancellationAcceleratesRecognition(t *testing.T) {
+	engine := NewRevenueRecognitionEngine()
+	domain := "a.myshopify.com"
+	txs := []*entity.Transaction{
+		recognitionTx(valueobject.ChargeTypeRecurring, domain, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 36500, "ANNUAL"),
+	}
+	cancellations := map[string][]time.Time{
+		domain: {time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC)},
+	}
+
+	schedules := engine.BuildSchedules(txs, cancellations)
+	if schedules[0].CancelledAt == nil {
+		t.Fatal("expected CancelledAt to be set")
+	}
+
+	rows := engine.MonthlyBalances(schedules, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+
+	mar := monthRow(rows, time.March)
+	if mar.RecognizedCents != 36500-5900 {
+		t.Errorf("March recognized = %d, want %d (remaining balance)", mar.RecognizedCents, 36500-5900)
+	}
+	if mar.DeferredClosingCents != 0 {
+		t.Errorf("March closing deferred = %d, want 0", mar.DeferredClosingCents)
+	}
+	if apr := monthRow(rows, time.April); apr.RecognizedCents != 0 {
+		t.Errorf("April recognized = %d, want 0", apr.RecognizedCents)
+	}
+}
+
+func TestRevenueRecognitionEngine_PointInTimeAndUnmatchedRefund(t *testing.T) {
+	engine := NewRevenueRecognitionEngine()
+	txs := []*entity.Transaction{
+		recognitionTx(valueobject.ChargeTypeUsage, "a.myshopify.com", time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC), 1000, ""),
+		recognitionTx(valueobject.ChargeTypeOneTime, "a.myshopify.com", time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC), 5000, ""),
+		recognitionTx(valueobject.ChargeTypeRefund, "b.myshopify.com", time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), 700, ""),
+	}
+
+	schedules := engine.BuildSchedules(txs, nil)
+	if len(schedules) != 3 {
+		t.Fatalf("len(schedules) = %d, want 3", len(schedules))
+	}
+
+	rows := engine.MonthlyBalances(schedules, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	jan := monthRow(rows, time.January)
+	if jan.RecognizedCents != 1000 || jan.DeferredClosingCents != 0 {
+		t.Errorf("January recognized/closing = %d/%d, want 1000/0", jan.RecognizedCents, jan.DeferredClosingCents)
+	}
+	feb := monthRow(rows, time.February)
+	if feb.RecognizedCents != 4300 {
+		t.Errorf("February recognized = %d, want 4300", feb.RecognizedCents)
+	}
+	if feb.RefundedCents != 700 {
+		t.Errorf("February refunded = %d, want 700", feb.RefundedCents)
+	}
+	if feb.DeferredClosingCents != 0 {
+		t.Errorf("February closing deferred = %d, want 0", feb.DeferredClosingCents)
+	}
+}
+
+func TestRevenueRecognitionEngine_MonthlyChargeUsesPeriodEnd(t *testing.T) {
+	engine := NewRevenueRecognitionEngine()
+	tx := recognitionTx(valueobject.ChargeTypeRecurring, "a.myshopify.com", time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC), 3100, "MONTHLY")
+	periodEnd := time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)
+	tx.SubscriptionPeriodEnd = &periodEnd
+
+	schedules := engine.BuildSchedules([]*entity.Transaction{tx}, nil)
+	allocations := engine.Allocate(schedules[0], time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	if len(allocations) != 2 {
+		t.Fatalf("len(allocations) = %d, want 2", len(allocations))
+	}
+	// 16 of 31 days in January, 15 in February
+	if allocations[0].RecognizedCents != 1600 || allocations[1].RecognizedCents != 1500 {
+		t.Errorf("allocations = %d/%d, want 1600/1500", allocations[0].RecognizedCents, allocations[1].RecognizedCents)
+	}
+}
@@ -3,8 +3,10 @@ package valueobject
 type Role string
 
 const (
-	RoleOwner Role = "OWNER"
-	RoleAdmin Role = "ADMIN"
+	RoleOwner   Role = "OWNER"
+	RoleAdmin   Role = "ADMIN"
+	RoleAnalyst Role = "ANALYST" // Read access plus exports
+	RoleViewer  Role = "VIEWER"  // Read-only access
 )
 
 func (r Role) String() string {
@@ -13,9 +15,30 @@ func (r Role) String() string {
 
 func (r Role) IsValid() bool {
 	switch r {
-	case RoleOwner, RoleAdmin:
+	case RoleOwner, RoleAdmin, RoleAnalyst, RoleViewer:
 		return true
 	default:
 		return false
 	}
 }
+
+// AtLeast returns true if the role grants everything the other role does.
+// Roles are ordered OWNER > ADMIN > ANALYST > VIEWER.
+func (r Role) AtLeast(other Role) bool {
+	return r.rank() >= other.rank() && r.rank() > 0
+}
+
+func (r Role) rank() int {
+	switch r {
+	case RoleOwner:
+		return 4
+	case RoleAdmin:
+		return 3
+	case RoleAnalyst:
+		return 2
+	case RoleViewer:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -9,8 +9,9 @@ import (
 
 // OAuthStateData holds the user info associated with an OAuth state
 type OAuthStateData struct {
-	UserID    uuid.UUID
-	CreatedAt time.Time
+	UserID      uuid.UUID
+	WorkspaceID uuid.UUID // uuid.Nil when the flow was started outside a workspace
+	CreatedAt   time.Time
 }
 
 // OAuthStateStore stores OAuth state tokens for CSRF protection.
@@ -45,15 +46,35 @@ func (s *OAuthStateStore) Store(state string, userID uuid.UUID) {
 	}
 }
 
+// StoreForWorkspace saves a state token with the user and the workspace the
+// partner account is being connected to.
+func (s *OAuthStateStore) StoreForWorkspace(state string, userID, workspaceID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[state] = OAuthStateData{
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		CreatedAt:   time.Now(),
+	}
+}
+
 // Validate checks if a state token is valid and returns the associated user ID.
 // The state is consumed (deleted) upon validation to prevent replay attacks.
 func (s *OAuthStateStore) Validate(state string) (uuid.UUID, bool) {
+	userID, _, ok := s.ValidateWorkspace(state)
+	return userID, ok
+}
+
+// ValidateWorkspace is Validate that also returns the associated workspace ID,
+// uuid.Nil if the state was stored without one.
+func (s *OAuthStateStore) ValidateWorkspace(state string) (uuid.UUID, uuid.UUID, bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	data, exists := s.states[state]
 	if !exists {
-		return uuid.Nil, false
+		return uuid.Nil, uuid.Nil, false
 	}
 
 	// Delete the state (one-time use)
@@ -61,10 +82,10 @@ func (s *OAuthStateStore) Validate(state string) (uuid.UUID, bool) {
 
 	// Check if expired
 	if time.Since(data.CreatedAt) > s.ttl {
-		return uuid.Nil, false
+		return uuid.Nil, uuid.Nil, false
 	}
 
-	return data.UserID, true
+	return data.UserID, data.WorkspaceID, true
 }
 
 // cleanup removes expired states periodically
@@ -99,3 +99,24 @@ func TestOAuthStateStore_MultipleStates(t *testing.T) {
 		t.Error("state2 validation failed")
 	}
 }
+
+func TestOAuthStateStore_StoreForWorkspace(t *testing.T) {
+	store := NewOAuthStateStore(5 * time.Minute)
+
+	userID := uuid.New()
+	workspaceID := uuid.New()
+
+	store.StoreForWorkspace("workspace-state", userID, workspaceID)
+	store.Store("personal-state", userID)
+
+	returnedUserID, returnedWorkspaceID, valid := store.ValidateWorkspace("workspace-state")
+	if !valid || returnedUserID != userID || returnedWorkspaceID != workspaceID {
+		t.Errorf("expected user %s and workspace %s, got %s, %s (valid=%v)", userID, workspaceID, returnedUserID, returnedWorkspaceID, valid)
+	}
+
+	// States stored without a workspace return uuid.Nil
+	_, returnedWorkspaceID, valid = store.ValidateWorkspace("personal-state")
+	if !valid || returnedWorkspaceID != uuid.Nil {
+		t.Errorf("expected no workspace, got %s (valid=%v)", returnedWorkspaceID, valid)
+	}
+}
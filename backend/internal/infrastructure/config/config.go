@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"gopkg.in/yaml.v3"
 )
@@ -13,6 +14,9 @@ type Config struct {
 	Firebase   FirebaseConfig   `yaml:"firebase"`
 	Shopify    ShopifyConfig    `yaml:"shopify"`
 	Encryption EncryptionConfig `yaml:"encryption"`
+	Email      EmailConfig      `yaml:"email"`
+	Webhooks   WebhooksConfig   `yaml:"webhooks"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
 }
 
 type ServerConfig struct {
@@ -45,6 +49,31 @@ type EncryptionConfig struct {
 	MasterKey string `yaml:"master_key"`
 }
 
+// EmailConfig configures the SMTP email notification channel. Email is disabled when SMTPHost is empty.
+type EmailConfig struct {
+	SMTPHost     string `yaml:"smtp_host"`
+	SMTPPort     string `yaml:"smtp_port"`
+	SMTPUsername string `yaml:"smtp_username"`
+	SMTPPassword string `yaml:"smtp_password"`
+	From         string `yaml:"from"`       // e.g. "LedgerGuard <alerts@example.com>"
+	PublicURL    string `yaml:"public_url"` // Base URL of this API, for verification and unsubscribe links
+}
+
+// WebhooksConfig configures outbound webhooks sent to customer URLs
+type WebhooksConfig struct {
+	AllowLoopback bool `yaml:"allow_loopback"` // Development only: allow http://localhost targets
+}
+
+// RateLimitConfig configures Revenue API rate limiting. Quotas are kept in Postgres
+// or Redis so they hold across replicas.
+type RateLimitConfig struct {
+	Store              string `yaml:"store"`      // "postgres" (default) or "redis"
+	RedisAddr          string `yaml:"redis_addr"` // host:port, required for the redis store
+	RedisPassword      string `yaml:"redis_password"`
+	RedisDB            int    `yaml:"redis_db"`
+	UserLimitPerMinute int    `yaml:"user_limit_per_minute"` // Across all of a user's keys; 0 disables
+}
+
 // Load loads configuration from file and environment variables.
 // Priority: defaults < config file < environment variables
 func Load(configPath string) (*Config, error) {
@@ -61,6 +90,14 @@ func Load(configPath string) (*Config, error) {
 			SSLMode:        "disable",
 			MigrationsPath: "migrations",
 		},
+		Email: EmailConfig{
+			SMTPPort:  "587",
+			PublicURL: "http://localhost:8080",
+		},
+		RateLimit: RateLimitConfig{
+			Store:              "postgres",
+			UserLimitPerMinute: 3000,
+		},
 	}
 
 	// Load from file if provided
@@ -144,6 +181,52 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("ENCRYPTION_MASTER_KEY"); v != "" {
 		cfg.Encryption.MasterKey = v
 	}
+
+	// Email
+	if v := os.Getenv("SMTP_HOST"); v != "" {
+		cfg.Email.SMTPHost = v
+	}
+	if v := os.Getenv("SMTP_PORT"); v != "" {
+		cfg.Email.SMTPPort = v
+	}
+	if v := os.Getenv("SMTP_USERNAME"); v != "" {
+		cfg.Email.SMTPUsername = v
+	}
+	if v := os.Getenv("SMTP_PASSWORD"); v != "" {
+		cfg.Email.SMTPPassword = v
+	}
+	if v := os.Getenv("EMAIL_FROM"); v != "" {
+		cfg.Email.From = v
+	}
+	if v := os.Getenv("PUBLIC_URL"); v != "" {
+		cfg.Email.PublicURL = v
+	}
+
+	// Webhooks
+	if v := os.Getenv("WEBHOOK_ALLOW_LOOPBACK"); v != "" {
+		cfg.Webhooks.AllowLoopback = v == "true"
+	}
+
+	// Rate limiting
+	if v := os.Getenv("RATE_LIMIT_STORE"); v != "" {
+		cfg.RateLimit.Store = v
+	}
+	if v := os.Getenv("REDIS_ADDR"); v != "" {
+		cfg.RateLimit.RedisAddr = v
+	}
+	if v := os.Getenv("REDIS_PASSWORD"); v != "" {
+		cfg.RateLimit.RedisPassword = v
+	}
+	if v := os.Getenv("REDIS_DB"); v != "" {
+		if db, err := strconv.Atoi(v); err == nil {
+			cfg.RateLimit.RedisDB = db
+		}
+	}
+	if v := os.Getenv("RATE_LIMIT_USER_PER_MINUTE"); v != "" {
+		if limit, err := strconv.Atoi(v); err == nil {
+			cfg.RateLimit.UserLimitPerMinute = limit
+		}
+	}
 }
 
 func (d *DatabaseConfig) DSN() string {
@@ -207,6 +290,19 @@ func (c *Config) Validate() []ValidationWarning {
 		})
 	}
 
+	// Rate limit quotas need a known store; Redis also needs an address
+	if c.RateLimit.Store != "postgres" && c.RateLimit.Store != "redis" {
+		warnings = append(warnings, ValidationWarning{
+			Field:   "rate_limit.store",
+			Message: "Unknown rate limit store; use postgres or redis. Falling back to postgres.",
+		})
+	} else if c.RateLimit.Store == "redis" && c.RateLimit.RedisAddr == "" {
+		warnings = append(warnings, ValidationWarning{
+			Field:   "rate_limit.redis_addr",
+			Message: "Redis rate limit store selected without an address. Set REDIS_ADDR environment variable.",
+		})
+	}
+
 	return warnings
 }
 
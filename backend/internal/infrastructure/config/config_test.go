@@ -164,6 +164,98 @@ func TestLoad_EnvOnly(t *testing.T) {
 	}
 }
 
+func TestLoad_EmailEnv(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("SMTP_HOST", "localhost")
+	os.Setenv("SMTP_PORT", "1025")
+	os.Setenv("EMAIL_FROM", "LedgerGuard <alerts@example.com>")
+	os.Setenv("PUBLIC_URL", "https://api.example.com")
+	defer os.Clearenv()
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Email.SMTPHost != "localhost" || cfg.Email.SMTPPort != "1025" {
+		t.Errorf("expected SMTP localhost:1025, got '%s:%s'", cfg.Email.SMTPHost, cfg.Email.SMTPPort)
+	}
+
+	if cfg.Email.From != "LedgerGuard <alerts@example.com>" {
+		t.Errorf("expected from address, got '%s'", cfg.Email.From)
+	}
+
+	if cfg.Email.PublicURL != "https://api.example.com" {
+		t.Errorf("expected public URL 'https://api.example.com', got '%s'", cfg.Email.PublicURL)
+	}
+}
+
+func TestLoad_WebhooksAllowLoopback(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Webhooks.AllowLoopback {
+		t.Error("expected loopback webhook targets to be refused by default")
+	}
+
+	os.Setenv("WEBHOOK_ALLOW_LOOPBACK", "true")
+	cfg, err = Load("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Webhooks.AllowLoopback {
+		t.Error("expected WEBHOOK_ALLOW_LOOPBACK=true to allow loopback targets")
+	}
+}
+
+func TestLoad_RateLimit(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RateLimit.Store != "postgres" || cfg.RateLimit.UserLimitPerMinute != 3000 {
+		t.Errorf("expected the postgres store with a 3000/min user limit by default, got %+v", cfg.RateLimit)
+	}
+
+	os.Setenv("RATE_LIMIT_STORE", "redis")
+	os.Setenv("REDIS_ADDR", "cache:6379")
+	os.Setenv("REDIS_DB", "2")
+	os.Setenv("RATE_LIMIT_USER_PER_MINUTE", "0")
+	cfg, err = Load("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RateLimit.Store != "redis" || cfg.RateLimit.RedisAddr != "cache:6379" || cfg.RateLimit.RedisDB != 2 {
+		t.Errorf("expected the redis store at cache:6379 db 2, got %+v", cfg.RateLimit)
+	}
+	if cfg.RateLimit.UserLimitPerMinute != 0 {
+		t.Errorf("expected the user limit to be disabled, got %d", cfg.RateLimit.UserLimitPerMinute)
+	}
+
+	for _, w := range cfg.Validate() {
+		if w.Field == "rate_limit.redis_addr" || w.Field == "rate_limit.store" {
+			t.Errorf("unexpected warning: %+v", w)
+		}
+	}
+	cfg.RateLimit.RedisAddr = ""
+	found := false
+	for _, w := range cfg.Validate() {
+		if w.Field == "rate_limit.redis_addr" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected a warning for the redis store without an address")
+	}
+}
+
 func TestDatabaseConfig_DSN(t *testing.T) {
 	cfg := DatabaseConfig{
 		Host:     "localhost",
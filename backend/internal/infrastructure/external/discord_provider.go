@@ -0,0 +1,134 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/pkg/netguard"
+)
+
+// ErrDiscordWebhookFailed is returned when the Discord webhook request fails
+var ErrDiscordWebhookFailed = errors.New("discord webhook request failed")
+
+// Discord embed limits
+const (
+	discordMaxTitle       = 256
+	discordMaxDescription = 4096
+)
+
+// DiscordNotifier defines the interface for sending Discord notifications
+type DiscordNotifier interface {
+	// SendDiscord posts an embed to a Discord webhook
+	SendDiscord(ctx context.Context, webhookURL string, title string, body string, color string) error
+}
+
+// DiscordEmbedFooter is the footer of a Discord embed
+type DiscordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// DiscordEmbed represents a Discord message embed
+type DiscordEmbed struct {
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color,omitempty"`
+	Footer      *DiscordEmbedFooter `json:"footer,omitempty"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+}
+
+// DiscordPayload represents the Discord webhook payload
+type DiscordPayload struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []DiscordEmbed `json:"embeds"`
+}
+
+// DiscordNotificationProvider implements DiscordNotifier for sending Discord webhooks
+type DiscordNotificationProvider struct {
+	httpClient *http.Client
+}
+
+// NewDiscordNotificationProvider creates a new Discord notification provider
+func NewDiscordNotificationProvider() *DiscordNotificationProvider {
+	return &DiscordNotificationProvider{
+		httpClient: netguard.New(false).HTTPClient(10 * time.Second),
+	}
+}
+
+// NewDiscordNotificationProviderWithClient creates a provider with a custom HTTP client,
+// e.g. one allowing localhost targets in development or for tests
+func NewDiscordNotificationProviderWithClient(client *http.Client) *DiscordNotificationProvider {
+	return &DiscordNotificationProvider{
+		httpClient: client,
+	}
+}
+
+// SendDiscord posts an embed with the title, body and colour. Text beyond
+// Discord's embed limits is truncated.
+func (p *DiscordNotificationProvider) SendDiscord(ctx context.Context, webhookURL string, title string, body string, color string) error {
+	if webhookURL == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	payload := DiscordPayload{
+		Username: "LedgerGuard",
+		Embeds: []DiscordEmbed{
+			{
+				Title:       truncateRunes(title, discordMaxTitle),
+				Description: truncateRunes(body, discordMaxDescription),
+				Color:       discordColor(color),
+				Footer:      &DiscordEmbedFooter{Text: "LedgerGuard"},
+				Timestamp:   time.Now().UTC().Format(time.RFC3339),
+			},
+		},
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal discord payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send discord webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Discord answers 204 No Content, or 200 with ?wait=true
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: status code %d", ErrDiscordWebhookFailed, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// discordColor converts a "#rrggbb" colour to the integer Discord expects. Invalid colours give 0 (no colour).
+func discordColor(color string) int {
+	value, err := strconv.ParseInt(strings.TrimPrefix(color, "#"), 16, 32)
+	if err != nil {
+		return 0
+	}
+	return int(value)
+}
+
+// truncateRunes shortens s to at most max runes, ending in an ellipsis if cut
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
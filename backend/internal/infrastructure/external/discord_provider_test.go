@@ -0,0 +1,89 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDiscordNotificationProvider_SendDiscord(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends embed", func(t *testing.T) {
+		var receivedPayload DiscordPayload
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&receivedPayload); err != nil {
+				t.Errorf("failed to decode payload: %v", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		provider := NewDiscordNotificationProviderWithClient(server.Client())
+		err := provider.SendDiscord(ctx, server.URL, "Test Title", "Test Body", SlackColorDanger)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(receivedPayload.Embeds) != 1 {
+			t.Fatalf("expected 1 embed, got %d", len(receivedPayload.Embeds))
+		}
+		embed := receivedPayload.Embeds[0]
+		if embed.Title != "Test Title" || embed.Description != "Test Body" {
+			t.Errorf("unexpected embed text: %+v", embed)
+		}
+		if embed.Color != 0xdc3545 {
+			t.Errorf("expected color %d, got %d", 0xdc3545, embed.Color)
+		}
+		if embed.Footer == nil || embed.Footer.Text != "LedgerGuard" {
+			t.Errorf("expected LedgerGuard footer, got %+v", embed.Footer)
+		}
+	})
+
+	t.Run("truncates long descriptions", func(t *testing.T) {
+		var receivedPayload DiscordPayload
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&receivedPayload)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		provider := NewDiscordNotificationProviderWithClient(server.Client())
+		if err := provider.SendDiscord(ctx, server.URL, "Title", strings.Repeat("é", 5000), SlackColorInfo); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if n := utf8.RuneCountInString(receivedPayload.Embeds[0].Description); n != discordMaxDescription {
+			t.Errorf("expected %d runes, got %d", discordMaxDescription, n)
+		}
+	})
+
+	t.Run("returns error for empty webhook URL", func(t *testing.T) {
+		provider := NewDiscordNotificationProvider()
+		err := provider.SendDiscord(ctx, "", "Title", "Body", SlackColorInfo)
+
+		if !errors.Is(err, ErrInvalidWebhookURL) {
+			t.Errorf("expected ErrInvalidWebhookURL, got %v", err)
+		}
+	})
+
+	t.Run("returns error for non-2xx response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		provider := NewDiscordNotificationProviderWithClient(server.Client())
+		err := provider.SendDiscord(ctx, server.URL, "Title", "Body", SlackColorWarning)
+
+		if !errors.Is(err, ErrDiscordWebhookFailed) {
+			t.Errorf("expected ErrDiscordWebhookFailed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	revenueservice "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/pkg/netguard"
+)
+
+// ErrOutgoingWebhookFailed is returned when the generic webhook request fails
+var ErrOutgoingWebhookFailed = errors.New("outgoing webhook request failed")
+
+// OutgoingWebhookSignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">".
+// Signing is shared with Revenue API webhooks so receivers can use one verifier.
+const OutgoingWebhookSignatureHeader = revenueservice.WebhookSignatureHeader
+
+// WebhookNotifier defines the interface for sending generic webhook notifications
+type WebhookNotifier interface {
+	// SendWebhook POSTs a JSON payload signed with the webhook's secret
+	SendWebhook(ctx context.Context, webhookURL string, secret string, payload []byte) error
+}
+
+// OutgoingWebhookProvider implements WebhookNotifier for generic JSON webhooks
+type OutgoingWebhookProvider struct {
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewOutgoingWebhookProvider creates a new generic webhook provider
+func NewOutgoingWebhookProvider() *OutgoingWebhookProvider {
+	return &OutgoingWebhookProvider{
+		httpClient: netguard.New(false).HTTPClient(10 * time.Second),
+		now:        time.Now,
+	}
+}
+
+// NewOutgoingWebhookProviderWithClient creates a provider with a custom HTTP client,
+// e.g. one allowing localhost targets in development or for tests
+func NewOutgoingWebhookProviderWithClient(client *http.Client) *OutgoingWebhookProvider {
+	return &OutgoingWebhookProvider{
+		httpClient: client,
+		now:        time.Now,
+	}
+}
+
+// SendWebhook POSTs the payload with its signature header
+func (p *OutgoingWebhookProvider) SendWebhook(ctx context.Context, webhookURL string, secret string, payload []byte) error {
+	if webhookURL == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := p.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "LedgerGuard-Notifications/1.0")
+	req.Header.Set(OutgoingWebhookSignatureHeader, revenueservice.WebhookSignatureHeaderValue(secret, timestamp, payload))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: status code %d", ErrOutgoingWebhookFailed, resp.StatusCode)
+	}
+
+	return nil
+}
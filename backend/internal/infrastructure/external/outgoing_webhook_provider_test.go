@@ -0,0 +1,102 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	revenueservice "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/pkg/netguard"
+)
+
+func TestOutgoingWebhookProvider_SendWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("signs payload", func(t *testing.T) {
+		payload := []byte(`{"event":"notification.test"}`)
+		var receivedBody []byte
+		var receivedSignature string
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedBody, _ = io.ReadAll(r.Body)
+			receivedSignature = r.Header.Get(OutgoingWebhookSignatureHeader)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		provider := NewOutgoingWebhookProviderWithClient(server.Client())
+		provider.now = func() time.Time { return time.Unix(1760000000, 0) }
+		if err := provider.SendWebhook(ctx, server.URL, "whsec_test", payload); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if string(receivedBody) != string(payload) {
+			t.Errorf("expected body %s, got %s", payload, receivedBody)
+		}
+		expected := fmt.Sprintf("t=1760000000,v1=%s", revenueservice.SignWebhookPayload("whsec_test", 1760000000, payload))
+		if receivedSignature != expected {
+			t.Errorf("expected signature %q, got %q", expected, receivedSignature)
+		}
+		if !revenueservice.VerifyWebhookSignature("whsec_test", receivedSignature, receivedBody, 0, time.Now()) {
+			t.Error("expected the Revenue API verifier to accept the signature")
+		}
+	})
+
+	t.Run("returns error for empty webhook URL", func(t *testing.T) {
+		provider := NewOutgoingWebhookProvider()
+		err := provider.SendWebhook(ctx, "", "secret", []byte("{}"))
+
+		if !errors.Is(err, ErrInvalidWebhookURL) {
+			t.Errorf("expected ErrInvalidWebhookURL, got %v", err)
+		}
+	})
+
+	t.Run("returns error for non-2xx response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		provider := NewOutgoingWebhookProviderWithClient(server.Client())
+		err := provider.SendWebhook(ctx, server.URL, "secret", []byte("{}"))
+
+		if !errors.Is(err, ErrOutgoingWebhookFailed) {
+			t.Errorf("expected ErrOutgoingWebhookFailed, got %v", err)
+		}
+	})
+
+	t.Run("refuses internal addresses by default", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("request should not reach a loopback address")
+		}))
+		defer server.Close()
+
+		err := NewOutgoingWebhookProvider().SendWebhook(ctx, server.URL, "secret", []byte("{}"))
+
+		if !errors.Is(err, netguard.ErrDisallowedAddress) {
+			t.Errorf("expected netguard.ErrDisallowedAddress, got %v", err)
+		}
+	})
+
+	t.Run("does not follow redirects", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/elsewhere" {
+				t.Error("redirect should not be followed")
+			}
+			http.Redirect(w, r, "/elsewhere", http.StatusTemporaryRedirect)
+		}))
+		defer server.Close()
+
+		provider := NewOutgoingWebhookProviderWithClient(netguard.New(true).HTTPClient(time.Second))
+		err := provider.SendWebhook(ctx, server.URL, "secret", []byte("{}"))
+
+		if !errors.Is(err, ErrOutgoingWebhookFailed) {
+			t.Errorf("expected ErrOutgoingWebhookFailed, got %v", err)
+		}
+	})
+}
@@ -599,6 +599,7 @@ func (c *ShopifyPartnerClient) parseTransaction(node transactionNode, appID uuid
 
 	// Add shop details
 	tx.ShopifyShopGID = shopGID
+	tx.ChargeGID = node.ChargeID
 	// Note: ShopPlan is no longer available from Partner API transactions query
 
 	// Note: Subscription status/details are not available from transactions query.
@@ -633,14 +634,14 @@ func (c *ShopifyPartnerClient) parseAmounts(node transactionNode) (grossCents, n
 	if node.GrossAmount != nil {
 		var dollars float64
 		fmt.Sscanf(node.GrossAmount.Amount, "%f", &dollars)
-		grossCents = int64(dollars * 100)
+		grossCents = int64(math.Round(dollars * 100))
 		currency = node.GrossAmount.CurrencyCode
 	}
 
 	if node.NetAmount != nil {
 		var dollars float64
 		fmt.Sscanf(node.NetAmount.Amount, "%f", &dollars)
-		netCents = int64(dollars * 100)
+		netCents = int64(math.Round(dollars * 100))
 		if currency == "USD" && node.NetAmount.CurrencyCode != "" {
 			currency = node.NetAmount.CurrencyCode
 		}
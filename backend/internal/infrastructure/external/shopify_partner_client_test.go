@@ -296,6 +296,9 @@ func TestFetchTransactions_Success(t *testing.T) {
 	}
 
 	// Verify second transaction
+	if transactions[1].ChargeGID != "charge456" {
+		t.Errorf("expected charge GID 'charge456', got %s", transactions[1].ChargeGID)
+	}
 	if transactions[1].ShopName != "Another Shop" {
 		t.Errorf("expected shop name 'Another Shop', got %s", transactions[1].ShopName)
 	}
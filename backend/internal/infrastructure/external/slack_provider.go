@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/pkg/netguard"
 )
 
 // ErrSlackWebhookFailed is returned when the Slack webhook request fails
@@ -45,13 +47,12 @@ type SlackNotificationProvider struct {
 // NewSlackNotificationProvider creates a new Slack notification provider
 func NewSlackNotificationProvider() *SlackNotificationProvider {
 	return &SlackNotificationProvider{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		httpClient: netguard.New(false).HTTPClient(10 * time.Second),
 	}
 }
 
-// NewSlackNotificationProviderWithClient creates a provider with a custom HTTP client (for testing)
+// NewSlackNotificationProviderWithClient creates a provider with a custom HTTP client,
+// e.g. one allowing localhost targets in development or for tests
 func NewSlackNotificationProviderWithClient(client *http.Client) *SlackNotificationProvider {
 	return &SlackNotificationProvider{
 		httpClient: client,
@@ -0,0 +1,200 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// ErrInvalidEmailRecipient is returned when the recipient address is empty or invalid
+var ErrInvalidEmailRecipient = errors.New("invalid email recipient")
+
+// EmailSender defines the interface for sending email
+type EmailSender interface {
+	// SendEmail sends a multipart text and HTML email. unsubscribeURL, if set,
+	// is sent as a one-click List-Unsubscribe header.
+	SendEmail(ctx context.Context, to string, subject string, textBody string, htmlBody string, unsubscribeURL string) error
+}
+
+// SMTPEmailProvider implements EmailSender over SMTP. STARTTLS is used when the
+// server offers it, and authentication when a username is set, so the same
+// provider works against a mail relay and a local SMTP sink such as Mailpit.
+type SMTPEmailProvider struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     *mail.Address
+	timeout  time.Duration
+}
+
+// NewSMTPEmailProvider creates a new SMTP email provider. from may include a
+// display name, e.g. "LedgerGuard <alerts@example.com>".
+func NewSMTPEmailProvider(host, port, username, password, from string) (*SMTPEmailProvider, error) {
+	if host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	fromAddr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	if port == "" {
+		port = "587"
+	}
+
+	return &SMTPEmailProvider{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     fromAddr,
+		timeout:  30 * time.Second,
+	}, nil
+}
+
+// SendEmail sends a multipart text and HTML email
+func (p *SMTPEmailProvider) SendEmail(ctx context.Context, to string, subject string, textBody string, htmlBody string, unsubscribeURL string) error {
+	toAddr, err := mail.ParseAddress(to)
+	if err != nil {
+		return ErrInvalidEmailRecipient
+	}
+
+	msg, err := buildEmailMessage(p.from, toAddr, subject, textBody, htmlBody, unsubscribeURL, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	dialer := net.Dialer{Timeout: p.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(p.host, p.port))
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	deadline := time.Now().Add(p.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, p.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: p.host}); err != nil {
+			return fmt.Errorf("smtp starttls failed: %w", err)
+		}
+	}
+	if p.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", p.username, p.password, p.host)); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(p.from.Address); err != nil {
+		return fmt.Errorf("smtp MAIL FROM rejected: %w", err)
+	}
+	if err := client.Rcpt(toAddr.Address); err != nil {
+		return fmt.Errorf("smtp RCPT TO rejected: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA rejected: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected email: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// buildEmailMessage renders a multipart/alternative message with the text part first
+func buildEmailMessage(from, to *mail.Address, subject, textBody, htmlBody, unsubscribeURL string, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", textBody},
+		{"text/html; charset=utf-8", htmlBody},
+	}
+	for _, part := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	messageID, err := newMessageID(from.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	writeHeader := func(key, value string) {
+		fmt.Fprintf(&msg, "%s: %s\r\n", key, value)
+	}
+	writeHeader("From", from.String())
+	writeHeader("To", to.String())
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", subject))
+	writeHeader("Date", date.Format(time.RFC1123Z))
+	writeHeader("Message-ID", messageID)
+	writeHeader("MIME-Version", "1.0")
+	if unsubscribeURL != "" {
+		// RFC 8058 one-click unsubscribe
+		writeHeader("List-Unsubscribe", "<"+unsubscribeURL+">")
+		writeHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+	writeHeader("Content-Type", "multipart/alternative; boundary="+mw.Boundary())
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+// newMessageID returns a unique Message-ID in the sender's domain
+func newMessageID(fromAddress string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	domain := "localhost"
+	if at := strings.LastIndex(fromAddress, "@"); at >= 0 {
+		domain = fromAddress[at+1:]
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">", nil
+}
@@ -0,0 +1,150 @@
+package external
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// smtpSink is a minimal local SMTP server that records the messages it accepts
+type smtpSink struct {
+	listener net.Listener
+	from     string
+	rcpt     []string
+	data     chan string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &smtpSink{listener: l, data: make(chan string, 1)}
+	go s.serve()
+	t.Cleanup(func() { l.Close() })
+	return s
+}
+
+func (s *smtpSink) port() string {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return port
+}
+
+func (s *smtpSink) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP sink")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			tp.PrintfLine("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = line[len("MAIL FROM:"):]
+			tp.PrintfLine("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.rcpt = append(s.rcpt, line[len("RCPT TO:"):])
+			tp.PrintfLine("250 OK")
+		case cmd == "DATA":
+			tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.data <- string(data)
+			tp.PrintfLine("250 OK queued")
+		case cmd == "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPEmailProvider_SendEmail(t *testing.T) {
+	sink := newSMTPSink(t)
+	provider, err := NewSMTPEmailProvider("127.0.0.1", sink.port(), "", "", "LedgerGuard <alerts@ledgerguard.test>")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = provider.SendEmail(context.Background(), "owner@example.com", "🚨 Risk Alert: MyApp",
+		"Plain body", "<p>HTML body</p>", "https://app.ledgerguard.test/unsubscribe?token=abc")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	data := <-sink.data
+	if sink.from != "<alerts@ledgerguard.test>" || len(sink.rcpt) != 1 || sink.rcpt[0] != "<owner@example.com>" {
+		t.Errorf("unexpected envelope: from %q, rcpt %v", sink.from, sink.rcpt)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "🚨 Risk Alert: MyApp" {
+		t.Errorf("Subject = %q", subject)
+	}
+	if got := msg.Header.Get("List-Unsubscribe"); got != "<https://app.ledgerguard.test/unsubscribe?token=abc>" {
+		t.Errorf("List-Unsubscribe = %q", got)
+	}
+	if got := msg.Header.Get("List-Unsubscribe-Post"); got != "List-Unsubscribe=One-Click" {
+		t.Errorf("List-Unsubscribe-Post = %q", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, %v", msg.Header.Get("Content-Type"), err)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read part: %v", err)
+		}
+		body, _ := io.ReadAll(part) // NextPart decodes quoted-printable
+		parts = append(parts, part.Header.Get("Content-Type")+"|"+string(body))
+	}
+	if len(parts) != 2 ||
+		parts[0] != "text/plain; charset=utf-8|Plain body" ||
+		parts[1] != "text/html; charset=utf-8|<p>HTML body</p>" {
+		t.Errorf("unexpected parts: %q", parts)
+	}
+}
+
+func TestSMTPEmailProvider_InvalidAddresses(t *testing.T) {
+	if _, err := NewSMTPEmailProvider("", "25", "", "", "alerts@ledgerguard.test"); err == nil {
+		t.Error("expected error for missing host")
+	}
+	if _, err := NewSMTPEmailProvider("localhost", "25", "", "", "not an address"); err == nil {
+		t.Error("expected error for invalid from address")
+	}
+
+	provider, _ := NewSMTPEmailProvider("localhost", "25", "", "", "alerts@ledgerguard.test")
+	if err := provider.SendEmail(context.Background(), "", "s", "t", "h", ""); err != ErrInvalidEmailRecipient {
+		t.Errorf("expected ErrInvalidEmailRecipient, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/pkg/netguard"
+)
+
+// ErrTeamsWebhookFailed is returned when the Teams webhook request fails
+var ErrTeamsWebhookFailed = errors.New("teams webhook request failed")
+
+// TeamsNotifier defines the interface for sending Microsoft Teams notifications
+type TeamsNotifier interface {
+	// SendTeams posts an Adaptive Card to a Teams incoming webhook or workflow URL
+	SendTeams(ctx context.Context, webhookURL string, title string, body string, color string) error
+}
+
+// AdaptiveCardElement is a TextBlock in an Adaptive Card body
+type AdaptiveCardElement struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	Wrap     bool   `json:"wrap,omitempty"`
+	Weight   string `json:"weight,omitempty"`
+	Size     string `json:"size,omitempty"`
+	Color    string `json:"color,omitempty"`
+	IsSubtle bool   `json:"isSubtle,omitempty"`
+}
+
+// AdaptiveCard is the card content of a Teams message
+type AdaptiveCard struct {
+	Schema  string                `json:"$schema"`
+	Type    string                `json:"type"`
+	Version string                `json:"version"`
+	Body    []AdaptiveCardElement `json:"body"`
+}
+
+// TeamsAttachment wraps an Adaptive Card in a Teams message
+type TeamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     AdaptiveCard `json:"content"`
+}
+
+// TeamsPayload represents the Teams webhook payload
+type TeamsPayload struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+// TeamsNotificationProvider implements TeamsNotifier for sending Teams webhooks
+type TeamsNotificationProvider struct {
+	httpClient *http.Client
+}
+
+// NewTeamsNotificationProvider creates a new Teams notification provider
+func NewTeamsNotificationProvider() *TeamsNotificationProvider {
+	return &TeamsNotificationProvider{
+		httpClient: netguard.New(false).HTTPClient(10 * time.Second),
+	}
+}
+
+// NewTeamsNotificationProviderWithClient creates a provider with a custom HTTP client,
+// e.g. one allowing localhost targets in development or for tests
+func NewTeamsNotificationProviderWithClient(client *http.Client) *TeamsNotificationProvider {
+	return &TeamsNotificationProvider{
+		httpClient: client,
+	}
+}
+
+// SendTeams posts an Adaptive Card with the title in the severity colour and
+// one text block per line of the body
+func (p *TeamsNotificationProvider) SendTeams(ctx context.Context, webhookURL string, title string, body string, color string) error {
+	if webhookURL == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	elements := []AdaptiveCardElement{
+		{Type: "TextBlock", Text: title, Wrap: true, Weight: "Bolder", Size: "Medium", Color: adaptiveCardColor(color)},
+	}
+	for _, line := range strings.Split(body, "\n") {
+		elements = append(elements, AdaptiveCardElement{Type: "TextBlock", Text: line, Wrap: true})
+	}
+	elements = append(elements, AdaptiveCardElement{Type: "TextBlock", Text: "LedgerGuard", Size: "Small", IsSubtle: true})
+
+	payload := TeamsPayload{
+		Type: "message",
+		Attachments: []TeamsAttachment{
+			{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content: AdaptiveCard{
+					Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+					Type:    "AdaptiveCard",
+					Version: "1.4",
+					Body:    elements,
+				},
+			},
+		},
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal teams payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send teams webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Incoming webhooks answer 200, workflow webhooks 202
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: status code %d", ErrTeamsWebhookFailed, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// adaptiveCardColor maps a Slack colour to the nearest Adaptive Card text colour
+func adaptiveCardColor(color string) string {
+	switch color {
+	case SlackColorDanger:
+		return "Attention"
+	case SlackColorWarning:
+		return "Warning"
+	case SlackColorSuccess:
+		return "Good"
+	}
+	return "Accent"
+}
@@ -0,0 +1,77 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTeamsNotificationProvider_SendTeams(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends adaptive card", func(t *testing.T) {
+		var receivedPayload TeamsPayload
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("expected Content-Type application/json, got %s", r.Header.Get("Content-Type"))
+			}
+			if err := json.NewDecoder(r.Body).Decode(&receivedPayload); err != nil {
+				t.Errorf("failed to decode payload: %v", err)
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		provider := NewTeamsNotificationProviderWithClient(server.Client())
+		err := provider.SendTeams(ctx, server.URL, "Test Title", "Line one\nLine two", SlackColorDanger)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if receivedPayload.Type != "message" || len(receivedPayload.Attachments) != 1 {
+			t.Fatalf("expected one attachment message, got %+v", receivedPayload)
+		}
+		attachment := receivedPayload.Attachments[0]
+		if attachment.ContentType != "application/vnd.microsoft.card.adaptive" || attachment.Content.Type != "AdaptiveCard" {
+			t.Errorf("expected an adaptive card, got %+v", attachment)
+		}
+
+		body := attachment.Content.Body
+		if len(body) != 4 {
+			t.Fatalf("expected title, two lines and footer, got %d elements", len(body))
+		}
+		if body[0].Text != "Test Title" || body[0].Color != "Attention" {
+			t.Errorf("expected title with Attention colour, got %+v", body[0])
+		}
+		if body[1].Text != "Line one" || body[2].Text != "Line two" {
+			t.Errorf("expected one text block per body line, got %+v", body[1:3])
+		}
+	})
+
+	t.Run("returns error for empty webhook URL", func(t *testing.T) {
+		provider := NewTeamsNotificationProvider()
+		err := provider.SendTeams(ctx, "", "Title", "Body", SlackColorInfo)
+
+		if !errors.Is(err, ErrInvalidWebhookURL) {
+			t.Errorf("expected ErrInvalidWebhookURL, got %v", err)
+		}
+	})
+
+	t.Run("returns error for non-2xx response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		provider := NewTeamsNotificationProviderWithClient(server.Client())
+		err := provider.SendTeams(ctx, server.URL, "Title", "Body", SlackColorWarning)
+
+		if !errors.Is(err, ErrTeamsWebhookFailed) {
+			t.Errorf("expected ErrTeamsWebhookFailed, got %v", err)
+		}
+	})
+}
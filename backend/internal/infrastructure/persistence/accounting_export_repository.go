@@ -0,0 +1,226 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+type PostgresGLAccountMappingRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresGLAccountMappingRepository(pool *pgxpool.Pool) *PostgresGLAccountMappingRepository {
+	return &PostgresGLAccountMappingRepository{pool: pool}
+}
+
+func (r *PostgresGLAccountMappingRepository) FindByAppID(ctx context.Context, appID uuid.UUID) (*entity.GLAccountMapping, error) {
+	query := `
+		SELECT id, app_id, recurring_revenue_account, usage_revenue_account, one_time_revenue_account,
+		       refund_account, revenue_share_fee_account, processing_fee_account, tax_on_fees_account,
+		       clearing_account, rounding_account, xero_tax_rate, created_at, updated_at
+		FROM gl_account_mappings
+		WHERE app_id = $1
+	`
+
+	var m entity.GLAccountMapping
+	err := r.pool.QueryRow(ctx, query, appID).Scan(
+		&m.ID,
+		&m.AppID,
+		&m.RecurringRevenueAccount,
+		&m.UsageRevenueAccount,
+		&m.OneTimeRevenueAccount,
+		&m.RefundAccount,
+		&m.RevenueShareFeeAccount,
+		&m.ProcessingFeeAccount,
+		&m.TaxOnFeesAccount,
+		&m.ClearingAccount,
+		&m.RoundingAccount,
+		&m.XeroTaxRate,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrGLAccountMappingNotFound
+		}
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (r *PostgresGLAccountMappingRepository) Upsert(ctx context.Context, m *entity.GLAccountMapping) error {
+	query := `
+		INSERT INTO gl_account_mappings (
+			id, app_id, recurring_revenue_account, usage_revenue_account, one_time_revenue_account,
+			refund_account, revenue_share_fee_account, processing_fee_account, tax_on_fees_account,
+			clearing_account, rounding_account, xero_tax_rate, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (app_id) DO UPDATE SET
+			recurring_revenue_account = EXCLUDED.recurring_revenue_account,
+			usage_revenue_account = EXCLUDED.usage_revenue_account,
+			one_time_revenue_account = EXCLUDED.one_time_revenue_account,
+			refund_account = EXCLUDED.refund_account,
+			revenue_share_fee_account = EXCLUDED.revenue_share_fee_account,
+			processing_fee_account = EXCLUDED.processing_fee_account,
+			tax_on_fees_account = EXCLUDED.tax_on_fees_account,
+			clearing_account = EXCLUDED.clearing_account,
+			rounding_account = EXCLUDED.rounding_account,
+			xero_tax_rate = EXCLUDED.xero_tax_rate,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		m.ID,
+		m.AppID,
+		m.RecurringRevenueAccount,
+		m.UsageRevenueAccount,
+		m.OneTimeRevenueAccount,
+		m.RefundAccount,
+		m.RevenueShareFeeAccount,
+		m.ProcessingFeeAccount,
+		m.TaxOnFeesAccount,
+		m.ClearingAccount,
+		m.RoundingAccount,
+		m.XeroTaxRate,
+		m.CreatedAt,
+		m.UpdatedAt,
+	)
+
+	return err
+}
+
+const accountingExportRunColumns = `id, app_id, user_id, format, granularity, period_start, period_end,
+	journal_count, transaction_count, total_debit_cents, confirmed, created_at`
+
+// overlappingExportRunsQuery selects an app's runs whose period intersects [$2, $3]
+const overlappingExportRunsQuery = `
+	SELECT ` + accountingExportRunColumns + `
+	FROM accounting_export_runs
+	WHERE app_id = $1 AND period_start <= $3 AND period_end >= $2
+	ORDER BY created_at DESC
+`
+
+type PostgresAccountingExportRunRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresAccountingExportRunRepository(pool *pgxpool.Pool) *PostgresAccountingExportRunRepository {
+	return &PostgresAccountingExportRunRepository{pool: pool}
+}
+
+func (r *PostgresAccountingExportRunRepository) CreateIfNoOverlap(ctx context.Context, run *entity.AccountingExportRun, allowOverlap bool) ([]*entity.AccountingExportRun, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize exports per app so the overlap check below cannot race an insert
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM apps WHERE id = $1 FOR UPDATE`, run.AppID); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, overlappingExportRunsQuery, run.AppID, run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+	overlapping, err := scanAccountingExportRuns(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(overlapping) > 0 && !allowOverlap {
+		return overlapping, nil
+	}
+	run.Confirmed = len(overlapping) > 0
+
+	query := `
+		INSERT INTO accounting_export_runs (` + accountingExportRunColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	if _, err := tx.Exec(ctx, query,
+		run.ID,
+		run.AppID,
+		run.UserID,
+		string(run.Format),
+		string(run.Granularity),
+		run.PeriodStart,
+		run.PeriodEnd,
+		run.JournalCount,
+		run.TransactionCount,
+		run.TotalDebitCents,
+		run.Confirmed,
+		run.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return overlapping, tx.Commit(ctx)
+}
+
+func (r *PostgresAccountingExportRunRepository) FindByAppID(ctx context.Context, appID uuid.UUID, limit int) ([]*entity.AccountingExportRun, error) {
+	query := `
+		SELECT ` + accountingExportRunColumns + `
+		FROM accounting_export_runs
+		WHERE app_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, appID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAccountingExportRuns(rows)
+}
+
+func (r *PostgresAccountingExportRunRepository) FindOverlapping(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.AccountingExportRun, error) {
+	rows, err := r.pool.Query(ctx, overlappingExportRunsQuery, appID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAccountingExportRuns(rows)
+}
+
+func scanAccountingExportRuns(rows pgx.Rows) ([]*entity.AccountingExportRun, error) {
+	var runs []*entity.AccountingExportRun
+	for rows.Next() {
+		var run entity.AccountingExportRun
+		var format, granularity string
+		err := rows.Scan(
+			&run.ID,
+			&run.AppID,
+			&run.UserID,
+			&format,
+			&granularity,
+			&run.PeriodStart,
+			&run.PeriodEnd,
+			&run.JournalCount,
+			&run.TransactionCount,
+			&run.TotalDebitCents,
+			&run.Confirmed,
+			&run.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		run.Format = entity.AccountingFormat(format)
+		run.Granularity = entity.JournalGranularity(granularity)
+		runs = append(runs, &run)
+	}
+
+	return runs, rows.Err()
+}
@@ -0,0 +1,175 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+// ErrAlertNotFound is returned when a triggered alert does not exist
+var ErrAlertNotFound = errors.New("alert not found")
+
+type PostgresAlertRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresAlertRepository(pool *pgxpool.Pool) *PostgresAlertRepository {
+	return &PostgresAlertRepository{pool: pool}
+}
+
+const alertColumns = `id, rule_id, user_id, app_id, alert_key, severity, title, message, status,
+	triggered_at, acknowledged_at, resolved_at, auto_resolved`
+
+func (r *PostgresAlertRepository) Create(ctx context.Context, a *entity.Alert, notification *entity.Notification) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO alerts (` + alertColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	if _, err := tx.Exec(ctx, query,
+		a.ID,
+		a.RuleID,
+		a.UserID,
+		a.AppID,
+		a.Key,
+		string(a.Severity),
+		a.Title,
+		a.Message,
+		string(a.Status),
+		a.TriggeredAt,
+		a.AcknowledgedAt,
+		a.ResolvedAt,
+		a.AutoResolved,
+	); err != nil {
+		return err
+	}
+
+	if notification != nil {
+		if err := insertNotification(ctx, tx, notification); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresAlertRepository) Update(ctx context.Context, a *entity.Alert) error {
+	query := `
+		UPDATE alerts
+		SET status = $2, acknowledged_at = $3, resolved_at = $4, auto_resolved = $5
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query, a.ID, string(a.Status), a.AcknowledgedAt, a.ResolvedAt, a.AutoResolved)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrAlertNotFound
+	}
+	return nil
+}
+
+func (r *PostgresAlertRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Alert, error) {
+	alerts, err := r.query(ctx, `SELECT `+alertColumns+` FROM alerts WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(alerts) == 0 {
+		return nil, ErrAlertNotFound
+	}
+	return alerts[0], nil
+}
+
+func (r *PostgresAlertRepository) List(ctx context.Context, filter repository.AlertFilter) ([]*entity.Alert, error) {
+	args := []interface{}{filter.UserID, filter.AppID}
+	conditions := []string{"user_id = $1", "app_id = $2"}
+
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	args = append(args, filter.Limit)
+	query := `SELECT ` + alertColumns + ` FROM alerts WHERE ` + strings.Join(conditions, " AND ") +
+		fmt.Sprintf(` ORDER BY triggered_at DESC LIMIT $%d`, len(args))
+
+	return r.query(ctx, query, args...)
+}
+
+func (r *PostgresAlertRepository) FindLatestByRuleAndKey(ctx context.Context, ruleID uuid.UUID, key string) (*entity.Alert, error) {
+	query := `
+		SELECT ` + alertColumns + ` FROM alerts
+		WHERE rule_id = $1 AND alert_key = $2
+		ORDER BY triggered_at DESC
+		LIMIT 1
+	`
+
+	alerts, err := r.query(ctx, query, ruleID, key)
+	if err != nil || len(alerts) == 0 {
+		return nil, err
+	}
+	return alerts[0], nil
+}
+
+func (r *PostgresAlertRepository) FindUnresolvedByRuleID(ctx context.Context, ruleID uuid.UUID) ([]*entity.Alert, error) {
+	query := `SELECT ` + alertColumns + ` FROM alerts WHERE rule_id = $1 AND status <> 'RESOLVED'`
+	return r.query(ctx, query, ruleID)
+}
+
+func (r *PostgresAlertRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.Alert, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []*entity.Alert
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+
+	return alerts, rows.Err()
+}
+
+func scanAlert(row pgx.Row) (*entity.Alert, error) {
+	var a entity.Alert
+	var severity, status string
+	if err := row.Scan(
+		&a.ID,
+		&a.RuleID,
+		&a.UserID,
+		&a.AppID,
+		&a.Key,
+		&severity,
+		&a.Title,
+		&a.Message,
+		&status,
+		&a.TriggeredAt,
+		&a.AcknowledgedAt,
+		&a.ResolvedAt,
+		&a.AutoResolved,
+	); err != nil {
+		return nil, err
+	}
+	a.Severity = entity.AlertSeverity(severity)
+	a.Status = entity.AlertStatus(status)
+	return &a, nil
+}
@@ -0,0 +1,160 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+// ErrAlertRuleNotFound is returned when an alert rule does not exist
+var ErrAlertRuleNotFound = errors.New("alert rule not found")
+
+type PostgresAlertRuleRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresAlertRuleRepository(pool *pgxpool.Pool) *PostgresAlertRuleRepository {
+	return &PostgresAlertRuleRepository{pool: pool}
+}
+
+const alertRuleColumns = `id, user_id, app_id, name, rule_type, threshold, risk_state, severity,
+	channels, cooldown_minutes, enabled, created_at, updated_at`
+
+func (r *PostgresAlertRuleRepository) Create(ctx context.Context, rule *entity.AlertRule) error {
+	query := `
+		INSERT INTO alert_rules (` + alertRuleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		rule.ID,
+		rule.UserID,
+		rule.AppID,
+		rule.Name,
+		string(rule.Type),
+		rule.Threshold,
+		string(rule.RiskState),
+		string(rule.Severity),
+		notificationChannelStrings(rule.Channels),
+		rule.CooldownMinutes,
+		rule.Enabled,
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresAlertRuleRepository) Update(ctx context.Context, rule *entity.AlertRule) error {
+	query := `
+		UPDATE alert_rules
+		SET name = $3, rule_type = $4, threshold = $5, risk_state = $6, severity = $7,
+		    channels = $8, cooldown_minutes = $9, enabled = $10, updated_at = $11
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		rule.ID,
+		rule.UserID,
+		rule.Name,
+		string(rule.Type),
+		rule.Threshold,
+		string(rule.RiskState),
+		string(rule.Severity),
+		notificationChannelStrings(rule.Channels),
+		rule.CooldownMinutes,
+		rule.Enabled,
+		rule.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrAlertRuleNotFound
+	}
+	return nil
+}
+
+func (r *PostgresAlertRuleRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM alert_rules WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrAlertRuleNotFound
+	}
+	return nil
+}
+
+func (r *PostgresAlertRuleRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.AlertRule, error) {
+	rules, err := r.query(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, ErrAlertRuleNotFound
+	}
+	return rules[0], nil
+}
+
+func (r *PostgresAlertRuleRepository) FindByUserAndApp(ctx context.Context, userID, appID uuid.UUID) ([]*entity.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE user_id = $1 AND app_id = $2 ORDER BY created_at`
+	return r.query(ctx, query, userID, appID)
+}
+
+func (r *PostgresAlertRuleRepository) FindEnabledByAppID(ctx context.Context, appID uuid.UUID) ([]*entity.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE app_id = $1 AND enabled ORDER BY created_at`
+	return r.query(ctx, query, appID)
+}
+
+func (r *PostgresAlertRuleRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.AlertRule, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*entity.AlertRule
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func scanAlertRule(row pgx.Row) (*entity.AlertRule, error) {
+	var rule entity.AlertRule
+	var ruleType, riskState, severity string
+	var channels []string
+	if err := row.Scan(
+		&rule.ID,
+		&rule.UserID,
+		&rule.AppID,
+		&rule.Name,
+		&ruleType,
+		&rule.Threshold,
+		&riskState,
+		&severity,
+		&channels,
+		&rule.CooldownMinutes,
+		&rule.Enabled,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	rule.Type = entity.AlertRuleType(ruleType)
+	rule.RiskState = valueobject.RiskState(riskState)
+	rule.Severity = entity.AlertSeverity(severity)
+	rule.Channels = toNotificationChannels(channels)
+	return &rule, nil
+}
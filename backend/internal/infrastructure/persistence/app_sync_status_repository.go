@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// ErrAppSyncStatusNotFound is returned when an app has never been synced
+var ErrAppSyncStatusNotFound = errors.New("app sync status not found")
+
+type PostgresAppSyncStatusRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresAppSyncStatusRepository(pool *pgxpool.Pool) *PostgresAppSyncStatusRepository {
+	return &PostgresAppSyncStatusRepository{pool: pool}
+}
+
+func (r *PostgresAppSyncStatusRepository) Upsert(ctx context.Context, s *entity.AppSyncStatus) error {
+	query := `
+		INSERT INTO app_sync_status (
+			app_id, last_attempt_at, last_success_at, failing_since, last_error, consecutive_failures
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (app_id) DO UPDATE SET
+			last_attempt_at = EXCLUDED.last_attempt_at,
+			last_success_at = EXCLUDED.last_success_at,
+			failing_since = EXCLUDED.failing_since,
+			last_error = EXCLUDED.last_error,
+			consecutive_failures = EXCLUDED.consecutive_failures
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		s.AppID,
+		s.LastAttemptAt,
+		s.LastSuccessAt,
+		s.FailingSince,
+		s.LastError,
+		s.ConsecutiveFailures,
+	)
+	return err
+}
+
+func (r *PostgresAppSyncStatusRepository) FindByAppID(ctx context.Context, appID uuid.UUID) (*entity.AppSyncStatus, error) {
+	query := `
+		SELECT app_id, last_attempt_at, last_success_at, failing_since, last_error, consecutive_failures
+		FROM app_sync_status
+		WHERE app_id = $1
+	`
+
+	var s entity.AppSyncStatus
+	err := r.pool.QueryRow(ctx, query, appID).Scan(
+		&s.AppID,
+		&s.LastAttemptAt,
+		&s.LastSuccessAt,
+		&s.FailingSince,
+		&s.LastError,
+		&s.ConsecutiveFailures,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAppSyncStatusNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
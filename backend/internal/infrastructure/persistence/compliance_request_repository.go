@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+type PostgresComplianceRequestRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresComplianceRequestRepository(pool *pgxpool.Pool) *PostgresComplianceRequestRepository {
+	return &PostgresComplianceRequestRepository{pool: pool}
+}
+
+func (r *PostgresComplianceRequestRepository) Create(ctx context.Context, c *entity.ComplianceRequest) error {
+	query := `
+		INSERT INTO compliance_requests (
+			id, topic, app_gid, shop_id, shop_domain, customer_id, data_request_id,
+			transactions_redacted, subscriptions_deleted, events_deleted, result,
+			requested_at, completed_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		c.ID,
+		c.Topic,
+		c.AppGID,
+		c.ShopID,
+		c.ShopDomain,
+		c.CustomerID,
+		c.DataRequestID,
+		c.TransactionsRedacted,
+		c.SubscriptionsDeleted,
+		c.EventsDeleted,
+		c.Result,
+		c.RequestedAt,
+		c.CompletedAt,
+	)
+	return err
+}
+
+func (r *PostgresComplianceRequestRepository) FindByShopDomain(ctx context.Context, shopDomain string) ([]*entity.ComplianceRequest, error) {
+	query := `
+		SELECT id, topic, app_gid, shop_id, shop_domain, customer_id, data_request_id,
+		       transactions_redacted, subscriptions_deleted, events_deleted, result,
+		       requested_at, completed_at
+		FROM compliance_requests
+		WHERE shop_domain = $1
+		ORDER BY requested_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, shopDomain)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*entity.ComplianceRequest
+	for rows.Next() {
+		var c entity.ComplianceRequest
+		if err := rows.Scan(
+			&c.ID,
+			&c.Topic,
+			&c.AppGID,
+			&c.ShopID,
+			&c.ShopDomain,
+			&c.CustomerID,
+			&c.DataRequestID,
+			&c.TransactionsRedacted,
+			&c.SubscriptionsDeleted,
+			&c.EventsDeleted,
+			&c.Result,
+			&c.RequestedAt,
+			&c.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		requests = append(requests, &c)
+	}
+
+	return requests, rows.Err()
+}
@@ -0,0 +1,502 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+var (
+	// ErrDunningSequenceNotFound is returned when a dunning sequence does not exist
+	ErrDunningSequenceNotFound = errors.New("dunning sequence not found")
+
+	// ErrDunningRunNotFound is returned when a dunning run does not exist
+	ErrDunningRunNotFound = errors.New("dunning run not found")
+
+	// ErrDunningTaskNotFound is returned when a dunning task does not exist
+	ErrDunningTaskNotFound = errors.New("dunning task not found")
+)
+
+type PostgresDunningSequenceRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresDunningSequenceRepository(pool *pgxpool.Pool) *PostgresDunningSequenceRepository {
+	return &PostgresDunningSequenceRepository{pool: pool}
+}
+
+const dunningSequenceColumns = `id, user_id, app_id, name, trigger_risk_state, steps, enabled, created_at, updated_at`
+
+func (r *PostgresDunningSequenceRepository) Create(ctx context.Context, sequence *entity.DunningSequence) error {
+	steps, err := json.Marshal(sequence.Steps)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO dunning_sequences (` + dunningSequenceColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		sequence.ID,
+		sequence.UserID,
+		sequence.AppID,
+		sequence.Name,
+		string(sequence.Trigger),
+		steps,
+		sequence.Enabled,
+		sequence.CreatedAt,
+		sequence.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresDunningSequenceRepository) Update(ctx context.Context, sequence *entity.DunningSequence) error {
+	steps, err := json.Marshal(sequence.Steps)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE dunning_sequences
+		SET name = $3, trigger_risk_state = $4, steps = $5, enabled = $6, updated_at = $7
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		sequence.ID,
+		sequence.UserID,
+		sequence.Name,
+		string(sequence.Trigger),
+		steps,
+		sequence.Enabled,
+		sequence.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrDunningSequenceNotFound
+	}
+	return nil
+}
+
+func (r *PostgresDunningSequenceRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM dunning_sequences WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrDunningSequenceNotFound
+	}
+	return nil
+}
+
+func (r *PostgresDunningSequenceRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.DunningSequence, error) {
+	sequences, err := r.query(ctx, `SELECT `+dunningSequenceColumns+` FROM dunning_sequences WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(sequences) == 0 {
+		return nil, ErrDunningSequenceNotFound
+	}
+	return sequences[0], nil
+}
+
+func (r *PostgresDunningSequenceRepository) FindByUserAndApp(ctx context.Context, userID, appID uuid.UUID) ([]*entity.DunningSequence, error) {
+	query := `SELECT ` + dunningSequenceColumns + ` FROM dunning_sequences WHERE user_id = $1 AND app_id = $2 ORDER BY created_at`
+	return r.query(ctx, query, userID, appID)
+}
+
+func (r *PostgresDunningSequenceRepository) FindEnabledByTrigger(ctx context.Context, appID uuid.UUID, trigger valueobject.RiskState) ([]*entity.DunningSequence, error) {
+	query := `
+		SELECT ` + dunningSequenceColumns + ` FROM dunning_sequences
+		WHERE app_id = $1 AND trigger_risk_state = $2 AND enabled
+		ORDER BY created_at
+	`
+	return r.query(ctx, query, appID, string(trigger))
+}
+
+func (r *PostgresDunningSequenceRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.DunningSequence, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sequences []*entity.DunningSequence
+	for rows.Next() {
+		var sequence entity.DunningSequence
+		var trigger string
+		var steps []byte
+		if err := rows.Scan(
+			&sequence.ID,
+			&sequence.UserID,
+			&sequence.AppID,
+			&sequence.Name,
+			&trigger,
+			&steps,
+			&sequence.Enabled,
+			&sequence.CreatedAt,
+			&sequence.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		sequence.Trigger = valueobject.RiskState(trigger)
+		if err := json.Unmarshal(steps, &sequence.Steps); err != nil {
+			return nil, err
+		}
+		sequences = append(sequences, &sequence)
+	}
+
+	return sequences, rows.Err()
+}
+
+type PostgresDunningRunRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresDunningRunRepository(pool *pgxpool.Pool) *PostgresDunningRunRepository {
+	return &PostgresDunningRunRepository{pool: pool}
+}
+
+const dunningRunColumns = `id, sequence_id, user_id, app_id, subscription_id, shop_domain, shop_name,
+	trigger_risk_state, mrr_cents, steps, results, status, next_step, next_step_at, attempts,
+	last_error, started_at, ended_at, recovered_at`
+
+func (r *PostgresDunningRunRepository) Create(ctx context.Context, run *entity.DunningRun) (bool, error) {
+	steps, err := json.Marshal(run.Steps)
+	if err != nil {
+		return false, err
+	}
+	results, err := json.Marshal(run.Results)
+	if err != nil {
+		return false, err
+	}
+
+	// The partial unique index on active runs makes a second active run for
+	// the same sequence and subscription a no-op
+	query := `
+		INSERT INTO dunning_runs (` + dunningRunColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		ON CONFLICT (sequence_id, subscription_id) WHERE status = 'ACTIVE' DO NOTHING
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		run.ID,
+		run.SequenceID,
+		run.UserID,
+		run.AppID,
+		run.SubscriptionID,
+		run.ShopDomain,
+		run.ShopName,
+		string(run.Trigger),
+		run.MRRCents,
+		steps,
+		results,
+		string(run.Status),
+		run.NextStep,
+		run.NextStepAt,
+		run.Attempts,
+		run.LastError,
+		run.StartedAt,
+		run.EndedAt,
+		run.RecoveredAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *PostgresDunningRunRepository) Update(ctx context.Context, run *entity.DunningRun) error {
+	results, err := json.Marshal(run.Results)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE dunning_runs
+		SET results = $2, status = $3, next_step = $4, next_step_at = $5, attempts = $6,
+			last_error = $7, ended_at = $8, recovered_at = $9, locked_until = NULL
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		run.ID,
+		results,
+		string(run.Status),
+		run.NextStep,
+		run.NextStepAt,
+		run.Attempts,
+		run.LastError,
+		run.EndedAt,
+		run.RecoveredAt,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrDunningRunNotFound
+	}
+	return nil
+}
+
+func (r *PostgresDunningRunRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.DunningRun, error) {
+	runs, err := r.query(ctx, `SELECT `+dunningRunColumns+` FROM dunning_runs WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, ErrDunningRunNotFound
+	}
+	return runs[0], nil
+}
+
+func (r *PostgresDunningRunRepository) List(ctx context.Context, filter repository.DunningRunFilter) ([]*entity.DunningRun, error) {
+	args := []interface{}{filter.UserID, filter.AppID}
+	conditions := []string{"user_id = $1", "app_id = $2"}
+
+	if filter.SequenceID != nil {
+		args = append(args, *filter.SequenceID)
+		conditions = append(conditions, fmt.Sprintf("sequence_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	args = append(args, filter.Limit)
+	query := `SELECT ` + dunningRunColumns + ` FROM dunning_runs WHERE ` + strings.Join(conditions, " AND ") +
+		fmt.Sprintf(` ORDER BY started_at DESC LIMIT $%d`, len(args))
+
+	return r.query(ctx, query, args...)
+}
+
+func (r *PostgresDunningRunRepository) FindRecoverable(ctx context.Context, subscriptionID uuid.UUID, exhaustedSince time.Time) ([]*entity.DunningRun, error) {
+	query := `
+		SELECT ` + dunningRunColumns + ` FROM dunning_runs
+		WHERE subscription_id = $1
+		  AND (status = 'ACTIVE' OR (status = 'EXHAUSTED' AND ended_at >= $2))
+	`
+	return r.query(ctx, query, subscriptionID, exhaustedSince)
+}
+
+func (r *PostgresDunningRunRepository) FindStartedBetween(ctx context.Context, userID, appID uuid.UUID, from, to time.Time) ([]*entity.DunningRun, error) {
+	query := `
+		SELECT ` + dunningRunColumns + ` FROM dunning_runs
+		WHERE user_id = $1 AND app_id = $2 AND started_at >= $3 AND started_at < $4
+		ORDER BY started_at
+	`
+	return r.query(ctx, query, userID, appID, from, to)
+}
+
+// ClaimDue leases up to limit active runs with a step due. SKIP LOCKED keeps
+// concurrent workers from claiming the same rows.
+func (r *PostgresDunningRunRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.DunningRun, error) {
+	query := `
+		UPDATE dunning_runs
+		SET locked_until = $2
+		WHERE id IN (
+			SELECT id FROM dunning_runs
+			WHERE status = 'ACTIVE' AND next_step_at <= $1
+			  AND (locked_until IS NULL OR locked_until <= $1)
+			ORDER BY next_step_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + dunningRunColumns
+
+	runs, err := r.query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not preserve the subquery order
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].NextStepAt.Before(*runs[j].NextStepAt)
+	})
+	return runs, nil
+}
+
+func (r *PostgresDunningRunRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.DunningRun, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*entity.DunningRun
+	for rows.Next() {
+		run, err := scanDunningRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+func scanDunningRun(row pgx.Row) (*entity.DunningRun, error) {
+	var run entity.DunningRun
+	var trigger, status string
+	var steps, results []byte
+	if err := row.Scan(
+		&run.ID,
+		&run.SequenceID,
+		&run.UserID,
+		&run.AppID,
+		&run.SubscriptionID,
+		&run.ShopDomain,
+		&run.ShopName,
+		&trigger,
+		&run.MRRCents,
+		&steps,
+		&results,
+		&status,
+		&run.NextStep,
+		&run.NextStepAt,
+		&run.Attempts,
+		&run.LastError,
+		&run.StartedAt,
+		&run.EndedAt,
+		&run.RecoveredAt,
+	); err != nil {
+		return nil, err
+	}
+
+	run.Trigger = valueobject.RiskState(trigger)
+	run.Status = entity.DunningRunStatus(status)
+	if err := json.Unmarshal(steps, &run.Steps); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(results, &run.Results); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+type PostgresDunningTaskRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresDunningTaskRepository(pool *pgxpool.Pool) *PostgresDunningTaskRepository {
+	return &PostgresDunningTaskRepository{pool: pool}
+}
+
+const dunningTaskColumns = `id, run_id, user_id, app_id, shop_domain, title, description, assignee,
+	status, created_at, completed_at`
+
+func (r *PostgresDunningTaskRepository) Create(ctx context.Context, task *entity.DunningTask) error {
+	query := `
+		INSERT INTO dunning_tasks (` + dunningTaskColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		task.ID,
+		task.RunID,
+		task.UserID,
+		task.AppID,
+		task.ShopDomain,
+		task.Title,
+		task.Description,
+		task.Assignee,
+		string(task.Status),
+		task.CreatedAt,
+		task.CompletedAt,
+	)
+	return err
+}
+
+func (r *PostgresDunningTaskRepository) Update(ctx context.Context, task *entity.DunningTask) error {
+	result, err := r.pool.Exec(ctx,
+		`UPDATE dunning_tasks SET status = $2, completed_at = $3 WHERE id = $1`,
+		task.ID, string(task.Status), task.CompletedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrDunningTaskNotFound
+	}
+	return nil
+}
+
+func (r *PostgresDunningTaskRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.DunningTask, error) {
+	tasks, err := r.query(ctx, `SELECT `+dunningTaskColumns+` FROM dunning_tasks WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, ErrDunningTaskNotFound
+	}
+	return tasks[0], nil
+}
+
+func (r *PostgresDunningTaskRepository) List(ctx context.Context, filter repository.DunningTaskFilter) ([]*entity.DunningTask, error) {
+	args := []interface{}{filter.UserID, filter.AppID}
+	conditions := []string{"user_id = $1", "app_id = $2"}
+
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	args = append(args, filter.Limit)
+	query := `SELECT ` + dunningTaskColumns + ` FROM dunning_tasks WHERE ` + strings.Join(conditions, " AND ") +
+		fmt.Sprintf(` ORDER BY created_at DESC LIMIT $%d`, len(args))
+
+	return r.query(ctx, query, args...)
+}
+
+func (r *PostgresDunningTaskRepository) CancelOpenByRunID(ctx context.Context, runID uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `UPDATE dunning_tasks SET status = 'CANCELLED' WHERE run_id = $1 AND status = 'OPEN'`, runID)
+	return err
+}
+
+func (r *PostgresDunningTaskRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.DunningTask, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*entity.DunningTask
+	for rows.Next() {
+		var task entity.DunningTask
+		var status string
+		if err := rows.Scan(
+			&task.ID,
+			&task.RunID,
+			&task.UserID,
+			&task.AppID,
+			&task.ShopDomain,
+			&task.Title,
+			&task.Description,
+			&task.Assignee,
+			&status,
+			&task.CreatedAt,
+			&task.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		task.Status = entity.DunningTaskStatus(status)
+		tasks = append(tasks, &task)
+	}
+
+	return tasks, rows.Err()
+}
@@ -0,0 +1,154 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// ErrNotificationEmailNotFound is returned when a notification email address does not exist
+var ErrNotificationEmailNotFound = errors.New("notification email not found")
+
+type PostgresNotificationEmailRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresNotificationEmailRepository(pool *pgxpool.Pool) *PostgresNotificationEmailRepository {
+	return &PostgresNotificationEmailRepository{pool: pool}
+}
+
+const notificationEmailColumns = `id, user_id, address, verification_token_hash, verification_sent_at, verified_at,
+	unsubscribe_token_hash, unsubscribed_at, created_at`
+
+func (r *PostgresNotificationEmailRepository) Create(ctx context.Context, email *entity.NotificationEmail) error {
+	query := `
+		INSERT INTO notification_emails (` + notificationEmailColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		email.ID,
+		email.UserID,
+		email.Address,
+		nullableString(email.VerificationTokenHash),
+		email.VerificationSentAt,
+		email.VerifiedAt,
+		email.UnsubscribeTokenHash,
+		email.UnsubscribedAt,
+		email.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresNotificationEmailRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.NotificationEmail, error) {
+	return r.findOne(ctx, `SELECT `+notificationEmailColumns+` FROM notification_emails WHERE id = $1`, id)
+}
+
+func (r *PostgresNotificationEmailRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationEmail, error) {
+	query := `SELECT ` + notificationEmailColumns + ` FROM notification_emails WHERE user_id = $1 ORDER BY created_at`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var emails []*entity.NotificationEmail
+	for rows.Next() {
+		email, err := scanNotificationEmail(rows)
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+
+	return emails, rows.Err()
+}
+
+func (r *PostgresNotificationEmailRepository) FindByVerificationTokenHash(ctx context.Context, tokenHash string) (*entity.NotificationEmail, error) {
+	return r.findOne(ctx, `SELECT `+notificationEmailColumns+` FROM notification_emails WHERE verification_token_hash = $1`, tokenHash)
+}
+
+func (r *PostgresNotificationEmailRepository) FindByUnsubscribeTokenHash(ctx context.Context, tokenHash string) (*entity.NotificationEmail, error) {
+	return r.findOne(ctx, `SELECT `+notificationEmailColumns+` FROM notification_emails WHERE unsubscribe_token_hash = $1`, tokenHash)
+}
+
+func (r *PostgresNotificationEmailRepository) Update(ctx context.Context, email *entity.NotificationEmail) error {
+	query := `
+		UPDATE notification_emails
+		SET verification_token_hash = $2, verification_sent_at = $3, verified_at = $4, unsubscribed_at = $5
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		email.ID,
+		nullableString(email.VerificationTokenHash),
+		email.VerificationSentAt,
+		email.VerifiedAt,
+		email.UnsubscribedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotificationEmailNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNotificationEmailRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM notification_emails WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotificationEmailNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNotificationEmailRepository) findOne(ctx context.Context, query string, arg interface{}) (*entity.NotificationEmail, error) {
+	email, err := scanNotificationEmail(r.pool.QueryRow(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotificationEmailNotFound
+		}
+		return nil, err
+	}
+	return email, nil
+}
+
+func scanNotificationEmail(row pgx.Row) (*entity.NotificationEmail, error) {
+	var email entity.NotificationEmail
+	var verificationTokenHash *string
+	if err := row.Scan(
+		&email.ID,
+		&email.UserID,
+		&email.Address,
+		&verificationTokenHash,
+		&email.VerificationSentAt,
+		&email.VerifiedAt,
+		&email.UnsubscribeTokenHash,
+		&email.UnsubscribedAt,
+		&email.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if verificationTokenHash != nil {
+		email.VerificationTokenHash = *verificationTokenHash
+	}
+	return &email, nil
+}
+
+// nullableString stores an empty string as NULL, so unique indexes ignore it
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -22,10 +22,22 @@ func NewPostgresNotificationPreferencesRepository(pool *pgxpool.Pool) *PostgresN
 	return &PostgresNotificationPreferencesRepository{pool: pool}
 }
 
+// notificationPreferencesColumns selects TIME columns as text for parseTimeOfDay
+const notificationPreferencesColumns = `id, user_id, critical_enabled, daily_summary_enabled, daily_summary_time::text,
+	weekly_digest_enabled, slack_webhook_url, timezone, quiet_hours_start::text, quiet_hours_end::text, skip_weekends,
+	next_daily_summary_at, created_at, updated_at`
+
+// prefixedNotificationPreferencesColumns is notificationPreferencesColumns for an UPDATE ... FROM returning clause
+const prefixedNotificationPreferencesColumns = `p.id, p.user_id, p.critical_enabled, p.daily_summary_enabled,
+	p.daily_summary_time::text, p.weekly_digest_enabled, p.slack_webhook_url, p.timezone, p.quiet_hours_start::text,
+	p.quiet_hours_end::text, p.skip_weekends, p.next_daily_summary_at, p.created_at, p.updated_at`
+
 func (r *PostgresNotificationPreferencesRepository) Create(ctx context.Context, prefs *entity.NotificationPreferences) error {
 	query := `
-		INSERT INTO notification_preferences (id, user_id, critical_enabled, daily_summary_enabled, daily_summary_time, slack_webhook_url, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO notification_preferences (id, user_id, critical_enabled, daily_summary_enabled, daily_summary_time,
+			weekly_digest_enabled, slack_webhook_url, timezone, quiet_hours_start, quiet_hours_end, skip_weekends,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
 
 	_, err := r.pool.Exec(ctx, query,
@@ -34,7 +46,12 @@ func (r *PostgresNotificationPreferencesRepository) Create(ctx context.Context,
 		prefs.CriticalEnabled,
 		prefs.DailySummaryEnabled,
 		prefs.DailySummaryTime.Format("15:04:05"),
+		prefs.WeeklyDigestEnabled,
 		prefs.SlackWebhookURL,
+		timezoneOrUTC(prefs.Timezone),
+		formatTimeOfDay(prefs.QuietHoursStart),
+		formatTimeOfDay(prefs.QuietHoursEnd),
+		prefs.SkipWeekends,
 		prefs.CreatedAt,
 		prefs.UpdatedAt,
 	)
@@ -43,27 +60,9 @@ func (r *PostgresNotificationPreferencesRepository) Create(ctx context.Context,
 }
 
 func (r *PostgresNotificationPreferencesRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*entity.NotificationPreferences, error) {
-	query := `
-		SELECT id, user_id, critical_enabled, daily_summary_enabled, daily_summary_time, slack_webhook_url, created_at, updated_at
-		FROM notification_preferences
-		WHERE user_id = $1
-	`
-
-	var prefs entity.NotificationPreferences
-	var summaryTimeStr string
-	var slackURL *string
-
-	err := r.pool.QueryRow(ctx, query, userID).Scan(
-		&prefs.ID,
-		&prefs.UserID,
-		&prefs.CriticalEnabled,
-		&prefs.DailySummaryEnabled,
-		&summaryTimeStr,
-		&slackURL,
-		&prefs.CreatedAt,
-		&prefs.UpdatedAt,
-	)
+	query := `SELECT ` + notificationPreferencesColumns + ` FROM notification_preferences WHERE user_id = $1`
 
+	prefs, err := scanNotificationPreferences(r.pool.QueryRow(ctx, query, userID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotificationPreferencesNotFound
@@ -71,24 +70,17 @@ func (r *PostgresNotificationPreferencesRepository) FindByUserID(ctx context.Con
 		return nil, err
 	}
 
-	// Parse time from HH:MM:SS format
-	parsedTime, err := parseTimeOfDay(summaryTimeStr)
-	if err != nil {
-		return nil, err
-	}
-	prefs.DailySummaryTime = parsedTime
-
-	if slackURL != nil {
-		prefs.SlackWebhookURL = *slackURL
-	}
-
-	return &prefs, nil
+	return prefs, nil
 }
 
+// Update saves the preferences and clears the next daily summary time, so the
+// scheduler recomputes it from the new settings
 func (r *PostgresNotificationPreferencesRepository) Update(ctx context.Context, prefs *entity.NotificationPreferences) error {
 	query := `
 		UPDATE notification_preferences
-		SET critical_enabled = $2, daily_summary_enabled = $3, daily_summary_time = $4, slack_webhook_url = $5, updated_at = $6
+		SET critical_enabled = $2, daily_summary_enabled = $3, daily_summary_time = $4, weekly_digest_enabled = $5,
+			slack_webhook_url = $6, timezone = $7, quiet_hours_start = $8, quiet_hours_end = $9, skip_weekends = $10,
+			next_daily_summary_at = NULL, updated_at = $11
 		WHERE user_id = $1
 	`
 
@@ -97,7 +89,12 @@ func (r *PostgresNotificationPreferencesRepository) Update(ctx context.Context,
 		prefs.CriticalEnabled,
 		prefs.DailySummaryEnabled,
 		prefs.DailySummaryTime.Format("15:04:05"),
+		prefs.WeeklyDigestEnabled,
 		prefs.SlackWebhookURL,
+		timezoneOrUTC(prefs.Timezone),
+		formatTimeOfDay(prefs.QuietHoursStart),
+		formatTimeOfDay(prefs.QuietHoursEnd),
+		prefs.SkipWeekends,
 		prefs.UpdatedAt,
 	)
 
@@ -112,15 +109,24 @@ func (r *PostgresNotificationPreferencesRepository) Update(ctx context.Context,
 	return nil
 }
 
+// Upsert creates or updates the preferences. Like Update, it clears the next daily summary time.
 func (r *PostgresNotificationPreferencesRepository) Upsert(ctx context.Context, prefs *entity.NotificationPreferences) error {
 	query := `
-		INSERT INTO notification_preferences (id, user_id, critical_enabled, daily_summary_enabled, daily_summary_time, slack_webhook_url, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO notification_preferences (id, user_id, critical_enabled, daily_summary_enabled, daily_summary_time,
+			weekly_digest_enabled, slack_webhook_url, timezone, quiet_hours_start, quiet_hours_end, skip_weekends,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		ON CONFLICT (user_id) DO UPDATE SET
 			critical_enabled = EXCLUDED.critical_enabled,
 			daily_summary_enabled = EXCLUDED.daily_summary_enabled,
 			daily_summary_time = EXCLUDED.daily_summary_time,
+			weekly_digest_enabled = EXCLUDED.weekly_digest_enabled,
 			slack_webhook_url = EXCLUDED.slack_webhook_url,
+			timezone = EXCLUDED.timezone,
+			quiet_hours_start = EXCLUDED.quiet_hours_start,
+			quiet_hours_end = EXCLUDED.quiet_hours_end,
+			skip_weekends = EXCLUDED.skip_weekends,
+			next_daily_summary_at = NULL,
 			updated_at = EXCLUDED.updated_at
 	`
 
@@ -130,7 +136,12 @@ func (r *PostgresNotificationPreferencesRepository) Upsert(ctx context.Context,
 		prefs.CriticalEnabled,
 		prefs.DailySummaryEnabled,
 		prefs.DailySummaryTime.Format("15:04:05"),
+		prefs.WeeklyDigestEnabled,
 		prefs.SlackWebhookURL,
+		timezoneOrUTC(prefs.Timezone),
+		formatTimeOfDay(prefs.QuietHoursStart),
+		formatTimeOfDay(prefs.QuietHoursEnd),
+		prefs.SkipWeekends,
 		prefs.CreatedAt,
 		prefs.UpdatedAt,
 	)
@@ -138,7 +149,126 @@ func (r *PostgresNotificationPreferencesRepository) Upsert(ctx context.Context,
 	return err
 }
 
+func (r *PostgresNotificationPreferencesRepository) ClaimDueDailySummaries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.NotificationPreferences, error) {
+	query := `
+		WITH due AS (
+			SELECT user_id
+			FROM notification_preferences
+			WHERE daily_summary_enabled = TRUE
+				AND (next_daily_summary_at IS NULL OR next_daily_summary_at <= $1)
+				AND (daily_summary_locked_until IS NULL OR daily_summary_locked_until <= $1)
+			ORDER BY next_daily_summary_at NULLS FIRST
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE notification_preferences p
+		SET daily_summary_locked_until = $2
+		FROM due
+		WHERE p.user_id = due.user_id
+		RETURNING ` + prefixedNotificationPreferencesColumns + `
+	`
+
+	rows, err := r.pool.Query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*entity.NotificationPreferences
+	for rows.Next() {
+		prefs, err := scanNotificationPreferences(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, prefs)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *PostgresNotificationPreferencesRepository) SetNextDailySummaryAt(ctx context.Context, userID uuid.UUID, next time.Time) error {
+	query := `
+		UPDATE notification_preferences
+		SET next_daily_summary_at = $2, daily_summary_locked_until = NULL
+		WHERE user_id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query, userID, next)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotificationPreferencesNotFound
+	}
+	return nil
+}
+
+func scanNotificationPreferences(row pgx.Row) (*entity.NotificationPreferences, error) {
+	var prefs entity.NotificationPreferences
+	var summaryTimeStr string
+	var slackURL, quietStart, quietEnd *string
+
+	if err := row.Scan(
+		&prefs.ID,
+		&prefs.UserID,
+		&prefs.CriticalEnabled,
+		&prefs.DailySummaryEnabled,
+		&summaryTimeStr,
+		&prefs.WeeklyDigestEnabled,
+		&slackURL,
+		&prefs.Timezone,
+		&quietStart,
+		&quietEnd,
+		&prefs.SkipWeekends,
+		&prefs.NextDailySummaryAt,
+		&prefs.CreatedAt,
+		&prefs.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	// Parse time from HH:MM:SS format
+	parsedTime, err := parseTimeOfDay(summaryTimeStr)
+	if err != nil {
+		return nil, err
+	}
+	prefs.DailySummaryTime = parsedTime
+
+	if slackURL != nil {
+		prefs.SlackWebhookURL = *slackURL
+	}
+	if quietStart != nil && quietEnd != nil {
+		start, err := parseTimeOfDay(*quietStart)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseTimeOfDay(*quietEnd)
+		if err != nil {
+			return nil, err
+		}
+		prefs.QuietHoursStart, prefs.QuietHoursEnd = &start, &end
+	}
+
+	return &prefs, nil
+}
+
 // parseTimeOfDay parses a time string in HH:MM:SS format to a time.Time
 func parseTimeOfDay(s string) (t time.Time, err error) {
 	return time.Parse("15:04:05", s)
 }
+
+// formatTimeOfDay formats an optional time of day for a TIME column
+func formatTimeOfDay(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format("15:04:05")
+	return &s
+}
+
+func timezoneOrUTC(tz string) string {
+	if tz == "" {
+		return "UTC"
+	}
+	return tz
+}
@@ -0,0 +1,316 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+// ErrNotificationNotFound is returned when a notification does not exist
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type PostgresNotificationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresNotificationRepository(pool *pgxpool.Pool) *PostgresNotificationRepository {
+	return &PostgresNotificationRepository{pool: pool}
+}
+
+const notificationColumns = `id, user_id, app_id, kind, source_id, severity, title, body, channels,
+	pending_channels, status, attempts, next_attempt_at, last_error, created_at, delivered_at, read_at`
+
+// execer is satisfied by the pool and by a transaction, so a notification can be
+// written in the same transaction as the event that causes it
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+func (r *PostgresNotificationRepository) Create(ctx context.Context, n *entity.Notification) error {
+	return insertNotification(ctx, r.pool, n)
+}
+
+func (r *PostgresNotificationRepository) CreateDailySummary(ctx context.Context, n *entity.Notification, summaryDate time.Time) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertNotification(ctx, tx, n); err != nil {
+		return false, err
+	}
+
+	// The (user_id, summary_date) key makes a second summary for the day a no-op
+	result, err := tx.Exec(ctx, `
+		INSERT INTO daily_summary_sends (user_id, summary_date, notification_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, summary_date) DO NOTHING
+	`, n.UserID, summaryDate.Format("2006-01-02"), n.ID, n.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+func insertNotification(ctx context.Context, db execer, n *entity.Notification) error {
+	query := `
+		INSERT INTO notifications (` + notificationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	`
+
+	_, err := db.Exec(ctx, query,
+		n.ID,
+		n.UserID,
+		n.AppID,
+		string(n.Kind),
+		n.SourceID,
+		string(n.Severity),
+		n.Title,
+		n.Body,
+		notificationChannelStrings(n.Channels),
+		notificationChannelStrings(n.PendingChannels),
+		string(n.Status),
+		n.Attempts,
+		n.NextAttemptAt,
+		n.LastError,
+		n.CreatedAt,
+		n.DeliveredAt,
+		n.ReadAt,
+	)
+	return err
+}
+
+func (r *PostgresNotificationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error) {
+	notifications, err := r.query(ctx, `SELECT `+notificationColumns+` FROM notifications WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(notifications) == 0 {
+		return nil, ErrNotificationNotFound
+	}
+	return notifications[0], nil
+}
+
+func (r *PostgresNotificationRepository) List(ctx context.Context, filter repository.NotificationFilter) ([]*entity.Notification, error) {
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE user_id = $1`
+	if filter.UnreadOnly {
+		query += ` AND read_at IS NULL`
+	}
+	query += ` ORDER BY created_at DESC LIMIT $2`
+
+	return r.query(ctx, query, filter.UserID, filter.Limit)
+}
+
+func (r *PostgresNotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+func (r *PostgresNotificationRepository) MarkRead(ctx context.Context, userID, id uuid.UUID, readAt time.Time) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, $3)
+		WHERE id = $1 AND user_id = $2
+	`, id, userID, readAt)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID, readAt time.Time) (int64, error) {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE notifications SET read_at = $2 WHERE user_id = $1 AND read_at IS NULL
+	`, userID, readAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// ClaimDue leases up to limit due pending notifications. SKIP LOCKED keeps
+// concurrent workers from claiming the same rows.
+func (r *PostgresNotificationRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.Notification, error) {
+	query := `
+		UPDATE notifications
+		SET locked_until = $2
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE status = 'PENDING' AND next_attempt_at <= $1
+			  AND (locked_until IS NULL OR locked_until <= $1)
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + notificationColumns
+
+	notifications, err := r.query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not preserve the subquery order
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.Before(notifications[j].CreatedAt)
+	})
+	return notifications, nil
+}
+
+func (r *PostgresNotificationRepository) Update(ctx context.Context, n *entity.Notification) error {
+	query := `
+		UPDATE notifications
+		SET pending_channels = $2, status = $3, attempts = $4, next_attempt_at = $5,
+			last_error = $6, delivered_at = $7, locked_until = NULL
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		n.ID,
+		notificationChannelStrings(n.PendingChannels),
+		string(n.Status),
+		n.Attempts,
+		n.NextAttemptAt,
+		n.LastError,
+		n.DeliveredAt,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) CreateAttempt(ctx context.Context, a *entity.NotificationAttempt) error {
+	query := `
+		INSERT INTO notification_attempts (id, notification_id, channel, attempt, status, error, attempted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		a.ID,
+		a.NotificationID,
+		string(a.Channel),
+		a.Attempt,
+		string(a.Status),
+		a.Error,
+		a.AttemptedAt,
+	)
+	return err
+}
+
+func (r *PostgresNotificationRepository) FindAttempts(ctx context.Context, notificationID uuid.UUID) ([]*entity.NotificationAttempt, error) {
+	query := `
+		SELECT id, notification_id, channel, attempt, status, error, attempted_at
+		FROM notification_attempts
+		WHERE notification_id = $1
+		ORDER BY attempted_at, channel
+	`
+
+	rows, err := r.pool.Query(ctx, query, notificationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []*entity.NotificationAttempt
+	for rows.Next() {
+		var a entity.NotificationAttempt
+		var channel, status string
+		if err := rows.Scan(&a.ID, &a.NotificationID, &channel, &a.Attempt, &status, &a.Error, &a.AttemptedAt); err != nil {
+			return nil, err
+		}
+		a.Channel = entity.NotificationChannel(channel)
+		a.Status = entity.NotificationAttemptStatus(status)
+		attempts = append(attempts, &a)
+	}
+
+	return attempts, rows.Err()
+}
+
+func (r *PostgresNotificationRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.Notification, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*entity.Notification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+func scanNotification(row pgx.Row) (*entity.Notification, error) {
+	var n entity.Notification
+	var kind, severity, status string
+	var channels, pending []string
+	if err := row.Scan(
+		&n.ID,
+		&n.UserID,
+		&n.AppID,
+		&kind,
+		&n.SourceID,
+		&severity,
+		&n.Title,
+		&n.Body,
+		&channels,
+		&pending,
+		&status,
+		&n.Attempts,
+		&n.NextAttemptAt,
+		&n.LastError,
+		&n.CreatedAt,
+		&n.DeliveredAt,
+		&n.ReadAt,
+	); err != nil {
+		return nil, err
+	}
+
+	n.Kind = entity.NotificationKind(kind)
+	n.Severity = entity.AlertSeverity(severity)
+	n.Status = entity.NotificationStatus(status)
+	n.Channels = toNotificationChannels(channels)
+	n.PendingChannels = toNotificationChannels(pending)
+	return &n, nil
+}
+
+func notificationChannelStrings(channels []entity.NotificationChannel) []string {
+	result := make([]string, len(channels))
+	for i, c := range channels {
+		result[i] = string(c)
+	}
+	return result
+}
+
+func toNotificationChannels(channels []string) []entity.NotificationChannel {
+	result := make([]entity.NotificationChannel, len(channels))
+	for i, c := range channels {
+		result[i] = entity.NotificationChannel(c)
+	}
+	return result
+}
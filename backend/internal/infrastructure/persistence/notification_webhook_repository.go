@@ -0,0 +1,152 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// ErrNotificationWebhookNotFound is returned when a notification webhook does not exist
+var ErrNotificationWebhookNotFound = errors.New("notification webhook not found")
+
+type PostgresNotificationWebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresNotificationWebhookRepository(pool *pgxpool.Pool) *PostgresNotificationWebhookRepository {
+	return &PostgresNotificationWebhookRepository{pool: pool}
+}
+
+const notificationWebhookColumns = `id, user_id, channel, name, url, encrypted_secret, enabled,
+	last_tested_at, last_test_error, created_at, updated_at`
+
+func (r *PostgresNotificationWebhookRepository) Create(ctx context.Context, webhook *entity.NotificationWebhook) error {
+	query := `
+		INSERT INTO notification_webhooks (
+			id, user_id, channel, name, url, encrypted_secret, enabled, last_tested_at, last_test_error,
+			created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		webhook.ID,
+		webhook.UserID,
+		string(webhook.Channel),
+		webhook.Name,
+		webhook.URL,
+		webhook.EncryptedSecret,
+		webhook.Enabled,
+		webhook.LastTestedAt,
+		nullableString(webhook.LastTestError),
+		webhook.CreatedAt,
+		webhook.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresNotificationWebhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.NotificationWebhook, error) {
+	query := `SELECT ` + notificationWebhookColumns + ` FROM notification_webhooks WHERE id = $1`
+
+	webhook, err := scanNotificationWebhook(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotificationWebhookNotFound
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (r *PostgresNotificationWebhookRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationWebhook, error) {
+	query := `SELECT ` + notificationWebhookColumns + ` FROM notification_webhooks WHERE user_id = $1 ORDER BY created_at`
+
+	return r.query(ctx, query, userID)
+}
+
+func (r *PostgresNotificationWebhookRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.NotificationWebhook, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*entity.NotificationWebhook
+	for rows.Next() {
+		webhook, err := scanNotificationWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (r *PostgresNotificationWebhookRepository) Update(ctx context.Context, webhook *entity.NotificationWebhook) error {
+	query := `
+		UPDATE notification_webhooks
+		SET name = $2, url = $3, encrypted_secret = $4, enabled = $5, last_tested_at = $6, last_test_error = $7, updated_at = $8
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		webhook.ID,
+		webhook.Name,
+		webhook.URL,
+		webhook.EncryptedSecret,
+		webhook.Enabled,
+		webhook.LastTestedAt,
+		nullableString(webhook.LastTestError),
+		webhook.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotificationWebhookNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNotificationWebhookRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM notification_webhooks WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotificationWebhookNotFound
+	}
+	return nil
+}
+
+func scanNotificationWebhook(row pgx.Row) (*entity.NotificationWebhook, error) {
+	var webhook entity.NotificationWebhook
+	var channel string
+	var lastTestError *string
+	if err := row.Scan(
+		&webhook.ID,
+		&webhook.UserID,
+		&channel,
+		&webhook.Name,
+		&webhook.URL,
+		&webhook.EncryptedSecret,
+		&webhook.Enabled,
+		&webhook.LastTestedAt,
+		&lastTestError,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	webhook.Channel = entity.NotificationChannel(channel)
+	if lastTestError != nil {
+		webhook.LastTestError = *lastTestError
+	}
+	return &webhook, nil
+}
@@ -23,13 +23,14 @@ func NewPostgresPartnerAccountRepository(pool *pgxpool.Pool) *PostgresPartnerAcc
 
 func (r *PostgresPartnerAccountRepository) Create(ctx context.Context, account *entity.PartnerAccount) error {
 	query := `
-		INSERT INTO partner_accounts (id, user_id, integration_type, partner_id, encrypted_access_token, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO partner_accounts (id, user_id, workspace_id, integration_type, partner_id, encrypted_access_token, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
 	_, err := r.pool.Exec(ctx, query,
 		account.ID,
 		account.UserID,
+		account.WorkspaceID,
 		account.IntegrationType.String(),
 		account.PartnerID,
 		account.EncryptedAccessToken,
@@ -41,7 +42,7 @@ func (r *PostgresPartnerAccountRepository) Create(ctx context.Context, account *
 
 func (r *PostgresPartnerAccountRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.PartnerAccount, error) {
 	query := `
-		SELECT id, user_id, integration_type, partner_id, encrypted_access_token, created_at
+		SELECT id, user_id, workspace_id, integration_type, partner_id, encrypted_access_token, created_at
 		FROM partner_accounts
 		WHERE id = $1
 	`
@@ -52,6 +53,7 @@ func (r *PostgresPartnerAccountRepository) FindByID(ctx context.Context, id uuid
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&account.ID,
 		&account.UserID,
+		&account.WorkspaceID,
 		&integrationType,
 		&account.PartnerID,
 		&account.EncryptedAccessToken,
@@ -71,7 +73,7 @@ func (r *PostgresPartnerAccountRepository) FindByID(ctx context.Context, id uuid
 
 func (r *PostgresPartnerAccountRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*entity.PartnerAccount, error) {
 	query := `
-		SELECT id, user_id, integration_type, partner_id, encrypted_access_token, created_at
+		SELECT id, user_id, workspace_id, integration_type, partner_id, encrypted_access_token, created_at
 		FROM partner_accounts
 		WHERE user_id = $1
 	`
@@ -82,6 +84,7 @@ func (r *PostgresPartnerAccountRepository) FindByUserID(ctx context.Context, use
 	err := r.pool.QueryRow(ctx, query, userID).Scan(
 		&account.ID,
 		&account.UserID,
+		&account.WorkspaceID,
 		&integrationType,
 		&account.PartnerID,
 		&account.EncryptedAccessToken,
@@ -99,9 +102,77 @@ func (r *PostgresPartnerAccountRepository) FindByUserID(ctx context.Context, use
 	return &account, nil
 }
 
+func (r *PostgresPartnerAccountRepository) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (*entity.PartnerAccount, error) {
+	query := `
+		SELECT id, user_id, workspace_id, integration_type, partner_id, encrypted_access_token, created_at
+		FROM partner_accounts
+		WHERE workspace_id = $1
+	`
+
+	var account entity.PartnerAccount
+	var integrationType string
+
+	err := r.pool.QueryRow(ctx, query, workspaceID).Scan(
+		&account.ID,
+		&account.UserID,
+		&account.WorkspaceID,
+		&integrationType,
+		&account.PartnerID,
+		&account.EncryptedAccessToken,
+		&account.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPartnerAccountNotFound
+		}
+		return nil, err
+	}
+
+	account.IntegrationType = valueobject.IntegrationType(integrationType)
+	return &account, nil
+}
+
+func (r *PostgresPartnerAccountRepository) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PartnerAccount, error) {
+	query := `
+		SELECT id, user_id, workspace_id, integration_type, partner_id, encrypted_access_token, created_at
+		FROM partner_accounts
+		WHERE user_id = $1
+		   OR workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1)
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*entity.PartnerAccount
+	for rows.Next() {
+		var account entity.PartnerAccount
+		var integrationType string
+		if err := rows.Scan(
+			&account.ID,
+			&account.UserID,
+			&account.WorkspaceID,
+			&integrationType,
+			&account.PartnerID,
+			&account.EncryptedAccessToken,
+			&account.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		account.IntegrationType = valueobject.IntegrationType(integrationType)
+		accounts = append(accounts, &account)
+	}
+
+	return accounts, rows.Err()
+}
+
 func (r *PostgresPartnerAccountRepository) FindByPartnerID(ctx context.Context, partnerID string) (*entity.PartnerAccount, error) {
 	query := `
-		SELECT id, user_id, integration_type, partner_id, encrypted_access_token, created_at
+		SELECT id, user_id, workspace_id, integration_type, partner_id, encrypted_access_token, created_at
 		FROM partner_accounts
 		WHERE partner_id = $1
 	`
@@ -112,6 +183,7 @@ func (r *PostgresPartnerAccountRepository) FindByPartnerID(ctx context.Context,
 	err := r.pool.QueryRow(ctx, query, partnerID).Scan(
 		&account.ID,
 		&account.UserID,
+		&account.WorkspaceID,
 		&integrationType,
 		&account.PartnerID,
 		&account.EncryptedAccessToken,
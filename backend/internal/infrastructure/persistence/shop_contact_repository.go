@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+type PostgresShopContactRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresShopContactRepository(pool *pgxpool.Pool) *PostgresShopContactRepository {
+	return &PostgresShopContactRepository{pool: pool}
+}
+
+func (r *PostgresShopContactRepository) Upsert(ctx context.Context, contact *entity.ShopContact) error {
+	query := `
+		INSERT INTO shop_contacts (app_id, myshopify_domain, email, name, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (app_id, myshopify_domain) DO UPDATE SET
+			email = EXCLUDED.email,
+			name = EXCLUDED.name,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		contact.AppID,
+		contact.MyshopifyDomain,
+		contact.Email,
+		contact.Name,
+		contact.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresShopContactRepository) FindByDomain(ctx context.Context, appID uuid.UUID, domain string) (*entity.ShopContact, error) {
+	query := `
+		SELECT app_id, myshopify_domain, email, name, updated_at
+		FROM shop_contacts
+		WHERE app_id = $1 AND myshopify_domain = $2
+	`
+
+	var contact entity.ShopContact
+	err := r.pool.QueryRow(ctx, query, appID, domain).Scan(
+		&contact.AppID,
+		&contact.MyshopifyDomain,
+		&contact.Email,
+		&contact.Name,
+		&contact.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &contact, nil
+}
+
+func (r *PostgresShopContactRepository) Delete(ctx context.Context, appID uuid.UUID, domain string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM shop_contacts WHERE app_id = $1 AND myshopify_domain = $2`, appID, domain)
+	return err
+}
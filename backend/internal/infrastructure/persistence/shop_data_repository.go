@@ -0,0 +1,148 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+type PostgresShopDataRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresShopDataRepository(pool *pgxpool.Pool) *PostgresShopDataRepository {
+	return &PostgresShopDataRepository{pool: pool}
+}
+
+// UpdateShopDetails also rewrites the shop's transactions, since a ledger rebuild
+// takes subscription shop names from the latest transaction
+func (r *PostgresShopDataRepository) UpdateShopDetails(ctx context.Context, appIDs []uuid.UUID, myshopifyDomain, shopName, shopPlan string) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE subscriptions
+		SET shop_name = $3, updated_at = NOW()
+		WHERE app_id = ANY($1) AND myshopify_domain = $2
+		  AND shop_name IS DISTINCT FROM $3
+	`, appIDs, myshopifyDomain, shopName)
+	if err != nil {
+		return 0, err
+	}
+	updated := result.RowsAffected()
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE transactions
+		SET shop_name = $3, shop_plan = COALESCE(NULLIF($4, ''), shop_plan)
+		WHERE app_id = ANY($1) AND myshopify_domain = $2 AND redacted_at IS NULL
+	`, appIDs, myshopifyDomain, shopName, shopPlan); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+func (r *PostgresShopDataRepository) RedactShop(ctx context.Context, appIDs []uuid.UUID, appGID, myshopifyDomain, placeholderDomain string, redactedAt time.Time) (*repository.ShopRedaction, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var redaction repository.ShopRedaction
+
+	// Revenue stays in the ledger; only the shop's identity is removed
+	result, err := tx.Exec(ctx, `
+		UPDATE transactions
+		SET myshopify_domain = $3, shop_name = NULL, shopify_shop_gid = NULL,
+			shop_plan = NULL, redacted_at = $4
+		WHERE app_id = ANY($1) AND myshopify_domain = $2
+	`, appIDs, myshopifyDomain, placeholderDomain, redactedAt)
+	if err != nil {
+		return nil, err
+	}
+	redaction.TransactionsRedacted = result.RowsAffected()
+
+	if err := tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM subscription_events
+		WHERE subscription_id IN (
+			SELECT id FROM subscriptions WHERE app_id = ANY($1) AND myshopify_domain = $2
+		)
+	`, appIDs, myshopifyDomain).Scan(&redaction.EventsDeleted); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM api_usage_status
+		WHERE subscription_shopify_gid IN (
+			SELECT shopify_gid FROM api_subscription_status
+			WHERE app_id = ANY($1) AND myshopify_domain = $2
+		)
+	`, appIDs, myshopifyDomain); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM api_subscription_status
+		WHERE app_id = ANY($1) AND myshopify_domain = $2
+	`, appIDs, myshopifyDomain); err != nil {
+		return nil, err
+	}
+
+	// subscription_events are removed by ON DELETE CASCADE
+	result, err = tx.Exec(ctx, `
+		DELETE FROM subscriptions
+		WHERE app_id = ANY($1) AND myshopify_domain = $2
+	`, appIDs, myshopifyDomain)
+	if err != nil {
+		return nil, err
+	}
+	redaction.SubscriptionsDeleted = result.RowsAffected()
+
+	// The merchant email address dunning steps send to; dunning runs and their
+	// tasks are removed with the subscriptions by ON DELETE CASCADE
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM shop_contacts
+		WHERE app_id = ANY($1) AND myshopify_domain = $2
+	`, appIDs, myshopifyDomain); err != nil {
+		return nil, err
+	}
+
+	// Inbound webhooks received for the shop; shop/redact itself is kept for replay
+	if _, err := tx.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET shop_domain = $3, payload = '{}'::jsonb
+		WHERE app_gid = $1 AND shop_domain = $2 AND topic <> 'shop/redact'
+	`, appGID, myshopifyDomain, placeholderDomain); err != nil {
+		return nil, err
+	}
+
+	// Revenue API events sent to the app owner's endpoints about the shop
+	if _, err := tx.Exec(ctx, `
+		UPDATE api_webhook_deliveries
+		SET payload = jsonb_set(payload, '{data}', '{}'::jsonb)
+		WHERE payload->'data'->>'myshopify_domain' = $2
+		  AND endpoint_id IN (
+			SELECT e.id FROM api_webhook_endpoints e
+			JOIN partner_accounts p ON p.user_id = e.user_id
+			JOIN apps a ON a.partner_account_id = p.id
+			WHERE a.id = ANY($1)
+		  )
+	`, appIDs, myshopifyDomain); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &redaction, nil
+}
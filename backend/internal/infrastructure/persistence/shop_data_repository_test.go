@@ -0,0 +1,60 @@
+package persistence
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// shopTablesKeptByRedaction are tables with a shop domain column that RedactShop
+// does not touch itself
+var shopTablesKeptByRedaction = map[string]string{
+	"compliance_requests": "the record of the redaction itself",
+	"dunning_runs":        "removed with the shop's subscriptions by ON DELETE CASCADE",
+	"dunning_tasks":       "removed with their dunning run by ON DELETE CASCADE",
+}
+
+// TestRedactShop_CoversShopTables fails when a migration adds a table keyed by a
+// shop's domain that shop redaction does not delete or anonymize
+func TestRedactShop_CoversShopTables(t *testing.T) {
+	migrations, err := filepath.Glob("../../../migrations/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("no migrations found: %v", err)
+	}
+	source, err := os.ReadFile("shop_data_repository.go")
+	if err != nil {
+		t.Fatalf("failed to read repository: %v", err)
+	}
+	redactShop := string(source)[strings.Index(string(source), "func (r *PostgresShopDataRepository) RedactShop"):]
+
+	createTable := regexp.MustCompile(`(?s)CREATE TABLE (?:IF NOT EXISTS )?(\w+)\s*\((.*?)\n\);`)
+	shopColumn := regexp.MustCompile(`\b(myshopify_domain|shop_domain)\b`)
+
+	var tables []string
+	for _, path := range migrations {
+		sql, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		for _, m := range createTable.FindAllStringSubmatch(string(sql), -1) {
+			if shopColumn.MatchString(m[2]) {
+				tables = append(tables, m[1])
+			}
+		}
+	}
+	if len(tables) == 0 {
+		t.Fatal("expected tables with a shop domain column")
+	}
+
+	for _, table := range tables {
+		if _, ok := shopTablesKeptByRedaction[table]; ok {
+			continue
+		}
+		touched := regexp.MustCompile(`(DELETE FROM|UPDATE) ` + table + `\b`)
+		if !touched.MatchString(redactShop) {
+			t.Errorf("RedactShop does not delete or anonymize %s", table)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -150,6 +151,27 @@ func (r *PostgresSubscriptionRepository) FindByRiskState(ctx context.Context, ap
 	return r.scanSubscriptions(rows)
 }
 
+// FindUpdatedSince returns subscriptions updated after since, including soft-deleted ones
+func (r *PostgresSubscriptionRepository) FindUpdatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*entity.Subscription, error) {
+	query := `
+		SELECT id, app_id, shopify_gid, shopify_shop_gid, myshopify_domain, shop_name, plan_name,
+			base_price_cents, currency, billing_interval, status,
+			last_recurring_charge_date, expected_next_charge_date, risk_state,
+			created_at, updated_at, deleted_at
+		FROM subscriptions
+		WHERE app_id = $1 AND updated_at > $2
+		ORDER BY updated_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, appID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanSubscriptions(rows)
+}
+
 func (r *PostgresSubscriptionRepository) DeleteByAppID(ctx context.Context, appID uuid.UUID) error {
 	query := `DELETE FROM subscriptions WHERE app_id = $1`
 	_, err := r.pool.Exec(ctx, query, appID)
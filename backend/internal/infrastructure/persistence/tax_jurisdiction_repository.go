@@ -0,0 +1,123 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// ErrTaxProfileNotFound is returned when a tax profile does not exist for the partner account
+var ErrTaxProfileNotFound = errors.New("tax jurisdiction profile not found")
+
+type PostgresTaxJurisdictionProfileRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresTaxJurisdictionProfileRepository(pool *pgxpool.Pool) *PostgresTaxJurisdictionProfileRepository {
+	return &PostgresTaxJurisdictionProfileRepository{pool: pool}
+}
+
+func (r *PostgresTaxJurisdictionProfileRepository) Create(ctx context.Context, p *entity.TaxJurisdictionProfile) error {
+	query := `
+		INSERT INTO tax_jurisdiction_profiles (
+			id, partner_account_id, jurisdiction, tax_name, rate, registration_number,
+			effective_from, effective_to, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		p.ID,
+		p.PartnerAccountID,
+		p.Jurisdiction,
+		p.TaxName,
+		p.Rate,
+		p.RegistrationNumber,
+		p.EffectiveFrom,
+		p.EffectiveTo,
+		p.CreatedAt,
+		p.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresTaxJurisdictionProfileRepository) Update(ctx context.Context, p *entity.TaxJurisdictionProfile) error {
+	query := `
+		UPDATE tax_jurisdiction_profiles
+		SET jurisdiction = $3, tax_name = $4, rate = $5, registration_number = $6,
+		    effective_from = $7, effective_to = $8, updated_at = $9
+		WHERE id = $1 AND partner_account_id = $2
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		p.ID,
+		p.PartnerAccountID,
+		p.Jurisdiction,
+		p.TaxName,
+		p.Rate,
+		p.RegistrationNumber,
+		p.EffectiveFrom,
+		p.EffectiveTo,
+		p.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrTaxProfileNotFound
+	}
+	return nil
+}
+
+func (r *PostgresTaxJurisdictionProfileRepository) Delete(ctx context.Context, partnerAccountID, id uuid.UUID) error {
+	query := `DELETE FROM tax_jurisdiction_profiles WHERE id = $1 AND partner_account_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, id, partnerAccountID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrTaxProfileNotFound
+	}
+	return nil
+}
+
+func (r *PostgresTaxJurisdictionProfileRepository) FindByPartnerAccountID(ctx context.Context, partnerAccountID uuid.UUID) ([]*entity.TaxJurisdictionProfile, error) {
+	query := `
+		SELECT id, partner_account_id, jurisdiction, tax_name, rate, registration_number,
+		       effective_from, effective_to, created_at, updated_at
+		FROM tax_jurisdiction_profiles
+		WHERE partner_account_id = $1
+		ORDER BY effective_from
+	`
+
+	rows, err := r.pool.Query(ctx, query, partnerAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []*entity.TaxJurisdictionProfile
+	for rows.Next() {
+		var p entity.TaxJurisdictionProfile
+		if err := rows.Scan(
+			&p.ID,
+			&p.PartnerAccountID,
+			&p.Jurisdiction,
+			&p.TaxName,
+			&p.Rate,
+			&p.RegistrationNumber,
+			&p.EffectiveFrom,
+			&p.EffectiveTo,
+			&p.CreatedAt,
+			&p.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, &p)
+	}
+
+	return profiles, rows.Err()
+}
@@ -31,11 +31,11 @@ func (r *PostgresTransactionRepository) Upsert(ctx context.Context, tx *entity.T
 			net_amount_cents, amount_cents, currency, transaction_date, created_at,
 			created_date, available_date, earnings_status,
 			shopify_shop_gid, shop_plan, subscription_gid, subscription_status,
-			subscription_period_end, billing_interval
+			subscription_period_end, billing_interval, charge_gid
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, NULLIF($25, ''))
 		ON CONFLICT (shopify_gid) DO UPDATE SET
-			shop_name = EXCLUDED.shop_name,
+			shop_name = CASE WHEN transactions.redacted_at IS NULL THEN EXCLUDED.shop_name END,
 			charge_type = EXCLUDED.charge_type,
 			gross_amount_cents = EXCLUDED.gross_amount_cents,
 			shopify_fee_cents = EXCLUDED.shopify_fee_cents,
@@ -47,12 +47,13 @@ func (r *PostgresTransactionRepository) Upsert(ctx context.Context, tx *entity.T
 			created_date = EXCLUDED.created_date,
 			available_date = EXCLUDED.available_date,
 			earnings_status = EXCLUDED.earnings_status,
-			shopify_shop_gid = EXCLUDED.shopify_shop_gid,
-			shop_plan = EXCLUDED.shop_plan,
+			shopify_shop_gid = CASE WHEN transactions.redacted_at IS NULL THEN EXCLUDED.shopify_shop_gid END,
+			shop_plan = CASE WHEN transactions.redacted_at IS NULL THEN EXCLUDED.shop_plan END,
 			subscription_gid = EXCLUDED.subscription_gid,
 			subscription_status = EXCLUDED.subscription_status,
 			subscription_period_end = EXCLUDED.subscription_period_end,
-			billing_interval = EXCLUDED.billing_interval
+			billing_interval = EXCLUDED.billing_interval,
+			charge_gid = COALESCE(EXCLUDED.charge_gid, transactions.charge_gid)
 	`
 
 	_, err := r.pool.Exec(ctx, query,
@@ -80,6 +81,7 @@ func (r *PostgresTransactionRepository) Upsert(ctx context.Context, tx *entity.T
 		tx.SubscriptionStatus,
 		tx.SubscriptionPeriodEnd,
 		tx.BillingInterval,
+		tx.ChargeGID,
 	)
 
 	return err
@@ -98,11 +100,11 @@ func (r *PostgresTransactionRepository) UpsertBatch(ctx context.Context, txs []*
 			net_amount_cents, amount_cents, currency, transaction_date, created_at,
 			created_date, available_date, earnings_status,
 			shopify_shop_gid, shop_plan, subscription_gid, subscription_status,
-			subscription_period_end, billing_interval
+			subscription_period_end, billing_interval, charge_gid
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, NULLIF($25, ''))
 		ON CONFLICT (shopify_gid) DO UPDATE SET
-			shop_name = EXCLUDED.shop_name,
+			shop_name = CASE WHEN transactions.redacted_at IS NULL THEN EXCLUDED.shop_name END,
 			charge_type = EXCLUDED.charge_type,
 			gross_amount_cents = EXCLUDED.gross_amount_cents,
 			shopify_fee_cents = EXCLUDED.shopify_fee_cents,
@@ -114,12 +116,13 @@ func (r *PostgresTransactionRepository) UpsertBatch(ctx context.Context, txs []*
 			created_date = EXCLUDED.created_date,
 			available_date = EXCLUDED.available_date,
 			earnings_status = EXCLUDED.earnings_status,
-			shopify_shop_gid = EXCLUDED.shopify_shop_gid,
-			shop_plan = EXCLUDED.shop_plan,
+			shopify_shop_gid = CASE WHEN transactions.redacted_at IS NULL THEN EXCLUDED.shopify_shop_gid END,
+			shop_plan = CASE WHEN transactions.redacted_at IS NULL THEN EXCLUDED.shop_plan END,
 			subscription_gid = EXCLUDED.subscription_gid,
 			subscription_status = EXCLUDED.subscription_status,
 			subscription_period_end = EXCLUDED.subscription_period_end,
-			billing_interval = EXCLUDED.billing_interval
+			billing_interval = EXCLUDED.billing_interval,
+			charge_gid = COALESCE(EXCLUDED.charge_gid, transactions.charge_gid)
 	`
 
 	for _, tx := range txs {
@@ -148,6 +151,7 @@ func (r *PostgresTransactionRepository) UpsertBatch(ctx context.Context, txs []*
 			tx.SubscriptionStatus,
 			tx.SubscriptionPeriodEnd,
 			tx.BillingInterval,
+			tx.ChargeGID,
 		)
 	}
 
@@ -171,7 +175,7 @@ func (r *PostgresTransactionRepository) FindByAppID(ctx context.Context, appID u
 		       COALESCE(net_amount_cents, amount_cents), currency, transaction_date, created_at,
 		       created_date, available_date, earnings_status,
 		       shopify_shop_gid, shop_plan, subscription_gid, subscription_status,
-		       subscription_period_end, billing_interval
+		       subscription_period_end, billing_interval, charge_gid
 		FROM transactions
 		WHERE app_id = $1 AND transaction_date >= $2 AND transaction_date <= $3
 		ORDER BY transaction_date DESC
@@ -199,7 +203,7 @@ func (r *PostgresTransactionRepository) FindByAppID(ctx context.Context, appID u
 func (r *PostgresTransactionRepository) scanTransaction(rows pgx.Rows) (*entity.Transaction, error) {
 	var tx entity.Transaction
 	var chargeType string
-	var shopName, shopifyShopGID, shopPlan, subscriptionGID, subscriptionStatus, billingInterval *string
+	var shopName, shopifyShopGID, shopPlan, subscriptionGID, subscriptionStatus, billingInterval, chargeGID *string
 	var earningsStatus string
 	var subscriptionPeriodEnd *time.Time
 
@@ -227,6 +231,7 @@ func (r *PostgresTransactionRepository) scanTransaction(rows pgx.Rows) (*entity.
 		&subscriptionStatus,
 		&subscriptionPeriodEnd,
 		&billingInterval,
+		&chargeGID,
 	)
 	if err != nil {
 		return nil, err
@@ -253,10 +258,46 @@ func (r *PostgresTransactionRepository) scanTransaction(rows pgx.Rows) (*entity.
 	if billingInterval != nil {
 		tx.BillingInterval = *billingInterval
 	}
+	if chargeGID != nil {
+		tx.ChargeGID = *chargeGID
+	}
 
 	return &tx, nil
 }
 
+// FindCreatedSince returns transactions first stored after since, oldest first
+func (r *PostgresTransactionRepository) FindCreatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*entity.Transaction, error) {
+	query := `
+		SELECT id, app_id, shopify_gid, myshopify_domain, shop_name, charge_type,
+		       COALESCE(gross_amount_cents, 0), COALESCE(shopify_fee_cents, 0),
+		       COALESCE(processing_fee_cents, 0), COALESCE(tax_on_fees_cents, 0),
+		       COALESCE(net_amount_cents, amount_cents), currency, transaction_date, created_at,
+		       created_date, available_date, earnings_status,
+		       shopify_shop_gid, shop_plan, subscription_gid, subscription_status,
+		       subscription_period_end, billing_interval, charge_gid
+		FROM transactions
+		WHERE app_id = $1 AND created_at > $2
+		ORDER BY created_at, transaction_date
+	`
+
+	rows, err := r.pool.Query(ctx, query, appID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*entity.Transaction
+	for rows.Next() {
+		tx, err := r.scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, rows.Err()
+}
+
 func (r *PostgresTransactionRepository) FindByShopifyGID(ctx context.Context, shopifyGID string) (*entity.Transaction, error) {
 	query := `
 		SELECT id, app_id, shopify_gid, myshopify_domain, shop_name, charge_type,
@@ -265,14 +306,14 @@ func (r *PostgresTransactionRepository) FindByShopifyGID(ctx context.Context, sh
 		       COALESCE(net_amount_cents, amount_cents), currency, transaction_date, created_at,
 		       created_date, available_date, earnings_status,
 		       shopify_shop_gid, shop_plan, subscription_gid, subscription_status,
-		       subscription_period_end, billing_interval
+		       subscription_period_end, billing_interval, charge_gid
 		FROM transactions
 		WHERE shopify_gid = $1
 	`
 
 	var tx entity.Transaction
 	var chargeType string
-	var shopName, shopifyShopGID, shopPlan, subscriptionGID, subscriptionStatus, billingInterval *string
+	var shopName, shopifyShopGID, shopPlan, subscriptionGID, subscriptionStatus, billingInterval, chargeGID *string
 	var earningsStatus string
 	var subscriptionPeriodEnd *time.Time
 
@@ -300,6 +341,7 @@ func (r *PostgresTransactionRepository) FindByShopifyGID(ctx context.Context, sh
 		&subscriptionStatus,
 		&subscriptionPeriodEnd,
 		&billingInterval,
+		&chargeGID,
 	)
 
 	if err != nil {
@@ -330,6 +372,9 @@ func (r *PostgresTransactionRepository) FindByShopifyGID(ctx context.Context, sh
 	if billingInterval != nil {
 		tx.BillingInterval = *billingInterval
 	}
+	if chargeGID != nil {
+		tx.ChargeGID = *chargeGID
+	}
 	return &tx, nil
 }
 
@@ -428,7 +473,7 @@ func (r *PostgresTransactionRepository) FindByDomain(ctx context.Context, appID
 		       COALESCE(net_amount_cents, amount_cents), currency, transaction_date, created_at,
 		       created_date, available_date, earnings_status,
 		       shopify_shop_gid, shop_plan, subscription_gid, subscription_status,
-		       subscription_period_end, billing_interval
+		       subscription_period_end, billing_interval, charge_gid
 		FROM transactions
 		WHERE app_id = $1 AND myshopify_domain = $2 AND transaction_date >= $3 AND transaction_date <= $4
 		ORDER BY transaction_date DESC
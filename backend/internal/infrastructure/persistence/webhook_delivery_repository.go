@@ -0,0 +1,223 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+// ErrWebhookDeliveryNotFound is returned when an inbound webhook delivery does not exist
+var ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+type PostgresWebhookDeliveryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresWebhookDeliveryRepository(pool *pgxpool.Pool) *PostgresWebhookDeliveryRepository {
+	return &PostgresWebhookDeliveryRepository{pool: pool}
+}
+
+const webhookDeliveryColumns = `id, webhook_id, topic, shop_domain, app_gid, resource_key, payload, triggered_at,
+	status, attempts, next_attempt_at, last_error, received_at, processed_at`
+
+func (r *PostgresWebhookDeliveryRepository) Create(ctx context.Context, d *entity.WebhookDelivery) (bool, error) {
+	query := `
+		INSERT INTO webhook_deliveries (` + webhookDeliveryColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (webhook_id) DO NOTHING
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		d.ID,
+		d.WebhookID,
+		d.Topic,
+		d.ShopDomain,
+		d.AppGID,
+		d.ResourceKey,
+		d.Payload,
+		d.TriggeredAt,
+		string(d.Status),
+		d.Attempts,
+		d.NextAttemptAt,
+		d.LastError,
+		d.ReceivedAt,
+		d.ProcessedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	deliveries, err := r.query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return deliveries[0], nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) List(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]*entity.WebhookDelivery, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.AppGID != "" {
+		args = append(args, filter.AppGID)
+		conditions = append(conditions, fmt.Sprintf("app_gid = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Topic != "" {
+		args = append(args, filter.Topic)
+		conditions = append(conditions, fmt.Sprintf("topic = $%d", len(args)))
+	}
+
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(` ORDER BY received_at DESC LIMIT $%d`, len(args))
+
+	return r.query(ctx, query, args...)
+}
+
+// ClaimDue leases up to limit due pending deliveries. SKIP LOCKED keeps
+// concurrent workers from claiming the same rows.
+func (r *PostgresWebhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET locked_until = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'PENDING' AND next_attempt_at <= $1
+			  AND (locked_until IS NULL OR locked_until <= $1)
+			ORDER BY triggered_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+
+	deliveries, err := r.query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not preserve the subquery order
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].TriggeredAt.Before(deliveries[j].TriggeredAt)
+	})
+	return deliveries, nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) Claim(ctx context.Context, id uuid.UUID, now time.Time, lease time.Duration) (bool, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET locked_until = $3
+		WHERE id = $1 AND (locked_until IS NULL OR locked_until <= $2)
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, now, now.Add(lease))
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) Update(ctx context.Context, d *entity.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5,
+			processed_at = $6, locked_until = NULL
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		d.ID,
+		string(d.Status),
+		d.Attempts,
+		d.NextAttemptAt,
+		d.LastError,
+		d.ProcessedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWebhookDeliveryNotFound
+	}
+	return nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) HasNewerProcessed(ctx context.Context, topic, resourceKey string, triggeredAt time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM webhook_deliveries
+			WHERE topic = $1 AND resource_key = $2 AND status = 'PROCESSED' AND triggered_at > $3
+		)
+	`
+
+	var exists bool
+	err := r.pool.QueryRow(ctx, query, topic, resourceKey, triggeredAt).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgresWebhookDeliveryRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.WebhookDelivery, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*entity.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func scanWebhookDelivery(row pgx.Row) (*entity.WebhookDelivery, error) {
+	var d entity.WebhookDelivery
+	var status string
+	if err := row.Scan(
+		&d.ID,
+		&d.WebhookID,
+		&d.Topic,
+		&d.ShopDomain,
+		&d.AppGID,
+		&d.ResourceKey,
+		&d.Payload,
+		&d.TriggeredAt,
+		&status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.LastError,
+		&d.ReceivedAt,
+		&d.ProcessedAt,
+	); err != nil {
+		return nil, err
+	}
+	d.Status = entity.WebhookDeliveryStatus(status)
+	return &d, nil
+}
@@ -0,0 +1,136 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// ErrWebhookSecretNotFound is returned when an app has no such unrevoked webhook secret
+var ErrWebhookSecretNotFound = errors.New("webhook secret not found")
+
+type PostgresWebhookSecretRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresWebhookSecretRepository(pool *pgxpool.Pool) *PostgresWebhookSecretRepository {
+	return &PostgresWebhookSecretRepository{pool: pool}
+}
+
+const webhookSecretColumns = `id, app_id, label, encrypted_secret, secret_hint, expires_at, last_used_at, created_at, revoked_at`
+
+func (r *PostgresWebhookSecretRepository) Create(ctx context.Context, s *entity.WebhookSecret) error {
+	query := `
+		INSERT INTO shopify_webhook_secrets (` + webhookSecretColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		s.ID,
+		s.AppID,
+		s.Label,
+		s.EncryptedSecret,
+		s.SecretHint,
+		s.ExpiresAt,
+		s.LastUsedAt,
+		s.CreatedAt,
+		s.RevokedAt,
+	)
+	return err
+}
+
+func (r *PostgresWebhookSecretRepository) FindByAppID(ctx context.Context, appID uuid.UUID) ([]*entity.WebhookSecret, error) {
+	query := `
+		SELECT ` + webhookSecretColumns + `
+		FROM shopify_webhook_secrets
+		WHERE app_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, appID)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhookSecrets(rows)
+}
+
+func (r *PostgresWebhookSecretRepository) FindActiveByAppIDs(ctx context.Context, appIDs []uuid.UUID, now time.Time) ([]*entity.WebhookSecret, error) {
+	query := `
+		SELECT ` + webhookSecretColumns + `
+		FROM shopify_webhook_secrets
+		WHERE app_id = ANY($1) AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, appIDs, now)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhookSecrets(rows)
+}
+
+func (r *PostgresWebhookSecretRepository) ExpireOthers(ctx context.Context, appID, keepID uuid.UUID, expiresAt time.Time) error {
+	query := `
+		UPDATE shopify_webhook_secrets
+		SET expires_at = $3
+		WHERE app_id = $1 AND id <> $2 AND revoked_at IS NULL
+		  AND (expires_at IS NULL OR expires_at > $3)
+	`
+
+	_, err := r.pool.Exec(ctx, query, appID, keepID, expiresAt)
+	return err
+}
+
+func (r *PostgresWebhookSecretRepository) Revoke(ctx context.Context, appID, id uuid.UUID, revokedAt time.Time) error {
+	query := `
+		UPDATE shopify_webhook_secrets
+		SET revoked_at = $3
+		WHERE id = $1 AND app_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, appID, revokedAt)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWebhookSecretNotFound
+	}
+	return nil
+}
+
+func (r *PostgresWebhookSecretRepository) MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	query := `UPDATE shopify_webhook_secrets SET last_used_at = $2 WHERE id = $1`
+
+	_, err := r.pool.Exec(ctx, query, id, usedAt)
+	return err
+}
+
+func scanWebhookSecrets(rows pgx.Rows) ([]*entity.WebhookSecret, error) {
+	defer rows.Close()
+
+	var secrets []*entity.WebhookSecret
+	for rows.Next() {
+		var s entity.WebhookSecret
+		if err := rows.Scan(
+			&s.ID,
+			&s.AppID,
+			&s.Label,
+			&s.EncryptedSecret,
+			&s.SecretHint,
+			&s.ExpiresAt,
+			&s.LastUsedAt,
+			&s.CreatedAt,
+			&s.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, &s)
+	}
+
+	return secrets, rows.Err()
+}
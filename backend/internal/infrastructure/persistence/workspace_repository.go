@@ -0,0 +1,420 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+var (
+	// ErrWorkspaceNotFound is returned when a workspace does not exist
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	// ErrWorkspaceMemberNotFound is returned when a user is not a member of the workspace
+	ErrWorkspaceMemberNotFound = errors.New("workspace member not found")
+	// ErrWorkspaceInvitationNotFound is returned when an invitation does not exist
+	ErrWorkspaceInvitationNotFound = errors.New("workspace invitation not found")
+)
+
+type PostgresWorkspaceRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresWorkspaceRepository(pool *pgxpool.Pool) *PostgresWorkspaceRepository {
+	return &PostgresWorkspaceRepository{pool: pool}
+}
+
+const workspaceColumns = `id, name, created_by, created_at, updated_at`
+
+func (r *PostgresWorkspaceRepository) Create(ctx context.Context, workspace *entity.Workspace, owner *entity.WorkspaceMember) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO workspaces (` + workspaceColumns + `)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := tx.Exec(ctx, query,
+		workspace.ID,
+		workspace.Name,
+		workspace.CreatedBy,
+		workspace.CreatedAt,
+		workspace.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	if err := insertWorkspaceMember(ctx, tx, owner); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresWorkspaceRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Workspace, error) {
+	workspace, err := scanWorkspace(r.pool.QueryRow(ctx, `SELECT `+workspaceColumns+` FROM workspaces WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+	return workspace, nil
+}
+
+func (r *PostgresWorkspaceRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Workspace, error) {
+	query := `
+		SELECT w.id, w.name, w.created_by, w.created_at, w.updated_at
+		FROM workspaces w
+		JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = $1
+		ORDER BY m.created_at, w.id
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workspaces []*entity.Workspace
+	for rows.Next() {
+		workspace, err := scanWorkspace(rows)
+		if err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, workspace)
+	}
+
+	return workspaces, rows.Err()
+}
+
+func (r *PostgresWorkspaceRepository) Update(ctx context.Context, workspace *entity.Workspace) error {
+	result, err := r.pool.Exec(ctx,
+		`UPDATE workspaces SET name = $2, updated_at = $3 WHERE id = $1`,
+		workspace.ID, workspace.Name, workspace.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWorkspaceNotFound
+	}
+	return nil
+}
+
+func scanWorkspace(row pgx.Row) (*entity.Workspace, error) {
+	var workspace entity.Workspace
+	var createdBy *uuid.UUID
+	if err := row.Scan(
+		&workspace.ID,
+		&workspace.Name,
+		&createdBy,
+		&workspace.CreatedAt,
+		&workspace.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if createdBy != nil {
+		workspace.CreatedBy = *createdBy
+	}
+	return &workspace, nil
+}
+
+type PostgresWorkspaceMemberRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresWorkspaceMemberRepository(pool *pgxpool.Pool) *PostgresWorkspaceMemberRepository {
+	return &PostgresWorkspaceMemberRepository{pool: pool}
+}
+
+const workspaceMemberColumns = `workspace_id, user_id, email, role, created_at, updated_at`
+
+func (r *PostgresWorkspaceMemberRepository) Create(ctx context.Context, member *entity.WorkspaceMember) error {
+	return insertWorkspaceMember(ctx, r.pool, member)
+}
+
+// Find returns nil, nil when the user is not a member of the workspace
+func (r *PostgresWorkspaceMemberRepository) Find(ctx context.Context, workspaceID, userID uuid.UUID) (*entity.WorkspaceMember, error) {
+	query := `SELECT ` + workspaceMemberColumns + ` FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`
+
+	member, err := scanWorkspaceMember(r.pool.QueryRow(ctx, query, workspaceID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func (r *PostgresWorkspaceMemberRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WorkspaceMember, error) {
+	return r.findMany(ctx, `SELECT `+workspaceMemberColumns+` FROM workspace_members WHERE user_id = $1 ORDER BY created_at, workspace_id`, userID)
+}
+
+func (r *PostgresWorkspaceMemberRepository) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]*entity.WorkspaceMember, error) {
+	return r.findMany(ctx, `SELECT `+workspaceMemberColumns+` FROM workspace_members WHERE workspace_id = $1 ORDER BY created_at, user_id`, workspaceID)
+}
+
+func (r *PostgresWorkspaceMemberRepository) CountByRole(ctx context.Context, workspaceID uuid.UUID, role valueobject.Role) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM workspace_members WHERE workspace_id = $1 AND role = $2`,
+		workspaceID, role.String(),
+	).Scan(&count)
+	return count, err
+}
+
+func (r *PostgresWorkspaceMemberRepository) Update(ctx context.Context, member *entity.WorkspaceMember) error {
+	result, err := r.pool.Exec(ctx,
+		`UPDATE workspace_members SET role = $3, updated_at = $4 WHERE workspace_id = $1 AND user_id = $2`,
+		member.WorkspaceID, member.UserID, member.Role.String(), member.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWorkspaceMemberNotFound
+	}
+	return nil
+}
+
+func (r *PostgresWorkspaceMemberRepository) Delete(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWorkspaceMemberNotFound
+	}
+	return nil
+}
+
+func (r *PostgresWorkspaceMemberRepository) findMany(ctx context.Context, query string, arg interface{}) ([]*entity.WorkspaceMember, error) {
+	rows, err := r.pool.Query(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*entity.WorkspaceMember
+	for rows.Next() {
+		member, err := scanWorkspaceMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+func insertWorkspaceMember(ctx context.Context, db execer, member *entity.WorkspaceMember) error {
+	query := `
+		INSERT INTO workspace_members (` + workspaceMemberColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := db.Exec(ctx, query,
+		member.WorkspaceID,
+		member.UserID,
+		member.Email,
+		member.Role.String(),
+		member.CreatedAt,
+		member.UpdatedAt,
+	)
+	return err
+}
+
+func scanWorkspaceMember(row pgx.Row) (*entity.WorkspaceMember, error) {
+	var member entity.WorkspaceMember
+	var role string
+	if err := row.Scan(
+		&member.WorkspaceID,
+		&member.UserID,
+		&member.Email,
+		&role,
+		&member.CreatedAt,
+		&member.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	member.Role = valueobject.Role(role)
+	return &member, nil
+}
+
+type PostgresWorkspaceInvitationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresWorkspaceInvitationRepository(pool *pgxpool.Pool) *PostgresWorkspaceInvitationRepository {
+	return &PostgresWorkspaceInvitationRepository{pool: pool}
+}
+
+const workspaceInvitationColumns = `id, workspace_id, email, role, token_hash, invited_by, expires_at,
+	accepted_at, accepted_by, revoked_at, created_at`
+
+func (r *PostgresWorkspaceInvitationRepository) Create(ctx context.Context, invitation *entity.WorkspaceInvitation) error {
+	query := `
+		INSERT INTO workspace_invitations (` + workspaceInvitationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		invitation.ID,
+		invitation.WorkspaceID,
+		invitation.Email,
+		invitation.Role.String(),
+		nullableString(invitation.TokenHash),
+		invitation.InvitedBy,
+		invitation.ExpiresAt,
+		invitation.AcceptedAt,
+		invitation.AcceptedBy,
+		invitation.RevokedAt,
+		invitation.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresWorkspaceInvitationRepository) FindByID(ctx context.Context, workspaceID, id uuid.UUID) (*entity.WorkspaceInvitation, error) {
+	query := `SELECT ` + workspaceInvitationColumns + ` FROM workspace_invitations WHERE id = $1 AND workspace_id = $2`
+
+	invitation, err := scanWorkspaceInvitation(r.pool.QueryRow(ctx, query, id, workspaceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWorkspaceInvitationNotFound
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (r *PostgresWorkspaceInvitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.WorkspaceInvitation, error) {
+	query := `SELECT ` + workspaceInvitationColumns + ` FROM workspace_invitations WHERE token_hash = $1`
+
+	invitation, err := scanWorkspaceInvitation(r.pool.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWorkspaceInvitationNotFound
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (r *PostgresWorkspaceInvitationRepository) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]*entity.WorkspaceInvitation, error) {
+	query := `SELECT ` + workspaceInvitationColumns + ` FROM workspace_invitations WHERE workspace_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.pool.Query(ctx, query, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []*entity.WorkspaceInvitation
+	for rows.Next() {
+		invitation, err := scanWorkspaceInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+func (r *PostgresWorkspaceInvitationRepository) Update(ctx context.Context, invitation *entity.WorkspaceInvitation) error {
+	query := `
+		UPDATE workspace_invitations
+		SET token_hash = $2, accepted_at = $3, accepted_by = $4, revoked_at = $5
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		invitation.ID,
+		nullableString(invitation.TokenHash),
+		invitation.AcceptedAt,
+		invitation.AcceptedBy,
+		invitation.RevokedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWorkspaceInvitationNotFound
+	}
+	return nil
+}
+
+func (r *PostgresWorkspaceInvitationRepository) Accept(ctx context.Context, invitation *entity.WorkspaceInvitation, member *entity.WorkspaceMember) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Only an open invitation can be accepted, so two accepts cannot both add a member
+	result, err := tx.Exec(ctx, `
+		UPDATE workspace_invitations
+		SET token_hash = $2, accepted_at = $3, accepted_by = $4
+		WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL
+	`,
+		invitation.ID,
+		nullableString(invitation.TokenHash),
+		invitation.AcceptedAt,
+		invitation.AcceptedBy,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWorkspaceInvitationNotFound
+	}
+
+	if err := insertWorkspaceMember(ctx, tx, member); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func scanWorkspaceInvitation(row pgx.Row) (*entity.WorkspaceInvitation, error) {
+	var invitation entity.WorkspaceInvitation
+	var role string
+	var tokenHash *string
+	var invitedBy *uuid.UUID
+	if err := row.Scan(
+		&invitation.ID,
+		&invitation.WorkspaceID,
+		&invitation.Email,
+		&role,
+		&tokenHash,
+		&invitedBy,
+		&invitation.ExpiresAt,
+		&invitation.AcceptedAt,
+		&invitation.AcceptedBy,
+		&invitation.RevokedAt,
+		&invitation.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	invitation.Role = valueobject.Role(role)
+	if tokenHash != nil {
+		invitation.TokenHash = *tokenHash
+	}
+	if invitedBy != nil {
+		invitation.InvitedBy = *invitedBy
+	}
+	return &invitation, nil
+}
@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// accountingAppGIDPrefix is the Shopify Partner App GID prefix
+const accountingAppGIDPrefix = "gid://partners/App/"
+
+// AccountingExportHandler handles QuickBooks / Xero journal exports and GL mapping
+type AccountingExportHandler struct {
+	accountingService *service.AccountingExportService
+	auditService      *service.AuditService
+	partnerRepo       repository.PartnerAccountRepository
+	appRepo           repository.AppRepository
+}
+
+// NewAccountingExportHandler creates a new AccountingExportHandler
+func NewAccountingExportHandler(
+	accountingService *service.AccountingExportService,
+	partnerRepo repository.PartnerAccountRepository,
+	appRepo repository.AppRepository,
+) *AccountingExportHandler {
+	return &AccountingExportHandler{
+		accountingService: accountingService,
+		partnerRepo:       partnerRepo,
+		appRepo:           appRepo,
+	}
+}
+
+// WithAuditService enables audit logging of export requests
+func (h *AccountingExportHandler) WithAuditService(auditService *service.AuditService) *AccountingExportHandler {
+	h.auditService = auditService
+	return h
+}
+
+// GLMappingRequest is the request/response body for the GL account mapping
+type GLMappingRequest struct {
+	RecurringRevenueAccount string `json:"recurring_revenue_account"`
+	UsageRevenueAccount     string `json:"usage_revenue_account"`
+	OneTimeRevenueAccount   string `json:"one_time_revenue_account"`
+	RefundAccount           string `json:"refund_account"`
+	RevenueShareFeeAccount  string `json:"revenue_share_fee_account"`
+	ProcessingFeeAccount    string `json:"processing_fee_account"`
+	TaxOnFeesAccount        string `json:"tax_on_fees_account"`
+	ClearingAccount         string `json:"clearing_account"`
+	RoundingAccount         string `json:"rounding_account"`
+	XeroTaxRate             string `json:"xero_tax_rate"`
+}
+
+// AccountingExportRequestBody is the request body for creating a journal export
+type AccountingExportRequestBody struct {
+	Format      string `json:"format"`      // quickbooks_iif | quickbooks_csv | xero_csv
+	Granularity string `json:"granularity"` // day | month
+	Start       string `json:"start"`       // YYYY-MM-DD
+	End         string `json:"end"`         // YYYY-MM-DD (inclusive)
+	Confirm     bool   `json:"confirm"`     // Re-export a period that was already exported
+}
+
+// AccountingExportRunResponse represents an export run in API responses
+type AccountingExportRunResponse struct {
+	ID               string `json:"id"`
+	Format           string `json:"format"`
+	Granularity      string `json:"granularity"`
+	PeriodStart      string `json:"period_start"`
+	PeriodEnd        string `json:"period_end"`
+	JournalCount     int    `json:"journal_count"`
+	TransactionCount int    `json:"transaction_count"`
+	TotalDebitCents  int64  `json:"total_debit_cents"`
+	Confirmed        bool   `json:"confirmed"`
+	CreatedAt        string `json:"created_at"`
+}
+
+// GetMapping handles GET /api/v1/apps/{appID}/accounting/gl-mapping
+func (h *AccountingExportHandler) GetMapping(w http.ResponseWriter, r *http.Request) {
+	app, herr := h.getAppFromRequest(r, requiredWorkspaceRole(r))
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	mapping, err := h.accountingService.GetMapping(r.Context(), app.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch GL mapping")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toGLMappingResponse(mapping))
+}
+
+// UpdateMapping handles PUT /api/v1/apps/{appID}/accounting/gl-mapping
+func (h *AccountingExportHandler) UpdateMapping(w http.ResponseWriter, r *http.Request) {
+	app, herr := h.getAppFromRequest(r, requiredWorkspaceRole(r))
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	var req GLMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	mapping, err := h.accountingService.GetMapping(r.Context(), app.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch GL mapping")
+		return
+	}
+
+	mapping.RecurringRevenueAccount = req.RecurringRevenueAccount
+	mapping.UsageRevenueAccount = req.UsageRevenueAccount
+	mapping.OneTimeRevenueAccount = req.OneTimeRevenueAccount
+	mapping.RefundAccount = req.RefundAccount
+	mapping.RevenueShareFeeAccount = req.RevenueShareFeeAccount
+	mapping.ProcessingFeeAccount = req.ProcessingFeeAccount
+	mapping.TaxOnFeesAccount = req.TaxOnFeesAccount
+	mapping.ClearingAccount = req.ClearingAccount
+	mapping.RoundingAccount = req.RoundingAccount
+	if req.XeroTaxRate != "" {
+		mapping.XeroTaxRate = req.XeroTaxRate
+	}
+
+	if err := h.accountingService.SaveMapping(r.Context(), mapping); err != nil {
+		if errors.Is(err, entity.ErrGLAccountRequired) {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSONError(w, http.StatusInternalServerError, "failed to save GL mapping")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toGLMappingResponse(mapping))
+}
+
+// ListRuns handles GET /api/v1/apps/{appID}/accounting/exports
+func (h *AccountingExportHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	app, herr := h.getAppFromRequest(r, requiredWorkspaceRole(r))
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	runs, err := h.accountingService.ListRuns(r.Context(), app.ID, 100)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch export history")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"exports": toExportRunResponses(runs),
+	})
+}
+
+// Export handles POST /api/v1/apps/{appID}/accounting/exports
+// Returns the journal file as an attachment. Responds 409 with the previous runs when
+// the period was already exported and "confirm" is not set.
+func (h *AccountingExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	app, herr := h.getAppFromRequest(r, valueobject.RoleAnalyst)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+	user := middleware.UserFromContext(ctx)
+
+	var body AccountingExportRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if body.Start == "" || body.End == "" {
+		writeJSONError(w, http.StatusBadRequest, "start and end dates are required (YYYY-MM-DD)")
+		return
+	}
+	start, err := time.Parse("2006-01-02", body.Start)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid start date format (expected YYYY-MM-DD)")
+		return
+	}
+	end, err := time.Parse("2006-01-02", body.End)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid end date format (expected YYYY-MM-DD)")
+		return
+	}
+
+	granularity := entity.JournalGranularityMonth
+	if body.Granularity != "" {
+		granularity = entity.JournalGranularity(body.Granularity)
+	}
+
+	req := service.AccountingExportRequest{
+		AppID:       app.ID,
+		UserID:      &user.ID,
+		Format:      entity.AccountingFormat(body.Format),
+		Granularity: granularity,
+		From:        start,
+		To:          end,
+		Confirm:     body.Confirm,
+	}
+
+	if h.auditService != nil {
+		h.auditService.LogExportRequest(ctx, user.ID, string(req.Format), &app.ID, r.RemoteAddr, r.UserAgent())
+	}
+
+	result, _, err := h.accountingService.Export(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPeriodAlreadyExported):
+			previous, _ := h.accountingService.FindOverlappingRuns(ctx, app.ID, start, end)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]interface{}{
+					"code":    http.StatusText(http.StatusConflict),
+					"message": "this period has already been exported; resend with \"confirm\": true to export it again",
+				},
+				"previous_exports": toExportRunResponses(previous),
+			})
+		case errors.Is(err, service.ErrInvalidAccountingFormat),
+			errors.Is(err, service.ErrInvalidJournalGranularity),
+			errors.Is(err, service.ErrInvalidDateRange):
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+		default:
+			writeJSONError(w, http.StatusInternalServerError, "failed to export journals")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", result.ContentType)
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+result.Filename+"\"")
+	w.Header().Set("X-Record-Count", formatInt(result.RecordCount))
+	w.Write(result.Data)
+}
+
+// getAppFromRequest resolves the app from the numeric Shopify app ID in the URL
+func (h *AccountingExportHandler) getAppFromRequest(r *http.Request, minRole valueobject.Role) (*entity.App, *subHandlerError) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		return nil, &subHandlerError{statusCode: http.StatusUnauthorized, message: "authentication required"}
+	}
+
+	partnerAccount, err := partnerAccountForRole(r.Context(), h.partnerRepo, user.ID, minRole)
+	if err != nil {
+		return nil, partnerAccountError(err, http.StatusNotFound, "no partner account found")
+	}
+
+	appIDStr := chi.URLParam(r, "appID")
+	if appIDStr == "" {
+		return nil, &subHandlerError{statusCode: http.StatusBadRequest, message: "app ID is required"}
+	}
+
+	app, err := h.appRepo.FindByPartnerAppID(r.Context(), partnerAccount.ID, accountingAppGIDPrefix+appIDStr)
+	if err != nil {
+		return nil, &subHandlerError{statusCode: http.StatusNotFound, message: "app not found"}
+	}
+
+	return app, nil
+}
+
+func toGLMappingResponse(m *entity.GLAccountMapping) GLMappingRequest {
+	return GLMappingRequest{
+		RecurringRevenueAccount: m.RecurringRevenueAccount,
+		UsageRevenueAccount:     m.UsageRevenueAccount,
+		OneTimeRevenueAccount:   m.OneTimeRevenueAccount,
+		RefundAccount:           m.RefundAccount,
+		RevenueShareFeeAccount:  m.RevenueShareFeeAccount,
+		ProcessingFeeAccount:    m.ProcessingFeeAccount,
+		TaxOnFeesAccount:        m.TaxOnFeesAccount,
+		ClearingAccount:         m.ClearingAccount,
+		RoundingAccount:         m.RoundingAccount,
+		XeroTaxRate:             m.XeroTaxRate,
+	}
+}
+
+func toExportRunResponses(runs []*entity.AccountingExportRun) []AccountingExportRunResponse {
+	result := make([]AccountingExportRunResponse, len(runs))
+	for i, run := range runs {
+		result[i] = AccountingExportRunResponse{
+			ID:               run.ID.String(),
+			Format:           string(run.Format),
+			Granularity:      string(run.Granularity),
+			PeriodStart:      run.PeriodStart.Format("2006-01-02"),
+			PeriodEnd:        run.PeriodEnd.Format("2006-01-02"),
+			JournalCount:     run.JournalCount,
+			TransactionCount: run.TransactionCount,
+			TotalDebitCents:  run.TotalDebitCents,
+			Confirmed:        run.Confirmed,
+			CreatedAt:        run.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return result
+}
@@ -0,0 +1,373 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// AlertHandler manages a user's alert rules on an app and the alerts they triggered
+type AlertHandler struct {
+	alertService *service.AlertService
+	partnerRepo  repository.PartnerAccountRepository
+	appRepo      repository.AppRepository
+}
+
+// NewAlertHandler creates a new AlertHandler
+func NewAlertHandler(
+	alertService *service.AlertService,
+	partnerRepo repository.PartnerAccountRepository,
+	appRepo repository.AppRepository,
+) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+		partnerRepo:  partnerRepo,
+		appRepo:      appRepo,
+	}
+}
+
+// AlertRuleRequest is the request body for creating or replacing an alert rule
+type AlertRuleRequest struct {
+	Name            string   `json:"name"`
+	RuleType        string   `json:"rule_type"`        // MRR_DROP, SHOP_AT_RISK, USAGE_REVENUE_ZERO, SYNC_FAILING
+	Threshold       float64  `json:"threshold"`        // Percent, MRR cents, days or hours depending on rule_type
+	RiskState       string   `json:"risk_state"`       // SHOP_AT_RISK only
+	Severity        string   `json:"severity"`         // Default WARNING
+	Channels        []string `json:"channels"`         // Default [PUSH]
+	CooldownMinutes *int     `json:"cooldown_minutes"` // Default 1440
+	Enabled         *bool    `json:"enabled"`          // Default true
+}
+
+// AlertRuleResponse represents an alert rule in API responses
+type AlertRuleResponse struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	RuleType        string   `json:"rule_type"`
+	Threshold       float64  `json:"threshold"`
+	RiskState       string   `json:"risk_state,omitempty"`
+	Severity        string   `json:"severity"`
+	Channels        []string `json:"channels"`
+	CooldownMinutes int      `json:"cooldown_minutes"`
+	Enabled         bool     `json:"enabled"`
+	CreatedAt       string   `json:"created_at"`
+	UpdatedAt       string   `json:"updated_at"`
+}
+
+// AlertResponse represents a triggered alert in API responses
+type AlertResponse struct {
+	ID             string  `json:"id"`
+	RuleID         string  `json:"rule_id"`
+	Key            string  `json:"key,omitempty"` // Shop domain for SHOP_AT_RISK
+	Severity       string  `json:"severity"`
+	Title          string  `json:"title"`
+	Message        string  `json:"message"`
+	Status         string  `json:"status"` // OPEN, ACKNOWLEDGED, RESOLVED
+	TriggeredAt    string  `json:"triggered_at"`
+	AcknowledgedAt *string `json:"acknowledged_at"`
+	ResolvedAt     *string `json:"resolved_at"`
+	AutoResolved   bool    `json:"auto_resolved"`
+}
+
+// ListRules handles GET /api/v1/apps/{appID}/alert-rules
+func (h *AlertHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	rules, err := h.alertService.ListRules(r.Context(), user.ID, app.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch alert rules")
+		return
+	}
+
+	response := make([]AlertRuleResponse, len(rules))
+	for i, rule := range rules {
+		response[i] = toAlertRuleResponse(rule)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"alert_rules": response,
+	})
+}
+
+// CreateRule handles POST /api/v1/apps/{appID}/alert-rules
+func (h *AlertHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	var req AlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	rule := newAlertRuleFromRequest(user.ID, app.ID, req)
+	if err := h.alertService.CreateRule(r.Context(), rule); err != nil {
+		writeAlertError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toAlertRuleResponse(rule))
+}
+
+// UpdateRule handles PUT /api/v1/apps/{appID}/alert-rules/{ruleID}
+func (h *AlertHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	ruleID, err := uuid.Parse(chi.URLParam(r, "ruleID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid rule ID")
+		return
+	}
+
+	var req AlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	rule := newAlertRuleFromRequest(user.ID, app.ID, req)
+	rule.ID = ruleID
+	if err := h.alertService.UpdateRule(r.Context(), rule); err != nil {
+		writeAlertError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAlertRuleResponse(rule))
+}
+
+// DeleteRule handles DELETE /api/v1/apps/{appID}/alert-rules/{ruleID}
+func (h *AlertHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	ruleID, err := uuid.Parse(chi.URLParam(r, "ruleID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid rule ID")
+		return
+	}
+
+	if err := h.alertService.DeleteRule(r.Context(), user.ID, app.ID, ruleID); err != nil {
+		writeAlertError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAlerts handles GET /api/v1/apps/{appID}/alerts?status=&limit=
+func (h *AlertHandler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	filter := repository.AlertFilter{
+		UserID: user.ID,
+		AppID:  app.ID,
+		Status: entity.AlertStatus(r.URL.Query().Get("status")),
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		writeJSONError(w, http.StatusBadRequest, "status must be one of OPEN, ACKNOWLEDGED, RESOLVED")
+		return
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeJSONError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	alerts, err := h.alertService.ListAlerts(r.Context(), filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch alerts")
+		return
+	}
+
+	response := make([]AlertResponse, len(alerts))
+	for i, a := range alerts {
+		response[i] = toAlertResponse(a)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"alerts": response,
+	})
+}
+
+// Acknowledge handles POST /api/v1/apps/{appID}/alerts/{alertID}/acknowledge
+func (h *AlertHandler) Acknowledge(w http.ResponseWriter, r *http.Request) {
+	h.changeAlertStatus(w, r, h.alertService.AcknowledgeAlert)
+}
+
+// Resolve handles POST /api/v1/apps/{appID}/alerts/{alertID}/resolve
+func (h *AlertHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	h.changeAlertStatus(w, r, h.alertService.ResolveAlert)
+}
+
+func (h *AlertHandler) changeAlertStatus(
+	w http.ResponseWriter,
+	r *http.Request,
+	change func(ctx context.Context, userID, appID, id uuid.UUID) (*entity.Alert, error),
+) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	alertID, err := uuid.Parse(chi.URLParam(r, "alertID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid alert ID")
+		return
+	}
+
+	alert, err := change(r.Context(), user.ID, app.ID, alertID)
+	if err != nil {
+		writeAlertError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAlertResponse(alert))
+}
+
+// getAppFromRequest resolves the user and the app from the numeric Shopify app ID in the URL
+func (h *AlertHandler) getAppFromRequest(r *http.Request) (*entity.User, *entity.App, *subHandlerError) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		return nil, nil, &subHandlerError{statusCode: http.StatusUnauthorized, message: "authentication required"}
+	}
+
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
+	if err != nil {
+		return nil, nil, partnerAccountError(err, http.StatusNotFound, "no partner account found")
+	}
+
+	appIDStr := chi.URLParam(r, "appID")
+	if appIDStr == "" {
+		return nil, nil, &subHandlerError{statusCode: http.StatusBadRequest, message: "app ID is required"}
+	}
+
+	app, err := h.appRepo.FindByPartnerAppID(r.Context(), partnerAccount.ID, appGIDPrefix+appIDStr)
+	if err != nil {
+		return nil, nil, &subHandlerError{statusCode: http.StatusNotFound, message: "app not found"}
+	}
+
+	return user, app, nil
+}
+
+// newAlertRuleFromRequest builds a rule from the request, keeping the defaults for omitted fields
+func newAlertRuleFromRequest(userID, appID uuid.UUID, req AlertRuleRequest) *entity.AlertRule {
+	rule := entity.NewAlertRule(userID, appID, req.Name, entity.AlertRuleType(req.RuleType), req.Threshold)
+	rule.RiskState = valueobject.RiskState(req.RiskState)
+	if req.Severity != "" {
+		rule.Severity = entity.AlertSeverity(req.Severity)
+	}
+	if req.Channels != nil {
+		rule.Channels = make([]entity.NotificationChannel, len(req.Channels))
+		for i, c := range req.Channels {
+			rule.Channels[i] = entity.NotificationChannel(c)
+		}
+	}
+	if req.CooldownMinutes != nil {
+		rule.CooldownMinutes = *req.CooldownMinutes
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	return rule
+}
+
+func writeAlertError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entity.ErrAlertRuleNameRequired),
+		errors.Is(err, entity.ErrInvalidAlertRuleType),
+		errors.Is(err, entity.ErrInvalidAlertThreshold),
+		errors.Is(err, entity.ErrInvalidAlertRiskState),
+		errors.Is(err, entity.ErrInvalidAlertSeverity),
+		errors.Is(err, entity.ErrInvalidAlertChannel),
+		errors.Is(err, entity.ErrInvalidAlertCooldown):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrAlertRuleNotFound),
+		errors.Is(err, service.ErrAlertNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, entity.ErrAlertAlreadyResolved):
+		writeJSONError(w, http.StatusConflict, err.Error())
+	default:
+		writeJSONError(w, http.StatusInternalServerError, "failed to save alert")
+	}
+}
+
+func toAlertRuleResponse(rule *entity.AlertRule) AlertRuleResponse {
+	channels := make([]string, len(rule.Channels))
+	for i, c := range rule.Channels {
+		channels[i] = string(c)
+	}
+	return AlertRuleResponse{
+		ID:              rule.ID.String(),
+		Name:            rule.Name,
+		RuleType:        string(rule.Type),
+		Threshold:       rule.Threshold,
+		RiskState:       string(rule.RiskState),
+		Severity:        string(rule.Severity),
+		Channels:        channels,
+		CooldownMinutes: rule.CooldownMinutes,
+		Enabled:         rule.Enabled,
+		CreatedAt:       rule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       rule.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toAlertResponse(a *entity.Alert) AlertResponse {
+	resp := AlertResponse{
+		ID:           a.ID.String(),
+		RuleID:       a.RuleID.String(),
+		Key:          a.Key,
+		Severity:     string(a.Severity),
+		Title:        a.Title,
+		Message:      a.Message,
+		Status:       string(a.Status),
+		TriggeredAt:  a.TriggeredAt.Format(time.RFC3339),
+		AutoResolved: a.AutoResolved,
+	}
+	if a.AcknowledgedAt != nil {
+		acknowledged := a.AcknowledgedAt.Format(time.RFC3339)
+		resp.AcknowledgedAt = &acknowledged
+	}
+	if a.ResolvedAt != nil {
+		resolved := a.ResolvedAt.Format(time.RFC3339)
+		resp.ResolvedAt = &resolved
+	}
+	return resp
+}
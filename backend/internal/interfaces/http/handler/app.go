@@ -58,9 +58,9 @@ func (h *AppHandler) GetAvailableApps(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get partner account
-	partnerAccount, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil {
-		writeJSONError(w, http.StatusNotFound, "no partner account found")
+		writePartnerAccountError(w, err, http.StatusNotFound, "no partner account found")
 		return
 	}
 
@@ -124,9 +124,9 @@ func (h *AppHandler) SelectApp(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get partner account
-	partnerAccount, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil {
-		writeJSONError(w, http.StatusNotFound, "no partner account found")
+		writePartnerAccountError(w, err, http.StatusNotFound, "no partner account found")
 		return
 	}
 
@@ -168,9 +168,9 @@ func (h *AppHandler) ListApps(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get partner account
-	partnerAccount, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil {
-		writeJSONError(w, http.StatusNotFound, "no partner account found")
+		writePartnerAccountError(w, err, http.StatusNotFound, "no partner account found")
 		return
 	}
 
@@ -253,9 +253,9 @@ func (h *AppHandler) UpdateAppTier(w http.ResponseWriter, r *http.Request) {
 	} else {
 		// Not a UUID - try as partner app ID (GID or numeric)
 		// Get partner account for this user
-		partnerAccount, paErr := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+		partnerAccount, paErr := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 		if paErr != nil {
-			writeJSONError(w, http.StatusNotFound, "partner account not found")
+			writePartnerAccountError(w, paErr, http.StatusNotFound, "partner account not found")
 			return
 		}
 
@@ -321,9 +321,9 @@ func (h *AppHandler) RefreshInstallCount(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Get partner account
-	partnerAccount, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil {
-		writeJSONError(w, http.StatusNotFound, "partner account not found")
+		writePartnerAccountError(w, err, http.StatusNotFound, "partner account not found")
 		return
 	}
 
@@ -396,9 +396,9 @@ func (h *AppHandler) GetInstallCount(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get partner account
-	partnerAccount, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil {
-		writeJSONError(w, http.StatusNotFound, "partner account not found")
+		writePartnerAccountError(w, err, http.StatusNotFound, "partner account not found")
 		return
 	}
 
@@ -92,6 +92,20 @@ func (m *mockPartnerRepoForApp) FindByUserID(ctx context.Context, userID uuid.UU
 	return m.account, m.findErr
 }
 
+func (m *mockPartnerRepoForApp) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (*entity.PartnerAccount, error) {
+	return m.account, m.findErr
+}
+
+func (m *mockPartnerRepoForApp) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PartnerAccount, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	if m.account == nil {
+		return nil, nil
+	}
+	return []*entity.PartnerAccount{m.account}, nil
+}
+
 func (m *mockPartnerRepoForApp) FindByPartnerID(ctx context.Context, partnerID string) (*entity.PartnerAccount, error) {
 	return nil, nil
 }
@@ -0,0 +1,656 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// defaultDunningReportDays is the report period when no range is given
+const defaultDunningReportDays = 90
+
+// DunningHandler manages a user's dunning sequences on an app, their runs and
+// tasks, shop contacts and the recovery report
+type DunningHandler struct {
+	dunningService *service.DunningService
+	partnerRepo    repository.PartnerAccountRepository
+	appRepo        repository.AppRepository
+}
+
+// NewDunningHandler creates a new DunningHandler
+func NewDunningHandler(
+	dunningService *service.DunningService,
+	partnerRepo repository.PartnerAccountRepository,
+	appRepo repository.AppRepository,
+) *DunningHandler {
+	return &DunningHandler{
+		dunningService: dunningService,
+		partnerRepo:    partnerRepo,
+		appRepo:        appRepo,
+	}
+}
+
+// DunningSequenceRequest is the request body for creating or replacing a dunning sequence
+type DunningSequenceRequest struct {
+	Name    string               `json:"name"`
+	Trigger string               `json:"trigger"` // ONE_CYCLE_MISSED, TWO_CYCLES_MISSED, CHURNED
+	Steps   []entity.DunningStep `json:"steps"`
+	Enabled *bool                `json:"enabled"` // Default true
+}
+
+// DunningSequenceResponse represents a dunning sequence in API responses
+type DunningSequenceResponse struct {
+	ID        string               `json:"id"`
+	Name      string               `json:"name"`
+	Trigger   string               `json:"trigger"`
+	Steps     []entity.DunningStep `json:"steps"`
+	Enabled   bool                 `json:"enabled"`
+	CreatedAt string               `json:"created_at"`
+	UpdatedAt string               `json:"updated_at"`
+}
+
+// DunningRunResponse represents a dunning run in API responses
+type DunningRunResponse struct {
+	ID          string                     `json:"id"`
+	SequenceID  string                     `json:"sequence_id"`
+	ShopDomain  string                     `json:"shop_domain"`
+	ShopName    string                     `json:"shop_name"`
+	Trigger     string                     `json:"trigger"`
+	MRRCents    int64                      `json:"mrr_cents"`
+	Status      string                     `json:"status"` // ACTIVE, RECOVERED, EXHAUSTED, CANCELLED
+	NextStep    int                        `json:"next_step"`
+	NextStepAt  *string                    `json:"next_step_at"`
+	LastError   string                     `json:"last_error,omitempty"`
+	Results     []entity.DunningStepResult `json:"results"`
+	StartedAt   string                     `json:"started_at"`
+	EndedAt     *string                    `json:"ended_at"`
+	RecoveredAt *string                    `json:"recovered_at"`
+}
+
+// DunningTaskResponse represents a dunning task in API responses
+type DunningTaskResponse struct {
+	ID          string  `json:"id"`
+	RunID       string  `json:"run_id"`
+	ShopDomain  string  `json:"shop_domain"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Assignee    string  `json:"assignee"`
+	Status      string  `json:"status"` // OPEN, DONE, CANCELLED
+	CreatedAt   string  `json:"created_at"`
+	CompletedAt *string `json:"completed_at"`
+}
+
+// ShopContactRequest is the request body for setting a shop's contact
+type ShopContactRequest struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// ShopContactResponse represents a shop contact in API responses
+type ShopContactResponse struct {
+	ShopDomain string `json:"shop_domain"`
+	Email      string `json:"email"`
+	Name       string `json:"name"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// DunningRecoveryStatsResponse represents run outcomes in the recovery report
+type DunningRecoveryStatsResponse struct {
+	Started           int     `json:"started"`
+	Active            int     `json:"active"`
+	Recovered         int     `json:"recovered"`
+	Exhausted         int     `json:"exhausted"`
+	Cancelled         int     `json:"cancelled"`
+	RecoveryRate      float64 `json:"recovery_rate"` // Percent of finished runs that recovered
+	AtRiskMRRCents    int64   `json:"at_risk_mrr_cents"`
+	RecoveredMRRCents int64   `json:"recovered_mrr_cents"`
+	AvgDaysToRecover  float64 `json:"avg_days_to_recover"`
+}
+
+// DunningSequenceRecoveryResponse represents one sequence in the recovery report
+type DunningSequenceRecoveryResponse struct {
+	SequenceID string `json:"sequence_id"`
+	Name       string `json:"name"`
+	DunningRecoveryStatsResponse
+}
+
+// ListSequences handles GET /api/v1/apps/{appID}/dunning-sequences
+func (h *DunningHandler) ListSequences(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	sequences, err := h.dunningService.ListSequences(r.Context(), user.ID, app.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch dunning sequences")
+		return
+	}
+
+	response := make([]DunningSequenceResponse, len(sequences))
+	for i, sequence := range sequences {
+		response[i] = toDunningSequenceResponse(sequence)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"dunning_sequences": response,
+	})
+}
+
+// GetSequence handles GET /api/v1/apps/{appID}/dunning-sequences/{sequenceID}
+func (h *DunningHandler) GetSequence(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	sequenceID, err := uuid.Parse(chi.URLParam(r, "sequenceID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid sequence ID")
+		return
+	}
+
+	sequence, err := h.dunningService.GetSequence(r.Context(), user.ID, app.ID, sequenceID)
+	if err != nil {
+		writeDunningError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toDunningSequenceResponse(sequence))
+}
+
+// CreateSequence handles POST /api/v1/apps/{appID}/dunning-sequences
+func (h *DunningHandler) CreateSequence(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	var req DunningSequenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	sequence := newDunningSequenceFromRequest(user.ID, app.ID, req)
+	if err := h.dunningService.CreateSequence(r.Context(), sequence); err != nil {
+		writeDunningError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toDunningSequenceResponse(sequence))
+}
+
+// UpdateSequence handles PUT /api/v1/apps/{appID}/dunning-sequences/{sequenceID}
+func (h *DunningHandler) UpdateSequence(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	sequenceID, err := uuid.Parse(chi.URLParam(r, "sequenceID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid sequence ID")
+		return
+	}
+
+	var req DunningSequenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	sequence := newDunningSequenceFromRequest(user.ID, app.ID, req)
+	sequence.ID = sequenceID
+	if err := h.dunningService.UpdateSequence(r.Context(), sequence); err != nil {
+		writeDunningError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toDunningSequenceResponse(sequence))
+}
+
+// DeleteSequence handles DELETE /api/v1/apps/{appID}/dunning-sequences/{sequenceID}
+func (h *DunningHandler) DeleteSequence(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	sequenceID, err := uuid.Parse(chi.URLParam(r, "sequenceID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid sequence ID")
+		return
+	}
+
+	if err := h.dunningService.DeleteSequence(r.Context(), user.ID, app.ID, sequenceID); err != nil {
+		writeDunningError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListRuns handles GET /api/v1/apps/{appID}/dunning-runs?status=&sequence_id=&limit=
+func (h *DunningHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := repository.DunningRunFilter{
+		UserID: user.ID,
+		AppID:  app.ID,
+		Status: entity.DunningRunStatus(query.Get("status")),
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		writeJSONError(w, http.StatusBadRequest, "status must be one of ACTIVE, RECOVERED, EXHAUSTED, CANCELLED")
+		return
+	}
+	if sequenceIDStr := query.Get("sequence_id"); sequenceIDStr != "" {
+		sequenceID, err := uuid.Parse(sequenceIDStr)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid sequence ID")
+			return
+		}
+		filter.SequenceID = &sequenceID
+	}
+	limit, ok := parseListLimit(w, r)
+	if !ok {
+		return
+	}
+	filter.Limit = limit
+
+	runs, err := h.dunningService.ListRuns(r.Context(), filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch dunning runs")
+		return
+	}
+
+	response := make([]DunningRunResponse, len(runs))
+	for i, run := range runs {
+		response[i] = toDunningRunResponse(run)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"dunning_runs": response,
+	})
+}
+
+// CancelRun handles POST /api/v1/apps/{appID}/dunning-runs/{runID}/cancel
+func (h *DunningHandler) CancelRun(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	runID, err := uuid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid run ID")
+		return
+	}
+
+	run, err := h.dunningService.CancelRun(r.Context(), user.ID, app.ID, runID)
+	if err != nil {
+		writeDunningError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toDunningRunResponse(run))
+}
+
+// ListTasks handles GET /api/v1/apps/{appID}/dunning-tasks?status=&limit=
+func (h *DunningHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	filter := repository.DunningTaskFilter{
+		UserID: user.ID,
+		AppID:  app.ID,
+		Status: entity.DunningTaskStatus(r.URL.Query().Get("status")),
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		writeJSONError(w, http.StatusBadRequest, "status must be one of OPEN, DONE, CANCELLED")
+		return
+	}
+	limit, ok := parseListLimit(w, r)
+	if !ok {
+		return
+	}
+	filter.Limit = limit
+
+	tasks, err := h.dunningService.ListTasks(r.Context(), filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch dunning tasks")
+		return
+	}
+
+	response := make([]DunningTaskResponse, len(tasks))
+	for i, task := range tasks {
+		response[i] = toDunningTaskResponse(task)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"dunning_tasks": response,
+	})
+}
+
+// CompleteTask handles POST /api/v1/apps/{appID}/dunning-tasks/{taskID}/complete
+func (h *DunningHandler) CompleteTask(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	taskID, err := uuid.Parse(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	task, err := h.dunningService.CompleteTask(r.Context(), user.ID, app.ID, taskID)
+	if err != nil {
+		writeDunningError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toDunningTaskResponse(task))
+}
+
+// GetShopContact handles GET /api/v1/apps/{appID}/shop-contacts/{domain}
+func (h *DunningHandler) GetShopContact(w http.ResponseWriter, r *http.Request) {
+	_, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	contact, err := h.dunningService.GetShopContact(r.Context(), app.ID, chi.URLParam(r, "domain"))
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch shop contact")
+		return
+	}
+	if contact == nil {
+		writeJSONError(w, http.StatusNotFound, "shop contact not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toShopContactResponse(contact))
+}
+
+// SetShopContact handles PUT /api/v1/apps/{appID}/shop-contacts/{domain}
+func (h *DunningHandler) SetShopContact(w http.ResponseWriter, r *http.Request) {
+	_, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	var req ShopContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	contact, err := h.dunningService.SetShopContact(r.Context(), app.ID, chi.URLParam(r, "domain"), req.Email, req.Name)
+	if err != nil {
+		writeDunningError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toShopContactResponse(contact))
+}
+
+// DeleteShopContact handles DELETE /api/v1/apps/{appID}/shop-contacts/{domain}
+func (h *DunningHandler) DeleteShopContact(w http.ResponseWriter, r *http.Request) {
+	_, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	if err := h.dunningService.DeleteShopContact(r.Context(), app.ID, chi.URLParam(r, "domain")); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to delete shop contact")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Report handles GET /api/v1/apps/{appID}/dunning-report?from=YYYY-MM-DD&to=YYYY-MM-DD
+// covering runs started in the range, both days included. Defaults to the last 90 days.
+func (h *DunningHandler) Report(w http.ResponseWriter, r *http.Request) {
+	user, app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if s := r.URL.Query().Get("to"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "to must be YYYY-MM-DD")
+			return
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -(defaultDunningReportDays - 1))
+	if s := r.URL.Query().Get("from"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "from must be YYYY-MM-DD")
+			return
+		}
+		from = t
+	}
+
+	report, err := h.dunningService.Report(r.Context(), user.ID, app.ID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		writeDunningError(w, err)
+		return
+	}
+
+	sequences := make([]DunningSequenceRecoveryResponse, len(report.Sequences))
+	for i, s := range report.Sequences {
+		sequences[i] = DunningSequenceRecoveryResponse{
+			SequenceID:                   s.SequenceID.String(),
+			Name:                         s.Name,
+			DunningRecoveryStatsResponse: toDunningRecoveryStatsResponse(s.DunningRecoveryStats),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"from":      from.Format("2006-01-02"),
+		"to":        to.Format("2006-01-02"),
+		"total":     toDunningRecoveryStatsResponse(report.Total),
+		"sequences": sequences,
+	})
+}
+
+// getAppFromRequest resolves the user and the app from the numeric Shopify app ID in the URL
+func (h *DunningHandler) getAppFromRequest(r *http.Request) (*entity.User, *entity.App, *subHandlerError) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		return nil, nil, &subHandlerError{statusCode: http.StatusUnauthorized, message: "authentication required"}
+	}
+
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
+	if err != nil {
+		return nil, nil, partnerAccountError(err, http.StatusNotFound, "no partner account found")
+	}
+
+	appIDStr := chi.URLParam(r, "appID")
+	if appIDStr == "" {
+		return nil, nil, &subHandlerError{statusCode: http.StatusBadRequest, message: "app ID is required"}
+	}
+
+	app, err := h.appRepo.FindByPartnerAppID(r.Context(), partnerAccount.ID, appGIDPrefix+appIDStr)
+	if err != nil {
+		return nil, nil, &subHandlerError{statusCode: http.StatusNotFound, message: "app not found"}
+	}
+
+	return user, app, nil
+}
+
+// parseListLimit reads the optional limit query parameter, writing a 400 if it is invalid
+func parseListLimit(w http.ResponseWriter, r *http.Request) (int, bool) {
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr == "" {
+		return 0, true
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 {
+		writeJSONError(w, http.StatusBadRequest, "limit must be a positive integer")
+		return 0, false
+	}
+	return limit, true
+}
+
+// newDunningSequenceFromRequest builds a sequence from the request, enabled unless set otherwise
+func newDunningSequenceFromRequest(userID, appID uuid.UUID, req DunningSequenceRequest) *entity.DunningSequence {
+	sequence := entity.NewDunningSequence(userID, appID, req.Name, valueobject.RiskState(req.Trigger), req.Steps, time.Now().UTC())
+	if req.Enabled != nil {
+		sequence.Enabled = *req.Enabled
+	}
+	return sequence
+}
+
+func writeDunningError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entity.ErrDunningSequenceNameRequired),
+		errors.Is(err, entity.ErrInvalidDunningTrigger),
+		errors.Is(err, entity.ErrInvalidDunningSteps),
+		errors.Is(err, entity.ErrInvalidDunningStepDay),
+		errors.Is(err, entity.ErrInvalidDunningAction),
+		errors.Is(err, entity.ErrInvalidDunningStep),
+		errors.Is(err, entity.ErrInvalidShopContactEmail),
+		errors.Is(err, service.ErrInvalidDunningTemplate),
+		errors.Is(err, service.ErrInvalidDunningReportRange):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrDunningSequenceNotFound),
+		errors.Is(err, service.ErrDunningRunNotFound),
+		errors.Is(err, service.ErrDunningTaskNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, entity.ErrDunningRunEnded),
+		errors.Is(err, entity.ErrDunningTaskClosed):
+		writeJSONError(w, http.StatusConflict, err.Error())
+	default:
+		writeJSONError(w, http.StatusInternalServerError, "failed to save dunning settings")
+	}
+}
+
+func toDunningSequenceResponse(sequence *entity.DunningSequence) DunningSequenceResponse {
+	return DunningSequenceResponse{
+		ID:        sequence.ID.String(),
+		Name:      sequence.Name,
+		Trigger:   string(sequence.Trigger),
+		Steps:     sequence.Steps,
+		Enabled:   sequence.Enabled,
+		CreatedAt: sequence.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: sequence.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toDunningRunResponse(run *entity.DunningRun) DunningRunResponse {
+	resp := DunningRunResponse{
+		ID:          run.ID.String(),
+		SequenceID:  run.SequenceID.String(),
+		ShopDomain:  run.ShopDomain,
+		ShopName:    run.ShopName,
+		Trigger:     string(run.Trigger),
+		MRRCents:    run.MRRCents,
+		Status:      string(run.Status),
+		NextStep:    run.NextStep,
+		LastError:   run.LastError,
+		Results:     run.Results,
+		StartedAt:   run.StartedAt.Format(time.RFC3339),
+		NextStepAt:  formatOptionalTime(run.NextStepAt),
+		EndedAt:     formatOptionalTime(run.EndedAt),
+		RecoveredAt: formatOptionalTime(run.RecoveredAt),
+	}
+	if resp.Results == nil {
+		resp.Results = []entity.DunningStepResult{}
+	}
+	return resp
+}
+
+func toDunningTaskResponse(task *entity.DunningTask) DunningTaskResponse {
+	return DunningTaskResponse{
+		ID:          task.ID.String(),
+		RunID:       task.RunID.String(),
+		ShopDomain:  task.ShopDomain,
+		Title:       task.Title,
+		Description: task.Description,
+		Assignee:    task.Assignee,
+		Status:      string(task.Status),
+		CreatedAt:   task.CreatedAt.Format(time.RFC3339),
+		CompletedAt: formatOptionalTime(task.CompletedAt),
+	}
+}
+
+func toShopContactResponse(contact *entity.ShopContact) ShopContactResponse {
+	return ShopContactResponse{
+		ShopDomain: contact.MyshopifyDomain,
+		Email:      contact.Email,
+		Name:       contact.Name,
+		UpdatedAt:  contact.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toDunningRecoveryStatsResponse(stats service.DunningRecoveryStats) DunningRecoveryStatsResponse {
+	return DunningRecoveryStatsResponse{
+		Started:           stats.Started,
+		Active:            stats.Active,
+		Recovered:         stats.Recovered,
+		Exhausted:         stats.Exhausted,
+		Cancelled:         stats.Cancelled,
+		RecoveryRate:      stats.RecoveryRate,
+		AtRiskMRRCents:    stats.AtRiskMRRCents,
+		RecoveredMRRCents: stats.RecoveredMRRCents,
+		AvgDaysToRecover:  stats.AvgDaysToRecover,
+	}
+}
+
+// formatOptionalTime formats a time as RFC 3339, or nil
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
 	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
 )
 
@@ -53,7 +54,8 @@ func (h *ExportHandler) ExportTransactions(w http.ResponseWriter, r *http.Reques
 	appIDStr := chi.URLParam(r, "appID")
 	app, err := h.lookupAppByNumericID(ctx, user.ID, appIDStr)
 	if err != nil {
-		writeExportError(w, http.StatusNotFound, "app not found")
+		herr := partnerAccountError(err, http.StatusNotFound, "app not found")
+		writeExportError(w, herr.statusCode, herr.message)
 		return
 	}
 
@@ -126,7 +128,8 @@ func (h *ExportHandler) ExportSubscriptions(w http.ResponseWriter, r *http.Reque
 	appIDStr := chi.URLParam(r, "appID")
 	app, err := h.lookupAppByNumericID(ctx, user.ID, appIDStr)
 	if err != nil {
-		writeExportError(w, http.StatusNotFound, "app not found")
+		herr := partnerAccountError(err, http.StatusNotFound, "app not found")
+		writeExportError(w, herr.statusCode, herr.message)
 		return
 	}
 
@@ -177,7 +180,8 @@ func (h *ExportHandler) ExportMetrics(w http.ResponseWriter, r *http.Request) {
 	appIDStr := chi.URLParam(r, "appID")
 	app, err := h.lookupAppByNumericID(ctx, user.ID, appIDStr)
 	if err != nil {
-		writeExportError(w, http.StatusNotFound, "app not found")
+		herr := partnerAccountError(err, http.StatusNotFound, "app not found")
+		writeExportError(w, herr.statusCode, herr.message)
 		return
 	}
 
@@ -247,7 +251,7 @@ func (h *ExportHandler) lookupAppByNumericID(ctx context.Context, userID uuid.UU
 	}
 
 	// Get partner account for user
-	partner, err := h.partnerRepo.FindByUserID(ctx, userID)
+	partner, err := partnerAccountForRole(ctx, h.partnerRepo, userID, valueobject.RoleAnalyst)
 	if err != nil {
 		return nil, err
 	}
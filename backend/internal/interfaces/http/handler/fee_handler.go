@@ -6,7 +6,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/service"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
@@ -15,10 +15,15 @@ import (
 
 const feeAppGIDPrefix = "gid://partners/App/"
 
+// defaultBreakdownTaxRate is the illustrative tax rate used by the breakdown calculator
+// when the partner has no tax profile
+const defaultBreakdownTaxRate = 0.08
+
 type FeeHandler struct {
 	appRepo         repository.AppRepository
 	partnerRepo     repository.PartnerAccountRepository
 	transactionRepo repository.TransactionRepository
+	taxProfileRepo  repository.TaxJurisdictionProfileRepository
 	feeService      *service.FeeVerificationService
 }
 
@@ -36,17 +41,24 @@ func NewFeeHandler(
 	}
 }
 
+// WithTaxProfiles enables expected tax-on-fees using the partner's tax jurisdiction profiles
+func (h *FeeHandler) WithTaxProfiles(taxProfileRepo repository.TaxJurisdictionProfileRepository) *FeeHandler {
+	h.taxProfileRepo = taxProfileRepo
+	return h
+}
+
 // getAppFromRequest resolves app from numeric Shopify app ID
-func (h *FeeHandler) getAppFromRequest(r *http.Request) (*uuid.UUID, *valueobject.RevenueShareTier, error) {
+func (h *FeeHandler) getAppFromRequest(r *http.Request) (*entity.App, *entity.PartnerAccount, error) {
 	user := middleware.UserFromContext(r.Context())
 	if user == nil {
 		return nil, nil, &feeError{http.StatusUnauthorized, "authentication required"}
 	}
 
 	// Get partner account
-	partnerAccount, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil {
-		return nil, nil, &feeError{http.StatusNotFound, "no partner account found"}
+		herr := partnerAccountError(err, http.StatusNotFound, "no partner account found")
+		return nil, nil, &feeError{herr.statusCode, herr.message}
 	}
 
 	// Get numeric appID from URL and construct full GID
@@ -62,7 +74,19 @@ func (h *FeeHandler) getAppFromRequest(r *http.Request) (*uuid.UUID, *valueobjec
 		return nil, nil, &feeError{http.StatusNotFound, "app not found"}
 	}
 
-	return &app.ID, &app.RevenueShareTier, nil
+	return app, partnerAccount, nil
+}
+
+// taxProfiles returns the partner's tax profiles, or nil if none are configured
+func (h *FeeHandler) taxProfiles(r *http.Request, partnerAccount *entity.PartnerAccount) []*entity.TaxJurisdictionProfile {
+	if h.taxProfileRepo == nil {
+		return nil
+	}
+	profiles, err := h.taxProfileRepo.FindByPartnerAccountID(r.Context(), partnerAccount.ID)
+	if err != nil {
+		return nil
+	}
+	return profiles
 }
 
 type feeError struct {
@@ -74,11 +98,14 @@ func (e *feeError) Error() string {
 	return e.message
 }
 
+// feeVerificationTolerance is the share of gross a fee may differ by, for rounding
+const feeVerificationTolerance = 0.001
+
 // GetFeeSummary returns aggregated fee information for an app
 // GET /api/v1/apps/{appID}/fees/summary?start=YYYY-MM-DD&end=YYYY-MM-DD
 // appID is numeric Shopify app ID (e.g., "4599915")
 func (h *FeeHandler) GetFeeSummary(w http.ResponseWriter, r *http.Request) {
-	appID, tier, err := h.getAppFromRequest(r)
+	app, partnerAccount, err := h.getAppFromRequest(r)
 	if err != nil {
 		if fe, ok := err.(*feeError); ok {
 			writeFeeError(w, fe.statusCode, fe.message)
@@ -104,18 +131,35 @@ func (h *FeeHandler) GetFeeSummary(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	tier := app.RevenueShareTier
+
 	// Get transactions
-	transactions, err2 := h.transactionRepo.FindByAppID(r.Context(), *appID, start, end)
+	transactions, err2 := h.transactionRepo.FindByAppID(r.Context(), app.ID, start, end)
 	if err2 != nil {
 		writeFeeError(w, http.StatusInternalServerError, "failed to fetch transactions")
 		return
 	}
 
-	// Calculate fee summary
-	summary := h.feeService.CalculateFeeSummary(transactions)
+	// Calculate fee summary (with expected tax on fees when the partner has tax profiles)
+	profiles := h.taxProfiles(r, partnerAccount)
+	summary := h.feeService.CalculateFeeSummaryWithTax(transactions, profiles)
+
+	// Verify each charge against the tier and the tax profile in effect on its date
+	verified, discrepancies := 0, 0
+	for _, tx := range transactions {
+		if tx.GrossAmountCents <= 0 {
+			continue // Refunds and adjustments have no tier-based fees to verify
+		}
+		profile := entity.EffectiveTaxProfile(profiles, tx.TransactionDate)
+		if h.feeService.VerifyTransactionWithTax(tx, tier, profile, feeVerificationTolerance).IsVerified {
+			verified++
+		} else {
+			discrepancies++
+		}
+	}
 
 	// Calculate tier savings
-	savings := h.feeService.CalculateTierSavings(summary.TotalGrossAmountCents, *tier)
+	savings := h.feeService.CalculateTierSavings(summary.TotalGrossAmountCents, tier)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -137,12 +181,20 @@ func (h *FeeHandler) GetFeeSummary(w http.ResponseWriter, r *http.Request) {
 			"total_revenue_share_cents": summary.TotalRevenueShareCents,
 			"total_processing_fee_cents": summary.TotalProcessingFeeCents,
 			"total_tax_on_fees_cents":   summary.TotalTaxOnFeesCents,
+			"expected_tax_on_fees_cents": summary.ExpectedTaxOnFeesCents,
+			"tax_on_fees_discrepancy_cents": summary.TaxOnFeesDiscrepancyCents,
+			"tax_profile_configured":    len(profiles) > 0,
 			"total_fees_cents":          summary.TotalFeesCents,
 			"total_net_cents":           summary.TotalNetAmountCents,
 			"avg_revenue_share_pct":     summary.AverageRevenueSharePct,
 			"avg_processing_fee_pct":    summary.AverageProcessingFeePct,
 			"effective_fee_pct":         summary.EffectiveFeePercent,
 		},
+		"verification": map[string]interface{}{
+			"verified_count":    verified,
+			"discrepancy_count": discrepancies,
+			"tolerance_pct":     feeVerificationTolerance * 100,
+		},
 		"savings": map[string]interface{}{
 			"compared_to":            "DEFAULT_20",
 			"default_fees_cents":     savings.DefaultTierFeesCents,
@@ -168,7 +220,7 @@ func writeFeeError(w http.ResponseWriter, statusCode int, message string) {
 // GET /api/v1/apps/{appID}/fees/breakdown?amount_cents=4900
 // appID is numeric Shopify app ID (e.g., "4599915")
 func (h *FeeHandler) GetTierBreakdown(w http.ResponseWriter, r *http.Request) {
-	_, currentTier, err := h.getAppFromRequest(r)
+	app, partnerAccount, err := h.getAppFromRequest(r)
 	if err != nil {
 		if fe, ok := err.(*feeError); ok {
 			writeFeeError(w, fe.statusCode, fe.message)
@@ -186,8 +238,15 @@ func (h *FeeHandler) GetTierBreakdown(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Tax rate (default 8%)
-	taxRate := 0.08
+	currentTier := &app.RevenueShareTier
+
+	// Tax rate from the partner's current tax profile (default 8%)
+	taxRate := defaultBreakdownTaxRate
+	if h.taxProfileRepo != nil {
+		if profile := entity.EffectiveTaxProfile(h.taxProfiles(r, partnerAccount), time.Now().UTC()); profile != nil {
+			taxRate = profile.Rate
+		}
+	}
 
 	// Calculate breakdowns for all tiers
 	tiers := []valueobject.RevenueShareTier{
@@ -278,3 +337,99 @@ func (h *FeeHandler) ListAvailableTiers(w http.ResponseWriter, r *http.Request)
 		"tiers": tiers,
 	})
 }
+
+// GetTaxReport returns fees and the tax charged on them per period, for filing input tax credits
+// GET /api/v1/apps/{appID}/fees/tax-report?start=YYYY-MM-DD&end=YYYY-MM-DD&period=month|quarter|year
+func (h *FeeHandler) GetTaxReport(w http.ResponseWriter, r *http.Request) {
+	app, partnerAccount, err := h.getAppFromRequest(r)
+	if err != nil {
+		if fe, ok := err.(*feeError); ok {
+			writeFeeError(w, fe.statusCode, fe.message)
+		} else {
+			writeFeeError(w, http.StatusInternalServerError, "internal error")
+		}
+		return
+	}
+
+	period := entity.TaxPeriodMonth
+	if p := r.URL.Query().Get("period"); p != "" {
+		period = entity.TaxPeriod(p)
+		if !period.IsValid() {
+			writeFeeError(w, http.StatusBadRequest, "invalid period (expected month, quarter or year)")
+			return
+		}
+	}
+
+	// Default to the current year to date
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := now
+
+	if startStr := r.URL.Query().Get("start"); startStr != "" {
+		parsed, err := time.Parse("2006-01-02", startStr)
+		if err != nil {
+			writeFeeError(w, http.StatusBadRequest, "invalid start date format (expected YYYY-MM-DD)")
+			return
+		}
+		start = parsed
+	}
+	if endStr := r.URL.Query().Get("end"); endStr != "" {
+		parsed, err := time.Parse("2006-01-02", endStr)
+		if err != nil {
+			writeFeeError(w, http.StatusBadRequest, "invalid end date format (expected YYYY-MM-DD)")
+			return
+		}
+		end = parsed.Add(24*time.Hour - time.Second) // End of day
+	}
+	if end.Before(start) {
+		writeFeeError(w, http.StatusBadRequest, "end date must not be before start date")
+		return
+	}
+
+	transactions, err := h.transactionRepo.FindByAppID(r.Context(), app.ID, start, end)
+	if err != nil {
+		writeFeeError(w, http.StatusInternalServerError, "failed to fetch transactions")
+		return
+	}
+
+	profiles := h.taxProfiles(r, partnerAccount)
+	rows := h.feeService.BuildTaxOnFeesReport(transactions, profiles, period)
+
+	periods := make([]map[string]interface{}, len(rows))
+	var totalFees, totalCharged, totalExpected int64
+	for i, row := range rows {
+		periods[i] = map[string]interface{}{
+			"period_start":          row.PeriodStart.Format("2006-01-02"),
+			"period_end":            row.PeriodEnd.Format("2006-01-02"),
+			"currency":              row.Currency,
+			"jurisdiction":          row.Jurisdiction,
+			"tax_name":              row.TaxName,
+			"tax_rate":              row.Rate,
+			"transaction_count":     row.TransactionCount,
+			"revenue_share_cents":   row.RevenueShareCents,
+			"processing_fee_cents":  row.ProcessingFeeCents,
+			"taxable_fees_cents":    row.TaxableFeesCents,
+			"tax_charged_cents":     row.TaxChargedCents,
+			"expected_tax_cents":    row.ExpectedTaxCents,
+			"tax_discrepancy_cents": row.TaxDiscrepancyCents,
+		}
+		totalFees += row.TaxableFeesCents
+		totalCharged += row.TaxChargedCents
+		totalExpected += row.ExpectedTaxCents
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"period": map[string]string{
+			"start": start.Format("2006-01-02"),
+			"end":   end.Format("2006-01-02"),
+		},
+		"granularity": string(period),
+		"periods":     periods,
+		"totals": map[string]interface{}{
+			"taxable_fees_cents": totalFees,
+			"tax_charged_cents":  totalCharged,
+			"expected_tax_cents": totalExpected,
+		},
+	})
+}
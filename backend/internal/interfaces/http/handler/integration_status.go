@@ -27,7 +27,7 @@ func (h *IntegrationStatusHandler) GetStatus(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	account, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	account, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil || account == nil {
 		// Not connected
 		w.Header().Set("Content-Type", "application/json")
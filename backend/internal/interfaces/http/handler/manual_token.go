@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
@@ -60,7 +61,11 @@ func (h *ManualTokenHandler) AddToken(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if account already exists
-	existingAccount, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	existingAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
+	if errors.Is(err, errWorkspaceAccessDenied) {
+		writeJSONError(w, http.StatusForbidden, "insufficient permissions")
+		return
+	}
 	if err == nil && existingAccount != nil {
 		// Update existing account
 		existingAccount.PartnerID = req.PartnerID
@@ -83,8 +88,16 @@ func (h *ManualTokenHandler) AddToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create new account
+	// Create new account, owned by the current workspace
 	account := entity.NewPartnerAccount(user.ID, req.PartnerID, valueobject.IntegrationTypeManual, encryptedToken)
+	if member := middleware.WorkspaceFromContext(r.Context()); member != nil {
+		// A user connects at most one partner account, as background jobs look accounts up by user
+		if connected, err := h.partnerRepo.FindByUserID(r.Context(), user.ID); err == nil && connected != nil {
+			writeJSONError(w, http.StatusConflict, "you already connected a partner account in another workspace")
+			return
+		}
+		account.WorkspaceID = &member.WorkspaceID
+	}
 
 	if err := h.partnerRepo.Create(r.Context(), account); err != nil {
 		writeJSONError(w, http.StatusInternalServerError, "failed to save partner account")
@@ -110,9 +123,9 @@ func (h *ManualTokenHandler) GetToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	account, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	account, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil {
-		writeJSONError(w, http.StatusNotFound, "no partner account found")
+		writePartnerAccountError(w, err, http.StatusNotFound, "no partner account found")
 		return
 	}
 
@@ -142,7 +155,18 @@ func (h *ManualTokenHandler) RevokeToken(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := h.partnerRepo.Delete(r.Context(), user.ID); err != nil {
+	// Inside a workspace, the account may have been connected by another member
+	connectedBy := user.ID
+	if middleware.WorkspaceFromContext(r.Context()) != nil {
+		account, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
+		if err != nil {
+			writePartnerAccountError(w, err, http.StatusNotFound, "no partner account found")
+			return
+		}
+		connectedBy = account.UserID
+	}
+
+	if err := h.partnerRepo.Delete(r.Context(), connectedBy); err != nil {
 		writeJSONError(w, http.StatusNotFound, "no partner account found")
 		return
 	}
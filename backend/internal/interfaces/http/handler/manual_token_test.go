@@ -48,6 +48,23 @@ func (m *mockPartnerRepoForManual) FindByUserID(ctx context.Context, userID uuid
 	return m.account, nil
 }
 
+func (m *mockPartnerRepoForManual) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (*entity.PartnerAccount, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	return m.account, nil
+}
+
+func (m *mockPartnerRepoForManual) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PartnerAccount, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	if m.account == nil {
+		return nil, nil
+	}
+	return []*entity.PartnerAccount{m.account}, nil
+}
+
 func (m *mockPartnerRepoForManual) FindByPartnerID(ctx context.Context, partnerID string) (*entity.PartnerAccount, error) {
 	return nil, nil
 }
@@ -132,9 +132,9 @@ func (h *MetricsHandler) GetMetricsByPeriod(w http.ResponseWriter, r *http.Reque
 	fullAppGID := appGIDPrefix + appID
 
 	// Get partner account to verify access
-	partnerAccount, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil {
-		writeJSONError(w, http.StatusForbidden, "no partner account found")
+		writePartnerAccountError(w, err, http.StatusForbidden, "no partner account found")
 		return
 	}
 
@@ -292,9 +292,9 @@ func (h *MetricsHandler) GetAggregateMetrics(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Get partner account
-	partnerAccount, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil {
-		writeJSONError(w, http.StatusNotFound, "no partner account found")
+		writePartnerAccountError(w, err, http.StatusNotFound, "no partner account found")
 		return
 	}
 
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// NotificationChannelHandler manages a user's Slack, Teams, Discord and
+// generic notification webhooks under the preferences API
+type NotificationChannelHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationChannelHandler creates a new NotificationChannelHandler
+func NewNotificationChannelHandler(notificationService *service.NotificationService) *NotificationChannelHandler {
+	return &NotificationChannelHandler{notificationService: notificationService}
+}
+
+// CreateNotificationChannelRequest is the request body for adding a webhook
+type CreateNotificationChannelRequest struct {
+	Channel string `json:"channel"` // SLACK, TEAMS, DISCORD or WEBHOOK
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+}
+
+// UpdateNotificationChannelRequest is the request body for updating a webhook; omitted fields are unchanged
+type UpdateNotificationChannelRequest struct {
+	Name    *string `json:"name,omitempty"`
+	URL     *string `json:"url,omitempty"`
+	Enabled *bool   `json:"enabled,omitempty"`
+}
+
+// NotificationChannelResponse represents a notification webhook in API responses
+type NotificationChannelResponse struct {
+	ID            string  `json:"id"`
+	Channel       string  `json:"channel"`
+	Name          string  `json:"name"`
+	URL           string  `json:"url"`
+	Enabled       bool    `json:"enabled"`
+	Secret        string  `json:"secret,omitempty"` // WEBHOOK only, on create and secret rotation
+	LastTestedAt  *string `json:"last_tested_at"`
+	LastTestError string  `json:"last_test_error,omitempty"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+// List handles GET /api/v1/user/preferences/notification-channels
+func (h *NotificationChannelHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	webhooks, err := h.notificationService.ListWebhooks(r.Context(), user.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch notification channels")
+		return
+	}
+
+	response := make([]NotificationChannelResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		response[i] = toNotificationChannelResponse(webhook)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"notification_channels": response,
+	})
+}
+
+// Create handles POST /api/v1/user/preferences/notification-channels. Generic
+// webhooks return their signing secret.
+func (h *NotificationChannelHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req CreateNotificationChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	webhook, err := h.notificationService.CreateWebhook(r.Context(), user.ID, entity.NotificationChannel(req.Channel), req.Name, req.URL)
+	if err != nil {
+		writeNotificationChannelError(w, err)
+		return
+	}
+
+	response := toNotificationChannelResponse(webhook)
+	response.Secret = webhook.Secret
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Update handles PUT /api/v1/user/preferences/notification-channels/{channelID}
+func (h *NotificationChannelHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	channelID, err := uuid.Parse(chi.URLParam(r, "channelID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid channel ID")
+		return
+	}
+
+	var req UpdateNotificationChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	webhook, err := h.notificationService.UpdateWebhook(r.Context(), user.ID, channelID, service.UpdateNotificationWebhookRequest{
+		Name:    req.Name,
+		URL:     req.URL,
+		Enabled: req.Enabled,
+	})
+	if err != nil {
+		writeNotificationChannelError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toNotificationChannelResponse(webhook))
+}
+
+// Delete handles DELETE /api/v1/user/preferences/notification-channels/{channelID}
+func (h *NotificationChannelHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	channelID, err := uuid.Parse(chi.URLParam(r, "channelID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid channel ID")
+		return
+	}
+
+	if err := h.notificationService.DeleteWebhook(r.Context(), user.ID, channelID); err != nil {
+		writeNotificationChannelError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Test handles POST /api/v1/user/preferences/notification-channels/{channelID}/test.
+// The outcome is in last_test_error, which is empty if the test was delivered.
+func (h *NotificationChannelHandler) Test(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	channelID, err := uuid.Parse(chi.URLParam(r, "channelID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid channel ID")
+		return
+	}
+
+	webhook, err := h.notificationService.TestWebhook(r.Context(), user.ID, channelID)
+	if err != nil {
+		writeNotificationChannelError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"delivered":            webhook.LastTestError == "",
+		"notification_channel": toNotificationChannelResponse(webhook),
+	})
+}
+
+// RotateSecret handles POST /api/v1/user/preferences/notification-channels/{channelID}/rotate-secret
+func (h *NotificationChannelHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	channelID, err := uuid.Parse(chi.URLParam(r, "channelID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid channel ID")
+		return
+	}
+
+	webhook, err := h.notificationService.RotateWebhookSecret(r.Context(), user.ID, channelID)
+	if err != nil {
+		writeNotificationChannelError(w, err)
+		return
+	}
+
+	response := toNotificationChannelResponse(webhook)
+	response.Secret = webhook.Secret
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeNotificationChannelError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidNotificationWebhookChannel),
+		errors.Is(err, entity.ErrInvalidNotificationWebhookName),
+		errors.Is(err, service.ErrInvalidNotificationWebhookURL),
+		errors.Is(err, service.ErrNotificationChannelUnavailable),
+		errors.Is(err, service.ErrNotificationWebhookUnsigned):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrNotificationWebhookNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrTooManyNotificationWebhooks):
+		writeJSONError(w, http.StatusConflict, err.Error())
+	default:
+		writeJSONError(w, http.StatusInternalServerError, "failed to save notification channel")
+	}
+}
+
+func toNotificationChannelResponse(webhook *entity.NotificationWebhook) NotificationChannelResponse {
+	resp := NotificationChannelResponse{
+		ID:            webhook.ID.String(),
+		Channel:       string(webhook.Channel),
+		Name:          webhook.Name,
+		URL:           webhook.URL,
+		Enabled:       webhook.Enabled,
+		LastTestError: webhook.LastTestError,
+		CreatedAt:     webhook.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     webhook.UpdatedAt.Format(time.RFC3339),
+	}
+	if webhook.LastTestedAt != nil {
+		tested := webhook.LastTestedAt.Format(time.RFC3339)
+		resp.LastTestedAt = &tested
+	}
+	return resp
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// NotificationEmailHandler manages a user's notification email addresses and
+// serves the verification and unsubscribe links sent in emails
+type NotificationEmailHandler struct {
+	emailService *service.NotificationEmailService
+}
+
+// NewNotificationEmailHandler creates a new NotificationEmailHandler
+func NewNotificationEmailHandler(emailService *service.NotificationEmailService) *NotificationEmailHandler {
+	return &NotificationEmailHandler{emailService: emailService}
+}
+
+// NotificationEmailRequest is the request body for adding an address
+type NotificationEmailRequest struct {
+	Address string `json:"address"`
+}
+
+// NotificationEmailResponse represents a notification address in API responses
+type NotificationEmailResponse struct {
+	ID                 string  `json:"id"`
+	Address            string  `json:"address"`
+	Verified           bool    `json:"verified"`
+	VerifiedAt         *string `json:"verified_at"`
+	VerificationSentAt string  `json:"verification_sent_at"`
+	Unsubscribed       bool    `json:"unsubscribed"`
+	UnsubscribedAt     *string `json:"unsubscribed_at"`
+	CreatedAt          string  `json:"created_at"`
+}
+
+// List handles GET /api/v1/notification-emails
+func (h *NotificationEmailHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	emails, err := h.emailService.ListAddresses(r.Context(), user.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch notification emails")
+		return
+	}
+
+	response := make([]NotificationEmailResponse, len(emails))
+	for i, e := range emails {
+		response[i] = toNotificationEmailResponse(e)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"notification_emails": response,
+	})
+}
+
+// Add handles POST /api/v1/notification-emails and sends the verification email
+func (h *NotificationEmailHandler) Add(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req NotificationEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	email, err := h.emailService.AddAddress(r.Context(), user.ID, req.Address)
+	if err != nil {
+		writeNotificationEmailError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toNotificationEmailResponse(email))
+}
+
+// ResendVerification handles POST /api/v1/notification-emails/{emailID}/resend-verification
+func (h *NotificationEmailHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	emailID, err := uuid.Parse(chi.URLParam(r, "emailID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid email ID")
+		return
+	}
+
+	email, err := h.emailService.ResendVerification(r.Context(), user.ID, emailID)
+	if err != nil {
+		writeNotificationEmailError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toNotificationEmailResponse(email))
+}
+
+// Delete handles DELETE /api/v1/notification-emails/{emailID}
+func (h *NotificationEmailHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	emailID, err := uuid.Parse(chi.URLParam(r, "emailID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid email ID")
+		return
+	}
+
+	if err := h.emailService.RemoveAddress(r.Context(), user.ID, emailID); err != nil {
+		writeNotificationEmailError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Verify handles GET /api/v1/notification-emails/verify?token= (public, opened from the email)
+func (h *NotificationEmailHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	email, err := h.emailService.Verify(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		writeEmailLinkError(w, err)
+		return
+	}
+
+	writeEmailLinkPage(w, http.StatusOK, email.Address+" is verified and will receive LedgerGuard notifications.")
+}
+
+// unsubscribeConfirmPage asks before unsubscribing, and posts back to the same link
+var unsubscribeConfirmPage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Unsubscribe from LedgerGuard notifications</title></head>
+<body>
+<p>Stop sending LedgerGuard notifications to {{.Address}}?</p>
+<form method="post" action="?token={{.Token}}">
+<button type="submit">Unsubscribe</button>
+</form>
+</body>
+</html>
+`))
+
+// ConfirmUnsubscribe handles GET /api/v1/notification-emails/unsubscribe?token=
+// (public, opened from the email). It only shows a confirmation page, so link
+// scanners that open every link in an email do not unsubscribe the address.
+func (h *NotificationEmailHandler) ConfirmUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	email, err := h.emailService.FindUnsubscribeAddress(r.Context(), token)
+	if err != nil {
+		writeEmailLinkError(w, err)
+		return
+	}
+	if email.UnsubscribedAt != nil {
+		writeEmailLinkPage(w, http.StatusOK, email.Address+" is already unsubscribed from LedgerGuard notifications.")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	unsubscribeConfirmPage.Execute(w, struct{ Address, Token string }{email.Address, token})
+}
+
+// Unsubscribe handles POST /api/v1/notification-emails/unsubscribe?token=
+// (public), from the confirmation page or as the RFC 8058 one-click
+// unsubscribe sent by mail clients.
+func (h *NotificationEmailHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	email, err := h.emailService.Unsubscribe(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		writeEmailLinkError(w, err)
+		return
+	}
+
+	writeEmailLinkPage(w, http.StatusOK, email.Address+" is unsubscribed from LedgerGuard notifications.")
+}
+
+func writeNotificationEmailError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidEmailAddress):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrNotificationEmailNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrNotificationEmailExists),
+		errors.Is(err, service.ErrNotificationEmailVerified):
+		writeJSONError(w, http.StatusConflict, err.Error())
+	default:
+		writeJSONError(w, http.StatusInternalServerError, "failed to save notification email")
+	}
+}
+
+// writeEmailLinkError responds to a link opened from an email, which is read in a browser
+func writeEmailLinkError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidEmailLink):
+		writeEmailLinkPage(w, http.StatusNotFound, "This link is invalid or has already been used.")
+	case errors.Is(err, entity.ErrEmailVerificationExpired):
+		writeEmailLinkPage(w, http.StatusGone, "This verification link has expired. Resend the verification email from your notification settings.")
+	default:
+		writeEmailLinkPage(w, http.StatusInternalServerError, "Something went wrong. Please try again.")
+	}
+}
+
+func writeEmailLinkPage(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write([]byte(message + "\n"))
+}
+
+func toNotificationEmailResponse(e *entity.NotificationEmail) NotificationEmailResponse {
+	resp := NotificationEmailResponse{
+		ID:                 e.ID.String(),
+		Address:            e.Address,
+		Verified:           e.IsVerified(),
+		VerificationSentAt: e.VerificationSentAt.Format(time.RFC3339),
+		Unsubscribed:       e.UnsubscribedAt != nil,
+		CreatedAt:          e.CreatedAt.Format(time.RFC3339),
+	}
+	if e.VerifiedAt != nil {
+		verified := e.VerifiedAt.Format(time.RFC3339)
+		resp.VerifiedAt = &verified
+	}
+	if e.UnsubscribedAt != nil {
+		unsubscribed := e.UnsubscribedAt.Format(time.RFC3339)
+		resp.UnsubscribedAt = &unsubscribed
+	}
+	return resp
+}
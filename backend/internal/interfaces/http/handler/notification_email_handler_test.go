@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+)
+
+type mockNotificationEmailRepoForHandler struct {
+	repository.NotificationEmailRepository
+	email *entity.NotificationEmail
+}
+
+func (m *mockNotificationEmailRepoForHandler) FindByUnsubscribeTokenHash(ctx context.Context, tokenHash string) (*entity.NotificationEmail, error) {
+	if m.email.UnsubscribeTokenHash != tokenHash {
+		return nil, errors.New("not found")
+	}
+	return m.email, nil
+}
+
+func (m *mockNotificationEmailRepoForHandler) Update(ctx context.Context, email *entity.NotificationEmail) error {
+	return nil
+}
+
+func TestNotificationEmailHandler_Unsubscribe(t *testing.T) {
+	key := []byte("test-unsubscribe-key")
+	now := time.Now().UTC()
+	email, _ := entity.NewNotificationEmail(uuid.New(), "owner@example.com", key, now)
+	email.Verify(now)
+	repo := &mockNotificationEmailRepoForHandler{email: email}
+	h := NewNotificationEmailHandler(service.NewNotificationEmailService(repo, nil, "https://api.example.com", key))
+	target := "/api/v1/notification-emails/unsubscribe?token=" + entity.UnsubscribeToken(key, email.ID)
+
+	t.Run("GET only asks for confirmation", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ConfirmUnsubscribe(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+		if body := rec.Body.String(); !strings.Contains(body, `method="post"`) || !strings.Contains(body, "owner@example.com") {
+			t.Errorf("expected a confirmation form, got %q", body)
+		}
+		if !email.IsDeliverable() {
+			t.Error("expected GET not to unsubscribe the address")
+		}
+	})
+
+	t.Run("GET with an unknown token", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ConfirmUnsubscribe(rec, httptest.NewRequest(http.MethodGet, "/api/v1/notification-emails/unsubscribe?token=nope", nil))
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("one-click POST unsubscribes", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader("List-Unsubscribe=One-Click"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		h.Unsubscribe(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+		if email.IsDeliverable() {
+			t.Error("expected POST to unsubscribe the address")
+		}
+	})
+}
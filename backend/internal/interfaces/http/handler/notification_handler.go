@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// NotificationHandler serves the user's notification history and read state
+type NotificationHandler struct {
+	outboxService *service.NotificationOutboxService
+}
+
+// NewNotificationHandler creates a new NotificationHandler
+func NewNotificationHandler(outboxService *service.NotificationOutboxService) *NotificationHandler {
+	return &NotificationHandler{outboxService: outboxService}
+}
+
+// NotificationResponse represents a notification in API responses
+type NotificationResponse struct {
+	ID              string   `json:"id"`
+	AppID           *string  `json:"app_id"`
+	Kind            string   `json:"kind"` // ALERT, RISK_CHANGE, DAILY_SUMMARY
+	SourceID        *string  `json:"source_id"`
+	Severity        string   `json:"severity"`
+	Title           string   `json:"title"`
+	Body            string   `json:"body"`
+	Channels        []string `json:"channels"`
+	PendingChannels []string `json:"pending_channels"`
+	Status          string   `json:"status"` // PENDING, DELIVERED, FAILED
+	Attempts        int      `json:"attempts"`
+	NextAttemptAt   *string  `json:"next_attempt_at"`
+	LastError       string   `json:"last_error,omitempty"`
+	Read            bool     `json:"read"`
+	CreatedAt       string   `json:"created_at"`
+	DeliveredAt     *string  `json:"delivered_at"`
+	ReadAt          *string  `json:"read_at"`
+}
+
+// NotificationAttemptResponse represents one delivery attempt on one channel
+type NotificationAttemptResponse struct {
+	Channel     string `json:"channel"`
+	Attempt     int    `json:"attempt"`
+	Status      string `json:"status"` // SENT, FAILED, SKIPPED
+	Error       string `json:"error,omitempty"`
+	AttemptedAt string `json:"attempted_at"`
+}
+
+// List handles GET /api/v1/notifications?unread=&limit=
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	filter := repository.NotificationFilter{UserID: user.ID}
+	if unreadStr := r.URL.Query().Get("unread"); unreadStr != "" {
+		unread, err := strconv.ParseBool(unreadStr)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "unread must be true or false")
+			return
+		}
+		filter.UnreadOnly = unread
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeJSONError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	notifications, unreadCount, err := h.outboxService.ListNotifications(r.Context(), filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch notifications")
+		return
+	}
+
+	response := make([]NotificationResponse, len(notifications))
+	for i, n := range notifications {
+		response[i] = toNotificationResponse(n)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"notifications": response,
+		"unread_count":  unreadCount,
+	})
+}
+
+// Get handles GET /api/v1/notifications/{notificationID}, including every delivery attempt
+func (h *NotificationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	notificationID, err := uuid.Parse(chi.URLParam(r, "notificationID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid notification ID")
+		return
+	}
+
+	notification, attempts, err := h.outboxService.GetNotification(r.Context(), user.ID, notificationID)
+	if err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	attemptResponses := make([]NotificationAttemptResponse, len(attempts))
+	for i, a := range attempts {
+		attemptResponses[i] = NotificationAttemptResponse{
+			Channel:     string(a.Channel),
+			Attempt:     a.Attempt,
+			Status:      string(a.Status),
+			Error:       a.Error,
+			AttemptedAt: a.AttemptedAt.Format(time.RFC3339),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"notification": toNotificationResponse(notification),
+		"attempts":     attemptResponses,
+	})
+}
+
+// MarkRead handles POST /api/v1/notifications/{notificationID}/read
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	notificationID, err := uuid.Parse(chi.URLParam(r, "notificationID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid notification ID")
+		return
+	}
+
+	if err := h.outboxService.MarkRead(r.Context(), user.ID, notificationID); err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllRead handles POST /api/v1/notifications/read-all
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	marked, err := h.outboxService.MarkAllRead(r.Context(), user.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to mark notifications read")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"marked_read": marked,
+	})
+}
+
+func writeNotificationError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrNotificationNotFound) {
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSONError(w, http.StatusInternalServerError, "failed to fetch notification")
+}
+
+func toNotificationResponse(n *entity.Notification) NotificationResponse {
+	resp := NotificationResponse{
+		ID:              n.ID.String(),
+		Kind:            string(n.Kind),
+		Severity:        string(n.Severity),
+		Title:           n.Title,
+		Body:            n.Body,
+		Channels:        notificationChannelStrings(n.Channels),
+		PendingChannels: notificationChannelStrings(n.PendingChannels),
+		Status:          string(n.Status),
+		Attempts:        n.Attempts,
+		LastError:       n.LastError,
+		Read:            n.IsRead(),
+		CreatedAt:       n.CreatedAt.Format(time.RFC3339),
+	}
+	if n.AppID != nil {
+		appID := n.AppID.String()
+		resp.AppID = &appID
+	}
+	if n.SourceID != nil {
+		sourceID := n.SourceID.String()
+		resp.SourceID = &sourceID
+	}
+	if n.NextAttemptAt != nil {
+		next := n.NextAttemptAt.Format(time.RFC3339)
+		resp.NextAttemptAt = &next
+	}
+	if n.DeliveredAt != nil {
+		delivered := n.DeliveredAt.Format(time.RFC3339)
+		resp.DeliveredAt = &delivered
+	}
+	if n.ReadAt != nil {
+		read := n.ReadAt.Format(time.RFC3339)
+		resp.ReadAt = &read
+	}
+	return resp
+}
+
+func notificationChannelStrings(channels []entity.NotificationChannel) []string {
+	result := make([]string, len(channels))
+	for i, c := range channels {
+		result[i] = string(c)
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// timeOfDayLayout is the "HH:MM" format of times of day in notification preferences
+const timeOfDayLayout = "15:04"
+
+// NotificationPreferencesHandler serves a user's notification settings,
+// including when the daily summary is sent
+type NotificationPreferencesHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationPreferencesHandler creates a new NotificationPreferencesHandler
+func NewNotificationPreferencesHandler(notificationService *service.NotificationService) *NotificationPreferencesHandler {
+	return &NotificationPreferencesHandler{notificationService: notificationService}
+}
+
+// UpdateNotificationPreferencesRequest is the request body for updating
+// notification preferences; omitted fields are unchanged. Quiet hours are
+// set or cleared together: send both quiet_hours_start and quiet_hours_end,
+// as "HH:MM" or null.
+type UpdateNotificationPreferencesRequest struct {
+	CriticalEnabled     *bool           `json:"critical_enabled,omitempty"`
+	DailySummaryEnabled *bool           `json:"daily_summary_enabled,omitempty"`
+	DailySummaryTime    *string         `json:"daily_summary_time,omitempty"` // HH:MM in timezone
+	WeeklyDigestEnabled *bool           `json:"weekly_digest_enabled,omitempty"`
+	Timezone            *string         `json:"timezone,omitempty"` // IANA name, e.g. Europe/Berlin
+	QuietHoursStart     json.RawMessage `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd       json.RawMessage `json:"quiet_hours_end,omitempty"`
+	SkipWeekends        *bool           `json:"skip_weekends,omitempty"`
+	SlackWebhookURL     *string         `json:"slack_webhook_url,omitempty"` // Empty removes it
+}
+
+// NotificationPreferencesResponse represents notification preferences in API responses
+type NotificationPreferencesResponse struct {
+	CriticalEnabled     bool    `json:"critical_enabled"`
+	DailySummaryEnabled bool    `json:"daily_summary_enabled"`
+	DailySummaryTime    string  `json:"daily_summary_time"`
+	WeeklyDigestEnabled bool    `json:"weekly_digest_enabled"`
+	Timezone            string  `json:"timezone"`
+	QuietHoursStart     *string `json:"quiet_hours_start"`
+	QuietHoursEnd       *string `json:"quiet_hours_end"`
+	SkipWeekends        bool    `json:"skip_weekends"`
+	SlackWebhookURL     string  `json:"slack_webhook_url"`
+	NextDailySummaryAt  *string `json:"next_daily_summary_at"` // Null until the scheduler has planned it
+}
+
+// Get handles GET /api/v1/user/preferences/notifications
+func (h *NotificationPreferencesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	prefs, err := h.notificationService.GetPreferences(r.Context(), user.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch notification preferences")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toNotificationPreferencesResponse(prefs))
+}
+
+// Update handles PUT /api/v1/user/preferences/notifications
+func (h *NotificationPreferencesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req UpdateNotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	update := service.UpdateNotificationPreferencesRequest{
+		CriticalEnabled:     req.CriticalEnabled,
+		DailySummaryEnabled: req.DailySummaryEnabled,
+		WeeklyDigestEnabled: req.WeeklyDigestEnabled,
+		Timezone:            req.Timezone,
+		SkipWeekends:        req.SkipWeekends,
+		SlackWebhookURL:     req.SlackWebhookURL,
+	}
+	if req.DailySummaryTime != nil {
+		t, err := time.Parse(timeOfDayLayout, *req.DailySummaryTime)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "daily_summary_time must be HH:MM")
+			return
+		}
+		update.DailySummaryTime = &t
+	}
+	if len(req.QuietHoursStart) > 0 || len(req.QuietHoursEnd) > 0 {
+		start, startErr := parseOptionalTimeOfDay(req.QuietHoursStart)
+		end, endErr := parseOptionalTimeOfDay(req.QuietHoursEnd)
+		if startErr != nil || endErr != nil {
+			writeJSONError(w, http.StatusBadRequest, "quiet hours must be HH:MM or null")
+			return
+		}
+		update.SetQuietHours = true
+		update.QuietHoursStart, update.QuietHoursEnd = start, end
+	}
+
+	prefs, err := h.notificationService.UpdateNotificationPreferences(r.Context(), user.ID, update)
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidTimezone),
+			errors.Is(err, entity.ErrInvalidQuietHours),
+			errors.Is(err, service.ErrInvalidNotificationWebhookURL):
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+		default:
+			writeJSONError(w, http.StatusInternalServerError, "failed to save notification preferences")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toNotificationPreferencesResponse(prefs))
+}
+
+// parseOptionalTimeOfDay parses an "HH:MM" JSON string; null or a missing value gives nil
+func parseOptionalTimeOfDay(raw json.RawMessage) (*time.Time, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	t, err := time.Parse(timeOfDayLayout, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func toNotificationPreferencesResponse(prefs *entity.NotificationPreferences) NotificationPreferencesResponse {
+	resp := NotificationPreferencesResponse{
+		CriticalEnabled:     prefs.CriticalEnabled,
+		DailySummaryEnabled: prefs.DailySummaryEnabled,
+		DailySummaryTime:    prefs.DailySummaryTime.Format(timeOfDayLayout),
+		WeeklyDigestEnabled: prefs.WeeklyDigestEnabled,
+		Timezone:            prefs.Location().String(),
+		SkipWeekends:        prefs.SkipWeekends,
+		SlackWebhookURL:     prefs.SlackWebhookURL,
+	}
+	if prefs.QuietHoursStart != nil && prefs.QuietHoursEnd != nil {
+		start := prefs.QuietHoursStart.Format(timeOfDayLayout)
+		end := prefs.QuietHoursEnd.Format(timeOfDayLayout)
+		resp.QuietHoursStart, resp.QuietHoursEnd = &start, &end
+	}
+	if prefs.NextDailySummaryAt != nil {
+		next := prefs.NextDailySummaryAt.Format(time.RFC3339)
+		resp.NextDailySummaryAt = &next
+	}
+	return resp
+}
@@ -31,6 +31,14 @@ type OAuthStateStore interface {
 	Validate(state string) (uuid.UUID, bool)
 }
 
+// OAuthWorkspaceStateStore is implemented by state stores that also remember
+// the workspace an OAuth flow was started in, so the callback can attach the
+// partner account to it
+type OAuthWorkspaceStateStore interface {
+	StoreForWorkspace(state string, userID, workspaceID uuid.UUID)
+	ValidateWorkspace(state string) (userID, workspaceID uuid.UUID, ok bool)
+}
+
 type OAuthHandler struct {
 	oauthService OAuthService
 	encryptor    Encryptor
@@ -64,11 +72,22 @@ func (h *OAuthHandler) StartOAuth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Connecting an account to a workspace takes an ADMIN
+	member := middleware.WorkspaceFromContext(r.Context())
+	if member != nil && !member.Role.AtLeast(valueobject.RoleAdmin) {
+		writeJSONError(w, http.StatusForbidden, "insufficient permissions")
+		return
+	}
+
 	state := generateState()
 	url := h.oauthService.GenerateAuthURL(state)
 
-	// Store state with user ID for validation in callback
-	h.stateStore.Store(state, user.ID)
+	// Store state with user ID (and workspace) for validation in callback
+	if workspaceStore, ok := h.stateStore.(OAuthWorkspaceStateStore); ok && member != nil {
+		workspaceStore.StoreForWorkspace(state, user.ID, member.WorkspaceID)
+	} else {
+		h.stateStore.Store(state, user.ID)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
@@ -92,8 +111,14 @@ func (h *OAuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate state and get associated user ID
-	userID, valid := h.stateStore.Validate(state)
+	// Validate state and get associated user ID (and workspace)
+	var userID, workspaceID uuid.UUID
+	var valid bool
+	if workspaceStore, ok := h.stateStore.(OAuthWorkspaceStateStore); ok {
+		userID, workspaceID, valid = workspaceStore.ValidateWorkspace(state)
+	} else {
+		userID, valid = h.stateStore.Validate(state)
+	}
 	if !valid {
 		writeJSONError(w, http.StatusBadRequest, "invalid or expired state parameter")
 		return
@@ -127,8 +152,42 @@ func (h *OAuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create partner account
+	// Reconnect the account of the workspace the flow was started in (or the
+	// user's own account without workspaces) if there is one
+	var existingAccount *entity.PartnerAccount
+	if workspaceID != uuid.Nil {
+		existingAccount, err = h.partnerRepo.FindByWorkspaceID(r.Context(), workspaceID)
+	} else {
+		existingAccount, err = h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	}
+	if err == nil && existingAccount != nil {
+		existingAccount.PartnerID = partnerID
+		existingAccount.EncryptedAccessToken = encryptedToken
+		existingAccount.IntegrationType = valueobject.IntegrationTypeOAuth
+
+		if err := h.partnerRepo.Update(r.Context(), existingAccount); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "failed to update partner account")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "Partner account reconnected successfully",
+			"id":      existingAccount.ID.String(),
+		})
+		return
+	}
+
+	// Create partner account, owned by the workspace the flow was started in
 	account := entity.NewPartnerAccount(user.ID, partnerID, valueobject.IntegrationTypeOAuth, encryptedToken)
+	if workspaceID != uuid.Nil {
+		// A user connects at most one partner account, as background jobs look accounts up by user
+		if connected, err := h.partnerRepo.FindByUserID(r.Context(), user.ID); err == nil && connected != nil {
+			writeJSONError(w, http.StatusConflict, "you already connected a partner account in another workspace")
+			return
+		}
+		account.WorkspaceID = &workspaceID
+	}
 
 	if err := h.partnerRepo.Create(r.Context(), account); err != nil {
 		writeJSONError(w, http.StatusInternalServerError, "failed to save partner account")
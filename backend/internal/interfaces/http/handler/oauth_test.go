@@ -53,6 +53,8 @@ func (m *mockEncryptor) Decrypt(ciphertext []byte) ([]byte, error) {
 
 type mockPartnerAccountRepo struct {
 	account   *entity.PartnerAccount
+	existing  *entity.PartnerAccount // Returned by FindByUserID and FindByWorkspaceID when it matches
+	updated   *entity.PartnerAccount
 	createErr error
 }
 
@@ -66,6 +68,20 @@ func (m *mockPartnerAccountRepo) FindByID(ctx context.Context, id uuid.UUID) (*e
 }
 
 func (m *mockPartnerAccountRepo) FindByUserID(ctx context.Context, userID uuid.UUID) (*entity.PartnerAccount, error) {
+	if m.existing != nil && m.existing.UserID == userID {
+		return m.existing, nil
+	}
+	return nil, nil
+}
+
+func (m *mockPartnerAccountRepo) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (*entity.PartnerAccount, error) {
+	if m.existing != nil && m.existing.WorkspaceID != nil && *m.existing.WorkspaceID == workspaceID {
+		return m.existing, nil
+	}
+	return nil, nil
+}
+
+func (m *mockPartnerAccountRepo) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PartnerAccount, error) {
 	return nil, nil
 }
 
@@ -74,6 +90,7 @@ func (m *mockPartnerAccountRepo) FindByPartnerID(ctx context.Context, partnerID
 }
 
 func (m *mockPartnerAccountRepo) Update(ctx context.Context, account *entity.PartnerAccount) error {
+	m.updated = account
 	return nil
 }
 
@@ -125,6 +142,23 @@ func (m *mockStateStore) Validate(state string) (uuid.UUID, bool) {
 	return uuid.Nil, false
 }
 
+type mockWorkspaceStateStore struct {
+	mockStateStore
+	returnWorkspaceID uuid.UUID
+}
+
+func (m *mockWorkspaceStateStore) StoreForWorkspace(state string, userID, workspaceID uuid.UUID) {
+	m.Store(state, userID)
+}
+
+func (m *mockWorkspaceStateStore) ValidateWorkspace(state string) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := m.Validate(state)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, m.returnWorkspaceID, true
+}
+
 func TestOAuthHandler_StartOAuth_NoUser(t *testing.T) {
 	oauthService := &mockOAuthService{authURL: "https://partners.shopify.com/authorize"}
 	stateStore := &mockStateStore{}
@@ -263,3 +297,70 @@ func TestOAuthHandler_Callback_Success(t *testing.T) {
 		t.Errorf("expected integration type OAUTH, got %s", partnerRepo.account.IntegrationType)
 	}
 }
+
+func TestOAuthHandler_Callback_ExistingAccount(t *testing.T) {
+	user := &entity.User{ID: uuid.New(), Role: valueobject.RoleOwner}
+	workspaceID := uuid.New()
+
+	callback := func(partnerRepo *mockPartnerAccountRepo) *httptest.ResponseRecorder {
+		stateStore := &mockWorkspaceStateStore{
+			mockStateStore:    mockStateStore{storedState: "valid-state", validState: true, returnUserID: user.ID},
+			returnWorkspaceID: workspaceID,
+		}
+		handler := NewOAuthHandler(
+			&mockOAuthService{token: "new-access-token"},
+			&mockEncryptor{encrypted: []byte("encrypted-token")},
+			partnerRepo,
+			&mockUserRepo{user: user},
+			stateStore,
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/callback?code=test-code&state=valid-state", nil)
+		rec := httptest.NewRecorder()
+		handler.Callback(rec, req)
+		return rec
+	}
+
+	t.Run("reconnects the workspace's account", func(t *testing.T) {
+		existing := &entity.PartnerAccount{
+			ID:              uuid.New(),
+			UserID:          uuid.New(), // Connected by another member
+			WorkspaceID:     &workspaceID,
+			IntegrationType: valueobject.IntegrationTypeManual,
+		}
+		partnerRepo := &mockPartnerAccountRepo{existing: existing}
+
+		rec := callback(partnerRepo)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d; body: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		if partnerRepo.account != nil {
+			t.Error("expected no new partner account")
+		}
+		if partnerRepo.updated != existing {
+			t.Fatal("expected the workspace's account to be updated")
+		}
+		if existing.IntegrationType != valueobject.IntegrationTypeOAuth {
+			t.Errorf("expected integration type OAUTH, got %s", existing.IntegrationType)
+		}
+	})
+
+	t.Run("user connected an account in another workspace", func(t *testing.T) {
+		otherWorkspaceID := uuid.New()
+		partnerRepo := &mockPartnerAccountRepo{existing: &entity.PartnerAccount{
+			ID:          uuid.New(),
+			UserID:      user.ID,
+			WorkspaceID: &otherWorkspaceID,
+		}}
+
+		rec := callback(partnerRepo)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("expected status %d, got %d; body: %s", http.StatusConflict, rec.Code, rec.Body.String())
+		}
+		if partnerRepo.account != nil || partnerRepo.updated != nil {
+			t.Error("expected no partner account changes")
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
 	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
 )
 
@@ -64,7 +65,7 @@ func (h *OnboardingHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if user has a partner account
-	partnerAccount, err := h.partnerRepo.FindByUserID(ctx, user.ID)
+	partnerAccount, err := partnerAccountForRole(ctx, h.partnerRepo, user.ID, valueobject.RoleViewer)
 	if err == nil && partnerAccount != nil {
 		status.HasPartnerAccount = true
 
@@ -120,7 +121,7 @@ func (h *OnboardingHandler) Complete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate that prerequisites are met
-	partnerAccount, err := h.partnerRepo.FindByUserID(ctx, user.ID)
+	partnerAccount, err := partnerAccountForRole(ctx, h.partnerRepo, user.ID, valueobject.RoleViewer)
 	if err != nil || partnerAccount == nil {
 		writeOnboardingError(w, http.StatusBadRequest, "must connect partner account before completing onboarding")
 		return
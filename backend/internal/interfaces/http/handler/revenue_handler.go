@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
 	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
 )
 
@@ -144,7 +145,7 @@ func (h *RevenueHandler) lookupAppByNumericID(ctx context.Context, userID uuid.U
 	ID uuid.UUID
 }, error) {
 	// Get partner account for user
-	partner, err := h.partnerRepo.FindByUserID(ctx, userID)
+	partner, err := partnerAccountForRole(ctx, h.partnerRepo, userID, valueobject.RoleViewer)
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +47,20 @@ func (m *mockPartnerRepoForRevenue) FindByUserID(ctx context.Context, userID uui
 	return m.account, m.err
 }
 
+func (m *mockPartnerRepoForRevenue) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (*entity.PartnerAccount, error) {
+	return m.account, m.err
+}
+
+func (m *mockPartnerRepoForRevenue) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PartnerAccount, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.account == nil {
+		return nil, nil
+	}
+	return []*entity.PartnerAccount{m.account}, nil
+}
+
 func (m *mockPartnerRepoForRevenue) FindByPartnerID(ctx context.Context, partnerID string) (*entity.PartnerAccount, error) {
 	return m.account, m.err
 }
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// RevenueRecognitionHandler serves accrual-basis (recognized / deferred) revenue reports
+type RevenueRecognitionHandler struct {
+	recognitionService *service.RevenueRecognitionService
+	partnerRepo        repository.PartnerAccountRepository
+	appRepo            repository.AppRepository
+}
+
+// NewRevenueRecognitionHandler creates a new RevenueRecognitionHandler
+func NewRevenueRecognitionHandler(
+	recognitionService *service.RevenueRecognitionService,
+	partnerRepo repository.PartnerAccountRepository,
+	appRepo repository.AppRepository,
+) *RevenueRecognitionHandler {
+	return &RevenueRecognitionHandler{
+		recognitionService: recognitionService,
+		partnerRepo:        partnerRepo,
+		appRepo:            appRepo,
+	}
+}
+
+// RecognitionMonthResponse is one month of the recognition report
+type RecognitionMonthResponse struct {
+	Month                string `json:"month"` // YYYY-MM
+	Currency             string `json:"currency"`
+	BilledCents          int64  `json:"billed_cents"`
+	RefundedCents        int64  `json:"refunded_cents"`
+	RecognizedCents      int64  `json:"recognized_cents"`
+	DeferredOpeningCents int64  `json:"deferred_opening_cents"`
+	DeferredClosingCents int64  `json:"deferred_closing_cents"`
+}
+
+// RecognitionScheduleResponse is a charge's recognition schedule
+type RecognitionScheduleResponse struct {
+	TransactionID  string                        `json:"transaction_id"`
+	ShopDomain     string                        `json:"shop_domain"`
+	ShopName       string                        `json:"shop_name"`
+	ChargeType     string                        `json:"charge_type"`
+	Currency       string                        `json:"currency"`
+	BilledCents    int64                         `json:"billed_cents"`
+	RefundedCents  int64                         `json:"refunded_cents"`
+	BilledDate     string                        `json:"billed_date"`
+	ServiceStart   string                        `json:"service_start"`
+	ServiceEnd     string                        `json:"service_end"`
+	CancelledAt    *string                       `json:"cancelled_at,omitempty"`
+	MonthlyAmounts []RecognitionAllocationAmount `json:"monthly_recognized"`
+}
+
+// RecognitionAllocationAmount is the revenue a schedule recognizes in a month
+type RecognitionAllocationAmount struct {
+	Month           string `json:"month"`
+	RecognizedCents int64  `json:"recognized_cents"`
+}
+
+// GetReport handles GET /api/v1/apps/{appID}/revenue-recognition?start=YYYY-MM&end=YYYY-MM
+// Set include_schedules=true to include per-charge schedules.
+func (h *RevenueRecognitionHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	app, herr := h.getAppFromRequest(r, requiredWorkspaceRole(r))
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	from, to, err := parseMonthRange(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.recognitionService.GetReport(r.Context(), app.ID, from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDateRange) {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSONError(w, http.StatusInternalServerError, "failed to build revenue recognition report")
+		return
+	}
+
+	months := make([]RecognitionMonthResponse, len(report.Months))
+	for i, m := range report.Months {
+		months[i] = RecognitionMonthResponse{
+			Month:                m.Month.Format("2006-01"),
+			Currency:             m.Currency,
+			BilledCents:          m.BilledCents,
+			RefundedCents:        m.RefundedCents,
+			RecognizedCents:      m.RecognizedCents,
+			DeferredOpeningCents: m.DeferredOpeningCents,
+			DeferredClosingCents: m.DeferredClosingCents,
+		}
+	}
+
+	response := map[string]interface{}{
+		"start":  report.FromMonth.Format("2006-01"),
+		"end":    report.ToMonth.Format("2006-01"),
+		"months": months,
+	}
+
+	if r.URL.Query().Get("include_schedules") == "true" {
+		schedules := make([]RecognitionScheduleResponse, len(report.Schedules))
+		for i, s := range report.Schedules {
+			schedules[i] = h.toScheduleResponse(s, report)
+		}
+		response["schedules"] = schedules
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Export handles GET /api/v1/apps/{appID}/revenue-recognition/export
+// Query params: start, end (YYYY-MM), format (csv|json), view (summary|detail)
+func (h *RevenueRecognitionHandler) Export(w http.ResponseWriter, r *http.Request) {
+	app, herr := h.getAppFromRequest(r, valueobject.RoleAnalyst)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	from, to, err := parseMonthRange(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	format := service.ExportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = service.ExportFormatCSV
+	}
+	if format != service.ExportFormatCSV && format != service.ExportFormatJSON {
+		writeJSONError(w, http.StatusBadRequest, "invalid format (expected csv or json)")
+		return
+	}
+
+	view := service.RecognitionExportView(r.URL.Query().Get("view"))
+	if view == "" {
+		view = service.RecognitionViewSummary
+	}
+
+	result, err := h.recognitionService.Export(r.Context(), app.ID, from, to, format, view)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRecognitionView) || errors.Is(err, service.ErrInvalidDateRange) {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSONError(w, http.StatusInternalServerError, "failed to export revenue recognition")
+		return
+	}
+
+	w.Header().Set("Content-Type", result.ContentType)
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+result.Filename+"\"")
+	w.Header().Set("X-Record-Count", formatInt(result.RecordCount))
+	w.Write(result.Data)
+}
+
+func (h *RevenueRecognitionHandler) toScheduleResponse(s *entity.RecognitionSchedule, report *service.RevenueRecognitionReport) RecognitionScheduleResponse {
+	resp := RecognitionScheduleResponse{
+		TransactionID:  s.TransactionID.String(),
+		ShopDomain:     s.MyshopifyDomain,
+		ShopName:       s.ShopName,
+		ChargeType:     string(s.ChargeType),
+		Currency:       s.Currency,
+		BilledCents:    s.BilledCents,
+		RefundedCents:  s.RefundedCents(),
+		BilledDate:     s.BilledDate.Format("2006-01-02"),
+		ServiceStart:   s.ServiceStart.Format("2006-01-02"),
+		ServiceEnd:     s.ServiceEnd.Format("2006-01-02"),
+		MonthlyAmounts: []RecognitionAllocationAmount{},
+	}
+	if s.CancelledAt != nil {
+		cancelled := s.CancelledAt.Format("2006-01-02")
+		resp.CancelledAt = &cancelled
+	}
+	for _, a := range h.recognitionService.Allocate(s, report.FromMonth, report.ToMonth) {
+		resp.MonthlyAmounts = append(resp.MonthlyAmounts, RecognitionAllocationAmount{
+			Month:           a.Month.Format("2006-01"),
+			RecognizedCents: a.RecognizedCents,
+		})
+	}
+	return resp
+}
+
+// getAppFromRequest resolves the app from the numeric Shopify app ID in the URL
+func (h *RevenueRecognitionHandler) getAppFromRequest(r *http.Request, minRole valueobject.Role) (*entity.App, *subHandlerError) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		return nil, &subHandlerError{statusCode: http.StatusUnauthorized, message: "authentication required"}
+	}
+
+	partnerAccount, err := partnerAccountForRole(r.Context(), h.partnerRepo, user.ID, minRole)
+	if err != nil {
+		return nil, partnerAccountError(err, http.StatusNotFound, "no partner account found")
+	}
+
+	appIDStr := chi.URLParam(r, "appID")
+	if appIDStr == "" {
+		return nil, &subHandlerError{statusCode: http.StatusBadRequest, message: "app ID is required"}
+	}
+
+	app, err := h.appRepo.FindByPartnerAppID(r.Context(), partnerAccount.ID, accountingAppGIDPrefix+appIDStr)
+	if err != nil {
+		return nil, &subHandlerError{statusCode: http.StatusNotFound, message: "app not found"}
+	}
+
+	return app, nil
+}
+
+// parseMonthRange parses start/end (YYYY-MM) query params.
+// Defaults to the trailing 12 months ending with the current month.
+func parseMonthRange(r *http.Request) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, -11, 0)
+
+	if s := r.URL.Query().Get("start"); s != "" {
+		t, err := time.Parse("2006-01", s)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid start month format (expected YYYY-MM)")
+		}
+		from = t
+	}
+	if s := r.URL.Query().Get("end"); s != "" {
+		t, err := time.Parse("2006-01", s)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid end month format (expected YYYY-MM)")
+		}
+		to = t
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("end month must not be before start month")
+	}
+	if from.AddDate(0, 36, 0).Before(to) {
+		return time.Time{}, time.Time{}, errors.New("range cannot exceed 36 months")
+	}
+	return from, to, nil
+}
@@ -88,9 +88,9 @@ func (h *StoreHealthHandler) GetStoreHealth(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Get partner account
-	partnerAccount, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil {
-		writeJSONError(w, http.StatusNotFound, "no partner account found")
+		writePartnerAccountError(w, err, http.StatusNotFound, "no partner account found")
 		return
 	}
 
@@ -226,9 +226,9 @@ func (h *SubscriptionHandler) getAppIDFromRequest(r *http.Request) (uuid.UUID, *
 	}
 
 	// Get partner account
-	partnerAccount, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil {
-		return uuid.Nil, &subHandlerError{statusCode: http.StatusNotFound, message: "no partner account found"}
+		return uuid.Nil, partnerAccountError(err, http.StatusNotFound, "no partner account found")
 	}
 
 	// Get numeric appID from URL and construct full GID
@@ -258,9 +258,9 @@ func (h *SubscriptionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get partner account
-	partnerAccount, err := h.partnerRepo.FindByUserID(r.Context(), user.ID)
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
 	if err != nil {
-		writeJSONError(w, http.StatusNotFound, "no partner account found")
+		writePartnerAccountError(w, err, http.StatusNotFound, "no partner account found")
 		return
 	}
 
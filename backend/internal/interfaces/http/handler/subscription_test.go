@@ -56,6 +56,10 @@ func (m *mockSubscriptionRepo) FindByAppIDAndDomain(ctx context.Context, appID u
 	return m.subscription, m.findErr
 }
 
+func (m *mockSubscriptionRepo) FindUpdatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*entity.Subscription, error) {
+	return nil, nil
+}
+
 func (m *mockSubscriptionRepo) FindByRiskState(ctx context.Context, appID uuid.UUID, riskState valueobject.RiskState) ([]*entity.Subscription, error) {
 	if m.findAllErr != nil {
 		return nil, m.findAllErr
@@ -260,6 +264,20 @@ func (m *mockPartnerRepoForSub) FindByUserID(ctx context.Context, userID uuid.UU
 	return m.account, m.findErr
 }
 
+func (m *mockPartnerRepoForSub) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (*entity.PartnerAccount, error) {
+	return m.account, m.findErr
+}
+
+func (m *mockPartnerRepoForSub) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PartnerAccount, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	if m.account == nil {
+		return nil, nil
+	}
+	return []*entity.PartnerAccount{m.account}, nil
+}
+
 func (m *mockPartnerRepoForSub) FindByPartnerID(ctx context.Context, partnerID string) (*entity.PartnerAccount, error) {
 	return nil, nil
 }
@@ -40,6 +40,10 @@ func (m *mockSyncTransactionRepo) UpsertBatch(ctx context.Context, txs []*entity
 	return m.err
 }
 
+func (m *mockSyncTransactionRepo) FindCreatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*entity.Transaction, error) {
+	return nil, nil
+}
+
 func (m *mockSyncTransactionRepo) FindByAppID(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.Transaction, error) {
 	return nil, nil
 }
@@ -126,6 +130,20 @@ func (m *mockSyncPartnerRepo) FindByUserID(ctx context.Context, userID uuid.UUID
 	return m.account, m.err
 }
 
+func (m *mockSyncPartnerRepo) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (*entity.PartnerAccount, error) {
+	return m.account, m.err
+}
+
+func (m *mockSyncPartnerRepo) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PartnerAccount, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.account == nil {
+		return nil, nil
+	}
+	return []*entity.PartnerAccount{m.account}, nil
+}
+
 func (m *mockSyncPartnerRepo) FindByPartnerID(ctx context.Context, partnerID string) (*entity.PartnerAccount, error) {
 	return nil, nil
 }
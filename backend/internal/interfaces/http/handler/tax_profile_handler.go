@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// TaxProfileHandler manages the partner's tax-on-fees jurisdiction profiles
+type TaxProfileHandler struct {
+	taxProfileService *service.TaxProfileService
+	partnerRepo       repository.PartnerAccountRepository
+}
+
+// NewTaxProfileHandler creates a new TaxProfileHandler
+func NewTaxProfileHandler(
+	taxProfileService *service.TaxProfileService,
+	partnerRepo repository.PartnerAccountRepository,
+) *TaxProfileHandler {
+	return &TaxProfileHandler{
+		taxProfileService: taxProfileService,
+		partnerRepo:       partnerRepo,
+	}
+}
+
+// TaxProfileRequest is the request/response body for a tax profile
+type TaxProfileRequest struct {
+	ID                 string  `json:"id,omitempty"`
+	Jurisdiction       string  `json:"jurisdiction"`        // e.g. CA-ON, AU, IE
+	TaxName            string  `json:"tax_name"`            // GST, VAT, HST
+	Rate               float64 `json:"rate"`                // 0.13 for 13%
+	RegistrationNumber string  `json:"registration_number"` // Optional
+	EffectiveFrom      string  `json:"effective_from"`      // YYYY-MM-DD
+	EffectiveTo        *string `json:"effective_to"`        // YYYY-MM-DD (exclusive), null = open-ended
+}
+
+// List handles GET /api/v1/tax-profiles
+func (h *TaxProfileHandler) List(w http.ResponseWriter, r *http.Request) {
+	partnerAccount, herr := h.getPartnerAccount(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	profiles, err := h.taxProfileService.ListProfiles(r.Context(), partnerAccount.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch tax profiles")
+		return
+	}
+
+	response := make([]TaxProfileRequest, len(profiles))
+	for i, p := range profiles {
+		response[i] = toTaxProfileResponse(p)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"tax_profiles": response,
+	})
+}
+
+// Create handles POST /api/v1/tax-profiles
+func (h *TaxProfileHandler) Create(w http.ResponseWriter, r *http.Request) {
+	partnerAccount, herr := h.getPartnerAccount(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	var req TaxProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	profile := entity.NewTaxJurisdictionProfile(partnerAccount.ID, req.Jurisdiction, req.TaxName, req.Rate, time.Time{})
+	if err := applyTaxProfileRequest(profile, req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.taxProfileService.CreateProfile(r.Context(), profile); err != nil {
+		writeTaxProfileError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toTaxProfileResponse(profile))
+}
+
+// Update handles PUT /api/v1/tax-profiles/{profileID}
+func (h *TaxProfileHandler) Update(w http.ResponseWriter, r *http.Request) {
+	partnerAccount, herr := h.getPartnerAccount(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	profileID, err := uuid.Parse(chi.URLParam(r, "profileID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid profile ID")
+		return
+	}
+
+	var req TaxProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	profile := entity.NewTaxJurisdictionProfile(partnerAccount.ID, req.Jurisdiction, req.TaxName, req.Rate, time.Time{})
+	profile.ID = profileID
+	if err := applyTaxProfileRequest(profile, req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.taxProfileService.UpdateProfile(r.Context(), profile); err != nil {
+		writeTaxProfileError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toTaxProfileResponse(profile))
+}
+
+// Delete handles DELETE /api/v1/tax-profiles/{profileID}
+func (h *TaxProfileHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	partnerAccount, herr := h.getPartnerAccount(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	profileID, err := uuid.Parse(chi.URLParam(r, "profileID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid profile ID")
+		return
+	}
+
+	if err := h.taxProfileService.DeleteProfile(r.Context(), partnerAccount.ID, profileID); err != nil {
+		writeTaxProfileError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaxProfileHandler) getPartnerAccount(r *http.Request) (*entity.PartnerAccount, *subHandlerError) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		return nil, &subHandlerError{statusCode: http.StatusUnauthorized, message: "authentication required"}
+	}
+
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
+	if err != nil {
+		return nil, partnerAccountError(err, http.StatusNotFound, "no partner account found")
+	}
+
+	return partnerAccount, nil
+}
+
+// applyTaxProfileRequest parses the request's dates onto the profile
+func applyTaxProfileRequest(profile *entity.TaxJurisdictionProfile, req TaxProfileRequest) error {
+	profile.RegistrationNumber = req.RegistrationNumber
+
+	if req.EffectiveFrom == "" {
+		return errors.New("effective_from is required (YYYY-MM-DD)")
+	}
+	from, err := time.Parse("2006-01-02", req.EffectiveFrom)
+	if err != nil {
+		return errors.New("invalid effective_from format (expected YYYY-MM-DD)")
+	}
+	profile.EffectiveFrom = from
+
+	if req.EffectiveTo != nil && *req.EffectiveTo != "" {
+		to, err := time.Parse("2006-01-02", *req.EffectiveTo)
+		if err != nil {
+			return errors.New("invalid effective_to format (expected YYYY-MM-DD)")
+		}
+		profile.EffectiveTo = &to
+	}
+
+	return nil
+}
+
+func writeTaxProfileError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidTaxRate),
+		errors.Is(err, entity.ErrInvalidEffectivePeriod),
+		errors.Is(err, entity.ErrJurisdictionRequired):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrOverlappingTaxProfile):
+		writeJSONError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrTaxProfileNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	default:
+		writeJSONError(w, http.StatusInternalServerError, "failed to save tax profile")
+	}
+}
+
+func toTaxProfileResponse(p *entity.TaxJurisdictionProfile) TaxProfileRequest {
+	resp := TaxProfileRequest{
+		ID:                 p.ID.String(),
+		Jurisdiction:       p.Jurisdiction,
+		TaxName:            p.TaxName,
+		Rate:               p.Rate,
+		RegistrationNumber: p.RegistrationNumber,
+		EffectiveFrom:      p.EffectiveFrom.Format("2006-01-02"),
+	}
+	if p.EffectiveTo != nil {
+		to := p.EffectiveTo.Format("2006-01-02")
+		resp.EffectiveTo = &to
+	}
+	return resp
+}
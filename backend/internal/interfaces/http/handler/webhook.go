@@ -1,18 +1,25 @@
 package handler
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
 )
 
 // WebhookHandler handles incoming Shopify webhooks
 type WebhookHandler struct {
-	webhookService *service.WebhookService
+	webhookService  *service.WebhookService
+	deliveryService *service.WebhookDeliveryService
 }
 
 func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
@@ -21,46 +28,63 @@ func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
 	}
 }
 
+// WithDeliveryService stores verified webhooks for background processing instead
+// of processing them within the request
+func (h *WebhookHandler) WithDeliveryService(deliveryService *service.WebhookDeliveryService) *WebhookHandler {
+	h.deliveryService = deliveryService
+	return h
+}
+
 // HandleWebhook processes incoming Shopify webhook events
-// POST /webhooks/shopify
+// POST /webhooks/shopify?app_id={appID}
 func (h *WebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
-	// Read the body for HMAC validation
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.Printf("Webhook: failed to read body: %v", err)
-		writeJSONError(w, http.StatusBadRequest, "failed to read request body")
-		return
-	}
-
-	// Get required headers
 	topic := r.Header.Get("X-Shopify-Topic")
-	shopID := r.Header.Get("X-Shopify-Shop-Domain")
-	hmacSignature := r.Header.Get("X-Shopify-Hmac-Sha256")
-	appID := r.Header.Get("X-Shopify-API-Version") // We'll use shop domain to look up app
-
 	if topic == "" {
 		log.Printf("Webhook: missing X-Shopify-Topic header")
 		writeJSONError(w, http.StatusBadRequest, "missing topic header")
 		return
 	}
 
-	// Note: In production, validate HMAC using the webhook secret
-	// For now, we log but don't reject to support development
-	if hmacSignature == "" {
-		log.Printf("Webhook: warning - missing HMAC signature for topic %s", topic)
+	h.receive(w, r, topic, h.webhookService.ProcessEvent)
+}
+
+// HandleSubscriptionUpdate handles subscription update webhooks
+// POST /webhooks/shopify/subscriptions?app_id={appID}
+func (h *WebhookHandler) HandleSubscriptionUpdate(w http.ResponseWriter, r *http.Request) {
+	h.receive(w, r, "app_subscriptions/update", h.webhookService.ProcessSubscriptionUpdate)
+}
+
+// HandleAppUninstalled handles app uninstallation webhooks
+// POST /webhooks/shopify/uninstalled?app_id={appID}
+func (h *WebhookHandler) HandleAppUninstalled(w http.ResponseWriter, r *http.Request) {
+	h.receive(w, r, "app/uninstalled", h.webhookService.ProcessAppUninstalled)
+}
+
+// HandleBillingFailure handles billing failure webhooks
+// POST /webhooks/shopify/billing-failure?app_id={appID}
+func (h *WebhookHandler) HandleBillingFailure(w http.ResponseWriter, r *http.Request) {
+	h.receive(w, r, "subscription_billing_attempts/failure", h.webhookService.ProcessBillingFailure)
+}
+
+// receive verifies a webhook and either queues it or, without a delivery
+// service, processes it within the request
+func (h *WebhookHandler) receive(
+	w http.ResponseWriter,
+	r *http.Request,
+	topic string,
+	process func(ctx context.Context, event service.WebhookEvent) error,
+) {
+	event, ok := h.readVerifiedEvent(w, r, topic)
+	if !ok {
+		return
 	}
 
-	// Build webhook event
-	event := service.WebhookEvent{
-		Topic:     topic,
-		ShopID:    shopID,
-		AppID:     appID,
-		Payload:   body,
-		Timestamp: time.Now().UTC(),
+	if h.deliveryService != nil {
+		h.enqueue(w, r, event)
+		return
 	}
 
-	// Process the webhook
-	if err := h.webhookService.ProcessEvent(r.Context(), event); err != nil {
+	if err := process(r.Context(), *event); err != nil {
 		log.Printf("Webhook: failed to process event (topic=%s): %v", topic, err)
 		// Return 200 to prevent Shopify from retrying
 		// Log the error for investigation
@@ -68,80 +92,83 @@ func (h *WebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("Webhook: processed event (topic=%s, shop=%s)", topic, shopID)
+	log.Printf("Webhook: processed event (topic=%s, shop=%s)", topic, event.ShopID)
 	w.WriteHeader(http.StatusOK)
 }
 
-// HandleSubscriptionUpdate handles subscription update webhooks
-// POST /webhooks/shopify/subscriptions
-func (h *WebhookHandler) HandleSubscriptionUpdate(w http.ResponseWriter, r *http.Request) {
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		writeJSONError(w, http.StatusBadRequest, "failed to read request body")
+// enqueue stores the delivery and acknowledges it. Shopify retries deliveries
+// with the same X-Shopify-Webhook-Id, which are acknowledged without storing.
+func (h *WebhookHandler) enqueue(w http.ResponseWriter, r *http.Request, event *service.WebhookEvent) {
+	if !json.Valid(event.Payload) {
+		writeJSONError(w, http.StatusBadRequest, "webhook body must be JSON")
 		return
 	}
 
-	event := service.WebhookEvent{
-		Topic:     "app_subscriptions/update",
-		ShopID:    r.Header.Get("X-Shopify-Shop-Domain"),
-		AppID:     r.Header.Get("X-Shopify-Webhook-Id"),
-		Payload:   body,
-		Timestamp: time.Now().UTC(),
+	webhookID := r.Header.Get("X-Shopify-Webhook-Id")
+	if webhookID == "" {
+		// Identical bodies of the same topic are treated as the same delivery
+		sum := sha256.Sum256(append([]byte(event.Topic+"\n"), event.Payload...))
+		webhookID = "sha256:" + hex.EncodeToString(sum[:])
 	}
 
-	if err := h.webhookService.ProcessSubscriptionUpdate(r.Context(), event); err != nil {
-		log.Printf("Webhook: subscription update failed: %v", err)
+	delivery := entity.NewWebhookDelivery(webhookID, event.Topic, event.ShopID, event.AppID, event.Payload, event.Timestamp)
+	created, err := h.deliveryService.Enqueue(r.Context(), delivery)
+	if err != nil {
+		// Shopify retries non-2xx responses, so the delivery is not lost
+		log.Printf("Webhook: failed to store delivery (topic=%s): %v", event.Topic, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to store webhook")
+		return
 	}
 
+	if !created {
+		log.Printf("Webhook: ignored duplicate delivery %s (topic=%s)", webhookID, event.Topic)
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
-// HandleAppUninstalled handles app uninstallation webhooks
-// POST /webhooks/shopify/uninstalled
-func (h *WebhookHandler) HandleAppUninstalled(w http.ResponseWriter, r *http.Request) {
+// readVerifiedEvent reads the webhook body and checks its X-Shopify-Hmac-Sha256
+// signature. The app is named by the app_id query parameter of the subscribed
+// webhook URL (numeric Shopify app ID or app GID); without it only the app client
+// secret is tried. Writes the error response and returns false on failure.
+func (h *WebhookHandler) readVerifiedEvent(w http.ResponseWriter, r *http.Request, topic string) (*service.WebhookEvent, bool) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
+		log.Printf("Webhook: failed to read body: %v", err)
 		writeJSONError(w, http.StatusBadRequest, "failed to read request body")
-		return
+		return nil, false
 	}
 
-	event := service.WebhookEvent{
-		Topic:     "app/uninstalled",
-		ShopID:    r.Header.Get("X-Shopify-Shop-Domain"),
-		AppID:     r.Header.Get("X-Shopify-Webhook-Id"),
-		Payload:   body,
-		Timestamp: time.Now().UTC(),
+	appID := r.URL.Query().Get("app_id")
+	if appID != "" && !strings.HasPrefix(appID, appGIDPrefix) {
+		appID = appGIDPrefix + appID
 	}
 
-	if err := h.webhookService.ProcessAppUninstalled(r.Context(), event); err != nil {
-		log.Printf("Webhook: app uninstalled failed: %v", err)
+	err = h.webhookService.ValidateHMAC(r.Context(), appID, body, r.Header.Get("X-Shopify-Hmac-Sha256"))
+	if errors.Is(err, service.ErrInvalidWebhookSignature) {
+		log.Printf("Webhook: rejected invalid signature (topic=%s, app=%s)", topic, appID)
+		writeJSONError(w, http.StatusUnauthorized, "invalid webhook signature")
+		return nil, false
 	}
-
-	w.WriteHeader(http.StatusOK)
-}
-
-// HandleBillingFailure handles billing failure webhooks
-// POST /webhooks/shopify/billing-failure
-func (h *WebhookHandler) HandleBillingFailure(w http.ResponseWriter, r *http.Request) {
-	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		writeJSONError(w, http.StatusBadRequest, "failed to read request body")
-		return
+		// Shopify retries non-2xx responses, so a transient failure is not lost
+		log.Printf("Webhook: failed to verify signature (topic=%s): %v", topic, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to verify webhook signature")
+		return nil, false
 	}
 
-	event := service.WebhookEvent{
-		Topic:     "subscription_billing_attempts/failure",
-		ShopID:    r.Header.Get("X-Shopify-Shop-Domain"),
-		AppID:     r.Header.Get("X-Shopify-Webhook-Id"),
-		Payload:   body,
-		Timestamp: time.Now().UTC(),
+	// Events are ordered by when Shopify raised them, not when they arrive
+	timestamp := time.Now().UTC()
+	if triggeredAt, err := time.Parse(time.RFC3339Nano, r.Header.Get("X-Shopify-Triggered-At")); err == nil {
+		timestamp = triggeredAt.UTC()
 	}
 
-	if err := h.webhookService.ProcessBillingFailure(r.Context(), event); err != nil {
-		log.Printf("Webhook: billing failure processing failed: %v", err)
-	}
-
-	w.WriteHeader(http.StatusOK)
+	return &service.WebhookEvent{
+		Topic:     topic,
+		ShopID:    r.Header.Get("X-Shopify-Shop-Domain"),
+		AppID:     appID,
+		Payload:   body,
+		Timestamp: timestamp,
+	}, true
 }
 
 // WebhookStats returns stats about processed webhooks
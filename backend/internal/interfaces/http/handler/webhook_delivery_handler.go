@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// WebhookDeliveryHandler lets admins inspect and replay the Shopify webhooks received for an app
+type WebhookDeliveryHandler struct {
+	deliveryService *service.WebhookDeliveryService
+	partnerRepo     repository.PartnerAccountRepository
+	appRepo         repository.AppRepository
+}
+
+// NewWebhookDeliveryHandler creates a new WebhookDeliveryHandler
+func NewWebhookDeliveryHandler(
+	deliveryService *service.WebhookDeliveryService,
+	partnerRepo repository.PartnerAccountRepository,
+	appRepo repository.AppRepository,
+) *WebhookDeliveryHandler {
+	return &WebhookDeliveryHandler{
+		deliveryService: deliveryService,
+		partnerRepo:     partnerRepo,
+		appRepo:         appRepo,
+	}
+}
+
+// WebhookDeliveryResponse represents an inbound webhook delivery in API responses
+type WebhookDeliveryResponse struct {
+	ID            string          `json:"id"`
+	WebhookID     string          `json:"webhook_id"`
+	Topic         string          `json:"topic"`
+	ShopDomain    string          `json:"shop_domain"`
+	Status        string          `json:"status"` // PENDING, PROCESSED, SKIPPED, FAILED
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	TriggeredAt   string          `json:"triggered_at"`
+	ReceivedAt    string          `json:"received_at"`
+	NextAttemptAt *string         `json:"next_attempt_at"`
+	ProcessedAt   *string         `json:"processed_at"`
+	Payload       json.RawMessage `json:"payload,omitempty"` // Single delivery only
+}
+
+// List handles GET /api/v1/apps/{appID}/webhook-deliveries?status=&topic=&limit=
+func (h *WebhookDeliveryHandler) List(w http.ResponseWriter, r *http.Request) {
+	app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	filter := repository.WebhookDeliveryFilter{
+		AppGID: app.PartnerAppID,
+		Status: entity.WebhookDeliveryStatus(r.URL.Query().Get("status")),
+		Topic:  r.URL.Query().Get("topic"),
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		writeJSONError(w, http.StatusBadRequest, "status must be one of PENDING, PROCESSED, SKIPPED, FAILED")
+		return
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeJSONError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	deliveries, err := h.deliveryService.ListDeliveries(r.Context(), filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch webhook deliveries")
+		return
+	}
+
+	response := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		response[i] = toWebhookDeliveryResponse(d, false)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"deliveries": response,
+	})
+}
+
+// Get handles GET /api/v1/apps/{appID}/webhook-deliveries/{deliveryID}
+func (h *WebhookDeliveryHandler) Get(w http.ResponseWriter, r *http.Request) {
+	app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid delivery ID")
+		return
+	}
+
+	delivery, err := h.deliveryService.GetDelivery(r.Context(), app.PartnerAppID, deliveryID)
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toWebhookDeliveryResponse(delivery, true))
+}
+
+// Replay handles POST /api/v1/apps/{appID}/webhook-deliveries/{deliveryID}/replay
+func (h *WebhookDeliveryHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid delivery ID")
+		return
+	}
+
+	delivery, err := h.deliveryService.Replay(r.Context(), app.PartnerAppID, deliveryID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWebhookDeliveryNotFound):
+			writeJSONError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrWebhookDeliveryInProgress):
+			writeJSONError(w, http.StatusConflict, err.Error())
+		default:
+			writeJSONError(w, http.StatusInternalServerError, "failed to replay webhook delivery")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toWebhookDeliveryResponse(delivery, false))
+}
+
+// getAppFromRequest resolves the app from the numeric Shopify app ID in the URL
+func (h *WebhookDeliveryHandler) getAppFromRequest(r *http.Request) (*entity.App, *subHandlerError) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		return nil, &subHandlerError{statusCode: http.StatusUnauthorized, message: "authentication required"}
+	}
+
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
+	if err != nil {
+		return nil, partnerAccountError(err, http.StatusNotFound, "no partner account found")
+	}
+
+	appIDStr := chi.URLParam(r, "appID")
+	if appIDStr == "" {
+		return nil, &subHandlerError{statusCode: http.StatusBadRequest, message: "app ID is required"}
+	}
+
+	app, err := h.appRepo.FindByPartnerAppID(r.Context(), partnerAccount.ID, appGIDPrefix+appIDStr)
+	if err != nil {
+		return nil, &subHandlerError{statusCode: http.StatusNotFound, message: "app not found"}
+	}
+
+	return app, nil
+}
+
+func toWebhookDeliveryResponse(d *entity.WebhookDelivery, withPayload bool) WebhookDeliveryResponse {
+	resp := WebhookDeliveryResponse{
+		ID:          d.ID.String(),
+		WebhookID:   d.WebhookID,
+		Topic:       d.Topic,
+		ShopDomain:  d.ShopDomain,
+		Status:      string(d.Status),
+		Attempts:    d.Attempts,
+		LastError:   d.LastError,
+		TriggeredAt: d.TriggeredAt.Format(time.RFC3339),
+		ReceivedAt:  d.ReceivedAt.Format(time.RFC3339),
+	}
+	if d.NextAttemptAt != nil && d.Status == entity.WebhookDeliveryPending {
+		next := d.NextAttemptAt.Format(time.RFC3339)
+		resp.NextAttemptAt = &next
+	}
+	if d.ProcessedAt != nil {
+		processed := d.ProcessedAt.Format(time.RFC3339)
+		resp.ProcessedAt = &processed
+	}
+	if withPayload {
+		resp.Payload = d.Payload
+	}
+	return resp
+}
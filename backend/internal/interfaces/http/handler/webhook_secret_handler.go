@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// WebhookSecretHandler manages the secrets Shopify signs an app's webhooks with
+type WebhookSecretHandler struct {
+	secretService *service.WebhookSecretService
+	partnerRepo   repository.PartnerAccountRepository
+	appRepo       repository.AppRepository
+}
+
+// NewWebhookSecretHandler creates a new WebhookSecretHandler
+func NewWebhookSecretHandler(
+	secretService *service.WebhookSecretService,
+	partnerRepo repository.PartnerAccountRepository,
+	appRepo repository.AppRepository,
+) *WebhookSecretHandler {
+	return &WebhookSecretHandler{
+		secretService: secretService,
+		partnerRepo:   partnerRepo,
+		appRepo:       appRepo,
+	}
+}
+
+// CreateWebhookSecretRequest is the request body for adding a webhook secret
+type CreateWebhookSecretRequest struct {
+	Secret string `json:"secret"`
+	Label  string `json:"label"`
+	// Rotation: expire the app's other secrets after this many hours (0 = now).
+	// Omitted keeps them active alongside the new secret.
+	RetireExistingAfterHours *int `json:"retire_existing_after_hours"`
+}
+
+// WebhookSecretResponse represents a webhook secret in API responses. The secret
+// itself is never returned.
+type WebhookSecretResponse struct {
+	ID         string  `json:"id"`
+	Label      string  `json:"label"`
+	SecretHint string  `json:"secret_hint"` // Last 4 characters
+	Active     bool    `json:"active"`
+	ExpiresAt  *string `json:"expires_at"`
+	LastUsedAt *string `json:"last_used_at"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+// List handles GET /api/v1/apps/{appID}/webhook-secrets
+func (h *WebhookSecretHandler) List(w http.ResponseWriter, r *http.Request) {
+	app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	secrets, err := h.secretService.ListSecrets(r.Context(), app.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch webhook secrets")
+		return
+	}
+
+	now := time.Now().UTC()
+	response := make([]WebhookSecretResponse, len(secrets))
+	for i, s := range secrets {
+		response[i] = toWebhookSecretResponse(s, now)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"webhook_secrets": response,
+	})
+}
+
+// Create handles POST /api/v1/apps/{appID}/webhook-secrets
+func (h *WebhookSecretHandler) Create(w http.ResponseWriter, r *http.Request) {
+	app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	var req CreateWebhookSecretRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var retireAfter *time.Duration
+	if req.RetireExistingAfterHours != nil {
+		if *req.RetireExistingAfterHours < 0 {
+			writeJSONError(w, http.StatusBadRequest, "retire_existing_after_hours must not be negative")
+			return
+		}
+		d := time.Duration(*req.RetireExistingAfterHours) * time.Hour
+		retireAfter = &d
+	}
+
+	secret, err := h.secretService.AddSecret(r.Context(), app.ID, req.Secret, req.Label, retireAfter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidWebhookSecret) {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSONError(w, http.StatusInternalServerError, "failed to save webhook secret")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toWebhookSecretResponse(secret, time.Now().UTC()))
+}
+
+// Revoke handles DELETE /api/v1/apps/{appID}/webhook-secrets/{secretID}
+func (h *WebhookSecretHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	app, herr := h.getAppFromRequest(r)
+	if herr != nil {
+		writeJSONError(w, herr.statusCode, herr.message)
+		return
+	}
+
+	secretID, err := uuid.Parse(chi.URLParam(r, "secretID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid secret ID")
+		return
+	}
+
+	if err := h.secretService.RevokeSecret(r.Context(), app.ID, secretID); err != nil {
+		if errors.Is(err, service.ErrWebhookSecretNotFound) {
+			writeJSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSONError(w, http.StatusInternalServerError, "failed to revoke webhook secret")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getAppFromRequest resolves the app from the numeric Shopify app ID in the URL
+func (h *WebhookSecretHandler) getAppFromRequest(r *http.Request) (*entity.App, *subHandlerError) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		return nil, &subHandlerError{statusCode: http.StatusUnauthorized, message: "authentication required"}
+	}
+
+	partnerAccount, err := partnerAccountForRequest(r, h.partnerRepo, user.ID)
+	if err != nil {
+		return nil, partnerAccountError(err, http.StatusNotFound, "no partner account found")
+	}
+
+	appIDStr := chi.URLParam(r, "appID")
+	if appIDStr == "" {
+		return nil, &subHandlerError{statusCode: http.StatusBadRequest, message: "app ID is required"}
+	}
+
+	app, err := h.appRepo.FindByPartnerAppID(r.Context(), partnerAccount.ID, appGIDPrefix+appIDStr)
+	if err != nil {
+		return nil, &subHandlerError{statusCode: http.StatusNotFound, message: "app not found"}
+	}
+
+	return app, nil
+}
+
+func toWebhookSecretResponse(s *entity.WebhookSecret, now time.Time) WebhookSecretResponse {
+	resp := WebhookSecretResponse{
+		ID:         s.ID.String(),
+		Label:      s.Label,
+		SecretHint: s.SecretHint,
+		Active:     s.IsActive(now),
+		CreatedAt:  s.CreatedAt.Format(time.RFC3339),
+	}
+	if s.ExpiresAt != nil {
+		expiresAt := s.ExpiresAt.Format(time.RFC3339)
+		resp.ExpiresAt = &expiresAt
+	}
+	if s.LastUsedAt != nil {
+		lastUsedAt := s.LastUsedAt.Format(time.RFC3339)
+		resp.LastUsedAt = &lastUsedAt
+	}
+	return resp
+}
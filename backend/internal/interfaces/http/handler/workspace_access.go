@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// errWorkspaceAccessDenied is returned when the member's workspace role is too low for the request
+var errWorkspaceAccessDenied = errors.New("insufficient workspace role")
+
+// requiredWorkspaceRole returns the workspace role a request needs: any
+// member may read, changes take an ADMIN
+func requiredWorkspaceRole(r *http.Request) valueobject.Role {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return valueobject.RoleViewer
+	default:
+		return valueobject.RoleAdmin
+	}
+}
+
+// partnerAccountForRequest resolves the partner account a request acts on,
+// checking the member's workspace role against the request method
+func partnerAccountForRequest(r *http.Request, repo repository.PartnerAccountRepository, userID uuid.UUID) (*entity.PartnerAccount, error) {
+	return partnerAccountForRole(r.Context(), repo, userID, requiredWorkspaceRole(r))
+}
+
+// partnerAccountForRole resolves the partner account a request acts on. Inside
+// a workspace it is the workspace's account, provided the member's role is at
+// least minRole; without workspaces it is the user's own account.
+func partnerAccountForRole(ctx context.Context, repo repository.PartnerAccountRepository, userID uuid.UUID, minRole valueobject.Role) (*entity.PartnerAccount, error) {
+	member := middleware.WorkspaceFromContext(ctx)
+	if member == nil {
+		return repo.FindByUserID(ctx, userID)
+	}
+	if !member.Role.AtLeast(minRole) {
+		return nil, errWorkspaceAccessDenied
+	}
+	return repo.FindByWorkspaceID(ctx, member.WorkspaceID)
+}
+
+// partnerAccountError maps a partnerAccountFor* error to a response
+func partnerAccountError(err error, status int, message string) *subHandlerError {
+	if errors.Is(err, errWorkspaceAccessDenied) {
+		return &subHandlerError{statusCode: http.StatusForbidden, message: "insufficient permissions"}
+	}
+	return &subHandlerError{statusCode: status, message: message}
+}
+
+// writePartnerAccountError writes a partnerAccountFor* error
+func writePartnerAccountError(w http.ResponseWriter, err error, status int, message string) {
+	e := partnerAccountError(err, status, message)
+	writeJSONError(w, e.statusCode, e.message)
+}
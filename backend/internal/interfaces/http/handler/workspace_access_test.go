@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+func TestPartnerAccountForRequest_WorkspaceRoles(t *testing.T) {
+	account := &entity.PartnerAccount{ID: uuid.New(), PartnerID: "partner-123"}
+	repo := &mockPartnerRepoForApp{account: account}
+
+	tests := []struct {
+		name       string
+		role       valueobject.Role
+		method     string
+		wantDenied bool
+	}{
+		{"viewer reads", valueobject.RoleViewer, http.MethodGet, false},
+		{"viewer cannot write", valueobject.RoleViewer, http.MethodPost, true},
+		{"analyst cannot write", valueobject.RoleAnalyst, http.MethodDelete, true},
+		{"admin writes", valueobject.RoleAdmin, http.MethodPost, false},
+		{"owner writes", valueobject.RoleOwner, http.MethodPut, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/apps", nil)
+			member := &entity.WorkspaceMember{WorkspaceID: uuid.New(), UserID: uuid.New(), Role: tt.role}
+			req = req.WithContext(middleware.SetWorkspaceContext(req.Context(), member))
+
+			got, err := partnerAccountForRequest(req, repo, member.UserID)
+			if tt.wantDenied {
+				if !errors.Is(err, errWorkspaceAccessDenied) {
+					t.Fatalf("expected errWorkspaceAccessDenied, got %v", err)
+				}
+				if e := partnerAccountError(err, http.StatusNotFound, "no partner account found"); e.statusCode != http.StatusForbidden {
+					t.Errorf("expected status 403, got %d", e.statusCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != account {
+				t.Errorf("expected the workspace partner account")
+			}
+		})
+	}
+}
+
+func TestPartnerAccountForRequest_WithoutWorkspace(t *testing.T) {
+	account := &entity.PartnerAccount{ID: uuid.New()}
+	repo := &mockPartnerRepoForApp{account: account}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/apps", nil)
+	got, err := partnerAccountForRequest(req, repo, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != account {
+		t.Errorf("expected the user's partner account")
+	}
+}
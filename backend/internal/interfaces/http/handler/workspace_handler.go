@@ -0,0 +1,447 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+)
+
+// WorkspaceHandler manages team workspaces. Member and invitation endpoints
+// act on the workspace selected for the request (see middleware.WorkspaceHeader).
+type WorkspaceHandler struct {
+	workspaceService *service.WorkspaceService
+}
+
+// NewWorkspaceHandler creates a new WorkspaceHandler
+func NewWorkspaceHandler(workspaceService *service.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{workspaceService: workspaceService}
+}
+
+// WorkspaceRequest is the request body for creating or renaming a workspace
+type WorkspaceRequest struct {
+	Name string `json:"name"`
+}
+
+// WorkspaceMemberRoleRequest is the request body for changing a member's role
+type WorkspaceMemberRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// WorkspaceInvitationRequest is the request body for inviting a member
+type WorkspaceInvitationRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// AcceptWorkspaceInvitationRequest is the request body for accepting an invitation
+type AcceptWorkspaceInvitationRequest struct {
+	Token string `json:"token"`
+}
+
+// WorkspaceResponse represents a workspace and the user's role in it
+type WorkspaceResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// WorkspaceMemberResponse represents a workspace member in API responses
+type WorkspaceMemberResponse struct {
+	WorkspaceID string `json:"workspace_id"`
+	UserID      string `json:"user_id"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+	JoinedAt    string `json:"joined_at"`
+}
+
+// WorkspaceInvitationResponse represents an invitation in API responses. The
+// token is only returned when the invitation is created, so it can be shared
+// if the invitation email does not arrive.
+type WorkspaceInvitationResponse struct {
+	ID         string  `json:"id"`
+	Email      string  `json:"email"`
+	Role       string  `json:"role"`
+	Status     string  `json:"status"` // PENDING, ACCEPTED, REVOKED or EXPIRED
+	Token      string  `json:"token,omitempty"`
+	ExpiresAt  string  `json:"expires_at"`
+	AcceptedAt *string `json:"accepted_at"`
+	RevokedAt  *string `json:"revoked_at"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+// List handles GET /api/v1/workspaces
+func (h *WorkspaceHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	workspaces, err := h.workspaceService.ListWorkspaces(r.Context(), user.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch workspaces")
+		return
+	}
+
+	response := make([]WorkspaceResponse, len(workspaces))
+	for i, uw := range workspaces {
+		response[i] = toWorkspaceResponse(uw.Workspace, uw.Role)
+	}
+
+	var current *string
+	if member := middleware.WorkspaceFromContext(r.Context()); member != nil {
+		id := member.WorkspaceID.String()
+		current = &id
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"workspaces":           response,
+		"current_workspace_id": current,
+	})
+}
+
+// Create handles POST /api/v1/workspaces. The user becomes its owner.
+func (h *WorkspaceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req WorkspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	workspace, err := h.workspaceService.CreateWorkspace(r.Context(), user, req.Name)
+	if err != nil {
+		writeWorkspaceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toWorkspaceResponse(workspace, valueobject.RoleOwner))
+}
+
+// AcceptInvitation handles POST /api/v1/workspaces/invitations/accept
+func (h *WorkspaceHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req AcceptWorkspaceInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	member, err := h.workspaceService.AcceptInvitation(r.Context(), user, req.Token)
+	if err != nil {
+		writeWorkspaceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toWorkspaceMemberResponse(member))
+}
+
+// GetCurrent handles GET /api/v1/workspace
+func (h *WorkspaceHandler) GetCurrent(w http.ResponseWriter, r *http.Request) {
+	member, ok := currentWorkspaceMember(w, r)
+	if !ok {
+		return
+	}
+
+	workspace, err := h.workspaceService.GetWorkspace(r.Context(), member.WorkspaceID)
+	if err != nil {
+		writeWorkspaceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toWorkspaceResponse(workspace, member.Role))
+}
+
+// UpdateCurrent handles PUT /api/v1/workspace (ADMIN)
+func (h *WorkspaceHandler) UpdateCurrent(w http.ResponseWriter, r *http.Request) {
+	member, ok := currentWorkspaceMember(w, r)
+	if !ok {
+		return
+	}
+
+	var req WorkspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	workspace, err := h.workspaceService.RenameWorkspace(r.Context(), member.WorkspaceID, req.Name)
+	if err != nil {
+		writeWorkspaceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toWorkspaceResponse(workspace, member.Role))
+}
+
+// ListMembers handles GET /api/v1/workspace/members
+func (h *WorkspaceHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	member, ok := currentWorkspaceMember(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := h.workspaceService.ListMembers(r.Context(), member.WorkspaceID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch workspace members")
+		return
+	}
+
+	response := make([]WorkspaceMemberResponse, len(members))
+	for i, m := range members {
+		response[i] = toWorkspaceMemberResponse(m)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"members": response,
+	})
+}
+
+// UpdateMember handles PUT /api/v1/workspace/members/{userID} (ADMIN)
+func (h *WorkspaceHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	actor, ok := currentWorkspaceMember(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	var req WorkspaceMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	member, err := h.workspaceService.UpdateMemberRole(r.Context(), actor, userID, valueobject.Role(req.Role))
+	if err != nil {
+		writeWorkspaceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toWorkspaceMemberResponse(member))
+}
+
+// RemoveMember handles DELETE /api/v1/workspace/members/{userID} (ADMIN)
+func (h *WorkspaceHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	actor, ok := currentWorkspaceMember(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	if err := h.workspaceService.RemoveMember(r.Context(), actor, userID); err != nil {
+		writeWorkspaceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Leave handles POST /api/v1/workspace/leave, removing the user from the current workspace
+func (h *WorkspaceHandler) Leave(w http.ResponseWriter, r *http.Request) {
+	actor, ok := currentWorkspaceMember(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.workspaceService.RemoveMember(r.Context(), actor, actor.UserID); err != nil {
+		writeWorkspaceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListInvitations handles GET /api/v1/workspace/invitations (ADMIN)
+func (h *WorkspaceHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	member, ok := currentWorkspaceMember(w, r)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.workspaceService.ListInvitations(r.Context(), member.WorkspaceID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to fetch workspace invitations")
+		return
+	}
+
+	now := time.Now().UTC()
+	response := make([]WorkspaceInvitationResponse, len(invitations))
+	for i, inv := range invitations {
+		response[i] = toWorkspaceInvitationResponse(inv, now)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"invitations": response,
+	})
+}
+
+// Invite handles POST /api/v1/workspace/invitations (ADMIN) and emails the invitation
+func (h *WorkspaceHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	actor, ok := currentWorkspaceMember(w, r)
+	if !ok {
+		return
+	}
+	user := middleware.UserFromContext(r.Context())
+
+	var req WorkspaceInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	invitation, err := h.workspaceService.Invite(r.Context(), actor, user.Email, req.Email, valueobject.Role(req.Role))
+	if err != nil {
+		writeWorkspaceError(w, err)
+		return
+	}
+
+	response := toWorkspaceInvitationResponse(invitation, time.Now().UTC())
+	response.Token = invitation.Token
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// RevokeInvitation handles DELETE /api/v1/workspace/invitations/{invitationID} (ADMIN)
+func (h *WorkspaceHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	member, ok := currentWorkspaceMember(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "invitationID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid invitation ID")
+		return
+	}
+
+	if _, err := h.workspaceService.RevokeInvitation(r.Context(), member.WorkspaceID, id); err != nil {
+		writeWorkspaceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// currentWorkspaceMember returns the membership the request acts under, or
+// writes an error if there is none
+func currentWorkspaceMember(w http.ResponseWriter, r *http.Request) (*entity.WorkspaceMember, bool) {
+	if middleware.UserFromContext(r.Context()) == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return nil, false
+	}
+	member := middleware.WorkspaceFromContext(r.Context())
+	if member == nil {
+		writeJSONError(w, http.StatusNotFound, "no workspace selected")
+		return nil, false
+	}
+	return member, true
+}
+
+func writeWorkspaceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidWorkspaceName),
+		errors.Is(err, entity.ErrInvalidWorkspaceRole),
+		errors.Is(err, entity.ErrInvalidEmailAddress),
+		errors.Is(err, service.ErrInvalidWorkspaceInvitation):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrInsufficientWorkspaceRole),
+		errors.Is(err, service.ErrWorkspaceRoleNotAllowed),
+		errors.Is(err, service.ErrWorkspaceInvitationEmailMismatch):
+		writeJSONError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrWorkspaceNotFound),
+		errors.Is(err, service.ErrWorkspaceMemberNotFound),
+		errors.Is(err, service.ErrWorkspaceInvitationNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrAlreadyWorkspaceMember),
+		errors.Is(err, service.ErrLastWorkspaceOwner),
+		errors.Is(err, entity.ErrWorkspaceInvitationClosed),
+		errors.Is(err, entity.ErrWorkspaceInvitationExpired):
+		writeJSONError(w, http.StatusConflict, err.Error())
+	default:
+		writeJSONError(w, http.StatusInternalServerError, "failed to save workspace")
+	}
+}
+
+func toWorkspaceResponse(workspace *entity.Workspace, role valueobject.Role) WorkspaceResponse {
+	return WorkspaceResponse{
+		ID:        workspace.ID.String(),
+		Name:      workspace.Name,
+		Role:      role.String(),
+		CreatedAt: workspace.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: workspace.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toWorkspaceMemberResponse(member *entity.WorkspaceMember) WorkspaceMemberResponse {
+	return WorkspaceMemberResponse{
+		WorkspaceID: member.WorkspaceID.String(),
+		UserID:      member.UserID.String(),
+		Email:       member.Email,
+		Role:        member.Role.String(),
+		JoinedAt:    member.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toWorkspaceInvitationResponse(invitation *entity.WorkspaceInvitation, now time.Time) WorkspaceInvitationResponse {
+	status := "PENDING"
+	switch {
+	case invitation.AcceptedAt != nil:
+		status = "ACCEPTED"
+	case invitation.RevokedAt != nil:
+		status = "REVOKED"
+	case !invitation.IsOpen(now):
+		status = "EXPIRED"
+	}
+
+	return WorkspaceInvitationResponse{
+		ID:         invitation.ID.String(),
+		Email:      invitation.Email,
+		Role:       invitation.Role.String(),
+		Status:     status,
+		ExpiresAt:  invitation.ExpiresAt.Format(time.RFC3339),
+		AcceptedAt: formatOptionalTime(invitation.AcceptedAt),
+		RevokedAt:  formatOptionalTime(invitation.RevokedAt),
+		CreatedAt:  invitation.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -22,6 +22,7 @@ const userContextKey contextKey = "user"
 type AuthMiddleware struct {
 	tokenVerifier service.AuthTokenVerifier
 	userRepo      repository.UserRepository
+	workspaces    WorkspaceResolver // Optional: see WithWorkspaces
 }
 
 func NewAuthMiddleware(tokenVerifier service.AuthTokenVerifier, userRepo repository.UserRepository) *AuthMiddleware {
@@ -61,6 +62,11 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 		}
 
 		ctx := context.WithValue(r.Context(), userContextKey, user)
+		if m.workspaces != nil {
+			if ctx = m.withWorkspace(w, r.WithContext(ctx), user); ctx == nil {
+				return
+			}
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
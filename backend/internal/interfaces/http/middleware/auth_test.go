@@ -226,3 +226,76 @@ func TestAuthMiddleware_CreateUserError(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
 	}
 }
+
+type mockWorkspaceResolver struct {
+	members map[uuid.UUID]*entity.WorkspaceMember // By workspace ID
+	def     *entity.WorkspaceMember
+}
+
+func (m *mockWorkspaceResolver) ResolveMembership(ctx context.Context, user *entity.User, workspaceID *uuid.UUID) (*entity.WorkspaceMember, error) {
+	if workspaceID == nil {
+		return m.def, nil
+	}
+	return m.members[*workspaceID], nil
+}
+
+func TestAuthMiddleware_WorkspaceRole(t *testing.T) {
+	user := &entity.User{ID: uuid.New(), FirebaseUID: "firebase-123", Role: valueobject.RoleOwner}
+	personal := &entity.WorkspaceMember{WorkspaceID: uuid.New(), UserID: user.ID, Role: valueobject.RoleOwner}
+	team := &entity.WorkspaceMember{WorkspaceID: uuid.New(), UserID: user.ID, Role: valueobject.RoleViewer}
+	resolver := &mockWorkspaceResolver{
+		members: map[uuid.UUID]*entity.WorkspaceMember{personal.WorkspaceID: personal, team.WorkspaceID: team},
+		def:     personal,
+	}
+	verifier := &mockTokenVerifier{claims: &service.TokenClaims{UID: "firebase-123"}}
+	middleware := NewAuthMiddleware(verifier, &mockUserRepository{user: user}).WithWorkspaces(resolver)
+
+	var ctxUser *entity.User
+	var ctxMember *entity.WorkspaceMember
+	handler := middleware.Authenticate(RequireRoles(valueobject.RoleAnalyst)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxUser = UserFromContext(r.Context())
+		ctxMember = WorkspaceFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantMember *entity.WorkspaceMember
+	}{
+		{"default workspace", "", http.StatusOK, personal},
+		{"selected workspace", personal.WorkspaceID.String(), http.StatusOK, personal},
+		{"viewer in selected workspace", team.WorkspaceID.String(), http.StatusForbidden, nil},
+		{"not a member", uuid.New().String(), http.StatusForbidden, nil},
+		{"invalid header", "not-a-uuid", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctxUser, ctxMember = nil, nil
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			if tt.header != "" {
+				req.Header.Set(WorkspaceHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if ctxMember != tt.wantMember {
+				t.Errorf("expected membership %+v, got %+v", tt.wantMember, ctxMember)
+			}
+			if tt.wantMember != nil && (ctxUser == nil || ctxUser.Role != tt.wantMember.Role) {
+				t.Errorf("expected user role %s, got %+v", tt.wantMember.Role, ctxUser)
+			}
+		})
+	}
+
+	if user.Role != valueobject.RoleOwner {
+		t.Errorf("expected stored user to keep its role, got %s", user.Role)
+	}
+}
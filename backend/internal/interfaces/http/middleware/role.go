@@ -9,7 +9,8 @@ import (
 )
 
 // RequireRoles returns middleware that checks if the user has one of the required roles.
-// OWNER role has access to all routes (superset of ADMIN).
+// Roles are hierarchical: OWNER has access to all routes, ADMIN to ANALYST routes,
+// and ANALYST to VIEWER routes.
 func RequireRoles(allowedRoles ...valueobject.Role) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -29,7 +30,7 @@ func RequireRoles(allowedRoles ...valueobject.Role) func(http.Handler) http.Hand
 	}
 }
 
-// hasRequiredRole checks if the user's role is in the allowed roles list.
+// hasRequiredRole checks if the user's role is, or outranks, one of the allowed roles.
 // OWNER has implicit access to all roles (superset).
 func hasRequiredRole(userRole valueobject.Role, allowedRoles []valueobject.Role) bool {
 	// OWNER has access to everything
@@ -37,9 +38,9 @@ func hasRequiredRole(userRole valueobject.Role, allowedRoles []valueobject.Role)
 		return true
 	}
 
-	// Check if user's role is in the allowed list
+	// Check if user's role satisfies any role in the allowed list
 	for _, role := range allowedRoles {
-		if userRole == role {
+		if userRole.AtLeast(role) {
 			return true
 		}
 	}
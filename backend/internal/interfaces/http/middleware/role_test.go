@@ -134,3 +134,37 @@ func TestRoleMiddleware_MultipleRolesAllowed(t *testing.T) {
 		t.Error("expected handler to be called")
 	}
 }
+
+func TestRoleMiddleware_RoleHierarchy(t *testing.T) {
+	// Route for analysts and above
+	middleware := RequireRoles(valueobject.RoleAnalyst)
+
+	tests := []struct {
+		role       valueobject.Role
+		wantStatus int
+	}{
+		{valueobject.RoleOwner, http.StatusOK},
+		{valueobject.RoleAdmin, http.StatusOK},
+		{valueobject.RoleAnalyst, http.StatusOK},
+		{valueobject.RoleViewer, http.StatusForbidden},
+		{valueobject.Role("UNKNOWN"), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role.String(), func(t *testing.T) {
+			handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(SetUserContext(req.Context(), &entity.User{Role: tt.role}))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+)
+
+// WorkspaceHeader selects the workspace a request acts in. Without it the
+// user's default (oldest) workspace is used.
+const WorkspaceHeader = "X-Workspace-ID"
+
+const workspaceContextKey contextKey = "workspace_member"
+
+// WorkspaceResolver resolves the workspace membership a request acts under
+type WorkspaceResolver interface {
+	// ResolveMembership returns the user's membership of workspaceID, or nil if
+	// they are not a member. A nil workspaceID selects the user's default
+	// workspace, which is created if the user has none.
+	ResolveMembership(ctx context.Context, user *entity.User, workspaceID *uuid.UUID) (*entity.WorkspaceMember, error)
+}
+
+// WithWorkspaces makes Authenticate resolve the request's workspace. The
+// membership is stored in the context and the user's role is replaced by
+// their role in the workspace, so RequireRoles enforces workspace roles.
+func (m *AuthMiddleware) WithWorkspaces(resolver WorkspaceResolver) *AuthMiddleware {
+	m.workspaces = resolver
+	return m
+}
+
+// withWorkspace resolves the workspace for an authenticated user and returns
+// the request context to continue with, or writes an error and returns nil
+func (m *AuthMiddleware) withWorkspace(w http.ResponseWriter, r *http.Request, user *entity.User) context.Context {
+	var workspaceID *uuid.UUID
+	if header := r.Header.Get(WorkspaceHeader); header != "" {
+		id, err := uuid.Parse(header)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid "+WorkspaceHeader+" header")
+			return nil
+		}
+		workspaceID = &id
+	}
+
+	member, err := m.workspaces.ResolveMembership(r.Context(), user, workspaceID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to resolve workspace")
+		return nil
+	}
+	if member == nil {
+		writeError(w, http.StatusForbidden, "not a member of this workspace")
+		return nil
+	}
+
+	scoped := *user
+	scoped.Role = member.Role
+
+	ctx := context.WithValue(r.Context(), workspaceContextKey, member)
+	return context.WithValue(ctx, userContextKey, &scoped)
+}
+
+// WorkspaceFromContext returns the workspace membership the request acts
+// under, or nil when workspaces are not enabled
+func WorkspaceFromContext(ctx context.Context) *entity.WorkspaceMember {
+	member, ok := ctx.Value(workspaceContextKey).(*entity.WorkspaceMember)
+	if !ok {
+		return nil
+	}
+	return member
+}
+
+// SetWorkspaceContext sets the workspace membership in context (exported for testing)
+func SetWorkspaceContext(ctx context.Context, member *entity.WorkspaceMember) context.Context {
+	return context.WithValue(ctx, workspaceContextKey, member)
+}
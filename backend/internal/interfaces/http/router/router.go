@@ -11,24 +11,39 @@ import (
 )
 
 type Config struct {
-	HealthHandler            *handler.HealthHandler
-	MeHandler                *handler.MeHandler
-	OAuthHandler             *handler.OAuthHandler
-	ManualTokenHandler       *handler.ManualTokenHandler
-	IntegrationStatusHandler *handler.IntegrationStatusHandler
-	AppHandler               *handler.AppHandler
-	MetricsHandler           *handler.MetricsHandler
-	RevenueHandler           *handler.RevenueHandler
-	SyncHandler              *handler.SyncHandler
-	SubscriptionHandler      *handler.SubscriptionHandler
-	StoreHealthHandler       *handler.StoreHealthHandler
-	FeeHandler               *handler.FeeHandler
-	UserPreferencesHandler   *handler.UserPreferencesHandler
-	WebhookHandler           *handler.WebhookHandler
-	APIKeyHandler            *apikeyhandler.APIKeyHandler
-	AuthMW                   func(next http.Handler) http.Handler
-	AdminMW                  func(next http.Handler) http.Handler // RequireRoles(ADMIN)
-	InternalMW               func(next http.Handler) http.Handler // Internal key authentication
+	HealthHandler                  *handler.HealthHandler
+	MeHandler                      *handler.MeHandler
+	OAuthHandler                   *handler.OAuthHandler
+	ManualTokenHandler             *handler.ManualTokenHandler
+	IntegrationStatusHandler       *handler.IntegrationStatusHandler
+	AppHandler                     *handler.AppHandler
+	MetricsHandler                 *handler.MetricsHandler
+	RevenueHandler                 *handler.RevenueHandler
+	SyncHandler                    *handler.SyncHandler
+	SubscriptionHandler            *handler.SubscriptionHandler
+	StoreHealthHandler             *handler.StoreHealthHandler
+	FeeHandler                     *handler.FeeHandler
+	TaxProfileHandler              *handler.TaxProfileHandler
+	AccountingExportHandler        *handler.AccountingExportHandler
+	RevenueRecognitionHandler      *handler.RevenueRecognitionHandler
+	UserPreferencesHandler         *handler.UserPreferencesHandler
+	WebhookHandler                 *handler.WebhookHandler
+	WebhookSecretHandler           *handler.WebhookSecretHandler
+	WebhookDeliveryHandler         *handler.WebhookDeliveryHandler
+	AlertHandler                   *handler.AlertHandler
+	DunningHandler                 *handler.DunningHandler
+	NotificationHandler            *handler.NotificationHandler
+	NotificationEmailHandler       *handler.NotificationEmailHandler
+	NotificationChannelHandler     *handler.NotificationChannelHandler
+	NotificationPreferencesHandler *handler.NotificationPreferencesHandler
+	WorkspaceHandler               *handler.WorkspaceHandler
+	APIKeyHandler                  *apikeyhandler.APIKeyHandler
+	APIUsageHandler                *apikeyhandler.APIUsageHandler
+	WebhookEndpointHandler         *apikeyhandler.WebhookEndpointHandler
+	EntitlementPolicyHandler       *apikeyhandler.EntitlementPolicyHandler
+	AuthMW                         func(next http.Handler) http.Handler
+	AdminMW                        func(next http.Handler) http.Handler // RequireRoles(ADMIN), workspace role when workspaces are enabled
+	InternalMW                     func(next http.Handler) http.Handler // Internal key authentication
 }
 
 func New(cfg Config) *chi.Mux {
@@ -38,7 +53,7 @@ func New(cfg Config) *chi.Mux {
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:*", "https://*.ledgerguard.app"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", "X-Workspace-ID"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:           300,
@@ -68,6 +83,34 @@ func New(cfg Config) *chi.Mux {
 			r.With(cfg.AuthMW).Get("/me", cfg.MeHandler.GetMe)
 		}
 
+		// Workspace routes. /workspace acts on the workspace selected by the
+		// X-Workspace-ID header (the user's default workspace without it).
+		if cfg.WorkspaceHandler != nil && cfg.AuthMW != nil && cfg.AdminMW != nil {
+			r.Route("/workspaces", func(r chi.Router) {
+				r.Use(cfg.AuthMW)
+				r.Get("/", cfg.WorkspaceHandler.List)
+				r.Post("/", cfg.WorkspaceHandler.Create)
+				r.Post("/invitations/accept", cfg.WorkspaceHandler.AcceptInvitation)
+			})
+
+			r.Route("/workspace", func(r chi.Router) {
+				r.Use(cfg.AuthMW)
+				r.Get("/", cfg.WorkspaceHandler.GetCurrent)
+				r.Get("/members", cfg.WorkspaceHandler.ListMembers)
+				r.Post("/leave", cfg.WorkspaceHandler.Leave)
+
+				r.Group(func(r chi.Router) {
+					r.Use(cfg.AdminMW)
+					r.Put("/", cfg.WorkspaceHandler.UpdateCurrent)
+					r.Put("/members/{userID}", cfg.WorkspaceHandler.UpdateMember)
+					r.Delete("/members/{userID}", cfg.WorkspaceHandler.RemoveMember)
+					r.Get("/invitations", cfg.WorkspaceHandler.ListInvitations)
+					r.Post("/invitations", cfg.WorkspaceHandler.Invite)
+					r.Delete("/invitations/{invitationID}", cfg.WorkspaceHandler.RevokeInvitation)
+				})
+			})
+		}
+
 		// User preferences routes
 		if cfg.UserPreferencesHandler != nil && cfg.AuthMW != nil {
 			r.Route("/user/preferences", func(r chi.Router) {
@@ -76,6 +119,52 @@ func New(cfg Config) *chi.Mux {
 				r.Put("/dashboard", cfg.UserPreferencesHandler.SaveDashboardPreferences)
 				r.Get("/default-app", cfg.UserPreferencesHandler.GetDefaultApp)
 				r.Put("/default-app", cfg.UserPreferencesHandler.SetDefaultApp)
+
+				// Notification settings, including the daily summary schedule
+				if cfg.NotificationPreferencesHandler != nil {
+					r.Get("/notifications", cfg.NotificationPreferencesHandler.Get)
+					r.Put("/notifications", cfg.NotificationPreferencesHandler.Update)
+				}
+
+				// Slack, Teams, Discord and generic notification webhooks
+				if cfg.NotificationChannelHandler != nil {
+					r.Route("/notification-channels", func(r chi.Router) {
+						r.Get("/", cfg.NotificationChannelHandler.List)
+						r.Post("/", cfg.NotificationChannelHandler.Create)
+						r.Put("/{channelID}", cfg.NotificationChannelHandler.Update)
+						r.Delete("/{channelID}", cfg.NotificationChannelHandler.Delete)
+						r.Post("/{channelID}/test", cfg.NotificationChannelHandler.Test)
+						r.Post("/{channelID}/rotate-secret", cfg.NotificationChannelHandler.RotateSecret)
+					})
+				}
+			})
+		}
+
+		// Notification history routes
+		if cfg.NotificationHandler != nil && cfg.AuthMW != nil {
+			r.Route("/notifications", func(r chi.Router) {
+				r.Use(cfg.AuthMW)
+				r.Get("/", cfg.NotificationHandler.List)
+				r.Post("/read-all", cfg.NotificationHandler.MarkAllRead)
+				r.Get("/{notificationID}", cfg.NotificationHandler.Get)
+				r.Post("/{notificationID}/read", cfg.NotificationHandler.MarkRead)
+			})
+		}
+
+		// Notification email routes. Verify and unsubscribe are opened from emails, so they are public.
+		if cfg.NotificationEmailHandler != nil && cfg.AuthMW != nil {
+			r.Route("/notification-emails", func(r chi.Router) {
+				r.Get("/verify", cfg.NotificationEmailHandler.Verify)
+				r.Get("/unsubscribe", cfg.NotificationEmailHandler.ConfirmUnsubscribe)
+				r.Post("/unsubscribe", cfg.NotificationEmailHandler.Unsubscribe)
+
+				r.Group(func(r chi.Router) {
+					r.Use(cfg.AuthMW)
+					r.Get("/", cfg.NotificationEmailHandler.List)
+					r.Post("/", cfg.NotificationEmailHandler.Add)
+					r.Delete("/{emailID}", cfg.NotificationEmailHandler.Delete)
+					r.Post("/{emailID}/resend-verification", cfg.NotificationEmailHandler.ResendVerification)
+				})
 			})
 		}
 
@@ -142,6 +231,70 @@ func New(cfg Config) *chi.Mux {
 				if cfg.FeeHandler != nil {
 					r.Get("/{appID}/fees/summary", cfg.FeeHandler.GetFeeSummary)
 					r.Get("/{appID}/fees/breakdown", cfg.FeeHandler.GetTierBreakdown)
+					r.Get("/{appID}/fees/tax-report", cfg.FeeHandler.GetTaxReport)
+				}
+
+				// Accounting export routes (QuickBooks, Xero journals)
+				if cfg.AccountingExportHandler != nil {
+					r.Get("/{appID}/accounting/gl-mapping", cfg.AccountingExportHandler.GetMapping)
+					r.Put("/{appID}/accounting/gl-mapping", cfg.AccountingExportHandler.UpdateMapping)
+					r.Get("/{appID}/accounting/exports", cfg.AccountingExportHandler.ListRuns)
+					r.Post("/{appID}/accounting/exports", cfg.AccountingExportHandler.Export)
+				}
+
+				// Revenue recognition routes (recognized / deferred revenue)
+				if cfg.RevenueRecognitionHandler != nil {
+					r.Get("/{appID}/revenue-recognition", cfg.RevenueRecognitionHandler.GetReport)
+					r.Get("/{appID}/revenue-recognition/export", cfg.RevenueRecognitionHandler.Export)
+				}
+
+				// Entitlement policy routes (Revenue API feature gating)
+				if cfg.EntitlementPolicyHandler != nil {
+					r.Get("/{appID}/entitlement-policy", cfg.EntitlementPolicyHandler.Get)
+					r.Put("/{appID}/entitlement-policy", cfg.EntitlementPolicyHandler.Update)
+					r.Delete("/{appID}/entitlement-policy", cfg.EntitlementPolicyHandler.Reset)
+				}
+
+				// Shopify webhook signing secrets (admin only)
+				if cfg.WebhookSecretHandler != nil && cfg.AdminMW != nil {
+					r.With(cfg.AdminMW).Get("/{appID}/webhook-secrets", cfg.WebhookSecretHandler.List)
+					r.With(cfg.AdminMW).Post("/{appID}/webhook-secrets", cfg.WebhookSecretHandler.Create)
+					r.With(cfg.AdminMW).Delete("/{appID}/webhook-secrets/{secretID}", cfg.WebhookSecretHandler.Revoke)
+				}
+
+				// Received Shopify webhooks and replay (admin only)
+				if cfg.WebhookDeliveryHandler != nil && cfg.AdminMW != nil {
+					r.With(cfg.AdminMW).Get("/{appID}/webhook-deliveries", cfg.WebhookDeliveryHandler.List)
+					r.With(cfg.AdminMW).Get("/{appID}/webhook-deliveries/{deliveryID}", cfg.WebhookDeliveryHandler.Get)
+					r.With(cfg.AdminMW).Post("/{appID}/webhook-deliveries/{deliveryID}/replay", cfg.WebhookDeliveryHandler.Replay)
+				}
+
+				// Alert rules and triggered alert history
+				if cfg.AlertHandler != nil {
+					r.Get("/{appID}/alert-rules", cfg.AlertHandler.ListRules)
+					r.Post("/{appID}/alert-rules", cfg.AlertHandler.CreateRule)
+					r.Put("/{appID}/alert-rules/{ruleID}", cfg.AlertHandler.UpdateRule)
+					r.Delete("/{appID}/alert-rules/{ruleID}", cfg.AlertHandler.DeleteRule)
+					r.Get("/{appID}/alerts", cfg.AlertHandler.ListAlerts)
+					r.Post("/{appID}/alerts/{alertID}/acknowledge", cfg.AlertHandler.Acknowledge)
+					r.Post("/{appID}/alerts/{alertID}/resolve", cfg.AlertHandler.Resolve)
+				}
+
+				// Dunning and win-back sequences, their runs and tasks, and the recovery report
+				if cfg.DunningHandler != nil {
+					r.Get("/{appID}/dunning-sequences", cfg.DunningHandler.ListSequences)
+					r.Post("/{appID}/dunning-sequences", cfg.DunningHandler.CreateSequence)
+					r.Get("/{appID}/dunning-sequences/{sequenceID}", cfg.DunningHandler.GetSequence)
+					r.Put("/{appID}/dunning-sequences/{sequenceID}", cfg.DunningHandler.UpdateSequence)
+					r.Delete("/{appID}/dunning-sequences/{sequenceID}", cfg.DunningHandler.DeleteSequence)
+					r.Get("/{appID}/dunning-runs", cfg.DunningHandler.ListRuns)
+					r.Post("/{appID}/dunning-runs/{runID}/cancel", cfg.DunningHandler.CancelRun)
+					r.Get("/{appID}/dunning-tasks", cfg.DunningHandler.ListTasks)
+					r.Post("/{appID}/dunning-tasks/{taskID}/complete", cfg.DunningHandler.CompleteTask)
+					r.Get("/{appID}/shop-contacts/{domain}", cfg.DunningHandler.GetShopContact)
+					r.Put("/{appID}/shop-contacts/{domain}", cfg.DunningHandler.SetShopContact)
+					r.Delete("/{appID}/shop-contacts/{domain}", cfg.DunningHandler.DeleteShopContact)
+					r.Get("/{appID}/dunning-report", cfg.DunningHandler.Report)
 				}
 
 				// Store health routes
@@ -155,6 +308,17 @@ func New(cfg Config) *chi.Mux {
 			})
 		}
 
+		// Tax-on-fees jurisdiction profiles (per partner account)
+		if cfg.TaxProfileHandler != nil && cfg.AuthMW != nil {
+			r.Route("/tax-profiles", func(r chi.Router) {
+				r.Use(cfg.AuthMW)
+				r.Get("/", cfg.TaxProfileHandler.List)
+				r.Post("/", cfg.TaxProfileHandler.Create)
+				r.Put("/{profileID}", cfg.TaxProfileHandler.Update)
+				r.Delete("/{profileID}", cfg.TaxProfileHandler.Delete)
+			})
+		}
+
 		// Tiers route (public info)
 		if cfg.FeeHandler != nil {
 			r.Get("/tiers", cfg.FeeHandler.ListAvailableTiers)
@@ -176,6 +340,26 @@ func New(cfg Config) *chi.Mux {
 				r.Get("/", cfg.APIKeyHandler.List)
 				r.Post("/", cfg.APIKeyHandler.Create)
 				r.Delete("/{id}", cfg.APIKeyHandler.Revoke)
+				r.Post("/{id}/rotate", cfg.APIKeyHandler.Rotate)
+				if cfg.APIUsageHandler != nil {
+					r.Get("/{id}/usage", cfg.APIUsageHandler.Usage)
+				}
+			})
+		}
+
+		// Revenue API customer webhook endpoints (requires auth)
+		if cfg.WebhookEndpointHandler != nil && cfg.AuthMW != nil {
+			r.Route("/webhook-endpoints", func(r chi.Router) {
+				r.Use(cfg.AuthMW)
+				r.Get("/", cfg.WebhookEndpointHandler.List)
+				r.Post("/", cfg.WebhookEndpointHandler.Create)
+				r.Get("/topics", cfg.WebhookEndpointHandler.Topics)
+				r.Get("/{id}", cfg.WebhookEndpointHandler.Get)
+				r.Put("/{id}", cfg.WebhookEndpointHandler.Update)
+				r.Delete("/{id}", cfg.WebhookEndpointHandler.Delete)
+				r.Post("/{id}/rotate-secret", cfg.WebhookEndpointHandler.RotateSecret)
+				r.Get("/{id}/deliveries", cfg.WebhookEndpointHandler.ListDeliveries)
+				r.Post("/{id}/deliveries/{deliveryID}/redeliver", cfg.WebhookEndpointHandler.Redeliver)
 			})
 		}
 
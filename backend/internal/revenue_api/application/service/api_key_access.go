@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrInsufficientScope is returned when the calling API key lacks the scope an operation needs
+var ErrInsufficientScope = errors.New("api key does not have the required scope")
+
+type apiKeyAccessContextKey struct{}
+
+// APIKeyAccess is what the calling API key may do. The API key middleware attaches
+// it to the request context; services enforce it. A nil access (dashboard and
+// background callers) is unrestricted.
+type APIKeyAccess struct {
+	Scopes []string
+	AppIDs []uuid.UUID // Empty = all of the user's apps
+}
+
+// WithAPIKeyAccess returns a context carrying the calling key's access
+func WithAPIKeyAccess(ctx context.Context, access *APIKeyAccess) context.Context {
+	return context.WithValue(ctx, apiKeyAccessContextKey{}, access)
+}
+
+// APIKeyAccessFromContext returns the calling key's access, or nil if the caller isn't an API key
+func APIKeyAccessFromContext(ctx context.Context) *APIKeyAccess {
+	access, _ := ctx.Value(apiKeyAccessContextKey{}).(*APIKeyAccess)
+	return access
+}
+
+// HasScope returns true if the key was granted scope
+func (a *APIKeyAccess) HasScope(scope string) bool {
+	if a == nil {
+		return true
+	}
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsApp returns true if the key may access the app
+func (a *APIKeyAccess) AllowsApp(appID uuid.UUID) bool {
+	if a == nil || len(a.AppIDs) == 0 {
+		return true
+	}
+	for _, id := range a.AppIDs {
+		if id == appID {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireScope returns ErrInsufficientScope if the calling key lacks scope
+func RequireScope(ctx context.Context, scope string) error {
+	if !APIKeyAccessFromContext(ctx).HasScope(scope) {
+		return ErrInsufficientScope
+	}
+	return nil
+}
+
+// filterAllowedApps drops the apps the calling key may not access
+func filterAllowedApps(ctx context.Context, appIDs []uuid.UUID) []uuid.UUID {
+	access := APIKeyAccessFromContext(ctx)
+	if access == nil || len(access.AppIDs) == 0 {
+		return appIDs
+	}
+
+	allowed := make([]uuid.UUID, 0, len(appIDs))
+	for _, id := range appIDs {
+		if access.AllowsApp(id) {
+			allowed = append(allowed, id)
+		}
+	}
+	return allowed
+}
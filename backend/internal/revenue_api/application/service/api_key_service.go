@@ -13,18 +13,52 @@ import (
 var (
 	ErrAPIKeyNotFound   = errors.New("api key not found")
 	ErrAPIKeyRevoked    = errors.New("api key has been revoked")
+	ErrAPIKeyExpired    = errors.New("api key has expired")
+	ErrAPIKeyRotated    = errors.New("api key has already been rotated")
 	ErrUnauthorized     = errors.New("unauthorized")
 	ErrRateLimitInvalid = errors.New("rate limit must be between 1 and 1000")
+	ErrInvalidScopes    = errors.New("scopes must be subscriptions:read, usage:read, usage:write, stream or webhooks:manage")
+	ErrInvalidKeyApps   = errors.New("app_ids must be apps you own")
+	ErrWebhooksAppScope = errors.New("webhooks:manage cannot be limited to specific apps")
+	ErrInvalidExpiry    = errors.New("expires_in_days must be between 1 and 730")
+	ErrInvalidOverlap   = errors.New("overlap must be between 0 and 168 hours")
 )
 
+const (
+	// MaxAPIKeyLifetimeDays is the longest expiry a key can be created with
+	MaxAPIKeyLifetimeDays = 730
+	// DefaultRotationOverlap is how long a rotated key keeps working alongside its successor
+	DefaultRotationOverlap = 24 * time.Hour
+	// MaxRotationOverlap is the longest overlap a rotation can request
+	MaxRotationOverlap = 7 * 24 * time.Hour
+)
+
+// AppResolver resolves an app reference (internal ID, Shopify app GID or numeric ID)
+// to one of the user's apps. SubscriptionStatusService implements it.
+type AppResolver interface {
+	ResolveAppIDs(ctx context.Context, userID uuid.UUID, appID string) ([]uuid.UUID, error)
+}
+
 // APIKeyService handles API key management
 type APIKeyService struct {
-	repo repository.APIKeyRepository
+	repo        repository.APIKeyRepository
+	appResolver AppResolver
+	now         func() time.Time
 }
 
 // NewAPIKeyService creates a new APIKeyService
 func NewAPIKeyService(repo repository.APIKeyRepository) *APIKeyService {
-	return &APIKeyService{repo: repo}
+	return &APIKeyService{
+		repo: repo,
+		now:  func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// WithAppResolver verifies that app restrictions name the user's own apps and
+// accepts Shopify app GIDs. Without it only internal app IDs are accepted.
+func (s *APIKeyService) WithAppResolver(resolver AppResolver) *APIKeyService {
+	s.appResolver = resolver
+	return s
 }
 
 // CreateKeyRequest contains the request data for creating an API key
@@ -32,15 +66,21 @@ type CreateKeyRequest struct {
 	UserID             uuid.UUID
 	Name               string
 	RateLimitPerMinute int
+	Scopes             []string // Empty = entity.DefaultAPIKeyScopes
+	AppIDs             []string // Empty = all of the user's apps
+	ExpiresInDays      int      // 0 = never expires
 }
 
 // CreateKeyResponse contains the response data after creating an API key
 type CreateKeyResponse struct {
-	ID                 uuid.UUID `json:"id"`
-	Name               string    `json:"name,omitempty"`
-	RateLimitPerMinute int       `json:"rate_limit_per_minute"`
-	CreatedAt          time.Time `json:"created_at"`
-	RawKey             string    `json:"api_key"` // Only returned on creation
+	ID                 uuid.UUID  `json:"id"`
+	Name               string     `json:"name,omitempty"`
+	RateLimitPerMinute int        `json:"rate_limit_per_minute"`
+	Scopes             []string   `json:"scopes"`
+	AppIDs             []string   `json:"app_ids"` // Empty = all apps
+	CreatedAt          time.Time  `json:"created_at"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+	RawKey             string     `json:"api_key"` // Only returned on creation
 }
 
 // Create creates a new API key for a user
@@ -52,24 +92,120 @@ func (s *APIKeyService) Create(ctx context.Context, req CreateKeyRequest) (*Crea
 	if req.RateLimitPerMinute > 1000 {
 		return nil, ErrRateLimitInvalid
 	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > MaxAPIKeyLifetimeDays {
+		return nil, ErrInvalidExpiry
+	}
+
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	appIDs, err := s.resolveKeyApps(ctx, req.UserID, req.AppIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(appIDs) > 0 && containsScope(scopes, entity.ScopeWebhooksManage) {
+		// Webhook endpoints receive events for all of the user's apps
+		return nil, ErrWebhooksAppScope
+	}
 
 	// Generate new key
 	keyWithRaw, err := entity.NewAPIKey(req.UserID, req.Name, req.RateLimitPerMinute)
 	if err != nil {
 		return nil, err
 	}
+	if scopes != nil {
+		keyWithRaw.Scopes = scopes
+	}
+	keyWithRaw.AppIDs = appIDs
+	if req.ExpiresInDays > 0 {
+		expiresAt := keyWithRaw.CreatedAt.AddDate(0, 0, req.ExpiresInDays)
+		keyWithRaw.ExpiresAt = &expiresAt
+	}
 
 	// Store the key
 	if err := s.repo.Create(ctx, &keyWithRaw.APIKey); err != nil {
 		return nil, err
 	}
 
-	return &CreateKeyResponse{
-		ID:                 keyWithRaw.ID,
-		Name:               keyWithRaw.Name,
-		RateLimitPerMinute: keyWithRaw.RateLimitPerMinute,
-		CreatedAt:          keyWithRaw.CreatedAt,
-		RawKey:             keyWithRaw.RawKey,
+	return newCreateKeyResponse(keyWithRaw), nil
+}
+
+// RotateKeyRequest contains the request data for rotating an API key
+type RotateKeyRequest struct {
+	UserID        uuid.UUID
+	KeyID         uuid.UUID
+	Overlap       *time.Duration // How long the old key keeps working; nil = DefaultRotationOverlap
+	ExpiresInDays int            // Successor expiry; 0 = same lifetime as the old key
+}
+
+// RotateKeyResponse contains the successor key and when the old key stops working
+type RotateKeyResponse struct {
+	CreateKeyResponse
+	PreviousKeyID        uuid.UUID `json:"previous_key_id"`
+	PreviousKeyExpiresAt time.Time `json:"previous_key_expires_at"`
+}
+
+// Rotate issues a successor for a key with the same name, scopes, apps and rate limit.
+// The old key keeps working until the overlap ends so clients can switch without downtime.
+func (s *APIKeyService) Rotate(ctx context.Context, req RotateKeyRequest) (*RotateKeyResponse, error) {
+	overlap := DefaultRotationOverlap
+	if req.Overlap != nil {
+		overlap = *req.Overlap
+	}
+	if overlap < 0 || overlap > MaxRotationOverlap {
+		return nil, ErrInvalidOverlap
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > MaxAPIKeyLifetimeDays {
+		return nil, ErrInvalidExpiry
+	}
+
+	old, err := s.repo.GetByID(ctx, req.KeyID)
+	if err != nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	if old.UserID != req.UserID {
+		return nil, ErrUnauthorized
+	}
+
+	now := s.now()
+	switch {
+	case old.IsRevoked():
+		return nil, ErrAPIKeyRevoked
+	case old.IsExpired(now):
+		return nil, ErrAPIKeyExpired
+	case old.IsRotated():
+		return nil, ErrAPIKeyRotated
+	}
+
+	successor, err := entity.NewAPIKey(old.UserID, old.Name, old.RateLimitPerMinute)
+	if err != nil {
+		return nil, err
+	}
+	successor.Scopes = old.Scopes
+	successor.AppIDs = old.AppIDs
+	if req.ExpiresInDays > 0 {
+		expiresAt := successor.CreatedAt.AddDate(0, 0, req.ExpiresInDays)
+		successor.ExpiresAt = &expiresAt
+	} else if old.ExpiresAt != nil {
+		expiresAt := successor.CreatedAt.Add(old.ExpiresAt.Sub(old.CreatedAt))
+		successor.ExpiresAt = &expiresAt
+	}
+
+	// Never extend the old key past its own expiry
+	oldExpiresAt := now.Add(overlap)
+	if old.ExpiresAt != nil && old.ExpiresAt.Before(oldExpiresAt) {
+		oldExpiresAt = *old.ExpiresAt
+	}
+
+	if err := s.repo.Rotate(ctx, old.ID, oldExpiresAt, &successor.APIKey); err != nil {
+		return nil, err
+	}
+
+	return &RotateKeyResponse{
+		CreateKeyResponse:    *newCreateKeyResponse(successor),
+		PreviousKeyID:        old.ID,
+		PreviousKeyExpiresAt: oldExpiresAt,
 	}, nil
 }
 
@@ -78,9 +214,15 @@ type APIKeyInfo struct {
 	ID                 uuid.UUID  `json:"id"`
 	Name               string     `json:"name,omitempty"`
 	RateLimitPerMinute int        `json:"rate_limit_per_minute"`
+	Scopes             []string   `json:"scopes"`
+	AppIDs             []string   `json:"app_ids"` // Empty = all apps
 	CreatedAt          time.Time  `json:"created_at"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+	LastUsedAt         *time.Time `json:"last_used_at"`
+	ReplacedByID       *uuid.UUID `json:"replaced_by,omitempty"`
 	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
 	IsActive           bool       `json:"is_active"`
+	Warnings           []string   `json:"warnings"` // expiring, rotating, unused
 }
 
 // List returns all API keys for a user
@@ -90,15 +232,22 @@ func (s *APIKeyService) List(ctx context.Context, userID uuid.UUID) ([]APIKeyInf
 		return nil, err
 	}
 
+	now := s.now()
 	result := make([]APIKeyInfo, len(keys))
 	for i, k := range keys {
 		result[i] = APIKeyInfo{
 			ID:                 k.ID,
 			Name:               k.Name,
 			RateLimitPerMinute: k.RateLimitPerMinute,
+			Scopes:             k.Scopes,
+			AppIDs:             appIDStrings(k.AppIDs),
 			CreatedAt:          k.CreatedAt,
+			ExpiresAt:          k.ExpiresAt,
+			LastUsedAt:         k.LastUsedAt,
+			ReplacedByID:       k.ReplacedByID,
 			RevokedAt:          k.RevokedAt,
-			IsActive:           k.IsActive(),
+			IsActive:           !k.IsRevoked() && !k.IsExpired(now),
+			Warnings:           k.Warnings(now),
 		}
 	}
 
@@ -129,6 +278,10 @@ type ValidatedKey struct {
 	ID                 uuid.UUID
 	UserID             uuid.UUID
 	RateLimitPerMinute int
+	Scopes             []string
+	AppIDs             []uuid.UUID // Empty = all of the user's apps
+	ExpiresAt          *time.Time
+	Rotated            bool // A successor was issued; the key stops working at ExpiresAt
 }
 
 // ValidateKey validates an API key and returns the associated user
@@ -146,10 +299,95 @@ func (s *APIKeyService) ValidateKey(ctx context.Context, rawKey string) (*Valida
 	if key.IsRevoked() {
 		return nil, ErrAPIKeyRevoked
 	}
+	if key.IsExpired(s.now()) {
+		return nil, ErrAPIKeyExpired
+	}
 
 	return &ValidatedKey{
 		ID:                 key.ID,
 		UserID:             key.UserID,
 		RateLimitPerMinute: key.RateLimitPerMinute,
+		Scopes:             key.Scopes,
+		AppIDs:             key.AppIDs,
+		ExpiresAt:          key.ExpiresAt,
+		Rotated:            key.IsRotated(),
 	}, nil
 }
+
+func newCreateKeyResponse(key *entity.APIKeyWithRaw) *CreateKeyResponse {
+	return &CreateKeyResponse{
+		ID:                 key.ID,
+		Name:               key.Name,
+		RateLimitPerMinute: key.RateLimitPerMinute,
+		Scopes:             key.Scopes,
+		AppIDs:             appIDStrings(key.AppIDs),
+		CreatedAt:          key.CreatedAt,
+		ExpiresAt:          key.ExpiresAt,
+		RawKey:             key.RawKey,
+	}
+}
+
+// normalizeScopes validates and de-duplicates scopes; nil means the defaults
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !entity.IsValidAPIKeyScope(scope) {
+			return nil, ErrInvalidScopes
+		}
+		if !containsScope(result, scope) {
+			result = append(result, scope)
+		}
+	}
+	return result, nil
+}
+
+// resolveKeyApps turns the requested app references into the user's app IDs
+func (s *APIKeyService) resolveKeyApps(ctx context.Context, userID uuid.UUID, refs []string) ([]uuid.UUID, error) {
+	var appIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+
+	for _, ref := range refs {
+		var id uuid.UUID
+		if s.appResolver != nil {
+			resolved, err := s.appResolver.ResolveAppIDs(ctx, userID, ref)
+			if err != nil || len(resolved) != 1 {
+				return nil, ErrInvalidKeyApps
+			}
+			id = resolved[0]
+		} else {
+			parsed, err := uuid.Parse(ref)
+			if err != nil {
+				return nil, ErrInvalidKeyApps
+			}
+			id = parsed
+		}
+
+		if !seen[id] {
+			seen[id] = true
+			appIDs = append(appIDs, id)
+		}
+	}
+
+	return appIDs, nil
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func appIDStrings(ids []uuid.UUID) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = id.String()
+	}
+	return result
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	domainEntity "github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+type memAPIKeyRepo struct {
+	keys map[uuid.UUID]*entity.APIKey
+}
+
+func newMemAPIKeyRepo() *memAPIKeyRepo {
+	return &memAPIKeyRepo{keys: make(map[uuid.UUID]*entity.APIKey)}
+}
+
+func (m *memAPIKeyRepo) Create(ctx context.Context, key *entity.APIKey) error {
+	m.keys[key.ID] = key
+	return nil
+}
+
+func (m *memAPIKeyRepo) GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	for _, k := range m.keys {
+		if k.KeyHash == keyHash {
+			return k, nil
+		}
+	}
+	return nil, errTestNotFound
+}
+
+func (m *memAPIKeyRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.APIKey, error) {
+	if k, ok := m.keys[id]; ok {
+		return k, nil
+	}
+	return nil, errTestNotFound
+}
+
+func (m *memAPIKeyRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.APIKey, error) {
+	var result []*entity.APIKey
+	for _, k := range m.keys {
+		if k.UserID == userID {
+			result = append(result, k)
+		}
+	}
+	return result, nil
+}
+
+func (m *memAPIKeyRepo) GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.APIKey, error) {
+	return m.GetByUserID(ctx, userID)
+}
+
+func (m *memAPIKeyRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	m.keys[id].Revoke()
+	return nil
+}
+
+func (m *memAPIKeyRepo) Rotate(ctx context.Context, oldID uuid.UUID, oldExpiresAt time.Time, successor *entity.APIKey) error {
+	old := m.keys[oldID]
+	old.ExpiresAt = &oldExpiresAt
+	old.ReplacedByID = &successor.ID
+	m.keys[successor.ID] = successor
+	return nil
+}
+
+func (m *memAPIKeyRepo) UpdateLastUsed(ctx context.Context, lastUsed map[uuid.UUID]time.Time) error {
+	for id, at := range lastUsed {
+		if k, ok := m.keys[id]; ok && (k.LastUsedAt == nil || at.After(*k.LastUsedAt)) {
+			at := at
+			k.LastUsedAt = &at
+		}
+	}
+	return nil
+}
+
+func newTestStatusServiceForApp() (*SubscriptionStatusService, *domainEntity.App) {
+	account := &domainEntity.PartnerAccount{ID: uuid.New(), UserID: uuid.New()}
+	app := &domainEntity.App{ID: uuid.New(), PartnerAccountID: account.ID, PartnerAppID: "gid://partners/App/42"}
+	status := &entity.SubscriptionStatus{
+		ID:              uuid.New(),
+		ShopifyGID:      "gid://shopify/AppSubscription/1",
+		AppID:           app.ID,
+		MyshopifyDomain: "store.myshopify.com",
+		RiskState:       valueobject.RiskStateSafe,
+		Status:          "ACTIVE",
+	}
+
+	return NewSubscriptionStatusService(
+		&memSubscriptionStatusRepo{statuses: []*entity.SubscriptionStatus{status}},
+		&stubAppRepo{app: app},
+		&stubPartnerRepo{account: account},
+	), app
+}
+
+func TestAPIKeyService_CreateDefaultsToReadScopes(t *testing.T) {
+	svc := NewAPIKeyService(newMemAPIKeyRepo())
+
+	resp, err := svc.Create(context.Background(), CreateKeyRequest{UserID: uuid.New(), Name: "default"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Scopes) != len(entity.DefaultAPIKeyScopes) {
+		t.Errorf("expected default scopes, got %v", resp.Scopes)
+	}
+	if len(resp.AppIDs) != 0 {
+		t.Errorf("expected no app restriction, got %v", resp.AppIDs)
+	}
+
+	validated, err := svc.ValidateKey(context.Background(), resp.RawKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	access := &APIKeyAccess{Scopes: validated.Scopes, AppIDs: validated.AppIDs}
+	if access.HasScope(entity.ScopeWebhooksManage) {
+		t.Error("expected default key not to manage webhooks")
+	}
+	if !access.HasScope(entity.ScopeStream) {
+		t.Error("expected default key to stream")
+	}
+}
+
+func TestAPIKeyService_CreateScopedKey(t *testing.T) {
+	statusService, app := newTestStatusServiceForApp()
+	svc := NewAPIKeyService(newMemAPIKeyRepo()).WithAppResolver(statusService)
+
+	resp, err := svc.Create(context.Background(), CreateKeyRequest{
+		UserID: uuid.New(),
+		Name:   "contractor",
+		Scopes: []string{entity.ScopeUsageRead, entity.ScopeUsageRead},
+		AppIDs: []string{"42", app.ID.String()},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Scopes) != 1 || resp.Scopes[0] != entity.ScopeUsageRead {
+		t.Errorf("expected de-duplicated usage:read scope, got %v", resp.Scopes)
+	}
+	if len(resp.AppIDs) != 1 || resp.AppIDs[0] != app.ID.String() {
+		t.Errorf("expected the app resolved once, got %v", resp.AppIDs)
+	}
+}
+
+func TestAPIKeyService_CreateValidation(t *testing.T) {
+	statusService, _ := newTestStatusServiceForApp()
+	svc := NewAPIKeyService(newMemAPIKeyRepo()).WithAppResolver(statusService)
+
+	tests := []struct {
+		name string
+		req  CreateKeyRequest
+		want error
+	}{
+		{"unknown scope", CreateKeyRequest{Scopes: []string{"admin"}}, ErrInvalidScopes},
+		{"foreign app", CreateKeyRequest{AppIDs: []string{uuid.NewString()}}, ErrInvalidKeyApps},
+		{"webhooks for one app", CreateKeyRequest{Scopes: []string{entity.ScopeWebhooksManage}, AppIDs: []string{"42"}}, ErrWebhooksAppScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.UserID = uuid.New()
+			if _, err := svc.Create(context.Background(), tt.req); err != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSubscriptionStatusService_EnforcesAPIKeyAccess(t *testing.T) {
+	statusService, app := newTestStatusServiceForApp()
+	userID := uuid.New()
+	gid := "gid://shopify/AppSubscription/1"
+
+	ctx := WithAPIKeyAccess(context.Background(), &APIKeyAccess{Scopes: []string{entity.ScopeUsageRead}})
+	if _, err := statusService.GetByShopifyGID(ctx, userID, gid); err != ErrInsufficientScope {
+		t.Errorf("expected ErrInsufficientScope, got %v", err)
+	}
+
+	ctx = WithAPIKeyAccess(context.Background(), &APIKeyAccess{
+		Scopes: []string{entity.ScopeSubscriptionsRead},
+		AppIDs: []uuid.UUID{uuid.New()},
+	})
+	if _, err := statusService.GetByShopifyGID(ctx, userID, gid); err != ErrAppAccessDenied {
+		t.Errorf("expected ErrAppAccessDenied for another app, got %v", err)
+	}
+	if _, err := statusService.GetByDomain(ctx, userID, "store.myshopify.com"); err != ErrSubscriptionNotFound {
+		t.Errorf("expected domain lookup outside the key's apps to miss, got %v", err)
+	}
+	if _, err := statusService.ResolveAppIDs(ctx, userID, "42"); err != ErrAppAccessDenied {
+		t.Errorf("expected ErrAppAccessDenied resolving another app, got %v", err)
+	}
+
+	ctx = WithAPIKeyAccess(context.Background(), &APIKeyAccess{
+		Scopes: []string{entity.ScopeSubscriptionsRead},
+		AppIDs: []uuid.UUID{app.ID},
+	})
+	if _, err := statusService.GetByShopifyGID(ctx, userID, gid); err != nil {
+		t.Errorf("expected access to the key's app, got %v", err)
+	}
+}
+
+func TestAPIKeyService_CreateWithExpiry(t *testing.T) {
+	svc := NewAPIKeyService(newMemAPIKeyRepo())
+
+	if _, err := svc.Create(context.Background(), CreateKeyRequest{UserID: uuid.New(), ExpiresInDays: 731}); err != ErrInvalidExpiry {
+		t.Errorf("expected ErrInvalidExpiry, got %v", err)
+	}
+
+	resp, err := svc.Create(context.Background(), CreateKeyRequest{UserID: uuid.New(), ExpiresInDays: 30})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.ExpiresAt == nil || !resp.ExpiresAt.Equal(resp.CreatedAt.AddDate(0, 0, 30)) {
+		t.Errorf("expected expiry 30 days after creation, got %v", resp.ExpiresAt)
+	}
+
+	svc.now = func() time.Time { return resp.ExpiresAt.Add(time.Second) }
+	if _, err := svc.ValidateKey(context.Background(), resp.RawKey); err != ErrAPIKeyExpired {
+		t.Errorf("expected ErrAPIKeyExpired, got %v", err)
+	}
+}
+
+func TestAPIKeyService_RotateKeepsOldKeyForOverlap(t *testing.T) {
+	repo := newMemAPIKeyRepo()
+	svc := NewAPIKeyService(repo)
+	userID := uuid.New()
+
+	old, err := svc.Create(context.Background(), CreateKeyRequest{
+		UserID:        userID,
+		Name:          "production",
+		Scopes:        []string{entity.ScopeUsageRead},
+		ExpiresInDays: 90,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := old.CreatedAt.Add(time.Hour)
+	svc.now = func() time.Time { return now }
+	overlap := 2 * time.Hour
+
+	rotated, err := svc.Rotate(context.Background(), RotateKeyRequest{UserID: userID, KeyID: old.ID, Overlap: &overlap})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rotated.Name != "production" || len(rotated.Scopes) != 1 || rotated.Scopes[0] != entity.ScopeUsageRead {
+		t.Errorf("expected successor to inherit name and scopes, got %q %v", rotated.Name, rotated.Scopes)
+	}
+	if rotated.ExpiresAt == nil || rotated.ExpiresAt.Sub(rotated.CreatedAt) != 90*24*time.Hour {
+		t.Errorf("expected successor to keep a 90 day lifetime, got %v", rotated.ExpiresAt)
+	}
+	if !rotated.PreviousKeyExpiresAt.Equal(now.Add(overlap)) {
+		t.Errorf("expected old key to expire after the overlap, got %v", rotated.PreviousKeyExpiresAt)
+	}
+
+	// Both keys work during the overlap
+	validated, err := svc.ValidateKey(context.Background(), old.RawKey)
+	if err != nil {
+		t.Fatalf("expected old key to work during the overlap, got %v", err)
+	}
+	if !validated.Rotated {
+		t.Error("expected old key to be reported as rotated")
+	}
+	if _, err := svc.ValidateKey(context.Background(), rotated.RawKey); err != nil {
+		t.Errorf("expected successor to work, got %v", err)
+	}
+
+	if _, err := svc.Rotate(context.Background(), RotateKeyRequest{UserID: userID, KeyID: old.ID}); err != ErrAPIKeyRotated {
+		t.Errorf("expected ErrAPIKeyRotated rotating twice, got %v", err)
+	}
+
+	now = now.Add(overlap)
+	if _, err := svc.ValidateKey(context.Background(), old.RawKey); err != ErrAPIKeyExpired {
+		t.Errorf("expected old key to expire after the overlap, got %v", err)
+	}
+}
+
+func TestAPIKeyService_RotateValidation(t *testing.T) {
+	svc := NewAPIKeyService(newMemAPIKeyRepo())
+	userID := uuid.New()
+
+	key, err := svc.Create(context.Background(), CreateKeyRequest{UserID: userID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tooLong := MaxRotationOverlap + time.Hour
+	if _, err := svc.Rotate(context.Background(), RotateKeyRequest{UserID: userID, KeyID: key.ID, Overlap: &tooLong}); err != ErrInvalidOverlap {
+		t.Errorf("expected ErrInvalidOverlap, got %v", err)
+	}
+	if _, err := svc.Rotate(context.Background(), RotateKeyRequest{UserID: uuid.New(), KeyID: key.ID}); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized for another user's key, got %v", err)
+	}
+
+	if err := svc.Revoke(context.Background(), userID, key.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.Rotate(context.Background(), RotateKeyRequest{UserID: userID, KeyID: key.ID}); err != ErrAPIKeyRevoked {
+		t.Errorf("expected ErrAPIKeyRevoked, got %v", err)
+	}
+}
+
+func TestAPIKeyService_ListWarnings(t *testing.T) {
+	repo := newMemAPIKeyRepo()
+	svc := NewAPIKeyService(repo)
+	userID := uuid.New()
+
+	expiring, _ := svc.Create(context.Background(), CreateKeyRequest{UserID: userID, ExpiresInDays: 10})
+	stale, _ := svc.Create(context.Background(), CreateKeyRequest{UserID: userID})
+
+	svc.now = func() time.Time { return stale.CreatedAt.Add(entity.APIKeyUnusedWarning) }
+	recent := stale.CreatedAt.Add(entity.APIKeyUnusedWarning - time.Hour)
+	if err := repo.UpdateLastUsed(context.Background(), map[uuid.UUID]time.Time{expiring.ID: recent}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys, err := svc.List(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	warnings := make(map[uuid.UUID][]string)
+	for _, k := range keys {
+		warnings[k.ID] = k.Warnings
+	}
+	// The expiring key has expired by now, so it carries no warnings and is inactive
+	if len(warnings[expiring.ID]) != 0 {
+		t.Errorf("expected no warnings for an expired key, got %v", warnings[expiring.ID])
+	}
+	if got := warnings[stale.ID]; len(got) != 1 || got[0] != entity.APIKeyWarningUnused {
+		t.Errorf("expected unused warning, got %v", got)
+	}
+}
+
+func TestAPIKeyEntity_ExpiringWarning(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(entity.APIKeyExpiryWarning)
+	lastUsed := now.Add(-time.Hour)
+	key := &entity.APIKey{CreatedAt: now.Add(-24 * time.Hour), ExpiresAt: &expiresAt, LastUsedAt: &lastUsed}
+
+	if got := key.Warnings(now); len(got) != 1 || got[0] != entity.APIKeyWarningExpiring {
+		t.Errorf("expected expiring warning, got %v", got)
+	}
+
+	successor := uuid.New()
+	key.ReplacedByID = &successor
+	if got := key.Warnings(now); len(got) != 1 || got[0] != entity.APIKeyWarningRotating {
+		t.Errorf("expected rotating warning, got %v", got)
+	}
+}
+
+func TestAPIKeyUsageTracker_FlushKeepsLatest(t *testing.T) {
+	repo := newMemAPIKeyRepo()
+	key := &entity.APIKey{ID: uuid.New()}
+	repo.keys[key.ID] = key
+	tracker := NewAPIKeyUsageTracker(repo)
+
+	later := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tracker.Touch(key.ID, later)
+	tracker.Touch(key.ID, later.Add(-time.Minute))
+
+	if err := tracker.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key.LastUsedAt == nil || !key.LastUsedAt.Equal(later) {
+		t.Errorf("expected last used %v, got %v", later, key.LastUsedAt)
+	}
+	if len(tracker.pending) != 0 {
+		t.Errorf("expected pending to be cleared, got %d", len(tracker.pending))
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
+)
+
+// APIKeyUsageTracker records when API keys were last used. Requests only touch an
+// in-memory map; the latest time per key is written in one batch per interval, so
+// tracking costs a single UPDATE per flush however busy the keys are.
+type APIKeyUsageTracker struct {
+	repo     repository.APIKeyRepository
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]time.Time
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewAPIKeyUsageTracker creates a new APIKeyUsageTracker
+func NewAPIKeyUsageTracker(repo repository.APIKeyRepository) *APIKeyUsageTracker {
+	return &APIKeyUsageTracker{
+		repo:     repo,
+		interval: time.Minute,
+		pending:  make(map[uuid.UUID]time.Time),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// WithInterval sets how often last-used times are written
+func (t *APIKeyUsageTracker) WithInterval(interval time.Duration) *APIKeyUsageTracker {
+	t.interval = interval
+	return t
+}
+
+// Touch records that a key was used at the given time
+func (t *APIKeyUsageTracker) Touch(keyID uuid.UUID, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if prev, ok := t.pending[keyID]; !ok || at.After(prev) {
+		t.pending[keyID] = at
+	}
+}
+
+// Flush writes the pending last-used times. On failure they are kept for the next flush.
+func (t *APIKeyUsageTracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	batch := t.pending
+	t.pending = make(map[uuid.UUID]time.Time)
+	t.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := t.repo.UpdateLastUsed(ctx, batch); err != nil {
+		for id, at := range batch {
+			t.Touch(id, at)
+		}
+		return err
+	}
+	return nil
+}
+
+// Start begins flushing every interval
+func (t *APIKeyUsageTracker) Start(ctx context.Context) {
+	go t.run(ctx)
+}
+
+// Stop flushes what's pending and stops the tracker
+func (t *APIKeyUsageTracker) Stop() {
+	close(t.stopCh)
+	<-t.doneCh
+}
+
+func (t *APIKeyUsageTracker) run(ctx context.Context) {
+	defer close(t.doneCh)
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := t.Flush(ctx); err != nil {
+				log.Printf("APIKeyUsageTracker: %v", err)
+			}
+		case <-t.stopCh:
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := t.Flush(flushCtx); err != nil {
+				log.Printf("APIKeyUsageTracker: %v", err)
+			}
+			cancel()
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
+)
+
+var ErrInvalidUsageRange = errors.New("from must be before to and the range at most 31 days")
+
+const (
+	// DefaultUsageRange is reported when no range is given
+	DefaultUsageRange = 24 * time.Hour
+	// MaxUsageRange is the longest range a usage report covers
+	MaxUsageRange = 31 * 24 * time.Hour
+	// DefaultTopCallers is how many client IPs a report lists by default
+	DefaultTopCallers = 10
+	// MaxTopCallers is the most client IPs a report lists
+	MaxTopCallers = 100
+	// usageRollupBackfill is how far back rollups are recomputed on start
+	usageRollupBackfill = 24 * time.Hour
+)
+
+// APIUsageService reports per-key API usage from hourly rollups of the audit log,
+// and keeps those rollups current
+type APIUsageService struct {
+	usageRepo repository.APIUsageRepository
+	keyRepo   repository.APIKeyRepository
+	interval  time.Duration
+	now       func() time.Time
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewAPIUsageService creates a new APIUsageService
+func NewAPIUsageService(usageRepo repository.APIUsageRepository, keyRepo repository.APIKeyRepository) *APIUsageService {
+	return &APIUsageService{
+		usageRepo: usageRepo,
+		keyRepo:   keyRepo,
+		interval:  5 * time.Minute,
+		now:       func() time.Time { return time.Now().UTC() },
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+}
+
+// WithInterval sets how often rollups are refreshed
+func (s *APIUsageService) WithInterval(interval time.Duration) *APIUsageService {
+	s.interval = interval
+	return s
+}
+
+// UsageQuery selects the range of a usage report
+type UsageQuery struct {
+	From       *time.Time // Default: To - DefaultUsageRange
+	To         *time.Time // Default: now
+	TopCallers int        // Default: DefaultTopCallers
+}
+
+// GetKeyUsage returns the usage report of one of the user's API keys.
+// The range is widened to whole hours, the granularity of the rollups.
+func (s *APIUsageService) GetKeyUsage(ctx context.Context, userID, keyID uuid.UUID, q UsageQuery) (*entity.APIKeyUsageReport, error) {
+	key, err := s.keyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	if key.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	to := s.now()
+	if q.To != nil {
+		to = q.To.UTC()
+	}
+	from := to.Add(-DefaultUsageRange)
+	if q.From != nil {
+		from = q.From.UTC()
+	}
+	if !from.Before(to) || to.Sub(from) > MaxUsageRange {
+		return nil, ErrInvalidUsageRange
+	}
+	from = from.Truncate(time.Hour)
+	if truncated := to.Truncate(time.Hour); !truncated.Equal(to) {
+		to = truncated.Add(time.Hour)
+	}
+
+	topCallers := q.TopCallers
+	if topCallers <= 0 {
+		topCallers = DefaultTopCallers
+	}
+	if topCallers > MaxTopCallers {
+		topCallers = MaxTopCallers
+	}
+
+	rollups, err := s.usageRepo.GetRollups(ctx, key.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	callers, err := s.usageRepo.GetCallerRollups(ctx, key.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewAPIKeyUsageReport(key, from, to, rollups, callers, topCallers), nil
+}
+
+// Start backfills the last day of rollups, then refreshes them every interval
+func (s *APIUsageService) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+// Stop stops refreshing rollups after the current run
+func (s *APIUsageService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+func (s *APIUsageService) run(ctx context.Context) {
+	defer close(s.doneCh)
+
+	if err := s.usageRepo.RollupSince(ctx, s.now().Add(-usageRollupBackfill)); err != nil {
+		log.Printf("APIUsageService: backfill: %v", err)
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.RefreshRollups(ctx); err != nil {
+				log.Printf("APIUsageService: %v", err)
+			}
+		case <-s.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RefreshRollups recomputes the current and previous hour. Audit logs are written
+// asynchronously, so the previous hour can still change just after it ends.
+func (s *APIUsageService) RefreshRollups(ctx context.Context) error {
+	return s.usageRepo.RollupSince(ctx, s.now().Add(-time.Hour))
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+type memAPIUsageRepo struct {
+	rollups []*entity.APIUsageRollup
+	callers []*entity.APICallerRollup
+	since   []time.Time
+}
+
+func (m *memAPIUsageRepo) RollupSince(ctx context.Context, since time.Time) error {
+	m.since = append(m.since, since)
+	return nil
+}
+
+func (m *memAPIUsageRepo) GetRollups(ctx context.Context, apiKeyID uuid.UUID, from, to time.Time) ([]*entity.APIUsageRollup, error) {
+	var result []*entity.APIUsageRollup
+	for _, r := range m.rollups {
+		if r.APIKeyID == apiKeyID && !r.Hour.Before(from) && r.Hour.Before(to) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (m *memAPIUsageRepo) GetCallerRollups(ctx context.Context, apiKeyID uuid.UUID, from, to time.Time) ([]*entity.APICallerRollup, error) {
+	var result []*entity.APICallerRollup
+	for _, r := range m.callers {
+		if r.APIKeyID == apiKeyID && !r.Hour.Before(from) && r.Hour.Before(to) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+// histogram puts count requests in the bucket ending at bound ms
+func histogram(bound int, count int64) entity.LatencyHistogram {
+	h := entity.NewLatencyHistogram()
+	for i, b := range entity.APIUsageLatencyBuckets {
+		if b == bound {
+			h[i] = count
+		}
+	}
+	return h
+}
+
+func TestAPIUsageService_GetKeyUsage(t *testing.T) {
+	keyRepo := newMemAPIKeyRepo()
+	userID := uuid.New()
+	key := &entity.APIKey{ID: uuid.New(), UserID: userID, RateLimitPerMinute: 60}
+	keyRepo.keys[key.ID] = key
+
+	now := time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC)
+	hour1 := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	hour2 := hour1.Add(time.Hour)
+
+	usageRepo := &memAPIUsageRepo{
+		rollups: []*entity.APIUsageRollup{
+			{APIKeyID: key.ID, Hour: hour1, Endpoint: "/v1/subscriptions/{shopify_gid}", Method: "GET",
+				RequestCount: 90, ErrorCount: 9, RateLimitedCount: 5, TotalResponseMs: 900, LatencyBuckets: histogram(10, 90)},
+			{APIKeyID: key.ID, Hour: hour2, Endpoint: "/v1/subscriptions/{shopify_gid}", Method: "GET",
+				RequestCount: 5, TotalResponseMs: 1000, LatencyBuckets: histogram(250, 5)},
+			{APIKeyID: key.ID, Hour: hour2, Endpoint: "/v1/usages/batch", Method: "POST",
+				RequestCount: 5, ErrorCount: 1, ServerErrorCount: 1, TotalResponseMs: 5000, LatencyBuckets: histogram(1000, 5)},
+			// Outside the default range
+			{APIKeyID: key.ID, Hour: hour1.Add(-48 * time.Hour), Endpoint: "/v1/usages/batch", Method: "POST",
+				RequestCount: 1000, LatencyBuckets: histogram(10, 1000)},
+		},
+		callers: []*entity.APICallerRollup{
+			{APIKeyID: key.ID, Hour: hour1, IPAddress: "10.0.0.1", RequestCount: 60, ErrorCount: 9},
+			{APIKeyID: key.ID, Hour: hour2, IPAddress: "10.0.0.1", RequestCount: 10},
+			{APIKeyID: key.ID, Hour: hour1, IPAddress: "10.0.0.2", RequestCount: 30, ErrorCount: 1},
+		},
+	}
+
+	svc := NewAPIUsageService(usageRepo, keyRepo)
+	svc.now = func() time.Time { return now }
+
+	report, err := svc.GetKeyUsage(context.Background(), userID, key.ID, UsageQuery{TopCallers: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !report.To.Equal(now.Truncate(time.Hour).Add(time.Hour)) || !report.From.Equal(now.Add(-24*time.Hour).Truncate(time.Hour)) {
+		t.Errorf("expected the range widened to whole hours, got %v - %v", report.From, report.To)
+	}
+	if report.Totals.Requests != 100 || report.Totals.Errors != 10 || report.Totals.ErrorRate != 0.1 {
+		t.Errorf("unexpected totals: %+v", report.Totals)
+	}
+	if report.Totals.AvgResponseMs != 69 {
+		t.Errorf("expected avg 69ms, got %d", report.Totals.AvgResponseMs)
+	}
+	if report.Totals.P50ResponseMs > 10 {
+		t.Errorf("expected p50 in the fastest bucket, got %d", report.Totals.P50ResponseMs)
+	}
+	if report.Totals.P95ResponseMs <= 100 || report.Totals.P95ResponseMs > 250 {
+		t.Errorf("expected p95 in the 100-250ms bucket, got %d", report.Totals.P95ResponseMs)
+	}
+
+	if len(report.Endpoints) != 2 || report.Endpoints[0].Endpoint != "/v1/subscriptions/{shopify_gid}" || report.Endpoints[0].Requests != 95 {
+		t.Fatalf("expected endpoints ordered by requests, got %+v", report.Endpoints)
+	}
+	if batch := report.Endpoints[1]; batch.ServerErrors != 1 || batch.ErrorRate != 0.2 {
+		t.Errorf("unexpected batch endpoint usage: %+v", batch)
+	}
+
+	if len(report.Hourly) != 2 || !report.Hourly[0].Hour.Equal(hour1) || report.Hourly[1].Requests != 10 {
+		t.Errorf("unexpected hourly usage: %+v", report.Hourly)
+	}
+	if report.Quota.PeakHourRequests != 90 || report.Quota.RateLimited != 5 || report.Quota.RateLimitPerMinute != 60 {
+		t.Errorf("unexpected quota: %+v", report.Quota)
+	}
+
+	if len(report.TopCallers) != 1 || report.TopCallers[0].IPAddress != "10.0.0.1" || report.TopCallers[0].Requests != 70 {
+		t.Errorf("expected the top caller merged across hours, got %+v", report.TopCallers)
+	}
+}
+
+func TestAPIUsageService_GetKeyUsageValidation(t *testing.T) {
+	keyRepo := newMemAPIKeyRepo()
+	userID := uuid.New()
+	key := &entity.APIKey{ID: uuid.New(), UserID: userID}
+	keyRepo.keys[key.ID] = key
+	svc := NewAPIUsageService(&memAPIUsageRepo{}, keyRepo)
+
+	if _, err := svc.GetKeyUsage(context.Background(), uuid.New(), key.ID, UsageQuery{}); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if _, err := svc.GetKeyUsage(context.Background(), userID, uuid.New(), UsageQuery{}); err != ErrAPIKeyNotFound {
+		t.Errorf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+
+	to := time.Now().UTC()
+	tooEarly := to.Add(-MaxUsageRange - time.Hour)
+	if _, err := svc.GetKeyUsage(context.Background(), userID, key.ID, UsageQuery{From: &tooEarly, To: &to}); err != ErrInvalidUsageRange {
+		t.Errorf("expected ErrInvalidUsageRange for a long range, got %v", err)
+	}
+	if _, err := svc.GetKeyUsage(context.Background(), userID, key.ID, UsageQuery{From: &to, To: &to}); err != ErrInvalidUsageRange {
+		t.Errorf("expected ErrInvalidUsageRange for an empty range, got %v", err)
+	}
+
+	report, err := svc.GetKeyUsage(context.Background(), userID, key.ID, UsageQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Totals.Requests != 0 || len(report.Endpoints) != 0 || report.Totals.P95ResponseMs != 0 {
+		t.Errorf("expected an empty report, got %+v", report)
+	}
+}
+
+func TestLatencyHistogram_Percentile(t *testing.T) {
+	h := entity.NewLatencyHistogram()
+	h[0] = 50                                 // <= 10ms
+	h[1] = 50                                 // 10-25ms
+	h[len(entity.APIUsageLatencyBuckets)] = 0 // overflow
+
+	if got := h.Percentile(50); got != 10 {
+		t.Errorf("expected p50 10ms, got %d", got)
+	}
+	if got := h.Percentile(75); got < 17 || got > 18 {
+		t.Errorf("expected p75 interpolated to ~17ms, got %d", got)
+	}
+
+	h[len(entity.APIUsageLatencyBuckets)] = 100
+	if got := h.Percentile(95); got != 5000 {
+		t.Errorf("expected overflow to report the last bound, got %d", got)
+	}
+}
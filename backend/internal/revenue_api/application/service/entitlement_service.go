@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
+)
+
+// EntitlementService decides store access from each app's entitlement policy
+type EntitlementService struct {
+	statusService *SubscriptionStatusService
+	policyRepo    repository.EntitlementPolicyRepository
+	now           func() time.Time
+}
+
+// NewEntitlementService creates a new EntitlementService
+func NewEntitlementService(
+	statusService *SubscriptionStatusService,
+	policyRepo repository.EntitlementPolicyRepository,
+) *EntitlementService {
+	return &EntitlementService{
+		statusService: statusService,
+		policyRepo:    policyRepo,
+		now:           time.Now,
+	}
+}
+
+// EntitlementCheck is the decision for one store together with the status it was based on
+type EntitlementCheck struct {
+	Status        *entity.SubscriptionStatus
+	Entitlement   *entity.Entitlement
+	DefaultPolicy bool // The app has no saved policy
+	EvaluatedAt   time.Time
+}
+
+// Check decides the entitlement of a store. appID optionally limits the lookup to one app.
+func (s *EntitlementService) Check(ctx context.Context, userID uuid.UUID, appID string, domain string) (*EntitlementCheck, error) {
+	status, err := s.statusService.GetByDomainInApp(ctx, userID, appID, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.policyForApp(ctx, status.AppID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	return &EntitlementCheck{
+		Status:        status,
+		Entitlement:   policy.Evaluate(status, now),
+		DefaultPolicy: policy.IsDefault(),
+		EvaluatedAt:   now,
+	}, nil
+}
+
+// GetPolicy returns the app's policy, or the default policy if none is saved
+func (s *EntitlementService) GetPolicy(ctx context.Context, userID uuid.UUID, appID string) (*entity.EntitlementPolicy, error) {
+	resolvedAppID, err := s.resolveApp(ctx, userID, appID)
+	if err != nil {
+		return nil, err
+	}
+	return s.policyForApp(ctx, resolvedAppID)
+}
+
+// SavePolicy validates and replaces the app's policy
+func (s *EntitlementService) SavePolicy(
+	ctx context.Context,
+	userID uuid.UUID,
+	appID string,
+	rules []entity.EntitlementRule,
+	defaultDecision entity.EntitlementDecision,
+	defaultReason string,
+) (*entity.EntitlementPolicy, error) {
+	resolvedAppID, err := s.resolveApp(ctx, userID, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.policyRepo.GetByAppID(ctx, resolvedAppID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := entity.NewEntitlementPolicy(resolvedAppID, rules, defaultDecision, defaultReason)
+	if existing != nil {
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.Upsert(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// ResetPolicy deletes the app's saved policy and returns the default it reverts to
+func (s *EntitlementService) ResetPolicy(ctx context.Context, userID uuid.UUID, appID string) (*entity.EntitlementPolicy, error) {
+	resolvedAppID, err := s.resolveApp(ctx, userID, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.DeleteByAppID(ctx, resolvedAppID); err != nil {
+		return nil, err
+	}
+	return entity.DefaultEntitlementPolicy(resolvedAppID), nil
+}
+
+func (s *EntitlementService) resolveApp(ctx context.Context, userID uuid.UUID, appID string) (uuid.UUID, error) {
+	if appID == "" {
+		return uuid.Nil, ErrAppAccessDenied
+	}
+	appIDs, err := s.statusService.ResolveAppIDs(ctx, userID, appID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return appIDs[0], nil
+}
+
+func (s *EntitlementService) policyForApp(ctx context.Context, appID uuid.UUID) (*entity.EntitlementPolicy, error) {
+	policy, err := s.policyRepo.GetByAppID(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return entity.DefaultEntitlementPolicy(appID), nil
+	}
+	return policy, nil
+}
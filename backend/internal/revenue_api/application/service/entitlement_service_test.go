@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	domainEntity "github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	revrepo "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
+)
+
+type memSubscriptionStatusRepo struct {
+	statuses      []*entity.SubscriptionStatus
+	lastPageQuery revrepo.SubscriptionStatusPageQuery
+}
+
+func (m *memSubscriptionStatusRepo) Upsert(ctx context.Context, status *entity.SubscriptionStatus) error {
+	for i, s := range m.statuses {
+		if s.ShopifyGID == status.ShopifyGID {
+			m.statuses[i] = status
+			return nil
+		}
+	}
+	m.statuses = append(m.statuses, status)
+	return nil
+}
+
+func (m *memSubscriptionStatusRepo) UpsertBatch(ctx context.Context, statuses []*entity.SubscriptionStatus) error {
+	for _, status := range statuses {
+		_ = m.Upsert(ctx, status)
+	}
+	return nil
+}
+
+func (m *memSubscriptionStatusRepo) GetByShopifyGID(ctx context.Context, shopifyGID string) (*entity.SubscriptionStatus, error) {
+	for _, s := range m.statuses {
+		if s.ShopifyGID == shopifyGID {
+			return s, nil
+		}
+	}
+	return nil, errTestNotFound
+}
+
+func (m *memSubscriptionStatusRepo) GetByShopifyGIDs(ctx context.Context, shopifyGIDs []string) ([]*entity.SubscriptionStatus, error) {
+	var result []*entity.SubscriptionStatus
+	for _, s := range m.statuses {
+		for _, gid := range shopifyGIDs {
+			if s.ShopifyGID == gid {
+				result = append(result, s)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *memSubscriptionStatusRepo) GetByDomain(ctx context.Context, appID uuid.UUID, domain string) (*entity.SubscriptionStatus, error) {
+	for _, s := range m.statuses {
+		if s.AppID == appID && s.MyshopifyDomain == domain {
+			return s, nil
+		}
+	}
+	return nil, errTestNotFound
+}
+
+func (m *memSubscriptionStatusRepo) GetByDomains(ctx context.Context, appID uuid.UUID, domains []string) ([]*entity.SubscriptionStatus, error) {
+	return nil, nil
+}
+
+func (m *memSubscriptionStatusRepo) GetByAppID(ctx context.Context, appID uuid.UUID) ([]*entity.SubscriptionStatus, error) {
+	var result []*entity.SubscriptionStatus
+	for _, s := range m.statuses {
+		if s.AppID == appID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *memSubscriptionStatusRepo) GetByAppIDAndRiskState(ctx context.Context, appID uuid.UUID, riskState valueobject.RiskState) ([]*entity.SubscriptionStatus, error) {
+	return nil, nil
+}
+
+// FindPage mirrors the Postgres keyset query: order by the sort column then id, resume
+// strictly after the cursor's (sort value, id)
+func (m *memSubscriptionStatusRepo) FindPage(ctx context.Context, query revrepo.SubscriptionStatusPageQuery) ([]*entity.SubscriptionStatus, error) {
+	m.lastPageQuery = query
+
+	less := func(a, b *entity.SubscriptionStatus) bool {
+		c := compareSortValues(query.OrderBy, subscriptionSortValue(query.OrderBy, a), subscriptionSortValue(query.OrderBy, b))
+		if c == 0 {
+			c = strings.Compare(a.ID.String(), b.ID.String())
+		}
+		if query.Descending {
+			return c > 0
+		}
+		return c < 0
+	}
+
+	var page []*entity.SubscriptionStatus
+	for _, s := range m.statuses {
+		if !m.matches(query.AppIDs, query.Filter, s) {
+			continue
+		}
+		if query.After != nil {
+			c := compareSortValues(query.OrderBy, subscriptionSortValue(query.OrderBy, s), query.After.SortValue)
+			if c == 0 {
+				c = strings.Compare(s.ID.String(), query.After.ID.String())
+			}
+			if (!query.Descending && c <= 0) || (query.Descending && c >= 0) {
+				continue
+			}
+		}
+		page = append(page, s)
+	}
+
+	sort.Slice(page, func(i, j int) bool { return less(page[i], page[j]) })
+	if len(page) > query.Limit {
+		page = page[:query.Limit]
+	}
+	return page, nil
+}
+
+func (m *memSubscriptionStatusRepo) Count(ctx context.Context, appIDs []uuid.UUID, filter revrepo.SubscriptionStatusFilter) (int, error) {
+	count := 0
+	for _, s := range m.statuses {
+		if m.matches(appIDs, filter, s) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memSubscriptionStatusRepo) matches(appIDs []uuid.UUID, filter revrepo.SubscriptionStatusFilter, s *entity.SubscriptionStatus) bool {
+	inApps := false
+	for _, id := range appIDs {
+		inApps = inApps || s.AppID == id
+	}
+	if !inApps {
+		return false
+	}
+	if filter.RiskState != nil && s.RiskState != *filter.RiskState {
+		return false
+	}
+	if filter.Status != nil && s.Status != *filter.Status {
+		return false
+	}
+	if filter.IsOverdue != nil && (s.MonthsOverdue > 0) != *filter.IsOverdue {
+		return false
+	}
+	return true
+}
+
+// compareSortValues compares rendered sort values the way Postgres compares the cast column
+func compareSortValues(field revrepo.SubscriptionStatusSortField, a, b string) int {
+	switch field {
+	case revrepo.SortByMonthsOverdue:
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x - y
+	case revrepo.SortByExpectedNextChargeDate, revrepo.SortByLastSuccessfulChargeDate:
+		parse := func(v string) time.Time {
+			if v == "infinity" {
+				return time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+			}
+			t, _ := time.Parse(time.RFC3339Nano, v)
+			return t
+		}
+		return parse(a).Compare(parse(b))
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func (m *memSubscriptionStatusRepo) DeleteByShopifyGIDs(ctx context.Context, shopifyGIDs []string) error {
+	kept := m.statuses[:0]
+	for _, s := range m.statuses {
+		deleted := false
+		for _, gid := range shopifyGIDs {
+			deleted = deleted || s.ShopifyGID == gid
+		}
+		if !deleted {
+			kept = append(kept, s)
+		}
+	}
+	m.statuses = kept
+	return nil
+}
+
+func (m *memSubscriptionStatusRepo) DeleteByAppID(ctx context.Context, appID uuid.UUID) error {
+	return nil
+}
+
+type memEntitlementPolicyRepo struct {
+	policies map[uuid.UUID]*entity.EntitlementPolicy
+}
+
+func (m *memEntitlementPolicyRepo) GetByAppID(ctx context.Context, appID uuid.UUID) (*entity.EntitlementPolicy, error) {
+	return m.policies[appID], nil
+}
+
+func (m *memEntitlementPolicyRepo) Upsert(ctx context.Context, policy *entity.EntitlementPolicy) error {
+	m.policies[policy.AppID] = policy
+	return nil
+}
+
+func (m *memEntitlementPolicyRepo) DeleteByAppID(ctx context.Context, appID uuid.UUID) error {
+	delete(m.policies, appID)
+	return nil
+}
+
+func newTestEntitlementService(statuses ...*entity.SubscriptionStatus) (*EntitlementService, *domainEntity.App, *memEntitlementPolicyRepo) {
+	account := &domainEntity.PartnerAccount{ID: uuid.New(), UserID: uuid.New()}
+	app := &domainEntity.App{ID: uuid.New(), PartnerAccountID: account.ID, PartnerAppID: "gid://partners/App/42"}
+	for _, s := range statuses {
+		s.AppID = app.ID
+	}
+
+	statusService := NewSubscriptionStatusService(
+		&memSubscriptionStatusRepo{statuses: statuses},
+		&stubAppRepo{app: app},
+		&stubPartnerRepo{account: account},
+	)
+	policyRepo := &memEntitlementPolicyRepo{policies: make(map[uuid.UUID]*entity.EntitlementPolicy)}
+	return NewEntitlementService(statusService, policyRepo), app, policyRepo
+}
+
+func newTestStatus(domain string, riskState valueobject.RiskState, missedAt *time.Time) *entity.SubscriptionStatus {
+	return &entity.SubscriptionStatus{
+		ID:                     uuid.New(),
+		ShopifyGID:             "gid://shopify/AppSubscription/" + domain,
+		MyshopifyDomain:        domain,
+		RiskState:              riskState,
+		Status:                 "ACTIVE",
+		ExpectedNextChargeDate: missedAt,
+	}
+}
+
+func TestEntitlementService_DefaultPolicy(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	recentlyMissed := now.AddDate(0, 0, -3)
+	longMissed := now.AddDate(0, 0, -10)
+
+	svc, _, _ := newTestEntitlementService(
+		newTestStatus("safe.myshopify.com", valueobject.RiskStateSafe, nil),
+		newTestStatus("grace.myshopify.com", valueobject.RiskStateOneCycleMissed, &recentlyMissed),
+		newTestStatus("expired.myshopify.com", valueobject.RiskStateOneCycleMissed, &longMissed),
+		newTestStatus("two.myshopify.com", valueobject.RiskStateTwoCyclesMissed, &longMissed),
+		newTestStatus("churned.myshopify.com", valueobject.RiskStateChurned, &longMissed),
+	)
+	svc.now = func() time.Time { return now }
+
+	tests := []struct {
+		domain       string
+		decision     entity.EntitlementDecision
+		reason       string
+		hasGraceTime bool
+	}{
+		{"safe.myshopify.com", entity.EntitlementAllow, "in_good_standing", false},
+		{"grace.myshopify.com", entity.EntitlementWarn, "payment_overdue", true},
+		{"expired.myshopify.com", entity.EntitlementRestrict, "payment_overdue", false},
+		{"two.myshopify.com", entity.EntitlementRestrict, "payment_overdue", false},
+		{"churned.myshopify.com", entity.EntitlementBlock, "subscription_churned", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			check, err := svc.Check(context.Background(), uuid.New(), "", tt.domain)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !check.DefaultPolicy {
+				t.Error("expected default policy")
+			}
+			if check.Entitlement.Decision != tt.decision {
+				t.Errorf("expected %s, got %s", tt.decision, check.Entitlement.Decision)
+			}
+			if check.Entitlement.Reason != tt.reason {
+				t.Errorf("expected reason %s, got %s", tt.reason, check.Entitlement.Reason)
+			}
+			if (check.Entitlement.GraceDeadline != nil) != tt.hasGraceTime {
+				t.Errorf("expected grace deadline set = %v, got %v", tt.hasGraceTime, check.Entitlement.GraceDeadline)
+			}
+		})
+	}
+}
+
+func TestEntitlementService_GraceDeadline(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	missed := now.AddDate(0, 0, -3)
+
+	svc, _, _ := newTestEntitlementService(newTestStatus("grace.myshopify.com", valueobject.RiskStateOneCycleMissed, &missed))
+	svc.now = func() time.Time { return now }
+
+	check, err := svc.Check(context.Background(), uuid.New(), "", "grace.myshopify.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := missed.AddDate(0, 0, 7)
+	if check.Entitlement.GraceDeadline == nil || !check.Entitlement.GraceDeadline.Equal(want) {
+		t.Errorf("expected grace deadline %v, got %v", want, check.Entitlement.GraceDeadline)
+	}
+}
+
+func TestEntitlementService_SavedPolicy(t *testing.T) {
+	svc, app, _ := newTestEntitlementService(newTestStatus("frozen.myshopify.com", valueobject.RiskStateSafe, nil))
+	svc.statusService.statusRepo.(*memSubscriptionStatusRepo).statuses[0].Status = "FROZEN"
+	userID := uuid.New()
+
+	isPaid := false
+	policy, err := svc.SavePolicy(context.Background(), userID, "42", []entity.EntitlementRule{
+		{Name: "Frozen", Decision: "restrict", Reason: "store_frozen", Statuses: []string{"frozen"}},
+		{Name: "Unpaid", Decision: entity.EntitlementWarn, Reason: "unpaid", IsPaidCurrentCycle: &isPaid},
+	}, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.AppID != app.ID || policy.DefaultDecision != entity.EntitlementAllow {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+
+	check, err := svc.Check(context.Background(), userID, app.ID.String(), "frozen.myshopify.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if check.DefaultPolicy {
+		t.Error("expected saved policy")
+	}
+	if check.Entitlement.Decision != entity.EntitlementRestrict || check.Entitlement.RuleName != "Frozen" {
+		t.Errorf("expected first matching rule to decide, got %+v", check.Entitlement)
+	}
+
+	reset, err := svc.ResetPolicy(context.Background(), userID, "42")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reset.IsDefault() {
+		t.Error("expected reset to return the default policy")
+	}
+}
+
+func TestEntitlementService_SavePolicyValidation(t *testing.T) {
+	svc, _, policyRepo := newTestEntitlementService()
+	userID := uuid.New()
+
+	tests := []struct {
+		name  string
+		rules []entity.EntitlementRule
+		want  error
+	}{
+		{"unknown decision", []entity.EntitlementRule{{Decision: "DENY", Reason: "x"}}, entity.ErrInvalidEntitlementDecision},
+		{"missing reason", []entity.EntitlementRule{{Decision: entity.EntitlementBlock}}, entity.ErrInvalidEntitlementRule},
+		{"unknown risk state", []entity.EntitlementRule{{Decision: entity.EntitlementBlock, Reason: "x", RiskStates: []string{"LATE"}}}, entity.ErrInvalidEntitlementRule},
+		{"after grace without grace days", []entity.EntitlementRule{{Decision: entity.EntitlementWarn, Reason: "x", AfterGraceDecision: entity.EntitlementBlock}}, entity.ErrInvalidEntitlementRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.SavePolicy(context.Background(), userID, "42", tt.rules, "", "")
+			if err != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if len(policyRepo.policies) != 0 {
+		t.Error("expected invalid policies not to be saved")
+	}
+
+	if _, err := svc.SavePolicy(context.Background(), userID, "99", nil, "", ""); err != ErrAppAccessDenied {
+		t.Errorf("expected ErrAppAccessDenied for another app, got %v", err)
+	}
+}
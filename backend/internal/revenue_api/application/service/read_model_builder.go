@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"log"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,6 +16,11 @@ import (
 	revrepo "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
 )
 
+// projectionOverlap is how far before a checkpoint incremental projections re-read the
+// ledger, so rows committed out of order by concurrent writers are not skipped.
+// Reprojecting a row that did not change is a no-op.
+const projectionOverlap = time.Hour
+
 // ReadModelBuilder populates the CQRS read model for the Revenue API
 type ReadModelBuilder struct {
 	// Source repositories (main ledger)
@@ -22,6 +30,18 @@ type ReadModelBuilder struct {
 	// Target repositories (read model)
 	subscriptionStatusRepo revrepo.SubscriptionStatusRepository
 	usageStatusRepo        revrepo.UsageStatusRepository
+
+	// Optional projection checkpoints; without them every projection is a rebuild
+	checkpointRepo revrepo.ProjectionCheckpointRepository
+
+	// Optional customer webhooks for usage.billed
+	webhookPublisher *WebhookPublisher
+
+	// Optional stream of status/risk changes found by rebuilds
+	changeHub *SubscriptionChangeHub
+
+	mu  sync.Mutex // Serializes projections of sync, webhooks and consistency checks
+	now func() time.Time
 }
 
 // NewReadModelBuilder creates a new ReadModelBuilder
@@ -36,14 +56,116 @@ func NewReadModelBuilder(
 		transactionRepo:        transactionRepo,
 		subscriptionStatusRepo: subscriptionStatusRepo,
 		usageStatusRepo:        usageStatusRepo,
+		now:                    func() time.Time { return time.Now().UTC() },
 	}
 }
 
-// RebuildForApp rebuilds the read model for a specific app
-// This should be called after a ledger sync completes
+// WithCheckpointRepo enables incremental projection from per-app checkpoints
+func (b *ReadModelBuilder) WithCheckpointRepo(repo revrepo.ProjectionCheckpointRepository) *ReadModelBuilder {
+	b.checkpointRepo = repo
+	return b
+}
+
+// WithWebhookPublisher publishes usage.billed for usage charges that become billed during a rebuild
+func (b *ReadModelBuilder) WithWebhookPublisher(publisher *WebhookPublisher) *ReadModelBuilder {
+	b.webhookPublisher = publisher
+	return b
+}
+
+// WithChangeHub streams status and risk changes detected by rebuilds (ledger updates)
+func (b *ReadModelBuilder) WithChangeHub(hub *SubscriptionChangeHub) *ReadModelBuilder {
+	b.changeHub = hub
+	return b
+}
+
+// RebuildForApp rebuilds the read model for a specific app from scratch
 func (b *ReadModelBuilder) RebuildForApp(ctx context.Context, appID uuid.UUID) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.rebuild(ctx, appID)
+}
+
+// ProjectApp brings the read model of an app up to date with its ledger. Only
+// subscriptions updated and transactions stored since the app's checkpoint are
+// projected; an app without a checkpoint is rebuilt. Call it after every ledger sync.
+func (b *ReadModelBuilder) ProjectApp(ctx context.Context, appID uuid.UUID) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.checkpointRepo == nil {
+		return b.rebuild(ctx, appID)
+	}
+
+	checkpoint, err := b.checkpointRepo.GetByAppID(ctx, appID)
+	if err != nil {
+		return err
+	}
+	if checkpoint == nil {
+		return b.rebuild(ctx, appID)
+	}
+
+	projectedAt := b.now()
+
+	subscriptions, err := b.subscriptionRepo.FindUpdatedSince(ctx, appID, checkpoint.SubscriptionsThrough.Add(-projectionOverlap))
+	if err != nil {
+		return err
+	}
+	changedSubscriptions, err := b.projectSubscriptions(ctx, appID, subscriptions, b.changeHub != nil)
+	if err != nil {
+		log.Printf("ReadModelBuilder: failed to project subscriptions for app %s: %v", appID, err)
+		return err
+	}
+
+	transactions, err := b.transactionRepo.FindCreatedSince(ctx, appID, checkpoint.TransactionsThrough.Add(-projectionOverlap))
+	if err != nil {
+		return err
+	}
+	usageTransactions := filterUsageTransactions(transactions)
+	if err := b.projectUsageTransactions(ctx, appID, usageTransactions); err != nil {
+		log.Printf("ReadModelBuilder: failed to project usage for app %s: %v", appID, err)
+		return err
+	}
+
+	// Advance by what was read rather than the local clock, which may trail the database's
+	for _, sub := range subscriptions {
+		if sub.UpdatedAt.After(checkpoint.SubscriptionsThrough) {
+			checkpoint.SubscriptionsThrough = sub.UpdatedAt
+		}
+	}
+	for _, txn := range transactions {
+		if txn.CreatedAt.After(checkpoint.TransactionsThrough) {
+			checkpoint.TransactionsThrough = txn.CreatedAt
+		}
+	}
+	checkpoint.ProjectedAt = projectedAt
+	if err := b.checkpointRepo.Upsert(ctx, checkpoint); err != nil {
+		return err
+	}
+
+	if changedSubscriptions > 0 || len(usageTransactions) > 0 {
+		log.Printf("ReadModelBuilder: projected %d subscription changes and %d usage charges for app %s",
+			changedSubscriptions, len(usageTransactions), appID)
+	}
+	return nil
+}
+
+// ProjectSubscription projects a single subscription as soon as the ledger records a
+// change to it (Shopify webhooks). It does not advance the checkpoint, so the next
+// ProjectApp re-reads the subscription and finds nothing to do. Changes are not
+// streamed; webhooks publish their own events.
+func (b *ReadModelBuilder) ProjectSubscription(ctx context.Context, sub *domainEntity.Subscription) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, err := b.projectSubscriptions(ctx, sub.AppID, []*domainEntity.Subscription{sub}, false)
+	return err
+}
+
+// rebuild rebuilds both read models of an app and resets its checkpoint. Callers hold b.mu.
+func (b *ReadModelBuilder) rebuild(ctx context.Context, appID uuid.UUID) error {
 	log.Printf("ReadModelBuilder: rebuilding read model for app %s", appID)
-	start := time.Now()
+	start := b.now()
 
 	// Rebuild subscription statuses
 	if err := b.rebuildSubscriptionStatuses(ctx, appID); err != nil {
@@ -57,10 +179,34 @@ func (b *ReadModelBuilder) RebuildForApp(ctx context.Context, appID uuid.UUID) e
 		return err
 	}
 
-	log.Printf("ReadModelBuilder: completed rebuild for app %s in %v", appID, time.Since(start))
+	if err := b.saveRebuildCheckpoint(ctx, appID, start); err != nil {
+		return err
+	}
+
+	log.Printf("ReadModelBuilder: completed rebuild for app %s in %v", appID, b.now().Sub(start))
 	return nil
 }
 
+// saveRebuildCheckpoint marks the ledger as projected through the start of a rebuild,
+// keeping the results of the last consistency check
+func (b *ReadModelBuilder) saveRebuildCheckpoint(ctx context.Context, appID uuid.UUID, start time.Time) error {
+	if b.checkpointRepo == nil {
+		return nil
+	}
+
+	existing, err := b.checkpointRepo.GetByAppID(ctx, appID)
+	if err != nil {
+		return err
+	}
+
+	checkpoint := entity.NewProjectionCheckpoint(appID, start)
+	if existing != nil {
+		checkpoint.CheckedAt = existing.CheckedAt
+		checkpoint.DriftCount = existing.DriftCount
+	}
+	return b.checkpointRepo.Upsert(ctx, checkpoint)
+}
+
 // rebuildSubscriptionStatuses rebuilds all subscription statuses for an app
 func (b *ReadModelBuilder) rebuildSubscriptionStatuses(ctx context.Context, appID uuid.UUID) error {
 	// Get all subscriptions for the app
@@ -75,25 +221,141 @@ func (b *ReadModelBuilder) rebuildSubscriptionStatuses(ctx context.Context, appI
 		statuses[i] = b.subscriptionToStatus(sub)
 	}
 
+	changes, err := b.findSubscriptionChanges(ctx, appID, statuses)
+	if err != nil {
+		return err
+	}
+
 	// Batch upsert
-	return b.subscriptionStatusRepo.UpsertBatch(ctx, statuses)
+	if err := b.subscriptionStatusRepo.UpsertBatch(ctx, statuses); err != nil {
+		return err
+	}
+
+	b.publishChanges(ctx, changes)
+	return nil
 }
 
-// subscriptionToStatus converts a domain subscription to a status read model
-func (b *ReadModelBuilder) subscriptionToStatus(sub *domainEntity.Subscription) *entity.SubscriptionStatus {
-	now := time.Now().UTC()
+// projectSubscriptions upserts the statuses of subscriptions whose projection changed
+// and deletes those of soft-deleted subscriptions. It returns how many rows it wrote.
+func (b *ReadModelBuilder) projectSubscriptions(ctx context.Context, appID uuid.UUID, subscriptions []*domainEntity.Subscription, publish bool) (int, error) {
+	if len(subscriptions) == 0 {
+		return 0, nil
+	}
 
-	// Calculate months overdue
-	monthsOverdue := 0
-	if sub.ExpectedNextChargeDate != nil && now.After(*sub.ExpectedNextChargeDate) {
-		days := int(now.Sub(*sub.ExpectedNextChargeDate).Hours() / 24)
-		monthsOverdue = days / 30
+	gids := make([]string, len(subscriptions))
+	for i, sub := range subscriptions {
+		gids[i] = sub.ShopifyGID
+	}
+	existing, err := b.subscriptionStatusRepo.GetByShopifyGIDs(ctx, gids)
+	if err != nil {
+		return 0, err
+	}
+	previous := make(map[string]*entity.SubscriptionStatus, len(existing))
+	for _, status := range existing {
+		previous[status.ShopifyGID] = status
 	}
 
-	// Determine if paid current cycle
-	isPaidCurrentCycle := sub.Status == "ACTIVE" && sub.RiskState == valueobject.RiskStateSafe
+	var upserts []*entity.SubscriptionStatus
+	var deletes []string
+	var changes []*entity.SubscriptionChange
+	for _, sub := range subscriptions {
+		old := previous[sub.ShopifyGID]
+		if sub.IsDeleted() {
+			if old != nil {
+				deletes = append(deletes, sub.ShopifyGID)
+			}
+			continue
+		}
+
+		status := b.subscriptionToStatus(sub)
+		if old != nil && old.SameProjection(status) {
+			continue
+		}
+		upserts = append(upserts, status)
 
-	return &entity.SubscriptionStatus{
+		if change := subscriptionChange(appID, old, status); publish && change != nil {
+			changes = append(changes, change)
+		}
+	}
+
+	if err := b.subscriptionStatusRepo.UpsertBatch(ctx, upserts); err != nil {
+		return 0, err
+	}
+	if err := b.subscriptionStatusRepo.DeleteByShopifyGIDs(ctx, deletes); err != nil {
+		return 0, err
+	}
+
+	b.publishChanges(ctx, changes)
+	return len(upserts) + len(deletes), nil
+}
+
+// publishChanges streams changes found while projecting, logging failures
+func (b *ReadModelBuilder) publishChanges(ctx context.Context, changes []*entity.SubscriptionChange) {
+	for _, change := range changes {
+		if err := b.changeHub.Publish(ctx, change); err != nil {
+			log.Printf("ReadModelBuilder: failed to publish change for %s: %v", change.SubscriptionID, err)
+		}
+	}
+}
+
+// findSubscriptionChanges diffs rebuilt statuses against the current read model.
+// Like usage.billed, the first rebuild of an app is a backfill and yields nothing.
+func (b *ReadModelBuilder) findSubscriptionChanges(ctx context.Context, appID uuid.UUID, statuses []*entity.SubscriptionStatus) ([]*entity.SubscriptionChange, error) {
+	if b.changeHub == nil {
+		return nil, nil
+	}
+
+	existing, err := b.subscriptionStatusRepo.GetByAppID(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return nil, nil
+	}
+
+	previous := make(map[string]*entity.SubscriptionStatus, len(existing))
+	for _, status := range existing {
+		previous[status.ShopifyGID] = status
+	}
+
+	var changes []*entity.SubscriptionChange
+	for _, status := range statuses {
+		if change := subscriptionChange(appID, previous[status.ShopifyGID], status); change != nil {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+// subscriptionChange returns the status or risk change from old (nil if new) to status, or nil
+func subscriptionChange(appID uuid.UUID, old, status *entity.SubscriptionStatus) *entity.SubscriptionChange {
+	var prevStatus, prevRisk string
+	if old != nil {
+		prevStatus, prevRisk = old.Status, old.RiskState.String()
+	}
+	if prevStatus == status.Status && prevRisk == status.RiskState.String() {
+		return nil
+	}
+
+	return entity.NewSubscriptionChange(
+		appID,
+		status.ShopifyGID,
+		status.MyshopifyDomain,
+		prevStatus,
+		status.Status,
+		prevRisk,
+		status.RiskState.String(),
+		"ledger_sync",
+		status.LastSyncedAt,
+	)
+}
+
+// subscriptionToStatus converts a domain subscription to a status read model
+func (b *ReadModelBuilder) subscriptionToStatus(sub *domainEntity.Subscription) *entity.SubscriptionStatus {
+	now := b.now()
+
+	status := &entity.SubscriptionStatus{
 		ID:                       uuid.New(),
 		ShopifyGID:               sub.ShopifyGID,
 		AppID:                    sub.AppID,
@@ -101,51 +363,69 @@ func (b *ReadModelBuilder) subscriptionToStatus(sub *domainEntity.Subscription)
 		ShopName:                 sub.ShopName,
 		PlanName:                 sub.PlanName,
 		RiskState:                sub.RiskState,
-		IsPaidCurrentCycle:       isPaidCurrentCycle,
-		MonthsOverdue:            monthsOverdue,
 		LastSuccessfulChargeDate: sub.LastRecurringChargeDate,
 		ExpectedNextChargeDate:   sub.ExpectedNextChargeDate,
 		Status:                   sub.Status,
 		LastSyncedAt:             now,
 	}
+	status.Derive(now)
+	return status
 }
 
-// rebuildUsageStatuses rebuilds all usage statuses for an app
+// rebuildUsageStatuses rebuilds the usage statuses of the last 12 months of transactions
 func (b *ReadModelBuilder) rebuildUsageStatuses(ctx context.Context, appID uuid.UUID) error {
-	// Get all subscriptions first (to map usage to subscriptions)
-	subscriptions, err := b.subscriptionRepo.FindByAppID(ctx, appID)
+	now := b.now()
+	allTransactions, err := b.transactionRepo.FindByAppID(ctx, appID, now.AddDate(-1, 0, 0), now)
 	if err != nil {
 		return err
 	}
 
-	// Get all transactions for the app (last 12 months)
-	now := time.Now()
-	from := now.AddDate(-1, 0, 0)
-	allTransactions, err := b.transactionRepo.FindByAppID(ctx, appID, from, now)
-	if err != nil {
-		return err
-	}
+	return b.projectUsageTransactions(ctx, appID, filterUsageTransactions(allTransactions))
+}
 
-	// Filter to USAGE transactions only
+// filterUsageTransactions returns the USAGE transactions, oldest first so records match in order
+func filterUsageTransactions(allTransactions []*domainEntity.Transaction) []*domainEntity.Transaction {
 	transactions := make([]*domainEntity.Transaction, 0)
 	for _, txn := range allTransactions {
 		if txn.ChargeType == valueobject.ChargeTypeUsage {
 			transactions = append(transactions, txn)
 		}
 	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].TransactionDate.Before(transactions[j].TransactionDate)
+	})
+	return transactions
+}
 
+// projectUsageTransactions upserts the usage statuses of usage transactions
+func (b *ReadModelBuilder) projectUsageTransactions(ctx context.Context, appID uuid.UUID, transactions []*domainEntity.Transaction) error {
 	if len(transactions) == 0 {
 		return nil
 	}
 
+	// Get all subscriptions (to map usage to subscriptions)
+	subscriptions, err := b.subscriptionRepo.FindByAppID(ctx, appID)
+	if err != nil {
+		return err
+	}
+
 	// Build subscription map by domain for matching
 	subByDomain := make(map[string]*domainEntity.Subscription)
 	for _, sub := range subscriptions {
 		subByDomain[sub.MyshopifyDomain] = sub
 	}
 
-	// Convert to usage status entities
+	now := b.now()
+	reported, err := b.usageStatusRepo.GetReportedByAppID(ctx, appID, now.AddDate(-1, 0, 0), now.Add(time.Hour))
+	if err != nil {
+		return err
+	}
+	matcher := newUsageMatcher(reported)
+
+	// Convert to usage status entities. Transactions billing a reported record
+	// update that record instead of getting a row of their own.
 	statuses := make([]*entity.UsageStatus, 0, len(transactions))
+	var superseded []string
 	for _, txn := range transactions {
 		// Find the parent subscription
 		sub := subByDomain[txn.MyshopifyDomain]
@@ -154,6 +434,13 @@ func (b *ReadModelBuilder) rebuildUsageStatuses(ctx context.Context, appID uuid.
 			continue
 		}
 
+		if record := matcher.match(txn, sub); record != nil {
+			record.MarkBilled(txn.ShopifyGID, txn.TransactionDate, int(txn.GrossAmountCents))
+			statuses = append(statuses, record)
+			superseded = append(superseded, txn.ShopifyGID)
+			continue
+		}
+
 		status := b.transactionToUsageStatus(txn, sub)
 		statuses = append(statuses, status)
 	}
@@ -162,8 +449,63 @@ func (b *ReadModelBuilder) rebuildUsageStatuses(ctx context.Context, appID uuid.
 		return nil
 	}
 
+	// Drop rows synced for these transactions before their records were reported
+	if err := b.usageStatusRepo.DeleteByShopifyGIDs(ctx, superseded); err != nil {
+		return err
+	}
+
+	newlyBilled, err := b.findNewlyBilled(ctx, statuses)
+	if err != nil {
+		return err
+	}
+
 	// Batch upsert
-	return b.usageStatusRepo.UpsertBatch(ctx, statuses)
+	if err := b.usageStatusRepo.UpsertBatch(ctx, statuses); err != nil {
+		return err
+	}
+
+	for _, status := range newlyBilled {
+		if err := b.webhookPublisher.PublishUsageBilled(ctx, appID, status); err != nil {
+			log.Printf("ReadModelBuilder: failed to publish usage.billed for %s: %v", status.ShopifyGID, err)
+		}
+	}
+
+	return nil
+}
+
+// findNewlyBilled returns the billed statuses that were unbilled or unknown before
+// this rebuild. The first rebuild of an app is a backfill and publishes nothing.
+func (b *ReadModelBuilder) findNewlyBilled(ctx context.Context, statuses []*entity.UsageStatus) ([]*entity.UsageStatus, error) {
+	if b.webhookPublisher == nil {
+		return nil, nil
+	}
+
+	gids := make([]string, len(statuses))
+	for i, status := range statuses {
+		gids[i] = status.ShopifyGID
+	}
+
+	existing, err := b.usageStatusRepo.GetByShopifyGIDs(ctx, gids)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return nil, nil
+	}
+
+	wasBilled := make(map[string]bool, len(existing))
+	for _, status := range existing {
+		wasBilled[status.ShopifyGID] = status.Billed
+	}
+
+	var newlyBilled []*entity.UsageStatus
+	for _, status := range statuses {
+		if status.Billed && !wasBilled[status.ShopifyGID] {
+			newlyBilled = append(newlyBilled, status)
+		}
+	}
+
+	return newlyBilled, nil
 }
 
 // transactionToUsageStatus converts a usage transaction to a status read model
@@ -173,10 +515,68 @@ func (b *ReadModelBuilder) transactionToUsageStatus(txn *domainEntity.Transactio
 		ShopifyGID:             txn.ShopifyGID,
 		SubscriptionShopifyGID: sub.ShopifyGID,
 		SubscriptionID:         sub.ID,
+		AppID:                  sub.AppID,
 		Billed:                 true, // If we have a transaction, it's billed
 		BillingDate:            &txn.TransactionDate,
 		AmountCents:            int(txn.NetAmountCents),
 		Description:            "", // Not stored in transaction
-		LastSyncedAt:           time.Now().UTC(),
+		LastSyncedAt:           b.now(),
+		Source:                 entity.UsageSourceLedger,
+	}
+}
+
+// usageMatcher pairs usage transactions with the usage records apps reported
+type usageMatcher struct {
+	byRecordID    map[string]*entity.UsageStatus   // Keyed by the record's numeric ID
+	byTransaction map[string]*entity.UsageStatus   // Records already billed, by transaction GID
+	unbilled      map[string][]*entity.UsageStatus // Unbilled records by subscription GID, oldest first
+}
+
+func newUsageMatcher(reported []*entity.UsageStatus) *usageMatcher {
+	m := &usageMatcher{
+		byRecordID:    make(map[string]*entity.UsageStatus, len(reported)),
+		byTransaction: make(map[string]*entity.UsageStatus),
+		unbilled:      make(map[string][]*entity.UsageStatus),
 	}
+	for _, record := range reported {
+		m.byRecordID[gidNumericID(record.ShopifyGID)] = record
+		if record.Billed {
+			m.byTransaction[record.BilledTransactionGID] = record
+			continue
+		}
+		m.unbilled[record.SubscriptionShopifyGID] = append(m.unbilled[record.SubscriptionShopifyGID], record)
+	}
+	return m
+}
+
+// match returns the reported record a transaction billed, or nil. Transactions
+// name their usage record (chargeId); without it, the oldest unbilled record of
+// the subscription for the same amount reported before the transaction is taken.
+func (m *usageMatcher) match(txn *domainEntity.Transaction, sub *domainEntity.Subscription) *entity.UsageStatus {
+	if record := m.byTransaction[txn.ShopifyGID]; record != nil {
+		return record
+	}
+
+	var record *entity.UsageStatus
+	if txn.ChargeGID != "" {
+		if r := m.byRecordID[gidNumericID(txn.ChargeGID)]; r != nil && !r.Billed {
+			record = r
+		}
+	} else {
+		for _, r := range m.unbilled[sub.ShopifyGID] {
+			if !r.Billed && int64(r.AmountCents) == txn.GrossAmountCents && !r.ReportedAt.After(txn.TransactionDate) {
+				record = r
+				break
+			}
+		}
+	}
+	if record != nil {
+		m.byTransaction[txn.ShopifyGID] = record
+	}
+	return record
+}
+
+// gidNumericID returns the trailing ID of a GID, so gid://shopify/AppUsageRecord/1 and 1 compare equal
+func gidNumericID(gid string) string {
+	return gid[strings.LastIndex(gid, "/")+1:]
 }
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	domainEntity "github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+type memProjectionCheckpointRepo struct {
+	checkpoints map[uuid.UUID]*entity.ProjectionCheckpoint
+}
+
+func (m *memProjectionCheckpointRepo) GetByAppID(ctx context.Context, appID uuid.UUID) (*entity.ProjectionCheckpoint, error) {
+	if checkpoint, ok := m.checkpoints[appID]; ok {
+		copied := *checkpoint
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *memProjectionCheckpointRepo) Upsert(ctx context.Context, checkpoint *entity.ProjectionCheckpoint) error {
+	copied := *checkpoint
+	m.checkpoints[checkpoint.AppID] = &copied
+	return nil
+}
+
+func newTestProjectedSubscription(appID uuid.UUID, n, status string, updatedAt, now time.Time) *domainEntity.Subscription {
+	next := now.AddDate(0, 0, 10)
+	return &domainEntity.Subscription{
+		ID:                     uuid.New(),
+		AppID:                  appID,
+		ShopifyGID:             "gid://shopify/AppSubscription/" + n,
+		MyshopifyDomain:        "store" + n + ".myshopify.com",
+		PlanName:               "Pro",
+		Status:                 status,
+		RiskState:              valueobject.RiskStateSafe,
+		ExpectedNextChargeDate: &next,
+		UpdatedAt:              updatedAt,
+	}
+}
+
+func newTestProjectedUsageSale(n string, sub *domainEntity.Subscription, storedAt time.Time) *domainEntity.Transaction {
+	txn := domainEntity.NewTransaction(sub.AppID, "gid://partners/AppUsageSale/"+n, sub.MyshopifyDomain, "Store",
+		valueobject.ChargeTypeUsage, 500, 400, "USD", storedAt)
+	txn.CreatedAt = storedAt
+	return txn
+}
+
+func TestReadModelBuilder_ProjectApp(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rebuilds without a checkpoint, then only writes changes", func(t *testing.T) {
+		appID := uuid.New()
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		unchanged := newTestProjectedSubscription(appID, "1", "ACTIVE", now.Add(-48*time.Hour), now)
+		cancelled := newTestProjectedSubscription(appID, "2", "ACTIVE", now.Add(-48*time.Hour), now)
+		subRepo := &stubLedgerSubscriptionRepo{subscriptions: []*domainEntity.Subscription{unchanged, cancelled}}
+		txRepo := &stubTransactionRepo{transactions: []*domainEntity.Transaction{
+			newTestProjectedUsageSale("1", unchanged, now.Add(-48*time.Hour)),
+		}}
+		statusRepo := &memSubscriptionStatusRepo{}
+		usageRepo := newMemUsageStatusRepo()
+		checkpointRepo := &memProjectionCheckpointRepo{checkpoints: make(map[uuid.UUID]*entity.ProjectionCheckpoint)}
+		builder := NewReadModelBuilder(subRepo, txRepo, statusRepo, usageRepo).WithCheckpointRepo(checkpointRepo)
+		builder.now = func() time.Time { return now }
+
+		if err := builder.ProjectApp(ctx, appID); err != nil {
+			t.Fatalf("first ProjectApp: %v", err)
+		}
+		checkpoint := checkpointRepo.checkpoints[appID]
+		if checkpoint == nil || checkpoint.RebuiltAt == nil || !checkpoint.SubscriptionsThrough.Equal(now) {
+			t.Fatalf("expected rebuild checkpoint at %v, got %+v", now, checkpoint)
+		}
+		if len(statusRepo.statuses) != 2 || len(usageRepo.statuses) != 1 {
+			t.Fatalf("expected 2 subscription and 1 usage rows, got %d and %d", len(statusRepo.statuses), len(usageRepo.statuses))
+		}
+		rebuiltAt := now
+
+		// Later: one subscription is cancelled, one is added, one usage charge is stored
+		now = now.Add(3 * time.Hour)
+		cancelled.Status = "CANCELLED"
+		cancelled.RiskState = valueobject.RiskStateChurned
+		cancelled.UpdatedAt = now.Add(-time.Hour)
+		added := newTestProjectedSubscription(appID, "3", "ACTIVE", now.Add(-30*time.Minute), now)
+		subRepo.subscriptions = append(subRepo.subscriptions, added)
+		sale := newTestProjectedUsageSale("2", added, now.Add(-10*time.Minute))
+		txRepo.transactions = append(txRepo.transactions, sale)
+
+		if err := builder.ProjectApp(ctx, appID); err != nil {
+			t.Fatalf("second ProjectApp: %v", err)
+		}
+
+		status, _ := statusRepo.GetByShopifyGID(ctx, unchanged.ShopifyGID)
+		if !status.LastSyncedAt.Equal(rebuiltAt) {
+			t.Errorf("unchanged subscription should not be rewritten, synced at %v", status.LastSyncedAt)
+		}
+		status, _ = statusRepo.GetByShopifyGID(ctx, cancelled.ShopifyGID)
+		if status.Status != "CANCELLED" || !status.LastSyncedAt.Equal(now) {
+			t.Errorf("expected cancelled subscription projected at %v, got %s at %v", now, status.Status, status.LastSyncedAt)
+		}
+		if _, err := statusRepo.GetByShopifyGID(ctx, added.ShopifyGID); err != nil {
+			t.Errorf("expected new subscription projected: %v", err)
+		}
+		if _, ok := usageRepo.statuses[sale.ShopifyGID]; !ok {
+			t.Error("expected new usage charge projected")
+		}
+
+		checkpoint = checkpointRepo.checkpoints[appID]
+		if !checkpoint.SubscriptionsThrough.Equal(added.UpdatedAt) || !checkpoint.TransactionsThrough.Equal(sale.CreatedAt) {
+			t.Errorf("checkpoint not advanced to what was read: %+v", checkpoint)
+		}
+		if !checkpoint.RebuiltAt.Equal(rebuiltAt) {
+			t.Errorf("incremental projection should keep RebuiltAt, got %v", checkpoint.RebuiltAt)
+		}
+	})
+}
+
+func TestReadModelBuilder_ProjectSubscription(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("removes an uninstalled subscription", func(t *testing.T) {
+		appID := uuid.New()
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		sub := newTestProjectedSubscription(appID, "1", "ACTIVE", now.Add(-time.Hour), now)
+		statusRepo := &memSubscriptionStatusRepo{}
+		checkpointRepo := &memProjectionCheckpointRepo{checkpoints: make(map[uuid.UUID]*entity.ProjectionCheckpoint)}
+		builder := NewReadModelBuilder(
+			&stubLedgerSubscriptionRepo{subscriptions: []*domainEntity.Subscription{sub}},
+			&stubTransactionRepo{},
+			statusRepo,
+			newMemUsageStatusRepo(),
+		).WithCheckpointRepo(checkpointRepo)
+		builder.now = func() time.Time { return now }
+
+		if err := builder.ProjectSubscription(ctx, sub); err != nil {
+			t.Fatalf("ProjectSubscription: %v", err)
+		}
+		if len(statusRepo.statuses) != 1 {
+			t.Fatalf("expected subscription projected, got %d rows", len(statusRepo.statuses))
+		}
+
+		sub.Status = "UNINSTALLED"
+		sub.SoftDelete()
+		if err := builder.ProjectSubscription(ctx, sub); err != nil {
+			t.Fatalf("ProjectSubscription: %v", err)
+		}
+		if len(statusRepo.statuses) != 0 {
+			t.Errorf("expected uninstalled subscription removed, got %d rows", len(statusRepo.statuses))
+		}
+		if checkpointRepo.checkpoints[appID] != nil {
+			t.Error("single subscription projections must not move the checkpoint")
+		}
+	})
+}
+
+func TestReadModelBuilder_CheckConsistency(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports and repairs drift", func(t *testing.T) {
+		appID := uuid.New()
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		missing := newTestProjectedSubscription(appID, "1", "ACTIVE", now.Add(-time.Hour), now)
+		stale := newTestProjectedSubscription(appID, "2", "ACTIVE", now.Add(-time.Hour), now)
+		other := newTestProjectedSubscription(appID, "3", "ACTIVE", now.Add(-time.Hour), now)
+		sale := newTestProjectedUsageSale("1", stale, now.Add(-time.Hour))
+		statusRepo := &memSubscriptionStatusRepo{}
+		usageRepo := newMemUsageStatusRepo()
+		checkpointRepo := &memProjectionCheckpointRepo{checkpoints: make(map[uuid.UUID]*entity.ProjectionCheckpoint)}
+		builder := NewReadModelBuilder(
+			&stubLedgerSubscriptionRepo{subscriptions: []*domainEntity.Subscription{missing, stale, other}},
+			&stubTransactionRepo{transactions: []*domainEntity.Transaction{sale}},
+			statusRepo,
+			usageRepo,
+		).WithCheckpointRepo(checkpointRepo)
+		builder.now = func() time.Time { return now }
+
+		if err := builder.RebuildForApp(ctx, appID); err != nil {
+			t.Fatalf("RebuildForApp: %v", err)
+		}
+
+		// Drift the read model away from the ledger
+		statusRepo.DeleteByShopifyGIDs(ctx, []string{missing.ShopifyGID})
+		stale.PlanName = "Enterprise"
+		statusRepo.Upsert(ctx, &entity.SubscriptionStatus{ShopifyGID: "gid://shopify/AppSubscription/9", AppID: appID})
+		usageRepo.DeleteByShopifyGIDs(ctx, []string{sale.ShopifyGID})
+
+		report, err := builder.CheckConsistency(ctx, appID, true)
+		if err != nil {
+			t.Fatalf("CheckConsistency: %v", err)
+		}
+
+		want := map[string]string{
+			missing.ShopifyGID:                entity.DriftMissing,
+			stale.ShopifyGID:                  entity.DriftStale,
+			"gid://shopify/AppSubscription/9": entity.DriftOrphaned,
+			sale.ShopifyGID:                   entity.DriftMissing,
+		}
+		if len(report.Drift) != len(want) {
+			t.Fatalf("expected %d drifted rows, got %+v", len(want), report.Drift)
+		}
+		for _, drift := range report.Drift {
+			if want[drift.ShopifyGID] != drift.Problem {
+				t.Errorf("%s: expected %s, got %s", drift.ShopifyGID, want[drift.ShopifyGID], drift.Problem)
+			}
+		}
+		if !report.Repaired || report.Subscriptions != 3 || report.UsageCharges != 1 {
+			t.Errorf("unexpected report %+v", report)
+		}
+
+		report, err = builder.CheckConsistency(ctx, appID, false)
+		if err != nil {
+			t.Fatalf("CheckConsistency after repair: %v", err)
+		}
+		if !report.Consistent() {
+			t.Errorf("expected consistent read model after repair, got %+v", report.Drift)
+		}
+		checkpoint := checkpointRepo.checkpoints[appID]
+		if checkpoint.CheckedAt == nil || checkpoint.DriftCount != 0 {
+			t.Errorf("expected check recorded on checkpoint, got %+v", checkpoint)
+		}
+	})
+}
+
+func TestReadModelBuilder_DerivesTimeDependentFields(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		dueAgo        time.Duration
+		monthsOverdue int
+		paid          bool
+	}{
+		{"not yet due", -5 * 24 * time.Hour, 0, true},
+		{"within grace period", 10 * 24 * time.Hour, 0, true},
+		{"past grace period", 31 * 24 * time.Hour, 1, false},
+		{"two months overdue", 65 * 24 * time.Hour, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewReadModelBuilder(&stubLedgerSubscriptionRepo{}, &stubTransactionRepo{}, &memSubscriptionStatusRepo{}, newMemUsageStatusRepo())
+			builder.now = func() time.Time { return now }
+
+			sub := newTestProjectedSubscription(uuid.New(), "1", "ACTIVE", now, now)
+			due := now.Add(-tt.dueAgo)
+			sub.ExpectedNextChargeDate = &due
+
+			status := builder.subscriptionToStatus(sub)
+			if status.MonthsOverdue != tt.monthsOverdue || status.IsPaidCurrentCycle != tt.paid {
+				t.Errorf("expected %d months overdue and paid=%v, got %d and %v",
+					tt.monthsOverdue, tt.paid, status.MonthsOverdue, status.IsPaidCurrentCycle)
+			}
+
+			// Read later, the same row ages without being rewritten
+			status.Derive(due.Add(95 * 24 * time.Hour))
+			if status.MonthsOverdue != 3 || status.IsPaidCurrentCycle {
+				t.Errorf("expected 3 months overdue and unpaid later, got %d and %v", status.MonthsOverdue, status.IsPaidCurrentCycle)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	domainEntity "github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// CheckConsistency compares an app's read model with the projection of its ledger:
+// every subscription and the last 12 months of usage charges. With repair, drifted
+// apps are rebuilt and orphaned subscription statuses deleted. The result is
+// recorded on the app's checkpoint.
+func (b *ReadModelBuilder) CheckConsistency(ctx context.Context, appID uuid.UUID, repair bool) (*entity.ReadModelConsistencyReport, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	report := &entity.ReadModelConsistencyReport{
+		AppID:     appID,
+		CheckedAt: b.now(),
+		Drift:     []entity.ReadModelDrift{},
+	}
+
+	subscriptions, err := b.subscriptionRepo.FindByAppID(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	orphaned, err := b.checkSubscriptionStatuses(ctx, appID, subscriptions, report)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.checkUsageStatuses(ctx, appID, subscriptions, report); err != nil {
+		return nil, err
+	}
+
+	if repair && !report.Consistent() {
+		if err := b.rebuild(ctx, appID); err != nil {
+			return nil, err
+		}
+		if err := b.subscriptionStatusRepo.DeleteByShopifyGIDs(ctx, orphaned); err != nil {
+			return nil, err
+		}
+		report.Repaired = true
+	}
+
+	if err := b.saveCheckResult(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// checkSubscriptionStatuses adds subscription drift to the report and returns the orphaned GIDs
+func (b *ReadModelBuilder) checkSubscriptionStatuses(ctx context.Context, appID uuid.UUID, subscriptions []*domainEntity.Subscription, report *entity.ReadModelConsistencyReport) ([]string, error) {
+	statuses, err := b.subscriptionStatusRepo.GetByAppID(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]*entity.SubscriptionStatus, len(statuses))
+	for _, status := range statuses {
+		projected[status.ShopifyGID] = status
+	}
+
+	report.Subscriptions = len(subscriptions)
+	for _, sub := range subscriptions {
+		status, ok := projected[sub.ShopifyGID]
+		delete(projected, sub.ShopifyGID)
+		switch {
+		case !ok:
+			report.AddDrift(entity.ReadModelSubscription, sub.ShopifyGID, entity.DriftMissing)
+		case !status.SameProjection(b.subscriptionToStatus(sub)):
+			report.AddDrift(entity.ReadModelSubscription, sub.ShopifyGID, entity.DriftStale)
+		}
+	}
+
+	var orphaned []string
+	for gid := range projected {
+		report.AddDrift(entity.ReadModelSubscription, gid, entity.DriftOrphaned)
+		orphaned = append(orphaned, gid)
+	}
+
+	return orphaned, nil
+}
+
+// checkUsageStatuses adds usage drift to the report. Each usage charge must be
+// projected either as a ledger row or as the billing of a reported record, not both.
+func (b *ReadModelBuilder) checkUsageStatuses(ctx context.Context, appID uuid.UUID, subscriptions []*domainEntity.Subscription, report *entity.ReadModelConsistencyReport) error {
+	now := b.now()
+	allTransactions, err := b.transactionRepo.FindByAppID(ctx, appID, now.AddDate(-1, 0, 0), now)
+	if err != nil {
+		return err
+	}
+
+	subByDomain := make(map[string]*domainEntity.Subscription, len(subscriptions))
+	for _, sub := range subscriptions {
+		subByDomain[sub.MyshopifyDomain] = sub
+	}
+
+	var transactions []*domainEntity.Transaction
+	var gids []string
+	for _, txn := range filterUsageTransactions(allTransactions) {
+		if subByDomain[txn.MyshopifyDomain] != nil {
+			transactions = append(transactions, txn)
+			gids = append(gids, txn.ShopifyGID)
+		}
+	}
+	report.UsageCharges = len(transactions)
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	rows, err := b.usageStatusRepo.GetByShopifyGIDs(ctx, gids)
+	if err != nil {
+		return err
+	}
+	ledgerRows := make(map[string]*entity.UsageStatus, len(rows))
+	for _, row := range rows {
+		ledgerRows[row.ShopifyGID] = row
+	}
+
+	reported, err := b.usageStatusRepo.GetReportedByAppID(ctx, appID, now.AddDate(-1, 0, 0), now.Add(time.Hour))
+	if err != nil {
+		return err
+	}
+	billedRecords := make(map[string]bool, len(reported))
+	for _, record := range reported {
+		if record.Billed {
+			billedRecords[record.BilledTransactionGID] = true
+		}
+	}
+
+	for _, txn := range transactions {
+		row := ledgerRows[txn.ShopifyGID]
+		sub := subByDomain[txn.MyshopifyDomain]
+		switch {
+		case billedRecords[txn.ShopifyGID]:
+			if row != nil {
+				report.AddDrift(entity.ReadModelUsage, txn.ShopifyGID, entity.DriftOrphaned)
+			}
+		case row == nil:
+			report.AddDrift(entity.ReadModelUsage, txn.ShopifyGID, entity.DriftMissing)
+		case !row.Billed || row.AmountCents != int(txn.NetAmountCents) || row.SubscriptionShopifyGID != sub.ShopifyGID:
+			report.AddDrift(entity.ReadModelUsage, txn.ShopifyGID, entity.DriftStale)
+		}
+	}
+
+	return nil
+}
+
+// saveCheckResult records when the app was checked and how much drift was found
+func (b *ReadModelBuilder) saveCheckResult(ctx context.Context, report *entity.ReadModelConsistencyReport) error {
+	if b.checkpointRepo == nil {
+		return nil
+	}
+
+	checkpoint, err := b.checkpointRepo.GetByAppID(ctx, report.AppID)
+	if err != nil {
+		return err
+	}
+	if checkpoint == nil {
+		// Never projected; the first ProjectApp rebuilds the app anyway
+		return nil
+	}
+
+	checkpoint.CheckedAt = &report.CheckedAt
+	checkpoint.DriftCount = len(report.Drift)
+	return b.checkpointRepo.Upsert(ctx, checkpoint)
+}
+
+// ReadModelConsistencyChecker periodically checks and repairs the read model of every app
+type ReadModelConsistencyChecker struct {
+	builder     *ReadModelBuilder
+	partnerRepo repository.PartnerAccountRepository
+	appRepo     repository.AppRepository
+	interval    time.Duration
+	stopCh      chan struct{}
+	doneCh      chan struct{}
+}
+
+// NewReadModelConsistencyChecker creates a new ReadModelConsistencyChecker running every 6 hours
+func NewReadModelConsistencyChecker(
+	builder *ReadModelBuilder,
+	partnerRepo repository.PartnerAccountRepository,
+	appRepo repository.AppRepository,
+) *ReadModelConsistencyChecker {
+	return &ReadModelConsistencyChecker{
+		builder:     builder,
+		partnerRepo: partnerRepo,
+		appRepo:     appRepo,
+		interval:    6 * time.Hour,
+		stopCh:      make(chan struct{}),
+		doneCh:      make(chan struct{}),
+	}
+}
+
+// WithInterval sets how often all apps are checked
+func (c *ReadModelConsistencyChecker) WithInterval(interval time.Duration) *ReadModelConsistencyChecker {
+	c.interval = interval
+	return c
+}
+
+// Start begins periodic checks
+func (c *ReadModelConsistencyChecker) Start(ctx context.Context) {
+	go c.run(ctx)
+}
+
+// Stop gracefully stops the checker after the current app
+func (c *ReadModelConsistencyChecker) Stop() {
+	close(c.stopCh)
+	<-c.doneCh
+}
+
+func (c *ReadModelConsistencyChecker) run(ctx context.Context) {
+	defer close(c.doneCh)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := c.CheckAll(ctx); err != nil {
+				log.Printf("ReadModelConsistencyChecker: %v", err)
+			}
+		case <-c.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// CheckAll checks and repairs every app and returns how many had drifted
+func (c *ReadModelConsistencyChecker) CheckAll(ctx context.Context) (int, error) {
+	partnerAccountIDs, err := c.partnerRepo.GetAllIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	drifted := 0
+	for _, partnerAccountID := range partnerAccountIDs {
+		apps, err := c.appRepo.FindByPartnerAccountID(ctx, partnerAccountID)
+		if err != nil {
+			log.Printf("ReadModelConsistencyChecker: failed to list apps for partner %s: %v", partnerAccountID, err)
+			continue
+		}
+
+		for _, app := range apps {
+			select {
+			case <-c.stopCh:
+				return drifted, nil
+			default:
+			}
+
+			report, err := c.builder.CheckConsistency(ctx, app.ID, true)
+			if err != nil {
+				log.Printf("ReadModelConsistencyChecker: failed to check app %s: %v", app.ID, err)
+				continue
+			}
+			if !report.Consistent() {
+				drifted++
+				log.Printf("ReadModelConsistencyChecker: repaired %d drifted rows for app %s", len(report.Drift), app.ID)
+			}
+		}
+	}
+
+	return drifted, nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	domainEntity "github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+const (
+	// DefaultSubscriptionChangeBufferSize is how many recent changes are kept for Last-Event-ID resumes
+	DefaultSubscriptionChangeBufferSize = 1000
+
+	subscriptionStreamChannelSize = 64
+)
+
+// SubscriptionChangeTransport carries changes to every replica. Implementations
+// must hand each published change (including this replica's) back to Broadcast.
+// Without a transport the hub fans out in-process only.
+type SubscriptionChangeTransport interface {
+	Publish(ctx context.Context, change *entity.SubscriptionChange) error
+}
+
+// SubscriptionChangeHub fans subscription changes out to stream subscribers and
+// keeps a ring buffer of recent changes so clients can resume after a reconnect.
+type SubscriptionChangeHub struct {
+	transport SubscriptionChangeTransport
+
+	mu          sync.Mutex
+	buffer      []*entity.SubscriptionChange // Ring buffer, oldest at start
+	start       int
+	bufferSize  int
+	subscribers map[*SubscriptionChangeStream]struct{}
+}
+
+// NewSubscriptionChangeHub creates an in-process hub keeping bufferSize recent changes
+func NewSubscriptionChangeHub(bufferSize int) *SubscriptionChangeHub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultSubscriptionChangeBufferSize
+	}
+	return &SubscriptionChangeHub{
+		buffer:      make([]*entity.SubscriptionChange, 0, bufferSize),
+		bufferSize:  bufferSize,
+		subscribers: make(map[*SubscriptionChangeStream]struct{}),
+	}
+}
+
+// WithTransport routes published changes through a cross-replica transport
+func (h *SubscriptionChangeHub) WithTransport(transport SubscriptionChangeTransport) *SubscriptionChangeHub {
+	h.transport = transport
+	return h
+}
+
+// Publish sends a change to all subscribers, through the transport if configured
+func (h *SubscriptionChangeHub) Publish(ctx context.Context, change *entity.SubscriptionChange) error {
+	if h.transport != nil {
+		return h.transport.Publish(ctx, change)
+	}
+	h.Broadcast(change)
+	return nil
+}
+
+// PublishSubscriptionEvent publishes a recorded lifecycle event (see WebhookService.WithEventPublisher)
+func (h *SubscriptionChangeHub) PublishSubscriptionEvent(ctx context.Context, sub *domainEntity.Subscription, event *domainEntity.SubscriptionEvent) error {
+	return h.Publish(ctx, entity.NewSubscriptionChange(
+		sub.AppID,
+		sub.ShopifyGID,
+		sub.MyshopifyDomain,
+		event.FromStatus,
+		event.ToStatus,
+		event.FromRiskState.String(),
+		event.ToRiskState.String(),
+		event.EventType,
+		event.OccurredAt,
+	))
+}
+
+// Broadcast records a change and delivers it to this replica's subscribers.
+// A subscriber whose channel is full is dropped; its client resumes with Last-Event-ID.
+func (h *SubscriptionChangeHub) Broadcast(change *entity.SubscriptionChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.buffer) < h.bufferSize {
+		h.buffer = append(h.buffer, change)
+	} else {
+		h.buffer[h.start] = change
+		h.start = (h.start + 1) % h.bufferSize
+	}
+
+	for stream := range h.subscribers {
+		if !stream.matches(change) {
+			continue
+		}
+		select {
+		case stream.ch <- change:
+		default:
+			h.removeLocked(stream)
+		}
+	}
+}
+
+// Subscribe registers a stream for changes of the given apps. If lastEventID is
+// still buffered, the changes after it are returned in Replay and Resumed is true.
+func (h *SubscriptionChangeHub) Subscribe(appIDs []uuid.UUID, lastEventID string) *SubscriptionChangeStream {
+	apps := make(map[uuid.UUID]bool, len(appIDs))
+	for _, id := range appIDs {
+		apps[id] = true
+	}
+
+	stream := &SubscriptionChangeStream{
+		hub:  h,
+		apps: apps,
+		ch:   make(chan *entity.SubscriptionChange, subscriptionStreamChannelSize),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Replay and registration happen under the same lock so no change is missed or duplicated
+	if lastEventID != "" {
+		found := false
+		for i := 0; i < len(h.buffer); i++ {
+			change := h.buffer[(h.start+i)%len(h.buffer)]
+			if found && stream.matches(change) {
+				stream.Replay = append(stream.Replay, change)
+			}
+			if change.ID == lastEventID {
+				found = true
+			}
+		}
+		stream.Resumed = found
+	}
+
+	h.subscribers[stream] = struct{}{}
+	stream.C = stream.ch
+	return stream
+}
+
+// SubscriberCount returns the number of open streams on this replica
+func (h *SubscriptionChangeHub) SubscriberCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
+
+func (h *SubscriptionChangeHub) removeLocked(stream *SubscriptionChangeStream) {
+	if _, ok := h.subscribers[stream]; ok {
+		delete(h.subscribers, stream)
+		close(stream.ch)
+	}
+}
+
+// SubscriptionChangeStream is one client's subscription to the hub
+type SubscriptionChangeStream struct {
+	// C receives live changes. It is closed when the stream falls too far behind.
+	C <-chan *entity.SubscriptionChange
+	// Replay holds buffered changes after the requested Last-Event-ID
+	Replay []*entity.SubscriptionChange
+	// Resumed is true if the requested Last-Event-ID was found in the buffer
+	Resumed bool
+
+	hub  *SubscriptionChangeHub
+	apps map[uuid.UUID]bool
+	ch   chan *entity.SubscriptionChange
+}
+
+// Close unsubscribes the stream
+func (s *SubscriptionChangeStream) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.removeLocked(s)
+}
+
+func (s *SubscriptionChangeStream) matches(change *entity.SubscriptionChange) bool {
+	return s.apps[change.AppID]
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	domainEntity "github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+func newTestChange(appID uuid.UUID, status string) *entity.SubscriptionChange {
+	return entity.NewSubscriptionChange(appID, "gid://shopify/AppSubscription/1", "store.myshopify.com",
+		"ACTIVE", status, "SAFE", "SAFE", "webhook", time.Now())
+}
+
+type recordingTransport struct {
+	hub       *SubscriptionChangeHub
+	published []*entity.SubscriptionChange
+}
+
+func (t *recordingTransport) Publish(ctx context.Context, change *entity.SubscriptionChange) error {
+	t.published = append(t.published, change)
+	t.hub.Broadcast(change)
+	return nil
+}
+
+func TestSubscriptionChangeHub_DeliversOnlySubscribedApps(t *testing.T) {
+	hub := NewSubscriptionChangeHub(10)
+	appA, appB := uuid.New(), uuid.New()
+
+	stream := hub.Subscribe([]uuid.UUID{appA}, "")
+	defer stream.Close()
+
+	hub.Publish(context.Background(), newTestChange(appB, "CANCELLED"))
+	want := newTestChange(appA, "FROZEN")
+	hub.Publish(context.Background(), want)
+
+	select {
+	case got := <-stream.C:
+		if got.ID != want.ID {
+			t.Errorf("expected change %s, got %s", want.ID, got.ID)
+		}
+	default:
+		t.Fatal("expected a change for the subscribed app")
+	}
+	if len(stream.C) != 0 {
+		t.Errorf("expected no other changes, got %d", len(stream.C))
+	}
+}
+
+func TestSubscriptionChangeHub_ResumeFromLastEventID(t *testing.T) {
+	hub := NewSubscriptionChangeHub(10)
+	appA, appB := uuid.New(), uuid.New()
+
+	first := newTestChange(appA, "FROZEN")
+	hub.Broadcast(first)
+	hub.Broadcast(newTestChange(appB, "FROZEN"))
+	second := newTestChange(appA, "CANCELLED")
+	hub.Broadcast(second)
+
+	stream := hub.Subscribe([]uuid.UUID{appA}, first.ID)
+	defer stream.Close()
+
+	if !stream.Resumed {
+		t.Fatal("expected stream to resume")
+	}
+	if len(stream.Replay) != 1 || stream.Replay[0].ID != second.ID {
+		t.Errorf("expected replay of the later change only, got %v", stream.Replay)
+	}
+}
+
+func TestSubscriptionChangeHub_UnknownLastEventID(t *testing.T) {
+	hub := NewSubscriptionChangeHub(2)
+	appID := uuid.New()
+
+	evicted := newTestChange(appID, "FROZEN")
+	hub.Broadcast(evicted)
+	hub.Broadcast(newTestChange(appID, "ACTIVE"))
+	hub.Broadcast(newTestChange(appID, "CANCELLED"))
+
+	stream := hub.Subscribe([]uuid.UUID{appID}, evicted.ID)
+	defer stream.Close()
+
+	if stream.Resumed {
+		t.Error("expected evicted event not to resume")
+	}
+	if len(stream.Replay) != 0 {
+		t.Errorf("expected no replay, got %d", len(stream.Replay))
+	}
+}
+
+func TestSubscriptionChangeHub_DropsLaggingSubscriber(t *testing.T) {
+	hub := NewSubscriptionChangeHub(DefaultSubscriptionChangeBufferSize)
+	appID := uuid.New()
+
+	stream := hub.Subscribe([]uuid.UUID{appID}, "")
+	for i := 0; i <= subscriptionStreamChannelSize; i++ {
+		hub.Broadcast(newTestChange(appID, "FROZEN"))
+	}
+
+	if hub.SubscriberCount() != 0 {
+		t.Errorf("expected lagging subscriber to be dropped, got %d", hub.SubscriberCount())
+	}
+
+	received := 0
+	for range stream.C {
+		received++
+	}
+	if received != subscriptionStreamChannelSize {
+		t.Errorf("expected %d buffered changes before close, got %d", subscriptionStreamChannelSize, received)
+	}
+
+	// Closing an already dropped stream is a no-op
+	stream.Close()
+}
+
+func TestSubscriptionChangeHub_PublishesThroughTransport(t *testing.T) {
+	hub := NewSubscriptionChangeHub(10)
+	transport := &recordingTransport{hub: hub}
+	hub.WithTransport(transport)
+	appID := uuid.New()
+
+	stream := hub.Subscribe([]uuid.UUID{appID}, "")
+	defer stream.Close()
+
+	sub := &domainEntity.Subscription{
+		ID:              uuid.New(),
+		AppID:           appID,
+		ShopifyGID:      "gid://shopify/AppSubscription/9",
+		MyshopifyDomain: "store.myshopify.com",
+	}
+	event := domainEntity.NewSubscriptionEvent(sub.ID, "ACTIVE", "FROZEN",
+		valueobject.RiskStateSafe, valueobject.RiskStateOneCycleMissed, "billing_failure", "")
+
+	if err := hub.PublishSubscriptionEvent(context.Background(), sub, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(transport.published) != 1 {
+		t.Fatalf("expected 1 change through transport, got %d", len(transport.published))
+	}
+	got := <-stream.C
+	if got.SubscriptionID != sub.ShopifyGID || !got.StatusChanged() || !got.RiskStateChanged() {
+		t.Errorf("unexpected change: %+v", got)
+	}
+}
@@ -2,9 +2,15 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	domainEntity "github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
 	revrepo "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
@@ -13,8 +19,44 @@ import (
 var (
 	ErrSubscriptionNotFound = errors.New("subscription not found")
 	ErrAppAccessDenied      = errors.New("access denied to this app")
+	ErrInvalidCursor        = errors.New("invalid pagination cursor")
+	ErrInvalidPageSize      = errors.New("first must be between 1 and 250")
+	ErrInvalidSortField     = errors.New("invalid sort field")
 )
 
+const (
+	DefaultSubscriptionPageSize = 50
+	MaxSubscriptionPageSize     = 250
+)
+
+// SubscriptionListRequest is a forward-paginated subscription listing request
+type SubscriptionListRequest struct {
+	AppID      string // Optional: internal app UUID or Shopify app GID; empty = all of the user's apps
+	Filter     revrepo.SubscriptionStatusFilter
+	OrderBy    revrepo.SubscriptionStatusSortField // Defaults to MYSHOPIFY_DOMAIN
+	Descending bool
+	First      int    // 0 = DefaultSubscriptionPageSize
+	After      string // Opaque cursor from a previous page
+}
+
+// SubscriptionPage is one page of subscription statuses with a cursor per item
+type SubscriptionPage struct {
+	Statuses        []*entity.SubscriptionStatus
+	Cursors         []string
+	HasNextPage     bool
+	HasPreviousPage bool
+	TotalCount      int
+}
+
+// subscriptionCursor is the decoded form of an opaque page cursor. The sort field and
+// direction are embedded so a cursor can't be replayed against a different ordering.
+type subscriptionCursor struct {
+	OrderBy    revrepo.SubscriptionStatusSortField `json:"o"`
+	Descending bool                                `json:"d,omitempty"`
+	SortValue  string                              `json:"v"`
+	ID         uuid.UUID                           `json:"id"`
+}
+
 // SubscriptionStatusService handles subscription status queries
 type SubscriptionStatusService struct {
 	statusRepo  revrepo.SubscriptionStatusRepository
@@ -38,6 +80,10 @@ func NewSubscriptionStatusService(
 // GetByShopifyGID retrieves a subscription status by Shopify GID
 // Returns ErrAppAccessDenied if the user doesn't own the app
 func (s *SubscriptionStatusService) GetByShopifyGID(ctx context.Context, userID uuid.UUID, shopifyGID string) (*entity.SubscriptionStatus, error) {
+	if err := RequireScope(ctx, entity.ScopeSubscriptionsRead); err != nil {
+		return nil, err
+	}
+
 	// Get the subscription status
 	status, err := s.statusRepo.GetByShopifyGID(ctx, shopifyGID)
 	if err != nil {
@@ -54,8 +100,18 @@ func (s *SubscriptionStatusService) GetByShopifyGID(ctx context.Context, userID
 
 // GetByDomain retrieves a subscription status by myshopify domain
 func (s *SubscriptionStatusService) GetByDomain(ctx context.Context, userID uuid.UUID, domain string) (*entity.SubscriptionStatus, error) {
+	return s.GetByDomainInApp(ctx, userID, "", domain)
+}
+
+// GetByDomainInApp retrieves a subscription status by myshopify domain within one of
+// the user's apps (see ResolveAppIDs), or within all of them if appID is empty
+func (s *SubscriptionStatusService) GetByDomainInApp(ctx context.Context, userID uuid.UUID, appID string, domain string) (*entity.SubscriptionStatus, error) {
+	if err := RequireScope(ctx, entity.ScopeSubscriptionsRead); err != nil {
+		return nil, err
+	}
+
 	// First, we need to find which apps this user has access to
-	apps, err := s.getUserApps(ctx, userID)
+	apps, err := s.ResolveAppIDs(ctx, userID, appID)
 	if err != nil {
 		return nil, err
 	}
@@ -73,6 +129,10 @@ func (s *SubscriptionStatusService) GetByDomain(ctx context.Context, userID uuid
 
 // GetByShopifyGIDs retrieves multiple subscription statuses by Shopify GIDs
 func (s *SubscriptionStatusService) GetByShopifyGIDs(ctx context.Context, userID uuid.UUID, shopifyGIDs []string) (*entity.SubscriptionStatusBatchResponse, error) {
+	if err := RequireScope(ctx, entity.ScopeSubscriptionsRead); err != nil {
+		return nil, err
+	}
+
 	if len(shopifyGIDs) == 0 {
 		return &entity.SubscriptionStatusBatchResponse{
 			Results:  []entity.SubscriptionStatusResponse{},
@@ -122,16 +182,145 @@ func (s *SubscriptionStatusService) GetByShopifyGIDs(ctx context.Context, userID
 	}, nil
 }
 
-// getUserApps returns all app IDs the user has access to
-func (s *SubscriptionStatusService) getUserApps(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
-	// Get partner account for user (currently single partner account per user)
-	partnerAccount, err := s.partnerRepo.FindByUserID(ctx, userID)
+// List returns one page of the user's subscription statuses, optionally restricted to one app
+func (s *SubscriptionStatusService) List(ctx context.Context, userID uuid.UUID, req SubscriptionListRequest) (*SubscriptionPage, error) {
+	if err := RequireScope(ctx, entity.ScopeSubscriptionsRead); err != nil {
+		return nil, err
+	}
+
+	if req.First == 0 {
+		req.First = DefaultSubscriptionPageSize
+	}
+	if req.First < 1 || req.First > MaxSubscriptionPageSize {
+		return nil, ErrInvalidPageSize
+	}
+	if req.OrderBy == "" {
+		req.OrderBy = revrepo.SortByMyshopifyDomain
+	}
+	if !req.OrderBy.IsValid() {
+		return nil, ErrInvalidSortField
+	}
+
+	appIDs, err := s.ResolveAppIDs(ctx, userID, req.AppID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := revrepo.SubscriptionStatusPageQuery{
+		AppIDs:     appIDs,
+		Filter:     req.Filter,
+		OrderBy:    req.OrderBy,
+		Descending: req.Descending,
+		Limit:      req.First + 1, // One extra row tells us whether there is a next page
+	}
+	if req.After != "" {
+		cursor, err := decodeSubscriptionCursor(req.After)
+		if err != nil || cursor.OrderBy != req.OrderBy || cursor.Descending != req.Descending {
+			return nil, ErrInvalidCursor
+		}
+		query.After = &revrepo.SubscriptionStatusCursor{SortValue: cursor.SortValue, ID: cursor.ID}
+	}
+
+	statuses, err := s.statusRepo.FindPage(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.statusRepo.Count(ctx, appIDs, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &SubscriptionPage{
+		HasPreviousPage: req.After != "",
+		TotalCount:      total,
+	}
+	if len(statuses) > req.First {
+		statuses = statuses[:req.First]
+		page.HasNextPage = true
+	}
+
+	page.Statuses = statuses
+	page.Cursors = make([]string, len(statuses))
+	for i, status := range statuses {
+		page.Cursors[i] = encodeSubscriptionCursor(subscriptionCursor{
+			OrderBy:    req.OrderBy,
+			Descending: req.Descending,
+			SortValue:  subscriptionSortValue(req.OrderBy, status),
+			ID:         status.ID,
+		})
+	}
+
+	return page, nil
+}
+
+// ResolveAppIDs returns the app IDs a listing or stream covers: all of the user's apps,
+// or the given app (internal ID, Shopify app GID or its numeric ID) if it belongs to the user.
+func (s *SubscriptionStatusService) ResolveAppIDs(ctx context.Context, userID uuid.UUID, appID string) ([]uuid.UUID, error) {
+	if appID == "" {
+		return s.getUserApps(ctx, userID)
+	}
+
+	apps, err := findUserApps(ctx, s.partnerRepo, s.appRepo, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get all apps for the partner account
-	apps, err := s.appRepo.FindByPartnerAccountID(ctx, partnerAccount.ID)
+	for _, app := range apps {
+		if app.ID.String() == appID || app.PartnerAppID == appID ||
+			strings.TrimPrefix(app.PartnerAppID, "gid://partners/App/") == appID {
+			if !APIKeyAccessFromContext(ctx).AllowsApp(app.ID) {
+				return nil, ErrAppAccessDenied
+			}
+			return []uuid.UUID{app.ID}, nil
+		}
+	}
+
+	return nil, ErrAppAccessDenied
+}
+
+// subscriptionSortValue renders a status's sort column the way the repository compares it
+func subscriptionSortValue(field revrepo.SubscriptionStatusSortField, status *entity.SubscriptionStatus) string {
+	var t *time.Time
+	switch field {
+	case revrepo.SortByMonthsOverdue:
+		return strconv.Itoa(status.MonthsOverdue)
+	case revrepo.SortByExpectedNextChargeDate:
+		t = status.ExpectedNextChargeDate
+	case revrepo.SortByLastSuccessfulChargeDate:
+		t = status.LastSuccessfulChargeDate
+	default:
+		return status.MyshopifyDomain
+	}
+
+	if t == nil {
+		return "infinity"
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func encodeSubscriptionCursor(c subscriptionCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSubscriptionCursor(s string) (subscriptionCursor, error) {
+	var c subscriptionCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, err
+	}
+	if c.ID == uuid.Nil {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// getUserApps returns all app IDs the user (and the calling API key) has access to
+func (s *SubscriptionStatusService) getUserApps(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	apps, err := findUserApps(ctx, s.partnerRepo, s.appRepo, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -141,27 +330,58 @@ func (s *SubscriptionStatusService) getUserApps(ctx context.Context, userID uuid
 		appIDs[i] = app.ID
 	}
 
-	return appIDs, nil
+	return filterAllowedApps(ctx, appIDs), nil
 }
 
-// verifyAppAccess checks if a user has access to an app
+// verifyAppAccess checks if a user, and the calling API key, has access to an app
 func (s *SubscriptionStatusService) verifyAppAccess(ctx context.Context, userID uuid.UUID, appID uuid.UUID) error {
-	// Get the app
-	app, err := s.appRepo.FindByID(ctx, appID)
-	if err != nil {
+	if !APIKeyAccessFromContext(ctx).AllowsApp(appID) {
 		return ErrAppAccessDenied
 	}
 
-	// Get the partner account for user
-	partnerAccount, err := s.partnerRepo.FindByUserID(ctx, userID)
+	// Get the app
+	app, err := s.appRepo.FindByID(ctx, appID)
 	if err != nil {
 		return ErrAppAccessDenied
 	}
 
-	// Check if the app belongs to the user's partner account
-	if app.PartnerAccountID != partnerAccount.ID {
+	// Check if the app belongs to a partner account the user can read
+	if !userCanAccessPartnerAccount(ctx, s.partnerRepo, userID, app.PartnerAccountID) {
 		return ErrAppAccessDenied
 	}
 
 	return nil
 }
+
+// findUserApps returns the apps of every partner account the user can read:
+// the one they connected and those of the workspaces they are a member of
+func findUserApps(ctx context.Context, partnerRepo repository.PartnerAccountRepository, appRepo repository.AppRepository, userID uuid.UUID) ([]*domainEntity.App, error) {
+	accounts, err := partnerRepo.FindAccessibleByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var apps []*domainEntity.App
+	for _, account := range accounts {
+		accountApps, err := appRepo.FindByPartnerAccountID(ctx, account.ID)
+		if err != nil {
+			return nil, err
+		}
+		apps = append(apps, accountApps...)
+	}
+	return apps, nil
+}
+
+// userCanAccessPartnerAccount reports whether the user can read the partner account's apps
+func userCanAccessPartnerAccount(ctx context.Context, partnerRepo repository.PartnerAccountRepository, userID, partnerAccountID uuid.UUID) bool {
+	accounts, err := partnerRepo.FindAccessibleByUserID(ctx, userID)
+	if err != nil {
+		return false
+	}
+	for _, account := range accounts {
+		if account.ID == partnerAccountID {
+			return true
+		}
+	}
+	return false
+}
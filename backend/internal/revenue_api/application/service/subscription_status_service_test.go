@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	domainEntity "github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	revrepo "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
+)
+
+func TestSubscriptionStatusService_List(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	newService := func(statuses ...*entity.SubscriptionStatus) (*SubscriptionStatusService, *memSubscriptionStatusRepo) {
+		account := &domainEntity.PartnerAccount{ID: uuid.New(), UserID: userID}
+		app := &domainEntity.App{ID: uuid.New(), PartnerAccountID: account.ID, PartnerAppID: "gid://partners/App/42"}
+		for _, s := range statuses {
+			s.AppID = app.ID
+		}
+		repo := &memSubscriptionStatusRepo{statuses: statuses}
+		return NewSubscriptionStatusService(repo, &stubAppRepo{app: app}, &stubPartnerRepo{account: account}), repo
+	}
+
+	newStatuses := func(n int) []*entity.SubscriptionStatus {
+		statuses := make([]*entity.SubscriptionStatus, n)
+		for i := range statuses {
+			statuses[i] = newTestStatus(fmt.Sprintf("shop-%02d.myshopify.com", i), valueobject.RiskStateSafe, nil)
+		}
+		return statuses
+	}
+
+	// listAll pages through every result, returning the domains in order and each page's flags
+	listAll := func(t *testing.T, svc *SubscriptionStatusService, req SubscriptionListRequest) ([]*entity.SubscriptionStatus, []*SubscriptionPage) {
+		t.Helper()
+		var all []*entity.SubscriptionStatus
+		var pages []*SubscriptionPage
+		for i := 0; i < 20; i++ {
+			page, err := svc.List(ctx, userID, req)
+			if err != nil {
+				t.Fatalf("page %d: unexpected error: %v", i+1, err)
+			}
+			all = append(all, page.Statuses...)
+			pages = append(pages, page)
+			if !page.HasNextPage {
+				return all, pages
+			}
+			req.After = page.Cursors[len(page.Cursors)-1]
+		}
+		t.Fatal("pagination did not terminate")
+		return nil, nil
+	}
+
+	t.Run("cursor round-trips across pages", func(t *testing.T) {
+		svc, _ := newService(newStatuses(5)...)
+
+		all, pages := listAll(t, svc, SubscriptionListRequest{First: 2})
+
+		if len(pages) != 3 {
+			t.Fatalf("pages = %d, want 3", len(pages))
+		}
+		for i, status := range all {
+			if want := fmt.Sprintf("shop-%02d.myshopify.com", i); status.MyshopifyDomain != want {
+				t.Errorf("result %d = %s, want %s", i, status.MyshopifyDomain, want)
+			}
+		}
+		if pages[0].HasPreviousPage || !pages[1].HasPreviousPage {
+			t.Error("only pages after the first should have a previous page")
+		}
+		for _, page := range pages {
+			if page.TotalCount != 5 {
+				t.Errorf("TotalCount = %d, want 5", page.TotalCount)
+			}
+			if len(page.Cursors) != len(page.Statuses) {
+				t.Errorf("cursors = %d, statuses = %d", len(page.Cursors), len(page.Statuses))
+			}
+		}
+	})
+
+	t.Run("descending order round-trips", func(t *testing.T) {
+		svc, _ := newService(newStatuses(5)...)
+
+		all, _ := listAll(t, svc, SubscriptionListRequest{First: 2, Descending: true})
+
+		if len(all) != 5 || all[0].MyshopifyDomain != "shop-04.myshopify.com" || all[4].MyshopifyDomain != "shop-00.myshopify.com" {
+			t.Errorf("unexpected descending order: %v", domainsOf(all))
+		}
+	})
+
+	t.Run("cursor for another ordering is rejected", func(t *testing.T) {
+		svc, _ := newService(newStatuses(3)...)
+
+		page, err := svc.List(ctx, userID, SubscriptionListRequest{First: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cursor := page.Cursors[0]
+
+		for name, req := range map[string]SubscriptionListRequest{
+			"other field":     {First: 1, After: cursor, OrderBy: revrepo.SortByMonthsOverdue},
+			"other direction": {First: 1, After: cursor, Descending: true},
+			"not a cursor":    {First: 1, After: "not-a-cursor"},
+		} {
+			if _, err := svc.List(ctx, userID, req); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("%s: err = %v, want ErrInvalidCursor", name, err)
+			}
+		}
+
+		// The same ordering, spelled out, still accepts it
+		if _, err := svc.List(ctx, userID, SubscriptionListRequest{First: 1, After: cursor, OrderBy: revrepo.SortByMyshopifyDomain}); err != nil {
+			t.Errorf("unexpected error for matching ordering: %v", err)
+		}
+	})
+
+	t.Run("first bounds", func(t *testing.T) {
+		svc, repo := newService(newStatuses(1)...)
+
+		tests := []struct {
+			first     int
+			wantErr   error
+			wantLimit int
+		}{
+			{first: 0, wantLimit: DefaultSubscriptionPageSize + 1},
+			{first: 1, wantLimit: 2},
+			{first: MaxSubscriptionPageSize, wantLimit: MaxSubscriptionPageSize + 1},
+			{first: MaxSubscriptionPageSize + 1, wantErr: ErrInvalidPageSize},
+			{first: -1, wantErr: ErrInvalidPageSize},
+		}
+		for _, tt := range tests {
+			repo.lastPageQuery = revrepo.SubscriptionStatusPageQuery{}
+			_, err := svc.List(ctx, userID, SubscriptionListRequest{First: tt.first})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("first=%d: err = %v, want %v", tt.first, err, tt.wantErr)
+				continue
+			}
+			if tt.wantErr == nil && repo.lastPageQuery.Limit != tt.wantLimit {
+				t.Errorf("first=%d: repository limit = %d, want %d", tt.first, repo.lastPageQuery.Limit, tt.wantLimit)
+			}
+		}
+	})
+
+	t.Run("hasNextPage at an exact page boundary", func(t *testing.T) {
+		svc, _ := newService(newStatuses(4)...)
+
+		_, pages := listAll(t, svc, SubscriptionListRequest{First: 2})
+		if len(pages) != 2 {
+			t.Fatalf("pages = %d, want 2 (no empty trailing page)", len(pages))
+		}
+		if !pages[0].HasNextPage || pages[1].HasNextPage {
+			t.Errorf("HasNextPage = %v, %v; want true, false", pages[0].HasNextPage, pages[1].HasNextPage)
+		}
+		if len(pages[1].Statuses) != 2 {
+			t.Errorf("last page size = %d, want 2", len(pages[1].Statuses))
+		}
+
+		page, err := svc.List(ctx, userID, SubscriptionListRequest{First: 4})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if page.HasNextPage {
+			t.Error("a page holding exactly every result should not have a next page")
+		}
+	})
+
+	t.Run("ties on the sort column are broken by id", func(t *testing.T) {
+		statuses := newStatuses(5)
+		for _, s := range statuses {
+			s.MonthsOverdue = 2
+		}
+		svc, _ := newService(statuses...)
+
+		all, _ := listAll(t, svc, SubscriptionListRequest{First: 2, OrderBy: revrepo.SortByMonthsOverdue})
+
+		if len(all) != 5 {
+			t.Fatalf("results = %d, want 5", len(all))
+		}
+		seen := make(map[uuid.UUID]bool)
+		for i, status := range all {
+			if seen[status.ID] {
+				t.Errorf("status %s returned twice", status.MyshopifyDomain)
+			}
+			seen[status.ID] = true
+			if i > 0 && all[i-1].ID.String() >= status.ID.String() {
+				t.Errorf("tied rows not ordered by id at %d", i)
+			}
+		}
+	})
+}
+
+func TestSubscriptionStatusService_WorkspaceMemberAccess(t *testing.T) {
+	ctx := context.Background()
+	ownerID := uuid.New()
+	memberID := uuid.New()
+	workspaceID := uuid.New()
+
+	// The workspace's account was connected by the owner; the member reaches it through the workspace
+	account := &domainEntity.PartnerAccount{ID: uuid.New(), UserID: ownerID, WorkspaceID: &workspaceID}
+	app := &domainEntity.App{ID: uuid.New(), PartnerAccountID: account.ID, PartnerAppID: "gid://partners/App/42"}
+	status := newTestStatus("shop.myshopify.com", valueobject.RiskStateSafe, nil)
+	status.AppID = app.ID
+
+	partnerRepo := &workspacePartnerRepo{accessible: map[uuid.UUID][]*domainEntity.PartnerAccount{
+		ownerID:  {account},
+		memberID: {account},
+	}}
+	svc := NewSubscriptionStatusService(&memSubscriptionStatusRepo{statuses: []*entity.SubscriptionStatus{status}}, &stubAppRepo{app: app}, partnerRepo)
+
+	t.Run("member reads the workspace's subscriptions", func(t *testing.T) {
+		got, err := svc.GetByShopifyGID(ctx, memberID, status.ShopifyGID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.ID != status.ID {
+			t.Errorf("got status %s, want %s", got.ID, status.ID)
+		}
+
+		page, err := svc.List(ctx, memberID, SubscriptionListRequest{AppID: "42"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(page.Statuses) != 1 {
+			t.Errorf("statuses = %d, want 1", len(page.Statuses))
+		}
+	})
+
+	t.Run("non-member is denied", func(t *testing.T) {
+		outsider := uuid.New()
+
+		if _, err := svc.GetByShopifyGID(ctx, outsider, status.ShopifyGID); !errors.Is(err, ErrAppAccessDenied) {
+			t.Errorf("GetByShopifyGID err = %v, want ErrAppAccessDenied", err)
+		}
+		if _, err := svc.ResolveAppIDs(ctx, outsider, "42"); !errors.Is(err, ErrAppAccessDenied) {
+			t.Errorf("ResolveAppIDs err = %v, want ErrAppAccessDenied", err)
+		}
+	})
+}
+
+// workspacePartnerRepo returns the partner accounts each user can read
+type workspacePartnerRepo struct {
+	stubPartnerRepo
+	accessible map[uuid.UUID][]*domainEntity.PartnerAccount
+}
+
+func (m *workspacePartnerRepo) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]*domainEntity.PartnerAccount, error) {
+	return m.accessible[userID], nil
+}
+
+func domainsOf(statuses []*entity.SubscriptionStatus) []string {
+	domains := make([]string, len(statuses))
+	for i, s := range statuses {
+		domains[i] = s.MyshopifyDomain
+	}
+	return domains
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	revrepo "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
+)
+
+var (
+	ErrInvalidUsageRecordGID      = errors.New("usage_id must be a gid://shopify/AppUsageRecord/ GID")
+	ErrInvalidUsageAmount         = errors.New("amount_cents must be positive")
+	ErrInvalidUsageCreatedAt      = errors.New("created_at is required and cannot be in the future")
+	ErrUsageBatchTooLarge         = errors.New("at most 100 usage records per request")
+	ErrInvalidReconciliationRange = errors.New("from must be before to and the range at most 92 days")
+	ErrInvalidReconciliationState = errors.New("state must be PENDING, BILLED, OVERDUE or AMOUNT_MISMATCH")
+)
+
+const (
+	// DefaultUsageOverdueAfter is how long a reported record may stay unbilled before it's flagged
+	DefaultUsageOverdueAfter = 72 * time.Hour
+	// MaxUsageReportBatch is the most records a single report accepts
+	MaxUsageReportBatch = 100
+	// DefaultReconciliationRange is reconciled when no range is given
+	DefaultReconciliationRange = 30 * 24 * time.Hour
+	// MaxReconciliationRange is the longest range a reconciliation report covers
+	MaxReconciliationRange = 92 * 24 * time.Hour
+
+	usageRecordGIDPrefix = "gid://shopify/AppUsageRecord/"
+	// usageClockSkew tolerates created_at slightly ahead of our clock
+	usageClockSkew = 5 * time.Minute
+)
+
+// UsageReconciliationService ingests the usage records apps create in Shopify and
+// reconciles them against the usage transactions the Partner API reports as billed.
+// The ReadModelBuilder marks reported records billed when their transaction syncs.
+type UsageReconciliationService struct {
+	usageRepo        revrepo.UsageStatusRepository
+	subscriptionRepo repository.SubscriptionRepository
+	statusService    *SubscriptionStatusService
+	overdueAfter     time.Duration
+	now              func() time.Time
+}
+
+// NewUsageReconciliationService creates a new UsageReconciliationService
+func NewUsageReconciliationService(
+	usageRepo revrepo.UsageStatusRepository,
+	subscriptionRepo repository.SubscriptionRepository,
+	statusService *SubscriptionStatusService,
+) *UsageReconciliationService {
+	return &UsageReconciliationService{
+		usageRepo:        usageRepo,
+		subscriptionRepo: subscriptionRepo,
+		statusService:    statusService,
+		overdueAfter:     DefaultUsageOverdueAfter,
+		now:              func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// WithOverdueAfter sets how long a reported record may stay unbilled before it's flagged overdue
+func (s *UsageReconciliationService) WithOverdueAfter(d time.Duration) *UsageReconciliationService {
+	s.overdueAfter = d
+	return s
+}
+
+// ReportedUsage is a usage record the app created in Shopify
+type ReportedUsage struct {
+	ShopifyGID      string // gid://shopify/AppUsageRecord/...
+	SubscriptionGID string // Parent gid://shopify/AppSubscription/...
+	AmountCents     int    // Price of the record
+	Description     string
+	CreatedAt       time.Time // When the record was created in Shopify
+}
+
+// Report records usage records as pending until their usage transaction is synced.
+// Invalid records are rejected individually; re-reporting a record updates it
+// without losing its billing.
+func (s *UsageReconciliationService) Report(ctx context.Context, userID uuid.UUID, records []ReportedUsage) (*entity.UsageReportResponse, error) {
+	if err := RequireScope(ctx, entity.ScopeUsageWrite); err != nil {
+		return nil, err
+	}
+	if len(records) > MaxUsageReportBatch {
+		return nil, ErrUsageBatchTooLarge
+	}
+
+	resp := &entity.UsageReportResponse{
+		Accepted: []entity.UsageStatusResponse{},
+		Rejected: []entity.UsageReportRejection{},
+	}
+	if len(records) == 0 {
+		return resp, nil
+	}
+
+	gids := make([]string, len(records))
+	for i, r := range records {
+		gids[i] = r.ShopifyGID
+	}
+	existing, err := s.usageRepo.GetByShopifyGIDs(ctx, gids)
+	if err != nil {
+		return nil, err
+	}
+	previous := make(map[string]*entity.UsageStatus, len(existing))
+	for _, status := range existing {
+		previous[status.ShopifyGID] = status
+	}
+
+	now := s.now()
+	statuses := make([]*entity.UsageStatus, 0, len(records))
+	for _, r := range records {
+		status, err := s.toStatus(ctx, userID, r, previous[r.ShopifyGID], now)
+		if err != nil {
+			resp.Rejected = append(resp.Rejected, entity.UsageReportRejection{UsageID: r.ShopifyGID, Error: err.Error()})
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+
+	if err := s.usageRepo.UpsertBatch(ctx, statuses); err != nil {
+		return nil, err
+	}
+
+	for _, status := range statuses {
+		resp.Accepted = append(resp.Accepted, status.ToReconciledResponse(now, s.overdueAfter))
+	}
+	return resp, nil
+}
+
+// toStatus validates a reported record and merges it with what's already known of it
+func (s *UsageReconciliationService) toStatus(ctx context.Context, userID uuid.UUID, r ReportedUsage, previous *entity.UsageStatus, now time.Time) (*entity.UsageStatus, error) {
+	if !strings.HasPrefix(r.ShopifyGID, usageRecordGIDPrefix) || len(r.ShopifyGID) == len(usageRecordGIDPrefix) {
+		return nil, ErrInvalidUsageRecordGID
+	}
+	if r.AmountCents <= 0 {
+		return nil, ErrInvalidUsageAmount
+	}
+	if r.CreatedAt.IsZero() || r.CreatedAt.After(now.Add(usageClockSkew)) {
+		return nil, ErrInvalidUsageCreatedAt
+	}
+
+	sub, err := s.subscriptionRepo.FindByShopifyGID(ctx, r.SubscriptionGID)
+	if err != nil || sub == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	if err := s.statusService.verifyAppAccess(ctx, userID, sub.AppID); err != nil {
+		return nil, err
+	}
+	if previous != nil && previous.AppID != uuid.Nil && previous.AppID != sub.AppID {
+		return nil, ErrAppAccessDenied
+	}
+
+	status := entity.NewReportedUsageStatus(r.ShopifyGID, sub.ShopifyGID, sub.ID, sub.AppID, r.AmountCents, r.Description, r.CreatedAt)
+	if previous != nil {
+		status.ID = previous.ID
+		if previous.Billed {
+			status.Billed = true
+			status.BillingDate = previous.BillingDate
+			status.BilledTransactionGID = previous.BilledTransactionGID
+			status.BilledAmountCents = previous.BilledAmountCents
+		}
+	}
+	return status, nil
+}
+
+// ReconciliationQuery selects the records of a reconciliation report
+type ReconciliationQuery struct {
+	From   *time.Time // Default: To - DefaultReconciliationRange
+	To     *time.Time // Default: now
+	States []string   // Listed states; empty lists all
+}
+
+// GetReconciliation reports how an app's usage records created in the range
+// compare with what Shopify billed
+func (s *UsageReconciliationService) GetReconciliation(ctx context.Context, userID uuid.UUID, appID string, q ReconciliationQuery) (*entity.UsageReconciliationReport, error) {
+	if err := RequireScope(ctx, entity.ScopeUsageRead); err != nil {
+		return nil, err
+	}
+
+	states := make([]entity.UsageReconciliationState, 0, len(q.States))
+	for _, state := range q.States {
+		if !entity.IsValidUsageReconciliationState(state) {
+			return nil, ErrInvalidReconciliationState
+		}
+		states = append(states, entity.UsageReconciliationState(state))
+	}
+
+	now := s.now()
+	to := now
+	if q.To != nil {
+		to = q.To.UTC()
+	}
+	from := to.Add(-DefaultReconciliationRange)
+	if q.From != nil {
+		from = q.From.UTC()
+	}
+	if !from.Before(to) || to.Sub(from) > MaxReconciliationRange {
+		return nil, ErrInvalidReconciliationRange
+	}
+
+	appIDs, err := s.statusService.ResolveAppIDs(ctx, userID, appID)
+	if err != nil {
+		return nil, err
+	}
+	if len(appIDs) != 1 {
+		return nil, ErrAppAccessDenied
+	}
+
+	records, err := s.usageRepo.GetReportedByAppID(ctx, appIDs[0], from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewUsageReconciliationReport(appIDs[0], from, to, records, now, s.overdueAfter, states), nil
+}
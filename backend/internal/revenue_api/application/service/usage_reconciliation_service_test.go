@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	domainEntity "github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+type memUsageStatusRepo struct {
+	statuses map[string]*entity.UsageStatus
+}
+
+func newMemUsageStatusRepo() *memUsageStatusRepo {
+	return &memUsageStatusRepo{statuses: make(map[string]*entity.UsageStatus)}
+}
+
+func (m *memUsageStatusRepo) Upsert(ctx context.Context, status *entity.UsageStatus) error {
+	copied := *status
+	m.statuses[status.ShopifyGID] = &copied
+	return nil
+}
+
+func (m *memUsageStatusRepo) UpsertBatch(ctx context.Context, statuses []*entity.UsageStatus) error {
+	for _, status := range statuses {
+		m.Upsert(ctx, status)
+	}
+	return nil
+}
+
+func (m *memUsageStatusRepo) GetByShopifyGID(ctx context.Context, shopifyGID string) (*entity.UsageStatus, error) {
+	if status, ok := m.statuses[shopifyGID]; ok {
+		copied := *status
+		return &copied, nil
+	}
+	return nil, errTestNotFound
+}
+
+func (m *memUsageStatusRepo) GetByShopifyGIDs(ctx context.Context, shopifyGIDs []string) ([]*entity.UsageStatus, error) {
+	var result []*entity.UsageStatus
+	for _, gid := range shopifyGIDs {
+		if status, err := m.GetByShopifyGID(ctx, gid); err == nil {
+			result = append(result, status)
+		}
+	}
+	return result, nil
+}
+
+func (m *memUsageStatusRepo) filter(keep func(*entity.UsageStatus) bool) []*entity.UsageStatus {
+	var result []*entity.UsageStatus
+	for _, status := range m.statuses {
+		if keep(status) {
+			copied := *status
+			result = append(result, &copied)
+		}
+	}
+	return result
+}
+
+func (m *memUsageStatusRepo) GetBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*entity.UsageStatus, error) {
+	return m.filter(func(u *entity.UsageStatus) bool { return u.SubscriptionID == subscriptionID }), nil
+}
+
+func (m *memUsageStatusRepo) GetBySubscriptionShopifyGID(ctx context.Context, subscriptionShopifyGID string) ([]*entity.UsageStatus, error) {
+	return m.filter(func(u *entity.UsageStatus) bool { return u.SubscriptionShopifyGID == subscriptionShopifyGID }), nil
+}
+
+func (m *memUsageStatusRepo) GetUnbilledBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*entity.UsageStatus, error) {
+	return m.filter(func(u *entity.UsageStatus) bool { return u.SubscriptionID == subscriptionID && !u.Billed }), nil
+}
+
+func (m *memUsageStatusRepo) GetReportedByAppID(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.UsageStatus, error) {
+	result := m.filter(func(u *entity.UsageStatus) bool {
+		return u.AppID == appID && u.IsReported() && !u.ReportedAt.Before(from) && u.ReportedAt.Before(to)
+	})
+	sort.Slice(result, func(i, j int) bool { return result[i].ReportedAt.Before(*result[j].ReportedAt) })
+	return result, nil
+}
+
+func (m *memUsageStatusRepo) DeleteBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) error {
+	for gid, status := range m.statuses {
+		if status.SubscriptionID == subscriptionID {
+			delete(m.statuses, gid)
+		}
+	}
+	return nil
+}
+
+func (m *memUsageStatusRepo) DeleteByShopifyGIDs(ctx context.Context, shopifyGIDs []string) error {
+	for _, gid := range shopifyGIDs {
+		delete(m.statuses, gid)
+	}
+	return nil
+}
+
+// stubLedgerSubscriptionRepo serves the ledger subscriptions; other methods are unused
+type stubLedgerSubscriptionRepo struct {
+	repository.SubscriptionRepository
+	subscriptions []*domainEntity.Subscription
+}
+
+func (m *stubLedgerSubscriptionRepo) FindByAppID(ctx context.Context, appID uuid.UUID) ([]*domainEntity.Subscription, error) {
+	var result []*domainEntity.Subscription
+	for _, sub := range m.subscriptions {
+		if sub.AppID == appID && !sub.IsDeleted() {
+			result = append(result, sub)
+		}
+	}
+	return result, nil
+}
+
+func (m *stubLedgerSubscriptionRepo) FindUpdatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*domainEntity.Subscription, error) {
+	var result []*domainEntity.Subscription
+	for _, sub := range m.subscriptions {
+		if sub.AppID == appID && sub.UpdatedAt.After(since) {
+			result = append(result, sub)
+		}
+	}
+	return result, nil
+}
+
+func (m *stubLedgerSubscriptionRepo) FindByShopifyGID(ctx context.Context, shopifyGID string) (*domainEntity.Subscription, error) {
+	for _, sub := range m.subscriptions {
+		if sub.ShopifyGID == shopifyGID {
+			return sub, nil
+		}
+	}
+	return nil, errTestNotFound
+}
+
+// stubTransactionRepo serves the ledger transactions; other methods are unused
+type stubTransactionRepo struct {
+	repository.TransactionRepository
+	transactions []*domainEntity.Transaction
+}
+
+func (m *stubTransactionRepo) FindByAppID(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*domainEntity.Transaction, error) {
+	return m.transactions, nil
+}
+
+func (m *stubTransactionRepo) FindCreatedSince(ctx context.Context, appID uuid.UUID, since time.Time) ([]*domainEntity.Transaction, error) {
+	var result []*domainEntity.Transaction
+	for _, txn := range m.transactions {
+		if txn.CreatedAt.After(since) {
+			result = append(result, txn)
+		}
+	}
+	return result, nil
+}
+
+// newTestUsageSubscription returns the ledger subscription usage is reported against
+func newTestUsageSubscription(appID uuid.UUID) *domainEntity.Subscription {
+	return &domainEntity.Subscription{
+		ID:              uuid.New(),
+		AppID:           appID,
+		ShopifyGID:      "gid://shopify/AppSubscription/1",
+		MyshopifyDomain: "store.myshopify.com",
+	}
+}
+
+func newTestReportedUsage(sub *domainEntity.Subscription, id string, amountCents int, createdAt time.Time) ReportedUsage {
+	return ReportedUsage{
+		ShopifyGID:      "gid://shopify/AppUsageRecord/" + id,
+		SubscriptionGID: sub.ShopifyGID,
+		AmountCents:     amountCents,
+		Description:     "usage " + id,
+		CreatedAt:       createdAt,
+	}
+}
+
+func newTestUsageSale(sub *domainEntity.Subscription, id, chargeGID string, grossCents int64, createdAt time.Time) *domainEntity.Transaction {
+	txn := domainEntity.NewTransaction(sub.AppID, "gid://partners/AppUsageSale/"+id, sub.MyshopifyDomain, "Store",
+		valueobject.ChargeTypeUsage, grossCents, grossCents*80/100, "USD", createdAt)
+	txn.ChargeGID = chargeGID
+	return txn
+}
+
+func TestUsageReconciliationService_Report(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("accepts valid records and rejects invalid ones", func(t *testing.T) {
+		statusSvc, app := newTestStatusServiceForApp()
+		sub := newTestUsageSubscription(app.ID)
+		usageRepo := newMemUsageStatusRepo()
+		svc := NewUsageReconciliationService(usageRepo, &stubLedgerSubscriptionRepo{subscriptions: []*domainEntity.Subscription{sub}}, statusSvc)
+		svc.now = func() time.Time { return now }
+
+		future := newTestReportedUsage(sub, "4", 100, now.Add(time.Hour))
+		unknownSub := newTestReportedUsage(sub, "5", 100, now)
+		unknownSub.SubscriptionGID = "gid://shopify/AppSubscription/999"
+		badGID := newTestReportedUsage(sub, "6", 100, now)
+		badGID.ShopifyGID = "gid://shopify/AppSubscription/6"
+
+		resp, err := svc.Report(ctx, uuid.New(), []ReportedUsage{
+			newTestReportedUsage(sub, "1", 500, now.Add(-time.Hour)),
+			newTestReportedUsage(sub, "2", 0, now.Add(-time.Hour)),
+			future,
+			unknownSub,
+			badGID,
+		})
+		if err != nil {
+			t.Fatalf("Report: %v", err)
+		}
+		if len(resp.Accepted) != 1 || resp.Accepted[0].UsageID != "gid://shopify/AppUsageRecord/1" {
+			t.Fatalf("expected only record 1 accepted, got %+v", resp.Accepted)
+		}
+		if resp.Accepted[0].Billed || resp.Accepted[0].ReconciliationState != entity.UsageStatePending {
+			t.Errorf("expected a pending unbilled record, got %+v", resp.Accepted[0])
+		}
+		wantErrors := map[string]error{
+			"gid://shopify/AppUsageRecord/2":  ErrInvalidUsageAmount,
+			"gid://shopify/AppUsageRecord/4":  ErrInvalidUsageCreatedAt,
+			"gid://shopify/AppUsageRecord/5":  ErrSubscriptionNotFound,
+			"gid://shopify/AppSubscription/6": ErrInvalidUsageRecordGID,
+		}
+		if len(resp.Rejected) != len(wantErrors) {
+			t.Fatalf("expected %d rejections, got %+v", len(wantErrors), resp.Rejected)
+		}
+		for _, rejection := range resp.Rejected {
+			if want := wantErrors[rejection.UsageID]; want == nil || rejection.Error != want.Error() {
+				t.Errorf("%s: got %q, want %v", rejection.UsageID, rejection.Error, want)
+			}
+		}
+
+		stored, err := usageRepo.GetByShopifyGID(ctx, "gid://shopify/AppUsageRecord/1")
+		if err != nil || stored.AppID != app.ID || stored.SubscriptionID != sub.ID || !stored.IsReported() {
+			t.Errorf("expected the record stored against the ledger subscription, got %+v (%v)", stored, err)
+		}
+	})
+
+	t.Run("requires the usage:write scope", func(t *testing.T) {
+		statusSvc, app := newTestStatusServiceForApp()
+		sub := newTestUsageSubscription(app.ID)
+		svc := NewUsageReconciliationService(newMemUsageStatusRepo(), &stubLedgerSubscriptionRepo{subscriptions: []*domainEntity.Subscription{sub}}, statusSvc)
+		svc.now = func() time.Time { return now }
+
+		readOnly := WithAPIKeyAccess(ctx, &APIKeyAccess{Scopes: entity.DefaultAPIKeyScopes})
+		if _, err := svc.Report(readOnly, uuid.New(), []ReportedUsage{newTestReportedUsage(sub, "7", 100, now)}); err != ErrInsufficientScope {
+			t.Errorf("expected ErrInsufficientScope without usage:write, got %v", err)
+		}
+	})
+
+	t.Run("re-reporting a billed record keeps its billing", func(t *testing.T) {
+		statusSvc, app := newTestStatusServiceForApp()
+		sub := newTestUsageSubscription(app.ID)
+		subRepo := &stubLedgerSubscriptionRepo{subscriptions: []*domainEntity.Subscription{sub}}
+		usageRepo := newMemUsageStatusRepo()
+		txRepo := &stubTransactionRepo{}
+		svc := NewUsageReconciliationService(usageRepo, subRepo, statusSvc)
+		svc.now = func() time.Time { return now }
+		builder := NewReadModelBuilder(subRepo, txRepo, &memSubscriptionStatusRepo{}, usageRepo)
+
+		if _, err := svc.Report(ctx, uuid.New(), []ReportedUsage{newTestReportedUsage(sub, "1", 500, now.Add(-2*time.Hour))}); err != nil {
+			t.Fatalf("Report: %v", err)
+		}
+		txRepo.transactions = []*domainEntity.Transaction{
+			newTestUsageSale(sub, "10", "gid://shopify/AppUsageRecord/1", 500, now.Add(-time.Hour)),
+		}
+		if err := builder.rebuildUsageStatuses(ctx, app.ID); err != nil {
+			t.Fatalf("rebuildUsageStatuses: %v", err)
+		}
+
+		resp, err := svc.Report(ctx, uuid.New(), []ReportedUsage{newTestReportedUsage(sub, "1", 600, now.Add(-2*time.Hour))})
+		if err != nil || len(resp.Accepted) != 1 {
+			t.Fatalf("Report: %+v (%v)", resp, err)
+		}
+		got := resp.Accepted[0]
+		if !got.Billed || got.BilledTransactionID != "gid://partners/AppUsageSale/10" || got.ReconciliationState != entity.UsageStateAmountMismatch {
+			t.Errorf("expected re-reported record to stay billed and flag the new amount, got %+v", got)
+		}
+	})
+}
+
+func TestUsageReconciliationService_GetReconciliation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("reconciles reported usage against billed transactions", func(t *testing.T) {
+		statusSvc, app := newTestStatusServiceForApp()
+		sub := newTestUsageSubscription(app.ID)
+		subRepo := &stubLedgerSubscriptionRepo{subscriptions: []*domainEntity.Subscription{sub}}
+		usageRepo := newMemUsageStatusRepo()
+		txRepo := &stubTransactionRepo{}
+		svc := NewUsageReconciliationService(usageRepo, subRepo, statusSvc)
+		svc.now = func() time.Time { return now }
+		builder := NewReadModelBuilder(subRepo, txRepo, &memSubscriptionStatusRepo{}, usageRepo)
+
+		_, err := svc.Report(ctx, uuid.New(), []ReportedUsage{
+			newTestReportedUsage(sub, "1", 500, now.Add(-10*time.Hour)),  // Billed by charge ID
+			newTestReportedUsage(sub, "2", 300, now.Add(-9*time.Hour)),   // Billed for 250 instead
+			newTestReportedUsage(sub, "3", 700, now.Add(-8*time.Hour)),   // Billed, transaction without charge ID
+			newTestReportedUsage(sub, "4", 900, now.Add(-100*time.Hour)), // Never billed, past the threshold
+			newTestReportedUsage(sub, "5", 400, now.Add(-time.Hour)),     // Not billed yet
+		})
+		if err != nil {
+			t.Fatalf("Report: %v", err)
+		}
+
+		txRepo.transactions = []*domainEntity.Transaction{
+			newTestUsageSale(sub, "10", "gid://shopify/AppUsageRecord/1", 500, now.Add(-9*time.Hour)),
+			newTestUsageSale(sub, "20", "2", 250, now.Add(-8*time.Hour)),
+			newTestUsageSale(sub, "30", "", 700, now.Add(-7*time.Hour)),
+			newTestUsageSale(sub, "40", "gid://shopify/AppUsageRecord/99", 1200, now.Add(-6*time.Hour)), // Not reported
+		}
+		if err := builder.rebuildUsageStatuses(ctx, app.ID); err != nil {
+			t.Fatalf("rebuildUsageStatuses: %v", err)
+		}
+
+		report, err := svc.GetReconciliation(ctx, uuid.New(), app.ID.String(), ReconciliationQuery{})
+		if err != nil {
+			t.Fatalf("GetReconciliation: %v", err)
+		}
+		want := entity.UsageReconciliationSummary{
+			Reported: 5, Pending: 1, Billed: 2, Overdue: 1, AmountMismatch: 1,
+			ReportedCents: 2800, BilledCents: 1450, UnbilledCents: 1300,
+		}
+		if report.Summary != want {
+			t.Errorf("summary = %+v, want %+v", report.Summary, want)
+		}
+
+		states := make(map[string]entity.UsageReconciliationState)
+		for _, record := range report.Records {
+			states[record.UsageID] = record.ReconciliationState
+		}
+		for id, want := range map[string]entity.UsageReconciliationState{
+			"1": entity.UsageStateBilled,
+			"2": entity.UsageStateAmountMismatch,
+			"3": entity.UsageStateBilled,
+			"4": entity.UsageStateOverdue,
+			"5": entity.UsageStatePending,
+		} {
+			if got := states["gid://shopify/AppUsageRecord/"+id]; got != want {
+				t.Errorf("record %s: state %s, want %s", id, got, want)
+			}
+		}
+
+		if _, err := usageRepo.GetByShopifyGID(ctx, "gid://partners/AppUsageSale/10"); err == nil {
+			t.Error("expected no ledger row for a transaction that billed a reported record")
+		}
+		if ledger, err := usageRepo.GetByShopifyGID(ctx, "gid://partners/AppUsageSale/40"); err != nil || ledger.Source != entity.UsageSourceLedger {
+			t.Errorf("expected a ledger row for the unreported transaction, got %+v (%v)", ledger, err)
+		}
+		unbilled, _ := usageRepo.GetUnbilledBySubscriptionID(ctx, sub.ID)
+		if len(unbilled) != 2 {
+			t.Errorf("expected 2 unbilled records, got %d", len(unbilled))
+		}
+
+		overdue, err := svc.GetReconciliation(ctx, uuid.New(), app.ID.String(), ReconciliationQuery{States: []string{"OVERDUE"}})
+		if err != nil || len(overdue.Records) != 1 || overdue.Records[0].UsageID != "gid://shopify/AppUsageRecord/4" {
+			t.Errorf("expected only record 4 listed as overdue, got %+v (%v)", overdue, err)
+		}
+	})
+
+	t.Run("rejects an unknown state", func(t *testing.T) {
+		statusSvc, app := newTestStatusServiceForApp()
+		svc := NewUsageReconciliationService(newMemUsageStatusRepo(), &stubLedgerSubscriptionRepo{}, statusSvc)
+		svc.now = func() time.Time { return now }
+
+		if _, err := svc.GetReconciliation(ctx, uuid.New(), app.ID.String(), ReconciliationQuery{States: []string{"LOST"}}); err != ErrInvalidReconciliationState {
+			t.Errorf("expected ErrInvalidReconciliationState, got %v", err)
+		}
+	})
+
+	t.Run("rejects a range that is too long", func(t *testing.T) {
+		statusSvc, app := newTestStatusServiceForApp()
+		svc := NewUsageReconciliationService(newMemUsageStatusRepo(), &stubLedgerSubscriptionRepo{}, statusSvc)
+		svc.now = func() time.Time { return now }
+
+		from := now.Add(-100 * 24 * time.Hour)
+		if _, err := svc.GetReconciliation(ctx, uuid.New(), app.ID.String(), ReconciliationQuery{From: &from}); err != ErrInvalidReconciliationRange {
+			t.Errorf("expected ErrInvalidReconciliationRange, got %v", err)
+		}
+	})
+
+	t.Run("denies another app", func(t *testing.T) {
+		statusSvc, _ := newTestStatusServiceForApp()
+		svc := NewUsageReconciliationService(newMemUsageStatusRepo(), &stubLedgerSubscriptionRepo{}, statusSvc)
+		svc.now = func() time.Time { return now }
+
+		if _, err := svc.GetReconciliation(ctx, uuid.New(), uuid.New().String(), ReconciliationQuery{}); err != ErrAppAccessDenied {
+			t.Errorf("expected ErrAppAccessDenied for another app, got %v", err)
+		}
+	})
+}
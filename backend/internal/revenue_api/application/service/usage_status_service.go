@@ -39,6 +39,10 @@ func NewUsageStatusService(
 
 // GetByShopifyGID retrieves a usage status by Shopify GID with parent subscription
 func (s *UsageStatusService) GetByShopifyGID(ctx context.Context, userID uuid.UUID, shopifyGID string) (*entity.UsageStatusResponse, error) {
+	if err := RequireScope(ctx, entity.ScopeUsageRead); err != nil {
+		return nil, err
+	}
+
 	// Get the usage status
 	usage, err := s.usageRepo.GetByShopifyGID(ctx, shopifyGID)
 	if err != nil {
@@ -63,6 +67,10 @@ func (s *UsageStatusService) GetByShopifyGID(ctx context.Context, userID uuid.UU
 
 // GetByShopifyGIDs retrieves multiple usage statuses by Shopify GIDs
 func (s *UsageStatusService) GetByShopifyGIDs(ctx context.Context, userID uuid.UUID, shopifyGIDs []string) (*entity.UsageStatusBatchResponse, error) {
+	if err := RequireScope(ctx, entity.ScopeUsageRead); err != nil {
+		return nil, err
+	}
+
 	if len(shopifyGIDs) == 0 {
 		return &entity.UsageStatusBatchResponse{
 			Results:  []entity.UsageStatusResponse{},
@@ -136,14 +144,9 @@ func (s *UsageStatusService) GetByShopifyGIDs(ctx context.Context, userID uuid.U
 	}, nil
 }
 
-// getUserApps returns all app IDs the user has access to
+// getUserApps returns all app IDs the user (and the calling API key) has access to
 func (s *UsageStatusService) getUserApps(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
-	partnerAccount, err := s.partnerRepo.FindByUserID(ctx, userID)
-	if err != nil {
-		return nil, err
-	}
-
-	apps, err := s.appRepo.FindByPartnerAccountID(ctx, partnerAccount.ID)
+	apps, err := findUserApps(ctx, s.partnerRepo, s.appRepo, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -153,22 +156,21 @@ func (s *UsageStatusService) getUserApps(ctx context.Context, userID uuid.UUID)
 		appIDs[i] = app.ID
 	}
 
-	return appIDs, nil
+	return filterAllowedApps(ctx, appIDs), nil
 }
 
-// verifyAppAccess checks if a user has access to an app
+// verifyAppAccess checks if a user, and the calling API key, has access to an app
 func (s *UsageStatusService) verifyAppAccess(ctx context.Context, userID uuid.UUID, appID uuid.UUID) error {
-	app, err := s.appRepo.FindByID(ctx, appID)
-	if err != nil {
+	if !APIKeyAccessFromContext(ctx).AllowsApp(appID) {
 		return ErrAppAccessDenied
 	}
 
-	partnerAccount, err := s.partnerRepo.FindByUserID(ctx, userID)
+	app, err := s.appRepo.FindByID(ctx, appID)
 	if err != nil {
 		return ErrAppAccessDenied
 	}
 
-	if app.PartnerAccountID != partnerAccount.ID {
+	if !userCanAccessPartnerAccount(ctx, s.partnerRepo, userID, app.PartnerAccountID) {
 		return ErrAppAccessDenied
 	}
 
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/pkg/netguard"
+)
+
+// Outbound webhook request headers
+const (
+	WebhookSignatureHeader  = "X-LedgerGuard-Signature"
+	WebhookTopicHeader      = "X-LedgerGuard-Topic"
+	WebhookEventIDHeader    = "X-LedgerGuard-Event-Id"
+	WebhookDeliveryIDHeader = "X-LedgerGuard-Delivery-Id"
+)
+
+const (
+	// MaxWebhookDeliveryAttempts is the number of attempts before a delivery fails (~29h of retries)
+	MaxWebhookDeliveryAttempts = 12
+	// DefaultWebhookDisableThreshold is the number of failed deliveries in a row that disables an endpoint
+	DefaultWebhookDisableThreshold = 5
+
+	webhookRetryBaseDelay   = 30 * time.Second
+	webhookRetryMaxDelay    = 12 * time.Hour
+	webhookRequestTimeout   = 10 * time.Second
+	webhookClaimLease       = time.Minute // Longer than a request can take
+	webhookMaxResponseBytes = 1024
+)
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the endpoint secret
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookSignatureHeaderValue formats the signature header: "t=<unix seconds>,v1=<signature>"
+func WebhookSignatureHeaderValue(secret string, timestamp int64, body []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, SignWebhookPayload(secret, timestamp, body))
+}
+
+// VerifyWebhookSignature checks a signature header against the body. Signatures
+// older than tolerance are rejected to limit replays; zero disables the check.
+func VerifyWebhookSignature(secret, header string, body []byte, tolerance time.Duration, now time.Time) bool {
+	var timestamp int64
+	var signatures []string
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return false
+			}
+			timestamp = t
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if timestamp == 0 || len(signatures) == 0 {
+		return false
+	}
+	if tolerance > 0 {
+		age := now.Sub(time.Unix(timestamp, 0))
+		if age > tolerance || age < -tolerance {
+			return false
+		}
+	}
+
+	expected := SignWebhookPayload(secret, timestamp, body)
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookRetryDelay returns the wait after the given failed attempt: 30s, 1m, 2m, ... capped at 12h
+func webhookRetryDelay(attempt int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= webhookRetryMaxDelay {
+			return webhookRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// WebhookDispatcher sends pending webhook deliveries with exponential-backoff retries
+type WebhookDispatcher struct {
+	endpointRepo     repository.WebhookEndpointRepository
+	deliveryRepo     repository.WebhookDeliveryRepository
+	decryptor        SecretDecryptor
+	client           *http.Client
+	interval         time.Duration
+	batchSize        int
+	disableThreshold int
+	now              func() time.Time
+	stopCh           chan struct{}
+	doneCh           chan struct{}
+}
+
+// NewWebhookDispatcher creates a new WebhookDispatcher polling every 5 seconds.
+// Deliveries only connect to public addresses and redirects are reported as
+// failures rather than followed.
+func NewWebhookDispatcher(
+	endpointRepo repository.WebhookEndpointRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	decryptor SecretDecryptor,
+) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		endpointRepo:     endpointRepo,
+		deliveryRepo:     deliveryRepo,
+		decryptor:        decryptor,
+		client:           netguard.New(false).HTTPClient(webhookRequestTimeout),
+		interval:         5 * time.Second,
+		batchSize:        100,
+		disableThreshold: DefaultWebhookDisableThreshold,
+		now:              func() time.Time { return time.Now().UTC() },
+		stopCh:           make(chan struct{}),
+		doneCh:           make(chan struct{}),
+	}
+}
+
+// WithHTTPClient replaces the HTTP client used for deliveries
+func (d *WebhookDispatcher) WithHTTPClient(client *http.Client) *WebhookDispatcher {
+	d.client = client
+	return d
+}
+
+// WithURLGuard sends deliveries through a client dialing with guard
+func (d *WebhookDispatcher) WithURLGuard(guard *netguard.Guard) *WebhookDispatcher {
+	d.client = guard.HTTPClient(webhookRequestTimeout)
+	return d
+}
+
+// WithInterval sets how often pending deliveries are polled
+func (d *WebhookDispatcher) WithInterval(interval time.Duration) *WebhookDispatcher {
+	d.interval = interval
+	return d
+}
+
+// WithDisableThreshold sets how many failed deliveries in a row disable an endpoint
+func (d *WebhookDispatcher) WithDisableThreshold(threshold int) *WebhookDispatcher {
+	d.disableThreshold = threshold
+	return d
+}
+
+// Start begins polling for due deliveries
+func (d *WebhookDispatcher) Start(ctx context.Context) {
+	go d.run(ctx)
+}
+
+// Stop gracefully stops the dispatcher after the current batch
+func (d *WebhookDispatcher) Stop() {
+	close(d.stopCh)
+	<-d.doneCh
+}
+
+func (d *WebhookDispatcher) run(ctx context.Context) {
+	defer close(d.doneCh)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// Drain full batches before waiting for the next tick
+			for {
+				n, err := d.DispatchDue(ctx)
+				if err != nil {
+					log.Printf("WebhookDispatcher: %v", err)
+				}
+				if err != nil || n < d.batchSize {
+					break
+				}
+			}
+		case <-d.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// DispatchDue attempts every pending delivery that is due and returns how many were attempted
+func (d *WebhookDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	deliveries, err := d.deliveryRepo.ClaimDue(ctx, d.now(), webhookClaimLease, d.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due deliveries: %w", err)
+	}
+
+	endpoints := make(map[uuid.UUID]*entity.WebhookEndpoint)
+	for _, delivery := range deliveries {
+		endpoint, ok := endpoints[delivery.EndpointID]
+		if !ok {
+			endpoint, err = d.endpointRepo.GetByID(ctx, delivery.EndpointID)
+			if err != nil {
+				endpoint = nil
+			}
+			endpoints[delivery.EndpointID] = endpoint
+		}
+
+		if err := d.attempt(ctx, endpoint, delivery); err != nil {
+			log.Printf("WebhookDispatcher: delivery %s: %v", delivery.ID, err)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// attempt sends one delivery and records the outcome on the delivery and endpoint
+func (d *WebhookDispatcher) attempt(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) error {
+	now := d.now()
+
+	if endpoint == nil || !endpoint.Enabled {
+		delivery.Status = entity.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = "endpoint disabled"
+		return d.deliveryRepo.Update(ctx, delivery)
+	}
+
+	status, body, sendErr := d.send(ctx, endpoint, delivery, now)
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = status
+	delivery.ResponseBody = body
+	delivery.LastError = ""
+
+	if sendErr == nil && status >= 200 && status < 300 {
+		delivery.Status = entity.WebhookDeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		if err := d.deliveryRepo.Update(ctx, delivery); err != nil {
+			return err
+		}
+
+		if endpoint.ConsecutiveFailures > 0 {
+			endpoint.RecordSuccess(now)
+			return d.endpointRepo.Update(ctx, endpoint)
+		}
+		return nil
+	}
+
+	if errors.Is(sendErr, netguard.ErrDisallowedAddress) {
+		// Don't echo which internal address the host resolved to
+		delivery.LastError = netguard.ErrDisallowedAddress.Error()
+	} else if sendErr != nil {
+		delivery.LastError = sendErr.Error()
+	} else {
+		delivery.LastError = fmt.Sprintf("endpoint responded with HTTP %d", status)
+	}
+
+	if delivery.Attempts < MaxWebhookDeliveryAttempts {
+		next := now.Add(webhookRetryDelay(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		return d.deliveryRepo.Update(ctx, delivery)
+	}
+
+	delivery.Status = entity.WebhookDeliveryFailed
+	delivery.NextAttemptAt = nil
+	if err := d.deliveryRepo.Update(ctx, delivery); err != nil {
+		return err
+	}
+
+	disabled := endpoint.RecordFailure(now, d.disableThreshold)
+	if err := d.endpointRepo.Update(ctx, endpoint); err != nil {
+		return err
+	}
+	if disabled {
+		log.Printf("WebhookDispatcher: disabled endpoint %s after %d failed deliveries", endpoint.ID, endpoint.ConsecutiveFailures)
+		return d.deliveryRepo.FailPendingByEndpointID(ctx, endpoint.ID, "endpoint disabled")
+	}
+	return nil
+}
+
+// send POSTs the signed payload and returns the status code and a truncated response body
+func (d *WebhookDispatcher) send(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery, now time.Time) (int, string, error) {
+	secret, err := d.signingSecret(endpoint)
+	if err != nil {
+		return 0, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "LedgerGuard-Webhooks/1.0")
+	req.Header.Set(WebhookTopicHeader, delivery.Topic)
+	req.Header.Set(WebhookEventIDHeader, delivery.EventID.String())
+	req.Header.Set(WebhookDeliveryIDHeader, delivery.ID.String())
+	req.Header.Set(WebhookSignatureHeader, WebhookSignatureHeaderValue(secret, now.Unix(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBytes))
+	return resp.StatusCode, string(body), nil
+}
+
+// signingSecret decrypts the endpoint's secret
+func (d *WebhookDispatcher) signingSecret(endpoint *entity.WebhookEndpoint) (string, error) {
+	secret, err := d.decryptor.Decrypt(endpoint.EncryptedSecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt signing secret: %w", err)
+	}
+	return string(secret), nil
+}
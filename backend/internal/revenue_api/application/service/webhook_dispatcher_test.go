@@ -0,0 +1,592 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	domainEntity "github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/pkg/crypto"
+	"github.com/sachin-sivadasan/ledgerguard/pkg/netguard"
+)
+
+var errTestNotFound = errors.New("not found")
+
+type memWebhookEndpointRepo struct {
+	endpoints map[uuid.UUID]*entity.WebhookEndpoint
+}
+
+func newMemWebhookEndpointRepo(endpoints ...*entity.WebhookEndpoint) *memWebhookEndpointRepo {
+	repo := &memWebhookEndpointRepo{endpoints: make(map[uuid.UUID]*entity.WebhookEndpoint)}
+	for _, e := range endpoints {
+		repo.endpoints[e.ID] = e
+	}
+	return repo
+}
+
+func (m *memWebhookEndpointRepo) Create(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	m.endpoints[endpoint.ID] = endpoint
+	return nil
+}
+
+func (m *memWebhookEndpointRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookEndpoint, error) {
+	if e, ok := m.endpoints[id]; ok {
+		copied := *e
+		return &copied, nil
+	}
+	return nil, errTestNotFound
+}
+
+func (m *memWebhookEndpointRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WebhookEndpoint, error) {
+	var result []*entity.WebhookEndpoint
+	for _, e := range m.endpoints {
+		if e.UserID == userID {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (m *memWebhookEndpointRepo) GetEnabledByUserIDAndTopic(ctx context.Context, userID uuid.UUID, topic string) ([]*entity.WebhookEndpoint, error) {
+	var result []*entity.WebhookEndpoint
+	for _, e := range m.endpoints {
+		if e.UserID == userID && e.Enabled && e.Subscribes(topic) {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (m *memWebhookEndpointRepo) Update(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	copied := *endpoint
+	m.endpoints[endpoint.ID] = &copied
+	return nil
+}
+
+func (m *memWebhookEndpointRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.endpoints, id)
+	return nil
+}
+
+type memWebhookDeliveryRepo struct {
+	deliveries map[uuid.UUID]*entity.WebhookDelivery
+}
+
+func newMemWebhookDeliveryRepo() *memWebhookDeliveryRepo {
+	return &memWebhookDeliveryRepo{deliveries: make(map[uuid.UUID]*entity.WebhookDelivery)}
+}
+
+func (m *memWebhookDeliveryRepo) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	m.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+func (m *memWebhookDeliveryRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	if d, ok := m.deliveries[id]; ok {
+		return d, nil
+	}
+	return nil, errTestNotFound
+}
+
+func (m *memWebhookDeliveryRepo) GetByEndpointID(ctx context.Context, endpointID uuid.UUID, limit int) ([]*entity.WebhookDelivery, error) {
+	var result []*entity.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.EndpointID == endpointID {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func (m *memWebhookDeliveryRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	var result []*entity.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.Status == entity.WebhookDeliveryPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) {
+			leased := now.Add(lease)
+			d.NextAttemptAt = &leased
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+func (m *memWebhookDeliveryRepo) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	m.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+func (m *memWebhookDeliveryRepo) FailPendingByEndpointID(ctx context.Context, endpointID uuid.UUID, reason string) error {
+	for _, d := range m.deliveries {
+		if d.EndpointID == endpointID && d.Status == entity.WebhookDeliveryPending {
+			d.Status = entity.WebhookDeliveryFailed
+			d.NextAttemptAt = nil
+			d.LastError = reason
+		}
+	}
+	return nil
+}
+
+var testSecretEncryptor, _ = crypto.NewAESEncryptor([]byte("12345678901234567890123456789012"))
+
+// newTestEndpoint creates an endpoint whose secret is stored encrypted; Secret
+// keeps the plaintext so tests can verify signatures
+func newTestEndpoint(t *testing.T, url string) *entity.WebhookEndpoint {
+	t.Helper()
+	endpoint, err := entity.NewWebhookEndpoint(uuid.New(), url, "", []string{entity.WebhookTopicSubscriptionRiskChanged})
+	if err != nil {
+		t.Fatalf("failed to create endpoint: %v", err)
+	}
+	endpoint.EncryptedSecret, err = testSecretEncryptor.Encrypt([]byte(endpoint.Secret))
+	if err != nil {
+		t.Fatalf("failed to encrypt secret: %v", err)
+	}
+	return endpoint
+}
+
+// newTestDispatcher creates a dispatcher that may reach the loopback httptest servers
+func newTestDispatcher(endpointRepo *memWebhookEndpointRepo, deliveryRepo *memWebhookDeliveryRepo) *WebhookDispatcher {
+	return NewWebhookDispatcher(endpointRepo, deliveryRepo, testSecretEncryptor).WithURLGuard(netguard.New(true))
+}
+
+func TestWebhookDispatcher_DeliversSignedPayload(t *testing.T) {
+	var gotBody []byte
+	var gotHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeaders = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	endpoint := newTestEndpoint(t, server.URL)
+	endpointRepo := newMemWebhookEndpointRepo(endpoint)
+	deliveryRepo := newMemWebhookDeliveryRepo()
+	delivery := entity.NewWebhookDelivery(endpoint.ID, uuid.New(), entity.WebhookTopicSubscriptionRiskChanged, []byte(`{"type":"subscription.risk_changed"}`))
+	deliveryRepo.Create(context.Background(), delivery)
+
+	n, err := newTestDispatcher(endpointRepo, deliveryRepo).DispatchDue(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 dispatched delivery, got %d (%v)", n, err)
+	}
+
+	if string(gotBody) != `{"type":"subscription.risk_changed"}` {
+		t.Errorf("unexpected body %q", gotBody)
+	}
+	if gotHeaders.Get(WebhookEventIDHeader) != delivery.EventID.String() {
+		t.Errorf("expected event ID header %s, got %q", delivery.EventID, gotHeaders.Get(WebhookEventIDHeader))
+	}
+	if !VerifyWebhookSignature(endpoint.Secret, gotHeaders.Get(WebhookSignatureHeader), gotBody, 5*time.Minute, time.Now()) {
+		t.Errorf("signature %q does not verify", gotHeaders.Get(WebhookSignatureHeader))
+	}
+
+	saved := deliveryRepo.deliveries[delivery.ID]
+	if saved.Status != entity.WebhookDeliverySucceeded || saved.Attempts != 1 || saved.DeliveredAt == nil {
+		t.Errorf("expected succeeded after 1 attempt, got %s after %d", saved.Status, saved.Attempts)
+	}
+	if saved.ResponseStatus != http.StatusOK {
+		t.Errorf("expected response status 200, got %d", saved.ResponseStatus)
+	}
+}
+
+func TestWebhookDispatcher_SchedulesRetryWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("maintenance"))
+	}))
+	defer server.Close()
+
+	endpoint := newTestEndpoint(t, server.URL)
+	deliveryRepo := newMemWebhookDeliveryRepo()
+	delivery := entity.NewWebhookDelivery(endpoint.ID, uuid.New(), entity.WebhookTopicSubscriptionRiskChanged, []byte(`{}`))
+	delivery.Attempts = 2
+	deliveryRepo.Create(context.Background(), delivery)
+
+	dispatcher := newTestDispatcher(newMemWebhookEndpointRepo(endpoint), deliveryRepo)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	dispatcher.now = func() time.Time { return now }
+	past := now.Add(-time.Second)
+	delivery.NextAttemptAt = &past
+
+	dispatcher.DispatchDue(context.Background())
+
+	saved := deliveryRepo.deliveries[delivery.ID]
+	if saved.Status != entity.WebhookDeliveryPending {
+		t.Fatalf("expected PENDING, got %s", saved.Status)
+	}
+	if saved.Attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", saved.Attempts)
+	}
+	if want := now.Add(2 * time.Minute); saved.NextAttemptAt == nil || !saved.NextAttemptAt.Equal(want) {
+		t.Errorf("expected next attempt at %v, got %v", want, saved.NextAttemptAt)
+	}
+	if saved.ResponseStatus != http.StatusServiceUnavailable || saved.ResponseBody != "maintenance" {
+		t.Errorf("unexpected response %d %q", saved.ResponseStatus, saved.ResponseBody)
+	}
+}
+
+func TestWebhookDispatcher_DisablesEndpointAfterRepeatedFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	endpoint := newTestEndpoint(t, server.URL)
+	endpoint.ConsecutiveFailures = 1
+	endpointRepo := newMemWebhookEndpointRepo(endpoint)
+	deliveryRepo := newMemWebhookDeliveryRepo()
+
+	exhausted := entity.NewWebhookDelivery(endpoint.ID, uuid.New(), entity.WebhookTopicSubscriptionRiskChanged, []byte(`{}`))
+	exhausted.Attempts = MaxWebhookDeliveryAttempts - 1
+	deliveryRepo.Create(context.Background(), exhausted)
+
+	queued := entity.NewWebhookDelivery(endpoint.ID, uuid.New(), entity.WebhookTopicSubscriptionRiskChanged, []byte(`{}`))
+	future := time.Now().Add(time.Hour)
+	queued.NextAttemptAt = &future
+	deliveryRepo.Create(context.Background(), queued)
+
+	newTestDispatcher(endpointRepo, deliveryRepo).WithDisableThreshold(2).DispatchDue(context.Background())
+
+	if deliveryRepo.deliveries[exhausted.ID].Status != entity.WebhookDeliveryFailed {
+		t.Errorf("expected exhausted delivery to fail, got %s", deliveryRepo.deliveries[exhausted.ID].Status)
+	}
+
+	saved := endpointRepo.endpoints[endpoint.ID]
+	if saved.Enabled || saved.DisabledAt == nil {
+		t.Fatalf("expected endpoint to be disabled")
+	}
+	if saved.ConsecutiveFailures != 2 {
+		t.Errorf("expected 2 consecutive failures, got %d", saved.ConsecutiveFailures)
+	}
+	if deliveryRepo.deliveries[queued.ID].Status != entity.WebhookDeliveryFailed {
+		t.Errorf("expected queued delivery of disabled endpoint to fail")
+	}
+}
+
+func TestWebhookDispatcher_SuccessResetsFailureStreak(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	endpoint := newTestEndpoint(t, server.URL)
+	endpoint.ConsecutiveFailures = 3
+	endpointRepo := newMemWebhookEndpointRepo(endpoint)
+	deliveryRepo := newMemWebhookDeliveryRepo()
+	deliveryRepo.Create(context.Background(), entity.NewWebhookDelivery(endpoint.ID, uuid.New(), entity.WebhookTopicSubscriptionRiskChanged, []byte(`{}`)))
+
+	newTestDispatcher(endpointRepo, deliveryRepo).DispatchDue(context.Background())
+
+	if got := endpointRepo.endpoints[endpoint.ID].ConsecutiveFailures; got != 0 {
+		t.Errorf("expected failure streak reset, got %d", got)
+	}
+}
+
+func TestWebhookDispatcher_RedirectIsFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://example.com/elsewhere", http.StatusFound)
+	}))
+	defer server.Close()
+
+	endpoint := newTestEndpoint(t, server.URL)
+	deliveryRepo := newMemWebhookDeliveryRepo()
+	delivery := entity.NewWebhookDelivery(endpoint.ID, uuid.New(), entity.WebhookTopicSubscriptionRiskChanged, []byte(`{}`))
+	deliveryRepo.Create(context.Background(), delivery)
+
+	newTestDispatcher(newMemWebhookEndpointRepo(endpoint), deliveryRepo).DispatchDue(context.Background())
+
+	saved := deliveryRepo.deliveries[delivery.ID]
+	if saved.Status != entity.WebhookDeliveryPending || saved.ResponseStatus != http.StatusFound {
+		t.Errorf("expected a retry after redirect, got %s with HTTP %d", saved.Status, saved.ResponseStatus)
+	}
+}
+
+func TestWebhookDispatcher_RefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("delivery should not reach a loopback address")
+	}))
+	defer server.Close()
+
+	endpoint := newTestEndpoint(t, server.URL)
+	deliveryRepo := newMemWebhookDeliveryRepo()
+	delivery := entity.NewWebhookDelivery(endpoint.ID, uuid.New(), entity.WebhookTopicSubscriptionRiskChanged, []byte(`{}`))
+	deliveryRepo.Create(context.Background(), delivery)
+
+	NewWebhookDispatcher(newMemWebhookEndpointRepo(endpoint), deliveryRepo, testSecretEncryptor).DispatchDue(context.Background())
+
+	saved := deliveryRepo.deliveries[delivery.ID]
+	if saved.Status != entity.WebhookDeliveryPending || saved.ResponseStatus != 0 {
+		t.Errorf("expected a failed attempt without a response, got %s with HTTP %d", saved.Status, saved.ResponseStatus)
+	}
+	if saved.LastError != netguard.ErrDisallowedAddress.Error() {
+		t.Errorf("expected a generic error, got %q", saved.LastError)
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{5, 8 * time.Minute},
+		{11, 512 * time.Minute},
+		{12, 12 * time.Hour},
+		{40, 12 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := webhookRetryDelay(tt.attempt); got != tt.want {
+			t.Errorf("webhookRetryDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"id":"evt"}`)
+	now := time.Unix(1_800_000_000, 0)
+	header := WebhookSignatureHeaderValue("whsec_test", now.Unix(), body)
+
+	if !VerifyWebhookSignature("whsec_test", header, body, 5*time.Minute, now.Add(time.Minute)) {
+		t.Error("expected valid signature")
+	}
+	if VerifyWebhookSignature("whsec_other", header, body, 5*time.Minute, now) {
+		t.Error("expected wrong secret to fail")
+	}
+	if VerifyWebhookSignature("whsec_test", header, []byte(`{"id":"tampered"}`), 5*time.Minute, now) {
+		t.Error("expected tampered body to fail")
+	}
+	if VerifyWebhookSignature("whsec_test", header, body, 5*time.Minute, now.Add(10*time.Minute)) {
+		t.Error("expected stale signature to fail")
+	}
+	if VerifyWebhookSignature("whsec_test", "v1=abc", body, 0, now) {
+		t.Error("expected header without timestamp to fail")
+	}
+}
+
+type stubAppRepo struct {
+	app *domainEntity.App
+}
+
+func (m *stubAppRepo) Create(ctx context.Context, app *domainEntity.App) error { return nil }
+
+func (m *stubAppRepo) FindByID(ctx context.Context, id uuid.UUID) (*domainEntity.App, error) {
+	if m.app.ID == id {
+		return m.app, nil
+	}
+	return nil, errTestNotFound
+}
+
+func (m *stubAppRepo) FindByPartnerAccountID(ctx context.Context, partnerAccountID uuid.UUID) ([]*domainEntity.App, error) {
+	return []*domainEntity.App{m.app}, nil
+}
+
+func (m *stubAppRepo) FindByPartnerAppID(ctx context.Context, partnerAccountID uuid.UUID, partnerAppID string) (*domainEntity.App, error) {
+	return nil, errTestNotFound
+}
+
+func (m *stubAppRepo) FindAllByPartnerAppID(ctx context.Context, partnerAppID string) ([]*domainEntity.App, error) {
+	return nil, nil
+}
+
+func (m *stubAppRepo) Update(ctx context.Context, app *domainEntity.App) error { return nil }
+
+func (m *stubAppRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
+
+type stubPartnerRepo struct {
+	account *domainEntity.PartnerAccount
+}
+
+func (m *stubPartnerRepo) Create(ctx context.Context, account *domainEntity.PartnerAccount) error {
+	return nil
+}
+
+func (m *stubPartnerRepo) FindByID(ctx context.Context, id uuid.UUID) (*domainEntity.PartnerAccount, error) {
+	if m.account.ID == id {
+		return m.account, nil
+	}
+	return nil, errTestNotFound
+}
+
+func (m *stubPartnerRepo) FindByUserID(ctx context.Context, userID uuid.UUID) (*domainEntity.PartnerAccount, error) {
+	return m.account, nil
+}
+
+func (m *stubPartnerRepo) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (*domainEntity.PartnerAccount, error) {
+	return m.account, nil
+}
+
+func (m *stubPartnerRepo) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]*domainEntity.PartnerAccount, error) {
+	if m.account == nil {
+		return nil, nil
+	}
+	return []*domainEntity.PartnerAccount{m.account}, nil
+}
+
+func (m *stubPartnerRepo) FindByPartnerID(ctx context.Context, partnerID string) (*domainEntity.PartnerAccount, error) {
+	return nil, errTestNotFound
+}
+
+func (m *stubPartnerRepo) Update(ctx context.Context, account *domainEntity.PartnerAccount) error {
+	return nil
+}
+
+func (m *stubPartnerRepo) Delete(ctx context.Context, userID uuid.UUID) error { return nil }
+
+func (m *stubPartnerRepo) GetAllIDs(ctx context.Context) ([]uuid.UUID, error) { return nil, nil }
+
+func TestWebhookPublisher_PublishSubscriptionEvent(t *testing.T) {
+	account := &domainEntity.PartnerAccount{ID: uuid.New(), UserID: uuid.New()}
+	app := &domainEntity.App{ID: uuid.New(), PartnerAccountID: account.ID}
+
+	riskOnly, _ := entity.NewWebhookEndpoint(account.UserID, "https://a.example.com", "", []string{entity.WebhookTopicSubscriptionRiskChanged})
+	both, _ := entity.NewWebhookEndpoint(account.UserID, "https://b.example.com", "", []string{entity.WebhookTopicSubscriptionRiskChanged, entity.WebhookTopicSubscriptionStatusChanged})
+	disabled, _ := entity.NewWebhookEndpoint(account.UserID, "https://c.example.com", "", []string{entity.WebhookTopicSubscriptionStatusChanged})
+	disabled.Enabled = false
+	otherUser, _ := entity.NewWebhookEndpoint(uuid.New(), "https://d.example.com", "", []string{entity.WebhookTopicSubscriptionStatusChanged})
+
+	deliveryRepo := newMemWebhookDeliveryRepo()
+	publisher := NewWebhookPublisher(
+		&stubAppRepo{app: app},
+		&stubPartnerRepo{account: account},
+		newMemWebhookEndpointRepo(riskOnly, both, disabled, otherUser),
+		deliveryRepo,
+	)
+
+	sub := &domainEntity.Subscription{ID: uuid.New(), AppID: app.ID, ShopifyGID: "gid://shopify/AppSubscription/7", MyshopifyDomain: "shop.myshopify.com"}
+	event := domainEntity.NewSubscriptionEvent(sub.ID, "ACTIVE", "FROZEN", valueobject.RiskStateSafe, valueobject.RiskStateTwoCyclesMissed, "webhook", "")
+
+	if err := publisher.PublishSubscriptionEvent(context.Background(), sub, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	counts := make(map[uuid.UUID]int)
+	for _, d := range deliveryRepo.deliveries {
+		counts[d.EndpointID]++
+
+		var envelope struct {
+			Type string                         `json:"type"`
+			Data entity.SubscriptionChangedData `json:"data"`
+		}
+		if err := json.Unmarshal(d.Payload, &envelope); err != nil {
+			t.Fatalf("invalid payload: %v", err)
+		}
+		if envelope.Type != d.Topic {
+			t.Errorf("envelope type %s does not match topic %s", envelope.Type, d.Topic)
+		}
+		if envelope.Data.Status != "FROZEN" || envelope.Data.PreviousRiskState != "SAFE" || envelope.Data.SubscriptionID != sub.ShopifyGID {
+			t.Errorf("unexpected data %+v", envelope.Data)
+		}
+	}
+
+	if counts[riskOnly.ID] != 1 || counts[both.ID] != 2 || counts[disabled.ID] != 0 || counts[otherUser.ID] != 0 {
+		t.Errorf("unexpected fan-out %v", counts)
+	}
+}
+
+func TestWebhookEndpointService_ValidatesInput(t *testing.T) {
+	svc := NewWebhookEndpointService(newMemWebhookEndpointRepo(), newMemWebhookDeliveryRepo(), testSecretEncryptor)
+	userID := uuid.New()
+
+	tests := []struct {
+		name   string
+		url    string
+		topics []string
+		want   error
+	}{
+		{"https", "https://example.com/hooks", []string{entity.WebhookTopicUsageBilled}, nil},
+		{"localhost http", "http://localhost:8080/hooks", []string{entity.WebhookTopicUsageBilled}, ErrInvalidWebhookURL},
+		{"metadata service", "https://169.254.169.254/latest/meta-data", []string{entity.WebhookTopicUsageBilled}, ErrInvalidWebhookURL},
+		{"private network", "https://10.0.0.8/hooks", []string{entity.WebhookTopicUsageBilled}, ErrInvalidWebhookURL},
+		{"plain http", "http://example.com/hooks", []string{entity.WebhookTopicUsageBilled}, ErrInvalidWebhookURL},
+		{"relative", "/hooks", []string{entity.WebhookTopicUsageBilled}, ErrInvalidWebhookURL},
+		{"unknown topic", "https://example.com", []string{"shop.created"}, ErrInvalidWebhookTopics},
+		{"no topics", "https://example.com", nil, ErrInvalidWebhookTopics},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Create(context.Background(), CreateWebhookEndpointRequest{UserID: userID, URL: tt.url, Topics: tt.topics})
+			if err != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestWebhookEndpointService_AllowsLocalhostWhenConfigured(t *testing.T) {
+	svc := NewWebhookEndpointService(newMemWebhookEndpointRepo(), newMemWebhookDeliveryRepo(), testSecretEncryptor).
+		WithURLGuard(netguard.New(true))
+
+	_, err := svc.Create(context.Background(), CreateWebhookEndpointRequest{
+		UserID: uuid.New(),
+		URL:    "http://localhost:8080/hooks",
+		Topics: []string{entity.WebhookTopicUsageBilled},
+	})
+	if err != nil {
+		t.Errorf("expected localhost to be accepted, got %v", err)
+	}
+}
+
+func TestWebhookEndpointService_StoresSecretEncrypted(t *testing.T) {
+	endpointRepo := newMemWebhookEndpointRepo()
+	svc := NewWebhookEndpointService(endpointRepo, newMemWebhookDeliveryRepo(), testSecretEncryptor)
+
+	endpoint, err := svc.Create(context.Background(), CreateWebhookEndpointRequest{
+		UserID: uuid.New(),
+		URL:    "https://example.com/hooks",
+		Topics: []string{entity.WebhookTopicUsageBilled},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decrypted, err := testSecretEncryptor.Decrypt(endpoint.EncryptedSecret)
+	if err != nil || string(decrypted) != endpoint.Secret {
+		t.Fatalf("expected the encrypted secret to decrypt to the returned secret (%v)", err)
+	}
+
+	rotated, err := svc.RotateSecret(context.Background(), endpoint.UserID, endpoint.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decrypted, _ = testSecretEncryptor.Decrypt(endpointRepo.endpoints[endpoint.ID].EncryptedSecret)
+	if string(decrypted) != rotated.Secret || rotated.Secret == endpoint.Secret {
+		t.Errorf("expected the rotated secret to be stored encrypted")
+	}
+}
+
+func TestWebhookEndpointService_Redeliver(t *testing.T) {
+	endpoint := newTestEndpoint(t, "https://example.com")
+	deliveryRepo := newMemWebhookDeliveryRepo()
+	svc := NewWebhookEndpointService(newMemWebhookEndpointRepo(endpoint), deliveryRepo, testSecretEncryptor)
+
+	original := entity.NewWebhookDelivery(endpoint.ID, uuid.New(), entity.WebhookTopicSubscriptionRiskChanged, []byte(`{"a":1}`))
+	original.Status = entity.WebhookDeliveryFailed
+	deliveryRepo.Create(context.Background(), original)
+
+	redelivery, err := svc.Redeliver(context.Background(), endpoint.UserID, endpoint.ID, original.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if redelivery.Status != entity.WebhookDeliveryPending || redelivery.EventID != original.EventID {
+		t.Errorf("expected pending redelivery of the same event")
+	}
+	if redelivery.RedeliveryOf == nil || *redelivery.RedeliveryOf != original.ID {
+		t.Errorf("expected redelivery to reference the original")
+	}
+
+	if _, err := svc.Redeliver(context.Background(), uuid.New(), endpoint.ID, original.ID); err != ErrWebhookEndpointNotFound {
+		t.Errorf("expected other users to get ErrWebhookEndpointNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/pkg/netguard"
+)
+
+var (
+	ErrWebhookEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL       = errors.New("url must be an absolute https URL to a public host")
+	ErrInvalidWebhookTopics    = errors.New("topics must contain at least one known topic")
+	ErrTooManyWebhookEndpoints = errors.New("webhook endpoint limit reached")
+	ErrWebhookEndpointDisabled = errors.New("webhook endpoint is disabled")
+)
+
+const (
+	// MaxWebhookEndpointsPerUser caps how many endpoints one user can register
+	MaxWebhookEndpointsPerUser = 10
+	// DefaultWebhookDeliveryLimit is the number of log entries returned by default
+	DefaultWebhookDeliveryLimit = 50
+	// MaxWebhookDeliveryLimit is the maximum number of log entries per request
+	MaxWebhookDeliveryLimit = 200
+)
+
+// SecretDecryptor decrypts webhook signing secrets for signing deliveries
+type SecretDecryptor interface {
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// SecretEncryptor encrypts webhook signing secrets before they are stored
+type SecretEncryptor interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	SecretDecryptor
+}
+
+// WebhookEndpointService manages outbound webhook endpoints and their delivery log
+type WebhookEndpointService struct {
+	endpointRepo repository.WebhookEndpointRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	encryptor    SecretEncryptor
+	urlGuard     *netguard.Guard
+}
+
+// NewWebhookEndpointService creates a new WebhookEndpointService that only
+// accepts URLs to public hosts
+func NewWebhookEndpointService(
+	endpointRepo repository.WebhookEndpointRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	encryptor SecretEncryptor,
+) *WebhookEndpointService {
+	return &WebhookEndpointService{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		encryptor:    encryptor,
+		urlGuard:     netguard.New(false),
+	}
+}
+
+// WithURLGuard replaces the guard endpoint URLs are validated with
+func (s *WebhookEndpointService) WithURLGuard(guard *netguard.Guard) *WebhookEndpointService {
+	s.urlGuard = guard
+	return s
+}
+
+// CreateWebhookEndpointRequest contains the data for registering an endpoint
+type CreateWebhookEndpointRequest struct {
+	UserID      uuid.UUID
+	URL         string
+	Description string
+	Topics      []string
+}
+
+// UpdateWebhookEndpointRequest contains the fields to change; nil fields are left as-is
+type UpdateWebhookEndpointRequest struct {
+	URL         *string
+	Description *string
+	Topics      []string
+	Enabled     *bool
+}
+
+// Create registers a new endpoint. The returned endpoint carries the signing secret.
+func (s *WebhookEndpointService) Create(ctx context.Context, req CreateWebhookEndpointRequest) (*entity.WebhookEndpoint, error) {
+	if err := s.validateURL(req.URL); err != nil {
+		return nil, err
+	}
+	topics, err := normalizeWebhookTopics(req.Topics)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.endpointRepo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxWebhookEndpointsPerUser {
+		return nil, ErrTooManyWebhookEndpoints
+	}
+
+	endpoint, err := entity.NewWebhookEndpoint(req.UserID, req.URL, req.Description, topics)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.encryptSecret(endpoint); err != nil {
+		return nil, err
+	}
+
+	if err := s.endpointRepo.Create(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+// List returns all endpoints of a user
+func (s *WebhookEndpointService) List(ctx context.Context, userID uuid.UUID) ([]*entity.WebhookEndpoint, error) {
+	endpoints, err := s.endpointRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if endpoints == nil {
+		endpoints = []*entity.WebhookEndpoint{}
+	}
+	return endpoints, nil
+}
+
+// Get returns an endpoint owned by the user
+func (s *WebhookEndpointService) Get(ctx context.Context, userID, endpointID uuid.UUID) (*entity.WebhookEndpoint, error) {
+	endpoint, err := s.endpointRepo.GetByID(ctx, endpointID)
+	if err != nil {
+		return nil, ErrWebhookEndpointNotFound
+	}
+	if endpoint.UserID != userID {
+		return nil, ErrWebhookEndpointNotFound
+	}
+	return endpoint, nil
+}
+
+// Update changes an endpoint. Re-enabling an endpoint clears its failure streak.
+func (s *WebhookEndpointService) Update(ctx context.Context, userID, endpointID uuid.UUID, req UpdateWebhookEndpointRequest) (*entity.WebhookEndpoint, error) {
+	endpoint, err := s.Get(ctx, userID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	if req.URL != nil {
+		if err := s.validateURL(*req.URL); err != nil {
+			return nil, err
+		}
+		endpoint.URL = *req.URL
+	}
+	if req.Description != nil {
+		endpoint.Description = *req.Description
+	}
+	if req.Topics != nil {
+		topics, err := normalizeWebhookTopics(req.Topics)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.Topics = topics
+	}
+
+	disabled := false
+	if req.Enabled != nil && *req.Enabled != endpoint.Enabled {
+		if *req.Enabled {
+			endpoint.Enable(now)
+		} else {
+			endpoint.Disable(now, "disabled by owner")
+			disabled = true
+		}
+	}
+	endpoint.UpdatedAt = now
+
+	if err := s.endpointRepo.Update(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	if disabled {
+		if err := s.deliveryRepo.FailPendingByEndpointID(ctx, endpoint.ID, "endpoint disabled"); err != nil {
+			return nil, err
+		}
+	}
+
+	return endpoint, nil
+}
+
+// RotateSecret replaces the signing secret. The old secret stops working immediately.
+func (s *WebhookEndpointService) RotateSecret(ctx context.Context, userID, endpointID uuid.UUID) (*entity.WebhookEndpoint, error) {
+	endpoint, err := s.Get(ctx, userID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := entity.NewWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Secret = secret
+	if err := s.encryptSecret(endpoint); err != nil {
+		return nil, err
+	}
+	endpoint.UpdatedAt = time.Now().UTC()
+
+	if err := s.endpointRepo.Update(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+// Delete removes an endpoint and its delivery log
+func (s *WebhookEndpointService) Delete(ctx context.Context, userID, endpointID uuid.UUID) error {
+	if _, err := s.Get(ctx, userID, endpointID); err != nil {
+		return err
+	}
+	return s.endpointRepo.Delete(ctx, endpointID)
+}
+
+// ListDeliveries returns the most recent deliveries of an endpoint
+func (s *WebhookEndpointService) ListDeliveries(ctx context.Context, userID, endpointID uuid.UUID, limit int) ([]*entity.WebhookDelivery, error) {
+	if _, err := s.Get(ctx, userID, endpointID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DefaultWebhookDeliveryLimit
+	}
+	if limit > MaxWebhookDeliveryLimit {
+		limit = MaxWebhookDeliveryLimit
+	}
+
+	deliveries, err := s.deliveryRepo.GetByEndpointID(ctx, endpointID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		deliveries = []*entity.WebhookDelivery{}
+	}
+	return deliveries, nil
+}
+
+// Redeliver queues a new attempt of a past delivery with the same event ID and payload
+func (s *WebhookEndpointService) Redeliver(ctx context.Context, userID, endpointID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	endpoint, err := s.Get(ctx, userID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if !endpoint.Enabled {
+		return nil, ErrWebhookEndpointDisabled
+	}
+
+	original, err := s.deliveryRepo.GetByID(ctx, deliveryID)
+	if err != nil || original.EndpointID != endpointID {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	redelivery := original.Redeliver()
+	if err := s.deliveryRepo.Create(ctx, redelivery); err != nil {
+		return nil, err
+	}
+
+	return redelivery, nil
+}
+
+// encryptSecret sets the endpoint's encrypted secret from its plaintext one
+func (s *WebhookEndpointService) encryptSecret(endpoint *entity.WebhookEndpoint) error {
+	encrypted, err := s.encryptor.Encrypt([]byte(endpoint.Secret))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+	endpoint.EncryptedSecret = encrypted
+	return nil
+}
+
+// validateURL requires https to a public host; see netguard.Guard.ValidateURL
+func (s *WebhookEndpointService) validateURL(raw string) error {
+	if err := s.urlGuard.ValidateURL(raw); err != nil {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// normalizeWebhookTopics validates topics and removes duplicates
+func normalizeWebhookTopics(topics []string) ([]string, error) {
+	seen := make(map[string]bool, len(topics))
+	result := make([]string, 0, len(topics))
+
+	for _, topic := range topics {
+		if !entity.IsValidWebhookTopic(topic) {
+			return nil, ErrInvalidWebhookTopics
+		}
+		if !seen[topic] {
+			seen[topic] = true
+			result = append(result, topic)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, ErrInvalidWebhookTopics
+	}
+	return result, nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	domainEntity "github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	revrepo "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
+)
+
+// WebhookPublisher turns Revenue API events into pending deliveries for every
+// enabled endpoint of the app owner subscribed to the topic. Sending is left
+// to the WebhookDispatcher, so publishing never blocks on customer servers.
+type WebhookPublisher struct {
+	appRepo      repository.AppRepository
+	partnerRepo  repository.PartnerAccountRepository
+	endpointRepo revrepo.WebhookEndpointRepository
+	deliveryRepo revrepo.WebhookDeliveryRepository
+}
+
+// NewWebhookPublisher creates a new WebhookPublisher
+func NewWebhookPublisher(
+	appRepo repository.AppRepository,
+	partnerRepo repository.PartnerAccountRepository,
+	endpointRepo revrepo.WebhookEndpointRepository,
+	deliveryRepo revrepo.WebhookDeliveryRepository,
+) *WebhookPublisher {
+	return &WebhookPublisher{
+		appRepo:      appRepo,
+		partnerRepo:  partnerRepo,
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+	}
+}
+
+// Publish queues an event for the owner of the app. Returns the number of deliveries queued.
+func (p *WebhookPublisher) Publish(ctx context.Context, appID uuid.UUID, topic string, data interface{}) (int, error) {
+	app, err := p.appRepo.FindByID(ctx, appID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find app %s: %w", appID, err)
+	}
+
+	account, err := p.partnerRepo.FindByID(ctx, app.PartnerAccountID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find partner account for app %s: %w", appID, err)
+	}
+
+	endpoints, err := p.endpointRepo.GetEnabledByUserIDAndTopic(ctx, account.UserID, topic)
+	if err != nil {
+		return 0, err
+	}
+	if len(endpoints) == 0 {
+		return 0, nil
+	}
+
+	event := entity.OutboundWebhookEvent{
+		ID:        uuid.New(),
+		Type:      topic,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, endpoint := range endpoints {
+		delivery := entity.NewWebhookDelivery(endpoint.ID, event.ID, topic, payload)
+		if err := p.deliveryRepo.Create(ctx, delivery); err != nil {
+			return queued, fmt.Errorf("failed to queue delivery to endpoint %s: %w", endpoint.ID, err)
+		}
+		queued++
+	}
+
+	return queued, nil
+}
+
+// PublishSubscriptionEvent queues subscription.status_changed and/or
+// subscription.risk_changed for a recorded lifecycle event
+func (p *WebhookPublisher) PublishSubscriptionEvent(ctx context.Context, sub *domainEntity.Subscription, event *domainEntity.SubscriptionEvent) error {
+	data := entity.SubscriptionChangedData{
+		SubscriptionID:         sub.ShopifyGID,
+		MyshopifyDomain:        sub.MyshopifyDomain,
+		ShopName:               sub.ShopName,
+		PlanName:               sub.PlanName,
+		PreviousStatus:         event.FromStatus,
+		Status:                 event.ToStatus,
+		PreviousRiskState:      event.FromRiskState.String(),
+		RiskState:              event.ToRiskState.String(),
+		Reason:                 event.Reason,
+		Source:                 event.EventType,
+		OccurredAt:             event.OccurredAt,
+		ExpectedNextChargeDate: sub.ExpectedNextChargeDate,
+	}
+
+	if event.FromStatus != event.ToStatus {
+		if _, err := p.Publish(ctx, sub.AppID, entity.WebhookTopicSubscriptionStatusChanged, data); err != nil {
+			return err
+		}
+	}
+
+	if event.FromRiskState != event.ToRiskState {
+		if _, err := p.Publish(ctx, sub.AppID, entity.WebhookTopicSubscriptionRiskChanged, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PublishUsageBilled queues usage.billed for a usage charge Shopify has billed
+func (p *WebhookPublisher) PublishUsageBilled(ctx context.Context, appID uuid.UUID, usage *entity.UsageStatus) error {
+	_, err := p.Publish(ctx, appID, entity.WebhookTopicUsageBilled, entity.UsageBilledData{
+		UsageID:        usage.ShopifyGID,
+		SubscriptionID: usage.SubscriptionShopifyGID,
+		AmountCents:    usage.AmountCents,
+		BillingDate:    usage.BillingDate,
+		Description:    usage.Description,
+	})
+	return err
+}
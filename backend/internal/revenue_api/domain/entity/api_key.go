@@ -14,8 +14,44 @@ const (
 	APIKeyPrefix = "lgk_"
 	// APIKeyLength is the length of the raw key (32 bytes = 256 bits)
 	APIKeyLength = 32
+	// APIKeyExpiryWarning is how long before expiry a key is reported as expiring
+	APIKeyExpiryWarning = 14 * 24 * time.Hour
+	// APIKeyUnusedWarning is how long a key can go unused before it is reported as unused
+	APIKeyUnusedWarning = 90 * 24 * time.Hour
 )
 
+// API key warnings
+const (
+	APIKeyWarningExpiring = "expiring" // Expires within APIKeyExpiryWarning
+	APIKeyWarningRotating = "rotating" // Replaced by a successor, valid until the overlap ends
+	APIKeyWarningUnused   = "unused"   // Not used for APIKeyUnusedWarning
+)
+
+// API key scopes
+const (
+	ScopeSubscriptionsRead = "subscriptions:read" // Subscription status lookups, listings and entitlements
+	ScopeUsageRead         = "usage:read"         // Usage status lookups and reconciliation reports
+	ScopeUsageWrite        = "usage:write"        // Reporting created usage records for reconciliation
+	ScopeStream            = "stream"             // Real-time subscription change stream
+	ScopeWebhooksManage    = "webhooks:manage"    // Outbound webhook endpoint management
+)
+
+// APIKeyScopes lists all scopes a key can be granted
+var APIKeyScopes = []string{ScopeSubscriptionsRead, ScopeUsageRead, ScopeUsageWrite, ScopeStream, ScopeWebhooksManage}
+
+// DefaultAPIKeyScopes are granted when a key is created without scopes (the access keys had before scoping)
+var DefaultAPIKeyScopes = []string{ScopeSubscriptionsRead, ScopeUsageRead, ScopeStream}
+
+// IsValidAPIKeyScope returns true if scope is a known scope
+func IsValidAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 // APIKey represents an API key for Revenue API authentication
 type APIKey struct {
 	ID                 uuid.UUID
@@ -23,7 +59,12 @@ type APIKey struct {
 	KeyHash            string // SHA-256 hash of raw key
 	Name               string
 	RateLimitPerMinute int
+	Scopes             []string
+	AppIDs             []uuid.UUID // Apps the key may access; empty = all of the user's apps
 	CreatedAt          time.Time
+	ExpiresAt          *time.Time // Nil = never expires
+	LastUsedAt         *time.Time
+	ReplacedByID       *uuid.UUID // Successor issued by a rotation
 	RevokedAt          *time.Time
 }
 
@@ -62,6 +103,7 @@ func NewAPIKey(userID uuid.UUID, name string, rateLimitPerMinute int) (*APIKeyWi
 			KeyHash:            keyHash,
 			Name:               name,
 			RateLimitPerMinute: rateLimitPerMinute,
+			Scopes:             append([]string(nil), DefaultAPIKeyScopes...),
 			CreatedAt:          now,
 			RevokedAt:          nil,
 		},
@@ -80,9 +122,43 @@ func (k *APIKey) IsRevoked() bool {
 	return k.RevokedAt != nil
 }
 
-// IsActive returns true if the key is active (not revoked)
+// IsExpired returns true if the key's expiry has passed
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// IsActive returns true if the key is active (not revoked or expired)
 func (k *APIKey) IsActive() bool {
-	return k.RevokedAt == nil
+	return k.RevokedAt == nil && !k.IsExpired(time.Now().UTC())
+}
+
+// IsRotated returns true if a successor key has been issued for the key
+func (k *APIKey) IsRotated() bool {
+	return k.ReplacedByID != nil
+}
+
+// Warnings returns the key's warnings at now: expiring, rotating, unused
+func (k *APIKey) Warnings(now time.Time) []string {
+	warnings := []string{}
+	if k.IsRevoked() || k.IsExpired(now) {
+		return warnings
+	}
+
+	if k.IsRotated() {
+		warnings = append(warnings, APIKeyWarningRotating)
+	} else if k.ExpiresAt != nil && k.ExpiresAt.Sub(now) <= APIKeyExpiryWarning {
+		warnings = append(warnings, APIKeyWarningExpiring)
+	}
+
+	lastActivity := k.CreatedAt
+	if k.LastUsedAt != nil {
+		lastActivity = *k.LastUsedAt
+	}
+	if now.Sub(lastActivity) >= APIKeyUnusedWarning {
+		warnings = append(warnings, APIKeyWarningUnused)
+	}
+
+	return warnings
 }
 
 // Revoke marks the key as revoked
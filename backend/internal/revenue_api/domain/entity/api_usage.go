@@ -0,0 +1,241 @@
+package entity
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIUsageLatencyBuckets are the upper bounds (ms) of the latency histogram buckets.
+// A final overflow bucket counts slower requests.
+var APIUsageLatencyBuckets = []int{10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// LatencyHistogram counts requests per latency bucket; len = len(APIUsageLatencyBuckets)+1
+type LatencyHistogram []int64
+
+// NewLatencyHistogram creates an empty histogram
+func NewLatencyHistogram() LatencyHistogram {
+	return make(LatencyHistogram, len(APIUsageLatencyBuckets)+1)
+}
+
+// Merge adds other's counts into h
+func (h LatencyHistogram) Merge(other LatencyHistogram) {
+	for i := range h {
+		if i < len(other) {
+			h[i] += other[i]
+		}
+	}
+}
+
+// Percentile estimates the p-th percentile (0-100) in ms by interpolating within
+// the bucket it falls in. The overflow bucket reports the last bound.
+func (h LatencyHistogram) Percentile(p float64) int {
+	var total int64
+	for _, c := range h {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(total)
+	var cumulative int64
+	for i, c := range h {
+		if c == 0 {
+			continue
+		}
+		if float64(cumulative+c) >= rank {
+			if i >= len(APIUsageLatencyBuckets) {
+				return APIUsageLatencyBuckets[len(APIUsageLatencyBuckets)-1]
+			}
+			lower := 0
+			if i > 0 {
+				lower = APIUsageLatencyBuckets[i-1]
+			}
+			upper := APIUsageLatencyBuckets[i]
+			fraction := (rank - float64(cumulative)) / float64(c)
+			return lower + int(fraction*float64(upper-lower)+0.5)
+		}
+		cumulative += c
+	}
+	return APIUsageLatencyBuckets[len(APIUsageLatencyBuckets)-1]
+}
+
+// APIUsageRollup is an hour of requests for one API key, endpoint and method
+type APIUsageRollup struct {
+	APIKeyID         uuid.UUID
+	Hour             time.Time
+	Endpoint         string // Route pattern, or the path for requests that didn't match a route
+	Method           string
+	RequestCount     int64
+	ErrorCount       int64 // status >= 400
+	ServerErrorCount int64 // status >= 500
+	RateLimitedCount int64 // status 429
+	TotalResponseMs  int64
+	LatencyBuckets   LatencyHistogram
+}
+
+// APICallerRollup is an hour of requests for one API key from one client IP
+type APICallerRollup struct {
+	APIKeyID     uuid.UUID
+	Hour         time.Time
+	IPAddress    string
+	RequestCount int64
+	ErrorCount   int64
+}
+
+// APIUsageStats are aggregated request counts and latencies
+type APIUsageStats struct {
+	Requests         int64   `json:"requests"`
+	Errors           int64   `json:"errors"`
+	ServerErrors     int64   `json:"server_errors"`
+	RateLimited      int64   `json:"rate_limited"`
+	ErrorRate        float64 `json:"error_rate"` // errors / requests
+	AvgResponseMs    int     `json:"avg_response_ms"`
+	P50ResponseMs    int     `json:"p50_response_ms"`
+	P95ResponseMs    int     `json:"p95_response_ms"`
+	totalResponseMs  int64
+	latencyHistogram LatencyHistogram
+}
+
+// Add accumulates a rollup into the stats
+func (s *APIUsageStats) Add(r *APIUsageRollup) {
+	if s.latencyHistogram == nil {
+		s.latencyHistogram = NewLatencyHistogram()
+	}
+	s.Requests += r.RequestCount
+	s.Errors += r.ErrorCount
+	s.ServerErrors += r.ServerErrorCount
+	s.RateLimited += r.RateLimitedCount
+	s.totalResponseMs += r.TotalResponseMs
+	s.latencyHistogram.Merge(r.LatencyBuckets)
+}
+
+// Finalize computes the derived rates and percentiles
+func (s *APIUsageStats) Finalize() {
+	if s.Requests == 0 {
+		return
+	}
+	s.ErrorRate = float64(s.Errors) / float64(s.Requests)
+	s.AvgResponseMs = int(s.totalResponseMs / s.Requests)
+	s.P50ResponseMs = s.latencyHistogram.Percentile(50)
+	s.P95ResponseMs = s.latencyHistogram.Percentile(95)
+}
+
+// APIEndpointUsage is the usage of one endpoint
+type APIEndpointUsage struct {
+	Endpoint string `json:"endpoint"`
+	Method   string `json:"method"`
+	APIUsageStats
+}
+
+// APIHourlyUsage is the usage in one hour
+type APIHourlyUsage struct {
+	Hour time.Time `json:"hour"`
+	APIUsageStats
+}
+
+// APICallerUsage is the usage from one client IP
+type APICallerUsage struct {
+	IPAddress string `json:"ip_address"`
+	Requests  int64  `json:"requests"`
+	Errors    int64  `json:"errors"`
+}
+
+// APIKeyQuota reports how close a key runs to its rate limit
+type APIKeyQuota struct {
+	RateLimitPerMinute int   `json:"rate_limit_per_minute"`
+	PeakHourRequests   int64 `json:"peak_hour_requests"`
+	RateLimited        int64 `json:"rate_limited"` // Requests rejected with 429
+}
+
+// APIKeyUsageReport is the usage of an API key over a time range
+type APIKeyUsageReport struct {
+	APIKeyID   uuid.UUID          `json:"api_key_id"`
+	From       time.Time          `json:"from"`
+	To         time.Time          `json:"to"`
+	Totals     APIUsageStats      `json:"totals"`
+	Quota      APIKeyQuota        `json:"quota"`
+	Endpoints  []APIEndpointUsage `json:"endpoints"`   // Most requested first
+	Hourly     []APIHourlyUsage   `json:"hourly"`      // Hours with requests, oldest first
+	TopCallers []APICallerUsage   `json:"top_callers"` // Most requests first
+}
+
+// NewAPIKeyUsageReport aggregates rollups into a report
+func NewAPIKeyUsageReport(key *APIKey, from, to time.Time, rollups []*APIUsageRollup, callers []*APICallerRollup, topCallers int) *APIKeyUsageReport {
+	report := &APIKeyUsageReport{
+		APIKeyID:   key.ID,
+		From:       from,
+		To:         to,
+		Quota:      APIKeyQuota{RateLimitPerMinute: key.RateLimitPerMinute},
+		Endpoints:  []APIEndpointUsage{},
+		Hourly:     []APIHourlyUsage{},
+		TopCallers: []APICallerUsage{},
+	}
+
+	endpoints := make(map[[2]string]*APIEndpointUsage)
+	hours := make(map[time.Time]*APIHourlyUsage)
+	for _, r := range rollups {
+		report.Totals.Add(r)
+
+		ek := [2]string{r.Endpoint, r.Method}
+		if endpoints[ek] == nil {
+			endpoints[ek] = &APIEndpointUsage{Endpoint: r.Endpoint, Method: r.Method}
+		}
+		endpoints[ek].Add(r)
+
+		if hours[r.Hour] == nil {
+			hours[r.Hour] = &APIHourlyUsage{Hour: r.Hour}
+		}
+		hours[r.Hour].Add(r)
+	}
+
+	report.Totals.Finalize()
+	report.Quota.RateLimited = report.Totals.RateLimited
+
+	for _, e := range endpoints {
+		e.Finalize()
+		report.Endpoints = append(report.Endpoints, *e)
+	}
+	sort.Slice(report.Endpoints, func(i, j int) bool {
+		if report.Endpoints[i].Requests != report.Endpoints[j].Requests {
+			return report.Endpoints[i].Requests > report.Endpoints[j].Requests
+		}
+		return report.Endpoints[i].Endpoint+report.Endpoints[i].Method < report.Endpoints[j].Endpoint+report.Endpoints[j].Method
+	})
+
+	for _, h := range hours {
+		h.Finalize()
+		if h.Requests > report.Quota.PeakHourRequests {
+			report.Quota.PeakHourRequests = h.Requests
+		}
+		report.Hourly = append(report.Hourly, *h)
+	}
+	sort.Slice(report.Hourly, func(i, j int) bool {
+		return report.Hourly[i].Hour.Before(report.Hourly[j].Hour)
+	})
+
+	byIP := make(map[string]*APICallerUsage)
+	for _, c := range callers {
+		if byIP[c.IPAddress] == nil {
+			byIP[c.IPAddress] = &APICallerUsage{IPAddress: c.IPAddress}
+		}
+		byIP[c.IPAddress].Requests += c.RequestCount
+		byIP[c.IPAddress].Errors += c.ErrorCount
+	}
+	for _, c := range byIP {
+		report.TopCallers = append(report.TopCallers, *c)
+	}
+	sort.Slice(report.TopCallers, func(i, j int) bool {
+		if report.TopCallers[i].Requests != report.TopCallers[j].Requests {
+			return report.TopCallers[i].Requests > report.TopCallers[j].Requests
+		}
+		return report.TopCallers[i].IPAddress < report.TopCallers[j].IPAddress
+	})
+	if len(report.TopCallers) > topCallers {
+		report.TopCallers = report.TopCallers[:topCallers]
+	}
+
+	return report
+}
@@ -12,6 +12,7 @@ type AuditLog struct {
 	ID             uuid.UUID
 	APIKeyID       uuid.UUID
 	Endpoint       string
+	Route          string // Matched route pattern, e.g. /v1/subscriptions/{shopify_gid}
 	Method         string
 	RequestParams  map[string]interface{}
 	ResponseStatus int
@@ -0,0 +1,247 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+)
+
+// EntitlementDecision is the access level an app should grant a store
+type EntitlementDecision string
+
+const (
+	EntitlementAllow    EntitlementDecision = "ALLOW"    // Full access
+	EntitlementWarn     EntitlementDecision = "WARN"     // Full access with a payment warning
+	EntitlementRestrict EntitlementDecision = "RESTRICT" // Limited access (e.g., read-only, free tier features)
+	EntitlementBlock    EntitlementDecision = "BLOCK"    // No access
+)
+
+// MaxEntitlementRules caps the number of rules in a policy
+const MaxEntitlementRules = 50
+
+var (
+	ErrInvalidEntitlementDecision = errors.New("decision must be one of ALLOW, WARN, RESTRICT, BLOCK")
+	ErrInvalidEntitlementRule     = errors.New("each rule needs a reason and valid conditions")
+	ErrTooManyEntitlementRules    = errors.New("too many entitlement rules")
+)
+
+// IsValid returns true for a known decision
+func (d EntitlementDecision) IsValid() bool {
+	switch d {
+	case EntitlementAllow, EntitlementWarn, EntitlementRestrict, EntitlementBlock:
+		return true
+	}
+	return false
+}
+
+// EntitlementRule maps matching subscriptions to a decision. Every condition
+// that is set must match; a rule without conditions matches everything.
+type EntitlementRule struct {
+	Name     string              `json:"name,omitempty"`
+	Decision EntitlementDecision `json:"decision"`
+	Reason   string              `json:"reason"` // Machine-readable code returned to the client, e.g. payment_overdue
+
+	// Conditions
+	RiskStates         []string `json:"risk_states,omitempty"`
+	Statuses           []string `json:"statuses,omitempty"`
+	MinMonthsOverdue   *int     `json:"min_months_overdue,omitempty"`
+	MaxMonthsOverdue   *int     `json:"max_months_overdue,omitempty"`
+	IsPaidCurrentCycle *bool    `json:"is_paid_current_cycle,omitempty"`
+
+	// Grace period counted from the missed charge date (expected_next_charge_date).
+	// Once it has passed, AfterGraceDecision applies instead of Decision.
+	GraceDays          int                 `json:"grace_days,omitempty"`
+	AfterGraceDecision EntitlementDecision `json:"after_grace_decision,omitempty"`
+}
+
+// Matches returns true if the subscription satisfies every condition of the rule
+func (r *EntitlementRule) Matches(status *SubscriptionStatus) bool {
+	if len(r.RiskStates) > 0 && !containsString(r.RiskStates, status.RiskState.String()) {
+		return false
+	}
+	if len(r.Statuses) > 0 && !containsString(r.Statuses, status.Status) {
+		return false
+	}
+	if r.MinMonthsOverdue != nil && status.MonthsOverdue < *r.MinMonthsOverdue {
+		return false
+	}
+	if r.MaxMonthsOverdue != nil && status.MonthsOverdue > *r.MaxMonthsOverdue {
+		return false
+	}
+	if r.IsPaidCurrentCycle != nil && status.IsPaidCurrentCycle != *r.IsPaidCurrentCycle {
+		return false
+	}
+	return true
+}
+
+// Validate normalizes and checks the rule
+func (r *EntitlementRule) Validate() error {
+	r.Decision = EntitlementDecision(strings.ToUpper(string(r.Decision)))
+	if !r.Decision.IsValid() {
+		return ErrInvalidEntitlementDecision
+	}
+	if r.AfterGraceDecision != "" {
+		r.AfterGraceDecision = EntitlementDecision(strings.ToUpper(string(r.AfterGraceDecision)))
+		if !r.AfterGraceDecision.IsValid() {
+			return ErrInvalidEntitlementDecision
+		}
+	}
+
+	r.Reason = strings.TrimSpace(r.Reason)
+	if r.Reason == "" || r.GraceDays < 0 {
+		return ErrInvalidEntitlementRule
+	}
+	if r.AfterGraceDecision != "" && r.GraceDays == 0 {
+		return ErrInvalidEntitlementRule
+	}
+	if r.MinMonthsOverdue != nil && r.MaxMonthsOverdue != nil && *r.MinMonthsOverdue > *r.MaxMonthsOverdue {
+		return ErrInvalidEntitlementRule
+	}
+
+	for i, state := range r.RiskStates {
+		r.RiskStates[i] = strings.ToUpper(state)
+		if !valueobject.RiskState(r.RiskStates[i]).IsValid() {
+			return ErrInvalidEntitlementRule
+		}
+	}
+	for i, status := range r.Statuses {
+		r.Statuses[i] = strings.ToUpper(status)
+	}
+
+	return nil
+}
+
+// EntitlementPolicy is an app's ordered list of entitlement rules. The first
+// matching rule decides; if none matches, DefaultDecision applies.
+type EntitlementPolicy struct {
+	ID              uuid.UUID
+	AppID           uuid.UUID
+	Rules           []EntitlementRule
+	DefaultDecision EntitlementDecision
+	DefaultReason   string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// NewEntitlementPolicy creates a policy for an app
+func NewEntitlementPolicy(appID uuid.UUID, rules []EntitlementRule, defaultDecision EntitlementDecision, defaultReason string) *EntitlementPolicy {
+	now := time.Now().UTC()
+	return &EntitlementPolicy{
+		ID:              uuid.New(),
+		AppID:           appID,
+		Rules:           rules,
+		DefaultDecision: defaultDecision,
+		DefaultReason:   defaultReason,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// DefaultEntitlementPolicy is used for apps without a saved policy. It mirrors the
+// gating recommended in the API docs: warn on one missed cycle for 7 days, then
+// restrict; restrict on two missed cycles; block churned stores.
+func DefaultEntitlementPolicy(appID uuid.UUID) *EntitlementPolicy {
+	policy := NewEntitlementPolicy(appID, []EntitlementRule{
+		{
+			Name:       "Churned",
+			Decision:   EntitlementBlock,
+			Reason:     "subscription_churned",
+			RiskStates: []string{valueobject.RiskStateChurned.String()},
+		},
+		{
+			Name:       "Two cycles missed",
+			Decision:   EntitlementRestrict,
+			Reason:     "payment_overdue",
+			RiskStates: []string{valueobject.RiskStateTwoCyclesMissed.String()},
+		},
+		{
+			Name:               "One cycle missed",
+			Decision:           EntitlementWarn,
+			Reason:             "payment_overdue",
+			RiskStates:         []string{valueobject.RiskStateOneCycleMissed.String()},
+			GraceDays:          7,
+			AfterGraceDecision: EntitlementRestrict,
+		},
+	}, EntitlementAllow, "in_good_standing")
+	policy.ID = uuid.Nil // Not persisted
+	return policy
+}
+
+// IsDefault returns true if the policy is the built-in default rather than a saved one
+func (p *EntitlementPolicy) IsDefault() bool {
+	return p.ID == uuid.Nil
+}
+
+// Validate normalizes and checks the policy
+func (p *EntitlementPolicy) Validate() error {
+	if len(p.Rules) > MaxEntitlementRules {
+		return ErrTooManyEntitlementRules
+	}
+	if p.DefaultDecision == "" {
+		p.DefaultDecision = EntitlementAllow
+	}
+	p.DefaultDecision = EntitlementDecision(strings.ToUpper(string(p.DefaultDecision)))
+	if !p.DefaultDecision.IsValid() {
+		return ErrInvalidEntitlementDecision
+	}
+	if strings.TrimSpace(p.DefaultReason) == "" {
+		p.DefaultReason = "in_good_standing"
+	}
+
+	for i := range p.Rules {
+		if err := p.Rules[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Evaluate decides the entitlement of a subscription at the given time
+func (p *EntitlementPolicy) Evaluate(status *SubscriptionStatus, now time.Time) *Entitlement {
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if !rule.Matches(status) {
+			continue
+		}
+
+		entitlement := &Entitlement{
+			Decision: rule.Decision,
+			Reason:   rule.Reason,
+			RuleName: rule.Name,
+		}
+		if rule.GraceDays > 0 && status.ExpectedNextChargeDate != nil {
+			deadline := status.ExpectedNextChargeDate.AddDate(0, 0, rule.GraceDays)
+			if rule.AfterGraceDecision != "" && !now.Before(deadline) {
+				entitlement.Decision = rule.AfterGraceDecision
+			} else {
+				entitlement.GraceDeadline = &deadline
+			}
+		}
+		return entitlement
+	}
+
+	return &Entitlement{
+		Decision: p.DefaultDecision,
+		Reason:   p.DefaultReason,
+	}
+}
+
+// Entitlement is the access decision for one subscription
+type Entitlement struct {
+	Decision      EntitlementDecision
+	Reason        string
+	RuleName      string     // Empty if the default decision applied
+	GraceDeadline *time.Time // Set while a grace period is running
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectionCheckpoint records how far an app's ledger has been projected into the read model
+type ProjectionCheckpoint struct {
+	AppID                uuid.UUID
+	SubscriptionsThrough time.Time  // Latest subscription UpdatedAt projected
+	TransactionsThrough  time.Time  // Latest transaction CreatedAt projected
+	ProjectedAt          time.Time  // When the last projection ran
+	RebuiltAt            *time.Time // When the read model was last rebuilt from scratch
+	CheckedAt            *time.Time // When the last consistency check ran
+	DriftCount           int        // Rows the last consistency check found out of sync
+}
+
+// NewProjectionCheckpoint creates the checkpoint of a full rebuild at now
+func NewProjectionCheckpoint(appID uuid.UUID, now time.Time) *ProjectionCheckpoint {
+	return &ProjectionCheckpoint{
+		AppID:                appID,
+		SubscriptionsThrough: now,
+		TransactionsThrough:  now,
+		ProjectedAt:          now,
+		RebuiltAt:            &now,
+	}
+}
+
+// Read model kinds compared by consistency checks
+const (
+	ReadModelSubscription = "subscription"
+	ReadModelUsage        = "usage"
+)
+
+// Problems a consistency check can find with a read model row
+const (
+	DriftMissing  = "missing"  // The ledger has a row the read model lacks
+	DriftStale    = "stale"    // The read model row differs from its projection
+	DriftOrphaned = "orphaned" // The read model row has no ledger row (or was superseded)
+)
+
+// ReadModelDrift is one read model row found out of sync with the ledger
+type ReadModelDrift struct {
+	Kind       string `json:"kind"`
+	ShopifyGID string `json:"shopify_gid"`
+	Problem    string `json:"problem"`
+}
+
+// ReadModelConsistencyReport is the result of comparing an app's read model with its ledger
+type ReadModelConsistencyReport struct {
+	AppID         uuid.UUID        `json:"app_id"`
+	CheckedAt     time.Time        `json:"checked_at"`
+	Subscriptions int              `json:"subscriptions"` // Ledger subscriptions compared
+	UsageCharges  int              `json:"usage_charges"` // Ledger usage transactions compared
+	Drift         []ReadModelDrift `json:"drift"`
+	Repaired      bool             `json:"repaired"`
+}
+
+// AddDrift records an out-of-sync row
+func (r *ReadModelConsistencyReport) AddDrift(kind, shopifyGID, problem string) {
+	r.Drift = append(r.Drift, ReadModelDrift{Kind: kind, ShopifyGID: shopifyGID, Problem: problem})
+}
+
+// Consistent returns true if no drift was found
+func (r *ReadModelConsistencyReport) Consistent() bool {
+	return len(r.Drift) == 0
+}
@@ -0,0 +1,80 @@
+package entity
+
+import "time"
+
+// RateLimitDecision is the outcome of a rate limit check
+type RateLimitDecision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration // Until the full quota is available again
+	RetryAfter time.Duration // Until the next request would be allowed; zero when allowed
+}
+
+// GCRA applies the generic cell rate algorithm: limit requests per period, spread
+// evenly, with bursts of up to limit. tat is the stored theoretical arrival time
+// (zero for a new key). It returns the TAT to store and the decision; a denied
+// request leaves the TAT unchanged. Unlike fixed windows, bursts at a window edge
+// can't double the limit.
+func GCRA(tat, now time.Time, limit int, period time.Duration) (time.Time, RateLimitDecision) {
+	if limit < 1 {
+		limit = 1
+	}
+	interval := period / time.Duration(limit)
+
+	if tat.Before(now) {
+		tat = now
+	}
+	newTAT := tat.Add(interval)
+	if newTAT.Add(-period).After(now) {
+		return tat, NewRateLimitDecision(false, tat, now, limit, period)
+	}
+	return newTAT, NewRateLimitDecision(true, newTAT, now, limit, period)
+}
+
+// GCRARefund gives back the request an allowed GCRA check consumed, for a request
+// that another quota then rejected. It returns the TAT to store; the quota never
+// refills past full.
+func GCRARefund(tat, now time.Time, limit int, period time.Duration) time.Time {
+	if limit < 1 {
+		limit = 1
+	}
+	tat = tat.Add(-(period / time.Duration(limit)))
+	if tat.Before(now) {
+		return now
+	}
+	return tat
+}
+
+// NewRateLimitDecision describes a quota from the TAT stored after a check. Stores
+// that run GCRA elsewhere (a SQL statement, a Redis script) use it to build the decision.
+func NewRateLimitDecision(allowed bool, tat, now time.Time, limit int, period time.Duration) RateLimitDecision {
+	if limit < 1 {
+		limit = 1
+	}
+	interval := period / time.Duration(limit)
+
+	resetAfter := tat.Sub(now)
+	if resetAfter < 0 {
+		resetAfter = 0
+	}
+	remaining := int((period - resetAfter) / interval)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	decision := RateLimitDecision{
+		Allowed:    allowed,
+		Limit:      limit,
+		Remaining:  remaining,
+		ResetAfter: resetAfter,
+	}
+	if !allowed {
+		decision.Remaining = 0
+		decision.RetryAfter = tat.Add(interval).Add(-period).Sub(now)
+		if decision.RetryAfter < 0 {
+			decision.RetryAfter = 0
+		}
+	}
+	return decision
+}
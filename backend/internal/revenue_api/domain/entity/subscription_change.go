@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionChange is a status and/or risk state change of a subscription,
+// streamed to Revenue API clients. It is serialized as-is between replicas.
+type SubscriptionChange struct {
+	ID                string    `json:"id"` // Unique across replicas; used as the SSE event ID
+	AppID             uuid.UUID `json:"app_id"`
+	SubscriptionID    string    `json:"subscription_id"` // Shopify GID
+	MyshopifyDomain   string    `json:"myshopify_domain"`
+	PreviousStatus    string    `json:"previous_status"`
+	Status            string    `json:"status"`
+	PreviousRiskState string    `json:"previous_risk_state"`
+	RiskState         string    `json:"risk_state"`
+	Source            string    `json:"source"` // webhook, billing_failure, app_uninstalled, ledger_sync
+	OccurredAt        time.Time `json:"occurred_at"`
+}
+
+// NewSubscriptionChange creates a change with a new ID
+func NewSubscriptionChange(
+	appID uuid.UUID,
+	subscriptionID string,
+	myshopifyDomain string,
+	previousStatus, status string,
+	previousRiskState, riskState string,
+	source string,
+	occurredAt time.Time,
+) *SubscriptionChange {
+	return &SubscriptionChange{
+		ID:                uuid.NewString(),
+		AppID:             appID,
+		SubscriptionID:    subscriptionID,
+		MyshopifyDomain:   myshopifyDomain,
+		PreviousStatus:    previousStatus,
+		Status:            status,
+		PreviousRiskState: previousRiskState,
+		RiskState:         riskState,
+		Source:            source,
+		OccurredAt:        occurredAt,
+	}
+}
+
+// StatusChanged returns true if the subscription status changed
+func (c *SubscriptionChange) StatusChanged() bool {
+	return c.PreviousStatus != c.Status
+}
+
+// RiskStateChanged returns true if the risk state changed
+func (c *SubscriptionChange) RiskStateChanged() bool {
+	return c.PreviousRiskState != c.RiskState
+}
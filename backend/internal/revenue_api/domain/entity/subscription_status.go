@@ -7,7 +7,16 @@ import (
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
 )
 
-// SubscriptionStatus represents the payment status of a subscription (CQRS read model)
+// PaymentGracePeriod is how long after the expected charge date a cycle still counts as paid,
+// matching the grace period of the ledger's risk classification
+const PaymentGracePeriod = 30 * 24 * time.Hour
+
+// overdueMonth is the length of a month when counting months overdue
+const overdueMonth = 30 * 24 * time.Hour
+
+// SubscriptionStatus represents the payment status of a subscription (CQRS read model).
+// IsPaidCurrentCycle and MonthsOverdue depend on the time and are derived when the
+// status is read rather than stored.
 type SubscriptionStatus struct {
 	ID                       uuid.UUID
 	ShopifyGID               string // e.g., gid://shopify/AppSubscription/123
@@ -38,16 +47,7 @@ func NewSubscriptionStatus(
 ) *SubscriptionStatus {
 	now := time.Now().UTC()
 
-	// Calculate months overdue
-	monthsOverdue := 0
-	if expectedNextChargeDate != nil && now.After(*expectedNextChargeDate) {
-		monthsOverdue = int(now.Sub(*expectedNextChargeDate).Hours() / (24 * 30))
-	}
-
-	// Determine if paid current cycle
-	isPaidCurrentCycle := status == "ACTIVE" && riskState == valueobject.RiskStateSafe
-
-	return &SubscriptionStatus{
+	s := &SubscriptionStatus{
 		ID:                       uuid.New(),
 		ShopifyGID:               shopifyGID,
 		AppID:                    appID,
@@ -55,13 +55,47 @@ func NewSubscriptionStatus(
 		ShopName:                 shopName,
 		PlanName:                 planName,
 		RiskState:                riskState,
-		IsPaidCurrentCycle:       isPaidCurrentCycle,
-		MonthsOverdue:            monthsOverdue,
 		LastSuccessfulChargeDate: lastRecurringChargeDate,
 		ExpectedNextChargeDate:   expectedNextChargeDate,
 		Status:                   status,
 		LastSyncedAt:             now,
 	}
+	s.Derive(now)
+	return s
+}
+
+// Derive computes the time-dependent fields as of now. The read model repository
+// derives the same values in SQL so it can filter and sort by them.
+func (s *SubscriptionStatus) Derive(now time.Time) {
+	s.MonthsOverdue = 0
+	if s.ExpectedNextChargeDate != nil && now.After(*s.ExpectedNextChargeDate) {
+		s.MonthsOverdue = int(now.Sub(*s.ExpectedNextChargeDate) / overdueMonth)
+	}
+
+	s.IsPaidCurrentCycle = s.Status == "ACTIVE" && s.RiskState == valueobject.RiskStateSafe &&
+		(s.ExpectedNextChargeDate == nil || !now.After(s.ExpectedNextChargeDate.Add(PaymentGracePeriod)))
+}
+
+// SameProjection returns true if other projects the same subscription state, ignoring
+// identity, sync time and derived fields
+func (s *SubscriptionStatus) SameProjection(other *SubscriptionStatus) bool {
+	return s.ShopifyGID == other.ShopifyGID &&
+		s.AppID == other.AppID &&
+		s.MyshopifyDomain == other.MyshopifyDomain &&
+		s.ShopName == other.ShopName &&
+		s.PlanName == other.PlanName &&
+		s.RiskState == other.RiskState &&
+		s.Status == other.Status &&
+		sameTime(s.LastSuccessfulChargeDate, other.LastSuccessfulChargeDate) &&
+		sameTime(s.ExpectedNextChargeDate, other.ExpectedNextChargeDate)
+}
+
+// sameTime compares optional times by instant
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
 }
 
 // SubscriptionStatusResponse is the API response format
@@ -99,3 +133,11 @@ type SubscriptionStatusBatchResponse struct {
 	Results  []SubscriptionStatusResponse `json:"results"`
 	NotFound []string                     `json:"not_found"`
 }
+
+// SubscriptionStatusListResponse is one page of the list API response format
+type SubscriptionStatusListResponse struct {
+	Data       []SubscriptionStatusResponse `json:"data"`
+	NextCursor string                       `json:"next_cursor,omitempty"` // Pass as after to get the next page
+	HasMore    bool                         `json:"has_more"`
+	TotalCount int                          `json:"total_count"`
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UsageReconciliationSummary counts reported records by state and totals their amounts
+type UsageReconciliationSummary struct {
+	Reported       int   `json:"reported"`
+	Pending        int   `json:"pending"`
+	Billed         int   `json:"billed"`
+	Overdue        int   `json:"overdue"`
+	AmountMismatch int   `json:"amount_mismatch"`
+	ReportedCents  int64 `json:"reported_cents"` // Sum of reported amounts
+	BilledCents    int64 `json:"billed_cents"`   // Sum of billed (gross) amounts
+	UnbilledCents  int64 `json:"unbilled_cents"` // Reported amounts not billed yet
+}
+
+// Add counts a record in the given state
+func (s *UsageReconciliationSummary) Add(u *UsageStatus, state UsageReconciliationState) {
+	s.Reported++
+	s.ReportedCents += int64(u.AmountCents)
+	switch state {
+	case UsageStatePending:
+		s.Pending++
+	case UsageStateBilled:
+		s.Billed++
+	case UsageStateOverdue:
+		s.Overdue++
+	case UsageStateAmountMismatch:
+		s.AmountMismatch++
+	}
+	if u.Billed && u.BilledAmountCents != nil {
+		s.BilledCents += int64(*u.BilledAmountCents)
+	} else if !u.Billed {
+		s.UnbilledCents += int64(u.AmountCents)
+	}
+}
+
+// UsageReconciliationReport compares the usage records an app reported over a
+// range with what Shopify billed
+type UsageReconciliationReport struct {
+	AppID             uuid.UUID                  `json:"app_id"`
+	From              time.Time                  `json:"from"`
+	To                time.Time                  `json:"to"`
+	OverdueAfterHours int                        `json:"overdue_after_hours"`
+	Summary           UsageReconciliationSummary `json:"summary"`
+	Records           []UsageStatusResponse      `json:"records"` // Oldest first, filtered by state
+}
+
+// NewUsageReconciliationReport reconciles records at now. The summary covers all
+// records; only those in one of states (all when empty) are listed.
+func NewUsageReconciliationReport(appID uuid.UUID, from, to time.Time, records []*UsageStatus, now time.Time, overdueAfter time.Duration, states []UsageReconciliationState) *UsageReconciliationReport {
+	report := &UsageReconciliationReport{
+		AppID:             appID,
+		From:              from,
+		To:                to,
+		OverdueAfterHours: int(overdueAfter / time.Hour),
+		Records:           []UsageStatusResponse{},
+	}
+
+	listed := make(map[UsageReconciliationState]bool, len(states))
+	for _, state := range states {
+		listed[state] = true
+	}
+
+	for _, u := range records {
+		if !u.IsReported() {
+			continue
+		}
+		state := u.ReconciliationState(now, overdueAfter)
+		report.Summary.Add(u, state)
+		if len(listed) == 0 || listed[state] {
+			report.Records = append(report.Records, u.ToReconciledResponse(now, overdueAfter))
+		}
+	}
+
+	return report
+}
+
+// UsageReportRejection is a reported record that wasn't accepted
+type UsageReportRejection struct {
+	UsageID string `json:"usage_id"`
+	Error   string `json:"error"`
+}
+
+// UsageReportResponse is the result of reporting a batch of usage records
+type UsageReportResponse struct {
+	Accepted []UsageStatusResponse  `json:"accepted"`
+	Rejected []UsageReportRejection `json:"rejected"`
+}
@@ -6,17 +6,49 @@ import (
 	"github.com/google/uuid"
 )
 
+// Usage status sources
+const (
+	UsageSourceLedger   = "ledger"   // Derived from a Partner usage transaction
+	UsageSourceReported = "reported" // Reported by the app when it created the usage record
+)
+
+// UsageReconciliationState is how a reported usage record compares to what Shopify billed
+type UsageReconciliationState string
+
+const (
+	UsageStatePending        UsageReconciliationState = "PENDING"         // Not billed yet, within the threshold
+	UsageStateBilled         UsageReconciliationState = "BILLED"          // Billed for the reported amount
+	UsageStateOverdue        UsageReconciliationState = "OVERDUE"         // Still unbilled past the threshold
+	UsageStateAmountMismatch UsageReconciliationState = "AMOUNT_MISMATCH" // Billed for a different amount
+)
+
+// IsValidUsageReconciliationState returns true if state is a known state
+func IsValidUsageReconciliationState(state string) bool {
+	switch UsageReconciliationState(state) {
+	case UsageStatePending, UsageStateBilled, UsageStateOverdue, UsageStateAmountMismatch:
+		return true
+	}
+	return false
+}
+
 // UsageStatus represents the billing status of a usage record (CQRS read model)
 type UsageStatus struct {
 	ID                    uuid.UUID
 	ShopifyGID            string // e.g., gid://shopify/AppUsageRecord/456
 	SubscriptionShopifyGID string // Parent subscription GID
 	SubscriptionID        uuid.UUID
+	AppID                 uuid.UUID
 	Billed                bool
 	BillingDate           *time.Time
-	AmountCents           int
+	AmountCents           int // Net revenue for ledger records, the reported price for reported ones
 	Description           string
 	LastSyncedAt          time.Time
+
+	// Reconciliation of reported records
+	Source               string     // UsageSourceLedger or UsageSourceReported
+	ReportedAt           *time.Time // When the app created the record in Shopify
+	BilledTransactionGID string     // Usage transaction matched to the record
+	BilledAmountCents    *int       // Gross amount of the matched transaction
 }
 
 // NewUsageStatus creates a new usage status
@@ -39,9 +71,64 @@ func NewUsageStatus(
 		AmountCents:           amountCents,
 		Description:           description,
 		LastSyncedAt:          time.Now().UTC(),
+		Source:                UsageSourceLedger,
 	}
 }
 
+// NewReportedUsageStatus creates an unbilled usage status for a record the app reported
+func NewReportedUsageStatus(
+	shopifyGID string,
+	subscriptionShopifyGID string,
+	subscriptionID uuid.UUID,
+	appID uuid.UUID,
+	amountCents int,
+	description string,
+	reportedAt time.Time,
+) *UsageStatus {
+	reportedAt = reportedAt.UTC()
+	return &UsageStatus{
+		ID:                     uuid.New(),
+		ShopifyGID:             shopifyGID,
+		SubscriptionShopifyGID: subscriptionShopifyGID,
+		SubscriptionID:         subscriptionID,
+		AppID:                  appID,
+		AmountCents:            amountCents,
+		Description:            description,
+		LastSyncedAt:           time.Now().UTC(),
+		Source:                 UsageSourceReported,
+		ReportedAt:             &reportedAt,
+	}
+}
+
+// IsReported returns true if the app reported the record
+func (u *UsageStatus) IsReported() bool {
+	return u.Source == UsageSourceReported
+}
+
+// MarkBilled records the usage transaction that billed a reported record
+func (u *UsageStatus) MarkBilled(transactionGID string, billingDate time.Time, billedAmountCents int) {
+	u.Billed = true
+	u.BillingDate = &billingDate
+	u.BilledTransactionGID = transactionGID
+	u.BilledAmountCents = &billedAmountCents
+	u.LastSyncedAt = time.Now().UTC()
+}
+
+// ReconciliationState compares a record to what was billed. Records unbilled for
+// longer than overdueAfter are overdue. Ledger records are billed by definition.
+func (u *UsageStatus) ReconciliationState(now time.Time, overdueAfter time.Duration) UsageReconciliationState {
+	if u.Billed {
+		if u.BilledAmountCents != nil && *u.BilledAmountCents != u.AmountCents {
+			return UsageStateAmountMismatch
+		}
+		return UsageStateBilled
+	}
+	if u.ReportedAt != nil && now.Sub(*u.ReportedAt) > overdueAfter {
+		return UsageStateOverdue
+	}
+	return UsageStatePending
+}
+
 // UsageStatusResponse is the API response format
 type UsageStatusResponse struct {
 	UsageID      string                              `json:"usage_id"`
@@ -50,6 +137,13 @@ type UsageStatusResponse struct {
 	AmountCents  int                                 `json:"amount_cents"`
 	Description  string                              `json:"description,omitempty"`
 	Subscription *UsageSubscriptionStatusResponse    `json:"subscription,omitempty"`
+
+	// Set for reported records
+	Source              string                   `json:"source"`
+	ReportedAt          *time.Time               `json:"reported_at,omitempty"`
+	BilledTransactionID string                   `json:"billed_transaction_id,omitempty"`
+	BilledAmountCents   *int                     `json:"billed_amount_cents,omitempty"`
+	ReconciliationState UsageReconciliationState `json:"reconciliation_state,omitempty"`
 }
 
 // UsageSubscriptionStatusResponse is the nested subscription info in usage response
@@ -69,7 +163,20 @@ func (u *UsageStatus) ToResponse() UsageStatusResponse {
 		AmountCents: u.AmountCents,
 		Description: u.Description,
 		// Subscription will be populated by the service layer
+		Source:              u.Source,
+		ReportedAt:          u.ReportedAt,
+		BilledTransactionID: u.BilledTransactionGID,
+		BilledAmountCents:   u.BilledAmountCents,
+	}
+}
+
+// ToReconciledResponse converts the entity to API response format with its reconciliation state
+func (u *UsageStatus) ToReconciledResponse(now time.Time, overdueAfter time.Duration) UsageStatusResponse {
+	resp := u.ToResponse()
+	if u.IsReported() {
+		resp.ReconciliationState = u.ReconciliationState(now, overdueAfter)
 	}
+	return resp
 }
 
 // ToResponseWithSubscription creates response with nested subscription
@@ -0,0 +1,228 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// WebhookSecretPrefix is the prefix for outbound webhook signing secrets
+	WebhookSecretPrefix = "whsec_"
+	// WebhookSecretLength is the number of random bytes in a signing secret
+	WebhookSecretLength = 32
+)
+
+// Outbound webhook topics
+const (
+	WebhookTopicSubscriptionRiskChanged   = "subscription.risk_changed"
+	WebhookTopicSubscriptionStatusChanged = "subscription.status_changed"
+	WebhookTopicUsageBilled               = "usage.billed"
+)
+
+// WebhookTopics lists every topic an endpoint can subscribe to
+var WebhookTopics = []string{
+	WebhookTopicSubscriptionRiskChanged,
+	WebhookTopicSubscriptionStatusChanged,
+	WebhookTopicUsageBilled,
+}
+
+// IsValidWebhookTopic returns true if the topic is a known outbound topic
+func IsValidWebhookTopic(topic string) bool {
+	for _, t := range WebhookTopics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEndpoint is a customer URL receiving Revenue API events
+type WebhookEndpoint struct {
+	ID                  uuid.UUID
+	UserID              uuid.UUID // Owner (same user as the API keys)
+	URL                 string
+	Description         string
+	Secret              string // Plaintext HMAC-SHA256 signing secret; only set on create and rotation
+	EncryptedSecret     []byte // Signing secret encrypted at rest
+	Topics              []string
+	Enabled             bool
+	ConsecutiveFailures int // Deliveries that exhausted their retries since the last success
+	DisabledAt          *time.Time
+	DisabledReason      string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// NewWebhookEndpoint creates an enabled endpoint with a random signing secret
+func NewWebhookEndpoint(userID uuid.UUID, url, description string, topics []string) (*WebhookEndpoint, error) {
+	secret, err := NewWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	return &WebhookEndpoint{
+		ID:          uuid.New(),
+		UserID:      userID,
+		URL:         url,
+		Description: description,
+		Secret:      secret,
+		Topics:      topics,
+		Enabled:     true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// NewWebhookSecret generates a new signing secret
+func NewWebhookSecret() (string, error) {
+	b := make([]byte, WebhookSecretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return WebhookSecretPrefix + hex.EncodeToString(b), nil
+}
+
+// Subscribes returns true if the endpoint receives the topic
+func (e *WebhookEndpoint) Subscribes(topic string) bool {
+	for _, t := range e.Topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordSuccess resets the failure streak after a successful delivery
+func (e *WebhookEndpoint) RecordSuccess(now time.Time) {
+	e.ConsecutiveFailures = 0
+	e.UpdatedAt = now
+}
+
+// RecordFailure counts a delivery that exhausted its retries and disables the
+// endpoint once the streak reaches threshold. Returns true if this call disabled it.
+func (e *WebhookEndpoint) RecordFailure(now time.Time, threshold int) bool {
+	e.ConsecutiveFailures++
+	e.UpdatedAt = now
+
+	if !e.Enabled || threshold <= 0 || e.ConsecutiveFailures < threshold {
+		return false
+	}
+
+	e.Enabled = false
+	e.DisabledAt = &now
+	e.DisabledReason = "too many consecutive failed deliveries"
+	return true
+}
+
+// Enable re-enables the endpoint and clears the failure streak
+func (e *WebhookEndpoint) Enable(now time.Time) {
+	e.Enabled = true
+	e.ConsecutiveFailures = 0
+	e.DisabledAt = nil
+	e.DisabledReason = ""
+	e.UpdatedAt = now
+}
+
+// Disable turns the endpoint off at the owner's request
+func (e *WebhookEndpoint) Disable(now time.Time, reason string) {
+	e.Enabled = false
+	e.DisabledAt = &now
+	e.DisabledReason = reason
+	e.UpdatedAt = now
+}
+
+// WebhookDeliveryStatus is the state of a delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookDelivery is one event sent (or to be sent) to one endpoint
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	EndpointID     uuid.UUID
+	EventID        uuid.UUID // Shared by all deliveries of the same event; lets receivers deduplicate
+	Topic          string
+	Payload        []byte // JSON body, identical across retries
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  *time.Time // Set while PENDING
+	LastAttemptAt  *time.Time
+	ResponseStatus int // HTTP status of the last attempt, 0 if no response
+	ResponseBody   string
+	LastError      string
+	RedeliveryOf   *uuid.UUID // Original delivery for manual redeliveries
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// NewWebhookDelivery creates a pending delivery due immediately
+func NewWebhookDelivery(endpointID, eventID uuid.UUID, topic string, payload []byte) *WebhookDelivery {
+	now := time.Now().UTC()
+	return &WebhookDelivery{
+		ID:            uuid.New(),
+		EndpointID:    endpointID,
+		EventID:       eventID,
+		Topic:         topic,
+		Payload:       payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+	}
+}
+
+// Redeliver creates a new pending delivery of the same event and payload
+func (d *WebhookDelivery) Redeliver() *WebhookDelivery {
+	redelivery := NewWebhookDelivery(d.EndpointID, d.EventID, d.Topic, d.Payload)
+	originalID := d.ID
+	if d.RedeliveryOf != nil {
+		originalID = *d.RedeliveryOf
+	}
+	redelivery.RedeliveryOf = &originalID
+	return redelivery
+}
+
+// IsFinal returns true once the delivery will not be attempted again
+func (d *WebhookDelivery) IsFinal() bool {
+	return d.Status != WebhookDeliveryPending
+}
+
+// OutboundWebhookEvent is the JSON envelope posted to endpoints
+type OutboundWebhookEvent struct {
+	ID        uuid.UUID   `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// SubscriptionChangedData is the payload of subscription.* events
+type SubscriptionChangedData struct {
+	SubscriptionID         string     `json:"subscription_id"`
+	MyshopifyDomain        string     `json:"myshopify_domain"`
+	ShopName               string     `json:"shop_name,omitempty"`
+	PlanName               string     `json:"plan_name,omitempty"`
+	PreviousStatus         string     `json:"previous_status"`
+	Status                 string     `json:"status"`
+	PreviousRiskState      string     `json:"previous_risk_state"`
+	RiskState              string     `json:"risk_state"`
+	Reason                 string     `json:"reason,omitempty"`
+	Source                 string     `json:"source"`
+	OccurredAt             time.Time  `json:"occurred_at"`
+	ExpectedNextChargeDate *time.Time `json:"expected_next_charge_date,omitempty"`
+}
+
+// UsageBilledData is the payload of usage.billed events
+type UsageBilledData struct {
+	UsageID        string     `json:"usage_id"`
+	SubscriptionID string     `json:"subscription_id"`
+	AmountCents    int        `json:"amount_cents"`
+	BillingDate    *time.Time `json:"billing_date,omitempty"`
+	Description    string     `json:"description,omitempty"`
+}
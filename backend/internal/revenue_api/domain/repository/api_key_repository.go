@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
@@ -26,4 +27,11 @@ type APIKeyRepository interface {
 
 	// Revoke marks an API key as revoked
 	Revoke(ctx context.Context, id uuid.UUID) error
+
+	// Rotate atomically creates the successor key and marks the old key as replaced,
+	// expiring it at oldExpiresAt
+	Rotate(ctx context.Context, oldID uuid.UUID, oldExpiresAt time.Time, successor *entity.APIKey) error
+
+	// UpdateLastUsed records when keys were last used; earlier times never overwrite later ones
+	UpdateLastUsed(ctx context.Context, lastUsed map[uuid.UUID]time.Time) error
 }
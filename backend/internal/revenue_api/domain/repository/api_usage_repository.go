@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// APIUsageRepository defines the interface for API usage rollups
+type APIUsageRepository interface {
+	// RollupSince recomputes the hourly rollups of every hour from since (truncated
+	// to the hour) onward from the audit log. Recomputing an hour is idempotent.
+	RollupSince(ctx context.Context, since time.Time) error
+
+	// GetRollups returns a key's endpoint rollups for hours in [from, to)
+	GetRollups(ctx context.Context, apiKeyID uuid.UUID, from, to time.Time) ([]*entity.APIUsageRollup, error)
+
+	// GetCallerRollups returns a key's per-IP rollups for hours in [from, to)
+	GetCallerRollups(ctx context.Context, apiKeyID uuid.UUID, from, to time.Time) ([]*entity.APICallerRollup, error)
+}
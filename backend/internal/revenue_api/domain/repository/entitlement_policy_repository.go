@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// EntitlementPolicyRepository defines the interface for per-app entitlement policy persistence
+type EntitlementPolicyRepository interface {
+	// GetByAppID retrieves the app's saved policy, or nil if it has none
+	GetByAppID(ctx context.Context, appID uuid.UUID) (*entity.EntitlementPolicy, error)
+
+	// Upsert creates or replaces the app's policy
+	Upsert(ctx context.Context, policy *entity.EntitlementPolicy) error
+
+	// DeleteByAppID removes the app's policy, reverting it to the default
+	DeleteByAppID(ctx context.Context, appID uuid.UUID) error
+}
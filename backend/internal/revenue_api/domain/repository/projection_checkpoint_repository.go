@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// ProjectionCheckpointRepository defines the interface for read model projection checkpoints
+type ProjectionCheckpointRepository interface {
+	// GetByAppID retrieves the app's checkpoint, or nil if the app was never projected
+	GetByAppID(ctx context.Context, appID uuid.UUID) (*entity.ProjectionCheckpoint, error)
+
+	// Upsert creates or replaces the app's checkpoint
+	Upsert(ctx context.Context, checkpoint *entity.ProjectionCheckpoint) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// RateLimitStore holds rate limit state. Implementations backed by a shared store
+// (Postgres, Redis) enforce one quota across all replicas.
+type RateLimitStore interface {
+	// Allow consumes one request from key's quota of limit per period if it's available
+	Allow(ctx context.Context, key string, limit int, period time.Duration) (entity.RateLimitDecision, error)
+
+	// Refund gives back the request an allowed Allow consumed, when another quota rejected it
+	Refund(ctx context.Context, key string, limit int, period time.Duration) error
+}
@@ -8,6 +8,48 @@ import (
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
 )
 
+// SubscriptionStatusSortField is a column subscription status pages can be ordered by
+type SubscriptionStatusSortField string
+
+const (
+	SortByMyshopifyDomain          SubscriptionStatusSortField = "MYSHOPIFY_DOMAIN"
+	SortByMonthsOverdue            SubscriptionStatusSortField = "MONTHS_OVERDUE"
+	SortByExpectedNextChargeDate   SubscriptionStatusSortField = "EXPECTED_NEXT_CHARGE_DATE"
+	SortByLastSuccessfulChargeDate SubscriptionStatusSortField = "LAST_SUCCESSFUL_CHARGE_DATE"
+)
+
+// IsValid returns true if the field is supported
+func (f SubscriptionStatusSortField) IsValid() bool {
+	switch f {
+	case SortByMyshopifyDomain, SortByMonthsOverdue, SortByExpectedNextChargeDate, SortByLastSuccessfulChargeDate:
+		return true
+	}
+	return false
+}
+
+// SubscriptionStatusFilter narrows subscription status queries. Nil fields are not applied.
+type SubscriptionStatusFilter struct {
+	RiskState *valueobject.RiskState
+	Status    *string
+	IsOverdue *bool // months_overdue > 0
+}
+
+// SubscriptionStatusCursor is the keyset position after which a page starts
+type SubscriptionStatusCursor struct {
+	SortValue string // Sort column value rendered as text (timestamps as RFC3339Nano, NULL as infinity)
+	ID        uuid.UUID
+}
+
+// SubscriptionStatusPageQuery describes a keyset-paginated query across one or more apps
+type SubscriptionStatusPageQuery struct {
+	AppIDs     []uuid.UUID
+	Filter     SubscriptionStatusFilter
+	OrderBy    SubscriptionStatusSortField
+	Descending bool
+	After      *SubscriptionStatusCursor
+	Limit      int
+}
+
 // SubscriptionStatusRepository defines the interface for subscription status persistence (CQRS read model)
 type SubscriptionStatusRepository interface {
 	// Upsert creates or updates a subscription status
@@ -34,6 +76,15 @@ type SubscriptionStatusRepository interface {
 	// GetByAppIDAndRiskState retrieves subscription statuses filtered by risk state
 	GetByAppIDAndRiskState(ctx context.Context, appID uuid.UUID, riskState valueobject.RiskState) ([]*entity.SubscriptionStatus, error)
 
+	// FindPage returns up to Limit statuses ordered by (OrderBy, id) after the cursor
+	FindPage(ctx context.Context, query SubscriptionStatusPageQuery) ([]*entity.SubscriptionStatus, error)
+
+	// Count returns the number of statuses matching the apps and filter (ignores cursor and limit)
+	Count(ctx context.Context, appIDs []uuid.UUID, filter SubscriptionStatusFilter) (int, error)
+
+	// DeleteByShopifyGIDs deletes subscription statuses by Shopify GIDs (orphans found by consistency checks)
+	DeleteByShopifyGIDs(ctx context.Context, shopifyGIDs []string) error
+
 	// DeleteByAppID deletes all subscription statuses for an app (for rebuild)
 	DeleteByAppID(ctx context.Context, appID uuid.UUID) error
 }
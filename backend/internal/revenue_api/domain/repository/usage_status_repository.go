@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
@@ -30,6 +31,12 @@ type UsageStatusRepository interface {
 	// GetUnbilledBySubscriptionID retrieves unbilled usage statuses for a subscription
 	GetUnbilledBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*entity.UsageStatus, error)
 
+	// GetReportedByAppID retrieves an app's reported usage records created in [from, to), oldest first
+	GetReportedByAppID(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.UsageStatus, error)
+
 	// DeleteBySubscriptionID deletes all usage statuses for a subscription
 	DeleteBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) error
+
+	// DeleteByShopifyGIDs deletes usage statuses by Shopify GIDs
+	DeleteByShopifyGIDs(ctx context.Context, shopifyGIDs []string) error
 }
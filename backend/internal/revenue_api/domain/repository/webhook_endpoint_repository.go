@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// WebhookEndpointRepository defines the interface for outbound webhook endpoint persistence
+type WebhookEndpointRepository interface {
+	// Create persists a new endpoint
+	Create(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+
+	// GetByID retrieves an endpoint by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookEndpoint, error)
+
+	// GetByUserID retrieves all endpoints of a user, newest first
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WebhookEndpoint, error)
+
+	// GetEnabledByUserIDAndTopic retrieves the enabled endpoints of a user subscribed to topic
+	GetEnabledByUserIDAndTopic(ctx context.Context, userID uuid.UUID, topic string) ([]*entity.WebhookEndpoint, error)
+
+	// Update saves URL, topics, encrypted secret, enabled state and failure counters
+	Update(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+
+	// Delete removes an endpoint and its delivery log
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// WebhookDeliveryRepository defines the interface for the outbound webhook delivery log
+type WebhookDeliveryRepository interface {
+	// Create persists a new delivery
+	Create(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// GetByID retrieves a delivery by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error)
+
+	// GetByEndpointID retrieves the most recent deliveries of an endpoint, newest first
+	GetByEndpointID(ctx context.Context, endpointID uuid.UUID, limit int) ([]*entity.WebhookDelivery, error)
+
+	// ClaimDue returns up to limit pending deliveries due at now and pushes their
+	// next attempt to now+lease, so concurrent dispatchers don't send them twice
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error)
+
+	// Update saves the outcome of a delivery attempt
+	Update(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// FailPendingByEndpointID marks all pending deliveries of an endpoint as failed
+	FailPendingByEndpointID(ctx context.Context, endpointID uuid.UUID, reason string) error
+}
@@ -0,0 +1,246 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedisError is an error reply from the server
+type RedisError string
+
+func (e RedisError) Error() string { return string(e) }
+
+// RedisClient is a minimal client for servers speaking the Redis protocol (RESP2):
+// Redis, Valkey, KeyDB, Dragonfly or a local stand-in. It keeps a small pool of
+// connections and only supports what the stores in this package need.
+type RedisClient struct {
+	addr        string
+	password    string
+	db          int
+	dialTimeout time.Duration
+	cmdTimeout  time.Duration
+
+	mu   sync.Mutex
+	idle []*redisConn
+}
+
+// maxIdleRedisConns is how many connections are kept for reuse
+const maxIdleRedisConns = 8
+
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+// NewRedisClient creates a client for addr (host:port). Connections are dialled on demand.
+func NewRedisClient(addr string) *RedisClient {
+	return &RedisClient{
+		addr:        addr,
+		dialTimeout: 2 * time.Second,
+		cmdTimeout:  time.Second,
+	}
+}
+
+// WithPassword authenticates new connections with AUTH
+func (c *RedisClient) WithPassword(password string) *RedisClient {
+	c.password = password
+	return c
+}
+
+// WithDB selects a database on new connections
+func (c *RedisClient) WithDB(db int) *RedisClient {
+	c.db = db
+	return c
+}
+
+// Do sends a command and returns its reply: string, int64, []interface{}, nil or a RedisError.
+// Commands time out after the context deadline or one second, whichever is first.
+func (c *RedisClient) Do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	conn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(c.cmdTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.conn.SetDeadline(deadline); err != nil {
+		conn.conn.Close()
+		return nil, err
+	}
+
+	reply, err := conn.do(args...)
+	if err != nil {
+		// The connection's state is unknown after an I/O error
+		conn.conn.Close()
+		return nil, err
+	}
+
+	c.put(conn)
+	if redisErr, ok := reply.(RedisError); ok {
+		return nil, redisErr
+	}
+	return reply, nil
+}
+
+// Close closes the idle connections
+func (c *RedisClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, conn := range c.idle {
+		conn.conn.Close()
+	}
+	c.idle = nil
+	return nil
+}
+
+func (c *RedisClient) get(ctx context.Context) (*redisConn, error) {
+	c.mu.Lock()
+	if n := len(c.idle); n > 0 {
+		conn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return conn, nil
+	}
+	c.mu.Unlock()
+
+	dialer := net.Dialer{Timeout: c.dialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis dial: %w", err)
+	}
+	conn := &redisConn{conn: netConn, r: bufio.NewReader(netConn), w: bufio.NewWriter(netConn)}
+
+	if err := netConn.SetDeadline(time.Now().Add(c.cmdTimeout)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	if c.password != "" {
+		if err := conn.expectOK("AUTH", c.password); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("redis auth: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if err := conn.expectOK("SELECT", c.db); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("redis select: %w", err)
+		}
+	}
+
+	return conn, nil
+}
+
+func (c *RedisClient) put(conn *redisConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.idle) >= maxIdleRedisConns {
+		conn.conn.Close()
+		return
+	}
+	c.idle = append(c.idle, conn)
+}
+
+func (c *redisConn) expectOK(args ...interface{}) error {
+	reply, err := c.do(args...)
+	if err != nil {
+		return err
+	}
+	if redisErr, ok := reply.(RedisError); ok {
+		return redisErr
+	}
+	return nil
+}
+
+func (c *redisConn) do(args ...interface{}) (interface{}, error) {
+	if err := writeCommand(c.w, args); err != nil {
+		return nil, err
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+// writeCommand encodes args as a RESP array of bulk strings
+func writeCommand(w *bufio.Writer, args []interface{}) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var s string
+		switch v := arg.(type) {
+		case string:
+			s = v
+		case int:
+			s = strconv.Itoa(v)
+		case int64:
+			s = strconv.FormatInt(v, 10)
+		default:
+			return fmt.Errorf("redis: unsupported argument type %T", arg)
+		}
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(s), s)
+	}
+	return nil
+}
+
+// readReply decodes one RESP2 reply
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, errors.New("redis: malformed reply")
+	}
+	payload := line[1 : len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return payload, nil
+	case '-':
+		return RedisError(payload), nil
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", line[0])
+	}
+}
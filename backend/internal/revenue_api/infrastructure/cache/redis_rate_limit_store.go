@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// gcraScript runs GCRA atomically against the server clock.
+// KEYS[1] = quota key, ARGV[1] = emission interval (µs), ARGV[2] = period (µs).
+// Returns {allowed, tat, now} in microseconds.
+const gcraScript = `
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then tat = now end
+local new_tat = tat + interval
+if new_tat - period > now then
+  return {0, tat, now}
+end
+redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil((new_tat - now) / 1000))
+return {1, new_tat, now}
+`
+
+// refundScript gives back one request, deleting the key once its quota is full.
+// KEYS[1] = quota key, ARGV[1] = emission interval (µs).
+const refundScript = `
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat then return 0 end
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+tat = tat - tonumber(ARGV[1])
+if tat <= now then
+  redis.call('DEL', KEYS[1])
+  return 0
+end
+redis.call('SET', KEYS[1], tat, 'PX', math.ceil((tat - now) / 1000))
+return 1
+`
+
+var gcraScriptSHA = func() string {
+	sum := sha1.Sum([]byte(gcraScript))
+	return hex.EncodeToString(sum[:])
+}()
+
+// RedisRateLimitStore is a Redis-backed implementation of RateLimitStore.
+// Replicas sharing the server share quotas; keys expire once their quota refills.
+type RedisRateLimitStore struct {
+	client *RedisClient
+	prefix string
+}
+
+// NewRedisRateLimitStore creates a new Redis rate limit store
+func NewRedisRateLimitStore(client *RedisClient) *RedisRateLimitStore {
+	return &RedisRateLimitStore{client: client, prefix: "ledgerguard:"}
+}
+
+// Allow consumes one request from key's quota if it's available
+func (s *RedisRateLimitStore) Allow(ctx context.Context, key string, limit int, period time.Duration) (entity.RateLimitDecision, error) {
+	if limit < 1 {
+		limit = 1
+	}
+	interval := period / time.Duration(limit)
+	args := []interface{}{s.prefix + key, interval.Microseconds(), period.Microseconds()}
+
+	// The script is cached by the server; send it only when it isn't loaded yet
+	reply, err := s.client.Do(ctx, append([]interface{}{"EVALSHA", gcraScriptSHA, 1}, args...)...)
+	var redisErr RedisError
+	if errors.As(err, &redisErr) && strings.HasPrefix(string(redisErr), "NOSCRIPT") {
+		reply, err = s.client.Do(ctx, append([]interface{}{"EVAL", gcraScript, 1}, args...)...)
+	}
+	if err != nil {
+		return entity.RateLimitDecision{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 3 {
+		return entity.RateLimitDecision{}, fmt.Errorf("redis: unexpected GCRA reply %v", reply)
+	}
+	allowed, ok1 := values[0].(int64)
+	tat, ok2 := values[1].(int64)
+	now, ok3 := values[2].(int64)
+	if !ok1 || !ok2 || !ok3 {
+		return entity.RateLimitDecision{}, fmt.Errorf("redis: unexpected GCRA reply %v", reply)
+	}
+
+	return entity.NewRateLimitDecision(allowed == 1, time.UnixMicro(tat), time.UnixMicro(now), limit, period), nil
+}
+
+// Refund gives back one request to key's quota. Refunds are rare, so the script is sent with EVAL.
+func (s *RedisRateLimitStore) Refund(ctx context.Context, key string, limit int, period time.Duration) error {
+	if limit < 1 {
+		limit = 1
+	}
+	interval := period / time.Duration(limit)
+	_, err := s.client.Do(ctx, "EVAL", refundScript, 1, s.prefix+key, interval.Microseconds())
+	return err
+}
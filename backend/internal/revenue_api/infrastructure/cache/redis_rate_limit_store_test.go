@@ -0,0 +1,218 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// fakeRedis speaks enough RESP to run the GCRA script: it answers EVALSHA with
+// NOSCRIPT until the script has been sent with EVAL, and evaluates it in Go.
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	now      time.Time
+	tats     map[string]int64
+	loaded   bool
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeRedis{
+		listener: listener,
+		password: password,
+		now:      time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		tats:     make(map[string]int64),
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := f.password == ""
+
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		args, _ := reply.([]interface{})
+		cmd := args[0].(string)
+
+		f.mu.Lock()
+		f.commands = append(f.commands, cmd)
+		var out string
+		switch {
+		case cmd == "AUTH":
+			authed = args[1] == f.password
+			out = "+OK\r\n"
+			if !authed {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			out = "-NOAUTH Authentication required.\r\n"
+		case cmd == "EVAL" && args[1] == refundScript:
+			out = f.refund(args[3].(string), args[4].(string))
+		case cmd == "EVALSHA" && !f.loaded:
+			out = "-NOSCRIPT No matching script.\r\n"
+		case cmd == "EVAL" || cmd == "EVALSHA":
+			f.loaded = true
+			out = f.gcra(args[3].(string), args[4].(string), args[5].(string))
+		default:
+			out = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+
+		if _, err := conn.Write([]byte(out)); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) gcra(key, intervalArg, periodArg string) string {
+	interval, _ := strconv.ParseInt(intervalArg, 10, 64)
+	period, _ := strconv.ParseInt(periodArg, 10, 64)
+	now := f.now.UnixMicro()
+
+	tat, ok := f.tats[key]
+	if !ok || tat < now {
+		tat = now
+	}
+	newTAT := tat + interval
+	allowed := int64(1)
+	if newTAT-period > now {
+		allowed = 0
+	} else {
+		f.tats[key] = newTAT
+		tat = newTAT
+	}
+	return "*3\r\n:" + strconv.FormatInt(allowed, 10) + "\r\n:" + strconv.FormatInt(tat, 10) + "\r\n:" + strconv.FormatInt(now, 10) + "\r\n"
+}
+
+func (f *fakeRedis) refund(key, intervalArg string) string {
+	interval, _ := strconv.ParseInt(intervalArg, 10, 64)
+	tat, ok := f.tats[key]
+	if !ok {
+		return ":0\r\n"
+	}
+	if tat -= interval; tat <= f.now.UnixMicro() {
+		delete(f.tats, key)
+		return ":0\r\n"
+	}
+	f.tats[key] = tat
+	return ":1\r\n"
+}
+
+func TestRedisRateLimitStore_Allow(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	client := NewRedisClient(server.listener.Addr().String()).WithPassword("secret")
+	defer client.Close()
+	store := NewRedisRateLimitStore(client)
+	ctx := context.Background()
+
+	var decision entity.RateLimitDecision
+	var err error
+	for i := 0; i < 3; i++ {
+		decision, err = store.Allow(ctx, "ratelimit:key:a", 3, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !decision.Allowed || decision.Remaining != 2-i {
+			t.Fatalf("request %d: expected allowed with %d remaining, got %+v", i+1, 2-i, decision)
+		}
+	}
+	if decision.ResetAfter != time.Minute {
+		t.Errorf("expected reset after a minute, got %v", decision.ResetAfter)
+	}
+
+	decision, err = store.Allow(ctx, "ratelimit:key:a", 3, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Allowed || decision.RetryAfter != 20*time.Second {
+		t.Errorf("expected denied with retry after 20s, got %+v", decision)
+	}
+
+	// Quotas are per key
+	if decision, _ := store.Allow(ctx, "ratelimit:key:b", 3, time.Minute); !decision.Allowed {
+		t.Error("expected another key to be allowed")
+	}
+
+	// The script is sent once, then run by its SHA on the pooled connection
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	want := []string{"AUTH", "EVALSHA", "EVAL", "EVALSHA", "EVALSHA", "EVALSHA", "EVALSHA"}
+	if len(server.commands) != len(want) {
+		t.Fatalf("expected commands %v, got %v", want, server.commands)
+	}
+	for i := range want {
+		if server.commands[i] != want[i] {
+			t.Fatalf("expected commands %v, got %v", want, server.commands)
+		}
+	}
+}
+
+func TestRedisRateLimitStore_Refund(t *testing.T) {
+	server := newFakeRedis(t, "")
+	client := NewRedisClient(server.listener.Addr().String())
+	defer client.Close()
+	store := NewRedisRateLimitStore(client)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if decision, err := store.Allow(ctx, "ratelimit:key:a", 2, time.Minute); err != nil || !decision.Allowed {
+			t.Fatalf("request %d: expected allowed, got %+v, %v", i+1, decision, err)
+		}
+	}
+	if err := store.Refund(ctx, "ratelimit:key:a", 2, time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decision, err := store.Allow(ctx, "ratelimit:key:a", 2, time.Minute)
+	if err != nil || !decision.Allowed {
+		t.Errorf("expected the refunded request to be allowed, got %+v, %v", decision, err)
+	}
+
+	// Refunding a full quota removes the key rather than banking extra requests
+	if err := store.Refund(ctx, "ratelimit:key:b", 2, time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if _, ok := server.tats["ledgerguard:ratelimit:key:b"]; ok {
+		t.Error("expected no state for a quota that was never used")
+	}
+}
+
+func TestRedisRateLimitStore_AuthFailure(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	client := NewRedisClient(server.listener.Addr().String()).WithPassword("wrong")
+	defer client.Close()
+
+	if _, err := NewRedisRateLimitStore(client).Allow(context.Background(), "k", 1, time.Minute); err == nil {
+		t.Error("expected an auth error")
+	}
+}
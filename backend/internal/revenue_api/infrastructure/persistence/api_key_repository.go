@@ -25,9 +25,14 @@ func NewPostgresAPIKeyRepository(pool *pgxpool.Pool) *PostgresAPIKeyRepository {
 
 // Create creates a new API key
 func (r *PostgresAPIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	appIDs := key.AppIDs
+	if appIDs == nil {
+		appIDs = []uuid.UUID{}
+	}
+
 	query := `
-		INSERT INTO api_keys (id, user_id, key_hash, name, rate_limit_per_minute, created_at, revoked_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO api_keys (id, user_id, key_hash, name, rate_limit_per_minute, scopes, app_ids, created_at, expires_at, revoked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
 	_, err := r.pool.Exec(ctx, query,
@@ -36,7 +41,10 @@ func (r *PostgresAPIKeyRepository) Create(ctx context.Context, key *entity.APIKe
 		key.KeyHash,
 		key.Name,
 		key.RateLimitPerMinute,
+		key.Scopes,
+		appIDs,
 		key.CreatedAt,
+		key.ExpiresAt,
 		key.RevokedAt,
 	)
 
@@ -46,7 +54,7 @@ func (r *PostgresAPIKeyRepository) Create(ctx context.Context, key *entity.APIKe
 // GetByHash retrieves an API key by its hash
 func (r *PostgresAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
 	query := `
-		SELECT id, user_id, key_hash, name, rate_limit_per_minute, created_at, revoked_at
+		SELECT id, user_id, key_hash, name, rate_limit_per_minute, scopes, app_ids, created_at, expires_at, last_used_at, replaced_by, revoked_at
 		FROM api_keys
 		WHERE key_hash = $1
 	`
@@ -57,7 +65,7 @@ func (r *PostgresAPIKeyRepository) GetByHash(ctx context.Context, keyHash string
 // GetByID retrieves an API key by ID
 func (r *PostgresAPIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.APIKey, error) {
 	query := `
-		SELECT id, user_id, key_hash, name, rate_limit_per_minute, created_at, revoked_at
+		SELECT id, user_id, key_hash, name, rate_limit_per_minute, scopes, app_ids, created_at, expires_at, last_used_at, replaced_by, revoked_at
 		FROM api_keys
 		WHERE id = $1
 	`
@@ -68,7 +76,7 @@ func (r *PostgresAPIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*
 // GetByUserID retrieves all API keys for a user
 func (r *PostgresAPIKeyRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.APIKey, error) {
 	query := `
-		SELECT id, user_id, key_hash, name, rate_limit_per_minute, created_at, revoked_at
+		SELECT id, user_id, key_hash, name, rate_limit_per_minute, scopes, app_ids, created_at, expires_at, last_used_at, replaced_by, revoked_at
 		FROM api_keys
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -86,7 +94,7 @@ func (r *PostgresAPIKeyRepository) GetByUserID(ctx context.Context, userID uuid.
 // GetActiveByUserID retrieves only active (non-revoked) keys for a user
 func (r *PostgresAPIKeyRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.APIKey, error) {
 	query := `
-		SELECT id, user_id, key_hash, name, rate_limit_per_minute, created_at, revoked_at
+		SELECT id, user_id, key_hash, name, rate_limit_per_minute, scopes, app_ids, created_at, expires_at, last_used_at, replaced_by, revoked_at
 		FROM api_keys
 		WHERE user_id = $1 AND revoked_at IS NULL
 		ORDER BY created_at DESC
@@ -121,6 +129,82 @@ func (r *PostgresAPIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) err
 	return nil
 }
 
+// Rotate creates the successor key and marks the old key as replaced in one transaction
+func (r *PostgresAPIKeyRepository) Rotate(ctx context.Context, oldID uuid.UUID, oldExpiresAt time.Time, successor *entity.APIKey) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Only an active, not yet rotated key can be rotated
+	result, err := tx.Exec(ctx, `
+		UPDATE api_keys
+		SET expires_at = LEAST(COALESCE(expires_at, $1), $1)
+		WHERE id = $2 AND revoked_at IS NULL AND replaced_by IS NULL
+	`, oldExpiresAt, oldID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	appIDs := successor.AppIDs
+	if appIDs == nil {
+		appIDs = []uuid.UUID{}
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO api_keys (id, user_id, key_hash, name, rate_limit_per_minute, scopes, app_ids, created_at, expires_at, revoked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		successor.ID,
+		successor.UserID,
+		successor.KeyHash,
+		successor.Name,
+		successor.RateLimitPerMinute,
+		successor.Scopes,
+		appIDs,
+		successor.CreatedAt,
+		successor.ExpiresAt,
+		successor.RevokedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE api_keys SET replaced_by = $1 WHERE id = $2`, successor.ID, oldID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UpdateLastUsed records last-used times for a batch of keys in one statement
+func (r *PostgresAPIKeyRepository) UpdateLastUsed(ctx context.Context, lastUsed map[uuid.UUID]time.Time) error {
+	if len(lastUsed) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(lastUsed))
+	times := make([]time.Time, 0, len(lastUsed))
+	for id, at := range lastUsed {
+		ids = append(ids, id)
+		times = append(times, at)
+	}
+
+	query := `
+		UPDATE api_keys k
+		SET last_used_at = GREATEST(COALESCE(k.last_used_at, u.used_at), u.used_at)
+		FROM unnest($1::uuid[], $2::timestamptz[]) AS u(id, used_at)
+		WHERE k.id = u.id
+	`
+
+	_, err := r.pool.Exec(ctx, query, ids, times)
+	return err
+}
+
 func (r *PostgresAPIKeyRepository) scanAPIKey(row pgx.Row) (*entity.APIKey, error) {
 	var key entity.APIKey
 	var name *string
@@ -131,7 +215,12 @@ func (r *PostgresAPIKeyRepository) scanAPIKey(row pgx.Row) (*entity.APIKey, erro
 		&key.KeyHash,
 		&name,
 		&key.RateLimitPerMinute,
+		&key.Scopes,
+		&key.AppIDs,
 		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.ReplacedByID,
 		&key.RevokedAt,
 	)
 
@@ -162,7 +251,12 @@ func (r *PostgresAPIKeyRepository) scanAPIKeys(rows pgx.Rows) ([]*entity.APIKey,
 			&key.KeyHash,
 			&name,
 			&key.RateLimitPerMinute,
+			&key.Scopes,
+			&key.AppIDs,
 			&key.CreatedAt,
+			&key.ExpiresAt,
+			&key.LastUsedAt,
+			&key.ReplacedByID,
 			&key.RevokedAt,
 		)
 		if err != nil {
@@ -0,0 +1,170 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// PostgresAPIUsageRepository implements APIUsageRepository using PostgreSQL
+type PostgresAPIUsageRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresAPIUsageRepository creates a new PostgresAPIUsageRepository
+func NewPostgresAPIUsageRepository(pool *pgxpool.Pool) *PostgresAPIUsageRepository {
+	return &PostgresAPIUsageRepository{pool: pool}
+}
+
+// latencyBucketsSQL builds the histogram array matching entity.APIUsageLatencyBuckets
+func latencyBucketsSQL() string {
+	parts := make([]string, 0, len(entity.APIUsageLatencyBuckets)+1)
+	lower := -1
+	for _, upper := range entity.APIUsageLatencyBuckets {
+		parts = append(parts, fmt.Sprintf("COUNT(*) FILTER (WHERE response_time_ms > %d AND response_time_ms <= %d)", lower, upper))
+		lower = upper
+	}
+	parts = append(parts, fmt.Sprintf("COUNT(*) FILTER (WHERE response_time_ms > %d)", lower))
+	return "ARRAY[" + strings.Join(parts, ", ") + "]"
+}
+
+// RollupSince recomputes the hourly rollups from since onward in one transaction
+func (r *PostgresAPIUsageRepository) RollupSince(ctx context.Context, since time.Time) error {
+	since = since.UTC().Truncate(time.Hour)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	usageQuery := `
+		INSERT INTO api_usage_hourly (
+			api_key_id, hour, endpoint, method, request_count, error_count,
+			server_error_count, rate_limited_count, total_response_ms, latency_buckets
+		)
+		SELECT
+			api_key_id,
+			date_trunc('hour', created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+			COALESCE(route, endpoint),
+			method,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE response_status >= 400),
+			COUNT(*) FILTER (WHERE response_status >= 500),
+			COUNT(*) FILTER (WHERE response_status = 429),
+			SUM(response_time_ms),
+			` + latencyBucketsSQL() + `
+		FROM api_audit_log
+		WHERE created_at >= $1
+		GROUP BY 1, 2, 3, 4
+		ON CONFLICT (api_key_id, hour, endpoint, method) DO UPDATE SET
+			request_count = EXCLUDED.request_count,
+			error_count = EXCLUDED.error_count,
+			server_error_count = EXCLUDED.server_error_count,
+			rate_limited_count = EXCLUDED.rate_limited_count,
+			total_response_ms = EXCLUDED.total_response_ms,
+			latency_buckets = EXCLUDED.latency_buckets
+	`
+	if _, err := tx.Exec(ctx, usageQuery, since); err != nil {
+		return fmt.Errorf("failed to roll up usage: %w", err)
+	}
+
+	callersQuery := `
+		INSERT INTO api_usage_callers_hourly (api_key_id, hour, ip_address, request_count, error_count)
+		SELECT
+			api_key_id,
+			date_trunc('hour', created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+			COALESCE(ip_address, 'unknown'),
+			COUNT(*),
+			COUNT(*) FILTER (WHERE response_status >= 400)
+		FROM api_audit_log
+		WHERE created_at >= $1
+		GROUP BY 1, 2, 3
+		ON CONFLICT (api_key_id, hour, ip_address) DO UPDATE SET
+			request_count = EXCLUDED.request_count,
+			error_count = EXCLUDED.error_count
+	`
+	if _, err := tx.Exec(ctx, callersQuery, since); err != nil {
+		return fmt.Errorf("failed to roll up callers: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetRollups returns a key's endpoint rollups for hours in [from, to)
+func (r *PostgresAPIUsageRepository) GetRollups(ctx context.Context, apiKeyID uuid.UUID, from, to time.Time) ([]*entity.APIUsageRollup, error) {
+	query := `
+		SELECT api_key_id, hour, endpoint, method, request_count, error_count,
+			server_error_count, rate_limited_count, total_response_ms, latency_buckets
+		FROM api_usage_hourly
+		WHERE api_key_id = $1 AND hour >= $2 AND hour < $3
+		ORDER BY hour
+	`
+
+	rows, err := r.pool.Query(ctx, query, apiKeyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rollups []*entity.APIUsageRollup
+	for rows.Next() {
+		var rollup entity.APIUsageRollup
+		var buckets []int64
+		if err := rows.Scan(
+			&rollup.APIKeyID,
+			&rollup.Hour,
+			&rollup.Endpoint,
+			&rollup.Method,
+			&rollup.RequestCount,
+			&rollup.ErrorCount,
+			&rollup.ServerErrorCount,
+			&rollup.RateLimitedCount,
+			&rollup.TotalResponseMs,
+			&buckets,
+		); err != nil {
+			return nil, err
+		}
+		rollup.LatencyBuckets = entity.LatencyHistogram(buckets)
+		rollups = append(rollups, &rollup)
+	}
+
+	return rollups, rows.Err()
+}
+
+// GetCallerRollups returns a key's per-IP rollups for hours in [from, to)
+func (r *PostgresAPIUsageRepository) GetCallerRollups(ctx context.Context, apiKeyID uuid.UUID, from, to time.Time) ([]*entity.APICallerRollup, error) {
+	query := `
+		SELECT api_key_id, hour, ip_address, request_count, error_count
+		FROM api_usage_callers_hourly
+		WHERE api_key_id = $1 AND hour >= $2 AND hour < $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, apiKeyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rollups []*entity.APICallerRollup
+	for rows.Next() {
+		var rollup entity.APICallerRollup
+		if err := rows.Scan(
+			&rollup.APIKeyID,
+			&rollup.Hour,
+			&rollup.IPAddress,
+			&rollup.RequestCount,
+			&rollup.ErrorCount,
+		); err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, &rollup)
+	}
+
+	return rollups, rows.Err()
+}
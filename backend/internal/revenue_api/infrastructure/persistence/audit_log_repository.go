@@ -34,9 +34,9 @@ func NewPostgresAuditLogRepository(pool *pgxpool.Pool) *PostgresAuditLogReposito
 func (r *PostgresAuditLogRepository) Create(ctx context.Context, auditLog *entity.AuditLog) error {
 	query := `
 		INSERT INTO api_audit_log (
-			id, api_key_id, endpoint, method, request_params,
+			id, api_key_id, endpoint, route, method, request_params,
 			response_status, response_time_ms, ip_address, user_agent, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	paramsJSON, err := auditLog.RequestParamsJSON()
@@ -48,6 +48,7 @@ func (r *PostgresAuditLogRepository) Create(ctx context.Context, auditLog *entit
 		auditLog.ID,
 		auditLog.APIKeyID,
 		auditLog.Endpoint,
+		auditLog.Route,
 		auditLog.Method,
 		paramsJSON,
 		auditLog.ResponseStatus,
@@ -85,7 +86,7 @@ func (r *PostgresAuditLogRepository) asyncWorker() {
 // GetByAPIKeyID retrieves audit logs for an API key
 func (r *PostgresAuditLogRepository) GetByAPIKeyID(ctx context.Context, apiKeyID uuid.UUID, limit int, offset int) ([]*entity.AuditLog, error) {
 	query := `
-		SELECT id, api_key_id, endpoint, method, request_params,
+		SELECT id, api_key_id, endpoint, COALESCE(route, ''), method, request_params,
 			response_status, response_time_ms, ip_address, user_agent, created_at
 		FROM api_audit_log
 		WHERE api_key_id = $1
@@ -105,7 +106,7 @@ func (r *PostgresAuditLogRepository) GetByAPIKeyID(ctx context.Context, apiKeyID
 // GetByAPIKeyIDSince retrieves audit logs since a specific time
 func (r *PostgresAuditLogRepository) GetByAPIKeyIDSince(ctx context.Context, apiKeyID uuid.UUID, since time.Time, limit int) ([]*entity.AuditLog, error) {
 	query := `
-		SELECT id, api_key_id, endpoint, method, request_params,
+		SELECT id, api_key_id, endpoint, COALESCE(route, ''), method, request_params,
 			response_status, response_time_ms, ip_address, user_agent, created_at
 		FROM api_audit_log
 		WHERE api_key_id = $1 AND created_at >= $2
@@ -125,7 +126,7 @@ func (r *PostgresAuditLogRepository) GetByAPIKeyIDSince(ctx context.Context, api
 // GetErrorsByAPIKeyID retrieves error logs (status >= 400) for an API key
 func (r *PostgresAuditLogRepository) GetErrorsByAPIKeyID(ctx context.Context, apiKeyID uuid.UUID, limit int) ([]*entity.AuditLog, error) {
 	query := `
-		SELECT id, api_key_id, endpoint, method, request_params,
+		SELECT id, api_key_id, endpoint, COALESCE(route, ''), method, request_params,
 			response_status, response_time_ms, ip_address, user_agent, created_at
 		FROM api_audit_log
 		WHERE api_key_id = $1 AND response_status >= 400
@@ -172,6 +173,7 @@ func (r *PostgresAuditLogRepository) scanLogs(rows pgx.Rows) ([]*entity.AuditLog
 			&auditLog.ID,
 			&auditLog.APIKeyID,
 			&auditLog.Endpoint,
+			&auditLog.Route,
 			&auditLog.Method,
 			&paramsJSON,
 			&auditLog.ResponseStatus,
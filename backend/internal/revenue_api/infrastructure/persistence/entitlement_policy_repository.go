@@ -0,0 +1,97 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// PostgresEntitlementPolicyRepository implements EntitlementPolicyRepository using PostgreSQL
+type PostgresEntitlementPolicyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresEntitlementPolicyRepository creates a new PostgresEntitlementPolicyRepository
+func NewPostgresEntitlementPolicyRepository(pool *pgxpool.Pool) *PostgresEntitlementPolicyRepository {
+	return &PostgresEntitlementPolicyRepository{pool: pool}
+}
+
+// GetByAppID retrieves the app's saved policy, or nil if it has none
+func (r *PostgresEntitlementPolicyRepository) GetByAppID(ctx context.Context, appID uuid.UUID) (*entity.EntitlementPolicy, error) {
+	query := `
+		SELECT id, app_id, rules, default_decision, default_reason, created_at, updated_at
+		FROM api_entitlement_policies
+		WHERE app_id = $1
+	`
+
+	var policy entity.EntitlementPolicy
+	var rulesJSON []byte
+	var defaultDecision string
+	err := r.pool.QueryRow(ctx, query, appID).Scan(
+		&policy.ID,
+		&policy.AppID,
+		&rulesJSON,
+		&defaultDecision,
+		&policy.DefaultReason,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	policy.DefaultDecision = entity.EntitlementDecision(defaultDecision)
+	if err := json.Unmarshal(rulesJSON, &policy.Rules); err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// Upsert creates or replaces the app's policy
+func (r *PostgresEntitlementPolicyRepository) Upsert(ctx context.Context, policy *entity.EntitlementPolicy) error {
+	rules := policy.Rules
+	if rules == nil {
+		rules = []entity.EntitlementRule{}
+	}
+	rulesJSON, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO api_entitlement_policies (id, app_id, rules, default_decision, default_reason, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (app_id) DO UPDATE SET
+			rules = EXCLUDED.rules,
+			default_decision = EXCLUDED.default_decision,
+			default_reason = EXCLUDED.default_reason,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		policy.ID,
+		policy.AppID,
+		rulesJSON,
+		string(policy.DefaultDecision),
+		policy.DefaultReason,
+		policy.CreatedAt,
+		policy.UpdatedAt,
+	)
+
+	return err
+}
+
+// DeleteByAppID removes the app's policy, reverting it to the default
+func (r *PostgresEntitlementPolicyRepository) DeleteByAppID(ctx context.Context, appID uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM api_entitlement_policies WHERE app_id = $1`, appID)
+	return err
+}
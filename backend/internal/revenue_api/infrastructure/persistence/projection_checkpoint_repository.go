@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// PostgresProjectionCheckpointRepository implements ProjectionCheckpointRepository using PostgreSQL
+type PostgresProjectionCheckpointRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresProjectionCheckpointRepository creates a new PostgresProjectionCheckpointRepository
+func NewPostgresProjectionCheckpointRepository(pool *pgxpool.Pool) *PostgresProjectionCheckpointRepository {
+	return &PostgresProjectionCheckpointRepository{pool: pool}
+}
+
+// GetByAppID retrieves the app's checkpoint, or nil if the app was never projected
+func (r *PostgresProjectionCheckpointRepository) GetByAppID(ctx context.Context, appID uuid.UUID) (*entity.ProjectionCheckpoint, error) {
+	query := `
+		SELECT app_id, subscriptions_through, transactions_through, projected_at,
+			rebuilt_at, checked_at, drift_count
+		FROM api_projection_checkpoints
+		WHERE app_id = $1
+	`
+
+	var checkpoint entity.ProjectionCheckpoint
+	err := r.pool.QueryRow(ctx, query, appID).Scan(
+		&checkpoint.AppID,
+		&checkpoint.SubscriptionsThrough,
+		&checkpoint.TransactionsThrough,
+		&checkpoint.ProjectedAt,
+		&checkpoint.RebuiltAt,
+		&checkpoint.CheckedAt,
+		&checkpoint.DriftCount,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+// Upsert creates or replaces the app's checkpoint
+func (r *PostgresProjectionCheckpointRepository) Upsert(ctx context.Context, checkpoint *entity.ProjectionCheckpoint) error {
+	query := `
+		INSERT INTO api_projection_checkpoints (
+			app_id, subscriptions_through, transactions_through, projected_at,
+			rebuilt_at, checked_at, drift_count
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (app_id) DO UPDATE SET
+			subscriptions_through = EXCLUDED.subscriptions_through,
+			transactions_through = EXCLUDED.transactions_through,
+			projected_at = EXCLUDED.projected_at,
+			rebuilt_at = EXCLUDED.rebuilt_at,
+			checked_at = EXCLUDED.checked_at,
+			drift_count = EXCLUDED.drift_count
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		checkpoint.AppID,
+		checkpoint.SubscriptionsThrough,
+		checkpoint.TransactionsThrough,
+		checkpoint.ProjectedAt,
+		checkpoint.RebuiltAt,
+		checkpoint.CheckedAt,
+		checkpoint.DriftCount,
+	)
+
+	return err
+}
@@ -0,0 +1,100 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// PostgresRateLimitStore implements RateLimitStore in PostgreSQL so replicas share quotas.
+// Each check is a single upsert evaluated against the database clock, so replica
+// clock skew doesn't matter.
+type PostgresRateLimitStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRateLimitStore creates a new PostgresRateLimitStore
+func NewPostgresRateLimitStore(pool *pgxpool.Pool) *PostgresRateLimitStore {
+	store := &PostgresRateLimitStore{pool: pool}
+
+	// Start background pruning of refilled quotas
+	go store.pruneWorker()
+
+	return store
+}
+
+// Allow consumes one request from key's quota if it's available
+func (s *PostgresRateLimitStore) Allow(ctx context.Context, key string, limit int, period time.Duration) (entity.RateLimitDecision, error) {
+	if limit < 1 {
+		limit = 1
+	}
+	interval := period / time.Duration(limit)
+
+	// GCRA: the TAT only moves when the request fits within the burst
+	query := `
+		INSERT INTO api_rate_limits AS r (key, tat)
+		VALUES ($1, now() + $2 * interval '1 microsecond')
+		ON CONFLICT (key) DO UPDATE
+		SET tat = GREATEST(r.tat, now()) + $2 * interval '1 microsecond'
+		WHERE GREATEST(r.tat, now()) + $2 * interval '1 microsecond' - $3 * interval '1 microsecond' <= now()
+		RETURNING tat, now()
+	`
+
+	var tat, now time.Time
+	err := s.pool.QueryRow(ctx, query, key, interval.Microseconds(), period.Microseconds()).Scan(&tat, &now)
+	if err == nil {
+		return entity.NewRateLimitDecision(true, tat, now, limit, period), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return entity.RateLimitDecision{}, err
+	}
+
+	// Denied: read the TAT to report when the next request fits
+	err = s.pool.QueryRow(ctx, `SELECT tat, now() FROM api_rate_limits WHERE key = $1`, key).Scan(&tat, &now)
+	if err != nil {
+		return entity.RateLimitDecision{}, err
+	}
+	return entity.NewRateLimitDecision(false, tat, now, limit, period), nil
+}
+
+// Refund gives back one request to key's quota
+func (s *PostgresRateLimitStore) Refund(ctx context.Context, key string, limit int, period time.Duration) error {
+	if limit < 1 {
+		limit = 1
+	}
+	interval := period / time.Duration(limit)
+
+	_, err := s.pool.Exec(ctx, `
+		UPDATE api_rate_limits
+		SET tat = GREATEST(tat - $2 * interval '1 microsecond', now())
+		WHERE key = $1
+	`, key, interval.Microseconds())
+	return err
+}
+
+// Prune deletes quotas that have fully refilled
+func (s *PostgresRateLimitStore) Prune(ctx context.Context) (int64, error) {
+	result, err := s.pool.Exec(ctx, `DELETE FROM api_rate_limits WHERE tat < now()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+func (s *PostgresRateLimitStore) pruneWorker() {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if _, err := s.Prune(ctx); err != nil {
+			log.Printf("failed to prune rate limits: %v", err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,109 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// SubscriptionChangeChannel is the Postgres NOTIFY channel for subscription changes
+const SubscriptionChangeChannel = "api_subscription_changes"
+
+// PostgresSubscriptionChangeNotifier fans subscription changes out to every
+// replica with LISTEN/NOTIFY. Each replica listens on a dedicated connection and
+// hands received changes (including its own) to the deliver callback.
+// Changes published while a listener is reconnecting are not redelivered.
+type PostgresSubscriptionChangeNotifier struct {
+	pool   *pgxpool.Pool
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+// NewPostgresSubscriptionChangeNotifier creates a new PostgresSubscriptionChangeNotifier
+func NewPostgresSubscriptionChangeNotifier(pool *pgxpool.Pool) *PostgresSubscriptionChangeNotifier {
+	return &PostgresSubscriptionChangeNotifier{
+		pool:   pool,
+		doneCh: make(chan struct{}),
+	}
+}
+
+// Publish notifies all listeners. Payloads are small, well under NOTIFY's 8000 byte limit.
+func (n *PostgresSubscriptionChangeNotifier) Publish(ctx context.Context, change *entity.SubscriptionChange) error {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+
+	_, err = n.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, SubscriptionChangeChannel, string(payload))
+	return err
+}
+
+// Start listens for changes until Stop is called, reconnecting on errors
+func (n *PostgresSubscriptionChangeNotifier) Start(ctx context.Context, deliver func(*entity.SubscriptionChange)) {
+	ctx, n.cancel = context.WithCancel(ctx)
+	go n.run(ctx, deliver)
+}
+
+// Stop stops listening
+func (n *PostgresSubscriptionChangeNotifier) Stop() {
+	if n.cancel != nil {
+		n.cancel()
+		<-n.doneCh
+	}
+}
+
+func (n *PostgresSubscriptionChangeNotifier) run(ctx context.Context, deliver func(*entity.SubscriptionChange)) {
+	defer close(n.doneCh)
+
+	backoff := time.Second
+	for {
+		err := n.listen(ctx, deliver)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("SubscriptionChangeNotifier: listener stopped: %v (retrying in %v)", err, backoff)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+func (n *PostgresSubscriptionChangeNotifier) listen(ctx context.Context, deliver func(*entity.SubscriptionChange)) error {
+	conn, err := n.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection carries LISTEN state, so it is closed instead of returned to the pool
+	defer func() {
+		conn.Conn().Close(context.Background())
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+SubscriptionChangeChannel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var change entity.SubscriptionChange
+		if err := json.Unmarshal([]byte(notification.Payload), &change); err != nil {
+			log.Printf("SubscriptionChangeNotifier: invalid payload: %v", err)
+			continue
+		}
+		deliver(&change)
+	}
+}
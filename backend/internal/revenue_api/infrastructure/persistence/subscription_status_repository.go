@@ -3,16 +3,29 @@ package persistence
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
 )
 
 var ErrSubscriptionStatusNotFound = errors.New("subscription status not found")
 
+// monthsOverdueExpr derives months overdue at read time (see entity.SubscriptionStatus.Derive)
+const monthsOverdueExpr = `COALESCE(GREATEST(0, FLOOR(EXTRACT(EPOCH FROM now() - expected_next_charge_date) / 2592000))::int, 0)`
+
+// subscriptionStatusColumns selects a status with its time-dependent fields derived as of now
+const subscriptionStatusColumns = `id, shopify_gid, app_id, myshopify_domain, shop_name, plan_name, risk_state,
+			(status = 'ACTIVE' AND risk_state = 'SAFE' AND (expected_next_charge_date IS NULL
+				OR now() <= expected_next_charge_date + interval '30 days')) AS is_paid_current_cycle,
+			` + monthsOverdueExpr + ` AS months_overdue,
+			last_successful_charge_date, expected_next_charge_date, status, last_synced_at`
+
 // PostgresSubscriptionStatusRepository implements SubscriptionStatusRepository using PostgreSQL
 type PostgresSubscriptionStatusRepository struct {
 	pool *pgxpool.Pool
@@ -28,15 +41,12 @@ func (r *PostgresSubscriptionStatusRepository) Upsert(ctx context.Context, statu
 	query := `
 		INSERT INTO api_subscription_status (
 			id, shopify_gid, app_id, myshopify_domain, shop_name, plan_name,
-			risk_state, is_paid_current_cycle, months_overdue,
-			last_successful_charge_date, expected_next_charge_date, status, last_synced_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			risk_state, last_successful_charge_date, expected_next_charge_date, status, last_synced_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		ON CONFLICT (shopify_gid) DO UPDATE SET
 			shop_name = EXCLUDED.shop_name,
 			plan_name = EXCLUDED.plan_name,
 			risk_state = EXCLUDED.risk_state,
-			is_paid_current_cycle = EXCLUDED.is_paid_current_cycle,
-			months_overdue = EXCLUDED.months_overdue,
 			last_successful_charge_date = EXCLUDED.last_successful_charge_date,
 			expected_next_charge_date = EXCLUDED.expected_next_charge_date,
 			status = EXCLUDED.status,
@@ -51,8 +61,6 @@ func (r *PostgresSubscriptionStatusRepository) Upsert(ctx context.Context, statu
 		status.ShopName,
 		status.PlanName,
 		string(status.RiskState),
-		status.IsPaidCurrentCycle,
-		status.MonthsOverdue,
 		status.LastSuccessfulChargeDate,
 		status.ExpectedNextChargeDate,
 		status.Status,
@@ -72,15 +80,12 @@ func (r *PostgresSubscriptionStatusRepository) UpsertBatch(ctx context.Context,
 	query := `
 		INSERT INTO api_subscription_status (
 			id, shopify_gid, app_id, myshopify_domain, shop_name, plan_name,
-			risk_state, is_paid_current_cycle, months_overdue,
-			last_successful_charge_date, expected_next_charge_date, status, last_synced_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			risk_state, last_successful_charge_date, expected_next_charge_date, status, last_synced_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		ON CONFLICT (shopify_gid) DO UPDATE SET
 			shop_name = EXCLUDED.shop_name,
 			plan_name = EXCLUDED.plan_name,
 			risk_state = EXCLUDED.risk_state,
-			is_paid_current_cycle = EXCLUDED.is_paid_current_cycle,
-			months_overdue = EXCLUDED.months_overdue,
 			last_successful_charge_date = EXCLUDED.last_successful_charge_date,
 			expected_next_charge_date = EXCLUDED.expected_next_charge_date,
 			status = EXCLUDED.status,
@@ -96,8 +101,6 @@ func (r *PostgresSubscriptionStatusRepository) UpsertBatch(ctx context.Context,
 			status.ShopName,
 			status.PlanName,
 			string(status.RiskState),
-			status.IsPaidCurrentCycle,
-			status.MonthsOverdue,
 			status.LastSuccessfulChargeDate,
 			status.ExpectedNextChargeDate,
 			status.Status,
@@ -120,9 +123,7 @@ func (r *PostgresSubscriptionStatusRepository) UpsertBatch(ctx context.Context,
 // GetByShopifyGID retrieves a subscription status by Shopify GID
 func (r *PostgresSubscriptionStatusRepository) GetByShopifyGID(ctx context.Context, shopifyGID string) (*entity.SubscriptionStatus, error) {
 	query := `
-		SELECT id, shopify_gid, app_id, myshopify_domain, shop_name, plan_name,
-			risk_state, is_paid_current_cycle, months_overdue,
-			last_successful_charge_date, expected_next_charge_date, status, last_synced_at
+		SELECT ` + subscriptionStatusColumns + `
 		FROM api_subscription_status
 		WHERE shopify_gid = $1
 	`
@@ -137,9 +138,7 @@ func (r *PostgresSubscriptionStatusRepository) GetByShopifyGIDs(ctx context.Cont
 	}
 
 	query := `
-		SELECT id, shopify_gid, app_id, myshopify_domain, shop_name, plan_name,
-			risk_state, is_paid_current_cycle, months_overdue,
-			last_successful_charge_date, expected_next_charge_date, status, last_synced_at
+		SELECT ` + subscriptionStatusColumns + `
 		FROM api_subscription_status
 		WHERE shopify_gid = ANY($1)
 	`
@@ -156,9 +155,7 @@ func (r *PostgresSubscriptionStatusRepository) GetByShopifyGIDs(ctx context.Cont
 // GetByDomain retrieves a subscription status by myshopify domain
 func (r *PostgresSubscriptionStatusRepository) GetByDomain(ctx context.Context, appID uuid.UUID, domain string) (*entity.SubscriptionStatus, error) {
 	query := `
-		SELECT id, shopify_gid, app_id, myshopify_domain, shop_name, plan_name,
-			risk_state, is_paid_current_cycle, months_overdue,
-			last_successful_charge_date, expected_next_charge_date, status, last_synced_at
+		SELECT ` + subscriptionStatusColumns + `
 		FROM api_subscription_status
 		WHERE app_id = $1 AND myshopify_domain = $2
 	`
@@ -173,9 +170,7 @@ func (r *PostgresSubscriptionStatusRepository) GetByDomains(ctx context.Context,
 	}
 
 	query := `
-		SELECT id, shopify_gid, app_id, myshopify_domain, shop_name, plan_name,
-			risk_state, is_paid_current_cycle, months_overdue,
-			last_successful_charge_date, expected_next_charge_date, status, last_synced_at
+		SELECT ` + subscriptionStatusColumns + `
 		FROM api_subscription_status
 		WHERE app_id = $1 AND myshopify_domain = ANY($2)
 	`
@@ -192,9 +187,7 @@ func (r *PostgresSubscriptionStatusRepository) GetByDomains(ctx context.Context,
 // GetByAppID retrieves all subscription statuses for an app
 func (r *PostgresSubscriptionStatusRepository) GetByAppID(ctx context.Context, appID uuid.UUID) ([]*entity.SubscriptionStatus, error) {
 	query := `
-		SELECT id, shopify_gid, app_id, myshopify_domain, shop_name, plan_name,
-			risk_state, is_paid_current_cycle, months_overdue,
-			last_successful_charge_date, expected_next_charge_date, status, last_synced_at
+		SELECT ` + subscriptionStatusColumns + `
 		FROM api_subscription_status
 		WHERE app_id = $1
 		ORDER BY COALESCE(shop_name, myshopify_domain)
@@ -212,9 +205,7 @@ func (r *PostgresSubscriptionStatusRepository) GetByAppID(ctx context.Context, a
 // GetByAppIDAndRiskState retrieves subscription statuses filtered by risk state
 func (r *PostgresSubscriptionStatusRepository) GetByAppIDAndRiskState(ctx context.Context, appID uuid.UUID, riskState valueobject.RiskState) ([]*entity.SubscriptionStatus, error) {
 	query := `
-		SELECT id, shopify_gid, app_id, myshopify_domain, shop_name, plan_name,
-			risk_state, is_paid_current_cycle, months_overdue,
-			last_successful_charge_date, expected_next_charge_date, status, last_synced_at
+		SELECT ` + subscriptionStatusColumns + `
 		FROM api_subscription_status
 		WHERE app_id = $1 AND risk_state = $2
 		ORDER BY COALESCE(shop_name, myshopify_domain)
@@ -229,6 +220,49 @@ func (r *PostgresSubscriptionStatusRepository) GetByAppIDAndRiskState(ctx contex
 	return r.scanStatuses(rows)
 }
 
+// FindPage retrieves one keyset-paginated page of subscription statuses
+func (r *PostgresSubscriptionStatusRepository) FindPage(ctx context.Context, q repository.SubscriptionStatusPageQuery) ([]*entity.SubscriptionStatus, error) {
+	if len(q.AppIDs) == 0 {
+		return []*entity.SubscriptionStatus{}, nil
+	}
+
+	query, args := subscriptionPageQuery(q)
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanStatuses(rows)
+}
+
+// Count returns the number of subscription statuses matching the filter
+func (r *PostgresSubscriptionStatusRepository) Count(ctx context.Context, appIDs []uuid.UUID, filter repository.SubscriptionStatusFilter) (int, error) {
+	if len(appIDs) == 0 {
+		return 0, nil
+	}
+
+	conditions, args := subscriptionFilterConditions(appIDs, filter)
+	query := `SELECT COUNT(*) FROM api_subscription_status WHERE ` + strings.Join(conditions, " AND ")
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// DeleteByShopifyGIDs deletes subscription statuses by Shopify GID
+func (r *PostgresSubscriptionStatusRepository) DeleteByShopifyGIDs(ctx context.Context, shopifyGIDs []string) error {
+	if len(shopifyGIDs) == 0 {
+		return nil
+	}
+
+	query := `DELETE FROM api_subscription_status WHERE shopify_gid = ANY($1)`
+	_, err := r.pool.Exec(ctx, query, shopifyGIDs)
+	return err
+}
+
 // DeleteByAppID deletes all subscription statuses for an app
 func (r *PostgresSubscriptionStatusRepository) DeleteByAppID(ctx context.Context, appID uuid.UUID) error {
 	query := `DELETE FROM api_subscription_status WHERE app_id = $1`
@@ -314,3 +348,72 @@ func (r *PostgresSubscriptionStatusRepository) scanStatuses(rows pgx.Rows) ([]*e
 
 	return statuses, rows.Err()
 }
+
+// subscriptionSortColumn returns the SQL sort expression and the type the cursor value is cast to.
+// Nullable dates sort as infinity so keyset comparisons never see NULL.
+func subscriptionSortColumn(field repository.SubscriptionStatusSortField) (string, string) {
+	switch field {
+	case repository.SortByMonthsOverdue:
+		return monthsOverdueExpr, "int"
+	case repository.SortByExpectedNextChargeDate:
+		return "COALESCE(expected_next_charge_date, 'infinity'::timestamptz)", "timestamptz"
+	case repository.SortByLastSuccessfulChargeDate:
+		return "COALESCE(last_successful_charge_date, 'infinity'::timestamptz)", "timestamptz"
+	default:
+		return "myshopify_domain", "text"
+	}
+}
+
+// subscriptionPageQuery builds the keyset page query for FindPage. Rows are ordered by the
+// sort column and then id, and a cursor resumes strictly after its (sort value, id) pair, so
+// rows that tie on the sort column are neither skipped nor repeated across pages.
+func subscriptionPageQuery(q repository.SubscriptionStatusPageQuery) (string, []interface{}) {
+	sortExpr, castType := subscriptionSortColumn(q.OrderBy)
+	conditions, args := subscriptionFilterConditions(q.AppIDs, q.Filter)
+
+	direction, comparator := "ASC", ">"
+	if q.Descending {
+		direction, comparator = "DESC", "<"
+	}
+
+	if q.After != nil {
+		args = append(args, q.After.SortValue, q.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d::%s, $%d)",
+			sortExpr, comparator, len(args)-1, castType, len(args)))
+	}
+
+	args = append(args, q.Limit)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM api_subscription_status
+		WHERE %s
+		ORDER BY %s %s, id %s
+		LIMIT $%d
+	`, subscriptionStatusColumns, strings.Join(conditions, " AND "), sortExpr, direction, direction, len(args))
+
+	return query, args
+}
+
+// subscriptionFilterConditions builds the WHERE conditions and positional args for a filter
+func subscriptionFilterConditions(appIDs []uuid.UUID, filter repository.SubscriptionStatusFilter) ([]string, []interface{}) {
+	args := []interface{}{appIDs}
+	conditions := []string{"app_id = ANY($1)"}
+
+	if filter.RiskState != nil {
+		args = append(args, filter.RiskState.String())
+		conditions = append(conditions, fmt.Sprintf("risk_state = $%d", len(args)))
+	}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.IsOverdue != nil {
+		if *filter.IsOverdue {
+			conditions = append(conditions, monthsOverdueExpr+" > 0")
+		} else {
+			conditions = append(conditions, monthsOverdueExpr+" = 0")
+		}
+	}
+
+	return conditions, args
+}
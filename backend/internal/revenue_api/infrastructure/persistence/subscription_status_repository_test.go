@@ -0,0 +1,126 @@
+package persistence
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
+)
+
+func TestSubscriptionPageQuery(t *testing.T) {
+	appIDs := []uuid.UUID{uuid.New()}
+	cursorID := uuid.New()
+	atRisk := valueobject.RiskStateOneCycleMissed
+	overdue := true
+
+	tests := []struct {
+		name         string
+		query        repository.SubscriptionStatusPageQuery
+		wantContains []string
+		wantMissing  []string
+		wantArgs     []interface{}
+	}{
+		{
+			name: "first page orders by sort column then id",
+			query: repository.SubscriptionStatusPageQuery{
+				AppIDs: appIDs,
+				Limit:  51,
+			},
+			wantContains: []string{
+				"WHERE app_id = ANY($1)",
+				"ORDER BY myshopify_domain ASC, id ASC",
+				"LIMIT $2",
+			},
+			wantMissing: []string{"(myshopify_domain, id)"},
+			wantArgs:    []interface{}{appIDs, 51},
+		},
+		{
+			name: "cursor resumes after sort value and id",
+			query: repository.SubscriptionStatusPageQuery{
+				AppIDs: appIDs,
+				After:  &repository.SubscriptionStatusCursor{SortValue: "b.myshopify.com", ID: cursorID},
+				Limit:  3,
+			},
+			wantContains: []string{
+				"(myshopify_domain, id) > ($2::text, $3)",
+				"ORDER BY myshopify_domain ASC, id ASC",
+				"LIMIT $4",
+			},
+			wantArgs: []interface{}{appIDs, "b.myshopify.com", cursorID, 3},
+		},
+		{
+			name: "descending cursor compares backwards",
+			query: repository.SubscriptionStatusPageQuery{
+				AppIDs:     appIDs,
+				OrderBy:    repository.SortByMonthsOverdue,
+				Descending: true,
+				After:      &repository.SubscriptionStatusCursor{SortValue: "2", ID: cursorID},
+				Limit:      11,
+			},
+			wantContains: []string{
+				"(" + monthsOverdueExpr + ", id) < ($2::int, $3)",
+				"ORDER BY " + monthsOverdueExpr + " DESC, id DESC",
+				"LIMIT $4",
+			},
+			wantArgs: []interface{}{appIDs, "2", cursorID, 11},
+		},
+		{
+			name: "nullable dates sort as infinity",
+			query: repository.SubscriptionStatusPageQuery{
+				AppIDs:  appIDs,
+				OrderBy: repository.SortByExpectedNextChargeDate,
+				After:   &repository.SubscriptionStatusCursor{SortValue: "infinity", ID: cursorID},
+				Limit:   2,
+			},
+			wantContains: []string{
+				"(COALESCE(expected_next_charge_date, 'infinity'::timestamptz), id) > ($2::timestamptz, $3)",
+			},
+			wantArgs: []interface{}{appIDs, "infinity", cursorID, 2},
+		},
+		{
+			name: "filter args come before cursor and limit",
+			query: repository.SubscriptionStatusPageQuery{
+				AppIDs: appIDs,
+				Filter: repository.SubscriptionStatusFilter{RiskState: &atRisk, IsOverdue: &overdue},
+				After:  &repository.SubscriptionStatusCursor{SortValue: "a.myshopify.com", ID: cursorID},
+				Limit:  6,
+			},
+			wantContains: []string{
+				"risk_state = $2",
+				monthsOverdueExpr + " > 0",
+				"(myshopify_domain, id) > ($3::text, $4)",
+				"LIMIT $5",
+			},
+			wantArgs: []interface{}{appIDs, atRisk.String(), "a.myshopify.com", cursorID, 6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := subscriptionPageQuery(tt.query)
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(query, want) {
+					t.Errorf("query missing %q:\n%s", want, query)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(query, missing) {
+					t.Errorf("query should not contain %q:\n%s", missing, query)
+				}
+			}
+
+			if len(args) != len(tt.wantArgs) {
+				t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+			}
+			// args[0] is the app ID slice, which can't be compared with !=
+			for i := 1; i < len(args); i++ {
+				if args[i] != tt.wantArgs[i] {
+					t.Errorf("args[%d] = %v, want %v", i, args[i], tt.wantArgs[i])
+				}
+			}
+		})
+	}
+}
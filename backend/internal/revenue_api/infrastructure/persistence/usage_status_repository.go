@@ -3,6 +3,7 @@ package persistence
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -27,14 +28,20 @@ func (r *PostgresUsageStatusRepository) Upsert(ctx context.Context, status *enti
 	query := `
 		INSERT INTO api_usage_status (
 			id, shopify_gid, subscription_shopify_gid, subscription_id,
-			billed, billing_date, amount_cents, description, last_synced_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			billed, billing_date, amount_cents, description, last_synced_at,
+			app_id, source, reported_at, billed_transaction_gid, billed_amount_cents
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''), $14)
 		ON CONFLICT (shopify_gid) DO UPDATE SET
 			billed = EXCLUDED.billed,
 			billing_date = EXCLUDED.billing_date,
 			amount_cents = EXCLUDED.amount_cents,
 			description = EXCLUDED.description,
-			last_synced_at = EXCLUDED.last_synced_at
+			last_synced_at = EXCLUDED.last_synced_at,
+			app_id = COALESCE(EXCLUDED.app_id, api_usage_status.app_id),
+			source = EXCLUDED.source,
+			reported_at = EXCLUDED.reported_at,
+			billed_transaction_gid = EXCLUDED.billed_transaction_gid,
+			billed_amount_cents = EXCLUDED.billed_amount_cents
 	`
 
 	_, err := r.pool.Exec(ctx, query,
@@ -47,6 +54,11 @@ func (r *PostgresUsageStatusRepository) Upsert(ctx context.Context, status *enti
 		status.AmountCents,
 		status.Description,
 		status.LastSyncedAt,
+		nullableUUID(status.AppID),
+		usageSource(status),
+		status.ReportedAt,
+		status.BilledTransactionGID,
+		status.BilledAmountCents,
 	)
 
 	return err
@@ -62,14 +74,20 @@ func (r *PostgresUsageStatusRepository) UpsertBatch(ctx context.Context, statuse
 	query := `
 		INSERT INTO api_usage_status (
 			id, shopify_gid, subscription_shopify_gid, subscription_id,
-			billed, billing_date, amount_cents, description, last_synced_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			billed, billing_date, amount_cents, description, last_synced_at,
+			app_id, source, reported_at, billed_transaction_gid, billed_amount_cents
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''), $14)
 		ON CONFLICT (shopify_gid) DO UPDATE SET
 			billed = EXCLUDED.billed,
 			billing_date = EXCLUDED.billing_date,
 			amount_cents = EXCLUDED.amount_cents,
 			description = EXCLUDED.description,
-			last_synced_at = EXCLUDED.last_synced_at
+			last_synced_at = EXCLUDED.last_synced_at,
+			app_id = COALESCE(EXCLUDED.app_id, api_usage_status.app_id),
+			source = EXCLUDED.source,
+			reported_at = EXCLUDED.reported_at,
+			billed_transaction_gid = EXCLUDED.billed_transaction_gid,
+			billed_amount_cents = EXCLUDED.billed_amount_cents
 	`
 
 	for _, status := range statuses {
@@ -83,6 +101,11 @@ func (r *PostgresUsageStatusRepository) UpsertBatch(ctx context.Context, statuse
 			status.AmountCents,
 			status.Description,
 			status.LastSyncedAt,
+			nullableUUID(status.AppID),
+			usageSource(status),
+			status.ReportedAt,
+			status.BilledTransactionGID,
+			status.BilledAmountCents,
 		)
 	}
 
@@ -102,7 +125,8 @@ func (r *PostgresUsageStatusRepository) UpsertBatch(ctx context.Context, statuse
 func (r *PostgresUsageStatusRepository) GetByShopifyGID(ctx context.Context, shopifyGID string) (*entity.UsageStatus, error) {
 	query := `
 		SELECT id, shopify_gid, subscription_shopify_gid, subscription_id,
-			billed, billing_date, amount_cents, description, last_synced_at
+			billed, billing_date, amount_cents, description, last_synced_at,
+			app_id, source, reported_at, billed_transaction_gid, billed_amount_cents
 		FROM api_usage_status
 		WHERE shopify_gid = $1
 	`
@@ -118,7 +142,8 @@ func (r *PostgresUsageStatusRepository) GetByShopifyGIDs(ctx context.Context, sh
 
 	query := `
 		SELECT id, shopify_gid, subscription_shopify_gid, subscription_id,
-			billed, billing_date, amount_cents, description, last_synced_at
+			billed, billing_date, amount_cents, description, last_synced_at,
+			app_id, source, reported_at, billed_transaction_gid, billed_amount_cents
 		FROM api_usage_status
 		WHERE shopify_gid = ANY($1)
 	`
@@ -136,7 +161,8 @@ func (r *PostgresUsageStatusRepository) GetByShopifyGIDs(ctx context.Context, sh
 func (r *PostgresUsageStatusRepository) GetBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*entity.UsageStatus, error) {
 	query := `
 		SELECT id, shopify_gid, subscription_shopify_gid, subscription_id,
-			billed, billing_date, amount_cents, description, last_synced_at
+			billed, billing_date, amount_cents, description, last_synced_at,
+			app_id, source, reported_at, billed_transaction_gid, billed_amount_cents
 		FROM api_usage_status
 		WHERE subscription_id = $1
 		ORDER BY last_synced_at DESC
@@ -155,7 +181,8 @@ func (r *PostgresUsageStatusRepository) GetBySubscriptionID(ctx context.Context,
 func (r *PostgresUsageStatusRepository) GetBySubscriptionShopifyGID(ctx context.Context, subscriptionShopifyGID string) ([]*entity.UsageStatus, error) {
 	query := `
 		SELECT id, shopify_gid, subscription_shopify_gid, subscription_id,
-			billed, billing_date, amount_cents, description, last_synced_at
+			billed, billing_date, amount_cents, description, last_synced_at,
+			app_id, source, reported_at, billed_transaction_gid, billed_amount_cents
 		FROM api_usage_status
 		WHERE subscription_shopify_gid = $1
 		ORDER BY last_synced_at DESC
@@ -174,7 +201,8 @@ func (r *PostgresUsageStatusRepository) GetBySubscriptionShopifyGID(ctx context.
 func (r *PostgresUsageStatusRepository) GetUnbilledBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*entity.UsageStatus, error) {
 	query := `
 		SELECT id, shopify_gid, subscription_shopify_gid, subscription_id,
-			billed, billing_date, amount_cents, description, last_synced_at
+			billed, billing_date, amount_cents, description, last_synced_at,
+			app_id, source, reported_at, billed_transaction_gid, billed_amount_cents
 		FROM api_usage_status
 		WHERE subscription_id = $1 AND billed = false
 		ORDER BY last_synced_at DESC
@@ -189,6 +217,26 @@ func (r *PostgresUsageStatusRepository) GetUnbilledBySubscriptionID(ctx context.
 	return r.scanStatuses(rows)
 }
 
+// GetReportedByAppID retrieves an app's reported usage records created in [from, to), oldest first
+func (r *PostgresUsageStatusRepository) GetReportedByAppID(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.UsageStatus, error) {
+	query := `
+		SELECT id, shopify_gid, subscription_shopify_gid, subscription_id,
+			billed, billing_date, amount_cents, description, last_synced_at,
+			app_id, source, reported_at, billed_transaction_gid, billed_amount_cents
+		FROM api_usage_status
+		WHERE app_id = $1 AND source = 'reported' AND reported_at >= $2 AND reported_at < $3
+		ORDER BY reported_at, shopify_gid
+	`
+
+	rows, err := r.pool.Query(ctx, query, appID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanStatuses(rows)
+}
+
 // DeleteBySubscriptionID deletes all usage statuses for a subscription
 func (r *PostgresUsageStatusRepository) DeleteBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) error {
 	query := `DELETE FROM api_usage_status WHERE subscription_id = $1`
@@ -196,9 +244,37 @@ func (r *PostgresUsageStatusRepository) DeleteBySubscriptionID(ctx context.Conte
 	return err
 }
 
+// DeleteByShopifyGIDs deletes usage statuses by Shopify GIDs
+func (r *PostgresUsageStatusRepository) DeleteByShopifyGIDs(ctx context.Context, shopifyGIDs []string) error {
+	if len(shopifyGIDs) == 0 {
+		return nil
+	}
+	query := `DELETE FROM api_usage_status WHERE shopify_gid = ANY($1)`
+	_, err := r.pool.Exec(ctx, query, shopifyGIDs)
+	return err
+}
+
+// nullableUUID maps the zero UUID to NULL
+func nullableUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
+// usageSource defaults statuses built without a source to ledger records
+func usageSource(status *entity.UsageStatus) string {
+	if status.Source == "" {
+		return entity.UsageSourceLedger
+	}
+	return status.Source
+}
+
 func (r *PostgresUsageStatusRepository) scanStatus(row pgx.Row) (*entity.UsageStatus, error) {
 	var status entity.UsageStatus
 	var description *string
+	var appID *uuid.UUID
+	var billedTransactionGID *string
 
 	err := row.Scan(
 		&status.ID,
@@ -210,6 +286,11 @@ func (r *PostgresUsageStatusRepository) scanStatus(row pgx.Row) (*entity.UsageSt
 		&status.AmountCents,
 		&description,
 		&status.LastSyncedAt,
+		&appID,
+		&status.Source,
+		&status.ReportedAt,
+		&billedTransactionGID,
+		&status.BilledAmountCents,
 	)
 
 	if err != nil {
@@ -222,6 +303,12 @@ func (r *PostgresUsageStatusRepository) scanStatus(row pgx.Row) (*entity.UsageSt
 	if description != nil {
 		status.Description = *description
 	}
+	if appID != nil {
+		status.AppID = *appID
+	}
+	if billedTransactionGID != nil {
+		status.BilledTransactionGID = *billedTransactionGID
+	}
 
 	return &status, nil
 }
@@ -232,6 +319,8 @@ func (r *PostgresUsageStatusRepository) scanStatuses(rows pgx.Rows) ([]*entity.U
 	for rows.Next() {
 		var status entity.UsageStatus
 		var description *string
+		var appID *uuid.UUID
+		var billedTransactionGID *string
 
 		err := rows.Scan(
 			&status.ID,
@@ -243,6 +332,11 @@ func (r *PostgresUsageStatusRepository) scanStatuses(rows pgx.Rows) ([]*entity.U
 			&status.AmountCents,
 			&description,
 			&status.LastSyncedAt,
+			&appID,
+			&status.Source,
+			&status.ReportedAt,
+			&billedTransactionGID,
+			&status.BilledAmountCents,
 		)
 		if err != nil {
 			return nil, err
@@ -251,6 +345,12 @@ func (r *PostgresUsageStatusRepository) scanStatuses(rows pgx.Rows) ([]*entity.U
 		if description != nil {
 			status.Description = *description
 		}
+		if appID != nil {
+			status.AppID = *appID
+		}
+		if billedTransactionGID != nil {
+			status.BilledTransactionGID = *billedTransactionGID
+		}
 		statuses = append(statuses, &status)
 	}
 
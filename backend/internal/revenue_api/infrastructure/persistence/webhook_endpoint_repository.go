@@ -0,0 +1,371 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+var (
+	ErrWebhookEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+const webhookEndpointColumns = `id, user_id, url, description, encrypted_secret,
+	topics, enabled, consecutive_failures, disabled_at, disabled_reason, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, endpoint_id, event_id, topic, payload, status, attempts,
+	next_attempt_at, last_attempt_at, response_status, response_body, last_error,
+	redelivery_of, created_at, delivered_at`
+
+// PostgresWebhookEndpointRepository implements WebhookEndpointRepository using PostgreSQL
+type PostgresWebhookEndpointRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresWebhookEndpointRepository creates a new PostgresWebhookEndpointRepository
+func NewPostgresWebhookEndpointRepository(pool *pgxpool.Pool) *PostgresWebhookEndpointRepository {
+	return &PostgresWebhookEndpointRepository{pool: pool}
+}
+
+// Create persists a new endpoint
+func (r *PostgresWebhookEndpointRepository) Create(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	query := `
+		INSERT INTO api_webhook_endpoints (
+			id, user_id, url, description, encrypted_secret, topics, enabled,
+			consecutive_failures, disabled_at, disabled_reason, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		endpoint.ID,
+		endpoint.UserID,
+		endpoint.URL,
+		endpoint.Description,
+		endpoint.EncryptedSecret,
+		endpoint.Topics,
+		endpoint.Enabled,
+		endpoint.ConsecutiveFailures,
+		endpoint.DisabledAt,
+		endpoint.DisabledReason,
+		endpoint.CreatedAt,
+		endpoint.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves an endpoint by ID
+func (r *PostgresWebhookEndpointRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookEndpoint, error) {
+	query := `SELECT ` + webhookEndpointColumns + ` FROM api_webhook_endpoints WHERE id = $1`
+
+	endpoint, err := scanWebhookEndpoint(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrWebhookEndpointNotFound
+	}
+	return endpoint, err
+}
+
+// GetByUserID retrieves all endpoints of a user, newest first
+func (r *PostgresWebhookEndpointRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WebhookEndpoint, error) {
+	query := `
+		SELECT ` + webhookEndpointColumns + `
+		FROM api_webhook_endpoints
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	return r.query(ctx, query, userID)
+}
+
+// GetEnabledByUserIDAndTopic retrieves the enabled endpoints of a user subscribed to topic
+func (r *PostgresWebhookEndpointRepository) GetEnabledByUserIDAndTopic(ctx context.Context, userID uuid.UUID, topic string) ([]*entity.WebhookEndpoint, error) {
+	query := `
+		SELECT ` + webhookEndpointColumns + `
+		FROM api_webhook_endpoints
+		WHERE user_id = $1 AND enabled AND $2 = ANY(topics)
+		ORDER BY created_at
+	`
+
+	return r.query(ctx, query, userID, topic)
+}
+
+// Update saves URL, topics, encrypted secret, enabled state and failure counters
+func (r *PostgresWebhookEndpointRepository) Update(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	query := `
+		UPDATE api_webhook_endpoints
+		SET url = $2, description = $3,
+			encrypted_secret = $4, topics = $5, enabled = $6,
+			consecutive_failures = $7, disabled_at = $8, disabled_reason = $9, updated_at = $10
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		endpoint.ID,
+		endpoint.URL,
+		endpoint.Description,
+		endpoint.EncryptedSecret,
+		endpoint.Topics,
+		endpoint.Enabled,
+		endpoint.ConsecutiveFailures,
+		endpoint.DisabledAt,
+		endpoint.DisabledReason,
+		endpoint.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrWebhookEndpointNotFound
+	}
+
+	return nil
+}
+
+// Delete removes an endpoint; deliveries are removed by ON DELETE CASCADE
+func (r *PostgresWebhookEndpointRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM api_webhook_endpoints WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrWebhookEndpointNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookEndpointRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.WebhookEndpoint, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var endpoints []*entity.WebhookEndpoint
+	for rows.Next() {
+		endpoint, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, rows.Err()
+}
+
+func scanWebhookEndpoint(row pgx.Row) (*entity.WebhookEndpoint, error) {
+	var e entity.WebhookEndpoint
+
+	err := row.Scan(
+		&e.ID,
+		&e.UserID,
+		&e.URL,
+		&e.Description,
+		&e.EncryptedSecret,
+		&e.Topics,
+		&e.Enabled,
+		&e.ConsecutiveFailures,
+		&e.DisabledAt,
+		&e.DisabledReason,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// PostgresWebhookDeliveryRepository implements WebhookDeliveryRepository using PostgreSQL
+type PostgresWebhookDeliveryRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresWebhookDeliveryRepository creates a new PostgresWebhookDeliveryRepository
+func NewPostgresWebhookDeliveryRepository(pool *pgxpool.Pool) *PostgresWebhookDeliveryRepository {
+	return &PostgresWebhookDeliveryRepository{pool: pool}
+}
+
+// Create persists a new delivery
+func (r *PostgresWebhookDeliveryRepository) Create(ctx context.Context, d *entity.WebhookDelivery) error {
+	query := `
+		INSERT INTO api_webhook_deliveries (` + webhookDeliveryColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		d.ID,
+		d.EndpointID,
+		d.EventID,
+		d.Topic,
+		d.Payload,
+		string(d.Status),
+		d.Attempts,
+		d.NextAttemptAt,
+		d.LastAttemptAt,
+		nullableStatus(d.ResponseStatus),
+		d.ResponseBody,
+		d.LastError,
+		d.RedeliveryOf,
+		d.CreatedAt,
+		d.DeliveredAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves a delivery by ID
+func (r *PostgresWebhookDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM api_webhook_deliveries WHERE id = $1`
+
+	delivery, err := scanWebhookDelivery(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return delivery, err
+}
+
+// GetByEndpointID retrieves the most recent deliveries of an endpoint, newest first
+func (r *PostgresWebhookDeliveryRepository) GetByEndpointID(ctx context.Context, endpointID uuid.UUID, limit int) ([]*entity.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM api_webhook_deliveries
+		WHERE endpoint_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	return r.query(ctx, query, endpointID, limit)
+}
+
+// ClaimDue leases up to limit due pending deliveries. SKIP LOCKED keeps
+// concurrent dispatchers from claiming the same rows.
+func (r *PostgresWebhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	query := `
+		UPDATE api_webhook_deliveries
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM api_webhook_deliveries
+			WHERE status = 'PENDING' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+
+	return r.query(ctx, query, now, now.Add(lease), limit)
+}
+
+// Update saves the outcome of a delivery attempt
+func (r *PostgresWebhookDeliveryRepository) Update(ctx context.Context, d *entity.WebhookDelivery) error {
+	query := `
+		UPDATE api_webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_attempt_at = $5,
+			response_status = $6, response_body = $7, last_error = $8, delivered_at = $9
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		d.ID,
+		string(d.Status),
+		d.Attempts,
+		d.NextAttemptAt,
+		d.LastAttemptAt,
+		nullableStatus(d.ResponseStatus),
+		d.ResponseBody,
+		d.LastError,
+		d.DeliveredAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrWebhookDeliveryNotFound
+	}
+
+	return nil
+}
+
+// FailPendingByEndpointID marks all pending deliveries of an endpoint as failed
+func (r *PostgresWebhookDeliveryRepository) FailPendingByEndpointID(ctx context.Context, endpointID uuid.UUID, reason string) error {
+	query := `
+		UPDATE api_webhook_deliveries
+		SET status = 'FAILED', next_attempt_at = NULL, last_error = $2
+		WHERE endpoint_id = $1 AND status = 'PENDING'
+	`
+
+	_, err := r.pool.Exec(ctx, query, endpointID, reason)
+	return err
+}
+
+func (r *PostgresWebhookDeliveryRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.WebhookDelivery, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*entity.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func scanWebhookDelivery(row pgx.Row) (*entity.WebhookDelivery, error) {
+	var d entity.WebhookDelivery
+	var status string
+	var responseStatus *int
+
+	err := row.Scan(
+		&d.ID,
+		&d.EndpointID,
+		&d.EventID,
+		&d.Topic,
+		&d.Payload,
+		&status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.LastAttemptAt,
+		&responseStatus,
+		&d.ResponseBody,
+		&d.LastError,
+		&d.RedeliveryOf,
+		&d.CreatedAt,
+		&d.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Status = entity.WebhookDeliveryStatus(status)
+	if responseStatus != nil {
+		d.ResponseStatus = *responseStatus
+	}
+
+	return &d, nil
+}
+
+// nullableStatus stores "no response" as NULL rather than 0
+func nullableStatus(status int) *int {
+	if status == 0 {
+		return nil
+	}
+	return &status
+}
@@ -0,0 +1,118 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/graphql/executor"
+)
+
+// rootResolvers binds each Query field in schema.graphql to its queryResolver method.
+// Arguments arrive coerced by the executor (Int -> int, enums -> string, inputs -> map).
+func (r *Resolver) rootResolvers() map[string]executor.ResolverFunc {
+	q := r.Query()
+
+	return map[string]executor.ResolverFunc{
+		"subscription": func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return wrap(q.Subscription(ctx, argString(args, "shopifyGid")))
+		},
+		"subscriptionByDomain": func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return wrap(q.SubscriptionByDomain(ctx, argString(args, "domain")))
+		},
+		"subscriptions": func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return wrap(q.Subscriptions(ctx, argStrings(args, "shopifyGids")))
+		},
+		"subscriptionsConnection": func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			var filter *SubscriptionFilter
+			if m, ok := args["filter"].(map[string]interface{}); ok {
+				filter = &SubscriptionFilter{
+					AppID:     optString(m, "appId"),
+					IsOverdue: optBool(m, "isOverdue"),
+				}
+				if s := optString(m, "riskState"); s != nil {
+					rs := RiskState(*s)
+					filter.RiskState = &rs
+				}
+				if s := optString(m, "status"); s != nil {
+					st := SubscriptionStatusEnum(*s)
+					filter.Status = &st
+				}
+			}
+
+			var first *int
+			if n, ok := args["first"].(int); ok {
+				first = &n
+			}
+
+			var orderBy *SubscriptionOrder
+			if m, ok := args["orderBy"].(map[string]interface{}); ok {
+				orderBy = &SubscriptionOrder{Field: SubscriptionOrderField(argString(m, "field"))}
+				if s := optString(m, "direction"); s != nil {
+					direction := OrderDirection(*s)
+					orderBy.Direction = &direction
+				}
+			}
+
+			return wrap(q.SubscriptionsConnection(ctx, filter, first, optString(args, "after"), orderBy))
+		},
+		"usage": func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return wrap(q.Usage(ctx, argString(args, "shopifyGid")))
+		},
+		"usages": func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return wrap(q.Usages(ctx, argStrings(args, "shopifyGids")))
+		},
+	}
+}
+
+// wrap adapts a typed resolver result, giving service errors a response code.
+// A typed nil result is completed as null by the executor.
+func wrap(result interface{}, err error) (interface{}, error) {
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	return result, nil
+}
+
+func toResolverError(err error) error {
+	var gqlErr *GraphQLError
+	switch {
+	case errors.As(err, &gqlErr):
+		return err
+	case errors.Is(err, service.ErrSubscriptionNotFound), errors.Is(err, service.ErrUsageNotFound):
+		return &GraphQLError{Message: err.Error(), Code: "NOT_FOUND"}
+	case errors.Is(err, service.ErrAppAccessDenied), errors.Is(err, service.ErrInsufficientScope):
+		return &GraphQLError{Message: err.Error(), Code: "FORBIDDEN"}
+	}
+	return &GraphQLError{Message: "internal error", Code: "INTERNAL_SERVER_ERROR"}
+}
+
+func argString(args map[string]interface{}, name string) string {
+	s, _ := args[name].(string)
+	return s
+}
+
+func argStrings(args map[string]interface{}, name string) []string {
+	list, _ := args[name].([]interface{})
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func optString(args map[string]interface{}, name string) *string {
+	if s, ok := args[name].(string); ok {
+		return &s
+	}
+	return nil
+}
+
+func optBool(args map[string]interface{}, name string) *bool {
+	if b, ok := args[name].(bool); ok {
+		return &b
+	}
+	return nil
+}
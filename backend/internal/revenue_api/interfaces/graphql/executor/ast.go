@@ -0,0 +1,228 @@
+package executor
+
+import (
+	"strings"
+)
+
+// Document is a parsed executable GraphQL document
+type Document struct {
+	Operations []*OperationDefinition
+	Fragments  []*FragmentDefinition
+}
+
+// Fragment returns the fragment definition with the given name, or nil
+func (d *Document) Fragment(name string) *FragmentDefinition {
+	for _, f := range d.Fragments {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// OperationDefinition is a query, mutation or subscription
+type OperationDefinition struct {
+	Operation           string // query, mutation, subscription
+	Name                string
+	VariableDefinitions []*VariableDefinition
+	Directives          []*Directive
+	SelectionSet        []Selection
+	Loc                 Location
+}
+
+// VariableDefinition declares an operation variable
+type VariableDefinition struct {
+	Name         string
+	Type         *TypeRef
+	DefaultValue *Value
+	Loc          Location
+}
+
+// FragmentDefinition is a named fragment
+type FragmentDefinition struct {
+	Name          string
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+	Loc           Location
+}
+
+// Selection is a *Field, *FragmentSpread or *InlineFragment
+type Selection interface {
+	location() Location
+}
+
+// Field is a field selection
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	Directives   []*Directive
+	SelectionSet []Selection
+	Loc          Location
+}
+
+// ResponseKey is the alias if set, otherwise the field name
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Argument returns the named argument, or nil
+func (f *Field) Argument(name string) *Argument {
+	for _, a := range f.Arguments {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// FragmentSpread is a ...FragmentName selection
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+	Loc        Location
+}
+
+// InlineFragment is a ... on Type { } selection
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+	Loc           Location
+}
+
+func (f *Field) location() Location          { return f.Loc }
+func (f *FragmentSpread) location() Location { return f.Loc }
+func (f *InlineFragment) location() Location { return f.Loc }
+
+// Argument is a name: value pair on a field or directive
+type Argument struct {
+	Name  string
+	Value *Value
+	Loc   Location
+}
+
+// Directive is an @name(args) annotation
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+	Loc       Location
+}
+
+// ValueKind identifies the kind of a literal value
+type ValueKind int
+
+const (
+	VariableValue ValueKind = iota
+	IntValue
+	FloatValue
+	StringValue
+	BooleanValue
+	NullValue
+	EnumValue
+	ListValue
+	ObjectValue
+)
+
+// Value is a literal or variable reference
+type Value struct {
+	Kind   ValueKind
+	Raw    string // Variable name, scalar text, or enum name
+	List   []*Value
+	Fields []*ObjectField
+	Loc    Location
+}
+
+// ObjectField is a field of an input object literal
+type ObjectField struct {
+	Name  string
+	Value *Value
+	Loc   Location
+}
+
+// String renders the value back to GraphQL syntax
+func (v *Value) String() string {
+	switch v.Kind {
+	case VariableValue:
+		return "$" + v.Raw
+	case StringValue:
+		return quoteString(v.Raw)
+	case NullValue:
+		return "null"
+	case ListValue:
+		parts := make([]string, len(v.List))
+		for i, item := range v.List {
+			parts[i] = item.String()
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case ObjectValue:
+		parts := make([]string, len(v.Fields))
+		for i, f := range v.Fields {
+			parts[i] = f.Name + ": " + f.Value.String()
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	default:
+		return v.Raw
+	}
+}
+
+// TypeRef is a named, list or non-null type reference
+type TypeRef struct {
+	Name    string   // Set for named types
+	Elem    *TypeRef // Set for list types
+	NonNull bool
+}
+
+// NamedType returns the innermost type name
+func (t *TypeRef) NamedType() string {
+	for t.Elem != nil {
+		t = t.Elem
+	}
+	return t.Name
+}
+
+// String renders the type in GraphQL syntax, e.g. [ID!]!
+func (t *TypeRef) String() string {
+	s := t.Name
+	if t.Elem != nil {
+		s = "[" + t.Elem.String() + "]"
+	}
+	if t.NonNull {
+		s += "!"
+	}
+	return s
+}
+
+// Nullable returns a copy of the type without the outer non-null wrapper
+func (t *TypeRef) Nullable() *TypeRef {
+	cp := *t
+	cp.NonNull = false
+	return &cp
+}
+
+func quoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
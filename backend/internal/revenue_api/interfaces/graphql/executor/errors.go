@@ -0,0 +1,85 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Location is a 1-based line/column position in a GraphQL document
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error is a GraphQL error as serialized in the response "errors" list
+type Error struct {
+	Message    string                 `json:"message"`
+	Locations  []Location             `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ExtendedError is implemented by resolver errors that carry response extensions
+// (e.g. {"code": "FORBIDDEN"})
+type ExtendedError interface {
+	error
+	Extensions() map[string]interface{}
+}
+
+// Error codes set in extensions.code for request-level failures
+const (
+	CodeParseFailed             = "GRAPHQL_PARSE_FAILED"
+	CodeValidationFailed        = "GRAPHQL_VALIDATION_FAILED"
+	CodeBadUserInput            = "BAD_USER_INPUT"
+	CodeQueryTooDeep            = "QUERY_TOO_DEEP"
+	CodeQueryTooComplex         = "QUERY_TOO_COMPLEX"
+	CodePersistedQueryNotFound  = "PERSISTED_QUERY_NOT_FOUND"
+	CodePersistedQueryMismatch  = "PERSISTED_QUERY_HASH_MISMATCH"
+	CodeOperationNotSupported   = "OPERATION_NOT_SUPPORTED"
+	CodeInternalServerError     = "INTERNAL_SERVER_ERROR"
+	persistedQueryNotFoundError = "PersistedQueryNotFound"
+)
+
+func newError(code string, locs []Location, format string, args ...interface{}) *Error {
+	return &Error{
+		Message:    fmt.Sprintf(format, args...),
+		Locations:  locs,
+		Extensions: map[string]interface{}{"code": code},
+	}
+}
+
+func syntaxError(loc Location, format string, args ...interface{}) *Error {
+	return newError(CodeParseFailed, []Location{loc}, "Syntax Error: "+format, args...)
+}
+
+func validationError(loc Location, format string, args ...interface{}) *Error {
+	return newError(CodeValidationFailed, []Location{loc}, format, args...)
+}
+
+// fieldError converts a resolver error into a located field error
+func fieldError(err error, loc Location, path []interface{}) *Error {
+	var gqlErr *Error
+	if errors.As(err, &gqlErr) {
+		out := *gqlErr
+		if out.Locations == nil {
+			out.Locations = []Location{loc}
+		}
+		out.Path = path
+		return &out
+	}
+
+	out := &Error{
+		Message:   err.Error(),
+		Locations: []Location{loc},
+		Path:      path,
+	}
+	var extErr ExtendedError
+	if errors.As(err, &extErr) {
+		out.Extensions = extErr.Extensions()
+	}
+	return out
+}
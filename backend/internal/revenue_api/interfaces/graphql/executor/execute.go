@@ -0,0 +1,534 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ResolverFunc resolves a root Query field from its coerced arguments
+type ResolverFunc func(ctx context.Context, args map[string]interface{}) (interface{}, error)
+
+// FieldResolver can be implemented by resolved objects that compute their own fields.
+// Other values are resolved by map key or by struct field (json tag, then name).
+type FieldResolver interface {
+	ResolveField(ctx context.Context, field string, args map[string]interface{}) (interface{}, error)
+}
+
+// Request is a GraphQL request
+type Request struct {
+	Query         string
+	OperationName string
+	Variables     map[string]interface{}
+}
+
+// Response is a GraphQL response. Data is omitted when the request failed before execution.
+type Response struct {
+	Data       *OrderedMap
+	Errors     []*Error
+	Extensions map[string]interface{}
+
+	executed bool
+}
+
+// MarshalJSON writes "data" (null if execution started but the root was nulled) and "errors"
+func (r *Response) MarshalJSON() ([]byte, error) {
+	out := &OrderedMap{}
+	if len(r.Errors) > 0 {
+		out.Set("errors", r.Errors)
+	}
+	if r.executed {
+		if r.Data != nil {
+			out.Set("data", r.Data)
+		} else {
+			out.Set("data", nil)
+		}
+	}
+	if len(r.Extensions) > 0 {
+		out.Set("extensions", r.Extensions)
+	}
+	return json.Marshal(out)
+}
+
+// Executor validates and executes queries against a schema with Go resolvers
+type Executor struct {
+	schema    *Schema
+	resolvers map[string]ResolverFunc
+	limits    Limits
+}
+
+// New creates an executor. Every Query field must have a resolver.
+func New(schema *Schema, resolvers map[string]ResolverFunc, limits Limits) (*Executor, error) {
+	for _, f := range schema.Type(schema.QueryType).Fields {
+		if resolvers[f.Name] == nil {
+			return nil, fmt.Errorf("no resolver for %s.%s", schema.QueryType, f.Name)
+		}
+	}
+	return &Executor{schema: schema, resolvers: resolvers, limits: limits}, nil
+}
+
+// WithLimits returns a copy of the executor using different limits
+func (e *Executor) WithLimits(limits Limits) *Executor {
+	cp := *e
+	cp.limits = limits
+	return &cp
+}
+
+// Schema returns the executor's schema
+func (e *Executor) Schema() *Schema {
+	return e.schema
+}
+
+// Execute parses, validates and executes a request
+func (e *Executor) Execute(ctx context.Context, req Request) *Response {
+	doc, err := ParseQuery(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{asError(err)}}
+	}
+	return e.ExecuteDocument(ctx, doc, req)
+}
+
+// ExecuteDocument validates and executes an already-parsed document
+func (e *Executor) ExecuteDocument(ctx context.Context, doc *Document, req Request) *Response {
+	if errs := Validate(e.schema, doc); len(errs) > 0 {
+		return &Response{Errors: errs}
+	}
+
+	op, opErr := selectOperation(doc, req.OperationName)
+	if opErr != nil {
+		return &Response{Errors: []*Error{opErr}}
+	}
+
+	vars, varErr := coerceVariables(e.schema, op, req.Variables)
+	if varErr != nil {
+		return &Response{Errors: []*Error{varErr}}
+	}
+
+	if limitErr := checkLimits(e.schema, doc, op, vars, e.limits); limitErr != nil {
+		return &Response{Errors: []*Error{limitErr}}
+	}
+
+	exec := &execution{executor: e, doc: doc, vars: vars}
+	root := e.schema.Type(e.schema.QueryType)
+	data, st := exec.executeSelectionSet(ctx, root, nil, op.SelectionSet, []interface{}{})
+
+	resp := &Response{Errors: exec.errors, executed: true}
+	if st == statusOK {
+		resp.Data = data
+	}
+	return resp
+}
+
+func asError(err error) *Error {
+	if gqlErr, ok := err.(*Error); ok {
+		return gqlErr
+	}
+	return &Error{Message: err.Error()}
+}
+
+func selectOperation(doc *Document, name string) (*OperationDefinition, *Error) {
+	if name == "" {
+		if len(doc.Operations) != 1 {
+			return nil, newError(CodeBadUserInput, nil, "Must provide operation name if query contains multiple operations.")
+		}
+		return doc.Operations[0], nil
+	}
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, newError(CodeBadUserInput, nil, "Unknown operation named %q.", name)
+}
+
+// completionStatus tracks null propagation: a null in a non-null position is reported once
+// and propagates to the nearest nullable parent
+type completionStatus int
+
+const (
+	statusOK        completionStatus = iota
+	statusNulled                     // Null because of an error that has already been reported
+	statusPropagate                  // Null in a non-null position; the parent must become null
+)
+
+type execution struct {
+	executor *Executor
+	doc      *Document
+	vars     map[string]interface{}
+
+	mu     sync.Mutex
+	errors []*Error
+}
+
+func (ex *execution) report(err *Error) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	ex.errors = append(ex.errors, err)
+}
+
+type collectedField struct {
+	key    string
+	fields []*Field
+}
+
+// collectFields groups selections by response key, applying @skip/@include and type conditions
+func (ex *execution) collectFields(objectType *Type, selections []Selection, collected *[]*collectedField, index map[string]*collectedField, visited map[string]bool) {
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *Field:
+			if !ex.shouldInclude(s.Directives) {
+				continue
+			}
+			key := s.ResponseKey()
+			if cf, ok := index[key]; ok {
+				cf.fields = append(cf.fields, s)
+				continue
+			}
+			cf := &collectedField{key: key, fields: []*Field{s}}
+			index[key] = cf
+			*collected = append(*collected, cf)
+		case *InlineFragment:
+			if !ex.shouldInclude(s.Directives) || !ex.fragmentApplies(objectType, s.TypeCondition) {
+				continue
+			}
+			ex.collectFields(objectType, s.SelectionSet, collected, index, visited)
+		case *FragmentSpread:
+			if visited[s.Name] || !ex.shouldInclude(s.Directives) {
+				continue
+			}
+			visited[s.Name] = true
+			frag := ex.doc.Fragment(s.Name)
+			if frag == nil || !ex.fragmentApplies(objectType, frag.TypeCondition) {
+				continue
+			}
+			ex.collectFields(objectType, frag.SelectionSet, collected, index, visited)
+		}
+	}
+}
+
+func (ex *execution) fragmentApplies(objectType *Type, condition string) bool {
+	if condition == "" || condition == objectType.Name {
+		return true
+	}
+	t := ex.executor.schema.Type(condition)
+	return t != nil && ex.executor.schema.isPossibleType(t, objectType)
+}
+
+func (ex *execution) shouldInclude(dirs []*Directive) bool {
+	for _, d := range dirs {
+		if d.Name != "skip" && d.Name != "include" {
+			continue
+		}
+		def := ex.executor.schema.Directive(d.Name)
+		args, err := coerceArguments(ex.executor.schema, def.Args, d.Arguments, ex.vars)
+		if err != nil {
+			continue
+		}
+		cond, _ := args["if"].(bool)
+		if d.Name == "skip" && cond {
+			return false
+		}
+		if d.Name == "include" && !cond {
+			return false
+		}
+	}
+	return true
+}
+
+func (ex *execution) executeSelectionSet(ctx context.Context, objectType *Type, source interface{}, selections []Selection, path []interface{}) (*OrderedMap, completionStatus) {
+	var collected []*collectedField
+	ex.collectFields(objectType, selections, &collected, make(map[string]*collectedField), make(map[string]bool))
+
+	result := &OrderedMap{}
+	for _, cf := range collected {
+		fieldPath := appendPath(path, cf.key)
+		value, st := ex.executeField(ctx, objectType, source, cf.fields, fieldPath)
+		if st == statusPropagate {
+			return nil, statusPropagate
+		}
+		result.Set(cf.key, value)
+	}
+	return result, statusOK
+}
+
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	out := make([]interface{}, len(path)+1)
+	copy(out, path)
+	out[len(path)] = elem
+	return out
+}
+
+func (ex *execution) executeField(ctx context.Context, objectType *Type, source interface{}, fields []*Field, path []interface{}) (interface{}, completionStatus) {
+	field := fields[0]
+	schema := ex.executor.schema
+
+	def := fieldDefinition(schema, objectType, field.Name)
+	if def == nil {
+		return nil, statusOK
+	}
+
+	resolved, err := ex.resolveField(ctx, objectType, source, field, def)
+	if err != nil {
+		ex.report(fieldError(err, field.Loc, path))
+		if def.Type.NonNull {
+			return nil, statusPropagate
+		}
+		return nil, statusNulled
+	}
+
+	return ex.completeValue(ctx, def.Type, fields, resolved, path)
+}
+
+func (ex *execution) resolveField(ctx context.Context, objectType *Type, source interface{}, field *Field, def *FieldDefinition) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newError(CodeInternalServerError, nil, "internal error resolving %s.%s", objectType.Name, field.Name)
+		}
+	}()
+
+	schema := ex.executor.schema
+	if field.Name == "__typename" {
+		return objectType.Name, nil
+	}
+
+	args, err := coerceArguments(schema, def.Args, field.Arguments, ex.vars)
+	if err != nil {
+		return nil, newError(CodeBadUserInput, nil, "%s", err)
+	}
+
+	if objectType.Name == schema.QueryType {
+		switch field.Name {
+		case "__schema":
+			return &schemaIntrospection{schema: schema}, nil
+		case "__type":
+			name, _ := args["name"].(string)
+			if t := schema.Type(name); t != nil {
+				return &typeIntrospection{schema: schema, named: t}, nil
+			}
+			return nil, nil
+		}
+		return ex.executor.resolvers[field.Name](ctx, args)
+	}
+
+	return defaultResolve(ctx, source, field.Name, args)
+}
+
+// defaultResolve reads a field from a FieldResolver, map or struct
+func defaultResolve(ctx context.Context, source interface{}, name string, args map[string]interface{}) (interface{}, error) {
+	if fr, ok := source.(FieldResolver); ok {
+		return fr.ResolveField(ctx, name, args)
+	}
+	if m, ok := source.(map[string]interface{}); ok {
+		return m[name], nil
+	}
+
+	rv := reflect.ValueOf(source)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot resolve field %q on %T", name, source)
+	}
+
+	if idx, ok := structFieldIndex(rv.Type(), name); ok {
+		return rv.FieldByIndex(idx).Interface(), nil
+	}
+	return nil, nil
+}
+
+var structFieldCache sync.Map // reflect.Type -> map[string][]int
+
+func structFieldIndex(t reflect.Type, name string) ([]int, bool) {
+	cached, ok := structFieldCache.Load(t)
+	if !ok {
+		fields := make(map[string][]int)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			key := strings.Split(f.Tag.Get("json"), ",")[0]
+			if key == "" || key == "-" {
+				key = strings.ToLower(f.Name[:1]) + f.Name[1:]
+			}
+			fields[key] = f.Index
+		}
+		cached, _ = structFieldCache.LoadOrStore(t, fields)
+	}
+	idx, ok := cached.(map[string][]int)[name]
+	return idx, ok
+}
+
+func (ex *execution) completeValue(ctx context.Context, typ *TypeRef, fields []*Field, value interface{}, path []interface{}) (interface{}, completionStatus) {
+	if typ.NonNull {
+		completed, st := ex.completeValue(ctx, typ.Nullable(), fields, value, path)
+		if completed == nil {
+			if st != statusNulled {
+				ex.report(&Error{
+					Message:   fmt.Sprintf("Cannot return null for non-nullable field %s.", fields[0].Name),
+					Locations: []Location{fields[0].Loc},
+					Path:      path,
+				})
+			}
+			return nil, statusPropagate
+		}
+		return completed, statusOK
+	}
+
+	if isNil(value) {
+		return nil, statusOK
+	}
+
+	if typ.Elem != nil {
+		rv := reflect.ValueOf(value)
+		for rv.Kind() == reflect.Ptr {
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			ex.report(fieldError(fmt.Errorf("Expected a list for field %q, got %T.", fields[0].Name, value), fields[0].Loc, path))
+			return nil, statusNulled
+		}
+		items := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item, st := ex.completeValue(ctx, typ.Elem, fields, rv.Index(i).Interface(), appendPath(path, i))
+			if st == statusPropagate {
+				return nil, statusNulled
+			}
+			items[i] = item
+		}
+		return items, statusOK
+	}
+
+	t := ex.executor.schema.Type(typ.Name)
+	switch t.Kind {
+	case KindScalar:
+		out, err := serializeScalar(t.Name, deref(value))
+		if err != nil {
+			ex.report(fieldError(err, fields[0].Loc, path))
+			return nil, statusNulled
+		}
+		return out, statusOK
+	case KindEnum:
+		rv := reflect.ValueOf(deref(value))
+		if rv.Kind() != reflect.String || !t.HasEnumValue(rv.String()) {
+			ex.report(fieldError(fmt.Errorf("Enum %q cannot represent value: %v", t.Name, value), fields[0].Loc, path))
+			return nil, statusNulled
+		}
+		return rv.String(), statusOK
+	default:
+		objectType := t
+		if t.Kind != KindObject {
+			objectType = ex.resolveAbstractType(t, value)
+			if objectType == nil {
+				ex.report(fieldError(fmt.Errorf("Abstract type %q must resolve to an object type, got %T.", t.Name, value), fields[0].Loc, path))
+				return nil, statusNulled
+			}
+		}
+
+		var selections []Selection
+		for _, f := range fields {
+			selections = append(selections, f.SelectionSet...)
+		}
+		obj, st := ex.executeSelectionSet(ctx, objectType, value, selections, path)
+		if st == statusPropagate {
+			return nil, statusNulled
+		}
+		return obj, statusOK
+	}
+}
+
+// TypeNamer can be implemented by values returned for interface or union fields
+type TypeNamer interface {
+	GraphQLTypeName() string
+}
+
+func (ex *execution) resolveAbstractType(abstract *Type, value interface{}) *Type {
+	if tn, ok := value.(TypeNamer); ok {
+		if t := ex.executor.schema.Type(tn.GraphQLTypeName()); t != nil && ex.executor.schema.isPossibleType(abstract, t) {
+			return t
+		}
+	}
+	return nil
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+func deref(value interface{}) interface{} {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	return rv.Interface()
+}
+
+// OrderedMap is a JSON object that keeps insertion order, so responses follow the query's field order
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// Set sets a key, appending it if new
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if m.values == nil {
+		m.values = make(map[string]interface{})
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value for a key
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// Keys returns the keys in insertion order
+func (m *OrderedMap) Keys() []string {
+	return m.keys
+}
+
+// MarshalJSON writes the object with keys in insertion order
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,415 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSDL = `
+"""A shop"""
+type Shop {
+  id: ID!
+  name: String
+  plan: Plan!
+  tags: [String!]
+  owner: Person!
+  risky(flag: Boolean): String
+  createdAt: Time
+  legacy: String @deprecated(reason: "Use name")
+}
+
+type Person {
+  name: String!
+  email: String!
+}
+
+enum Plan {
+  FREE
+  PRO
+}
+
+input ShopFilter {
+  plan: Plan
+  first: Int = 10
+}
+
+type ShopConnection {
+  nodes: [Shop!]!
+  total: Int!
+}
+
+type Query {
+  shop(id: ID!): Shop
+  shops(filter: ShopFilter, first: Int = 2): ShopConnection!
+  echo(value: String, count: Int): String
+  fail: String
+  mustNotBeNull: String!
+}
+
+scalar Time
+`
+
+type testShop struct {
+	ID        string     `json:"id"`
+	Name      *string    `json:"name"`
+	Plan      string     `json:"plan"`
+	Tags      []string   `json:"tags"`
+	Owner     *testOwner `json:"owner"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
+type testOwner struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func (o *testOwner) ResolveField(ctx context.Context, field string, args map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "name":
+		return o.Name, nil
+	case "email":
+		if o.Email == "" {
+			return nil, errors.New("email hidden")
+		}
+		return o.Email, nil
+	}
+	return nil, nil
+}
+
+func newTestExecutor(t *testing.T, limits Limits) *Executor {
+	t.Helper()
+
+	schema, err := ParseSchema(testSDL)
+	if err != nil {
+		t.Fatalf("ParseSchema: %v", err)
+	}
+
+	name := "Acme"
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	shops := map[string]*testShop{
+		"1": {ID: "1", Name: &name, Plan: "PRO", Tags: []string{"a", "b"}, Owner: &testOwner{Name: "Ann", Email: "ann@example.com"}, CreatedAt: &created},
+		"2": {ID: "2", Plan: "FREE", Owner: &testOwner{Name: "Bob"}},
+		"3": {ID: "3", Plan: "GOLD", Owner: &testOwner{Name: "Cy", Email: "cy@example.com"}},
+	}
+
+	resolvers := map[string]ResolverFunc{
+		"shop": func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return shops[args["id"].(string)], nil
+		},
+		"shops": func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			first := args["first"].(int)
+			var plan string
+			if f, ok := args["filter"].(map[string]interface{}); ok {
+				plan, _ = f["plan"].(string)
+			}
+			var nodes []*testShop
+			for _, id := range []string{"1", "2"} {
+				if plan == "" || shops[id].Plan == plan {
+					nodes = append(nodes, shops[id])
+				}
+			}
+			if len(nodes) > first {
+				nodes = nodes[:first]
+			}
+			return map[string]interface{}{"nodes": nodes, "total": len(nodes)}, nil
+		},
+		"echo": func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			v, _ := json.Marshal(args)
+			return string(v), nil
+		},
+		"fail": func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return nil, &testCodedError{}
+		},
+		"mustNotBeNull": func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return nil, nil
+		},
+	}
+
+	exec, err := New(schema, resolvers, limits)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return exec
+}
+
+type testCodedError struct{}
+
+func (e *testCodedError) Error() string { return "boom" }
+func (e *testCodedError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": "TEST_CODE"}
+}
+
+func run(t *testing.T, exec *Executor, query string, vars map[string]interface{}) string {
+	t.Helper()
+	resp := exec.Execute(context.Background(), Request{Query: query, Variables: vars})
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(data)
+}
+
+func TestExecutor_Conformance(t *testing.T) {
+	exec := newTestExecutor(t, Limits{})
+
+	tests := []struct {
+		name  string
+		query string
+		vars  map[string]interface{}
+		want  string
+	}{
+		{
+			name:  "fields follow query order with aliases",
+			query: `{ a: shop(id: "1") { plan id n: name } }`,
+			want:  `{"data":{"a":{"plan":"PRO","id":"1","n":"Acme"}}}`,
+		},
+		{
+			name:  "nullable field and custom scalar",
+			query: `{ shop(id: "2") { name createdAt } s: shop(id: "1") { createdAt } }`,
+			want:  `{"data":{"shop":{"name":null,"createdAt":null},"s":{"createdAt":"2026-01-02T03:04:05Z"}}}`,
+		},
+		{
+			name:  "missing object is null",
+			query: `{ shop(id: "404") { id } }`,
+			want:  `{"data":{"shop":null}}`,
+		},
+		{
+			name:  "named and inline fragments merge",
+			query: `query { shop(id: "1") { ...F ... on Shop { tags } id } } fragment F on Shop { id owner { name } }`,
+			want:  `{"data":{"shop":{"id":"1","owner":{"name":"Ann"},"tags":["a","b"]}}}`,
+		},
+		{
+			name:  "typename",
+			query: `{ __typename shop(id: "1") { __typename } }`,
+			want:  `{"data":{"__typename":"Query","shop":{"__typename":"Shop"}}}`,
+		},
+		{
+			name:  "variables, defaults and input objects",
+			query: `query Q($plan: Plan, $n: Int = 5) { shops(filter: {plan: $plan}, first: $n) { total nodes { id } } }`,
+			vars:  map[string]interface{}{"plan": "FREE"},
+			want:  `{"data":{"shops":{"total":1,"nodes":[{"id":"2"}]}}}`,
+		},
+		{
+			name:  "argument default applies",
+			query: `{ shops { total } }`,
+			want:  `{"data":{"shops":{"total":2}}}`,
+		},
+		{
+			name:  "skip and include",
+			query: `query ($yes: Boolean!) { shop(id: "1") { id @skip(if: $yes) plan @include(if: $yes) name @include(if: false) } }`,
+			vars:  map[string]interface{}{"yes": true},
+			want:  `{"data":{"shop":{"plan":"PRO"}}}`,
+		},
+		{
+			name:  "json numbers coerce to Int",
+			query: `query ($c: Int) { echo(count: $c) }`,
+			vars:  map[string]interface{}{"c": json.Number("3")},
+			want:  `{"data":{"echo":"{\"count\":3}"}}`,
+		},
+		{
+			name:  "explicit null argument is passed",
+			query: `{ echo(value: null) }`,
+			want:  `{"data":{"echo":"{\"value\":null}"}}`,
+		},
+		{
+			name:  "resolver error is a located field error with extensions",
+			query: `{ fail echo }`,
+			want:  `{"errors":[{"message":"boom","locations":[{"line":1,"column":3}],"path":["fail"],"extensions":{"code":"TEST_CODE"}}],"data":{"fail":null,"echo":"{}"}}`,
+		},
+		{
+			name:  "non-null field error propagates to nearest nullable parent",
+			query: `{ shop(id: "2") { id owner { email } } }`,
+			want:  `{"errors":[{"message":"email hidden","locations":[{"line":1,"column":30}],"path":["shop","owner","email"]}],"data":{"shop":null}}`,
+		},
+		{
+			name:  "null root field in non-null position nulls data",
+			query: `{ mustNotBeNull }`,
+			want:  `{"errors":[{"message":"Cannot return null for non-nullable field mustNotBeNull.","locations":[{"line":1,"column":3}],"path":["mustNotBeNull"]}],"data":null}`,
+		},
+		{
+			name:  "invalid enum value from resolver",
+			query: `{ shop(id: "3") { plan } }`,
+			want:  `{"errors":[{"message":"Enum \"Plan\" cannot represent value: GOLD","locations":[{"line":1,"column":19}],"path":["shop","plan"]}],"data":{"shop":null}}`,
+		},
+		{
+			name:  "list items carry their index in the path",
+			query: `{ shops { nodes { owner { email } } } }`,
+			want:  `{"errors":[{"message":"email hidden","locations":[{"line":1,"column":27}],"path":["shops","nodes",1,"owner","email"]}],"data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := run(t, exec, tt.query, tt.vars); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutor_RequestErrors(t *testing.T) {
+	exec := newTestExecutor(t, Limits{})
+
+	tests := []struct {
+		name    string
+		query   string
+		vars    map[string]interface{}
+		op      string
+		wantMsg string
+	}{
+		{"syntax error", `{ shop(id: "1") { id }`, nil, "", "Syntax Error: Expected Name, found <EOF>"},
+		{"unknown field", `{ shop(id: "1") { nope } }`, nil, "", `Cannot query field "nope" on type "Shop".`},
+		{"missing required argument", `{ shop { id } }`, nil, "", `Field "shop" argument "id" of type "ID!" is required, but it was not provided.`},
+		{"unknown argument", `{ shop(id: "1", x: 1) { id } }`, nil, "", `Unknown argument "x" on field "shop".`},
+		{"wrong literal type", `{ shops(first: "two") { total } }`, nil, "", `Argument "first" has invalid value "two". Expected value of type "Int", found "two".`},
+		{"unknown enum literal", `{ shops(filter: {plan: GOLD}) { total } }`, nil, "", `Argument "filter" has invalid value {plan: GOLD}. Value GOLD does not exist in "Plan" enum.`},
+		{"leaf with selection", `{ shop(id: "1") { id { x } } }`, nil, "", `Field "id" must not have a selection since type "ID!" has no subfields.`},
+		{"object without selection", `{ shop(id: "1") }`, nil, "", `Field "shop" of type "Shop" must have a selection of subfields. Did you mean "shop { ... }"?`},
+		{"undefined variable", `query Q { shop(id: $id) { id } }`, nil, "", `Variable "$id" is not defined by operation "Q".`},
+		{"unused variable", `query ($x: Int) { shops { total } }`, nil, "", `Variable "$x" is never used.`},
+		{"variable type mismatch", `query ($id: ID) { shop(id: $id) { id } }`, nil, "", `Variable "$id" of type "ID" used in position expecting type "ID!".`},
+		{"unknown fragment", `{ shop(id: "1") { ...Nope } }`, nil, "", `Unknown fragment "Nope".`},
+		{"unused fragment", `{ shops { total } } fragment F on Shop { id }`, nil, "", `Fragment "F" is never used.`},
+		{"fragment cycle", `{ shop(id: "1") { ...A } } fragment A on Shop { ...B } fragment B on Shop { ...A }`, nil, "", `Cannot spread fragment "A" within itself.`},
+		{"impossible spread", `{ shop(id: "1") { ... on Person { name } } }`, nil, "", `Fragment cannot be spread here as objects of type "Shop" can never be of type "Person".`},
+		{"conflicting aliases", `{ shop(id: "1") { x: id x: name } }`, nil, "", `Fields "x" conflict because "id" and "name" are different fields. Use different aliases on the fields to fetch both if this was intentional.`},
+		{"unknown directive", `{ shops @cached { total } }`, nil, "", `Unknown directive "@cached".`},
+		{"mutations unsupported", `mutation { shops { total } }`, nil, "", `Schema is not configured for mutations.`},
+		{"operation name required", `query A { shops { total } } query B { shops { total } }`, nil, "", `Must provide operation name if query contains multiple operations.`},
+		{"unknown operation name", `query A { shops { total } }`, nil, "B", `Unknown operation named "B".`},
+		{"missing required variable", `query ($id: ID!) { shop(id: $id) { id } }`, nil, "", `Variable "$id" of required type "ID!" was not provided.`},
+		{"invalid variable value", `query ($n: Int) { shops(first: $n) { total } }`, map[string]interface{}{"n": "x"}, "", `Variable "$n" got invalid value "x"; Int cannot represent non 32-bit signed integer value: "x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := exec.Execute(context.Background(), Request{Query: tt.query, Variables: tt.vars, OperationName: tt.op})
+			if resp.executed {
+				t.Fatalf("expected request error, got executed response")
+			}
+			if len(resp.Errors) == 0 || resp.Errors[0].Message != tt.wantMsg {
+				t.Fatalf("errors = %+v, want first message %q", resp.Errors, tt.wantMsg)
+			}
+			data, _ := json.Marshal(resp)
+			if strings.Contains(string(data), `"data"`) {
+				t.Errorf("request errors must not include data: %s", data)
+			}
+		})
+	}
+}
+
+func TestExecutor_Introspection(t *testing.T) {
+	exec := newTestExecutor(t, Limits{MaxDepth: 3, MaxComplexity: 10})
+
+	got := run(t, exec, `{
+		__type(name: "Shop") {
+			kind name description
+			fields { name type { kind name ofType { kind name } } }
+			all: fields(includeDeprecated: true) { name isDeprecated deprecationReason }
+		}
+	}`, nil)
+
+	var resp struct {
+		Data struct {
+			Type struct {
+				Kind        string
+				Name        string
+				Description string
+				Fields      []struct {
+					Name string
+					Type struct {
+						Kind   string
+						Name   *string
+						OfType *struct{ Kind, Name string }
+					}
+				}
+				All []struct {
+					Name              string
+					IsDeprecated      bool
+					DeprecationReason *string
+				}
+			} `json:"__type"`
+		}
+		Errors []interface{}
+	}
+	if err := json.Unmarshal([]byte(got), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors (introspection must be exempt from limits): %s", got)
+	}
+
+	ty := resp.Data.Type
+	if ty.Kind != "OBJECT" || ty.Name != "Shop" || ty.Description != "A shop" {
+		t.Errorf("type = %+v", ty)
+	}
+	if len(ty.Fields) != 7 || len(ty.All) != 8 {
+		t.Fatalf("fields = %d, all = %d; want 7 and 8", len(ty.Fields), len(ty.All))
+	}
+	if f := ty.Fields[0]; f.Name != "id" || f.Type.Kind != "NON_NULL" || f.Type.OfType.Name != "ID" {
+		t.Errorf("id field = %+v", f)
+	}
+	if legacy := ty.All[7]; !legacy.IsDeprecated || legacy.DeprecationReason == nil || *legacy.DeprecationReason != "Use name" {
+		t.Errorf("legacy field = %+v", legacy)
+	}
+
+	schemaResp := run(t, exec, `{ __schema { queryType { name } types { name } directives { name locations } } }`, nil)
+	for _, want := range []string{`"queryType":{"name":"Query"}`, `{"name":"ShopFilter"}`, `{"name":"__Schema"}`, `"name":"skip"`} {
+		if !strings.Contains(schemaResp, want) {
+			t.Errorf("__schema response missing %s: %s", want, schemaResp)
+		}
+	}
+}
+
+func TestExecutor_Limits(t *testing.T) {
+	exec := newTestExecutor(t, Limits{MaxDepth: 2})
+	resp := exec.Execute(context.Background(), Request{Query: `{ shop(id: "1") { owner { name } } }`})
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != CodeQueryTooDeep {
+		t.Fatalf("errors = %+v, want QUERY_TOO_DEEP", resp.Errors)
+	}
+
+	// shops(first: 2) { nodes { id name } } = 1 + 2 * (1 + (1 + 1)) = 7
+	exec = newTestExecutor(t, Limits{MaxComplexity: 6})
+	resp = exec.Execute(context.Background(), Request{Query: `{ shops { nodes { id name } } }`})
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != CodeQueryTooComplex {
+		t.Fatalf("errors = %+v, want QUERY_TOO_COMPLEX", resp.Errors)
+	}
+	resp = exec.Execute(context.Background(), Request{Query: `query ($n: Int) { shops(first: $n) { nodes { id name } } }`, Variables: map[string]interface{}{"n": 1}})
+	if len(resp.Errors) != 0 {
+		t.Fatalf("first: 1 should fit the limit, got %+v", resp.Errors)
+	}
+}
+
+func TestResolvePersistedQuery(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryPersistedQueryStore(1)
+	query := `{ shops { total } }`
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+
+	if _, err := ResolvePersistedQuery(ctx, store, "", &PersistedQuery{Version: 1, Sha256Hash: hash}); err == nil || err.Message != "PersistedQueryNotFound" {
+		t.Fatalf("err = %v, want PersistedQueryNotFound", err)
+	}
+	if _, err := ResolvePersistedQuery(ctx, store, query, &PersistedQuery{Version: 1, Sha256Hash: strings.Repeat("0", 64)}); err == nil || err.Extensions["code"] != CodePersistedQueryMismatch {
+		t.Fatalf("err = %v, want hash mismatch", err)
+	}
+	if _, err := ResolvePersistedQuery(ctx, store, query, &PersistedQuery{Version: 1, Sha256Hash: hash}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	got, err := ResolvePersistedQuery(ctx, store, "", &PersistedQuery{Version: 1, Sha256Hash: hash})
+	if err != nil || got != query {
+		t.Fatalf("got %q, %v; want stored query", got, err)
+	}
+
+	// Capacity 1: storing another query evicts the first
+	store.Put(ctx, "other", "{ __typename }")
+	if _, ok := store.Get(ctx, hash); ok {
+		t.Error("expected least recently used query to be evicted")
+	}
+}
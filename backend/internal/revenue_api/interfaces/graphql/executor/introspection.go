@@ -0,0 +1,231 @@
+package executor
+
+import (
+	"context"
+	"strings"
+)
+
+// Introspection objects resolve the __Schema, __Type, __Field, __InputValue,
+// __EnumValue and __Directive types directly from the parsed schema.
+
+type schemaIntrospection struct {
+	schema *Schema
+}
+
+func (s *schemaIntrospection) ResolveField(ctx context.Context, field string, args map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "types":
+		types := make([]*typeIntrospection, 0, len(s.schema.TypeNames))
+		for _, name := range s.schema.TypeNames {
+			types = append(types, &typeIntrospection{schema: s.schema, named: s.schema.Types[name]})
+		}
+		return types, nil
+	case "queryType":
+		return &typeIntrospection{schema: s.schema, named: s.schema.Type(s.schema.QueryType)}, nil
+	case "directives":
+		dirs := make([]*directiveIntrospection, len(s.schema.Directives))
+		for i, d := range s.schema.Directives {
+			dirs[i] = &directiveIntrospection{schema: s.schema, def: d}
+		}
+		return dirs, nil
+	}
+	// description, mutationType, subscriptionType
+	return nil, nil
+}
+
+// typeIntrospection is a named type, or a LIST/NON_NULL wrapper when ref is set
+type typeIntrospection struct {
+	schema *Schema
+	named  *Type
+	ref    *TypeRef
+}
+
+func newTypeRefIntrospection(schema *Schema, ref *TypeRef) *typeIntrospection {
+	if !ref.NonNull && ref.Elem == nil {
+		return &typeIntrospection{schema: schema, named: schema.Type(ref.Name)}
+	}
+	return &typeIntrospection{schema: schema, ref: ref}
+}
+
+func (t *typeIntrospection) ResolveField(ctx context.Context, field string, args map[string]interface{}) (interface{}, error) {
+	if t.ref != nil {
+		switch field {
+		case "kind":
+			if t.ref.NonNull {
+				return string(KindNonNull), nil
+			}
+			return string(KindList), nil
+		case "ofType":
+			if t.ref.NonNull {
+				return newTypeRefIntrospection(t.schema, t.ref.Nullable()), nil
+			}
+			return newTypeRefIntrospection(t.schema, t.ref.Elem), nil
+		}
+		return nil, nil
+	}
+
+	includeDeprecated, _ := args["includeDeprecated"].(bool)
+	nt := t.named
+	switch field {
+	case "kind":
+		return string(nt.Kind), nil
+	case "name":
+		return nt.Name, nil
+	case "description":
+		return optionalString(nt.Description), nil
+	case "fields":
+		if nt.Kind != KindObject && nt.Kind != KindInterface {
+			return nil, nil
+		}
+		fields := make([]*fieldIntrospection, 0, len(nt.Fields))
+		for _, f := range nt.Fields {
+			if f.IsDeprecated && !includeDeprecated {
+				continue
+			}
+			fields = append(fields, &fieldIntrospection{schema: t.schema, def: f})
+		}
+		return fields, nil
+	case "interfaces":
+		if nt.Kind != KindObject && nt.Kind != KindInterface {
+			return nil, nil
+		}
+		ifaces := make([]*typeIntrospection, len(nt.Interfaces))
+		for i, name := range nt.Interfaces {
+			ifaces[i] = &typeIntrospection{schema: t.schema, named: t.schema.Type(name)}
+		}
+		return ifaces, nil
+	case "possibleTypes":
+		if nt.Kind != KindInterface && nt.Kind != KindUnion {
+			return nil, nil
+		}
+		possible := t.schema.PossibleTypes(nt)
+		types := make([]*typeIntrospection, len(possible))
+		for i, p := range possible {
+			types[i] = &typeIntrospection{schema: t.schema, named: p}
+		}
+		return types, nil
+	case "enumValues":
+		if nt.Kind != KindEnum {
+			return nil, nil
+		}
+		values := make([]*EnumValueDefinition, 0, len(nt.EnumValues))
+		for _, v := range nt.EnumValues {
+			if v.IsDeprecated && !includeDeprecated {
+				continue
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	case "inputFields":
+		if nt.Kind != KindInputObject {
+			return nil, nil
+		}
+		return inputValues(t.schema, nt.InputFields), nil
+	}
+	// specifiedByURL, ofType
+	return nil, nil
+}
+
+type fieldIntrospection struct {
+	schema *Schema
+	def    *FieldDefinition
+}
+
+func (f *fieldIntrospection) ResolveField(ctx context.Context, field string, args map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "name":
+		return f.def.Name, nil
+	case "description":
+		return optionalString(f.def.Description), nil
+	case "args":
+		return inputValues(f.schema, f.def.Args), nil
+	case "type":
+		return newTypeRefIntrospection(f.schema, f.def.Type), nil
+	case "isDeprecated":
+		return f.def.IsDeprecated, nil
+	case "deprecationReason":
+		if !f.def.IsDeprecated {
+			return nil, nil
+		}
+		return f.def.DeprecationReason, nil
+	}
+	return nil, nil
+}
+
+type inputValueIntrospection struct {
+	schema *Schema
+	def    *InputValueDefinition
+}
+
+func inputValues(schema *Schema, defs []*InputValueDefinition) []*inputValueIntrospection {
+	values := make([]*inputValueIntrospection, len(defs))
+	for i, d := range defs {
+		values[i] = &inputValueIntrospection{schema: schema, def: d}
+	}
+	return values
+}
+
+func (v *inputValueIntrospection) ResolveField(ctx context.Context, field string, args map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "name":
+		return v.def.Name, nil
+	case "description":
+		return optionalString(v.def.Description), nil
+	case "type":
+		return newTypeRefIntrospection(v.schema, v.def.Type), nil
+	case "defaultValue":
+		if v.def.DefaultValue == nil {
+			return nil, nil
+		}
+		return v.def.DefaultValue.String(), nil
+	case "isDeprecated":
+		return false, nil
+	}
+	return nil, nil
+}
+
+// ResolveField exposes enum values as __EnumValue
+func (v *EnumValueDefinition) ResolveField(ctx context.Context, field string, args map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "name":
+		return v.Name, nil
+	case "description":
+		return optionalString(v.Description), nil
+	case "isDeprecated":
+		return v.IsDeprecated, nil
+	case "deprecationReason":
+		if !v.IsDeprecated {
+			return nil, nil
+		}
+		return v.DeprecationReason, nil
+	}
+	return nil, nil
+}
+
+type directiveIntrospection struct {
+	schema *Schema
+	def    *DirectiveDefinition
+}
+
+func (d *directiveIntrospection) ResolveField(ctx context.Context, field string, args map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "name":
+		return d.def.Name, nil
+	case "description":
+		return optionalString(d.def.Description), nil
+	case "locations":
+		return d.def.Locations, nil
+	case "args":
+		return inputValues(d.schema, d.def.Args), nil
+	case "isRepeatable":
+		return false, nil
+	}
+	return nil, nil
+}
+
+func optionalString(s string) interface{} {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return s
+}
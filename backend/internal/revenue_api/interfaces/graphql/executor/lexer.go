@@ -0,0 +1,281 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+	tokBlockString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	loc   Location
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "<EOF>"
+	case tokString, tokBlockString:
+		return fmt.Sprintf("%q", t.value)
+	default:
+		return t.value
+	}
+}
+
+// lexer splits a GraphQL source document into tokens. Whitespace, commas and comments are ignored.
+type lexer struct {
+	src  string
+	pos  int
+	line int
+	col  int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src, line: 1, col: 1}
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.pos < len(l.src); i++ {
+		if l.src[l.pos] == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.advance(1)
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.advance(1)
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\ufeff"):
+			l.pos += len("\ufeff")
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	loc := Location{Line: l.line, Column: l.col}
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, loc: loc}, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.advance(3)
+		return token{kind: tokPunct, value: "...", loc: loc}, nil
+	case strings.IndexByte("!$&():=@[]{}|", c) >= 0:
+		l.advance(1)
+		return token{kind: tokPunct, value: string(c), loc: loc}, nil
+	case c == '_' || isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.advance(1)
+		}
+		return token{kind: tokName, value: l.src[start:l.pos], loc: loc}, nil
+	case c == '-' || isDigit(c):
+		return l.readNumber(loc)
+	case strings.HasPrefix(l.src[l.pos:], `"""`):
+		return l.readBlockString(loc)
+	case c == '"':
+		return l.readString(loc)
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return token{}, syntaxError(loc, "Unexpected character %q", r)
+}
+
+func (l *lexer) readNumber(loc Location) (token, error) {
+	start := l.pos
+	kind := tokInt
+
+	if l.src[l.pos] == '-' {
+		l.advance(1)
+	}
+	if l.pos >= len(l.src) || !isDigit(l.src[l.pos]) {
+		return token{}, syntaxError(loc, "Invalid number, expected digit")
+	}
+	if l.src[l.pos] == '0' {
+		l.advance(1)
+		if l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			return token{}, syntaxError(loc, "Invalid number, unexpected digit after 0")
+		}
+	} else {
+		l.readDigits()
+	}
+
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokFloat
+		l.advance(1)
+		if l.pos >= len(l.src) || !isDigit(l.src[l.pos]) {
+			return token{}, syntaxError(loc, "Invalid number, expected digit after '.'")
+		}
+		l.readDigits()
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokFloat
+		l.advance(1)
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.advance(1)
+		}
+		if l.pos >= len(l.src) || !isDigit(l.src[l.pos]) {
+			return token{}, syntaxError(loc, "Invalid number, expected digit in exponent")
+		}
+		l.readDigits()
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || l.src[l.pos] == '.') {
+		return token{}, syntaxError(loc, "Invalid number, unexpected %q", l.src[l.pos])
+	}
+
+	return token{kind: kind, value: l.src[start:l.pos], loc: loc}, nil
+}
+
+func (l *lexer) readDigits() {
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.advance(1)
+	}
+}
+
+func (l *lexer) readString(loc Location) (token, error) {
+	l.advance(1)
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) || l.src[l.pos] == '\n' || l.src[l.pos] == '\r' {
+			return token{}, syntaxError(loc, "Unterminated string")
+		}
+		c := l.src[l.pos]
+		if c == '"' {
+			l.advance(1)
+			return token{kind: tokString, value: b.String(), loc: loc}, nil
+		}
+		if c != '\\' {
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			b.WriteRune(r)
+			l.advance(size)
+			continue
+		}
+
+		if l.pos+1 >= len(l.src) {
+			return token{}, syntaxError(loc, "Unterminated string")
+		}
+		esc := l.src[l.pos+1]
+		switch esc {
+		case '"', '\\', '/':
+			b.WriteByte(esc)
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if l.pos+6 > len(l.src) {
+				return token{}, syntaxError(loc, "Invalid unicode escape sequence")
+			}
+			var r rune
+			if _, err := fmt.Sscanf(l.src[l.pos+2:l.pos+6], "%04x", &r); err != nil {
+				return token{}, syntaxError(loc, "Invalid unicode escape sequence")
+			}
+			b.WriteRune(r)
+			l.advance(4)
+		default:
+			return token{}, syntaxError(loc, "Invalid escape sequence \\%c", esc)
+		}
+		l.advance(2)
+	}
+}
+
+func (l *lexer) readBlockString(loc Location) (token, error) {
+	l.advance(3)
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) {
+			return token{}, syntaxError(loc, "Unterminated block string")
+		}
+		if strings.HasPrefix(l.src[l.pos:], `"""`) {
+			l.advance(3)
+			return token{kind: tokBlockString, value: blockStringValue(b.String()), loc: loc}, nil
+		}
+		if strings.HasPrefix(l.src[l.pos:], `\"""`) {
+			b.WriteString(`"""`)
+			l.advance(4)
+			continue
+		}
+		b.WriteByte(l.src[l.pos])
+		l.advance(1)
+	}
+}
+
+// blockStringValue strips the common indentation and blank leading/trailing lines of a block string
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+
+	common := -1
+	for i, line := range lines {
+		if i == 0 {
+			continue
+		}
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		indent := len(line) - len(trimmed)
+		if common < 0 || indent < common {
+			common = indent
+		}
+	}
+	if common > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= common {
+				lines[i] = lines[i][common:]
+			} else {
+				lines[i] = strings.TrimLeft(lines[i], " \t")
+			}
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,124 @@
+package executor
+
+// Limits bounds the cost of a single operation. Zero disables a limit.
+type Limits struct {
+	MaxDepth      int // Deepest field nesting, e.g. { a { b } } has depth 2
+	MaxComplexity int // Sum of field costs; list fields multiply their children
+}
+
+// checkLimits measures an operation (with coerced variables) and returns an error if it exceeds the limits.
+// Introspection fields are exempt so standard tooling can always fetch the schema.
+func checkLimits(schema *Schema, doc *Document, op *OperationDefinition, variables map[string]interface{}, limits Limits) *Error {
+	if limits.MaxDepth <= 0 && limits.MaxComplexity <= 0 {
+		return nil
+	}
+
+	m := &measurer{schema: schema, doc: doc, variables: variables}
+	root := schema.Type(schema.QueryType)
+	depth := m.depth(root, op.SelectionSet)
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return newError(CodeQueryTooDeep, []Location{op.Loc}, "Query depth %d exceeds the maximum of %d.", depth, limits.MaxDepth)
+	}
+
+	complexity := m.complexity(root, op.SelectionSet)
+	if limits.MaxComplexity > 0 && complexity > limits.MaxComplexity {
+		return newError(CodeQueryTooComplex, []Location{op.Loc}, "Query complexity %d exceeds the maximum of %d.", complexity, limits.MaxComplexity)
+	}
+	return nil
+}
+
+type measurer struct {
+	schema    *Schema
+	doc       *Document
+	variables map[string]interface{}
+}
+
+// fields flattens fragments into (parent type, field) pairs
+func (m *measurer) fields(parent *Type, selections []Selection, visit func(*Type, *Field)) {
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *Field:
+			visit(parent, s)
+		case *InlineFragment:
+			target := parent
+			if s.TypeCondition != "" {
+				target = m.schema.Type(s.TypeCondition)
+			}
+			if target != nil {
+				m.fields(target, s.SelectionSet, visit)
+			}
+		case *FragmentSpread:
+			if frag := m.doc.Fragment(s.Name); frag != nil {
+				if target := m.schema.Type(frag.TypeCondition); target != nil {
+					m.fields(target, frag.SelectionSet, visit)
+				}
+			}
+		}
+	}
+}
+
+func isIntrospectionField(name string) bool {
+	return name == "__schema" || name == "__type" || name == "__typename"
+}
+
+func (m *measurer) depth(parent *Type, selections []Selection) int {
+	max := 0
+	m.fields(parent, selections, func(p *Type, f *Field) {
+		if isIntrospectionField(f.Name) {
+			return
+		}
+		d := 1
+		if def := fieldDefinition(m.schema, p, f.Name); def != nil && len(f.SelectionSet) > 0 {
+			if child := m.schema.Type(def.Type.NamedType()); child != nil {
+				d += m.depth(child, f.SelectionSet)
+			}
+		}
+		if d > max {
+			max = d
+		}
+	})
+	return max
+}
+
+func (m *measurer) complexity(parent *Type, selections []Selection) int {
+	total := 0
+	m.fields(parent, selections, func(p *Type, f *Field) {
+		if isIntrospectionField(f.Name) {
+			return
+		}
+		def := fieldDefinition(m.schema, p, f.Name)
+		if def == nil {
+			return
+		}
+
+		cost := 1
+		if len(f.SelectionSet) > 0 {
+			if child := m.schema.Type(def.Type.NamedType()); child != nil {
+				cost += m.multiplier(def, f) * m.complexity(child, f.SelectionSet)
+			}
+		}
+		total += cost
+	})
+	return total
+}
+
+// multiplier estimates how many child objects a field returns: the page size for
+// paginated fields (first), otherwise the length of the longest list argument
+func (m *measurer) multiplier(def *FieldDefinition, f *Field) int {
+	args, err := coerceArguments(m.schema, def.Args, f.Arguments, m.variables)
+	if err != nil {
+		return 1
+	}
+
+	if first, ok := args["first"].(int); ok && first > 0 {
+		return first
+	}
+
+	n := 1
+	for _, a := range def.Args {
+		if list, ok := args[a.Name].([]interface{}); ok && len(list) > n {
+			n = len(list)
+		}
+	}
+	return n
+}
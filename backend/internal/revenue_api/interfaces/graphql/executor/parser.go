@@ -0,0 +1,474 @@
+package executor
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+func newParser(src string) (*parser, error) {
+	p := &parser{lex: newLexer(src)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokPunct && p.tok.value == punct
+}
+
+func (p *parser) peekName(name string) bool {
+	return p.tok.kind == tokName && p.tok.value == name
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.peek(punct) {
+		return syntaxError(p.tok.loc, "Expected %q, found %s", punct, p.tok)
+	}
+	return p.advance()
+}
+
+// skip consumes the punctuator if present
+func (p *parser) skip(punct string) (bool, error) {
+	if !p.peek(punct) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expectKeyword(name string) error {
+	if !p.peekName(name) {
+		return syntaxError(p.tok.loc, "Expected %q, found %s", name, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) parseName() (string, error) {
+	if p.tok.kind != tokName {
+		return "", syntaxError(p.tok.loc, "Expected Name, found %s", p.tok)
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+// ParseQuery parses an executable document (operations and fragments)
+func ParseQuery(src string) (*Document, error) {
+	p, err := newParser(src)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{}
+	if p.tok.kind == tokEOF {
+		return nil, syntaxError(p.tok.loc, "Unexpected <EOF>")
+	}
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.peek("{"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.peekName("query"), p.peekName("mutation"), p.peekName("subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.peekName("fragment"):
+			frag, err := p.parseFragmentDefinition()
+			if err != nil {
+				return nil, err
+			}
+			doc.Fragments = append(doc.Fragments, frag)
+		default:
+			return nil, syntaxError(p.tok.loc, "Unexpected %s", p.tok)
+		}
+	}
+
+	return doc, nil
+}
+
+func (p *parser) parseOperation() (*OperationDefinition, error) {
+	op := &OperationDefinition{Operation: "query", Loc: p.tok.loc}
+
+	if p.peek("{") {
+		sel, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		op.SelectionSet = sel
+		return op, nil
+	}
+
+	op.Operation = p.tok.value
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokName {
+		op.Name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek("(") {
+		defs, err := p.parseVariableDefinitions()
+		if err != nil {
+			return nil, err
+		}
+		op.VariableDefinitions = defs
+	}
+
+	dirs, err := p.parseDirectives(false)
+	if err != nil {
+		return nil, err
+	}
+	op.Directives = dirs
+
+	sel, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.SelectionSet = sel
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinitions() ([]*VariableDefinition, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var defs []*VariableDefinition
+	for !p.peek(")") {
+		def := &VariableDefinition{Loc: p.tok.loc}
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		def.Name = name
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if def.Type, err = p.parseTypeRef(); err != nil {
+			return nil, err
+		}
+		if ok, err := p.skip("="); err != nil {
+			return nil, err
+		} else if ok {
+			if def.DefaultValue, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := p.parseDirectives(true); err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+
+	return defs, p.expect(")")
+}
+
+func (p *parser) parseFragmentDefinition() (*FragmentDefinition, error) {
+	frag := &FragmentDefinition{Loc: p.tok.loc}
+	if err := p.expectKeyword("fragment"); err != nil {
+		return nil, err
+	}
+	if p.peekName("on") {
+		return nil, syntaxError(p.tok.loc, "Unexpected Name \"on\"")
+	}
+
+	var err error
+	if frag.Name, err = p.parseName(); err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("on"); err != nil {
+		return nil, err
+	}
+	if frag.TypeCondition, err = p.parseName(); err != nil {
+		return nil, err
+	}
+	if frag.Directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+	if frag.SelectionSet, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return frag, nil
+}
+
+func (p *parser) parseSelectionSet() ([]Selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var selections []Selection
+	for !p.peek("}") {
+		if p.tok.kind == tokEOF {
+			return nil, syntaxError(p.tok.loc, "Expected Name, found <EOF>")
+		}
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+	if len(selections) == 0 {
+		return nil, syntaxError(p.tok.loc, "Expected Name, found \"}\"")
+	}
+
+	return selections, p.advance()
+}
+
+func (p *parser) parseSelection() (Selection, error) {
+	if !p.peek("...") {
+		return p.parseField()
+	}
+
+	loc := p.tok.loc
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokName && p.tok.value != "on" {
+		spread := &FragmentSpread{Name: p.tok.value, Loc: loc}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		if spread.Directives, err = p.parseDirectives(false); err != nil {
+			return nil, err
+		}
+		return spread, nil
+	}
+
+	inline := &InlineFragment{Loc: loc}
+	if p.peekName("on") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		inline.TypeCondition = name
+	}
+	var err error
+	if inline.Directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+	if inline.SelectionSet, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return inline, nil
+}
+
+func (p *parser) parseField() (*Field, error) {
+	field := &Field{Loc: p.tok.loc}
+
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if ok {
+		field.Alias = name
+		if name, err = p.parseName(); err != nil {
+			return nil, err
+		}
+	}
+	field.Name = name
+
+	if p.peek("(") {
+		if field.Arguments, err = p.parseArguments(false); err != nil {
+			return nil, err
+		}
+	}
+	if field.Directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+	if p.peek("{") {
+		if field.SelectionSet, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+func (p *parser) parseArguments(isConst bool) ([]*Argument, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var args []*Argument
+	for !p.peek(")") {
+		arg := &Argument{Loc: p.tok.loc}
+		var err error
+		if arg.Name, err = p.parseName(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if arg.Value, err = p.parseValue(isConst); err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if len(args) == 0 {
+		return nil, syntaxError(p.tok.loc, "Expected Name, found \")\"")
+	}
+
+	return args, p.advance()
+}
+
+func (p *parser) parseDirectives(isConst bool) ([]*Directive, error) {
+	var dirs []*Directive
+	for p.peek("@") {
+		dir := &Directive{Loc: p.tok.loc}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		if dir.Name, err = p.parseName(); err != nil {
+			return nil, err
+		}
+		if p.peek("(") {
+			if dir.Arguments, err = p.parseArguments(isConst); err != nil {
+				return nil, err
+			}
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+func (p *parser) parseTypeRef() (*TypeRef, error) {
+	var t *TypeRef
+	if ok, err := p.skip("["); err != nil {
+		return nil, err
+	} else if ok {
+		elem, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		t = &TypeRef{Elem: elem}
+	} else {
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		t = &TypeRef{Name: name}
+	}
+
+	if ok, err := p.skip("!"); err != nil {
+		return nil, err
+	} else if ok {
+		t.NonNull = true
+	}
+	return t, nil
+}
+
+func (p *parser) parseValue(isConst bool) (*Value, error) {
+	v := &Value{Loc: p.tok.loc}
+
+	switch p.tok.kind {
+	case tokInt:
+		v.Kind, v.Raw = IntValue, p.tok.value
+	case tokFloat:
+		v.Kind, v.Raw = FloatValue, p.tok.value
+	case tokString, tokBlockString:
+		v.Kind, v.Raw = StringValue, p.tok.value
+	case tokName:
+		switch p.tok.value {
+		case "true", "false":
+			v.Kind = BooleanValue
+		case "null":
+			v.Kind = NullValue
+		default:
+			v.Kind = EnumValue
+		}
+		v.Raw = p.tok.value
+	case tokPunct:
+		switch p.tok.value {
+		case "$":
+			if isConst {
+				return nil, syntaxError(p.tok.loc, "Unexpected variable in constant value")
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			v.Kind, v.Raw = VariableValue, name
+			return v, nil
+		case "[":
+			v.Kind = ListValue
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			for !p.peek("]") {
+				item, err := p.parseValue(isConst)
+				if err != nil {
+					return nil, err
+				}
+				v.List = append(v.List, item)
+			}
+			return v, p.advance()
+		case "{":
+			v.Kind = ObjectValue
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			for !p.peek("}") {
+				field := &ObjectField{Loc: p.tok.loc}
+				var err error
+				if field.Name, err = p.parseName(); err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				if field.Value, err = p.parseValue(isConst); err != nil {
+					return nil, err
+				}
+				v.Fields = append(v.Fields, field)
+			}
+			return v, p.advance()
+		default:
+			return nil, syntaxError(p.tok.loc, "Unexpected %s", p.tok)
+		}
+	default:
+		return nil, syntaxError(p.tok.loc, "Unexpected %s", p.tok)
+	}
+
+	return v, p.advance()
+}
+
+// parseDescription consumes an optional SDL description string
+func (p *parser) parseDescription() (string, error) {
+	if p.tok.kind != tokString && p.tok.kind != tokBlockString {
+		return "", nil
+	}
+	desc := p.tok.value
+	return desc, p.advance()
+}
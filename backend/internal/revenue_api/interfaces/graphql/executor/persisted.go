@@ -0,0 +1,106 @@
+package executor
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+)
+
+// PersistedQueryStore stores query documents by their SHA-256 hash
+// (Automatic Persisted Queries, version 1)
+type PersistedQueryStore interface {
+	Get(ctx context.Context, hash string) (string, bool)
+	Put(ctx context.Context, hash string, query string)
+}
+
+// PersistedQuery is the "persistedQuery" request extension
+type PersistedQuery struct {
+	Version    int    `json:"version"`
+	Sha256Hash string `json:"sha256Hash"`
+}
+
+// ResolvePersistedQuery returns the query text to execute for an APQ request.
+// With only a hash, the stored query is returned, or PersistedQueryNotFound so the client
+// retries with the full text. With both, the hash is verified and the query is stored.
+func ResolvePersistedQuery(ctx context.Context, store PersistedQueryStore, query string, pq *PersistedQuery) (string, *Error) {
+	if pq == nil {
+		return query, nil
+	}
+	if pq.Version != 1 {
+		return "", newError(CodeBadUserInput, nil, "Unsupported persisted query version %d.", pq.Version)
+	}
+
+	hash := strings.ToLower(pq.Sha256Hash)
+	if query == "" {
+		if stored, ok := store.Get(ctx, hash); ok {
+			return stored, nil
+		}
+		return "", newError(CodePersistedQueryNotFound, nil, persistedQueryNotFoundError)
+	}
+
+	sum := sha256.Sum256([]byte(query))
+	if hex.EncodeToString(sum[:]) != hash {
+		return "", newError(CodePersistedQueryMismatch, nil, "Provided sha256Hash does not match query.")
+	}
+	store.Put(ctx, hash, query)
+	return query, nil
+}
+
+// MemoryPersistedQueryStore is a bounded in-process LRU store
+type MemoryPersistedQueryStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type persistedEntry struct {
+	hash  string
+	query string
+}
+
+// NewMemoryPersistedQueryStore creates a store that keeps at most capacity queries
+func NewMemoryPersistedQueryStore(capacity int) *MemoryPersistedQueryStore {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &MemoryPersistedQueryStore{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns a stored query and marks it recently used
+func (s *MemoryPersistedQueryStore) Get(ctx context.Context, hash string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.entries[hash]
+	if !ok {
+		return "", false
+	}
+	s.order.MoveToFront(el)
+	return el.Value.(*persistedEntry).query, true
+}
+
+// Put stores a query, evicting the least recently used one when full
+func (s *MemoryPersistedQueryStore) Put(ctx context.Context, hash string, query string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[hash]; ok {
+		s.order.MoveToFront(el)
+		return
+	}
+
+	s.entries[hash] = s.order.PushFront(&persistedEntry{hash: hash, query: query})
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*persistedEntry).hash)
+	}
+}
@@ -0,0 +1,618 @@
+package executor
+
+import (
+	"fmt"
+)
+
+// TypeKind is the __TypeKind of a schema type
+type TypeKind string
+
+const (
+	KindScalar      TypeKind = "SCALAR"
+	KindObject      TypeKind = "OBJECT"
+	KindInterface   TypeKind = "INTERFACE"
+	KindUnion       TypeKind = "UNION"
+	KindEnum        TypeKind = "ENUM"
+	KindInputObject TypeKind = "INPUT_OBJECT"
+	KindList        TypeKind = "LIST"
+	KindNonNull     TypeKind = "NON_NULL"
+)
+
+// Type is a named type in the schema
+type Type struct {
+	Kind          TypeKind
+	Name          string
+	Description   string
+	Fields        []*FieldDefinition      // OBJECT, INTERFACE
+	Interfaces    []string                // OBJECT, INTERFACE
+	PossibleTypes []string                // UNION (INTERFACE is computed)
+	EnumValues    []*EnumValueDefinition  // ENUM
+	InputFields   []*InputValueDefinition // INPUT_OBJECT
+}
+
+// Field returns the field definition with the given name, or nil
+func (t *Type) Field(name string) *FieldDefinition {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// InputField returns the input field definition with the given name, or nil
+func (t *Type) InputField(name string) *InputValueDefinition {
+	for _, f := range t.InputFields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// HasEnumValue reports whether the enum declares the value
+func (t *Type) HasEnumValue(name string) bool {
+	for _, v := range t.EnumValues {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsComposite reports whether the type has a selection set
+func (t *Type) IsComposite() bool {
+	return t.Kind == KindObject || t.Kind == KindInterface || t.Kind == KindUnion
+}
+
+// IsLeaf reports whether the type is a scalar or enum
+func (t *Type) IsLeaf() bool {
+	return t.Kind == KindScalar || t.Kind == KindEnum
+}
+
+// IsInput reports whether the type can be used for arguments and variables
+func (t *Type) IsInput() bool {
+	return t.Kind == KindScalar || t.Kind == KindEnum || t.Kind == KindInputObject
+}
+
+// FieldDefinition is a field of an object or interface type
+type FieldDefinition struct {
+	Name              string
+	Description       string
+	Args              []*InputValueDefinition
+	Type              *TypeRef
+	IsDeprecated      bool
+	DeprecationReason string
+}
+
+// Arg returns the argument definition with the given name, or nil
+func (f *FieldDefinition) Arg(name string) *InputValueDefinition {
+	for _, a := range f.Args {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// InputValueDefinition is an argument or input object field
+type InputValueDefinition struct {
+	Name         string
+	Description  string
+	Type         *TypeRef
+	DefaultValue *Value
+}
+
+// EnumValueDefinition is a value of an enum type
+type EnumValueDefinition struct {
+	Name              string
+	Description       string
+	IsDeprecated      bool
+	DeprecationReason string
+}
+
+// DirectiveDefinition is a directive the executor understands
+type DirectiveDefinition struct {
+	Name        string
+	Description string
+	Locations   []string
+	Args        []*InputValueDefinition
+}
+
+// Schema is a parsed, validated type system
+type Schema struct {
+	Types      map[string]*Type
+	TypeNames  []string // Declaration order, for introspection
+	QueryType  string
+	Directives []*DirectiveDefinition
+}
+
+// Type returns the named type, or nil
+func (s *Schema) Type(name string) *Type {
+	return s.Types[name]
+}
+
+// Directive returns the named directive definition, or nil
+func (s *Schema) Directive(name string) *DirectiveDefinition {
+	for _, d := range s.Directives {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// PossibleTypes returns the object types an abstract type can resolve to
+func (s *Schema) PossibleTypes(t *Type) []*Type {
+	var result []*Type
+	switch t.Kind {
+	case KindObject:
+		return []*Type{t}
+	case KindUnion:
+		for _, name := range t.PossibleTypes {
+			result = append(result, s.Types[name])
+		}
+	case KindInterface:
+		for _, name := range s.TypeNames {
+			candidate := s.Types[name]
+			if candidate.Kind != KindObject {
+				continue
+			}
+			for _, iface := range candidate.Interfaces {
+				if iface == t.Name {
+					result = append(result, candidate)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// isPossibleType reports whether the object type is a member of the abstract type
+func (s *Schema) isPossibleType(abstract, object *Type) bool {
+	for _, t := range s.PossibleTypes(abstract) {
+		if t.Name == object.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// builtinSDL declares the specified scalars and the introspection types
+const builtinSDL = `
+scalar Int
+scalar Float
+scalar String
+scalar Boolean
+scalar ID
+
+type __Schema {
+  description: String
+  types: [__Type!]!
+  queryType: __Type!
+  mutationType: __Type
+  subscriptionType: __Type
+  directives: [__Directive!]!
+}
+
+type __Type {
+  kind: __TypeKind!
+  name: String
+  description: String
+  specifiedByURL: String
+  fields(includeDeprecated: Boolean = false): [__Field!]
+  interfaces: [__Type!]
+  possibleTypes: [__Type!]
+  enumValues(includeDeprecated: Boolean = false): [__EnumValue!]
+  inputFields(includeDeprecated: Boolean = false): [__InputValue!]
+  ofType: __Type
+}
+
+enum __TypeKind {
+  SCALAR
+  OBJECT
+  INTERFACE
+  UNION
+  ENUM
+  INPUT_OBJECT
+  LIST
+  NON_NULL
+}
+
+type __Field {
+  name: String!
+  description: String
+  args(includeDeprecated: Boolean = false): [__InputValue!]!
+  type: __Type!
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __InputValue {
+  name: String!
+  description: String
+  type: __Type!
+  defaultValue: String
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __EnumValue {
+  name: String!
+  description: String
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __Directive {
+  name: String!
+  description: String
+  locations: [__DirectiveLocation!]!
+  args(includeDeprecated: Boolean = false): [__InputValue!]!
+  isRepeatable: Boolean!
+}
+
+enum __DirectiveLocation {
+  QUERY
+  MUTATION
+  SUBSCRIPTION
+  FIELD
+  FRAGMENT_DEFINITION
+  FRAGMENT_SPREAD
+  INLINE_FRAGMENT
+  VARIABLE_DEFINITION
+  SCHEMA
+  SCALAR
+  OBJECT
+  FIELD_DEFINITION
+  ARGUMENT_DEFINITION
+  INTERFACE
+  UNION
+  ENUM
+  ENUM_VALUE
+  INPUT_OBJECT
+  INPUT_FIELD_DEFINITION
+}
+`
+
+// ParseSchema parses SDL into a Schema and checks that every referenced type exists.
+// Only query operations are executed; a Query type is required.
+func ParseSchema(sdl string) (*Schema, error) {
+	schema := &Schema{Types: make(map[string]*Type), QueryType: "Query"}
+
+	if err := parseSDLInto(schema, builtinSDL); err != nil {
+		return nil, fmt.Errorf("builtin schema: %w", err)
+	}
+	if err := parseSDLInto(schema, sdl); err != nil {
+		return nil, err
+	}
+
+	boolType := &TypeRef{Name: "Boolean", NonNull: true}
+	schema.Directives = []*DirectiveDefinition{
+		{
+			Name:        "include",
+			Description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+			Locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+			Args:        []*InputValueDefinition{{Name: "if", Description: "Included when true.", Type: boolType}},
+		},
+		{
+			Name:        "skip",
+			Description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+			Locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+			Args:        []*InputValueDefinition{{Name: "if", Description: "Skipped when true.", Type: boolType}},
+		},
+		{
+			Name:        "deprecated",
+			Description: "Marks an element of a GraphQL schema as no longer supported.",
+			Locations:   []string{"FIELD_DEFINITION", "ARGUMENT_DEFINITION", "INPUT_FIELD_DEFINITION", "ENUM_VALUE"},
+			Args: []*InputValueDefinition{{
+				Name:         "reason",
+				Type:         &TypeRef{Name: "String"},
+				DefaultValue: &Value{Kind: StringValue, Raw: "No longer supported"},
+			}},
+		},
+	}
+
+	if schema.Types[schema.QueryType] == nil || schema.Types[schema.QueryType].Kind != KindObject {
+		return nil, fmt.Errorf("schema must define an object type %q", schema.QueryType)
+	}
+	if err := schema.checkReferences(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func (s *Schema) checkReferences() error {
+	check := func(ref *TypeRef, where string, wantInput bool) error {
+		t := s.Types[ref.NamedType()]
+		if t == nil {
+			return fmt.Errorf("%s: unknown type %q", where, ref.NamedType())
+		}
+		if wantInput && !t.IsInput() {
+			return fmt.Errorf("%s: %q is not an input type", where, t.Name)
+		}
+		if !wantInput && t.Kind == KindInputObject {
+			return fmt.Errorf("%s: %q is not an output type", where, t.Name)
+		}
+		return nil
+	}
+
+	for _, name := range s.TypeNames {
+		t := s.Types[name]
+		for _, f := range t.Fields {
+			if err := check(f.Type, name+"."+f.Name, false); err != nil {
+				return err
+			}
+			for _, a := range f.Args {
+				if err := check(a.Type, name+"."+f.Name+"("+a.Name+")", true); err != nil {
+					return err
+				}
+			}
+		}
+		for _, f := range t.InputFields {
+			if err := check(f.Type, name+"."+f.Name, true); err != nil {
+				return err
+			}
+		}
+		for _, iface := range t.Interfaces {
+			if it := s.Types[iface]; it == nil || it.Kind != KindInterface {
+				return fmt.Errorf("%s: %q is not an interface", name, iface)
+			}
+		}
+		for _, member := range t.PossibleTypes {
+			if mt := s.Types[member]; mt == nil || mt.Kind != KindObject {
+				return fmt.Errorf("%s: union member %q is not an object type", name, member)
+			}
+		}
+	}
+	return nil
+}
+
+func parseSDLInto(schema *Schema, sdl string) error {
+	p, err := newParser(sdl)
+	if err != nil {
+		return err
+	}
+
+	for p.tok.kind != tokEOF {
+		desc, err := p.parseDescription()
+		if err != nil {
+			return err
+		}
+
+		if p.peekName("schema") {
+			if err := p.parseSchemaDefinition(schema); err != nil {
+				return err
+			}
+			continue
+		}
+
+		t := &Type{Description: desc}
+		keyword, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		switch keyword {
+		case "scalar":
+			t.Kind = KindScalar
+		case "type":
+			t.Kind = KindObject
+		case "interface":
+			t.Kind = KindInterface
+		case "union":
+			t.Kind = KindUnion
+		case "enum":
+			t.Kind = KindEnum
+		case "input":
+			t.Kind = KindInputObject
+		default:
+			return syntaxError(p.tok.loc, "Unexpected Name %q", keyword)
+		}
+
+		if t.Name, err = p.parseName(); err != nil {
+			return err
+		}
+		if _, exists := schema.Types[t.Name]; exists {
+			return fmt.Errorf("type %q is defined more than once", t.Name)
+		}
+
+		if err := p.parseTypeBody(t); err != nil {
+			return err
+		}
+		schema.Types[t.Name] = t
+		schema.TypeNames = append(schema.TypeNames, t.Name)
+	}
+	return nil
+}
+
+func (p *parser) parseSchemaDefinition(schema *Schema) error {
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if _, err := p.parseDirectives(true); err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.peek("}") {
+		op, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		name, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		if op == "query" {
+			schema.QueryType = name
+		}
+	}
+	return p.advance()
+}
+
+func (p *parser) parseTypeBody(t *Type) error {
+	if p.peekName("implements") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if _, err := p.skip("&"); err != nil {
+			return err
+		}
+		for p.tok.kind == tokName {
+			t.Interfaces = append(t.Interfaces, p.tok.value)
+			if err := p.advance(); err != nil {
+				return err
+			}
+			if _, err := p.skip("&"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := p.parseDirectives(true); err != nil {
+		return err
+	}
+
+	switch t.Kind {
+	case KindUnion:
+		if ok, err := p.skip("="); err != nil || !ok {
+			return err
+		}
+		if _, err := p.skip("|"); err != nil {
+			return err
+		}
+		for {
+			name, err := p.parseName()
+			if err != nil {
+				return err
+			}
+			t.PossibleTypes = append(t.PossibleTypes, name)
+			if ok, err := p.skip("|"); err != nil {
+				return err
+			} else if !ok {
+				return nil
+			}
+		}
+	case KindScalar:
+		return nil
+	}
+
+	if !p.peek("{") {
+		return nil
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+
+	for !p.peek("}") {
+		desc, err := p.parseDescription()
+		if err != nil {
+			return err
+		}
+		name, err := p.parseName()
+		if err != nil {
+			return err
+		}
+
+		switch t.Kind {
+		case KindEnum:
+			value := &EnumValueDefinition{Name: name, Description: desc}
+			dirs, err := p.parseDirectives(true)
+			if err != nil {
+				return err
+			}
+			value.IsDeprecated, value.DeprecationReason = deprecation(dirs)
+			t.EnumValues = append(t.EnumValues, value)
+		case KindInputObject:
+			field, err := p.parseInputValueRest(name, desc)
+			if err != nil {
+				return err
+			}
+			t.InputFields = append(t.InputFields, field)
+		default:
+			field := &FieldDefinition{Name: name, Description: desc}
+			if p.peek("(") {
+				if field.Args, err = p.parseArgumentDefinitions(); err != nil {
+					return err
+				}
+			}
+			if err := p.expect(":"); err != nil {
+				return err
+			}
+			if field.Type, err = p.parseTypeRef(); err != nil {
+				return err
+			}
+			dirs, err := p.parseDirectives(true)
+			if err != nil {
+				return err
+			}
+			field.IsDeprecated, field.DeprecationReason = deprecation(dirs)
+			t.Fields = append(t.Fields, field)
+		}
+	}
+	return p.advance()
+}
+
+func (p *parser) parseArgumentDefinitions() ([]*InputValueDefinition, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var args []*InputValueDefinition
+	for !p.peek(")") {
+		desc, err := p.parseDescription()
+		if err != nil {
+			return nil, err
+		}
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		arg, err := p.parseInputValueRest(name, desc)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, p.advance()
+}
+
+func (p *parser) parseInputValueRest(name, desc string) (*InputValueDefinition, error) {
+	def := &InputValueDefinition{Name: name, Description: desc}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	var err error
+	if def.Type, err = p.parseTypeRef(); err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip("="); err != nil {
+		return nil, err
+	} else if ok {
+		if def.DefaultValue, err = p.parseValue(true); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.parseDirectives(true); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+func deprecation(dirs []*Directive) (bool, string) {
+	for _, d := range dirs {
+		if d.Name != "deprecated" {
+			continue
+		}
+		for _, a := range d.Arguments {
+			if a.Name == "reason" && a.Value.Kind == StringValue {
+				return true, a.Value.Raw
+			}
+		}
+		return true, "No longer supported"
+	}
+	return false, ""
+}
@@ -0,0 +1,651 @@
+package executor
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// variableUsage is a variable referenced at a position with an expected input type
+type variableUsage struct {
+	name       string
+	typ        *TypeRef
+	hasDefault bool // The argument or input field at the position declares a default
+	loc        Location
+}
+
+// selectionScope accumulates what a selection set (operation or fragment body) references
+type selectionScope struct {
+	usages    []variableUsage
+	fragments map[string]bool
+}
+
+type validator struct {
+	schema *Schema
+	doc    *Document
+	errs   []*Error
+
+	fragmentScopes map[string]*selectionScope
+}
+
+// Validate checks a document against the schema and returns every validation error
+func Validate(schema *Schema, doc *Document) []*Error {
+	v := &validator{
+		schema:         schema,
+		doc:            doc,
+		fragmentScopes: make(map[string]*selectionScope),
+	}
+
+	v.validateOperationNames()
+	v.validateFragments()
+
+	for _, op := range doc.Operations {
+		v.validateOperation(op)
+	}
+
+	return v.errs
+}
+
+func (v *validator) report(err *Error) {
+	v.errs = append(v.errs, err)
+}
+
+func (v *validator) validateOperationNames() {
+	seen := make(map[string]bool)
+	anonymous := 0
+	for _, op := range v.doc.Operations {
+		if op.Name == "" {
+			anonymous++
+			continue
+		}
+		if seen[op.Name] {
+			v.report(validationError(op.Loc, "There can be only one operation named %q.", op.Name))
+		}
+		seen[op.Name] = true
+	}
+	if anonymous > 0 && len(v.doc.Operations) > 1 {
+		for _, op := range v.doc.Operations {
+			if op.Name == "" {
+				v.report(validationError(op.Loc, "This anonymous operation must be the only defined operation."))
+			}
+		}
+	}
+}
+
+func (v *validator) validateFragments() {
+	seen := make(map[string]bool)
+	for _, frag := range v.doc.Fragments {
+		if seen[frag.Name] {
+			v.report(validationError(frag.Loc, "There can be only one fragment named %q.", frag.Name))
+			continue
+		}
+		seen[frag.Name] = true
+
+		scope := &selectionScope{fragments: make(map[string]bool)}
+		v.fragmentScopes[frag.Name] = scope
+
+		t := v.schema.Type(frag.TypeCondition)
+		if t == nil {
+			v.report(validationError(frag.Loc, "Unknown type %q.", frag.TypeCondition))
+			continue
+		}
+		if !t.IsComposite() {
+			v.report(validationError(frag.Loc, "Fragment %q cannot condition on non composite type %q.", frag.Name, t.Name))
+			continue
+		}
+		v.validateDirectives(frag.Directives, "FRAGMENT_DEFINITION", scope)
+		v.validateSelectionSet(t, frag.SelectionSet, scope)
+	}
+
+	// Every fragment must be used and fragment spreads must not form cycles
+	used := make(map[string]bool)
+	for _, op := range v.doc.Operations {
+		scope := &selectionScope{fragments: make(map[string]bool)}
+		collectSpreads(op.SelectionSet, scope.fragments)
+		for name := range v.transitiveFragments(scope) {
+			used[name] = true
+		}
+	}
+	for _, frag := range v.doc.Fragments {
+		if !used[frag.Name] {
+			v.report(validationError(frag.Loc, "Fragment %q is never used.", frag.Name))
+		}
+	}
+
+	for _, frag := range v.doc.Fragments {
+		if v.fragmentReaches(frag.Name, frag.Name, make(map[string]bool)) {
+			v.report(validationError(frag.Loc, "Cannot spread fragment %q within itself.", frag.Name))
+		}
+	}
+}
+
+func collectSpreads(selections []Selection, into map[string]bool) {
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *Field:
+			collectSpreads(s.SelectionSet, into)
+		case *InlineFragment:
+			collectSpreads(s.SelectionSet, into)
+		case *FragmentSpread:
+			into[s.Name] = true
+		}
+	}
+}
+
+func (v *validator) fragmentReaches(from, target string, visited map[string]bool) bool {
+	frag := v.doc.Fragment(from)
+	if frag == nil || visited[from] {
+		return false
+	}
+	visited[from] = true
+
+	spreads := make(map[string]bool)
+	collectSpreads(frag.SelectionSet, spreads)
+	for name := range spreads {
+		if name == target || v.fragmentReaches(name, target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// transitiveFragments returns every fragment reachable from the scope
+func (v *validator) transitiveFragments(scope *selectionScope) map[string]bool {
+	result := make(map[string]bool)
+	queue := make([]string, 0, len(scope.fragments))
+	for name := range scope.fragments {
+		queue = append(queue, name)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if result[name] {
+			continue
+		}
+		result[name] = true
+		if frag := v.doc.Fragment(name); frag != nil {
+			spreads := make(map[string]bool)
+			collectSpreads(frag.SelectionSet, spreads)
+			for next := range spreads {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return result
+}
+
+func (v *validator) validateOperation(op *OperationDefinition) {
+	if op.Operation != "query" {
+		v.report(newError(CodeOperationNotSupported, []Location{op.Loc}, "Schema is not configured for %ss.", op.Operation))
+		return
+	}
+
+	root := v.schema.Type(v.schema.QueryType)
+	scope := &selectionScope{fragments: make(map[string]bool)}
+
+	defined := make(map[string]*VariableDefinition)
+	for _, def := range op.VariableDefinitions {
+		if defined[def.Name] != nil {
+			v.report(validationError(def.Loc, "There can be only one variable named \"$%s\".", def.Name))
+			continue
+		}
+		defined[def.Name] = def
+
+		t := v.schema.Type(def.Type.NamedType())
+		if t == nil {
+			v.report(validationError(def.Loc, "Unknown type %q.", def.Type.NamedType()))
+			continue
+		}
+		if !t.IsInput() {
+			v.report(validationError(def.Loc, "Variable \"$%s\" cannot be non-input type %q.", def.Name, def.Type))
+			continue
+		}
+		if def.DefaultValue != nil {
+			if msg := v.literalError(def.DefaultValue, def.Type); msg != "" {
+				v.report(validationError(def.DefaultValue.Loc, "Variable \"$%s\" has invalid default value: %s", def.Name, msg))
+			}
+		}
+	}
+
+	v.validateDirectives(op.Directives, "QUERY", scope)
+	v.validateSelectionSet(root, op.SelectionSet, scope)
+
+	usages := append([]variableUsage{}, scope.usages...)
+	for name := range v.transitiveFragments(scope) {
+		if fs := v.fragmentScopes[name]; fs != nil {
+			usages = append(usages, fs.usages...)
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, u := range usages {
+		used[u.name] = true
+		def := defined[u.name]
+		if def == nil {
+			if op.Name != "" {
+				v.report(validationError(u.loc, "Variable \"$%s\" is not defined by operation %q.", u.name, op.Name))
+			} else {
+				v.report(validationError(u.loc, "Variable \"$%s\" is not defined.", u.name))
+			}
+			continue
+		}
+		if !variableUsageAllowed(def, u) {
+			v.report(validationError(u.loc, "Variable \"$%s\" of type %q used in position expecting type %q.", u.name, def.Type, u.typ))
+		}
+	}
+	for _, def := range op.VariableDefinitions {
+		if !used[def.Name] {
+			v.report(validationError(def.Loc, "Variable \"$%s\" is never used.", def.Name))
+		}
+	}
+}
+
+func variableUsageAllowed(def *VariableDefinition, u variableUsage) bool {
+	locType := u.typ
+	if locType.NonNull && !def.Type.NonNull {
+		hasNonNullDefault := def.DefaultValue != nil && def.DefaultValue.Kind != NullValue
+		if !hasNonNullDefault && !u.hasDefault {
+			return false
+		}
+		locType = locType.Nullable()
+	}
+	return isSubType(def.Type, locType)
+}
+
+func isSubType(varType, locType *TypeRef) bool {
+	if locType.NonNull {
+		if !varType.NonNull {
+			return false
+		}
+		return isSubType(varType.Nullable(), locType.Nullable())
+	}
+	if varType.NonNull {
+		return isSubType(varType.Nullable(), locType)
+	}
+	if locType.Elem != nil {
+		return varType.Elem != nil && isSubType(varType.Elem, locType.Elem)
+	}
+	if varType.Elem != nil {
+		return false
+	}
+	return varType.Name == locType.Name
+}
+
+func (v *validator) validateSelectionSet(parent *Type, selections []Selection, scope *selectionScope) {
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *Field:
+			v.validateField(parent, s, scope)
+		case *InlineFragment:
+			v.validateDirectives(s.Directives, "INLINE_FRAGMENT", scope)
+			target := parent
+			if s.TypeCondition != "" {
+				target = v.schema.Type(s.TypeCondition)
+				if target == nil {
+					v.report(validationError(s.Loc, "Unknown type %q.", s.TypeCondition))
+					continue
+				}
+				if !target.IsComposite() {
+					v.report(validationError(s.Loc, "Fragment cannot condition on non composite type %q.", target.Name))
+					continue
+				}
+				if !v.typesOverlap(parent, target) {
+					v.report(validationError(s.Loc, "Fragment cannot be spread here as objects of type %q can never be of type %q.", parent.Name, target.Name))
+					continue
+				}
+			}
+			v.validateSelectionSet(target, s.SelectionSet, scope)
+		case *FragmentSpread:
+			v.validateDirectives(s.Directives, "FRAGMENT_SPREAD", scope)
+			frag := v.doc.Fragment(s.Name)
+			if frag == nil {
+				v.report(validationError(s.Loc, "Unknown fragment %q.", s.Name))
+				continue
+			}
+			scope.fragments[s.Name] = true
+			if target := v.schema.Type(frag.TypeCondition); target != nil && target.IsComposite() && !v.typesOverlap(parent, target) {
+				v.report(validationError(s.Loc, "Fragment %q cannot be spread here as objects of type %q can never be of type %q.", s.Name, parent.Name, target.Name))
+			}
+		}
+	}
+
+	v.validateFieldMerging(parent, selections)
+}
+
+func (v *validator) typesOverlap(a, b *Type) bool {
+	if a.Name == b.Name {
+		return true
+	}
+	for _, pa := range v.schema.PossibleTypes(a) {
+		for _, pb := range v.schema.PossibleTypes(b) {
+			if pa.Name == pb.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (v *validator) validateField(parent *Type, field *Field, scope *selectionScope) {
+	def := v.fieldDefinition(parent, field.Name)
+	if def == nil {
+		v.report(validationError(field.Loc, "Cannot query field %q on type %q.", field.Name, parent.Name))
+		return
+	}
+
+	v.validateArguments(field.Arguments, def.Args, field.Loc, "Field \""+field.Name+"\"", scope)
+	v.validateDirectives(field.Directives, "FIELD", scope)
+
+	fieldType := v.schema.Type(def.Type.NamedType())
+	switch {
+	case fieldType.IsLeaf() && len(field.SelectionSet) > 0:
+		v.report(validationError(field.Loc, "Field %q must not have a selection since type %q has no subfields.", field.Name, def.Type))
+	case fieldType.IsComposite() && len(field.SelectionSet) == 0:
+		v.report(validationError(field.Loc, "Field %q of type %q must have a selection of subfields. Did you mean \"%s { ... }\"?", field.Name, def.Type, field.Name))
+	case fieldType.IsComposite():
+		v.validateSelectionSet(fieldType, field.SelectionSet, scope)
+	}
+}
+
+// fieldDefinition resolves a field on a type, including the introspection meta-fields
+func (v *validator) fieldDefinition(parent *Type, name string) *FieldDefinition {
+	return fieldDefinition(v.schema, parent, name)
+}
+
+var (
+	typenameField = &FieldDefinition{Name: "__typename", Type: &TypeRef{Name: "String", NonNull: true}}
+	schemaField   = &FieldDefinition{Name: "__schema", Type: &TypeRef{Name: "__Schema", NonNull: true}}
+	typeField     = &FieldDefinition{
+		Name: "__type",
+		Type: &TypeRef{Name: "__Type"},
+		Args: []*InputValueDefinition{{Name: "name", Type: &TypeRef{Name: "String", NonNull: true}}},
+	}
+)
+
+func fieldDefinition(schema *Schema, parent *Type, name string) *FieldDefinition {
+	switch name {
+	case "__typename":
+		if parent.IsComposite() {
+			return typenameField
+		}
+	case "__schema":
+		if parent.Name == schema.QueryType {
+			return schemaField
+		}
+	case "__type":
+		if parent.Name == schema.QueryType {
+			return typeField
+		}
+	}
+	if parent.Kind == KindUnion {
+		return nil
+	}
+	return parent.Field(name)
+}
+
+func (v *validator) validateArguments(args []*Argument, defs []*InputValueDefinition, loc Location, owner string, scope *selectionScope) {
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		if seen[arg.Name] {
+			v.report(validationError(arg.Loc, "There can be only one argument named %q.", arg.Name))
+			continue
+		}
+		seen[arg.Name] = true
+
+		var def *InputValueDefinition
+		for _, d := range defs {
+			if d.Name == arg.Name {
+				def = d
+			}
+		}
+		if def == nil {
+			v.report(validationError(arg.Loc, "Unknown argument %q on %s.", arg.Name, strings.ToLower(owner[:1])+owner[1:]))
+			continue
+		}
+
+		if msg := v.literalError(arg.Value, def.Type); msg != "" {
+			v.report(validationError(arg.Value.Loc, "Argument %q has invalid value %s. %s", arg.Name, arg.Value, msg))
+		}
+		v.collectUsages(arg.Value, def.Type, def.DefaultValue != nil, scope)
+	}
+
+	for _, def := range defs {
+		if def.Type.NonNull && def.DefaultValue == nil && !seen[def.Name] {
+			v.report(validationError(loc, "%s argument %q of type %q is required, but it was not provided.", owner, def.Name, def.Type))
+		}
+	}
+}
+
+// collectUsages records variable references inside a value with the type expected at their position
+func (v *validator) collectUsages(value *Value, typ *TypeRef, hasDefault bool, scope *selectionScope) {
+	switch value.Kind {
+	case VariableValue:
+		scope.usages = append(scope.usages, variableUsage{name: value.Raw, typ: typ, hasDefault: hasDefault, loc: value.Loc})
+	case ListValue:
+		elem := typ.Nullable()
+		if elem.Elem != nil {
+			elem = elem.Elem
+		}
+		for _, item := range value.List {
+			v.collectUsages(item, elem, false, scope)
+		}
+	case ObjectValue:
+		t := v.schema.Type(typ.NamedType())
+		if t == nil || t.Kind != KindInputObject {
+			return
+		}
+		for _, f := range value.Fields {
+			if def := t.InputField(f.Name); def != nil {
+				v.collectUsages(f.Value, def.Type, def.DefaultValue != nil, scope)
+			}
+		}
+	}
+}
+
+func (v *validator) validateDirectives(dirs []*Directive, location string, scope *selectionScope) {
+	seen := make(map[string]bool)
+	for _, d := range dirs {
+		def := v.schema.Directive(d.Name)
+		if def == nil {
+			v.report(validationError(d.Loc, "Unknown directive \"@%s\".", d.Name))
+			continue
+		}
+		if seen[d.Name] {
+			v.report(validationError(d.Loc, "The directive \"@%s\" can only be used once at this location.", d.Name))
+		}
+		seen[d.Name] = true
+
+		allowed := false
+		for _, l := range def.Locations {
+			if l == location {
+				allowed = true
+			}
+		}
+		if !allowed {
+			v.report(validationError(d.Loc, "Directive \"@%s\" may not be used on %s.", d.Name, location))
+			continue
+		}
+		v.validateArguments(d.Arguments, def.Args, d.Loc, "Directive \"@"+d.Name+"\"", scope)
+	}
+}
+
+// literalError returns why a literal can't be coerced to the type, or "" if it can.
+// Variables are checked separately against their usage position.
+func (v *validator) literalError(value *Value, typ *TypeRef) string {
+	if value.Kind == VariableValue {
+		return ""
+	}
+	if typ.NonNull {
+		if value.Kind == NullValue {
+			return "Expected value of type \"" + typ.String() + "\", found null."
+		}
+		return v.literalError(value, typ.Nullable())
+	}
+	if value.Kind == NullValue {
+		return ""
+	}
+
+	if typ.Elem != nil {
+		if value.Kind != ListValue {
+			return v.literalError(value, typ.Elem)
+		}
+		for _, item := range value.List {
+			if msg := v.literalError(item, typ.Elem); msg != "" {
+				return msg
+			}
+		}
+		return ""
+	}
+
+	t := v.schema.Type(typ.Name)
+	if t == nil {
+		return "Unknown type \"" + typ.Name + "\"."
+	}
+	mismatch := "Expected value of type \"" + typ.String() + "\", found " + value.String() + "."
+
+	switch t.Kind {
+	case KindEnum:
+		if value.Kind != EnumValue || !t.HasEnumValue(value.Raw) {
+			return "Value " + value.String() + " does not exist in \"" + t.Name + "\" enum."
+		}
+	case KindInputObject:
+		if value.Kind != ObjectValue {
+			return mismatch
+		}
+		seen := make(map[string]bool)
+		for _, f := range value.Fields {
+			def := t.InputField(f.Name)
+			if def == nil {
+				return "Field \"" + f.Name + "\" is not defined by type \"" + t.Name + "\"."
+			}
+			if seen[f.Name] {
+				return "There can be only one input field named \"" + f.Name + "\"."
+			}
+			seen[f.Name] = true
+			if msg := v.literalError(f.Value, def.Type); msg != "" {
+				return msg
+			}
+		}
+		for _, def := range t.InputFields {
+			if def.Type.NonNull && def.DefaultValue == nil && !seen[def.Name] {
+				return "Field \"" + t.Name + "." + def.Name + "\" of required type \"" + def.Type.String() + "\" was not provided."
+			}
+		}
+	case KindScalar:
+		switch t.Name {
+		case "Int":
+			if value.Kind != IntValue {
+				return mismatch
+			}
+			n, err := strconv.ParseInt(value.Raw, 10, 64)
+			if err != nil || n > math.MaxInt32 || n < math.MinInt32 {
+				return "Int cannot represent non 32-bit signed integer value: " + value.String()
+			}
+		case "Float":
+			if value.Kind != IntValue && value.Kind != FloatValue {
+				return mismatch
+			}
+		case "String":
+			if value.Kind != StringValue {
+				return mismatch
+			}
+		case "Boolean":
+			if value.Kind != BooleanValue {
+				return mismatch
+			}
+		case "ID":
+			if value.Kind != StringValue && value.Kind != IntValue {
+				return mismatch
+			}
+		default:
+			if _, err := parseCustomScalarLiteral(t.Name, value); err != nil {
+				return err.Error()
+			}
+		}
+	}
+	return ""
+}
+
+// fieldRef is a field collected for the overlapping-fields check
+type fieldRef struct {
+	parent *Type
+	field  *Field
+}
+
+// validateFieldMerging reports fields sharing a response key that can't be merged:
+// different underlying fields or different arguments on the same parent type
+func (v *validator) validateFieldMerging(parent *Type, selections []Selection) {
+	byKey := make(map[string][]fieldRef)
+	var keys []string
+	v.collectFieldRefs(parent, selections, byKey, &keys, make(map[string]bool))
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		refs := byKey[key]
+		for i := 1; i < len(refs); i++ {
+			a, b := refs[0], refs[i]
+			if a.parent.Name != b.parent.Name && a.parent.Kind == KindObject && b.parent.Kind == KindObject {
+				continue
+			}
+			if a.field.Name != b.field.Name {
+				v.report(&Error{
+					Message:    "Fields \"" + key + "\" conflict because \"" + a.field.Name + "\" and \"" + b.field.Name + "\" are different fields. Use different aliases on the fields to fetch both if this was intentional.",
+					Locations:  []Location{a.field.Loc, b.field.Loc},
+					Extensions: map[string]interface{}{"code": CodeValidationFailed},
+				})
+				break
+			}
+			if argumentsKey(a.field.Arguments) != argumentsKey(b.field.Arguments) {
+				v.report(&Error{
+					Message:    "Fields \"" + key + "\" conflict because they have differing arguments. Use different aliases on the fields to fetch both if this was intentional.",
+					Locations:  []Location{a.field.Loc, b.field.Loc},
+					Extensions: map[string]interface{}{"code": CodeValidationFailed},
+				})
+				break
+			}
+		}
+	}
+}
+
+func (v *validator) collectFieldRefs(parent *Type, selections []Selection, byKey map[string][]fieldRef, keys *[]string, visited map[string]bool) {
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *Field:
+			key := s.ResponseKey()
+			if _, ok := byKey[key]; !ok {
+				*keys = append(*keys, key)
+			}
+			byKey[key] = append(byKey[key], fieldRef{parent: parent, field: s})
+		case *InlineFragment:
+			target := parent
+			if s.TypeCondition != "" {
+				if target = v.schema.Type(s.TypeCondition); target == nil {
+					continue
+				}
+			}
+			v.collectFieldRefs(target, s.SelectionSet, byKey, keys, visited)
+		case *FragmentSpread:
+			if visited[s.Name] {
+				continue
+			}
+			visited[s.Name] = true
+			frag := v.doc.Fragment(s.Name)
+			if frag == nil {
+				continue
+			}
+			if target := v.schema.Type(frag.TypeCondition); target != nil {
+				v.collectFieldRefs(target, frag.SelectionSet, byKey, keys, visited)
+			}
+		}
+	}
+}
+
+func argumentsKey(args []*Argument) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = a.Name + ":" + a.Value.String()
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,466 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Input values are coerced to plain Go values before reaching resolvers:
+// Int -> int, Float -> float64, String/ID/enum -> string, Boolean -> bool, Time -> time.Time,
+// lists -> []interface{}, input objects -> map[string]interface{}.
+
+// coerceVariables coerces JSON request variables against the operation's declarations
+func coerceVariables(schema *Schema, op *OperationDefinition, raw map[string]interface{}) (map[string]interface{}, *Error) {
+	coerced := make(map[string]interface{})
+	for _, def := range op.VariableDefinitions {
+		value, provided := raw[def.Name]
+
+		if !provided {
+			if def.DefaultValue != nil {
+				v, err := valueFromLiteral(schema, def.Type, def.DefaultValue, nil)
+				if err != nil {
+					return nil, newError(CodeBadUserInput, []Location{def.Loc}, "Variable \"$%s\" got invalid default value: %s", def.Name, err)
+				}
+				coerced[def.Name] = v
+				continue
+			}
+			if def.Type.NonNull {
+				return nil, newError(CodeBadUserInput, []Location{def.Loc}, "Variable \"$%s\" of required type %q was not provided.", def.Name, def.Type)
+			}
+			continue
+		}
+
+		v, err := coerceInputValue(schema, def.Type, value)
+		if err != nil {
+			return nil, newError(CodeBadUserInput, []Location{def.Loc}, "Variable \"$%s\" got invalid value %s; %s", def.Name, jsonString(value), err)
+		}
+		coerced[def.Name] = v
+	}
+	return coerced, nil
+}
+
+func jsonString(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// coerceInputValue coerces a JSON-decoded value to the input type
+func coerceInputValue(schema *Schema, typ *TypeRef, value interface{}) (interface{}, error) {
+	if typ.NonNull {
+		if value == nil {
+			return nil, fmt.Errorf("Expected non-nullable type %q not to be null.", typ)
+		}
+		return coerceInputValue(schema, typ.Nullable(), value)
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	if typ.Elem != nil {
+		list, ok := value.([]interface{})
+		if !ok {
+			item, err := coerceInputValue(schema, typ.Elem, value)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{item}, nil
+		}
+		result := make([]interface{}, len(list))
+		for i, item := range list {
+			v, err := coerceInputValue(schema, typ.Elem, item)
+			if err != nil {
+				return nil, fmt.Errorf("at index %d: %w", i, err)
+			}
+			result[i] = v
+		}
+		return result, nil
+	}
+
+	t := schema.Type(typ.Name)
+	if t == nil {
+		return nil, fmt.Errorf("Unknown type %q.", typ.Name)
+	}
+
+	switch t.Kind {
+	case KindEnum:
+		s, ok := value.(string)
+		if !ok || !t.HasEnumValue(s) {
+			return nil, fmt.Errorf("Value %s does not exist in %q enum.", jsonString(value), t.Name)
+		}
+		return s, nil
+	case KindInputObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Expected type %q to be an object.", t.Name)
+		}
+		for name := range obj {
+			if t.InputField(name) == nil {
+				return nil, fmt.Errorf("Field %q is not defined by type %q.", name, t.Name)
+			}
+		}
+		result := make(map[string]interface{})
+		for _, def := range t.InputFields {
+			fv, provided := obj[def.Name]
+			if !provided {
+				if def.DefaultValue != nil {
+					v, err := valueFromLiteral(schema, def.Type, def.DefaultValue, nil)
+					if err != nil {
+						return nil, err
+					}
+					result[def.Name] = v
+				} else if def.Type.NonNull {
+					return nil, fmt.Errorf("Field %q of required type %q was not provided.", def.Name, def.Type)
+				}
+				continue
+			}
+			v, err := coerceInputValue(schema, def.Type, fv)
+			if err != nil {
+				return nil, fmt.Errorf("at %q: %w", def.Name, err)
+			}
+			result[def.Name] = v
+		}
+		return result, nil
+	case KindScalar:
+		return coerceScalarInput(t.Name, value)
+	}
+	return nil, fmt.Errorf("%q is not an input type.", t.Name)
+}
+
+func coerceScalarInput(name string, value interface{}) (interface{}, error) {
+	switch name {
+	case "Int":
+		f, ok := numberValue(value)
+		if !ok || f != math.Trunc(f) || f > math.MaxInt32 || f < math.MinInt32 {
+			return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %s", jsonString(value))
+		}
+		return int(f), nil
+	case "Float":
+		f, ok := numberValue(value)
+		if !ok {
+			return nil, fmt.Errorf("Float cannot represent non numeric value: %s", jsonString(value))
+		}
+		return f, nil
+	case "String":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("String cannot represent a non string value: %s", jsonString(value))
+		}
+		return s, nil
+	case "Boolean":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %s", jsonString(value))
+		}
+		return b, nil
+	case "ID":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		if f, ok := numberValue(value); ok && f == math.Trunc(f) {
+			return strconv.FormatInt(int64(f), 10), nil
+		}
+		return nil, fmt.Errorf("ID cannot represent value: %s", jsonString(value))
+	case "Time":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("Time must be an RFC 3339 string")
+		}
+		return parseTime(s)
+	}
+	// Unknown custom scalars are passed through as decoded
+	return value, nil
+}
+
+func numberValue(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func parseTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Time must be an RFC 3339 string: %q", s)
+	}
+	return t, nil
+}
+
+// parseCustomScalarLiteral validates a literal for a non-builtin scalar
+func parseCustomScalarLiteral(name string, value *Value) (interface{}, error) {
+	switch name {
+	case "Time":
+		if value.Kind != StringValue {
+			return nil, fmt.Errorf("Time must be an RFC 3339 string, found %s.", value)
+		}
+		return parseTime(value.Raw)
+	}
+	return literalToGo(value), nil
+}
+
+func literalToGo(value *Value) interface{} {
+	switch value.Kind {
+	case IntValue:
+		n, _ := strconv.Atoi(value.Raw)
+		return n
+	case FloatValue:
+		f, _ := strconv.ParseFloat(value.Raw, 64)
+		return f
+	case BooleanValue:
+		return value.Raw == "true"
+	case NullValue:
+		return nil
+	case ListValue:
+		list := make([]interface{}, len(value.List))
+		for i, item := range value.List {
+			list[i] = literalToGo(item)
+		}
+		return list
+	case ObjectValue:
+		obj := make(map[string]interface{})
+		for _, f := range value.Fields {
+			obj[f.Name] = literalToGo(f.Value)
+		}
+		return obj
+	default:
+		return value.Raw
+	}
+}
+
+// valueFromLiteral coerces a validated literal (with variables resolved from vars) to a Go value
+func valueFromLiteral(schema *Schema, typ *TypeRef, value *Value, vars map[string]interface{}) (interface{}, error) {
+	if value.Kind == VariableValue {
+		v, ok := vars[value.Raw]
+		if !ok || v == nil {
+			if typ.NonNull {
+				return nil, fmt.Errorf("Variable \"$%s\" of non-null type must not be null.", value.Raw)
+			}
+			return nil, nil
+		}
+		return v, nil
+	}
+
+	if typ.NonNull {
+		if value.Kind == NullValue {
+			return nil, fmt.Errorf("Expected non-null value of type %q.", typ)
+		}
+		return valueFromLiteral(schema, typ.Nullable(), value, vars)
+	}
+	if value.Kind == NullValue {
+		return nil, nil
+	}
+
+	if typ.Elem != nil {
+		if value.Kind != ListValue {
+			item, err := valueFromLiteral(schema, typ.Elem, value, vars)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{item}, nil
+		}
+		list := make([]interface{}, len(value.List))
+		for i, item := range value.List {
+			v, err := valueFromLiteral(schema, typ.Elem, item, vars)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	}
+
+	t := schema.Type(typ.Name)
+	if t == nil {
+		return nil, fmt.Errorf("Unknown type %q.", typ.Name)
+	}
+
+	switch t.Kind {
+	case KindEnum:
+		if value.Kind != EnumValue || !t.HasEnumValue(value.Raw) {
+			return nil, fmt.Errorf("Value %s does not exist in %q enum.", value, t.Name)
+		}
+		return value.Raw, nil
+	case KindInputObject:
+		if value.Kind != ObjectValue {
+			return nil, fmt.Errorf("Expected value of type %q, found %s.", t.Name, value)
+		}
+		result := make(map[string]interface{})
+		for _, def := range t.InputFields {
+			var field *ObjectField
+			for _, f := range value.Fields {
+				if f.Name == def.Name {
+					field = f
+				}
+			}
+			if field == nil || (field.Value.Kind == VariableValue && !hasVariable(vars, field.Value.Raw)) {
+				if def.DefaultValue != nil {
+					v, err := valueFromLiteral(schema, def.Type, def.DefaultValue, nil)
+					if err != nil {
+						return nil, err
+					}
+					result[def.Name] = v
+				} else if def.Type.NonNull {
+					return nil, fmt.Errorf("Field %q of required type %q was not provided.", def.Name, def.Type)
+				}
+				continue
+			}
+			v, err := valueFromLiteral(schema, def.Type, field.Value, vars)
+			if err != nil {
+				return nil, err
+			}
+			result[def.Name] = v
+		}
+		return result, nil
+	case KindScalar:
+		switch t.Name {
+		case "Int":
+			n, err := strconv.ParseInt(value.Raw, 10, 32)
+			if value.Kind != IntValue || err != nil {
+				return nil, fmt.Errorf("Int cannot represent value: %s", value)
+			}
+			return int(n), nil
+		case "Float":
+			f, err := strconv.ParseFloat(value.Raw, 64)
+			if (value.Kind != IntValue && value.Kind != FloatValue) || err != nil {
+				return nil, fmt.Errorf("Float cannot represent value: %s", value)
+			}
+			return f, nil
+		case "String":
+			if value.Kind != StringValue {
+				return nil, fmt.Errorf("String cannot represent value: %s", value)
+			}
+			return value.Raw, nil
+		case "Boolean":
+			if value.Kind != BooleanValue {
+				return nil, fmt.Errorf("Boolean cannot represent value: %s", value)
+			}
+			return value.Raw == "true", nil
+		case "ID":
+			if value.Kind != StringValue && value.Kind != IntValue {
+				return nil, fmt.Errorf("ID cannot represent value: %s", value)
+			}
+			return value.Raw, nil
+		default:
+			return parseCustomScalarLiteral(t.Name, value)
+		}
+	}
+	return nil, fmt.Errorf("%q is not an input type.", t.Name)
+}
+
+func hasVariable(vars map[string]interface{}, name string) bool {
+	_, ok := vars[name]
+	return ok
+}
+
+// coerceArguments builds the argument map for a field or directive. Arguments that are
+// omitted and have no default are left out; explicit nulls are present with a nil value.
+func coerceArguments(schema *Schema, defs []*InputValueDefinition, args []*Argument, vars map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for _, def := range defs {
+		var arg *Argument
+		for _, a := range args {
+			if a.Name == def.Name {
+				arg = a
+			}
+		}
+
+		if arg == nil || (arg.Value.Kind == VariableValue && !hasVariable(vars, arg.Value.Raw)) {
+			if def.DefaultValue != nil {
+				v, err := valueFromLiteral(schema, def.Type, def.DefaultValue, nil)
+				if err != nil {
+					return nil, err
+				}
+				result[def.Name] = v
+			} else if def.Type.NonNull {
+				return nil, fmt.Errorf("Argument %q of required type %q was not provided.", def.Name, def.Type)
+			}
+			continue
+		}
+
+		v, err := valueFromLiteral(schema, def.Type, arg.Value, vars)
+		if err != nil {
+			return nil, fmt.Errorf("Argument %q has invalid value %s: %w", def.Name, arg.Value, err)
+		}
+		result[def.Name] = v
+	}
+	return result, nil
+}
+
+// serializeScalar converts a resolved Go value to its JSON output for a scalar type
+func serializeScalar(name string, value interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(value)
+
+	switch name {
+	case "Int":
+		var n int64
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = rv.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n = int64(rv.Uint())
+		case reflect.Float32, reflect.Float64:
+			f := rv.Float()
+			if f != math.Trunc(f) {
+				return nil, fmt.Errorf("Int cannot represent non-integer value: %v", value)
+			}
+			n = int64(f)
+		default:
+			return nil, fmt.Errorf("Int cannot represent value: %v", value)
+		}
+		if n > math.MaxInt32 || n < math.MinInt32 {
+			return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %d", n)
+		}
+		return n, nil
+	case "Float":
+		switch rv.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return rv.Float(), nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return float64(rv.Int()), nil
+		}
+		return nil, fmt.Errorf("Float cannot represent value: %v", value)
+	case "String", "ID":
+		switch rv.Kind() {
+		case reflect.String:
+			return rv.String(), nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if name == "ID" {
+				return strconv.FormatInt(rv.Int(), 10), nil
+			}
+		}
+		if s, ok := value.(fmt.Stringer); ok {
+			return s.String(), nil
+		}
+		return nil, fmt.Errorf("%s cannot represent value: %v", name, value)
+	case "Boolean":
+		if rv.Kind() == reflect.Bool {
+			return rv.Bool(), nil
+		}
+		return nil, fmt.Errorf("Boolean cannot represent value: %v", value)
+	case "Time":
+		if t, ok := value.(time.Time); ok {
+			return t.Format(time.RFC3339Nano), nil
+		}
+		return nil, fmt.Errorf("Time cannot represent value: %v", value)
+	}
+	return value, nil
+}
@@ -1,20 +1,64 @@
 package graphql
 
 import (
+	_ "embed"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/graphql/executor"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/middleware"
 )
 
+//go:embed schema.graphql
+var schemaSDL string
+
+// Default execution limits. Introspection fields don't count towards either limit.
+var DefaultLimits = executor.Limits{
+	MaxDepth:      10,
+	MaxComplexity: 5000, // subscriptionsConnection(first: 250) with every node field fits
+}
+
+const maxRequestBodyBytes = 1 << 20
+
 // Handler handles GraphQL requests for the Revenue API
 type Handler struct {
-	resolver *Resolver
+	executor         *executor.Executor
+	persistedQueries executor.PersistedQueryStore
 }
 
-// NewHandler creates a new GraphQL handler
+// NewHandler creates a new GraphQL handler executing schema.graphql against the resolver.
+// Panics if the schema and resolvers don't match, which the package tests guard against.
 func NewHandler(resolver *Resolver) *Handler {
-	return &Handler{resolver: resolver}
+	exec, err := newExecutor(resolver, DefaultLimits)
+	if err != nil {
+		panic(fmt.Sprintf("graphql: %v", err))
+	}
+	return &Handler{
+		executor:         exec,
+		persistedQueries: executor.NewMemoryPersistedQueryStore(1000),
+	}
+}
+
+func newExecutor(resolver *Resolver, limits executor.Limits) (*executor.Executor, error) {
+	schema, err := executor.ParseSchema(schemaSDL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema.graphql: %w", err)
+	}
+	return executor.New(schema, resolver.rootResolvers(), limits)
+}
+
+// WithLimits replaces the default depth and complexity limits
+func (h *Handler) WithLimits(limits executor.Limits) *Handler {
+	h.executor = h.executor.WithLimits(limits)
+	return h
+}
+
+// WithPersistedQueryStore replaces the in-memory persisted query store (e.g. with a shared one)
+func (h *Handler) WithPersistedQueryStore(store executor.PersistedQueryStore) *Handler {
+	h.persistedQueries = store
+	return h
 }
 
 // GraphQLRequest represents an incoming GraphQL request
@@ -22,16 +66,13 @@ type GraphQLRequest struct {
 	Query         string                 `json:"query"`
 	OperationName string                 `json:"operationName,omitempty"`
 	Variables     map[string]interface{} `json:"variables,omitempty"`
-}
-
-// GraphQLResponse represents a GraphQL response
-type GraphQLResponse struct {
-	Data   interface{}     `json:"data,omitempty"`
-	Errors []GraphQLError  `json:"errors,omitempty"`
+	Extensions    struct {
+		PersistedQuery *executor.PersistedQuery `json:"persistedQuery,omitempty"`
+	} `json:"extensions,omitempty"`
 }
 
 // ServeHTTP handles GraphQL requests
-// POST /graphql
+// POST /graphql (JSON body), GET /graphql?query=...&variables=...&extensions=... (for persisted queries)
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Check authentication
 	apiKey := middleware.APIKeyFromContext(r.Context())
@@ -40,60 +81,75 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Only accept POST
-	if r.Method != http.MethodPost {
-		writeGraphQLError(w, http.StatusMethodNotAllowed, "only POST is allowed", "METHOD_NOT_ALLOWED")
+	var req GraphQLRequest
+	switch r.Method {
+	case http.MethodPost:
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+		decoder.UseNumber()
+		if err := decoder.Decode(&req); err != nil {
+			writeGraphQLError(w, http.StatusBadRequest, "invalid request body", "INVALID_REQUEST")
+			return
+		}
+	case http.MethodGet:
+		if err := parseGetRequest(r, &req); err != nil {
+			writeGraphQLError(w, http.StatusBadRequest, err.Error(), "INVALID_REQUEST")
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeGraphQLError(w, http.StatusMethodNotAllowed, "only GET and POST are allowed", "METHOD_NOT_ALLOWED")
 		return
 	}
 
-	// Parse request
-	var req GraphQLRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeGraphQLError(w, http.StatusBadRequest, "invalid request body", "INVALID_REQUEST")
+	query, pqErr := executor.ResolvePersistedQuery(r.Context(), h.persistedQueries, req.Query, req.Extensions.PersistedQuery)
+	if pqErr != nil {
+		writeResponse(w, &executor.Response{Errors: []*executor.Error{pqErr}})
 		return
 	}
-
-	if req.Query == "" {
+	if strings.TrimSpace(query) == "" {
 		writeGraphQLError(w, http.StatusBadRequest, "query is required", "INVALID_REQUEST")
 		return
 	}
 
-	// Execute query using our simple resolver
-	// Note: For a full implementation, you would use gqlgen's generated executor
-	// This is a simplified version that demonstrates the pattern
-	result, err := h.executeQuery(r.Context(), req)
-	if err != nil {
-		writeGraphQLError(w, http.StatusInternalServerError, err.Error(), "INTERNAL_ERROR")
-		return
-	}
+	resp := h.executor.Execute(r.Context(), executor.Request{
+		Query:         query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+	})
+	writeResponse(w, resp)
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(GraphQLResponse{Data: result})
+func parseGetRequest(r *http.Request, req *GraphQLRequest) error {
+	params := r.URL.Query()
+	req.Query = params.Get("query")
+	req.OperationName = params.Get("operationName")
+
+	if v := params.Get("variables"); v != "" {
+		decoder := json.NewDecoder(strings.NewReader(v))
+		decoder.UseNumber()
+		if err := decoder.Decode(&req.Variables); err != nil {
+			return fmt.Errorf("invalid variables parameter")
+		}
+	}
+	if ext := params.Get("extensions"); ext != "" {
+		if err := json.Unmarshal([]byte(ext), &req.Extensions); err != nil {
+			return fmt.Errorf("invalid extensions parameter")
+		}
+	}
+	return nil
 }
 
-// executeQuery executes a GraphQL query
-// This is a simplified implementation - a full implementation would use gqlgen
-func (h *Handler) executeQuery(ctx interface{}, req GraphQLRequest) (interface{}, error) {
-	// For now, return a helpful message
-	// Full gqlgen integration would parse and execute the query
-	return map[string]interface{}{
-		"_info": "GraphQL endpoint active. Use gqlgen for full query parsing.",
-		"_schema": map[string]interface{}{
-			"queries": []string{
-				"subscription(shopifyGid: ID!): SubscriptionStatus",
-				"subscriptionByDomain(domain: String!): SubscriptionStatus",
-				"subscriptions(shopifyGids: [ID!]!): SubscriptionBatchResult",
-				"usage(shopifyGid: ID!): UsageStatus",
-				"usages(shopifyGids: [ID!]!): UsageBatchResult",
-			},
-		},
-	}, nil
+// writeResponse writes a GraphQL result. Request and field errors use 200 as
+// recommended for application/json responses, so clients always parse "errors".
+func writeResponse(w http.ResponseWriter, resp *executor.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
 }
 
 func writeGraphQLError(w http.ResponseWriter, status int, message string, code string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(GraphQLResponse{
-		Errors: []GraphQLError{{Message: message, Code: code}},
+	json.NewEncoder(w).Encode(&executor.Response{
+		Errors: []*executor.Error{{Message: message, Extensions: map[string]interface{}{"code": code}}},
 	})
 }
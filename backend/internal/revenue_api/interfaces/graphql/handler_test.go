@@ -0,0 +1,503 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	coreentity "github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	revrepo "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/graphql/executor"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/middleware"
+)
+
+var errNotFound = errors.New("not found")
+
+type mockStatusRepo struct {
+	statuses map[string]*entity.SubscriptionStatus
+}
+
+func (m *mockStatusRepo) Upsert(ctx context.Context, status *entity.SubscriptionStatus) error {
+	return nil
+}
+
+func (m *mockStatusRepo) UpsertBatch(ctx context.Context, statuses []*entity.SubscriptionStatus) error {
+	return nil
+}
+
+func (m *mockStatusRepo) GetByShopifyGID(ctx context.Context, shopifyGID string) (*entity.SubscriptionStatus, error) {
+	if s, ok := m.statuses[shopifyGID]; ok {
+		return s, nil
+	}
+	return nil, errNotFound
+}
+
+func (m *mockStatusRepo) GetByShopifyGIDs(ctx context.Context, shopifyGIDs []string) ([]*entity.SubscriptionStatus, error) {
+	var result []*entity.SubscriptionStatus
+	for _, gid := range shopifyGIDs {
+		if s, ok := m.statuses[gid]; ok {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockStatusRepo) GetByDomain(ctx context.Context, appID uuid.UUID, domain string) (*entity.SubscriptionStatus, error) {
+	for _, s := range m.statuses {
+		if s.AppID == appID && s.MyshopifyDomain == domain {
+			return s, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+func (m *mockStatusRepo) GetByDomains(ctx context.Context, appID uuid.UUID, domains []string) ([]*entity.SubscriptionStatus, error) {
+	return nil, nil
+}
+
+func (m *mockStatusRepo) GetByAppID(ctx context.Context, appID uuid.UUID) ([]*entity.SubscriptionStatus, error) {
+	return nil, nil
+}
+
+func (m *mockStatusRepo) GetByAppIDAndRiskState(ctx context.Context, appID uuid.UUID, riskState valueobject.RiskState) ([]*entity.SubscriptionStatus, error) {
+	return nil, nil
+}
+
+func (m *mockStatusRepo) FindPage(ctx context.Context, query revrepo.SubscriptionStatusPageQuery) ([]*entity.SubscriptionStatus, error) {
+	var result []*entity.SubscriptionStatus
+	for _, s := range m.statuses {
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (m *mockStatusRepo) Count(ctx context.Context, appIDs []uuid.UUID, filter revrepo.SubscriptionStatusFilter) (int, error) {
+	return len(m.statuses), nil
+}
+
+func (m *mockStatusRepo) DeleteByShopifyGIDs(ctx context.Context, shopifyGIDs []string) error {
+	return nil
+}
+
+func (m *mockStatusRepo) DeleteByAppID(ctx context.Context, appID uuid.UUID) error {
+	return nil
+}
+
+type mockUsageRepo struct{}
+
+func (m *mockUsageRepo) Upsert(ctx context.Context, status *entity.UsageStatus) error { return nil }
+
+func (m *mockUsageRepo) UpsertBatch(ctx context.Context, statuses []*entity.UsageStatus) error {
+	return nil
+}
+
+func (m *mockUsageRepo) GetByShopifyGID(ctx context.Context, shopifyGID string) (*entity.UsageStatus, error) {
+	return nil, errNotFound
+}
+
+func (m *mockUsageRepo) GetByShopifyGIDs(ctx context.Context, shopifyGIDs []string) ([]*entity.UsageStatus, error) {
+	return nil, nil
+}
+
+func (m *mockUsageRepo) GetBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*entity.UsageStatus, error) {
+	return nil, nil
+}
+
+func (m *mockUsageRepo) GetBySubscriptionShopifyGID(ctx context.Context, subscriptionShopifyGID string) ([]*entity.UsageStatus, error) {
+	return nil, nil
+}
+
+func (m *mockUsageRepo) GetUnbilledBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*entity.UsageStatus, error) {
+	return nil, nil
+}
+
+func (m *mockUsageRepo) GetReportedByAppID(ctx context.Context, appID uuid.UUID, from, to time.Time) ([]*entity.UsageStatus, error) {
+	return nil, nil
+}
+
+func (m *mockUsageRepo) DeleteBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) error {
+	return nil
+}
+
+func (m *mockUsageRepo) DeleteByShopifyGIDs(ctx context.Context, shopifyGIDs []string) error {
+	return nil
+}
+
+type mockAppRepo struct {
+	app *coreentity.App
+}
+
+func (m *mockAppRepo) Create(ctx context.Context, app *coreentity.App) error { return nil }
+
+func (m *mockAppRepo) FindByID(ctx context.Context, id uuid.UUID) (*coreentity.App, error) {
+	if m.app.ID == id {
+		return m.app, nil
+	}
+	return nil, errNotFound
+}
+
+func (m *mockAppRepo) FindByPartnerAccountID(ctx context.Context, partnerAccountID uuid.UUID) ([]*coreentity.App, error) {
+	if m.app.PartnerAccountID == partnerAccountID {
+		return []*coreentity.App{m.app}, nil
+	}
+	return nil, nil
+}
+
+func (m *mockAppRepo) FindByPartnerAppID(ctx context.Context, partnerAccountID uuid.UUID, partnerAppID string) (*coreentity.App, error) {
+	return nil, errNotFound
+}
+
+func (m *mockAppRepo) FindAllByPartnerAppID(ctx context.Context, partnerAppID string) ([]*coreentity.App, error) {
+	return nil, nil
+}
+
+func (m *mockAppRepo) Update(ctx context.Context, app *coreentity.App) error { return nil }
+
+func (m *mockAppRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
+
+type mockPartnerRepo struct {
+	account *coreentity.PartnerAccount
+}
+
+func (m *mockPartnerRepo) Create(ctx context.Context, account *coreentity.PartnerAccount) error {
+	return nil
+}
+
+func (m *mockPartnerRepo) FindByID(ctx context.Context, id uuid.UUID) (*coreentity.PartnerAccount, error) {
+	return m.account, nil
+}
+
+func (m *mockPartnerRepo) FindByUserID(ctx context.Context, userID uuid.UUID) (*coreentity.PartnerAccount, error) {
+	if m.account.UserID == userID {
+		return m.account, nil
+	}
+	return nil, errNotFound
+}
+
+func (m *mockPartnerRepo) FindByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (*coreentity.PartnerAccount, error) {
+	if m.account.WorkspaceID != nil && *m.account.WorkspaceID == workspaceID {
+		return m.account, nil
+	}
+	return nil, errNotFound
+}
+
+func (m *mockPartnerRepo) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]*coreentity.PartnerAccount, error) {
+	if m.account.UserID == userID {
+		return []*coreentity.PartnerAccount{m.account}, nil
+	}
+	return nil, nil
+}
+
+func (m *mockPartnerRepo) FindByPartnerID(ctx context.Context, partnerID string) (*coreentity.PartnerAccount, error) {
+	return nil, errNotFound
+}
+
+func (m *mockPartnerRepo) Update(ctx context.Context, account *coreentity.PartnerAccount) error {
+	return nil
+}
+
+func (m *mockPartnerRepo) Delete(ctx context.Context, userID uuid.UUID) error { return nil }
+
+func (m *mockPartnerRepo) GetAllIDs(ctx context.Context) ([]uuid.UUID, error) { return nil, nil }
+
+type testEnv struct {
+	handler *Handler
+	userID  uuid.UUID
+	access  *service.APIKeyAccess // Scopes and apps of the calling key; nil = unrestricted
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	userID := uuid.New()
+	account := &coreentity.PartnerAccount{ID: uuid.New(), UserID: userID}
+	app := &coreentity.App{ID: uuid.New(), PartnerAccountID: account.ID, PartnerAppID: "gid://partners/App/42"}
+
+	status := &entity.SubscriptionStatus{
+		ID:              uuid.New(),
+		ShopifyGID:      "gid://shopify/AppSubscription/1",
+		AppID:           app.ID,
+		MyshopifyDomain: "store.myshopify.com",
+		PlanName:        "Pro",
+		RiskState:       valueobject.RiskStateOneCycleMissed,
+		MonthsOverdue:   1,
+		Status:          "ACTIVE",
+	}
+
+	statusRepo := &mockStatusRepo{statuses: map[string]*entity.SubscriptionStatus{status.ShopifyGID: status}}
+	appRepo := &mockAppRepo{app: app}
+	partnerRepo := &mockPartnerRepo{account: account}
+
+	resolver := NewResolver(
+		service.NewSubscriptionStatusService(statusRepo, appRepo, partnerRepo),
+		service.NewUsageStatusService(&mockUsageRepo{}, statusRepo, appRepo, partnerRepo),
+	)
+	return &testEnv{handler: NewHandler(resolver), userID: userID}
+}
+
+func (e *testEnv) do(t *testing.T, req *http.Request) (int, map[string]interface{}) {
+	t.Helper()
+
+	ctx := middleware.SetAPIKeyContext(req.Context(), &middleware.ValidatedAPIKey{ID: uuid.New(), UserID: e.userID})
+	if e.access != nil {
+		ctx = service.WithAPIKeyAccess(ctx, e.access)
+	}
+	req = req.WithContext(ctx)
+	rec := httptest.NewRecorder()
+	e.handler.ServeHTTP(rec, req)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, body
+}
+
+func (e *testEnv) post(t *testing.T, payload string) (int, map[string]interface{}) {
+	t.Helper()
+	return e.do(t, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(payload)))
+}
+
+func firstErrorCode(t *testing.T, body map[string]interface{}) string {
+	t.Helper()
+
+	errs, _ := body["errors"].([]interface{})
+	if len(errs) == 0 {
+		t.Fatalf("expected errors, got %v", body)
+	}
+	ext, _ := errs[0].(map[string]interface{})["extensions"].(map[string]interface{})
+	code, _ := ext["code"].(string)
+	return code
+}
+
+func TestNewHandler_SchemaMatchesResolvers(t *testing.T) {
+	schema, err := executor.ParseSchema(schemaSDL)
+	if err != nil {
+		t.Fatalf("schema.graphql does not parse: %v", err)
+	}
+
+	roots := (&Resolver{}).rootResolvers()
+	for _, field := range schema.Types[schema.QueryType].Fields {
+		if _, ok := roots[field.Name]; !ok {
+			t.Errorf("Query.%s has no resolver", field.Name)
+		}
+	}
+	if len(roots) != len(schema.Types[schema.QueryType].Fields) {
+		t.Errorf("expected %d resolvers, got %d", len(schema.Types[schema.QueryType].Fields), len(roots))
+	}
+}
+
+func TestServeHTTP_RequiresAPIKey(t *testing.T) {
+	env := newTestEnv(t)
+
+	rec := httptest.NewRecorder()
+	env.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{ __typename }"}`)))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestServeHTTP_Subscription(t *testing.T) {
+	env := newTestEnv(t)
+
+	code, body := env.post(t, `{"query":"query($gid: ID!) { subscription(shopifyGid: $gid) { subscriptionId planName riskState monthsOverdue status lastSuccessfulChargeDate } }","variables":{"gid":"gid://shopify/AppSubscription/1"}}`)
+
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if _, ok := body["errors"]; ok {
+		t.Fatalf("unexpected errors: %v", body["errors"])
+	}
+	sub := body["data"].(map[string]interface{})["subscription"].(map[string]interface{})
+	if sub["subscriptionId"] != "gid://shopify/AppSubscription/1" {
+		t.Errorf("unexpected subscriptionId %v", sub["subscriptionId"])
+	}
+	if sub["riskState"] != "ONE_CYCLE_MISSED" {
+		t.Errorf("expected ONE_CYCLE_MISSED, got %v", sub["riskState"])
+	}
+	if sub["monthsOverdue"] != float64(1) {
+		t.Errorf("expected monthsOverdue 1, got %v", sub["monthsOverdue"])
+	}
+	if v, ok := sub["lastSuccessfulChargeDate"]; !ok || v != nil {
+		t.Errorf("expected null lastSuccessfulChargeDate, got %v", v)
+	}
+}
+
+func TestServeHTTP_SubscriptionNotFound(t *testing.T) {
+	env := newTestEnv(t)
+
+	_, body := env.post(t, `{"query":"{ subscription(shopifyGid: \"gid://shopify/AppSubscription/999\") { subscriptionId } }"}`)
+
+	data := body["data"].(map[string]interface{})
+	if v, ok := data["subscription"]; !ok || v != nil {
+		t.Errorf("expected null subscription, got %v", v)
+	}
+	if code := firstErrorCode(t, body); code != "NOT_FOUND" {
+		t.Errorf("expected NOT_FOUND, got %s", code)
+	}
+	path := body["errors"].([]interface{})[0].(map[string]interface{})["path"].([]interface{})
+	if len(path) != 1 || path[0] != "subscription" {
+		t.Errorf("expected path [subscription], got %v", path)
+	}
+}
+
+func TestServeHTTP_ScopedAPIKey(t *testing.T) {
+	query := `{"query":"{ subscription(shopifyGid: \"gid://shopify/AppSubscription/1\") { subscriptionId } }"}`
+
+	tests := []struct {
+		name   string
+		access *service.APIKeyAccess
+		want   string
+	}{
+		{"missing scope", &service.APIKeyAccess{Scopes: []string{entity.ScopeUsageRead}}, "FORBIDDEN"},
+		{"other app", &service.APIKeyAccess{Scopes: entity.DefaultAPIKeyScopes, AppIDs: []uuid.UUID{uuid.New()}}, "FORBIDDEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.access = tt.access
+
+			_, body := env.post(t, query)
+			if code := firstErrorCode(t, body); code != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, code)
+			}
+		})
+	}
+
+	env := newTestEnv(t)
+	env.access = &service.APIKeyAccess{Scopes: []string{entity.ScopeSubscriptionsRead}}
+	_, body := env.post(t, query)
+	if _, ok := body["errors"]; ok {
+		t.Errorf("expected scoped key to read subscriptions, got %v", body["errors"])
+	}
+}
+
+func TestServeHTTP_SubscriptionsConnection(t *testing.T) {
+	env := newTestEnv(t)
+
+	_, body := env.post(t, `{"query":"{ subscriptionsConnection(first: 10, filter: {appId: \"42\"}) { totalCount edges { cursor node { myshopifyDomain } } pageInfo { hasNextPage } } }"}`)
+
+	if _, ok := body["errors"]; ok {
+		t.Fatalf("unexpected errors: %v", body["errors"])
+	}
+	conn := body["data"].(map[string]interface{})["subscriptionsConnection"].(map[string]interface{})
+	if conn["totalCount"] != float64(1) {
+		t.Errorf("expected totalCount 1, got %v", conn["totalCount"])
+	}
+	edges := conn["edges"].([]interface{})
+	if len(edges) != 1 {
+		t.Fatalf("expected 1 edge, got %d", len(edges))
+	}
+	node := edges[0].(map[string]interface{})["node"].(map[string]interface{})
+	if node["myshopifyDomain"] != "store.myshopify.com" {
+		t.Errorf("unexpected node %v", node)
+	}
+}
+
+func TestServeHTTP_InvalidPageSize(t *testing.T) {
+	env := newTestEnv(t)
+
+	_, body := env.post(t, `{"query":"{ subscriptionsConnection(first: 1000) { totalCount } }"}`)
+
+	if code := firstErrorCode(t, body); code != "BAD_USER_INPUT" {
+		t.Errorf("expected BAD_USER_INPUT, got %s", code)
+	}
+}
+
+func TestServeHTTP_ValidationError(t *testing.T) {
+	env := newTestEnv(t)
+
+	code, body := env.post(t, `{"query":"{ subscription(shopifyGid: \"x\") { unknownField } }"}`)
+
+	if code != http.StatusOK {
+		t.Errorf("expected 200, got %d", code)
+	}
+	if _, ok := body["data"]; ok {
+		t.Errorf("expected no data for invalid document, got %v", body["data"])
+	}
+	if code := firstErrorCode(t, body); code != executor.CodeValidationFailed {
+		t.Errorf("expected %s, got %s", executor.CodeValidationFailed, code)
+	}
+}
+
+func TestServeHTTP_Introspection(t *testing.T) {
+	env := newTestEnv(t)
+
+	_, body := env.post(t, `{"query":"{ __schema { queryType { name } } __type(name: \"RiskState\") { kind enumValues { name } } }"}`)
+
+	if _, ok := body["errors"]; ok {
+		t.Fatalf("unexpected errors: %v", body["errors"])
+	}
+	data := body["data"].(map[string]interface{})
+	if name := data["__schema"].(map[string]interface{})["queryType"].(map[string]interface{})["name"]; name != "Query" {
+		t.Errorf("expected Query, got %v", name)
+	}
+	riskState := data["__type"].(map[string]interface{})
+	if riskState["kind"] != "ENUM" || len(riskState["enumValues"].([]interface{})) != 4 {
+		t.Errorf("unexpected RiskState introspection %v", riskState)
+	}
+}
+
+func TestServeHTTP_DepthLimit(t *testing.T) {
+	env := newTestEnv(t)
+	env.handler.WithLimits(executor.Limits{MaxDepth: 2})
+
+	_, body := env.post(t, `{"query":"{ subscriptionsConnection { edges { node { status } } } }"}`)
+
+	if code := firstErrorCode(t, body); code != executor.CodeQueryTooDeep {
+		t.Errorf("expected %s, got %s", executor.CodeQueryTooDeep, code)
+	}
+}
+
+func TestServeHTTP_PersistedQueryOverGET(t *testing.T) {
+	env := newTestEnv(t)
+
+	query := `{ subscription(shopifyGid: "gid://shopify/AppSubscription/1") { planName } }`
+	sum := sha256.Sum256([]byte(query))
+	extensions := `{"persistedQuery":{"version":1,"sha256Hash":"` + hex.EncodeToString(sum[:]) + `"}}`
+
+	hashOnly := "/graphql?extensions=" + url.QueryEscape(extensions)
+	_, body := env.do(t, httptest.NewRequest(http.MethodGet, hashOnly, nil))
+	if code := firstErrorCode(t, body); code != executor.CodePersistedQueryNotFound {
+		t.Fatalf("expected %s, got %s", executor.CodePersistedQueryNotFound, code)
+	}
+
+	register := hashOnly + "&query=" + url.QueryEscape(query)
+	if _, body := env.do(t, httptest.NewRequest(http.MethodGet, register, nil)); body["errors"] != nil {
+		t.Fatalf("unexpected errors registering query: %v", body["errors"])
+	}
+
+	_, body = env.do(t, httptest.NewRequest(http.MethodGet, hashOnly, nil))
+	if _, ok := body["errors"]; ok {
+		t.Fatalf("unexpected errors: %v", body["errors"])
+	}
+	sub := body["data"].(map[string]interface{})["subscription"].(map[string]interface{})
+	if sub["planName"] != "Pro" {
+		t.Errorf("expected planName Pro, got %v", sub["planName"])
+	}
+}
+
+func TestServeHTTP_MethodNotAllowed(t *testing.T) {
+	env := newTestEnv(t)
+
+	code, _ := env.do(t, httptest.NewRequest(http.MethodPut, "/graphql", nil))
+
+	if code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", code)
+	}
+}
@@ -1,6 +1,12 @@
 package graphql
 
+//go:generate go run github.com/99designs/gqlgen generate --config gqlgen.yml
+
 import (
+	"fmt"
+	"io"
+	"strconv"
+
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
 )
 
@@ -31,6 +37,25 @@ const (
 	RiskStateChurned         RiskState = "CHURNED"
 )
 
+func (e RiskState) IsValid() bool {
+	switch e {
+	case RiskStateSafe, RiskStateOneCycleMissed, RiskStateTwoCyclesMissed, RiskStateChurned:
+		return true
+	}
+	return false
+}
+
+func (e *RiskState) UnmarshalGQL(v interface{}) error {
+	return unmarshalEnum(v, "RiskState", func(s string) bool {
+		*e = RiskState(s)
+		return e.IsValid()
+	})
+}
+
+func (e RiskState) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(e)))
+}
+
 // SubscriptionStatusEnum values
 type SubscriptionStatusEnum string
 
@@ -40,3 +65,88 @@ const (
 	SubscriptionStatusFrozen    SubscriptionStatusEnum = "FROZEN"
 	SubscriptionStatusPending   SubscriptionStatusEnum = "PENDING"
 )
+
+func (e SubscriptionStatusEnum) IsValid() bool {
+	switch e {
+	case SubscriptionStatusActive, SubscriptionStatusCancelled, SubscriptionStatusFrozen, SubscriptionStatusPending:
+		return true
+	}
+	return false
+}
+
+func (e *SubscriptionStatusEnum) UnmarshalGQL(v interface{}) error {
+	return unmarshalEnum(v, "SubscriptionStatusEnum", func(s string) bool {
+		*e = SubscriptionStatusEnum(s)
+		return e.IsValid()
+	})
+}
+
+func (e SubscriptionStatusEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(e)))
+}
+
+// SubscriptionOrderField values
+type SubscriptionOrderField string
+
+const (
+	SubscriptionOrderFieldMyshopifyDomain          SubscriptionOrderField = "MYSHOPIFY_DOMAIN"
+	SubscriptionOrderFieldMonthsOverdue            SubscriptionOrderField = "MONTHS_OVERDUE"
+	SubscriptionOrderFieldExpectedNextChargeDate   SubscriptionOrderField = "EXPECTED_NEXT_CHARGE_DATE"
+	SubscriptionOrderFieldLastSuccessfulChargeDate SubscriptionOrderField = "LAST_SUCCESSFUL_CHARGE_DATE"
+)
+
+func (e SubscriptionOrderField) IsValid() bool {
+	switch e {
+	case SubscriptionOrderFieldMyshopifyDomain, SubscriptionOrderFieldMonthsOverdue,
+		SubscriptionOrderFieldExpectedNextChargeDate, SubscriptionOrderFieldLastSuccessfulChargeDate:
+		return true
+	}
+	return false
+}
+
+func (e *SubscriptionOrderField) UnmarshalGQL(v interface{}) error {
+	return unmarshalEnum(v, "SubscriptionOrderField", func(s string) bool {
+		*e = SubscriptionOrderField(s)
+		return e.IsValid()
+	})
+}
+
+func (e SubscriptionOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(e)))
+}
+
+// OrderDirection values
+type OrderDirection string
+
+const (
+	OrderDirectionAsc  OrderDirection = "ASC"
+	OrderDirectionDesc OrderDirection = "DESC"
+)
+
+func (e OrderDirection) IsValid() bool {
+	return e == OrderDirectionAsc || e == OrderDirectionDesc
+}
+
+func (e *OrderDirection) UnmarshalGQL(v interface{}) error {
+	return unmarshalEnum(v, "OrderDirection", func(s string) bool {
+		*e = OrderDirection(s)
+		return e.IsValid()
+	})
+}
+
+func (e OrderDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(e)))
+}
+
+// unmarshalEnum implements UnmarshalGQL for the string enums above, the way gqlgen
+// generates it for models it owns
+func unmarshalEnum(v interface{}, name string, set func(string) bool) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+	if !set(str) {
+		return fmt.Errorf("%s is not a valid %s", str, name)
+	}
+	return nil
+}
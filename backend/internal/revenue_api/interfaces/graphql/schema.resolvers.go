@@ -2,23 +2,28 @@ package graphql
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	revrepo "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/middleware"
 )
 
 // SubscriptionStatus represents a subscription in GraphQL
 type SubscriptionStatus struct {
-	SubscriptionID           string     `json:"subscriptionId"`
-	MyshopifyDomain          string     `json:"myshopifyDomain"`
-	ShopName                 *string    `json:"shopName"`
-	PlanName                 *string    `json:"planName"`
-	RiskState                RiskState  `json:"riskState"`
-	IsPaidCurrentCycle       bool       `json:"isPaidCurrentCycle"`
-	MonthsOverdue            int        `json:"monthsOverdue"`
-	LastSuccessfulChargeDate *time.Time `json:"lastSuccessfulChargeDate"`
-	ExpectedNextChargeDate   *time.Time `json:"expectedNextChargeDate"`
+	SubscriptionID           string                 `json:"subscriptionId"`
+	MyshopifyDomain          string                 `json:"myshopifyDomain"`
+	ShopName                 *string                `json:"shopName"`
+	PlanName                 *string                `json:"planName"`
+	RiskState                RiskState              `json:"riskState"`
+	IsPaidCurrentCycle       bool                   `json:"isPaidCurrentCycle"`
+	MonthsOverdue            int                    `json:"monthsOverdue"`
+	LastSuccessfulChargeDate *time.Time             `json:"lastSuccessfulChargeDate"`
+	ExpectedNextChargeDate   *time.Time             `json:"expectedNextChargeDate"`
 	Status                   SubscriptionStatusEnum `json:"status"`
 }
 
@@ -44,28 +49,65 @@ type UsageBatchResult struct {
 	NotFound []string       `json:"notFound"`
 }
 
-// QueryResolver implements the Query resolvers
-type QueryResolver struct {
-	*Resolver
+// SubscriptionFilter is the subscriptionsConnection filter input
+type SubscriptionFilter struct {
+	AppID     *string                 `json:"appId"`
+	RiskState *RiskState              `json:"riskState"`
+	Status    *SubscriptionStatusEnum `json:"status"`
+	IsOverdue *bool                   `json:"isOverdue"`
 }
 
+// SubscriptionOrder is the subscriptionsConnection orderBy input
+type SubscriptionOrder struct {
+	Field     SubscriptionOrderField `json:"field"`
+	Direction *OrderDirection        `json:"direction"` // Defaults to ASC
+}
+
+// PageInfo is Relay pagination info
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+// SubscriptionEdge is a subscription with its cursor
+type SubscriptionEdge struct {
+	Cursor string              `json:"cursor"`
+	Node   *SubscriptionStatus `json:"node"`
+}
+
+// SubscriptionConnection is a Relay-style page of subscriptions
+type SubscriptionConnection struct {
+	Edges      []*SubscriptionEdge `json:"edges"`
+	PageInfo   *PageInfo           `json:"pageInfo"`
+	TotalCount int                 `json:"totalCount"`
+}
+
+// queryResolver implements the Query resolvers
+type queryResolver struct{ *Resolver }
+
 // Query returns the query resolver
-func (r *Resolver) Query() *QueryResolver {
-	return &QueryResolver{r}
+func (r *Resolver) Query() *queryResolver {
+	return &queryResolver{r}
 }
 
 // getUserID extracts the user ID from context (set by API key auth middleware)
-func getUserID(ctx context.Context) (uuid.UUID, error) {
+// and checks that the key was granted scope
+func getUserID(ctx context.Context, scope string) (uuid.UUID, error) {
 	apiKey := middleware.APIKeyFromContext(ctx)
 	if apiKey == nil {
 		return uuid.Nil, ErrUnauthorized
 	}
+	if !service.APIKeyAccessFromContext(ctx).HasScope(scope) {
+		return uuid.Nil, &GraphQLError{Message: "API key lacks the " + scope + " scope", Code: "FORBIDDEN"}
+	}
 	return apiKey.UserID, nil
 }
 
 // Subscription resolves a single subscription by Shopify GID
-func (r *QueryResolver) Subscription(ctx context.Context, shopifyGid string) (*SubscriptionStatus, error) {
-	userID, err := getUserID(ctx)
+func (r *queryResolver) Subscription(ctx context.Context, shopifyGid string) (*SubscriptionStatus, error) {
+	userID, err := getUserID(ctx, entity.ScopeSubscriptionsRead)
 	if err != nil {
 		return nil, err
 	}
@@ -75,12 +117,12 @@ func (r *QueryResolver) Subscription(ctx context.Context, shopifyGid string) (*S
 		return nil, err
 	}
 
-	return toGraphQLSubscription(status), nil
+	return toGraphQLSubscriptionFromResponse(status.ToResponse()), nil
 }
 
 // SubscriptionByDomain resolves a subscription by myshopify domain
-func (r *QueryResolver) SubscriptionByDomain(ctx context.Context, domain string) (*SubscriptionStatus, error) {
-	userID, err := getUserID(ctx)
+func (r *queryResolver) SubscriptionByDomain(ctx context.Context, domain string) (*SubscriptionStatus, error) {
+	userID, err := getUserID(ctx, entity.ScopeSubscriptionsRead)
 	if err != nil {
 		return nil, err
 	}
@@ -90,12 +132,12 @@ func (r *QueryResolver) SubscriptionByDomain(ctx context.Context, domain string)
 		return nil, err
 	}
 
-	return toGraphQLSubscription(status), nil
+	return toGraphQLSubscriptionFromResponse(status.ToResponse()), nil
 }
 
 // Subscriptions resolves multiple subscriptions by Shopify GIDs
-func (r *QueryResolver) Subscriptions(ctx context.Context, shopifyGids []string) (*SubscriptionBatchResult, error) {
-	userID, err := getUserID(ctx)
+func (r *queryResolver) Subscriptions(ctx context.Context, shopifyGids []string) (*SubscriptionBatchResult, error) {
+	userID, err := getUserID(ctx, entity.ScopeSubscriptionsRead)
 	if err != nil {
 		return nil, err
 	}
@@ -107,18 +149,7 @@ func (r *QueryResolver) Subscriptions(ctx context.Context, shopifyGids []string)
 
 	gqlResults := make([]*SubscriptionStatus, len(result.Results))
 	for i, s := range result.Results {
-		gqlResults[i] = &SubscriptionStatus{
-			SubscriptionID:           s.SubscriptionID,
-			MyshopifyDomain:          s.MyshopifyDomain,
-			ShopName:                 strPtr(s.ShopName),
-			PlanName:                 strPtr(s.PlanName),
-			RiskState:                RiskState(s.RiskState),
-			IsPaidCurrentCycle:       s.IsPaidCurrentCycle,
-			MonthsOverdue:            s.MonthsOverdue,
-			LastSuccessfulChargeDate: s.LastSuccessfulChargeDate,
-			ExpectedNextChargeDate:   s.ExpectedNextChargeDate,
-			Status:                   SubscriptionStatusEnum(s.Status),
-		}
+		gqlResults[i] = toGraphQLSubscriptionFromResponse(s)
 	}
 
 	return &SubscriptionBatchResult{
@@ -127,9 +158,86 @@ func (r *QueryResolver) Subscriptions(ctx context.Context, shopifyGids []string)
 	}, nil
 }
 
+// SubscriptionsConnection resolves a filtered, cursor-paginated list of subscriptions
+func (r *queryResolver) SubscriptionsConnection(
+	ctx context.Context,
+	filter *SubscriptionFilter,
+	first *int,
+	after *string,
+	orderBy *SubscriptionOrder,
+) (*SubscriptionConnection, error) {
+	userID, err := getUserID(ctx, entity.ScopeSubscriptionsRead)
+	if err != nil {
+		return nil, err
+	}
+
+	req := service.SubscriptionListRequest{}
+	if filter != nil {
+		if filter.AppID != nil {
+			req.AppID = *filter.AppID
+		}
+		if filter.RiskState != nil {
+			riskState := valueobject.RiskState(*filter.RiskState)
+			req.Filter.RiskState = &riskState
+		}
+		if filter.Status != nil {
+			status := string(*filter.Status)
+			req.Filter.Status = &status
+		}
+		req.Filter.IsOverdue = filter.IsOverdue
+	}
+	if first != nil {
+		if *first <= 0 {
+			return nil, &GraphQLError{Message: service.ErrInvalidPageSize.Error(), Code: "BAD_USER_INPUT"}
+		}
+		req.First = *first
+	}
+	if after != nil {
+		req.After = *after
+	}
+	if orderBy != nil {
+		req.OrderBy = revrepo.SubscriptionStatusSortField(orderBy.Field)
+		req.Descending = orderBy.Direction != nil && *orderBy.Direction == OrderDirectionDesc
+	}
+
+	page, err := r.subscriptionService.List(ctx, userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) ||
+			errors.Is(err, service.ErrInvalidPageSize) ||
+			errors.Is(err, service.ErrInvalidSortField) {
+			return nil, &GraphQLError{Message: err.Error(), Code: "BAD_USER_INPUT"}
+		}
+		if errors.Is(err, service.ErrAppAccessDenied) {
+			return nil, &GraphQLError{Message: err.Error(), Code: "FORBIDDEN"}
+		}
+		return nil, err
+	}
+
+	conn := &SubscriptionConnection{
+		Edges: make([]*SubscriptionEdge, len(page.Statuses)),
+		PageInfo: &PageInfo{
+			HasNextPage:     page.HasNextPage,
+			HasPreviousPage: page.HasPreviousPage,
+		},
+		TotalCount: page.TotalCount,
+	}
+	for i, status := range page.Statuses {
+		conn.Edges[i] = &SubscriptionEdge{
+			Cursor: page.Cursors[i],
+			Node:   toGraphQLSubscriptionFromResponse(status.ToResponse()),
+		}
+	}
+	if len(page.Cursors) > 0 {
+		conn.PageInfo.StartCursor = &page.Cursors[0]
+		conn.PageInfo.EndCursor = &page.Cursors[len(page.Cursors)-1]
+	}
+
+	return conn, nil
+}
+
 // Usage resolves a single usage record by Shopify GID
-func (r *QueryResolver) Usage(ctx context.Context, shopifyGid string) (*UsageStatus, error) {
-	userID, err := getUserID(ctx)
+func (r *queryResolver) Usage(ctx context.Context, shopifyGid string) (*UsageStatus, error) {
+	userID, err := getUserID(ctx, entity.ScopeUsageRead)
 	if err != nil {
 		return nil, err
 	}
@@ -139,12 +247,17 @@ func (r *QueryResolver) Usage(ctx context.Context, shopifyGid string) (*UsageSta
 		return nil, err
 	}
 
-	return toGraphQLUsage(status), nil
+	subscriptions, err := r.usageSubscriptions(ctx, userID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return toGraphQLUsage(status, subscriptions), nil
 }
 
 // Usages resolves multiple usage records by Shopify GIDs
-func (r *QueryResolver) Usages(ctx context.Context, shopifyGids []string) (*UsageBatchResult, error) {
-	userID, err := getUserID(ctx)
+func (r *queryResolver) Usages(ctx context.Context, shopifyGids []string) (*UsageBatchResult, error) {
+	userID, err := getUserID(ctx, entity.ScopeUsageRead)
 	if err != nil {
 		return nil, err
 	}
@@ -154,9 +267,18 @@ func (r *QueryResolver) Usages(ctx context.Context, shopifyGids []string) (*Usag
 		return nil, err
 	}
 
-	gqlResults := make([]*UsageStatus, len(result.Results))
-	for i, u := range result.Results {
-		gqlResults[i] = toGraphQLUsageFromResponse(&u)
+	usages := make([]*entity.UsageStatusResponse, len(result.Results))
+	for i := range result.Results {
+		usages[i] = &result.Results[i]
+	}
+	subscriptions, err := r.usageSubscriptions(ctx, userID, usages...)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlResults := make([]*UsageStatus, len(usages))
+	for i, u := range usages {
+		gqlResults[i] = toGraphQLUsage(u, subscriptions)
 	}
 
 	return &UsageBatchResult{
@@ -174,87 +296,80 @@ func strPtr(s string) *string {
 	return &s
 }
 
-func toGraphQLSubscription(s interface{}) *SubscriptionStatus {
-	// Type assert to the entity type
-	type statusEntity interface {
-		ToResponse() interface{}
-	}
-
-	if entity, ok := s.(statusEntity); ok {
-		resp := entity.ToResponse()
-		if r, ok := resp.(struct {
-			SubscriptionID           string
-			MyshopifyDomain          string
-			ShopName                 string
-			PlanName                 string
-			RiskState                string
-			IsPaidCurrentCycle       bool
-			MonthsOverdue            int
-			LastSuccessfulChargeDate *time.Time
-			ExpectedNextChargeDate   *time.Time
-			Status                   string
-		}); ok {
-			return &SubscriptionStatus{
-				SubscriptionID:           r.SubscriptionID,
-				MyshopifyDomain:          r.MyshopifyDomain,
-				ShopName:                 strPtr(r.ShopName),
-				PlanName:                 strPtr(r.PlanName),
-				RiskState:                RiskState(r.RiskState),
-				IsPaidCurrentCycle:       r.IsPaidCurrentCycle,
-				MonthsOverdue:            r.MonthsOverdue,
-				LastSuccessfulChargeDate: r.LastSuccessfulChargeDate,
-				ExpectedNextChargeDate:   r.ExpectedNextChargeDate,
-				Status:                   SubscriptionStatusEnum(r.Status),
-			}
-		}
+func subscriptionFromEntity(s *entity.SubscriptionStatus) *SubscriptionStatus {
+	return &SubscriptionStatus{
+		SubscriptionID:           s.ShopifyGID,
+		MyshopifyDomain:          s.MyshopifyDomain,
+		ShopName:                 strPtr(s.ShopName),
+		PlanName:                 strPtr(s.PlanName),
+		RiskState:                RiskState(s.RiskState),
+		IsPaidCurrentCycle:       s.IsPaidCurrentCycle,
+		MonthsOverdue:            s.MonthsOverdue,
+		LastSuccessfulChargeDate: s.LastSuccessfulChargeDate,
+		ExpectedNextChargeDate:   s.ExpectedNextChargeDate,
+		Status:                   SubscriptionStatusEnum(s.Status),
 	}
-	return nil
 }
 
-func toGraphQLUsage(u interface{}) *UsageStatus {
-	// Direct conversion from response pointer
-	if resp, ok := u.(*struct {
-		UsageID      string
-		Billed       bool
-		BillingDate  *time.Time
-		AmountCents  int
-		Description  string
-		Subscription *struct {
-			SubscriptionID     string
-			MyshopifyDomain    string
-			RiskState          string
-			IsPaidCurrentCycle bool
-		}
-	}); ok {
-		result := &UsageStatus{
-			UsageID:     resp.UsageID,
-			Billed:      resp.Billed,
-			BillingDate: resp.BillingDate,
-			AmountCents: resp.AmountCents,
-			Description: strPtr(resp.Description),
-		}
-		if resp.Subscription != nil {
-			result.Subscription = &SubscriptionStatus{
-				SubscriptionID:     resp.Subscription.SubscriptionID,
-				MyshopifyDomain:    resp.Subscription.MyshopifyDomain,
-				RiskState:          RiskState(resp.Subscription.RiskState),
-				IsPaidCurrentCycle: resp.Subscription.IsPaidCurrentCycle,
-			}
-		}
-		return result
+func toGraphQLSubscriptionFromResponse(r entity.SubscriptionStatusResponse) *SubscriptionStatus {
+	return &SubscriptionStatus{
+		SubscriptionID:           r.SubscriptionID,
+		MyshopifyDomain:          r.MyshopifyDomain,
+		ShopName:                 strPtr(r.ShopName),
+		PlanName:                 strPtr(r.PlanName),
+		RiskState:                RiskState(r.RiskState),
+		IsPaidCurrentCycle:       r.IsPaidCurrentCycle,
+		MonthsOverdue:            r.MonthsOverdue,
+		LastSuccessfulChargeDate: r.LastSuccessfulChargeDate,
+		ExpectedNextChargeDate:   r.ExpectedNextChargeDate,
+		Status:                   SubscriptionStatusEnum(r.Status),
+	}
+}
+
+// toGraphQLUsage converts a usage response; the parent subscription is taken from subscriptions
+// (keyed by Shopify GID) because the usage response only carries a summary of it
+func toGraphQLUsage(u *entity.UsageStatusResponse, subscriptions map[string]*SubscriptionStatus) *UsageStatus {
+	result := &UsageStatus{
+		UsageID:     u.UsageID,
+		Billed:      u.Billed,
+		BillingDate: u.BillingDate,
+		AmountCents: u.AmountCents,
+		Description: strPtr(u.Description),
+	}
+	if u.Subscription != nil {
+		result.Subscription = subscriptions[u.Subscription.SubscriptionID]
 	}
-	return nil
+	return result
 }
 
-func toGraphQLUsageFromResponse(u interface{}) *UsageStatus {
-	// Handle the entity.UsageStatusResponse type
-	return toGraphQLUsage(u)
+// usageSubscriptions loads the full parent subscriptions of usage responses
+func (r *queryResolver) usageSubscriptions(ctx context.Context, userID uuid.UUID, usages ...*entity.UsageStatusResponse) (map[string]*SubscriptionStatus, error) {
+	var gids []string
+	for _, u := range usages {
+		if u.Subscription != nil {
+			gids = append(gids, u.Subscription.SubscriptionID)
+		}
+	}
+
+	result := make(map[string]*SubscriptionStatus)
+	// Usage-only keys get usages without their parent subscription
+	if len(gids) == 0 || !service.APIKeyAccessFromContext(ctx).HasScope(entity.ScopeSubscriptionsRead) {
+		return result, nil
+	}
+	batch, err := r.subscriptionService.GetByShopifyGIDs(ctx, userID, gids)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range batch.Results {
+		result[s.SubscriptionID] = toGraphQLSubscriptionFromResponse(s)
+	}
+	return result, nil
 }
 
 // ErrUnauthorized is returned when no API key is in context
 var ErrUnauthorized = &GraphQLError{Message: "unauthorized: API key required", Code: "UNAUTHORIZED"}
 
-// GraphQLError represents a GraphQL error
+// GraphQLError is a resolver error with a machine-readable code (extensions.code)
 type GraphQLError struct {
 	Message string
 	Code    string
@@ -263,3 +378,8 @@ type GraphQLError struct {
 func (e *GraphQLError) Error() string {
 	return e.Message
 }
+
+// Extensions returns the error's response extensions
+func (e *GraphQLError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
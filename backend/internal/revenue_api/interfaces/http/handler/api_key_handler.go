@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -22,8 +23,17 @@ func NewAPIKeyHandler(svc *service.APIKeyService) *APIKeyHandler {
 
 // CreateRequest is the request body for creating an API key
 type CreateRequest struct {
-	Name               string `json:"name"`
-	RateLimitPerMinute int    `json:"rate_limit_per_minute,omitempty"`
+	Name               string   `json:"name"`
+	RateLimitPerMinute int      `json:"rate_limit_per_minute,omitempty"`
+	Scopes             []string `json:"scopes,omitempty"`  // Defaults to subscriptions:read, usage:read, stream
+	AppIDs             []string `json:"app_ids,omitempty"` // Internal IDs or Shopify app GIDs; empty = all apps
+	ExpiresInDays      int      `json:"expires_in_days,omitempty"` // 0 = never expires
+}
+
+// RotateRequest is the request body for rotating an API key
+type RotateRequest struct {
+	OverlapHours  *int `json:"overlap_hours,omitempty"`   // How long the old key keeps working; default 24
+	ExpiresInDays int  `json:"expires_in_days,omitempty"` // Successor expiry; default = old key's lifetime
 }
 
 // APIKeyResponse is the response format for an API key
@@ -33,6 +43,11 @@ type APIKeyResponse struct {
 	KeyPrefix string  `json:"key_prefix"`
 	CreatedAt string  `json:"created_at"`
 	LastUsedAt *string `json:"last_used_at"`
+	ExpiresAt  *string  `json:"expires_at"`
+	Scopes     []string `json:"scopes"`
+	AppIDs     []string `json:"app_ids"` // Empty = all apps
+	ReplacedBy *string  `json:"replaced_by,omitempty"`
+	Warnings   []string `json:"warnings"` // expiring, rotating, unused
 }
 
 // CreateResponse is the response format after creating an API key
@@ -41,6 +56,13 @@ type CreateResponse struct {
 	FullKey string         `json:"full_key"`
 }
 
+// RotateResponse is the response format after rotating an API key
+type RotateResponse struct {
+	CreateResponse
+	PreviousKeyID        string `json:"previous_key_id"`
+	PreviousKeyExpiresAt string `json:"previous_key_expires_at"`
+}
+
 // Create creates a new API key
 // POST /api/v1/api-keys
 func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -66,11 +88,14 @@ func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
 		UserID:             user.ID,
 		Name:               req.Name,
 		RateLimitPerMinute: req.RateLimitPerMinute,
+		Scopes:             req.Scopes,
+		AppIDs:             req.AppIDs,
+		ExpiresInDays:      req.ExpiresInDays,
 	})
 
 	if err != nil {
 		switch err {
-		case service.ErrRateLimitInvalid:
+		case service.ErrRateLimitInvalid, service.ErrInvalidScopes, service.ErrInvalidKeyApps, service.ErrWebhooksAppScope, service.ErrInvalidExpiry:
 			writeJSONError(w, http.StatusBadRequest, err.Error())
 		default:
 			writeJSONError(w, http.StatusInternalServerError, "failed to create API key")
@@ -78,26 +103,75 @@ func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Format response for frontend
-	keyPrefix := resp.RawKey
-	if len(keyPrefix) > 12 {
-		keyPrefix = keyPrefix[:12] + "..."
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newCreateResponse(resp))
+}
+
+// Rotate issues a successor key; the old key keeps working for the overlap window
+// POST /api/v1/api-keys/{id}/rotate
+func (h *APIKeyHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
 	}
 
-	createResp := CreateResponse{
-		APIKey: APIKeyResponse{
-			ID:         resp.ID.String(),
-			Name:       resp.Name,
-			KeyPrefix:  keyPrefix,
-			CreatedAt:  resp.CreatedAt.Format("2006-01-02T15:04:05Z"),
-			LastUsedAt: nil,
-		},
-		FullKey: resp.RawKey,
+	// Only OWNER role can rotate API keys
+	if user.Role != "OWNER" {
+		writeJSONError(w, http.StatusForbidden, "only account owners can manage API keys")
+		return
+	}
+
+	keyID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid key ID")
+		return
+	}
+
+	// The body is optional
+	var req RotateRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	rotateReq := service.RotateKeyRequest{
+		UserID:        user.ID,
+		KeyID:         keyID,
+		ExpiresInDays: req.ExpiresInDays,
+	}
+	if req.OverlapHours != nil {
+		overlap := time.Duration(*req.OverlapHours) * time.Hour
+		rotateReq.Overlap = &overlap
+	}
+
+	resp, err := h.service.Rotate(r.Context(), rotateReq)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidOverlap, service.ErrInvalidExpiry:
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+		case service.ErrAPIKeyNotFound:
+			writeJSONError(w, http.StatusNotFound, "API key not found")
+		case service.ErrUnauthorized:
+			writeJSONError(w, http.StatusForbidden, "you don't own this API key")
+		case service.ErrAPIKeyRevoked, service.ErrAPIKeyExpired, service.ErrAPIKeyRotated:
+			writeJSONError(w, http.StatusConflict, err.Error())
+		default:
+			writeJSONError(w, http.StatusInternalServerError, "failed to rotate API key")
+		}
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(createResp)
+	json.NewEncoder(w).Encode(RotateResponse{
+		CreateResponse:       newCreateResponse(&resp.CreateKeyResponse),
+		PreviousKeyID:        resp.PreviousKeyID.String(),
+		PreviousKeyExpiresAt: resp.PreviousKeyExpiresAt.Format(time.RFC3339),
+	})
 }
 
 // List returns all API keys for the authenticated user
@@ -128,14 +202,22 @@ func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
 		if !k.IsActive {
 			continue
 		}
-		var lastUsed *string
-		// The service doesn't currently track last_used_at, so we leave it nil
+		var replacedBy *string
+		if k.ReplacedByID != nil {
+			id := k.ReplacedByID.String()
+			replacedBy = &id
+		}
 		apiKeys[i] = APIKeyResponse{
 			ID:         k.ID.String(),
 			Name:       k.Name,
 			KeyPrefix:  "lgk_" + k.ID.String()[:8] + "...", // Use part of ID as visual prefix
 			CreatedAt:  k.CreatedAt.Format("2006-01-02T15:04:05Z"),
-			LastUsedAt: lastUsed,
+			LastUsedAt: formatKeyTime(k.LastUsedAt),
+			ExpiresAt:  formatKeyTime(k.ExpiresAt),
+			Scopes:     k.Scopes,
+			AppIDs:     k.AppIDs,
+			ReplacedBy: replacedBy,
+			Warnings:   k.Warnings,
 		}
 	}
 
@@ -195,6 +277,37 @@ func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
 
 // Helper functions
 
+func newCreateResponse(resp *service.CreateKeyResponse) CreateResponse {
+	// Format response for frontend
+	keyPrefix := resp.RawKey
+	if len(keyPrefix) > 12 {
+		keyPrefix = keyPrefix[:12] + "..."
+	}
+
+	return CreateResponse{
+		APIKey: APIKeyResponse{
+			ID:         resp.ID.String(),
+			Name:       resp.Name,
+			KeyPrefix:  keyPrefix,
+			CreatedAt:  resp.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			LastUsedAt: nil,
+			ExpiresAt:  formatKeyTime(resp.ExpiresAt),
+			Scopes:     resp.Scopes,
+			AppIDs:     resp.AppIDs,
+			Warnings:   []string{},
+		},
+		FullKey: resp.RawKey,
+	}
+}
+
+func formatKeyTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.UTC().Format("2006-01-02T15:04:05Z")
+	return &formatted
+}
+
 func writeJSONError(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+)
+
+// APIUsageHandler handles per-key API usage analytics
+type APIUsageHandler struct {
+	service *service.APIUsageService
+}
+
+// NewAPIUsageHandler creates a new APIUsageHandler
+func NewAPIUsageHandler(svc *service.APIUsageService) *APIUsageHandler {
+	return &APIUsageHandler{service: svc}
+}
+
+// Usage returns request volume, error rates, latency percentiles and top callers of a key
+// GET /api/v1/api-keys/{id}/usage?from=&to=&top_callers=
+func (h *APIUsageHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	// Only OWNER role can see API key usage
+	if user.Role != "OWNER" {
+		writeJSONError(w, http.StatusForbidden, "only account owners can manage API keys")
+		return
+	}
+
+	keyID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid key ID")
+		return
+	}
+
+	var query service.UsageQuery
+	for name, target := range map[string]**time.Time{"from": &query.From, "to": &query.To} {
+		if v := r.URL.Query().Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, name+" must be an RFC 3339 timestamp")
+				return
+			}
+			*target = &t
+		}
+	}
+	if v := r.URL.Query().Get("top_callers"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeJSONError(w, http.StatusBadRequest, "top_callers must be a positive integer")
+			return
+		}
+		query.TopCallers = n
+	}
+
+	report, err := h.service.GetKeyUsage(r.Context(), user.ID, keyID, query)
+	if err != nil {
+		switch err {
+		case service.ErrAPIKeyNotFound:
+			writeJSONError(w, http.StatusNotFound, "API key not found")
+		case service.ErrUnauthorized:
+			writeJSONError(w, http.StatusForbidden, "you don't own this API key")
+		case service.ErrInvalidUsageRange:
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+		default:
+			writeJSONError(w, http.StatusInternalServerError, "failed to get API key usage")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
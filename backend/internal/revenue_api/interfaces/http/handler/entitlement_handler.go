@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/middleware"
+)
+
+// EntitlementHandler handles access-decision lookups for feature gating
+type EntitlementHandler struct {
+	service *service.EntitlementService
+}
+
+// NewEntitlementHandler creates a new EntitlementHandler
+func NewEntitlementHandler(svc *service.EntitlementService) *EntitlementHandler {
+	return &EntitlementHandler{service: svc}
+}
+
+// EntitlementResponse is the response format for an entitlement decision
+type EntitlementResponse struct {
+	MyshopifyDomain string                            `json:"myshopify_domain"`
+	Decision        string                            `json:"decision"` // ALLOW, WARN, RESTRICT, BLOCK
+	Reason          string                            `json:"reason"`
+	Rule            string                            `json:"rule,omitempty"`
+	GraceDeadline   *string                           `json:"grace_deadline"`
+	DefaultPolicy   bool                              `json:"default_policy"`
+	EvaluatedAt     string                            `json:"evaluated_at"`
+	Subscription    entity.SubscriptionStatusResponse `json:"subscription"`
+}
+
+// Check returns the access decision for a store
+// GET /v1/entitlements?domain=&app_id=
+func (h *EntitlementHandler) Check(w http.ResponseWriter, r *http.Request) {
+	apiKey := middleware.APIKeyFromContext(r.Context())
+	if apiKey == nil {
+		writeJSONError(w, http.StatusUnauthorized, "API key required")
+		return
+	}
+
+	domain := r.URL.Query().Get("domain")
+	if domain == "" {
+		writeJSONError(w, http.StatusBadRequest, "domain query parameter is required")
+		return
+	}
+
+	check, err := h.service.Check(r.Context(), apiKey.UserID, r.URL.Query().Get("app_id"), domain)
+	if err != nil {
+		switch err {
+		case service.ErrSubscriptionNotFound:
+			writeJSONError(w, http.StatusNotFound, "subscription not found")
+		case service.ErrAppAccessDenied:
+			writeJSONError(w, http.StatusForbidden, "access denied")
+		case service.ErrInsufficientScope:
+			writeJSONError(w, http.StatusForbidden, "API key lacks the subscriptions:read scope")
+		default:
+			writeJSONError(w, http.StatusInternalServerError, "failed to evaluate entitlement")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, EntitlementResponse{
+		MyshopifyDomain: check.Status.MyshopifyDomain,
+		Decision:        string(check.Entitlement.Decision),
+		Reason:          check.Entitlement.Reason,
+		Rule:            check.Entitlement.RuleName,
+		GraceDeadline:   formatOptionalTime(check.Entitlement.GraceDeadline),
+		DefaultPolicy:   check.DefaultPolicy,
+		EvaluatedAt:     check.EvaluatedAt.Format(time.RFC3339),
+		Subscription:    check.Status.ToResponse(),
+	})
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	revenueentity "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+)
+
+// EntitlementPolicyHandler handles per-app entitlement policy management from the dashboard
+type EntitlementPolicyHandler struct {
+	service *service.EntitlementService
+}
+
+// NewEntitlementPolicyHandler creates a new EntitlementPolicyHandler
+func NewEntitlementPolicyHandler(svc *service.EntitlementService) *EntitlementPolicyHandler {
+	return &EntitlementPolicyHandler{service: svc}
+}
+
+// EntitlementPolicyRequest is the request body for replacing a policy
+type EntitlementPolicyRequest struct {
+	Rules           []revenueentity.EntitlementRule `json:"rules"`
+	DefaultDecision string                          `json:"default_decision"` // Defaults to ALLOW
+	DefaultReason   string                          `json:"default_reason"`   // Defaults to in_good_standing
+}
+
+// EntitlementPolicyResponse is the response format for a policy
+type EntitlementPolicyResponse struct {
+	Rules           []revenueentity.EntitlementRule `json:"rules"`
+	DefaultDecision string                          `json:"default_decision"`
+	DefaultReason   string                          `json:"default_reason"`
+	IsDefault       bool                            `json:"is_default"`
+	UpdatedAt       *string                         `json:"updated_at"`
+}
+
+// Get returns the app's policy (the built-in default if none is saved)
+// GET /api/v1/apps/{appID}/entitlement-policy
+func (h *EntitlementPolicyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	policy, err := h.service.GetPolicy(r.Context(), user.ID, chi.URLParam(r, "appID"))
+	if err != nil {
+		writeEntitlementPolicyError(w, err, "failed to fetch entitlement policy")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toEntitlementPolicyResponse(policy))
+}
+
+// Update replaces the app's policy
+// PUT /api/v1/apps/{appID}/entitlement-policy
+func (h *EntitlementPolicyHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user, ok := requirePolicyOwner(w, r)
+	if !ok {
+		return
+	}
+
+	var req EntitlementPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	policy, err := h.service.SavePolicy(
+		r.Context(),
+		user.ID,
+		chi.URLParam(r, "appID"),
+		req.Rules,
+		revenueentity.EntitlementDecision(req.DefaultDecision),
+		req.DefaultReason,
+	)
+	if err != nil {
+		writeEntitlementPolicyError(w, err, "failed to save entitlement policy")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toEntitlementPolicyResponse(policy))
+}
+
+// Reset deletes the app's policy so the built-in default applies again
+// DELETE /api/v1/apps/{appID}/entitlement-policy
+func (h *EntitlementPolicyHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	user, ok := requirePolicyOwner(w, r)
+	if !ok {
+		return
+	}
+
+	policy, err := h.service.ResetPolicy(r.Context(), user.ID, chi.URLParam(r, "appID"))
+	if err != nil {
+		writeEntitlementPolicyError(w, err, "failed to reset entitlement policy")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toEntitlementPolicyResponse(policy))
+}
+
+// requirePolicyOwner allows only account owners to change what customers' stores can access
+func requirePolicyOwner(w http.ResponseWriter, r *http.Request) (*entity.User, bool) {
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return nil, false
+	}
+
+	if user.Role != "OWNER" {
+		writeJSONError(w, http.StatusForbidden, "only account owners can change entitlement policies")
+		return nil, false
+	}
+
+	return user, true
+}
+
+func writeEntitlementPolicyError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case service.ErrAppAccessDenied:
+		writeJSONError(w, http.StatusNotFound, "app not found")
+	case revenueentity.ErrInvalidEntitlementDecision, revenueentity.ErrInvalidEntitlementRule, revenueentity.ErrTooManyEntitlementRules:
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeJSONError(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+func toEntitlementPolicyResponse(p *revenueentity.EntitlementPolicy) EntitlementPolicyResponse {
+	rules := p.Rules
+	if rules == nil {
+		rules = []revenueentity.EntitlementRule{}
+	}
+
+	resp := EntitlementPolicyResponse{
+		Rules:           rules,
+		DefaultDecision: string(p.DefaultDecision),
+		DefaultReason:   p.DefaultReason,
+		IsDefault:       p.IsDefault(),
+	}
+	if !p.IsDefault() {
+		updatedAt := p.UpdatedAt.Format(time.RFC3339)
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
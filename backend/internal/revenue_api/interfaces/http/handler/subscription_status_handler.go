@@ -3,9 +3,15 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/sachin-sivadasan/ledgerguard/internal/domain/valueobject"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	revrepo "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/middleware"
 )
 
@@ -28,7 +34,7 @@ func (h *SubscriptionStatusHandler) GetByGID(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	shopifyGID := chi.URLParam(r, "shopify_gid")
+	shopifyGID := shopifyGIDParam(r)
 	if shopifyGID == "" {
 		writeJSONError(w, http.StatusBadRequest, "shopify_gid is required")
 		return
@@ -41,6 +47,8 @@ func (h *SubscriptionStatusHandler) GetByGID(w http.ResponseWriter, r *http.Requ
 			writeJSONError(w, http.StatusNotFound, "subscription not found")
 		case service.ErrAppAccessDenied:
 			writeJSONError(w, http.StatusForbidden, "access denied")
+		case service.ErrInsufficientScope:
+			writeJSONError(w, http.StatusForbidden, "API key lacks the subscriptions:read scope")
 		default:
 			writeJSONError(w, http.StatusInternalServerError, "failed to get subscription status")
 		}
@@ -73,6 +81,8 @@ func (h *SubscriptionStatusHandler) GetByDomain(w http.ResponseWriter, r *http.R
 			writeJSONError(w, http.StatusNotFound, "subscription not found")
 		case service.ErrAppAccessDenied:
 			writeJSONError(w, http.StatusForbidden, "access denied")
+		case service.ErrInsufficientScope:
+			writeJSONError(w, http.StatusForbidden, "API key lacks the subscriptions:read scope")
 		default:
 			writeJSONError(w, http.StatusInternalServerError, "failed to get subscription status")
 		}
@@ -83,6 +93,16 @@ func (h *SubscriptionStatusHandler) GetByDomain(w http.ResponseWriter, r *http.R
 	json.NewEncoder(w).Encode(status.ToResponse())
 }
 
+// shopifyGIDParam returns the shopify_gid path parameter. Clients URL-encode the
+// slashes of a GID, which chi leaves escaped when routing on the raw path.
+func shopifyGIDParam(r *http.Request) string {
+	gid := chi.URLParam(r, "shopify_gid")
+	if unescaped, err := url.PathUnescape(gid); err == nil {
+		return unescaped
+	}
+	return gid
+}
+
 // BatchRequest is the request body for batch lookups
 type BatchRequest struct {
 	IDs []string `json:"ids"`
@@ -115,6 +135,10 @@ func (h *SubscriptionStatusHandler) GetBatch(w http.ResponseWriter, r *http.Requ
 
 	resp, err := h.service.GetByShopifyGIDs(r.Context(), apiKey.UserID, req.IDs)
 	if err != nil {
+		if err == service.ErrInsufficientScope {
+			writeJSONError(w, http.StatusForbidden, "API key lacks the subscriptions:read scope")
+			return
+		}
 		writeJSONError(w, http.StatusInternalServerError, "failed to get subscription statuses")
 		return
 	}
@@ -122,3 +146,92 @@ func (h *SubscriptionStatusHandler) GetBatch(w http.ResponseWriter, r *http.Requ
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
+
+// List returns one page of subscription statuses. The next page is linked in the
+// Link header and its cursor returned as next_cursor.
+// GET /v1/subscriptions?app_id=&risk_state=&status=&overdue=&order_by=&direction=&limit=&after=
+func (h *SubscriptionStatusHandler) List(w http.ResponseWriter, r *http.Request) {
+	apiKey := middleware.APIKeyFromContext(r.Context())
+	if apiKey == nil {
+		writeJSONError(w, http.StatusUnauthorized, "API key required")
+		return
+	}
+
+	q := r.URL.Query()
+	req := service.SubscriptionListRequest{
+		AppID:   q.Get("app_id"),
+		OrderBy: revrepo.SubscriptionStatusSortField(strings.ToUpper(q.Get("order_by"))),
+		After:   q.Get("after"),
+	}
+
+	if v := q.Get("risk_state"); v != "" {
+		riskState := valueobject.RiskState(strings.ToUpper(v))
+		if !riskState.IsValid() {
+			writeJSONError(w, http.StatusBadRequest, "invalid risk_state")
+			return
+		}
+		req.Filter.RiskState = &riskState
+	}
+	if v := q.Get("status"); v != "" {
+		status := strings.ToUpper(v)
+		req.Filter.Status = &status
+	}
+	if v := q.Get("overdue"); v != "" {
+		overdue, err := strconv.ParseBool(v)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "overdue must be true or false")
+			return
+		}
+		req.Filter.IsOverdue = &overdue
+	}
+	switch strings.ToLower(q.Get("direction")) {
+	case "", "asc":
+	case "desc":
+		req.Descending = true
+	default:
+		writeJSONError(w, http.StatusBadRequest, "direction must be asc or desc")
+		return
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > service.MaxSubscriptionPageSize {
+			writeJSONError(w, http.StatusBadRequest, "limit must be between 1 and 250")
+			return
+		}
+		req.First = n
+	}
+
+	page, err := h.service.List(r.Context(), apiKey.UserID, req)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidCursor, service.ErrInvalidSortField:
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+		case service.ErrAppAccessDenied:
+			writeJSONError(w, http.StatusForbidden, "access denied")
+		case service.ErrInsufficientScope:
+			writeJSONError(w, http.StatusForbidden, "API key lacks the subscriptions:read scope")
+		default:
+			writeJSONError(w, http.StatusInternalServerError, "failed to list subscription statuses")
+		}
+		return
+	}
+
+	resp := entity.SubscriptionStatusListResponse{
+		Data:       make([]entity.SubscriptionStatusResponse, len(page.Statuses)),
+		HasMore:    page.HasNextPage,
+		TotalCount: page.TotalCount,
+	}
+	for i, status := range page.Statuses {
+		resp.Data[i] = status.ToResponse()
+	}
+	if page.HasNextPage && len(page.Cursors) > 0 {
+		resp.NextCursor = page.Cursors[len(page.Cursors)-1]
+
+		query := r.URL.Query()
+		query.Set("after", resp.NextCursor)
+		next := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		w.Header().Set("Link", "<"+next.String()+`>; rel="next"`)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
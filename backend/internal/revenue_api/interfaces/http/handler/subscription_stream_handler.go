@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/middleware"
+)
+
+const (
+	subscriptionChangedEvent = "subscription.changed"
+	streamResyncEvent        = "resync"
+	streamRetryMillis        = 3000
+	streamHeartbeatInterval  = 25 * time.Second
+)
+
+// SubscriptionStreamHandler streams subscription changes as Server-Sent Events
+type SubscriptionStreamHandler struct {
+	hub               *service.SubscriptionChangeHub
+	service           *service.SubscriptionStatusService
+	heartbeatInterval time.Duration
+}
+
+// NewSubscriptionStreamHandler creates a new SubscriptionStreamHandler
+func NewSubscriptionStreamHandler(hub *service.SubscriptionChangeHub, svc *service.SubscriptionStatusService) *SubscriptionStreamHandler {
+	return &SubscriptionStreamHandler{
+		hub:               hub,
+		service:           svc,
+		heartbeatInterval: streamHeartbeatInterval,
+	}
+}
+
+// subscriptionChangeEvent is the data of a subscription.changed event
+type subscriptionChangeEvent struct {
+	*entity.SubscriptionChange
+	Changes []string `json:"changes"` // status, risk_state
+}
+
+// Stream pushes status and risk state changes of the API key owner's apps
+// GET /v1/stream/subscriptions?app_id=
+//
+// Clients resume with the Last-Event-ID header (or last_event_id query parameter).
+// If that event is no longer buffered a "resync" event is sent first and the
+// client should re-read current state before applying further changes.
+func (h *SubscriptionStreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	apiKey := middleware.APIKeyFromContext(r.Context())
+	if apiKey == nil {
+		writeJSONError(w, http.StatusUnauthorized, "API key required")
+		return
+	}
+
+	if err := service.RequireScope(r.Context(), entity.ScopeStream); err != nil {
+		writeJSONError(w, http.StatusForbidden, "API key lacks the stream scope")
+		return
+	}
+
+	appIDs, err := h.service.ResolveAppIDs(r.Context(), apiKey.UserID, r.URL.Query().Get("app_id"))
+	if err != nil {
+		switch err {
+		case service.ErrAppAccessDenied:
+			writeJSONError(w, http.StatusForbidden, "access denied")
+		default:
+			writeJSONError(w, http.StatusInternalServerError, "failed to resolve apps")
+		}
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// Streams outlive the server's write timeout
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	stream := h.hub.Subscribe(appIDs, lastEventID)
+	defer stream.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetryMillis)
+	if lastEventID != "" && !stream.Resumed {
+		fmt.Fprintf(w, "event: %s\ndata: {\"last_event_id\":%q}\n\n", streamResyncEvent, lastEventID)
+	}
+	for _, change := range stream.Replay {
+		if err := writeSubscriptionChange(w, change); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case change, ok := <-stream.C:
+			if !ok {
+				// Dropped for falling behind; the client reconnects with its Last-Event-ID
+				return
+			}
+			if err := writeSubscriptionChange(w, change); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeSubscriptionChange(w http.ResponseWriter, change *entity.SubscriptionChange) error {
+	event := subscriptionChangeEvent{SubscriptionChange: change, Changes: []string{}}
+	if change.StatusChanged() {
+		event.Changes = append(event.Changes, "status")
+	}
+	if change.RiskStateChanged() {
+		event.Changes = append(event.Changes, "risk_state")
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", change.ID, subscriptionChangedEvent, data)
+	return err
+}
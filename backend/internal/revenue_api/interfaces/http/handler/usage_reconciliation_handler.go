@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/middleware"
+)
+
+// UsageReconciliationHandler handles usage record reporting and reconciliation
+type UsageReconciliationHandler struct {
+	service *service.UsageReconciliationService
+}
+
+// NewUsageReconciliationHandler creates a new UsageReconciliationHandler
+func NewUsageReconciliationHandler(svc *service.UsageReconciliationService) *UsageReconciliationHandler {
+	return &UsageReconciliationHandler{service: svc}
+}
+
+// ReportedUsageRequest is a usage record the app created in Shopify
+type ReportedUsageRequest struct {
+	UsageID        string    `json:"usage_id"`        // gid://shopify/AppUsageRecord/...
+	SubscriptionID string    `json:"subscription_id"` // gid://shopify/AppSubscription/...
+	AmountCents    int       `json:"amount_cents"`
+	Description    string    `json:"description"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// UsageReportRequest is the request body for reporting usage records
+type UsageReportRequest struct {
+	Records []ReportedUsageRequest `json:"records"`
+}
+
+// Report records usage records so they can be reconciled against billing
+// POST /v1/usages
+func (h *UsageReconciliationHandler) Report(w http.ResponseWriter, r *http.Request) {
+	apiKey := middleware.APIKeyFromContext(r.Context())
+	if apiKey == nil {
+		writeJSONError(w, http.StatusUnauthorized, "API key required")
+		return
+	}
+
+	var req UsageReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Records) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "records array is required")
+		return
+	}
+
+	records := make([]service.ReportedUsage, len(req.Records))
+	for i, rec := range req.Records {
+		records[i] = service.ReportedUsage{
+			ShopifyGID:      rec.UsageID,
+			SubscriptionGID: rec.SubscriptionID,
+			AmountCents:     rec.AmountCents,
+			Description:     rec.Description,
+			CreatedAt:       rec.CreatedAt,
+		}
+	}
+
+	resp, err := h.service.Report(r.Context(), apiKey.UserID, records)
+	if err != nil {
+		switch err {
+		case service.ErrInsufficientScope:
+			writeJSONError(w, http.StatusForbidden, "API key lacks the usage:write scope")
+		case service.ErrUsageBatchTooLarge:
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+		default:
+			writeJSONError(w, http.StatusInternalServerError, "failed to report usage records")
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if len(resp.Accepted) == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	writeJSON(w, status, resp)
+}
+
+// Reconciliation compares an app's reported usage records with what Shopify billed
+// GET /v1/usages/reconciliation?app_id=&from=&to=&state=
+func (h *UsageReconciliationHandler) Reconciliation(w http.ResponseWriter, r *http.Request) {
+	apiKey := middleware.APIKeyFromContext(r.Context())
+	if apiKey == nil {
+		writeJSONError(w, http.StatusUnauthorized, "API key required")
+		return
+	}
+
+	var query service.ReconciliationQuery
+	for name, target := range map[string]**time.Time{"from": &query.From, "to": &query.To} {
+		if v := r.URL.Query().Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, name+" must be an RFC 3339 timestamp")
+				return
+			}
+			*target = &t
+		}
+	}
+	if v := r.URL.Query().Get("state"); v != "" {
+		query.States = strings.Split(strings.ToUpper(v), ",")
+	}
+
+	report, err := h.service.GetReconciliation(r.Context(), apiKey.UserID, r.URL.Query().Get("app_id"), query)
+	if err != nil {
+		switch err {
+		case service.ErrInsufficientScope:
+			writeJSONError(w, http.StatusForbidden, "API key lacks the usage:read scope")
+		case service.ErrAppAccessDenied:
+			writeJSONError(w, http.StatusForbidden, "access denied")
+		case service.ErrInvalidReconciliationRange, service.ErrInvalidReconciliationState:
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+		default:
+			writeJSONError(w, http.StatusInternalServerError, "failed to reconcile usage records")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
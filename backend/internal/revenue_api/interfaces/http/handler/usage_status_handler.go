@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"net/http"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/middleware"
 )
@@ -28,7 +27,7 @@ func (h *UsageStatusHandler) GetByGID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	shopifyGID := chi.URLParam(r, "shopify_gid")
+	shopifyGID := shopifyGIDParam(r)
 	if shopifyGID == "" {
 		writeJSONError(w, http.StatusBadRequest, "shopify_gid is required")
 		return
@@ -41,6 +40,8 @@ func (h *UsageStatusHandler) GetByGID(w http.ResponseWriter, r *http.Request) {
 			writeJSONError(w, http.StatusNotFound, "usage record not found")
 		case service.ErrAppAccessDenied:
 			writeJSONError(w, http.StatusForbidden, "access denied")
+		case service.ErrInsufficientScope:
+			writeJSONError(w, http.StatusForbidden, "API key lacks the usage:read scope")
 		default:
 			writeJSONError(w, http.StatusInternalServerError, "failed to get usage status")
 		}
@@ -83,6 +84,10 @@ func (h *UsageStatusHandler) GetBatch(w http.ResponseWriter, r *http.Request) {
 
 	resp, err := h.service.GetByShopifyGIDs(r.Context(), apiKey.UserID, req.IDs)
 	if err != nil {
+		if err == service.ErrInsufficientScope {
+			writeJSONError(w, http.StatusForbidden, "API key lacks the usage:read scope")
+			return
+		}
 		writeJSONError(w, http.StatusInternalServerError, "failed to get usage statuses")
 		return
 	}
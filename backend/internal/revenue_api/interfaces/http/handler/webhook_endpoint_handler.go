@@ -0,0 +1,394 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/interfaces/http/middleware"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	revenueentity "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	revenueMiddleware "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/middleware"
+)
+
+// WebhookEndpointHandler handles outbound webhook endpoint management
+type WebhookEndpointHandler struct {
+	service *service.WebhookEndpointService
+}
+
+// NewWebhookEndpointHandler creates a new WebhookEndpointHandler
+func NewWebhookEndpointHandler(svc *service.WebhookEndpointService) *WebhookEndpointHandler {
+	return &WebhookEndpointHandler{service: svc}
+}
+
+// CreateWebhookEndpointRequest is the request body for registering an endpoint
+type CreateWebhookEndpointRequest struct {
+	URL         string   `json:"url"`
+	Description string   `json:"description,omitempty"`
+	Topics      []string `json:"topics"`
+}
+
+// UpdateWebhookEndpointRequest is the request body for updating an endpoint; omitted fields are unchanged
+type UpdateWebhookEndpointRequest struct {
+	URL         *string  `json:"url,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Topics      []string `json:"topics,omitempty"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+}
+
+// WebhookEndpointResponse is the response format for an endpoint
+type WebhookEndpointResponse struct {
+	ID                  string   `json:"id"`
+	URL                 string   `json:"url"`
+	Description         string   `json:"description"`
+	Topics              []string `json:"topics"`
+	Enabled             bool     `json:"enabled"`
+	ConsecutiveFailures int      `json:"consecutive_failures"`
+	DisabledAt          *string  `json:"disabled_at"`
+	DisabledReason      string   `json:"disabled_reason,omitempty"`
+	Secret              string   `json:"secret,omitempty"` // Only on create and secret rotation
+	CreatedAt           string   `json:"created_at"`
+	UpdatedAt           string   `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse is the response format for a delivery log entry
+type WebhookDeliveryResponse struct {
+	ID             string          `json:"id"`
+	EventID        string          `json:"event_id"`
+	Topic          string          `json:"topic"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *string         `json:"next_attempt_at"`
+	LastAttemptAt  *string         `json:"last_attempt_at"`
+	ResponseStatus *int            `json:"response_status"`
+	ResponseBody   string          `json:"response_body,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	RedeliveryOf   *string         `json:"redelivery_of"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      string          `json:"created_at"`
+	DeliveredAt    *string         `json:"delivered_at"`
+}
+
+// Topics lists the topics endpoints can subscribe to
+// GET /api/v1/webhook-endpoints/topics
+func (h *WebhookEndpointHandler) Topics(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireOwner(w, r); !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"topics": revenueentity.WebhookTopics,
+	})
+}
+
+// Create registers a new endpoint and returns its signing secret
+// POST /api/v1/webhook-endpoints
+func (h *WebhookEndpointHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireOwner(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateWebhookEndpointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	endpoint, err := h.service.Create(r.Context(), service.CreateWebhookEndpointRequest{
+		UserID:      userID,
+		URL:         req.URL,
+		Description: req.Description,
+		Topics:      req.Topics,
+	})
+	if err != nil {
+		writeWebhookEndpointError(w, err, "failed to create webhook endpoint")
+		return
+	}
+
+	resp := toWebhookEndpointResponse(endpoint)
+	resp.Secret = endpoint.Secret
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"webhook_endpoint": resp,
+	})
+}
+
+// List returns all endpoints of the authenticated user
+// GET /api/v1/webhook-endpoints
+func (h *WebhookEndpointHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireOwner(w, r)
+	if !ok {
+		return
+	}
+
+	endpoints, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to list webhook endpoints")
+		return
+	}
+
+	resp := make([]WebhookEndpointResponse, len(endpoints))
+	for i, e := range endpoints {
+		resp[i] = toWebhookEndpointResponse(e)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"webhook_endpoints": resp,
+	})
+}
+
+// Get returns a single endpoint
+// GET /api/v1/webhook-endpoints/{id}
+func (h *WebhookEndpointHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, endpointID, ok := h.requireOwnerAndEndpoint(w, r)
+	if !ok {
+		return
+	}
+
+	endpoint, err := h.service.Get(r.Context(), userID, endpointID)
+	if err != nil {
+		writeWebhookEndpointError(w, err, "failed to get webhook endpoint")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"webhook_endpoint": toWebhookEndpointResponse(endpoint),
+	})
+}
+
+// Update changes URL, description, topics or enabled state
+// PUT /api/v1/webhook-endpoints/{id}
+func (h *WebhookEndpointHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, endpointID, ok := h.requireOwnerAndEndpoint(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateWebhookEndpointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	endpoint, err := h.service.Update(r.Context(), userID, endpointID, service.UpdateWebhookEndpointRequest{
+		URL:         req.URL,
+		Description: req.Description,
+		Topics:      req.Topics,
+		Enabled:     req.Enabled,
+	})
+	if err != nil {
+		writeWebhookEndpointError(w, err, "failed to update webhook endpoint")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"webhook_endpoint": toWebhookEndpointResponse(endpoint),
+	})
+}
+
+// RotateSecret replaces the signing secret and returns the new one
+// POST /api/v1/webhook-endpoints/{id}/rotate-secret
+func (h *WebhookEndpointHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	userID, endpointID, ok := h.requireOwnerAndEndpoint(w, r)
+	if !ok {
+		return
+	}
+
+	endpoint, err := h.service.RotateSecret(r.Context(), userID, endpointID)
+	if err != nil {
+		writeWebhookEndpointError(w, err, "failed to rotate webhook secret")
+		return
+	}
+
+	resp := toWebhookEndpointResponse(endpoint)
+	resp.Secret = endpoint.Secret
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"webhook_endpoint": resp,
+	})
+}
+
+// Delete removes an endpoint and its delivery log
+// DELETE /api/v1/webhook-endpoints/{id}
+func (h *WebhookEndpointHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, endpointID, ok := h.requireOwnerAndEndpoint(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), userID, endpointID); err != nil {
+		writeWebhookEndpointError(w, err, "failed to delete webhook endpoint")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries returns the delivery log of an endpoint, newest first
+// GET /api/v1/webhook-endpoints/{id}/deliveries?limit=50
+func (h *WebhookEndpointHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID, endpointID, ok := h.requireOwnerAndEndpoint(w, r)
+	if !ok {
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeJSONError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := h.service.ListDeliveries(r.Context(), userID, endpointID, limit)
+	if err != nil {
+		writeWebhookEndpointError(w, err, "failed to list webhook deliveries")
+		return
+	}
+
+	resp := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		resp[i] = toWebhookDeliveryResponse(d)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"deliveries": resp,
+	})
+}
+
+// Redeliver queues a past delivery again with the same event ID and payload
+// POST /api/v1/webhook-endpoints/{id}/deliveries/{deliveryID}/redeliver
+func (h *WebhookEndpointHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	userID, endpointID, ok := h.requireOwnerAndEndpoint(w, r)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid delivery ID")
+		return
+	}
+
+	delivery, err := h.service.Redeliver(r.Context(), userID, endpointID, deliveryID)
+	if err != nil {
+		writeWebhookEndpointError(w, err, "failed to redeliver webhook")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"delivery": toWebhookDeliveryResponse(delivery),
+	})
+}
+
+// requireOwner resolves the calling user: a dashboard user with the OWNER role, like
+// API key management, or an API key with the webhooks:manage scope
+func (h *WebhookEndpointHandler) requireOwner(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	if apiKey := revenueMiddleware.APIKeyFromContext(r.Context()); apiKey != nil {
+		if err := service.RequireScope(r.Context(), revenueentity.ScopeWebhooksManage); err != nil {
+			writeJSONError(w, http.StatusForbidden, "API key lacks the webhooks:manage scope")
+			return uuid.Nil, false
+		}
+		return apiKey.UserID, true
+	}
+
+	user := middleware.UserFromContext(r.Context())
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "authentication required")
+		return uuid.Nil, false
+	}
+
+	if user.Role != "OWNER" {
+		writeJSONError(w, http.StatusForbidden, "only account owners can manage webhook endpoints")
+		return uuid.Nil, false
+	}
+
+	return user.ID, true
+}
+
+func (h *WebhookEndpointHandler) requireOwnerAndEndpoint(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := h.requireOwner(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	endpointID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid webhook endpoint ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, endpointID, true
+}
+
+func writeWebhookEndpointError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case service.ErrWebhookEndpointNotFound, service.ErrWebhookDeliveryNotFound:
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	case service.ErrInvalidWebhookURL, service.ErrInvalidWebhookTopics:
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	case service.ErrTooManyWebhookEndpoints, service.ErrWebhookEndpointDisabled:
+		writeJSONError(w, http.StatusConflict, err.Error())
+	default:
+		writeJSONError(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+func toWebhookEndpointResponse(e *revenueentity.WebhookEndpoint) WebhookEndpointResponse {
+	return WebhookEndpointResponse{
+		ID:                  e.ID.String(),
+		URL:                 e.URL,
+		Description:         e.Description,
+		Topics:              e.Topics,
+		Enabled:             e.Enabled,
+		ConsecutiveFailures: e.ConsecutiveFailures,
+		DisabledAt:          formatOptionalTime(e.DisabledAt),
+		DisabledReason:      e.DisabledReason,
+		CreatedAt:           e.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           e.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toWebhookDeliveryResponse(d *revenueentity.WebhookDelivery) WebhookDeliveryResponse {
+	resp := WebhookDeliveryResponse{
+		ID:            d.ID.String(),
+		EventID:       d.EventID.String(),
+		Topic:         d.Topic,
+		Status:        string(d.Status),
+		Attempts:      d.Attempts,
+		NextAttemptAt: formatOptionalTime(d.NextAttemptAt),
+		LastAttemptAt: formatOptionalTime(d.LastAttemptAt),
+		ResponseBody:  d.ResponseBody,
+		LastError:     d.LastError,
+		Payload:       json.RawMessage(d.Payload),
+		CreatedAt:     d.CreatedAt.Format(time.RFC3339),
+		DeliveredAt:   formatOptionalTime(d.DeliveredAt),
+	}
+	if d.ResponseStatus != 0 {
+		status := d.ResponseStatus
+		resp.ResponseStatus = &status
+	}
+	if d.RedeliveryOf != nil {
+		original := d.RedeliveryOf.String()
+		resp.RedeliveryOf = &original
+	}
+	return resp
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
 )
 
 // contextKey is a custom type for context keys to avoid collisions
@@ -22,6 +24,8 @@ type ValidatedAPIKey struct {
 	ID                 uuid.UUID
 	UserID             uuid.UUID
 	RateLimitPerMinute int
+	Scopes             []string
+	AppIDs             []uuid.UUID // Empty = all of the user's apps
 }
 
 // APIKeyFromContext retrieves the validated API key from context
@@ -74,17 +78,36 @@ func (m *APIKeyAuth) Middleware(next http.Handler) http.Handler {
 				writeJSONError(w, http.StatusUnauthorized, "invalid API key")
 			case service.ErrAPIKeyRevoked:
 				writeJSONError(w, http.StatusUnauthorized, "API key has been revoked")
+			case service.ErrAPIKeyExpired:
+				writeJSONError(w, http.StatusUnauthorized, "API key has expired")
 			default:
 				writeJSONError(w, http.StatusInternalServerError, "authentication error")
 			}
 			return
 		}
 
-		// Add validated key to context
+		// Tell clients ahead of time that the key is going away
+		if validatedKey.ExpiresAt != nil {
+			w.Header().Set("X-API-Key-Expires-At", validatedKey.ExpiresAt.Format(time.RFC3339))
+			switch {
+			case validatedKey.Rotated:
+				w.Header().Set("X-API-Key-Warning", "key has been rotated; switch to its successor before it expires")
+			case time.Until(*validatedKey.ExpiresAt) <= entity.APIKeyExpiryWarning:
+				w.Header().Set("X-API-Key-Warning", "key expires soon; rotate it")
+			}
+		}
+
+		// Add validated key to context, and its scopes and apps for the services to enforce
 		ctx := SetAPIKeyContext(r.Context(), &ValidatedAPIKey{
 			ID:                 validatedKey.ID,
 			UserID:             validatedKey.UserID,
 			RateLimitPerMinute: validatedKey.RateLimitPerMinute,
+			Scopes:             validatedKey.Scopes,
+			AppIDs:             validatedKey.AppIDs,
+		})
+		ctx = service.WithAPIKeyAccess(ctx, &service.APIKeyAccess{
+			Scopes: validatedKey.Scopes,
+			AppIDs: validatedKey.AppIDs,
 		})
 
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -7,13 +7,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
 )
 
 // AuditLogger is middleware that logs API requests
 type AuditLogger struct {
-	repo repository.AuditLogRepository
+	repo         repository.AuditLogRepository
+	usageTracker *service.APIKeyUsageTracker
 }
 
 // NewAuditLogger creates a new AuditLogger middleware
@@ -21,6 +24,12 @@ func NewAuditLogger(repo repository.AuditLogRepository) *AuditLogger {
 	return &AuditLogger{repo: repo}
 }
 
+// WithUsageTracker records each logged request as the key's last use
+func (m *AuditLogger) WithUsageTracker(tracker *service.APIKeyUsageTracker) *AuditLogger {
+	m.usageTracker = tracker
+	return m
+}
+
 // responseWriter wraps http.ResponseWriter to capture the status code
 type responseWriter struct {
 	http.ResponseWriter
@@ -32,6 +41,11 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap exposes the underlying writer so streaming handlers can flush through it
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Middleware returns the HTTP middleware handler
 func (m *AuditLogger) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -72,8 +86,17 @@ func (m *AuditLogger) Middleware(next http.Handler) http.Handler {
 			r.UserAgent(),
 		)
 
+		// Route pattern is known once routing has run
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			auditLog.Route = rctx.RoutePattern()
+		}
+
 		// Log asynchronously to not block the response
 		m.repo.CreateAsync(auditLog)
+
+		if m.usageTracker != nil {
+			m.usageTracker.Touch(apiKey.ID, auditLog.CreatedAt)
+		}
 	})
 }
 
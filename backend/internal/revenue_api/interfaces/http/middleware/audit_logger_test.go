@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/application/service"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
+)
+
+// memAuditLogRepo stores audit logs synchronously so the test can roll them up at once
+type memAuditLogRepo struct {
+	repository.AuditLogRepository
+	mu   sync.Mutex
+	logs []*entity.AuditLog
+}
+
+func (m *memAuditLogRepo) CreateAsync(log *entity.AuditLog) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, log)
+}
+
+// rollupUsageRepo rolls the audit log up by key, hour, route and method like the Postgres repository
+type rollupUsageRepo struct {
+	audit   *memAuditLogRepo
+	rollups []*entity.APIUsageRollup
+}
+
+func (m *rollupUsageRepo) RollupSince(ctx context.Context, since time.Time) error {
+	type key struct {
+		apiKeyID uuid.UUID
+		hour     time.Time
+		endpoint string
+		method   string
+	}
+	byKey := make(map[key]*entity.APIUsageRollup)
+	m.rollups = nil
+	for _, l := range m.audit.logs {
+		if l.CreatedAt.Before(since.Truncate(time.Hour)) {
+			continue
+		}
+		endpoint := l.Route
+		if endpoint == "" {
+			endpoint = l.Endpoint
+		}
+		k := key{l.APIKeyID, l.CreatedAt.Truncate(time.Hour), endpoint, l.Method}
+		r, ok := byKey[k]
+		if !ok {
+			r = &entity.APIUsageRollup{APIKeyID: k.apiKeyID, Hour: k.hour, Endpoint: endpoint, Method: l.Method, LatencyBuckets: entity.NewLatencyHistogram()}
+			byKey[k] = r
+			m.rollups = append(m.rollups, r)
+		}
+		r.RequestCount++
+		if l.ResponseStatus >= 400 {
+			r.ErrorCount++
+		}
+		r.TotalResponseMs += int64(l.ResponseTimeMs)
+	}
+	return nil
+}
+
+func (m *rollupUsageRepo) GetRollups(ctx context.Context, apiKeyID uuid.UUID, from, to time.Time) ([]*entity.APIUsageRollup, error) {
+	var result []*entity.APIUsageRollup
+	for _, r := range m.rollups {
+		if r.APIKeyID == apiKeyID && !r.Hour.Before(from) && r.Hour.Before(to) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (m *rollupUsageRepo) GetCallerRollups(ctx context.Context, apiKeyID uuid.UUID, from, to time.Time) ([]*entity.APICallerRollup, error) {
+	return nil, nil
+}
+
+type singleAPIKeyRepo struct {
+	repository.APIKeyRepository
+	key *entity.APIKey
+}
+
+func (m *singleAPIKeyRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.APIKey, error) {
+	if id != m.key.ID {
+		return nil, errors.New("not found")
+	}
+	return m.key, nil
+}
+
+func TestAuditLogger_RequestsShowUpInKeyUsage(t *testing.T) {
+	userID := uuid.New()
+	key := &entity.APIKey{ID: uuid.New(), UserID: userID, RateLimitPerMinute: 60}
+	auditRepo := &memAuditLogRepo{}
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		// Stands in for APIKeyAuth, which runs before the audit logger
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			validated := &ValidatedAPIKey{ID: key.ID, UserID: userID, RateLimitPerMinute: key.RateLimitPerMinute}
+			next.ServeHTTP(w, r.WithContext(SetAPIKeyContext(r.Context(), validated)))
+		})
+	})
+	r.Use(NewAuditLogger(auditRepo).Middleware)
+	r.Get("/v1/subscriptions/{shopify_gid}", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "shopify_gid") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, gid := range []string{"gid-1", "gid-2", "missing"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/subscriptions/"+gid, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	usageRepo := &rollupUsageRepo{audit: auditRepo}
+	usageSvc := service.NewAPIUsageService(usageRepo, &singleAPIKeyRepo{key: key})
+	if err := usageSvc.RefreshRollups(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report, err := usageSvc.GetKeyUsage(context.Background(), userID, key.ID, service.UsageQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Totals.Requests != 3 || report.Totals.Errors != 1 {
+		t.Errorf("totals = %+v, want 3 requests and 1 error", report.Totals)
+	}
+	if len(report.Endpoints) != 1 || report.Endpoints[0].Endpoint != "/v1/subscriptions/{shopify_gid}" || report.Endpoints[0].Method != http.MethodGet {
+		t.Errorf("endpoints = %+v, want one GET /v1/subscriptions/{shopify_gid}", report.Endpoints)
+	}
+}
@@ -2,30 +2,28 @@ package middleware
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"strconv"
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/repository"
 )
 
-// RateLimitStore is an interface for rate limit storage
-type RateLimitStore interface {
-	// Increment increments the counter for a key and returns the new count
-	// Returns the count after increment and the TTL remaining
-	Increment(ctx context.Context, key string, window time.Duration) (count int64, err error)
-}
-
-// RateLimiter is middleware that enforces rate limits per API key
+// RateLimiter is middleware that enforces rate limits per API key, and optionally
+// per user across all of the user's keys
 type RateLimiter struct {
-	store         RateLimitStore
+	store         repository.RateLimitStore
 	defaultLimit  int
 	windowSeconds int
+	userLimit     int // 0 = no per-user quota
 }
 
-// NewRateLimiter creates a new RateLimiter
-func NewRateLimiter(store RateLimitStore, defaultLimit int, windowSeconds int) *RateLimiter {
+// NewRateLimiter creates a new RateLimiter. Limits are requests per windowSeconds;
+// use a shared store (Postgres, Redis) when running more than one replica.
+func NewRateLimiter(store repository.RateLimitStore, defaultLimit int, windowSeconds int) *RateLimiter {
 	return &RateLimiter{
 		store:         store,
 		defaultLimit:  defaultLimit,
@@ -33,6 +31,12 @@ func NewRateLimiter(store RateLimitStore, defaultLimit int, windowSeconds int) *
 	}
 }
 
+// WithUserLimit caps the requests of all of a user's keys combined per window
+func (m *RateLimiter) WithUserLimit(limit int) *RateLimiter {
+	m.userLimit = limit
+	return m
+}
+
 // Middleware returns the HTTP middleware handler
 func (m *RateLimiter) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -49,13 +53,10 @@ func (m *RateLimiter) Middleware(next http.Handler) http.Handler {
 		if limit <= 0 {
 			limit = m.defaultLimit
 		}
+		period := time.Duration(m.windowSeconds) * time.Second
 
-		// Create a unique key for this API key + current window
-		window := time.Duration(m.windowSeconds) * time.Second
-		windowKey := m.getWindowKey(apiKey.ID, window)
-
-		// Increment the counter
-		count, err := m.store.Increment(r.Context(), windowKey, window)
+		keyQuota := "ratelimit:key:" + apiKey.ID.String()
+		decision, err := m.store.Allow(r.Context(), keyQuota, limit, period)
 		if err != nil {
 			// On error, allow the request but log it
 			// In production, you might want to fail closed instead
@@ -63,15 +64,28 @@ func (m *RateLimiter) Middleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// Set rate limit headers
-		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
-		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(max(0, limit-int(count))))
-		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(window).Unix(), 10))
+		// The user quota is only charged for requests the key quota let through, and
+		// a request the user quota rejects gives its key token back. Headers report
+		// whichever quota is closer to running out.
+		message := "rate limit exceeded"
+		if decision.Allowed && m.userLimit > 0 {
+			userDecision, err := m.store.Allow(r.Context(), "ratelimit:user:"+apiKey.UserID.String(), m.userLimit, period)
+			if err == nil && (!userDecision.Allowed || userDecision.Remaining < decision.Remaining) {
+				decision = userDecision
+				message = "user rate limit exceeded across all API keys"
+			}
+			if err == nil && !userDecision.Allowed {
+				if err := m.store.Refund(r.Context(), keyQuota, limit, period); err != nil {
+					log.Printf("RateLimiter: refund %s: %v", keyQuota, err)
+				}
+			}
+		}
+
+		setRateLimitHeaders(w, decision, time.Now())
 
-		// Check if over limit
-		if int(count) > limit {
-			w.Header().Set("Retry-After", strconv.Itoa(m.windowSeconds))
-			writeJSONError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		if !decision.Allowed {
+			w.Header().Set("Retry-After", strconv.FormatInt(ceilSeconds(decision.RetryAfter), 10))
+			writeJSONError(w, http.StatusTooManyRequests, message)
 			return
 		}
 
@@ -79,35 +93,31 @@ func (m *RateLimiter) Middleware(next http.Handler) http.Handler {
 	})
 }
 
-func (m *RateLimiter) getWindowKey(keyID uuid.UUID, window time.Duration) string {
-	// Create a key based on the API key ID and the current window
-	windowStart := time.Now().Truncate(window).Unix()
-	return "ratelimit:" + keyID.String() + ":" + strconv.FormatInt(windowStart, 10)
+func setRateLimitHeaders(w http.ResponseWriter, decision entity.RateLimitDecision, now time.Time) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	// When the full quota is available again
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Unix()+ceilSeconds(decision.ResetAfter), 10))
 }
 
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
+// ceilSeconds rounds d up to whole seconds, at least 1 for a positive d
+func ceilSeconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
 }
 
 // InMemoryRateLimitStore is an in-memory implementation of RateLimitStore
 // Suitable for single-instance deployments or development
 type InMemoryRateLimitStore struct {
-	mu       sync.RWMutex
-	counters map[string]*rateLimitEntry
-}
-
-type rateLimitEntry struct {
-	count     int64
-	expiresAt time.Time
+	mu   sync.Mutex
+	tats map[string]time.Time
+	now  func() time.Time
 }
 
 // NewInMemoryRateLimitStore creates a new in-memory rate limit store
 func NewInMemoryRateLimitStore() *InMemoryRateLimitStore {
 	store := &InMemoryRateLimitStore{
-		counters: make(map[string]*rateLimitEntry),
+		tats: make(map[string]time.Time),
+		now:  time.Now,
 	}
 
 	// Start cleanup goroutine
@@ -116,65 +126,40 @@ func NewInMemoryRateLimitStore() *InMemoryRateLimitStore {
 	return store
 }
 
-// Increment increments the counter for a key
-func (s *InMemoryRateLimitStore) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+// Allow consumes one request from key's quota if it's available
+func (s *InMemoryRateLimitStore) Allow(ctx context.Context, key string, limit int, period time.Duration) (entity.RateLimitDecision, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	now := time.Now()
-	entry, exists := s.counters[key]
+	tat, decision := entity.GCRA(s.tats[key], s.now(), limit, period)
+	s.tats[key] = tat
+	return decision, nil
+}
 
-	if !exists || now.After(entry.expiresAt) {
-		// Create new entry
-		s.counters[key] = &rateLimitEntry{
-			count:     1,
-			expiresAt: now.Add(window),
-		}
-		return 1, nil
-	}
+// Refund gives back one request to key's quota
+func (s *InMemoryRateLimitStore) Refund(ctx context.Context, key string, limit int, period time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Increment existing entry
-	entry.count++
-	return entry.count, nil
+	if tat, ok := s.tats[key]; ok {
+		s.tats[key] = entity.GCRARefund(tat, s.now(), limit, period)
+	}
+	return nil
 }
 
-// cleanup removes expired entries periodically
+// cleanup removes keys whose quota has fully refilled
 func (s *InMemoryRateLimitStore) cleanup() {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
 	for range ticker.C {
 		s.mu.Lock()
-		now := time.Now()
-		for key, entry := range s.counters {
-			if now.After(entry.expiresAt) {
-				delete(s.counters, key)
+		now := s.now()
+		for key, tat := range s.tats {
+			if !tat.After(now) {
+				delete(s.tats, key)
 			}
 		}
 		s.mu.Unlock()
 	}
 }
-
-// RedisRateLimitStore is a Redis-backed implementation of RateLimitStore
-// This is a placeholder - actual implementation would use go-redis
-type RedisRateLimitStore struct {
-	// client *redis.Client
-	// In production, inject redis.Client here
-}
-
-// NewRedisRateLimitStore creates a new Redis rate limit store
-// func NewRedisRateLimitStore(client *redis.Client) *RedisRateLimitStore {
-// 	return &RedisRateLimitStore{client: client}
-// }
-
-// Increment increments the counter for a key using Redis INCR + EXPIRE
-// func (s *RedisRateLimitStore) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
-// 	pipe := s.client.Pipeline()
-// 	incr := pipe.Incr(ctx, key)
-// 	pipe.Expire(ctx, key, window)
-// 	_, err := pipe.Exec(ctx)
-// 	if err != nil {
-// 		return 0, err
-// 	}
-// 	return incr.Val(), nil
-// }
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newTestRateLimiter(store *InMemoryRateLimitStore) http.Handler {
+	return NewRateLimiter(store, 60, 60).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func doRateLimited(handler http.Handler, key *ValidatedAPIKey) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/v1/subscriptions/status", nil)
+	req = req.WithContext(SetAPIKeyContext(req.Context(), key))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func newTestStore(now *time.Time) *InMemoryRateLimitStore {
+	return &InMemoryRateLimitStore{
+		tats: make(map[string]time.Time),
+		now:  func() time.Time { return *now },
+	}
+}
+
+func TestRateLimiter_BurstThenSteadyRate(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 59, 0, time.UTC)
+	handler := newTestRateLimiter(newTestStore(&now))
+	key := &ValidatedAPIKey{ID: uuid.New(), UserID: uuid.New(), RateLimitPerMinute: 6}
+
+	for i := 0; i < 6; i++ {
+		rec := doRateLimited(handler, key)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, rec.Code)
+		}
+		if got := rec.Header().Get("X-RateLimit-Remaining"); got != strconv.Itoa(5-i) {
+			t.Errorf("request %d: expected remaining %d, got %s", i+1, 5-i, got)
+		}
+	}
+
+	rec := doRateLimited(handler, key)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after the burst, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "10" {
+		t.Errorf("expected Retry-After 10, got %s", got)
+	}
+
+	// Crossing a minute boundary doesn't refill the quota like a fixed window would
+	now = now.Add(2 * time.Second)
+	if rec := doRateLimited(handler, key); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 across the window edge, got %d", rec.Code)
+	}
+
+	// One request is earned back every 10 seconds
+	now = now.Add(8 * time.Second)
+	rec = doRateLimited(handler, key)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 after one emission interval, got %d", rec.Code)
+	}
+	// The quota is full again a minute after this request
+	reset, _ := strconv.ParseInt(rec.Header().Get("X-RateLimit-Reset"), 10, 64)
+	if until := reset - time.Now().Unix(); until < 59 || until > 61 {
+		t.Errorf("expected reset in about 60s, got %ds", until)
+	}
+}
+
+func TestRateLimiter_UserLimitAcrossKeys(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	handler := NewRateLimiter(newTestStore(&now), 60, 60).WithUserLimit(3).
+		Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	userID := uuid.New()
+	first := &ValidatedAPIKey{ID: uuid.New(), UserID: userID, RateLimitPerMinute: 10}
+	second := &ValidatedAPIKey{ID: uuid.New(), UserID: userID, RateLimitPerMinute: 10}
+
+	for i, key := range []*ValidatedAPIKey{first, second, first} {
+		rec := doRateLimited(handler, key)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, rec.Code)
+		}
+		// The user quota is the tighter one
+		if got := rec.Header().Get("X-RateLimit-Limit"); got != "3" {
+			t.Errorf("request %d: expected limit 3, got %s", i+1, got)
+		}
+	}
+
+	if rec := doRateLimited(handler, second); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected the user quota to stop the second key, got %d", rec.Code)
+	}
+
+	other := &ValidatedAPIKey{ID: uuid.New(), UserID: uuid.New(), RateLimitPerMinute: 10}
+	if rec := doRateLimited(handler, other); rec.Code != http.StatusOK {
+		t.Errorf("expected another user to be unaffected, got %d", rec.Code)
+	}
+}
+
+func TestRateLimiter_UserLimitRefundsKeyToken(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(&now)
+	handler := NewRateLimiter(store, 60, 60).WithUserLimit(1).
+		Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	key := &ValidatedAPIKey{ID: uuid.New(), UserID: uuid.New(), RateLimitPerMinute: 2}
+
+	if rec := doRateLimited(handler, key); rec.Code != http.StatusOK {
+		t.Fatalf("expected the first request to pass, got %d", rec.Code)
+	}
+	if rec := doRateLimited(handler, key); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the user quota to reject the second request, got %d", rec.Code)
+	}
+
+	// The rejected request gave its key token back, so the key still has one left
+	decision, _ := store.Allow(context.Background(), "ratelimit:key:"+key.ID.String(), 2, time.Minute)
+	if !decision.Allowed {
+		t.Errorf("expected the key quota to be refunded, got %+v", decision)
+	}
+}
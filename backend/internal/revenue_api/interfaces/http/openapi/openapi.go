@@ -0,0 +1,129 @@
+// Package openapi serves the OpenAPI 3 document of the Revenue API. The document is
+// maintained by hand in openapi.yaml and checked against the router and the response
+// types in tests.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+var (
+	loadOnce sync.Once
+	document map[string]interface{}
+	specJSON []byte
+	loadErr  error
+)
+
+func load() {
+	if loadErr = yaml.Unmarshal(specYAML, &document); loadErr != nil {
+		return
+	}
+	specJSON, loadErr = json.Marshal(document)
+}
+
+// JSON returns the document encoded as JSON
+func JSON() ([]byte, error) {
+	loadOnce.Do(load)
+	return specJSON, loadErr
+}
+
+// Document returns the decoded document. Callers must not modify it.
+func Document() (map[string]interface{}, error) {
+	loadOnce.Do(load)
+	return document, loadErr
+}
+
+// Handler serves the document
+// GET /v1/openapi.json
+func Handler(w http.ResponseWriter, r *http.Request) {
+	spec, err := JSON()
+	if err != nil {
+		http.Error(w, "failed to load OpenAPI document", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Write(spec)
+}
+
+// SchemaProperties returns the sorted property names of a component schema,
+// including those of schemas it composes with allOf
+func SchemaProperties(name string) ([]string, error) {
+	doc, err := Document()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	if err := collectProperties(doc, "#/components/schemas/"+name, seen); err != nil {
+		return nil, err
+	}
+
+	properties := make([]string, 0, len(seen))
+	for property := range seen {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	return properties, nil
+}
+
+func collectProperties(doc map[string]interface{}, ref string, seen map[string]bool) error {
+	schema, err := Resolve(doc, ref)
+	if err != nil {
+		return err
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for property := range properties {
+			seen[property] = true
+		}
+	}
+	allOf, _ := schema["allOf"].([]interface{})
+	for _, part := range allOf {
+		partSchema, ok := part.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if partRef, ok := partSchema["$ref"].(string); ok {
+			if err := collectProperties(doc, partRef, seen); err != nil {
+				return err
+			}
+			continue
+		}
+		if properties, ok := partSchema["properties"].(map[string]interface{}); ok {
+			for property := range properties {
+				seen[property] = true
+			}
+		}
+	}
+	return nil
+}
+
+// Resolve returns the object a local reference such as #/components/schemas/Error points to
+func Resolve(doc map[string]interface{}, ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("openapi: unsupported reference %q", ref)
+	}
+
+	node := doc
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		next, ok := node[part].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("openapi: unresolved reference %q", ref)
+		}
+		node = next
+	}
+	return node, nil
+}
@@ -0,0 +1,201 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/domain/entity"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/handler"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/openapi"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/router"
+)
+
+// documentedPrefixes are the route prefixes the document must describe completely
+var documentedPrefixes = []string{"/v1/openapi.json", "/v1/subscriptions", "/v1/usages", "/v1/api-keys"}
+
+func loadDocument(t *testing.T) map[string]interface{} {
+	t.Helper()
+	doc, err := openapi.Document()
+	if err != nil {
+		t.Fatalf("failed to load document: %v", err)
+	}
+	return doc
+}
+
+func TestDocument_ReferencesResolve(t *testing.T) {
+	doc := loadDocument(t)
+
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch v := node.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok {
+				if _, err := openapi.Resolve(doc, ref); err != nil {
+					t.Error(err)
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+
+	operationIDs := make(map[string]bool)
+	for path, operations := range doc["paths"].(map[string]interface{}) {
+		for method, operation := range operations.(map[string]interface{}) {
+			id, _ := operation.(map[string]interface{})["operationId"].(string)
+			if id == "" || operationIDs[id] {
+				t.Errorf("%s %s: missing or duplicate operationId %q", method, path, id)
+			}
+			operationIDs[id] = true
+		}
+	}
+}
+
+func TestDocument_MatchesRouter(t *testing.T) {
+	doc := loadDocument(t)
+
+	documented := make(map[string]bool)
+	for path, operations := range doc["paths"].(map[string]interface{}) {
+		for method := range operations.(map[string]interface{}) {
+			documented[strings.ToUpper(method)+" /v1"+path] = true
+		}
+	}
+
+	passthrough := func(next http.Handler) http.Handler { return next }
+	r := router.New(router.Config{
+		APIKeyHandler:              &handler.APIKeyHandler{},
+		APIUsageHandler:            &handler.APIUsageHandler{},
+		SubscriptionStatusHandler:  &handler.SubscriptionStatusHandler{},
+		UsageStatusHandler:         &handler.UsageStatusHandler{},
+		UsageReconciliationHandler: &handler.UsageReconciliationHandler{},
+		FirebaseAuthMW:             passthrough,
+	})
+
+	routed := make(map[string]bool)
+	err := chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.TrimSuffix(route, "/")
+		for _, prefix := range documentedPrefixes {
+			if strings.HasPrefix(route, prefix) {
+				routed[method+" "+route] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk router: %v", err)
+	}
+
+	for route := range routed {
+		if !documented[route] {
+			t.Errorf("route %s is not documented", route)
+		}
+	}
+	for route := range documented {
+		if !routed[route] {
+			t.Errorf("documented operation %s is not routed", route)
+		}
+	}
+}
+
+func TestDocument_SchemasMatchTypes(t *testing.T) {
+	tests := []struct {
+		schema string
+		value  interface{}
+	}{
+		{"SubscriptionStatus", entity.SubscriptionStatusResponse{}},
+		{"SubscriptionStatusList", entity.SubscriptionStatusListResponse{}},
+		{"SubscriptionStatusBatch", entity.SubscriptionStatusBatchResponse{}},
+		{"BatchRequest", handler.BatchRequest{}},
+		{"UsageStatus", entity.UsageStatusResponse{}},
+		{"UsageSubscriptionStatus", entity.UsageSubscriptionStatusResponse{}},
+		{"UsageStatusBatch", entity.UsageStatusBatchResponse{}},
+		{"ReportedUsage", handler.ReportedUsageRequest{}},
+		{"UsageReportRequest", handler.UsageReportRequest{}},
+		{"UsageReportRejection", entity.UsageReportRejection{}},
+		{"UsageReport", entity.UsageReportResponse{}},
+		{"UsageReconciliationSummary", entity.UsageReconciliationSummary{}},
+		{"UsageReconciliationReport", entity.UsageReconciliationReport{}},
+		{"APIKey", handler.APIKeyResponse{}},
+		{"CreateAPIKeyRequest", handler.CreateRequest{}},
+		{"CreatedAPIKey", handler.CreateResponse{}},
+		{"RotateAPIKeyRequest", handler.RotateRequest{}},
+		{"RotatedAPIKey", handler.RotateResponse{}},
+		{"APIUsageStats", entity.APIUsageStats{}},
+		{"APIEndpointUsage", entity.APIEndpointUsage{}},
+		{"APIHourlyUsage", entity.APIHourlyUsage{}},
+		{"APICallerUsage", entity.APICallerUsage{}},
+		{"APIKeyQuota", entity.APIKeyQuota{}},
+		{"APIKeyUsageReport", entity.APIKeyUsageReport{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			properties, err := openapi.SchemaProperties(tt.schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fields := jsonFields(reflect.TypeOf(tt.value))
+			if !reflect.DeepEqual(properties, fields) {
+				t.Errorf("schema properties %v do not match JSON fields %v", properties, fields)
+			}
+		})
+	}
+}
+
+func TestHandler_ServesJSON(t *testing.T) {
+	r := router.New(router.Config{})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/openapi.json", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+
+	var served map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil {
+		t.Fatalf("served document is not JSON: %v", err)
+	}
+	if served["openapi"] != "3.0.3" {
+		t.Errorf("expected OpenAPI 3.0.3, got %v", served["openapi"])
+	}
+}
+
+// jsonFields returns the sorted JSON names of a struct's exported fields, flattening embedded structs
+func jsonFields(t reflect.Type) []string {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			fields = append(fields, jsonFields(field.Type)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -9,15 +9,21 @@ import (
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/graphql"
 	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/handler"
 	revenueMiddleware "github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/middleware"
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/openapi"
 )
 
 // Config holds all handlers and middleware for the Revenue API router
 type Config struct {
 	// Handlers
-	APIKeyHandler             *handler.APIKeyHandler
-	SubscriptionStatusHandler *handler.SubscriptionStatusHandler
-	UsageStatusHandler        *handler.UsageStatusHandler
-	GraphQLHandler            *graphql.Handler
+	APIKeyHandler              *handler.APIKeyHandler
+	APIUsageHandler            *handler.APIUsageHandler
+	SubscriptionStatusHandler  *handler.SubscriptionStatusHandler
+	UsageStatusHandler         *handler.UsageStatusHandler
+	UsageReconciliationHandler *handler.UsageReconciliationHandler
+	SubscriptionStreamHandler  *handler.SubscriptionStreamHandler
+	EntitlementHandler         *handler.EntitlementHandler
+	WebhookEndpointHandler     *handler.WebhookEndpointHandler
+	GraphQLHandler             *graphql.Handler
 
 	// Middleware
 	APIKeyAuthMW  *revenueMiddleware.APIKeyAuth
@@ -36,8 +42,8 @@ func New(cfg Config) *chi.Mux {
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"}, // Allow all origins for API access
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key", "X-Request-ID"},
-		ExposedHeaders:   []string{"Link", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key", "X-Request-ID", "Last-Event-ID"},
+		ExposedHeaders:   []string{"Link", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-API-Key-Expires-At", "X-API-Key-Warning"},
 		AllowCredentials: false,
 		MaxAge:           86400, // 24 hours
 	}))
@@ -54,13 +60,20 @@ func New(cfg Config) *chi.Mux {
 
 	// API v1 routes
 	r.Route("/v1", func(r chi.Router) {
+		// OpenAPI document (public)
+		r.Get("/openapi.json", openapi.Handler)
+
 		// API Key management routes (requires Firebase auth - user must be logged in)
 		if cfg.APIKeyHandler != nil && cfg.FirebaseAuthMW != nil {
 			r.Route("/api-keys", func(r chi.Router) {
 				r.Use(cfg.FirebaseAuthMW)
 				r.Post("/", cfg.APIKeyHandler.Create)
 				r.Get("/", cfg.APIKeyHandler.List)
-				r.Delete("/{keyID}", cfg.APIKeyHandler.Revoke)
+				r.Delete("/{id}", cfg.APIKeyHandler.Revoke)
+				r.Post("/{id}/rotate", cfg.APIKeyHandler.Rotate)
+				if cfg.APIUsageHandler != nil {
+					r.Get("/{id}/usage", cfg.APIUsageHandler.Usage)
+				}
 			})
 		}
 
@@ -78,6 +91,7 @@ func New(cfg Config) *chi.Mux {
 
 		// REST endpoints
 		if cfg.SubscriptionStatusHandler != nil {
+			apiKeyProtected.Get("/subscriptions", cfg.SubscriptionStatusHandler.List) // ?app_id=&risk_state=&status=&overdue=&order_by=&direction=&limit=&after=
 			apiKeyProtected.Get("/subscriptions/{shopify_gid}", cfg.SubscriptionStatusHandler.GetByGID)
 			apiKeyProtected.Get("/subscriptions/status", cfg.SubscriptionStatusHandler.GetByDomain) // ?domain=
 			apiKeyProtected.Post("/subscriptions/batch", cfg.SubscriptionStatusHandler.GetBatch)
@@ -88,6 +102,36 @@ func New(cfg Config) *chi.Mux {
 			apiKeyProtected.Post("/usages/batch", cfg.UsageStatusHandler.GetBatch)
 		}
 
+		// Usage records reported by the app, reconciled against billing
+		if cfg.UsageReconciliationHandler != nil {
+			apiKeyProtected.Post("/usages", cfg.UsageReconciliationHandler.Report)
+			apiKeyProtected.Get("/usages/reconciliation", cfg.UsageReconciliationHandler.Reconciliation) // ?app_id=&from=&to=&state=
+		}
+
+		if cfg.EntitlementHandler != nil {
+			apiKeyProtected.Get("/entitlements", cfg.EntitlementHandler.Check) // ?domain=&app_id=
+		}
+
+		// Server-Sent Events stream of subscription changes
+		if cfg.SubscriptionStreamHandler != nil {
+			apiKeyProtected.Get("/stream/subscriptions", cfg.SubscriptionStreamHandler.Stream)
+		}
+
+		// Webhook endpoint management for keys with the webhooks:manage scope
+		if cfg.WebhookEndpointHandler != nil {
+			apiKeyProtected.Route("/webhook-endpoints", func(r chi.Router) {
+				r.Get("/", cfg.WebhookEndpointHandler.List)
+				r.Post("/", cfg.WebhookEndpointHandler.Create)
+				r.Get("/topics", cfg.WebhookEndpointHandler.Topics)
+				r.Get("/{id}", cfg.WebhookEndpointHandler.Get)
+				r.Put("/{id}", cfg.WebhookEndpointHandler.Update)
+				r.Delete("/{id}", cfg.WebhookEndpointHandler.Delete)
+				r.Post("/{id}/rotate-secret", cfg.WebhookEndpointHandler.RotateSecret)
+				r.Get("/{id}/deliveries", cfg.WebhookEndpointHandler.ListDeliveries)
+				r.Post("/{id}/deliveries/{deliveryID}/redeliver", cfg.WebhookEndpointHandler.Redeliver)
+			})
+		}
+
 		// GraphQL endpoint
 		if cfg.GraphQLHandler != nil {
 			apiKeyProtected.Mount("/graphql", cfg.GraphQLHandler)
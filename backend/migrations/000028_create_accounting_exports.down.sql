@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS accounting_export_runs;
+DROP TABLE IF EXISTS gl_account_mappings;
@@ -0,0 +1,40 @@
+-- GL account mapping per app for accounting system exports
+CREATE TABLE IF NOT EXISTS gl_account_mappings (
+    id UUID PRIMARY KEY,
+    app_id UUID NOT NULL UNIQUE REFERENCES apps(id) ON DELETE CASCADE,
+    recurring_revenue_account VARCHAR(100) NOT NULL,
+    usage_revenue_account VARCHAR(100) NOT NULL,
+    one_time_revenue_account VARCHAR(100) NOT NULL,
+    refund_account VARCHAR(100) NOT NULL,
+    revenue_share_fee_account VARCHAR(100) NOT NULL,
+    processing_fee_account VARCHAR(100) NOT NULL,
+    tax_on_fees_account VARCHAR(100) NOT NULL,
+    clearing_account VARCHAR(100) NOT NULL,
+    rounding_account VARCHAR(100) NOT NULL,
+    xero_tax_rate VARCHAR(100) NOT NULL DEFAULT 'Tax Exempt',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- History of journal exports (QuickBooks, Xero) to prevent double-exporting a period
+CREATE TABLE IF NOT EXISTS accounting_export_runs (
+    id UUID PRIMARY KEY,
+    app_id UUID NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    format VARCHAR(30) NOT NULL,
+    granularity VARCHAR(10) NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    journal_count INT NOT NULL DEFAULT 0,
+    transaction_count INT NOT NULL DEFAULT 0,
+    total_debit_cents BIGINT NOT NULL DEFAULT 0,
+    confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (period_end >= period_start)
+);
+
+-- Index for overlap checks and history listing
+CREATE INDEX idx_accounting_export_runs_app_period ON accounting_export_runs(app_id, period_start, period_end);
+CREATE INDEX idx_accounting_export_runs_app_created ON accounting_export_runs(app_id, created_at DESC);
+
+COMMENT ON TABLE accounting_export_runs IS 'Journal exports to accounting systems. A period already exported requires confirmation to export again.';
@@ -0,0 +1,18 @@
+-- Tax charged on Shopify fees per partner jurisdiction (GST / VAT / HST), effective-dated
+CREATE TABLE IF NOT EXISTS tax_jurisdiction_profiles (
+    id UUID PRIMARY KEY,
+    partner_account_id UUID NOT NULL REFERENCES partner_accounts(id) ON DELETE CASCADE,
+    jurisdiction VARCHAR(20) NOT NULL,
+    tax_name VARCHAR(50) NOT NULL DEFAULT '',
+    rate NUMERIC(6,5) NOT NULL CHECK (rate >= 0 AND rate < 1),
+    registration_number VARCHAR(100) NOT NULL DEFAULT '',
+    effective_from DATE NOT NULL,
+    effective_to DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+CREATE INDEX idx_tax_jurisdiction_profiles_partner ON tax_jurisdiction_profiles(partner_account_id, effective_from);
+
+COMMENT ON TABLE tax_jurisdiction_profiles IS 'Tax rate applied to Shopify fees for a partner account. effective_to is exclusive; NULL means open-ended.';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_api_sub_status_app_domain_id;
@@ -0,0 +1,2 @@
+-- Keyset pagination index for subscriptionsConnection (default order: domain, id)
+CREATE INDEX idx_api_sub_status_app_domain_id ON api_subscription_status(app_id, myshopify_domain, id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS api_webhook_deliveries;
+DROP TABLE IF EXISTS api_webhook_endpoints;
@@ -0,0 +1,46 @@
+-- Outbound webhook endpoints registered by Revenue API key owners
+CREATE TABLE api_webhook_endpoints (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    encrypted_secret BYTEA NOT NULL,
+    topics TEXT[] NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMPTZ,
+    disabled_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_api_webhook_endpoints_user ON api_webhook_endpoints(user_id);
+
+-- Delivery log: one row per (event, endpoint), retried until succeeded or failed
+CREATE TABLE api_webhook_deliveries (
+    id UUID PRIMARY KEY,
+    endpoint_id UUID NOT NULL REFERENCES api_webhook_endpoints(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    topic VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    last_attempt_at TIMESTAMPTZ,
+    response_status INT,
+    response_body TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    redelivery_of UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_api_webhook_deliveries_endpoint ON api_webhook_deliveries(endpoint_id, created_at DESC);
+
+-- Dispatcher queue: only pending deliveries are polled
+CREATE INDEX idx_api_webhook_deliveries_due ON api_webhook_deliveries(next_attempt_at) WHERE status = 'PENDING';
+
+COMMENT ON TABLE api_webhook_endpoints IS 'Customer webhook endpoints for Revenue API events';
+COMMENT ON COLUMN api_webhook_endpoints.encrypted_secret IS 'HMAC-SHA256 signing secret, encrypted with the master key';
+COMMENT ON COLUMN api_webhook_endpoints.consecutive_failures IS 'Deliveries that exhausted their retries since the last success; the endpoint is disabled at the threshold';
+COMMENT ON TABLE api_webhook_deliveries IS 'Outbound webhook delivery log. status: PENDING, SUCCEEDED, FAILED';
@@ -0,0 +1,13 @@
+-- Per-app entitlement policies for GET /v1/entitlements (apps without one use the built-in default)
+CREATE TABLE IF NOT EXISTS api_entitlement_policies (
+    id UUID PRIMARY KEY,
+    app_id UUID NOT NULL UNIQUE REFERENCES apps(id) ON DELETE CASCADE,
+    rules JSONB NOT NULL DEFAULT '[]',
+    default_decision VARCHAR(20) NOT NULL DEFAULT 'ALLOW',
+    default_reason VARCHAR(100) NOT NULL DEFAULT 'in_good_standing',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (default_decision IN ('ALLOW', 'WARN', 'RESTRICT', 'BLOCK'))
+);
+
+COMMENT ON TABLE api_entitlement_policies IS 'Ordered entitlement rules per app; the first matching rule decides allow / warn / restrict / block';
@@ -0,0 +1,3 @@
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS app_ids,
+    DROP COLUMN IF EXISTS scopes;
@@ -0,0 +1,8 @@
+-- Scopes and app restrictions for least-privilege API keys.
+-- Existing keys keep the access they had: read scopes and the stream, all apps.
+ALTER TABLE api_keys
+    ADD COLUMN scopes TEXT[] NOT NULL DEFAULT ARRAY['subscriptions:read', 'usage:read', 'stream'],
+    ADD COLUMN app_ids UUID[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN api_keys.scopes IS 'Granted scopes: subscriptions:read, usage:read, stream, webhooks:manage';
+COMMENT ON COLUMN api_keys.app_ids IS 'Apps the key may access; empty means all of the owner''s apps';
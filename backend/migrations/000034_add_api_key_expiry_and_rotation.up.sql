@@ -0,0 +1,9 @@
+-- Optional expiry, rotation and last-used tracking for API keys.
+ALTER TABLE api_keys
+    ADD COLUMN expires_at TIMESTAMPTZ,
+    ADD COLUMN last_used_at TIMESTAMPTZ,
+    ADD COLUMN replaced_by UUID REFERENCES api_keys(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN api_keys.expires_at IS 'Key stops authenticating after this time; NULL means it never expires';
+COMMENT ON COLUMN api_keys.last_used_at IS 'Last authenticated request, flushed periodically from the audit logger';
+COMMENT ON COLUMN api_keys.replaced_by IS 'Successor key issued by a rotation; this key expires when the overlap ends';
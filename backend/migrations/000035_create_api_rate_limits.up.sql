@@ -0,0 +1,11 @@
+-- Shared rate limit state so all replicas enforce one quota.
+-- One row per quota key (per API key or per user) holding its GCRA theoretical arrival time.
+CREATE UNLOGGED TABLE api_rate_limits (
+    key TEXT PRIMARY KEY,
+    tat TIMESTAMPTZ NOT NULL
+);
+
+-- Rows whose TAT has passed carry no state and are pruned
+CREATE INDEX idx_api_rate_limits_tat ON api_rate_limits(tat);
+
+COMMENT ON TABLE api_rate_limits IS 'GCRA rate limit state for the Revenue API; unlogged, losing it on crash only resets quotas';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS api_usage_callers_hourly;
+DROP TABLE IF EXISTS api_usage_hourly;
+ALTER TABLE api_audit_log DROP COLUMN IF EXISTS route;
@@ -0,0 +1,32 @@
+-- Route pattern (e.g. /v1/subscriptions/{shopify_gid}) so usage groups by endpoint, not by ID
+ALTER TABLE api_audit_log ADD COLUMN route VARCHAR(255);
+
+-- Hourly request rollups per API key, endpoint and method, built from api_audit_log.
+-- latency_buckets counts requests per response-time bucket (see entity.APIUsageLatencyBuckets)
+-- so percentiles can be merged across hours and endpoints.
+CREATE TABLE api_usage_hourly (
+    api_key_id UUID NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    hour TIMESTAMPTZ NOT NULL,
+    endpoint VARCHAR(255) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    request_count BIGINT NOT NULL,
+    error_count BIGINT NOT NULL,         -- status >= 400
+    server_error_count BIGINT NOT NULL,  -- status >= 500
+    rate_limited_count BIGINT NOT NULL,  -- status = 429
+    total_response_ms BIGINT NOT NULL,
+    latency_buckets BIGINT[] NOT NULL,
+    PRIMARY KEY (api_key_id, hour, endpoint, method)
+);
+
+-- Hourly request counts per API key and client IP, for top callers
+CREATE TABLE api_usage_callers_hourly (
+    api_key_id UUID NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    hour TIMESTAMPTZ NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    request_count BIGINT NOT NULL,
+    error_count BIGINT NOT NULL,
+    PRIMARY KEY (api_key_id, hour, ip_address)
+);
+
+COMMENT ON TABLE api_usage_hourly IS 'Revenue API usage per key/endpoint/hour; recomputed from api_audit_log by the usage rollup worker';
+COMMENT ON TABLE api_usage_callers_hourly IS 'Revenue API requests per key/client IP/hour; recomputed from api_audit_log by the usage rollup worker';
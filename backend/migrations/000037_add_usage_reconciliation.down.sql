@@ -0,0 +1,15 @@
+DELETE FROM api_usage_status WHERE source = 'reported';
+
+DROP INDEX IF EXISTS idx_api_usage_status_reported;
+
+ALTER TABLE api_usage_status
+    DROP COLUMN IF EXISTS billed_amount_cents,
+    DROP COLUMN IF EXISTS billed_transaction_gid,
+    DROP COLUMN IF EXISTS reported_at,
+    DROP COLUMN IF EXISTS source,
+    DROP COLUMN IF EXISTS app_id;
+
+DROP INDEX IF EXISTS idx_transactions_charge_gid;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS charge_gid;
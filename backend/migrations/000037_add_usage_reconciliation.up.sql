@@ -0,0 +1,28 @@
+-- Usage records reported by apps, reconciled against Partner usage transactions.
+-- Reported rows are keyed by the AppUsageRecord GID and stay unbilled until a
+-- usage transaction for the same charge (or subscription and amount) is synced.
+ALTER TABLE transactions
+    ADD COLUMN charge_gid VARCHAR(255);
+
+CREATE INDEX idx_transactions_charge_gid ON transactions(charge_gid) WHERE charge_gid IS NOT NULL;
+
+COMMENT ON COLUMN transactions.charge_gid IS 'Charge behind the sale (chargeId); the AppUsageRecord GID for usage sales';
+
+ALTER TABLE api_usage_status
+    ADD COLUMN app_id UUID REFERENCES apps(id) ON DELETE CASCADE,
+    ADD COLUMN source VARCHAR(20) NOT NULL DEFAULT 'ledger' CHECK (source IN ('ledger', 'reported')),
+    ADD COLUMN reported_at TIMESTAMPTZ,
+    ADD COLUMN billed_transaction_gid VARCHAR(255),
+    ADD COLUMN billed_amount_cents INT;
+
+UPDATE api_usage_status u
+SET app_id = s.app_id
+FROM api_subscription_status s
+WHERE s.shopify_gid = u.subscription_shopify_gid;
+
+CREATE INDEX idx_api_usage_status_reported ON api_usage_status(app_id, reported_at) WHERE source = 'reported';
+
+COMMENT ON COLUMN api_usage_status.source IS 'ledger: derived from a usage transaction; reported: sent by the app via POST /v1/usages';
+COMMENT ON COLUMN api_usage_status.reported_at IS 'When the app created the usage record in Shopify';
+COMMENT ON COLUMN api_usage_status.billed_transaction_gid IS 'Partner usage transaction matched to a reported record';
+COMMENT ON COLUMN api_usage_status.billed_amount_cents IS 'Gross amount of the matched transaction; differs from amount_cents on a mismatch';
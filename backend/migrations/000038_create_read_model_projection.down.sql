@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS api_projection_checkpoints;
+
+DELETE FROM api_subscription_status WHERE status NOT IN ('ACTIVE', 'CANCELLED', 'FROZEN', 'PENDING');
+
+ALTER TABLE api_subscription_status
+    DROP CONSTRAINT IF EXISTS api_subscription_status_status_check,
+    ADD CONSTRAINT api_subscription_status_status_check
+        CHECK (status IN ('ACTIVE', 'CANCELLED', 'FROZEN', 'PENDING'));
+
+ALTER TABLE api_subscription_status
+    ADD COLUMN is_paid_current_cycle BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN months_overdue INT NOT NULL DEFAULT 0 CHECK (months_overdue >= 0);
+
+UPDATE api_subscription_status
+SET is_paid_current_cycle = (status = 'ACTIVE' AND risk_state = 'SAFE'),
+    months_overdue = COALESCE(GREATEST(0, FLOOR(EXTRACT(EPOCH FROM NOW() - expected_next_charge_date) / 2592000))::int, 0);
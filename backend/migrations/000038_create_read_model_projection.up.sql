@@ -0,0 +1,27 @@
+-- Incremental projection of the ledger into the Revenue API read model.
+-- months_overdue and is_paid_current_cycle went stale between syncs; they are
+-- now derived from expected_next_charge_date when the read model is queried.
+ALTER TABLE api_subscription_status
+    DROP COLUMN is_paid_current_cycle,
+    DROP COLUMN months_overdue;
+
+-- The ledger also records EXPIRED and UNINSTALLED subscriptions
+ALTER TABLE api_subscription_status
+    DROP CONSTRAINT IF EXISTS api_subscription_status_status_check,
+    ADD CONSTRAINT api_subscription_status_status_check
+        CHECK (status IN ('ACTIVE', 'CANCELLED', 'FROZEN', 'EXPIRED', 'PENDING', 'UNINSTALLED'));
+
+CREATE TABLE api_projection_checkpoints (
+    app_id UUID PRIMARY KEY REFERENCES apps(id) ON DELETE CASCADE,
+    subscriptions_through TIMESTAMPTZ NOT NULL,
+    transactions_through TIMESTAMPTZ NOT NULL,
+    projected_at TIMESTAMPTZ NOT NULL,
+    rebuilt_at TIMESTAMPTZ,
+    checked_at TIMESTAMPTZ,
+    drift_count INT NOT NULL DEFAULT 0
+);
+
+COMMENT ON TABLE api_projection_checkpoints IS 'How far the ledger of each app has been projected into the Revenue API read model';
+COMMENT ON COLUMN api_projection_checkpoints.subscriptions_through IS 'Latest subscriptions.updated_at projected';
+COMMENT ON COLUMN api_projection_checkpoints.transactions_through IS 'Latest transactions.created_at (first stored) projected';
+COMMENT ON COLUMN api_projection_checkpoints.drift_count IS 'Rows the last consistency check found missing, stale or orphaned';
@@ -0,0 +1,17 @@
+-- Per-app Shopify webhook signing secrets, encrypted at rest. Several secrets can be
+-- active at once so a secret can be rotated without rejecting in-flight webhooks.
+CREATE TABLE IF NOT EXISTS shopify_webhook_secrets (
+    id UUID PRIMARY KEY,
+    app_id UUID NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    label VARCHAR(100) NOT NULL DEFAULT '',
+    encrypted_secret BYTEA NOT NULL,
+    secret_hint VARCHAR(8) NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_shopify_webhook_secrets_app ON shopify_webhook_secrets(app_id, created_at) WHERE revoked_at IS NULL;
+
+COMMENT ON TABLE shopify_webhook_secrets IS 'Secrets used to verify X-Shopify-Hmac-Sha256. A secret is active until revoked_at or expires_at; NULL expires_at means no expiry.';
@@ -0,0 +1,32 @@
+-- Inbound Shopify webhook deliveries. Every verified delivery is stored before it is
+-- acknowledged and processed by a background worker; webhook_id deduplicates retries.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY,
+    webhook_id VARCHAR(255) NOT NULL UNIQUE,
+    topic VARCHAR(100) NOT NULL,
+    shop_domain VARCHAR(255) NOT NULL DEFAULT '',
+    app_gid VARCHAR(255) NOT NULL DEFAULT '',
+    resource_key VARCHAR(255) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    triggered_at TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'PROCESSED', 'SKIPPED', 'FAILED')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    locked_until TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMPTZ
+);
+
+-- Worker queue: only pending deliveries are polled
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'PENDING';
+
+-- Out-of-order check: newest processed event per resource
+CREATE INDEX idx_webhook_deliveries_resource ON webhook_deliveries(topic, resource_key, triggered_at) WHERE status = 'PROCESSED';
+
+CREATE INDEX idx_webhook_deliveries_app ON webhook_deliveries(app_gid, received_at DESC);
+
+COMMENT ON TABLE webhook_deliveries IS 'Inbound Shopify webhook log. status: PENDING, PROCESSED, SKIPPED (older than an already processed event for the same resource), FAILED';
+COMMENT ON COLUMN webhook_deliveries.triggered_at IS 'X-Shopify-Triggered-At (received_at when missing); orders events for the same resource';
+COMMENT ON COLUMN webhook_deliveries.locked_until IS 'Lease held by the processing worker or a replay';
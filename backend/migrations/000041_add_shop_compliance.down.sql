@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS compliance_requests;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS redacted_at;
@@ -0,0 +1,29 @@
+-- Shopify mandatory compliance webhooks (customers/data_request, customers/redact,
+-- shop/redact). A redacted shop keeps its revenue in the ledger: its transactions are
+-- anonymized rather than deleted, and redacted_at stops a later sync from restoring
+-- the shop's identity.
+ALTER TABLE transactions
+    ADD COLUMN redacted_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN transactions.redacted_at IS 'Set when shop/redact anonymized the shop; shop_name, shopify_shop_gid and shop_plan are no longer synced';
+
+CREATE TABLE IF NOT EXISTS compliance_requests (
+    id UUID PRIMARY KEY,
+    topic VARCHAR(100) NOT NULL,
+    app_gid VARCHAR(255) NOT NULL DEFAULT '',
+    shop_id BIGINT NOT NULL DEFAULT 0,
+    shop_domain VARCHAR(255) NOT NULL,
+    customer_id BIGINT,
+    data_request_id BIGINT,
+    transactions_redacted INT NOT NULL DEFAULT 0,
+    subscriptions_deleted INT NOT NULL DEFAULT 0,
+    events_deleted INT NOT NULL DEFAULT 0,
+    result TEXT NOT NULL DEFAULT '',
+    requested_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_compliance_requests_shop ON compliance_requests(shop_domain, requested_at DESC);
+
+COMMENT ON TABLE compliance_requests IS 'Record of every handled Shopify compliance webhook. Customer contact details are never stored.';
+COMMENT ON COLUMN compliance_requests.result IS 'What was done, e.g. that no customer data is held';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS app_sync_status;
+DROP TABLE IF EXISTS alerts;
+DROP TABLE IF EXISTS alert_rules;
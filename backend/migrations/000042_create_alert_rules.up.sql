@@ -0,0 +1,55 @@
+-- User-defined alert rules, evaluated after every sync, and the alerts they trigger
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id UUID NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    rule_type VARCHAR(30) NOT NULL
+        CHECK (rule_type IN ('MRR_DROP', 'SHOP_AT_RISK', 'USAGE_REVENUE_ZERO', 'SYNC_FAILING')),
+    threshold DOUBLE PRECISION NOT NULL CHECK (threshold > 0),
+    risk_state VARCHAR(30) NOT NULL DEFAULT '',
+    severity VARCHAR(20) NOT NULL DEFAULT 'WARNING' CHECK (severity IN ('INFO', 'WARNING', 'CRITICAL')),
+    channels TEXT[] NOT NULL,
+    cooldown_minutes INT NOT NULL DEFAULT 1440 CHECK (cooldown_minutes >= 0),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_alert_rules_app ON alert_rules(app_id) WHERE enabled;
+CREATE INDEX idx_alert_rules_user ON alert_rules(user_id, app_id);
+
+CREATE TABLE IF NOT EXISTS alerts (
+    id UUID PRIMARY KEY,
+    rule_id UUID NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id UUID NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    alert_key VARCHAR(255) NOT NULL DEFAULT '',
+    severity VARCHAR(20) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'ACKNOWLEDGED', 'RESOLVED')),
+    triggered_at TIMESTAMPTZ NOT NULL,
+    acknowledged_at TIMESTAMPTZ,
+    resolved_at TIMESTAMPTZ,
+    auto_resolved BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_alerts_user_app ON alerts(user_id, app_id, triggered_at DESC);
+
+-- Cooldown and still-firing checks: latest alert per rule and key
+CREATE INDEX idx_alerts_rule_key ON alerts(rule_id, alert_key, triggered_at DESC);
+
+-- Outcome of each app's Partner API syncs (SYNC_FAILING rules)
+CREATE TABLE IF NOT EXISTS app_sync_status (
+    app_id UUID PRIMARY KEY REFERENCES apps(id) ON DELETE CASCADE,
+    last_attempt_at TIMESTAMPTZ NOT NULL,
+    last_success_at TIMESTAMPTZ,
+    failing_since TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    consecutive_failures INT NOT NULL DEFAULT 0
+);
+
+COMMENT ON COLUMN alert_rules.threshold IS 'MRR_DROP: percent; SHOP_AT_RISK: minimum shop MRR in cents; USAGE_REVENUE_ZERO: days; SYNC_FAILING: hours';
+COMMENT ON COLUMN alerts.alert_key IS 'What the rule fired for: shop domain for SHOP_AT_RISK, empty for app-wide rules';
+COMMENT ON COLUMN app_sync_status.failing_since IS 'First failed sync since the last success; NULL while syncs succeed';
@@ -0,0 +1,47 @@
+-- Notification outbox. A notification is stored in the same transaction as the
+-- event that causes it and delivered by a background worker with retries.
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id UUID REFERENCES apps(id) ON DELETE CASCADE,
+    kind VARCHAR(30) NOT NULL,
+    source_id UUID,
+    severity VARCHAR(20) NOT NULL DEFAULT 'INFO',
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    channels TEXT[] NOT NULL,
+    pending_channels TEXT[] NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    locked_until TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    read_at TIMESTAMPTZ
+);
+
+-- Worker queue: only pending notifications are polled
+CREATE INDEX idx_notifications_due ON notifications(next_attempt_at) WHERE status = 'PENDING';
+
+-- Notification history and unread count
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- One row per delivery attempt per channel
+CREATE TABLE IF NOT EXISTS notification_attempts (
+    id UUID PRIMARY KEY,
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    attempt INT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('SENT', 'FAILED', 'SKIPPED')),
+    error TEXT NOT NULL DEFAULT '',
+    attempted_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_notification_attempts_notification ON notification_attempts(notification_id, attempted_at);
+
+COMMENT ON COLUMN notifications.pending_channels IS 'Channels not yet delivered; a retry only sends to these';
+COMMENT ON COLUMN notifications.locked_until IS 'Lease held by the delivery worker';
+COMMENT ON COLUMN notification_attempts.status IS 'SENT, FAILED, or SKIPPED when there was nothing to send to (no devices, no webhook)';
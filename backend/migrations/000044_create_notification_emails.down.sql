@@ -0,0 +1,2 @@
+ALTER TABLE notification_preferences DROP COLUMN IF EXISTS weekly_digest_enabled;
+DROP TABLE IF EXISTS notification_emails;
@@ -0,0 +1,24 @@
+-- Addresses a user receives notification emails at. Email is only sent to
+-- verified addresses that have not unsubscribed.
+CREATE TABLE IF NOT EXISTS notification_emails (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    address VARCHAR(320) NOT NULL,
+    verification_token_hash VARCHAR(64),
+    verification_sent_at TIMESTAMPTZ NOT NULL,
+    verified_at TIMESTAMPTZ,
+    unsubscribe_token_hash VARCHAR(64) NOT NULL,
+    unsubscribed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, address)
+);
+
+CREATE UNIQUE INDEX idx_notification_emails_verification_token_hash ON notification_emails(verification_token_hash)
+    WHERE verification_token_hash IS NOT NULL;
+CREATE UNIQUE INDEX idx_notification_emails_unsubscribe_token_hash ON notification_emails(unsubscribe_token_hash);
+
+-- Weekly digest opt-out, alongside the daily summary setting
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS weekly_digest_enabled BOOLEAN NOT NULL DEFAULT TRUE;
+
+COMMENT ON COLUMN notification_emails.verification_token_hash IS 'SHA-256 of the token in the verification link; NULL once verified';
+COMMENT ON COLUMN notification_emails.unsubscribe_token_hash IS 'SHA-256 of the token in every email''s unsubscribe link';
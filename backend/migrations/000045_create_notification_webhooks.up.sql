@@ -0,0 +1,20 @@
+-- Slack, Microsoft Teams, Discord and generic webhooks a user receives
+-- notifications on. Every enabled webhook of a channel gets its notifications.
+CREATE TABLE IF NOT EXISTS notification_webhooks (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('SLACK', 'TEAMS', 'DISCORD', 'WEBHOOK')),
+    name VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL,
+    encrypted_secret BYTEA,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_tested_at TIMESTAMPTZ,
+    last_test_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_notification_webhooks_user_id ON notification_webhooks(user_id);
+
+COMMENT ON COLUMN notification_webhooks.encrypted_secret IS 'HMAC-SHA256 signing secret, encrypted with the master key; WEBHOOK channel only';
+COMMENT ON COLUMN notification_webhooks.last_test_error IS 'NULL if the last test notification was delivered';
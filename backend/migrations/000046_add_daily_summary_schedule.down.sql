@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS daily_summary_sends;
+
+DROP INDEX IF EXISTS idx_notification_preferences_next_daily_summary;
+
+ALTER TABLE notification_preferences
+    DROP COLUMN IF EXISTS daily_summary_locked_until,
+    DROP COLUMN IF EXISTS next_daily_summary_at,
+    DROP COLUMN IF EXISTS skip_weekends,
+    DROP COLUMN IF EXISTS quiet_hours_end,
+    DROP COLUMN IF EXISTS quiet_hours_start,
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,26 @@
+-- Daily summary scheduling: the user's time zone, quiet hours and weekend
+-- skipping, and the next send time computed by the scheduler
+ALTER TABLE notification_preferences
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    ADD COLUMN IF NOT EXISTS quiet_hours_start TIME,
+    ADD COLUMN IF NOT EXISTS quiet_hours_end TIME,
+    ADD COLUMN IF NOT EXISTS skip_weekends BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS next_daily_summary_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS daily_summary_locked_until TIMESTAMPTZ;
+
+CREATE INDEX idx_notification_preferences_next_daily_summary ON notification_preferences(next_daily_summary_at)
+    WHERE daily_summary_enabled = TRUE;
+
+-- One row per user per local day a daily summary was queued, written in the
+-- same transaction as the notification so each day is sent exactly once
+CREATE TABLE IF NOT EXISTS daily_summary_sends (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    summary_date DATE NOT NULL,
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, summary_date)
+);
+
+COMMENT ON COLUMN notification_preferences.next_daily_summary_at IS 'Next daily summary send time; NULL until the scheduler computes it, reset when preferences change';
+COMMENT ON COLUMN notification_preferences.daily_summary_locked_until IS 'Scheduler lease, so one replica handles each user';
+COMMENT ON COLUMN daily_summary_sends.summary_date IS 'Date in the user''s time zone the summary was sent on';
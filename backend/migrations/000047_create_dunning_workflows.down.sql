@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS shop_contacts;
+DROP TABLE IF EXISTS dunning_tasks;
+DROP TABLE IF EXISTS dunning_runs;
+DROP TABLE IF EXISTS dunning_sequences;
@@ -0,0 +1,81 @@
+-- Dunning and win-back sequences, started for a shop when its subscription enters a risk state
+CREATE TABLE IF NOT EXISTS dunning_sequences (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id UUID NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    trigger_risk_state VARCHAR(30) NOT NULL
+        CHECK (trigger_risk_state IN ('ONE_CYCLE_MISSED', 'TWO_CYCLES_MISSED', 'CHURNED')),
+    steps JSONB NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_dunning_sequences_trigger ON dunning_sequences(app_id, trigger_risk_state) WHERE enabled;
+CREATE INDEX idx_dunning_sequences_user ON dunning_sequences(user_id, app_id);
+
+CREATE TABLE IF NOT EXISTS dunning_runs (
+    id UUID PRIMARY KEY,
+    sequence_id UUID NOT NULL REFERENCES dunning_sequences(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id UUID NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    shop_domain VARCHAR(255) NOT NULL,
+    shop_name VARCHAR(255) NOT NULL DEFAULT '',
+    trigger_risk_state VARCHAR(30) NOT NULL,
+    mrr_cents BIGINT NOT NULL DEFAULT 0,
+    steps JSONB NOT NULL,
+    results JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE'
+        CHECK (status IN ('ACTIVE', 'RECOVERED', 'EXHAUSTED', 'CANCELLED')),
+    next_step INT NOT NULL DEFAULT 0,
+    next_step_at TIMESTAMPTZ,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    locked_until TIMESTAMPTZ,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    recovered_at TIMESTAMPTZ
+);
+
+-- At most one active run per sequence and subscription
+CREATE UNIQUE INDEX idx_dunning_runs_active ON dunning_runs(sequence_id, subscription_id) WHERE status = 'ACTIVE';
+
+-- Worker: due active runs
+CREATE INDEX idx_dunning_runs_due ON dunning_runs(next_step_at) WHERE status = 'ACTIVE';
+
+CREATE INDEX idx_dunning_runs_subscription ON dunning_runs(subscription_id, status);
+CREATE INDEX idx_dunning_runs_user_app ON dunning_runs(user_id, app_id, started_at DESC);
+
+CREATE TABLE IF NOT EXISTS dunning_tasks (
+    id UUID PRIMARY KEY,
+    run_id UUID NOT NULL REFERENCES dunning_runs(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id UUID NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    shop_domain VARCHAR(255) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    assignee VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'DONE', 'CANCELLED')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_dunning_tasks_user_app ON dunning_tasks(user_id, app_id, created_at DESC);
+CREATE INDEX idx_dunning_tasks_run ON dunning_tasks(run_id) WHERE status = 'OPEN';
+
+-- Who to email about a shop's subscription (merchant email steps)
+CREATE TABLE IF NOT EXISTS shop_contacts (
+    app_id UUID NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    myshopify_domain VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (app_id, myshopify_domain)
+);
+
+COMMENT ON COLUMN dunning_sequences.steps IS 'Array of {day, action, subject, body, assignee}; day 1 is the day the run starts';
+COMMENT ON COLUMN dunning_runs.steps IS 'Copy of the sequence steps when the run started';
+COMMENT ON COLUMN dunning_runs.results IS 'Array of {step, action, status, detail, executed_at}, one per executed step';
+COMMENT ON COLUMN dunning_runs.mrr_cents IS 'Subscription MRR when the run started, the revenue at stake';
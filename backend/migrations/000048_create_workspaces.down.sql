@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_partner_accounts_workspace;
+ALTER TABLE partner_accounts DROP COLUMN IF EXISTS workspace_id;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('OWNER', 'ADMIN'));
+
+DROP TABLE IF EXISTS workspace_invitations;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
@@ -0,0 +1,69 @@
+-- Team workspaces. A workspace owns a partner account (and through it, the
+-- apps); users reach it through a membership with a role.
+CREATE TABLE IF NOT EXISTS workspaces (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    role VARCHAR(20) NOT NULL CHECK (role IN ('OWNER', 'ADMIN', 'ANALYST', 'VIEWER')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX idx_workspace_members_user ON workspace_members(user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS workspace_invitations (
+    id UUID PRIMARY KEY,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('OWNER', 'ADMIN', 'ANALYST', 'VIEWER')),
+    token_hash VARCHAR(64) UNIQUE, -- SHA-256 of the invitation token; NULL once accepted or revoked
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    accepted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_workspace_invitations_workspace ON workspace_invitations(workspace_id, created_at DESC);
+
+-- Partner accounts now belong to a workspace; user_id records who connected it
+ALTER TABLE partner_accounts
+    ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_partner_accounts_workspace ON partner_accounts(workspace_id);
+
+-- Users keep their account-level role; workspace roles live on memberships
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('OWNER', 'ADMIN', 'ANALYST', 'VIEWER'));
+
+-- Backfill: every existing user gets a personal workspace, reusing the user's
+-- ID, that owns the partner account they connected
+INSERT INTO workspaces (id, name, created_by, created_at, updated_at)
+SELECT id, 'Personal workspace', id, created_at, created_at
+FROM users
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO workspace_members (workspace_id, user_id, email, role, created_at, updated_at)
+SELECT id, id, LOWER(email), 'OWNER', created_at, created_at
+FROM users
+ON CONFLICT (workspace_id, user_id) DO NOTHING;
+
+UPDATE partner_accounts pa
+SET workspace_id = pa.user_id
+WHERE pa.workspace_id IS NULL
+  AND pa.id = (
+      SELECT first.id FROM partner_accounts first
+      WHERE first.user_id = pa.user_id
+      ORDER BY first.created_at, first.id
+      LIMIT 1
+  );
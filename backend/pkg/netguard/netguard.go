@@ -0,0 +1,164 @@
+// Package netguard keeps requests to user-supplied URLs (outbound webhooks)
+// away from internal networks. URLs are checked when they are saved, and every
+// connection is checked again at dial time against the resolved address, so a
+// hostname that later resolves to a private IP (DNS rebinding) is still refused.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrInvalidURL is returned for URLs that are not absolute https URLs to a public host
+	ErrInvalidURL = errors.New("url must be an absolute https URL to a public host")
+
+	// ErrDisallowedAddress is returned when a connection would reach a non-public address
+	ErrDisallowedAddress = errors.New("destination address is not allowed")
+)
+
+// blockedNetworks are ranges not covered by the net.IP predicates that must not be reachable
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",      // "this" network
+	"100.64.0.0/10",  // carrier-grade NAT
+	"192.0.0.0/24",   // IETF protocol assignments
+	"198.18.0.0/15",  // benchmarking
+	"240.0.0.0/4",    // reserved
+	"64:ff9b::/96",   // NAT64, can embed any IPv4 address
+	"64:ff9b:1::/48", // local-use NAT64
+	"2001:db8::/32",  // documentation
+	"2002::/16",      // 6to4, can embed any IPv4 address
+)
+
+// Guard validates outbound URLs and dials only public addresses.
+// Loopback targets (localhost) are refused unless allowLoopback is set, which is
+// meant for local development only.
+type Guard struct {
+	allowLoopback bool
+}
+
+// New creates a Guard. allowLoopback permits http and https requests to localhost.
+func New(allowLoopback bool) *Guard {
+	return &Guard{allowLoopback: allowLoopback}
+}
+
+// ValidateURL checks a URL before it is saved: https to a host that is not a
+// literal non-public IP or localhost. Plain http is only accepted for loopback
+// hosts when loopback is allowed.
+func (g *Guard) ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil {
+		return ErrInvalidURL
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return ErrInvalidURL
+	}
+
+	loopback := isLoopbackHost(host)
+	if loopback && !g.allowLoopback {
+		return ErrInvalidURL
+	}
+	if ip := net.ParseIP(host); ip != nil && !loopback && !IsPublicIP(ip) {
+		return ErrInvalidURL
+	}
+
+	switch u.Scheme {
+	case "https":
+		return nil
+	case "http":
+		if loopback {
+			return nil
+		}
+	}
+	return ErrInvalidURL
+}
+
+// IsPublicIP returns true if ip is a globally routable unicast address
+func IsPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// DialContext dials like net.Dialer but refuses connections to non-public
+// addresses. The check runs on the resolved IP just before connecting.
+func (g *Guard) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   g.control,
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// HTTPClient returns a client that dials through the guard, ignores proxy
+// settings (a proxy would dial on our behalf) and does not follow redirects,
+// so a public URL cannot bounce the request to an internal one
+func (g *Guard) HTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = g.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func (g *Guard) control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrDisallowedAddress, address)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: %s", ErrDisallowedAddress, address)
+	}
+	if IsPublicIP(ip) || (g.allowLoopback && ip.IsLoopback()) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrDisallowedAddress, ip)
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
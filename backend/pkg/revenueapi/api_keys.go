@@ -0,0 +1,86 @@
+package revenueapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// API key management authenticates with the session token of an account owner
+// (see WithSessionToken), not with an API key.
+
+// ListAPIKeys returns the account's active API keys
+func (c *Client) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var resp struct {
+		APIKeys []APIKey `json:"api_keys"`
+	}
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api-keys", auth: authSession}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.APIKeys, nil
+}
+
+// CreateAPIKey creates an API key. The full key is only returned once.
+func (c *Client) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (*CreatedAPIKey, error) {
+	var created CreatedAPIKey
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api-keys", body: req, auth: authSession}, &created)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// RevokeAPIKey revokes an API key
+func (c *Client) RevokeAPIKey(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api-keys/" + url.PathEscape(id), auth: authSession}, nil)
+}
+
+// RotateAPIKey issues a successor key; the old key keeps working for the overlap window
+func (c *Client) RotateAPIKey(ctx context.Context, id string, req RotateAPIKeyRequest) (*RotatedAPIKey, error) {
+	var rotated RotatedAPIKey
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/api-keys/" + url.PathEscape(id) + "/rotate",
+		body:   req,
+		auth:   authSession,
+	}, &rotated)
+	if err != nil {
+		return nil, err
+	}
+	return &rotated, nil
+}
+
+// APIKeyUsageParams selects the range of an API key usage report. Zero values are not sent.
+type APIKeyUsageParams struct {
+	From       time.Time
+	To         time.Time
+	TopCallers int
+}
+
+// GetAPIKeyUsage returns request volume, error rates, latency percentiles and top callers of a key
+func (c *Client) GetAPIKeyUsage(ctx context.Context, id string, params APIKeyUsageParams) (*APIKeyUsageReport, error) {
+	q := url.Values{}
+	if !params.From.IsZero() {
+		q.Set("from", params.From.Format(time.RFC3339))
+	}
+	if !params.To.IsZero() {
+		q.Set("to", params.To.Format(time.RFC3339))
+	}
+	if params.TopCallers > 0 {
+		q.Set("top_callers", strconv.Itoa(params.TopCallers))
+	}
+
+	var report APIKeyUsageReport
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/api-keys/" + url.PathEscape(id) + "/usage",
+		query:  q,
+		auth:   authSession,
+	}, &report)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
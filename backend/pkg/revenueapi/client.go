@@ -0,0 +1,304 @@
+// Package revenueapi is a Go client for the LedgerGuard Revenue API.
+//
+// The request and response types follow the OpenAPI document served at
+// /v1/openapi.json. Rate-limited requests are retried after the Retry-After
+// delay, batch lookups are split into requests of at most 100 IDs and
+// subscription listings can be iterated across pages.
+package revenueapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the production API including its version prefix
+	DefaultBaseURL = "https://api.ledgerguard.app/v1"
+
+	// MaxBatchSize is the most IDs or records the API accepts per request
+	MaxBatchSize = 100
+
+	defaultMaxRetries   = 3
+	defaultMaxRetryWait = time.Minute
+	defaultTimeout      = 30 * time.Second
+)
+
+// ErrSessionTokenRequired is returned by API key management calls on a client without a session token
+var ErrSessionTokenRequired = errors.New("revenueapi: API key management requires a session token")
+
+// APIError is a non-2xx response of the API
+type APIError struct {
+	StatusCode int
+	Code       string        // HTTP status text, e.g. Not Found
+	Message    string        // e.g. subscription not found
+	RetryAfter time.Duration // Set on 429 responses
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("revenueapi: %d %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("revenueapi: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// IsNotFound returns true if err is a 404 response
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsRateLimited returns true if err is a 429 response that was not retried further
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// Client calls the Revenue API. It is safe for concurrent use.
+type Client struct {
+	baseURL      string
+	apiKey       string
+	sessionToken string
+	httpClient   *http.Client
+	userAgent    string
+	maxRetries   int
+	maxRetryWait time.Duration
+	sleep        func(ctx context.Context, d time.Duration) error
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL sets the API base URL including the version prefix, e.g. https://api-sandbox.ledgerguard.app/v1
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithSessionToken sets the dashboard session ID token of an account owner,
+// which API key management calls authenticate with instead of the API key
+func WithSessionToken(token string) Option {
+	return func(c *Client) {
+		c.sessionToken = token
+	}
+}
+
+// WithMaxRetries sets how often a rate-limited request is retried; 0 disables retries
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		c.maxRetries = n
+	}
+}
+
+// WithMaxRetryWait sets the longest Retry-After the client waits for. Longer
+// delays are returned as an APIError instead.
+func WithMaxRetryWait(d time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetryWait = d
+	}
+}
+
+// WithUserAgent sets the User-Agent header
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New creates a client authenticating with an API key. The key may be empty
+// for a client that only manages API keys with WithSessionToken.
+func New(apiKey string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:      DefaultBaseURL,
+		apiKey:       apiKey,
+		httpClient:   &http.Client{Timeout: defaultTimeout},
+		userAgent:    "ledgerguard-revenueapi-go",
+		maxRetries:   defaultMaxRetries,
+		maxRetryWait: defaultMaxRetryWait,
+		sleep:        sleepContext,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// authKind is the credential a request is sent with
+type authKind int
+
+const (
+	authAPIKey authKind = iota
+	authSession
+)
+
+// request describes one API call
+type request struct {
+	method string
+	path   string // Relative to the base URL, already escaped
+	query  url.Values
+	body   interface{}
+	auth   authKind
+	accept []int // Non-2xx statuses whose body decodes into out instead of failing
+}
+
+// do sends a request, retrying 429 responses, and decodes a JSON body into out
+func (c *Client) do(ctx context.Context, req request, out interface{}) error {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("revenueapi: failed to encode request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req, body)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			apiErr := readAPIError(resp)
+			apiErr.RetryAfter = retryAfter(resp.Header.Get("Retry-After"), attempt, time.Now())
+			if attempt >= c.maxRetries || apiErr.RetryAfter > c.maxRetryWait {
+				return apiErr
+			}
+			if err := c.sleep(ctx, apiErr.RetryAfter); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if (resp.StatusCode < 200 || resp.StatusCode > 299) && !containsStatus(req.accept, resp.StatusCode) {
+			return readAPIError(resp)
+		}
+		defer resp.Body.Close()
+		if out != nil && resp.StatusCode != http.StatusNoContent {
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return fmt.Errorf("revenueapi: failed to decode response: %w", err)
+			}
+		}
+		return nil
+	}
+}
+
+// send performs a single attempt. The body is re-read from the buffer each time.
+func (c *Client) send(ctx context.Context, req request, body []byte) (*http.Response, error) {
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("revenueapi: failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	switch req.auth {
+	case authSession:
+		if c.sessionToken == "" {
+			return nil, ErrSessionTokenRequired
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+c.sessionToken)
+	default:
+		httpReq.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	return c.httpClient.Do(httpReq)
+}
+
+// readAPIError consumes and closes the body of a failed response
+func readAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+
+	apiErr := &APIError{StatusCode: resp.StatusCode, Code: http.StatusText(resp.StatusCode)}
+	var envelope struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&envelope); err == nil {
+		if envelope.Error.Code != "" {
+			apiErr.Code = envelope.Error.Code
+		}
+		apiErr.Message = envelope.Error.Message
+	}
+	return apiErr
+}
+
+// retryAfter parses a Retry-After header in seconds or as an HTTP date, falling
+// back to exponential backoff from one second when it is missing
+func retryAfter(header string, attempt int, now time.Time) time.Duration {
+	if header != "" {
+		if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(header); err == nil {
+			if d := at.Sub(now); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+	return time.Second << attempt
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func containsStatus(statuses []int, status int) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// chunk splits items into slices of at most MaxBatchSize
+func chunk[T any](items []T) [][]T {
+	var chunks [][]T
+	for len(items) > MaxBatchSize {
+		chunks = append(chunks, items[:MaxBatchSize])
+		items = items[MaxBatchSize:]
+	}
+	if len(items) > 0 {
+		chunks = append(chunks, items)
+	}
+	return chunks
+}
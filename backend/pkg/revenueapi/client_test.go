@@ -0,0 +1,266 @@
+package revenueapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client for server that records retry waits instead of sleeping
+func newTestClient(server *httptest.Server, opts ...Option) (*Client, *[]time.Duration) {
+	var waits []time.Duration
+	c := New("lgk_test", append([]Option{WithBaseURL(server.URL + "/v1")}, opts...)...)
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return c, &waits
+}
+
+func writeTestError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"error":{"code":%q,"message":%q}}`, http.StatusText(status), message)
+}
+
+func TestClient_RetriesRateLimitedRequests(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		var req struct {
+			IDs []string `json:"ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.IDs) != 1 {
+			t.Errorf("attempt %d: expected the request body to be resent, got %v (%v)", attempts, req.IDs, err)
+		}
+		if r.Header.Get("X-API-Key") != "lgk_test" {
+			t.Errorf("expected API key header, got %q", r.Header.Get("X-API-Key"))
+		}
+
+		switch attempts {
+		case 1:
+			w.Header().Set("Retry-After", "2")
+			writeTestError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		case 2:
+			w.Header().Set("Retry-After", time.Now().Add(5*time.Second).UTC().Format(http.TimeFormat))
+			writeTestError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		default:
+			json.NewEncoder(w).Encode(SubscriptionStatusBatch{Results: []SubscriptionStatus{{SubscriptionID: req.IDs[0]}}, NotFound: []string{}})
+		}
+	}))
+	defer server.Close()
+
+	client, waits := newTestClient(server)
+	batch, err := client.GetSubscriptions(context.Background(), []string{"gid://shopify/AppSubscription/1"})
+	if err != nil {
+		t.Fatalf("GetSubscriptions: %v", err)
+	}
+	if len(batch.Results) != 1 || attempts != 3 {
+		t.Fatalf("expected 1 result after 3 attempts, got %d after %d", len(batch.Results), attempts)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 2*time.Second || (*waits)[1] < 3*time.Second || (*waits)[1] > 5*time.Second {
+		t.Errorf("expected waits honoring Retry-After seconds and date, got %v", *waits)
+	}
+}
+
+func TestClient_GivesUpOnRateLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		options    []Option
+		attempts   int
+	}{
+		{"retries exhausted", "1", []Option{WithMaxRetries(2)}, 3},
+		{"retries disabled", "1", []Option{WithMaxRetries(0)}, 1},
+		{"wait too long", "120", nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.Header().Set("Retry-After", tt.retryAfter)
+				writeTestError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			}))
+			defer server.Close()
+
+			client, _ := newTestClient(server, tt.options...)
+			_, err := client.GetSubscription(context.Background(), "gid://shopify/AppSubscription/1")
+
+			var apiErr *APIError
+			if !IsRateLimited(err) || !errors.As(err, &apiErr) || apiErr.Message != "rate limit exceeded" {
+				t.Fatalf("expected rate limit error, got %v", err)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("expected %d attempts, got %d", tt.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestClient_RetryStopsWhenContextDone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		writeTestError(w, http.StatusTooManyRequests, "rate limit exceeded")
+	}))
+	defer server.Close()
+
+	client := New("lgk_test", WithBaseURL(server.URL+"/v1"))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.GetSubscription(ctx, "gid://shopify/AppSubscription/1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context deadline, got %v", err)
+	}
+}
+
+func TestClient_BatchesLookups(t *testing.T) {
+	var batchSizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			IDs []string `json:"ids"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		batchSizes = append(batchSizes, len(req.IDs))
+
+		resp := UsageStatusBatch{Results: []UsageStatus{}, NotFound: []string{}}
+		for _, id := range req.IDs {
+			if id == "missing" {
+				resp.NotFound = append(resp.NotFound, id)
+			} else {
+				resp.Results = append(resp.Results, UsageStatus{UsageID: id})
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	ids := make([]string, 250)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("gid://shopify/AppUsageRecord/%d", i)
+	}
+	ids[249] = "missing"
+
+	client, _ := newTestClient(server)
+	batch, err := client.GetUsages(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("GetUsages: %v", err)
+	}
+	if fmt.Sprint(batchSizes) != "[100 100 50]" {
+		t.Errorf("expected batches of 100, 100 and 50, got %v", batchSizes)
+	}
+	if len(batch.Results) != 249 || len(batch.NotFound) != 1 || batch.Results[248].UsageID != ids[248] {
+		t.Errorf("expected merged results in order, got %d results and %v", len(batch.Results), batch.NotFound)
+	}
+}
+
+func TestClient_ReportUsageReturnsRejections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/usages" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(UsageReport{
+			Accepted: []UsageStatus{},
+			Rejected: []UsageReportRejection{{UsageID: "gid://shopify/AppUsageRecord/1", Error: "unknown subscription"}},
+		})
+	}))
+	defer server.Close()
+
+	client, _ := newTestClient(server)
+	report, err := client.ReportUsage(context.Background(), []ReportedUsage{{
+		UsageID:        "gid://shopify/AppUsageRecord/1",
+		SubscriptionID: "gid://shopify/AppSubscription/1",
+		AmountCents:    500,
+		CreatedAt:      time.Now(),
+	}})
+	if err != nil {
+		t.Fatalf("ReportUsage: %v", err)
+	}
+	if len(report.Rejected) != 1 || report.Rejected[0].Error != "unknown subscription" {
+		t.Errorf("expected the rejection, got %+v", report)
+	}
+}
+
+func TestClient_ListAllSubscriptionsFollowsCursors(t *testing.T) {
+	pages := map[string]SubscriptionStatusList{
+		"":   {Data: []SubscriptionStatus{{SubscriptionID: "1"}, {SubscriptionID: "2"}}, NextCursor: "c2", HasMore: true, TotalCount: 3},
+		"c2": {Data: []SubscriptionStatus{{SubscriptionID: "3"}}, TotalCount: 3},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("risk_state") != RiskStateChurned || q.Get("direction") != "desc" || q.Get("limit") != "2" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		page, ok := pages[q.Get("after")]
+		if !ok {
+			writeTestError(w, http.StatusBadRequest, "invalid pagination cursor")
+			return
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client, _ := newTestClient(server)
+	it := client.ListAllSubscriptions(context.Background(), ListSubscriptionsParams{
+		RiskState:  RiskStateChurned,
+		Descending: true,
+		Limit:      2,
+	})
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Subscription().SubscriptionID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iteration failed: %v", err)
+	}
+	if fmt.Sprint(ids) != "[1 2 3]" {
+		t.Errorf("expected all three subscriptions, got %v", ids)
+	}
+}
+
+func TestClient_ErrorsAndAuthentication(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/api-keys":
+			if r.Header.Get("Authorization") != "Bearer session-token" || r.Header.Get("X-API-Key") != "" {
+				writeTestError(w, http.StatusUnauthorized, "authentication required")
+				return
+			}
+			w.Write([]byte(`{"api_keys":[{"id":"k1","created_at":"2026-01-02T03:04:05Z","scopes":["stream"]}]}`))
+		default:
+			if r.URL.EscapedPath() != "/v1/subscriptions/gid:%2F%2Fshopify%2FAppSubscription%2F404" {
+				t.Errorf("expected the GID to be escaped, got %s", r.URL.EscapedPath())
+			}
+			writeTestError(w, http.StatusNotFound, "subscription not found")
+		}
+	}))
+	defer server.Close()
+
+	client, _ := newTestClient(server)
+	_, err := client.GetSubscription(context.Background(), "gid://shopify/AppSubscription/404")
+	if !IsNotFound(err) || err.Error() != "revenueapi: 404 Not Found: subscription not found" {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	if _, err := client.ListAPIKeys(context.Background()); !errors.Is(err, ErrSessionTokenRequired) {
+		t.Errorf("expected ErrSessionTokenRequired, got %v", err)
+	}
+
+	owner, _ := newTestClient(server, WithSessionToken("session-token"))
+	keys, err := owner.ListAPIKeys(context.Background())
+	if err != nil {
+		t.Fatalf("ListAPIKeys: %v", err)
+	}
+	if len(keys) != 1 || keys[0].ID != "k1" || keys[0].CreatedAt.Year() != 2026 {
+		t.Errorf("unexpected keys %+v", keys)
+	}
+}
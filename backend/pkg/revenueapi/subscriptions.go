@@ -0,0 +1,157 @@
+package revenueapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetSubscription returns the status of a subscription by its Shopify GID
+func (c *Client) GetSubscription(ctx context.Context, shopifyGID string) (*SubscriptionStatus, error) {
+	var status SubscriptionStatus
+	err := c.do(ctx, request{method: http.MethodGet, path: "/subscriptions/" + url.PathEscape(shopifyGID)}, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetSubscriptionByDomain returns the status of a shop's subscription by its myshopify domain
+func (c *Client) GetSubscriptionByDomain(ctx context.Context, domain string) (*SubscriptionStatus, error) {
+	var status SubscriptionStatus
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/subscriptions/status",
+		query:  url.Values{"domain": {domain}},
+	}, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetSubscriptions looks up any number of subscriptions by Shopify GID, in
+// requests of at most MaxBatchSize IDs
+func (c *Client) GetSubscriptions(ctx context.Context, shopifyGIDs []string) (*SubscriptionStatusBatch, error) {
+	merged := &SubscriptionStatusBatch{Results: []SubscriptionStatus{}, NotFound: []string{}}
+	for _, ids := range chunk(shopifyGIDs) {
+		var batch SubscriptionStatusBatch
+		err := c.do(ctx, request{
+			method: http.MethodPost,
+			path:   "/subscriptions/batch",
+			body:   map[string][]string{"ids": ids},
+		}, &batch)
+		if err != nil {
+			return nil, err
+		}
+		merged.Results = append(merged.Results, batch.Results...)
+		merged.NotFound = append(merged.NotFound, batch.NotFound...)
+	}
+	return merged, nil
+}
+
+// ListSubscriptionsParams filters and orders a subscription listing. Zero values are not sent.
+type ListSubscriptionsParams struct {
+	AppID      string // Internal app ID or Shopify app GID
+	RiskState  string
+	Status     string
+	Overdue    *bool
+	OrderBy    string // One of the OrderBy constants
+	Descending bool
+	Limit      int    // 1-250, default 50
+	After      string // NextCursor of the previous page
+}
+
+func (p ListSubscriptionsParams) query() url.Values {
+	q := url.Values{}
+	for name, value := range map[string]string{
+		"app_id":     p.AppID,
+		"risk_state": p.RiskState,
+		"status":     p.Status,
+		"order_by":   p.OrderBy,
+		"after":      p.After,
+	} {
+		if value != "" {
+			q.Set(name, value)
+		}
+	}
+	if p.Overdue != nil {
+		q.Set("overdue", strconv.FormatBool(*p.Overdue))
+	}
+	if p.Descending {
+		q.Set("direction", "desc")
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	return q
+}
+
+// ListSubscriptions returns one page of subscription statuses
+func (c *Client) ListSubscriptions(ctx context.Context, params ListSubscriptionsParams) (*SubscriptionStatusList, error) {
+	var page SubscriptionStatusList
+	err := c.do(ctx, request{method: http.MethodGet, path: "/subscriptions", query: params.query()}, &page)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// SubscriptionIterator walks a subscription listing across pages:
+//
+//	it := client.ListAllSubscriptions(ctx, params)
+//	for it.Next() {
+//		status := it.Subscription()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type SubscriptionIterator struct {
+	ctx     context.Context
+	client  *Client
+	params  ListSubscriptionsParams
+	page    []SubscriptionStatus
+	current SubscriptionStatus
+	done    bool
+	err     error
+}
+
+// ListAllSubscriptions returns an iterator over every subscription matching params,
+// starting after params.After
+func (c *Client) ListAllSubscriptions(ctx context.Context, params ListSubscriptionsParams) *SubscriptionIterator {
+	return &SubscriptionIterator{ctx: ctx, client: c, params: params}
+}
+
+// Next advances to the next subscription, fetching the next page when needed.
+// It returns false when the listing is exhausted or a request failed.
+func (it *SubscriptionIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		page, err := it.client.ListSubscriptions(it.ctx, it.params)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = page.Data
+		it.params.After = page.NextCursor
+		it.done = !page.HasMore || page.NextCursor == ""
+	}
+
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Subscription returns the subscription Next advanced to
+func (it *SubscriptionIterator) Subscription() SubscriptionStatus {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *SubscriptionIterator) Err() error {
+	return it.err
+}
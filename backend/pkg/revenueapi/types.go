@@ -0,0 +1,231 @@
+package revenueapi
+
+import "time"
+
+// Risk states of a subscription
+const (
+	RiskStateSafe            = "SAFE"
+	RiskStateOneCycleMissed  = "ONE_CYCLE_MISSED"
+	RiskStateTwoCyclesMissed = "TWO_CYCLES_MISSED"
+	RiskStateChurned         = "CHURNED"
+)
+
+// Reconciliation states of a reported usage record
+const (
+	ReconciliationPending        = "PENDING"
+	ReconciliationBilled         = "BILLED"
+	ReconciliationOverdue        = "OVERDUE"
+	ReconciliationAmountMismatch = "AMOUNT_MISMATCH"
+)
+
+// Orderings of subscription listings
+const (
+	OrderByMyshopifyDomain          = "MYSHOPIFY_DOMAIN"
+	OrderByMonthsOverdue            = "MONTHS_OVERDUE"
+	OrderByExpectedNextChargeDate   = "EXPECTED_NEXT_CHARGE_DATE"
+	OrderByLastSuccessfulChargeDate = "LAST_SUCCESSFUL_CHARGE_DATE"
+)
+
+// API key scopes
+const (
+	ScopeSubscriptionsRead = "subscriptions:read"
+	ScopeUsageRead         = "usage:read"
+	ScopeUsageWrite        = "usage:write"
+	ScopeStream            = "stream"
+	ScopeWebhooksManage    = "webhooks:manage"
+)
+
+// SubscriptionStatus is the payment status of a subscription
+type SubscriptionStatus struct {
+	SubscriptionID           string     `json:"subscription_id"`
+	MyshopifyDomain          string     `json:"myshopify_domain"`
+	ShopName                 string     `json:"shop_name,omitempty"`
+	PlanName                 string     `json:"plan_name,omitempty"`
+	RiskState                string     `json:"risk_state"`
+	IsPaidCurrentCycle       bool       `json:"is_paid_current_cycle"`
+	MonthsOverdue            int        `json:"months_overdue"`
+	LastSuccessfulChargeDate *time.Time `json:"last_successful_charge_date,omitempty"`
+	ExpectedNextChargeDate   *time.Time `json:"expected_next_charge_date,omitempty"`
+	Status                   string     `json:"status"`
+}
+
+// SubscriptionStatusList is one page of a subscription listing
+type SubscriptionStatusList struct {
+	Data       []SubscriptionStatus `json:"data"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+	HasMore    bool                 `json:"has_more"`
+	TotalCount int                  `json:"total_count"`
+}
+
+// SubscriptionStatusBatch is the result of a bulk subscription lookup
+type SubscriptionStatusBatch struct {
+	Results  []SubscriptionStatus `json:"results"`
+	NotFound []string             `json:"not_found"`
+}
+
+// UsageSubscriptionStatus is the parent subscription of a usage charge
+type UsageSubscriptionStatus struct {
+	SubscriptionID     string `json:"subscription_id"`
+	MyshopifyDomain    string `json:"myshopify_domain"`
+	RiskState          string `json:"risk_state"`
+	IsPaidCurrentCycle bool   `json:"is_paid_current_cycle"`
+}
+
+// UsageStatus is the billing status of a usage charge
+type UsageStatus struct {
+	UsageID      string                   `json:"usage_id"`
+	Billed       bool                     `json:"billed"`
+	BillingDate  *time.Time               `json:"billing_date,omitempty"`
+	AmountCents  int                      `json:"amount_cents"`
+	Description  string                   `json:"description,omitempty"`
+	Subscription *UsageSubscriptionStatus `json:"subscription,omitempty"`
+
+	// Set for reported records
+	Source              string     `json:"source"`
+	ReportedAt          *time.Time `json:"reported_at,omitempty"`
+	BilledTransactionID string     `json:"billed_transaction_id,omitempty"`
+	BilledAmountCents   *int       `json:"billed_amount_cents,omitempty"`
+	ReconciliationState string     `json:"reconciliation_state,omitempty"`
+}
+
+// UsageStatusBatch is the result of a bulk usage lookup
+type UsageStatusBatch struct {
+	Results  []UsageStatus `json:"results"`
+	NotFound []string      `json:"not_found"`
+}
+
+// ReportedUsage is a usage record the app created in Shopify
+type ReportedUsage struct {
+	UsageID        string    `json:"usage_id"`        // gid://shopify/AppUsageRecord/...
+	SubscriptionID string    `json:"subscription_id"` // gid://shopify/AppSubscription/...
+	AmountCents    int       `json:"amount_cents"`
+	Description    string    `json:"description,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// UsageReportRejection is a reported record that wasn't accepted
+type UsageReportRejection struct {
+	UsageID string `json:"usage_id"`
+	Error   string `json:"error"`
+}
+
+// UsageReport is the result of reporting usage records
+type UsageReport struct {
+	Accepted []UsageStatus          `json:"accepted"`
+	Rejected []UsageReportRejection `json:"rejected"`
+}
+
+// UsageReconciliationSummary counts reported records by state and totals their amounts
+type UsageReconciliationSummary struct {
+	Reported       int   `json:"reported"`
+	Pending        int   `json:"pending"`
+	Billed         int   `json:"billed"`
+	Overdue        int   `json:"overdue"`
+	AmountMismatch int   `json:"amount_mismatch"`
+	ReportedCents  int64 `json:"reported_cents"`
+	BilledCents    int64 `json:"billed_cents"`
+	UnbilledCents  int64 `json:"unbilled_cents"`
+}
+
+// UsageReconciliationReport compares reported usage records with what Shopify billed
+type UsageReconciliationReport struct {
+	AppID             string                     `json:"app_id"`
+	From              time.Time                  `json:"from"`
+	To                time.Time                  `json:"to"`
+	OverdueAfterHours int                        `json:"overdue_after_hours"`
+	Summary           UsageReconciliationSummary `json:"summary"`
+	Records           []UsageStatus              `json:"records"`
+}
+
+// APIKey is an API key without its secret
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	KeyPrefix  string     `json:"key_prefix"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	Scopes     []string   `json:"scopes"`
+	AppIDs     []string   `json:"app_ids"` // Empty = all apps
+	ReplacedBy string     `json:"replaced_by,omitempty"`
+	Warnings   []string   `json:"warnings"` // expiring, rotating, unused
+}
+
+// CreateAPIKeyRequest creates an API key
+type CreateAPIKeyRequest struct {
+	Name               string   `json:"name"`
+	RateLimitPerMinute int      `json:"rate_limit_per_minute,omitempty"`
+	Scopes             []string `json:"scopes,omitempty"`          // Defaults to subscriptions:read, usage:read, stream
+	AppIDs             []string `json:"app_ids,omitempty"`         // Empty = all apps
+	ExpiresInDays      int      `json:"expires_in_days,omitempty"` // 0 = never expires
+}
+
+// CreatedAPIKey is a new API key with its secret, which is only returned once
+type CreatedAPIKey struct {
+	APIKey  APIKey `json:"api_key"`
+	FullKey string `json:"full_key"`
+}
+
+// RotateAPIKeyRequest rotates an API key
+type RotateAPIKeyRequest struct {
+	OverlapHours  *int `json:"overlap_hours,omitempty"`   // How long the old key keeps working; default 24
+	ExpiresInDays int  `json:"expires_in_days,omitempty"` // Successor expiry; default = old key's lifetime
+}
+
+// RotatedAPIKey is the successor of a rotated key
+type RotatedAPIKey struct {
+	CreatedAPIKey
+	PreviousKeyID        string    `json:"previous_key_id"`
+	PreviousKeyExpiresAt time.Time `json:"previous_key_expires_at"`
+}
+
+// APIUsageStats are aggregated request counts and latencies
+type APIUsageStats struct {
+	Requests      int64   `json:"requests"`
+	Errors        int64   `json:"errors"`
+	ServerErrors  int64   `json:"server_errors"`
+	RateLimited   int64   `json:"rate_limited"`
+	ErrorRate     float64 `json:"error_rate"`
+	AvgResponseMs int     `json:"avg_response_ms"`
+	P50ResponseMs int     `json:"p50_response_ms"`
+	P95ResponseMs int     `json:"p95_response_ms"`
+}
+
+// APIEndpointUsage is the usage of one endpoint
+type APIEndpointUsage struct {
+	Endpoint string `json:"endpoint"`
+	Method   string `json:"method"`
+	APIUsageStats
+}
+
+// APIHourlyUsage is the usage in one hour
+type APIHourlyUsage struct {
+	Hour time.Time `json:"hour"`
+	APIUsageStats
+}
+
+// APICallerUsage is the usage from one client IP
+type APICallerUsage struct {
+	IPAddress string `json:"ip_address"`
+	Requests  int64  `json:"requests"`
+	Errors    int64  `json:"errors"`
+}
+
+// APIKeyQuota reports how close a key runs to its rate limit
+type APIKeyQuota struct {
+	RateLimitPerMinute int   `json:"rate_limit_per_minute"`
+	PeakHourRequests   int64 `json:"peak_hour_requests"`
+	RateLimited        int64 `json:"rate_limited"`
+}
+
+// APIKeyUsageReport is the usage of an API key over a time range
+type APIKeyUsageReport struct {
+	APIKeyID   string             `json:"api_key_id"`
+	From       time.Time          `json:"from"`
+	To         time.Time          `json:"to"`
+	Totals     APIUsageStats      `json:"totals"`
+	Quota      APIKeyQuota        `json:"quota"`
+	Endpoints  []APIEndpointUsage `json:"endpoints"`
+	Hourly     []APIHourlyUsage   `json:"hourly"`
+	TopCallers []APICallerUsage   `json:"top_callers"`
+}
@@ -0,0 +1,68 @@
+package revenueapi
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sachin-sivadasan/ledgerguard/internal/revenue_api/interfaces/http/openapi"
+)
+
+func TestTypes_MatchOpenAPIDocument(t *testing.T) {
+	tests := []struct {
+		schema string
+		value  interface{}
+	}{
+		{"SubscriptionStatus", SubscriptionStatus{}},
+		{"SubscriptionStatusList", SubscriptionStatusList{}},
+		{"SubscriptionStatusBatch", SubscriptionStatusBatch{}},
+		{"UsageSubscriptionStatus", UsageSubscriptionStatus{}},
+		{"UsageStatus", UsageStatus{}},
+		{"UsageStatusBatch", UsageStatusBatch{}},
+		{"ReportedUsage", ReportedUsage{}},
+		{"UsageReportRejection", UsageReportRejection{}},
+		{"UsageReport", UsageReport{}},
+		{"UsageReconciliationSummary", UsageReconciliationSummary{}},
+		{"UsageReconciliationReport", UsageReconciliationReport{}},
+		{"APIKey", APIKey{}},
+		{"CreateAPIKeyRequest", CreateAPIKeyRequest{}},
+		{"CreatedAPIKey", CreatedAPIKey{}},
+		{"RotateAPIKeyRequest", RotateAPIKeyRequest{}},
+		{"RotatedAPIKey", RotatedAPIKey{}},
+		{"APIUsageStats", APIUsageStats{}},
+		{"APIEndpointUsage", APIEndpointUsage{}},
+		{"APIHourlyUsage", APIHourlyUsage{}},
+		{"APICallerUsage", APICallerUsage{}},
+		{"APIKeyQuota", APIKeyQuota{}},
+		{"APIKeyUsageReport", APIKeyUsageReport{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			properties, err := openapi.SchemaProperties(tt.schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fields := jsonFields(reflect.TypeOf(tt.value))
+			if !reflect.DeepEqual(properties, fields) {
+				t.Errorf("schema properties %v do not match JSON fields %v", properties, fields)
+			}
+		})
+	}
+}
+
+// jsonFields returns the sorted JSON names of a struct's fields, flattening embedded structs
+func jsonFields(t reflect.Type) []string {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			fields = append(fields, jsonFields(field.Type)...)
+			continue
+		}
+		fields = append(fields, strings.Split(field.Tag.Get("json"), ",")[0])
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,92 @@
+package revenueapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GetUsage returns the billing status of a usage charge by its Shopify GID
+func (c *Client) GetUsage(ctx context.Context, shopifyGID string) (*UsageStatus, error) {
+	var status UsageStatus
+	err := c.do(ctx, request{method: http.MethodGet, path: "/usages/" + url.PathEscape(shopifyGID)}, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetUsages looks up any number of usage charges by Shopify GID, in requests
+// of at most MaxBatchSize IDs
+func (c *Client) GetUsages(ctx context.Context, shopifyGIDs []string) (*UsageStatusBatch, error) {
+	merged := &UsageStatusBatch{Results: []UsageStatus{}, NotFound: []string{}}
+	for _, ids := range chunk(shopifyGIDs) {
+		var batch UsageStatusBatch
+		err := c.do(ctx, request{
+			method: http.MethodPost,
+			path:   "/usages/batch",
+			body:   map[string][]string{"ids": ids},
+		}, &batch)
+		if err != nil {
+			return nil, err
+		}
+		merged.Results = append(merged.Results, batch.Results...)
+		merged.NotFound = append(merged.NotFound, batch.NotFound...)
+	}
+	return merged, nil
+}
+
+// ReportUsage reports any number of created usage records for reconciliation,
+// in requests of at most MaxBatchSize records. Records the API rejects are
+// returned in Rejected rather than as an error.
+func (c *Client) ReportUsage(ctx context.Context, records []ReportedUsage) (*UsageReport, error) {
+	merged := &UsageReport{Accepted: []UsageStatus{}, Rejected: []UsageReportRejection{}}
+	for _, batch := range chunk(records) {
+		var report UsageReport
+		err := c.do(ctx, request{
+			method: http.MethodPost,
+			path:   "/usages",
+			body:   map[string][]ReportedUsage{"records": batch},
+			accept: []int{http.StatusUnprocessableEntity}, // Every record in the batch was rejected
+		}, &report)
+		if err != nil {
+			return nil, err
+		}
+		merged.Accepted = append(merged.Accepted, report.Accepted...)
+		merged.Rejected = append(merged.Rejected, report.Rejected...)
+	}
+	return merged, nil
+}
+
+// ReconciliationParams selects the records of a reconciliation report. Zero values are not sent.
+type ReconciliationParams struct {
+	AppID  string // Required when the key covers several apps
+	From   time.Time
+	To     time.Time
+	States []string // Reconciliation constants; all states when empty
+}
+
+// GetUsageReconciliation compares an app's reported usage records with what Shopify billed
+func (c *Client) GetUsageReconciliation(ctx context.Context, params ReconciliationParams) (*UsageReconciliationReport, error) {
+	q := url.Values{}
+	if params.AppID != "" {
+		q.Set("app_id", params.AppID)
+	}
+	if !params.From.IsZero() {
+		q.Set("from", params.From.Format(time.RFC3339))
+	}
+	if !params.To.IsZero() {
+		q.Set("to", params.To.Format(time.RFC3339))
+	}
+	if len(params.States) > 0 {
+		q.Set("state", strings.Join(params.States, ","))
+	}
+
+	var report UsageReconciliationReport
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/usages/reconciliation", query: q}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}